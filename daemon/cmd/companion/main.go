@@ -7,9 +7,9 @@
 // restoring protection with NO network. Then it exits; launchd re-runs it.
 //
 // Minimal by design (ADR-0020): few reasons to change, so the rail stays stable
-// and out of the way. Its dependencies are deliberately tiny — mode, sig, and
-// internal/companion — and it does NOT import the daemon's osadapter install
-// code. launchd, not cron, so it can be established + repaired in an automated
+// and out of the way. Its dependencies are deliberately tiny — mode, sig,
+// codesign and internal/companion — and it does NOT import the daemon's
+// osadapter install code. launchd, not cron, so it can be established + repaired in an automated
 // context WITHOUT Full Disk Access (the failure ADR-0020 reverses).
 package main
 
@@ -19,6 +19,7 @@ import (
 	"os/exec"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/codesign"
 	"github.com/eliteGoblin/focusd/daemon/internal/companion"
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
 )
@@ -42,7 +43,7 @@ func run() int {
 		return 1
 	}
 	dir := companion.DirFromBinary(exe)
-	if rerr := recover(dir, time.Now(), sig.VerifyFile, execWatchdog); rerr != nil {
+	if rerr := recover(dir, time.Now(), verifyBackup, keepSigned(exe, execWatchdog)); rerr != nil {
 		// PATH-FREE: never print the disguised companion/daemon paths a
 		// weak-moment self would need. Keep it abstract; launchd captures this
 		// to the companion log.
@@ -52,6 +53,29 @@ func run() int {
 	return 0
 }
 
+// verifyBackup signature-checks the daemon a backup or spare holds. Both are
+// stored packed (companion.Pack), so the trailer is only reachable unpacked.
+func verifyBackup(path string) (bool, error) {
+	b, err := companion.ReadBackup(path)
+	if err != nil {
+		return false, err
+	}
+	return sig.VerifyBytes(b)
+}
+
+// keepSigned gates a restore on the Apple code signature: the companion
+// ships in the same release as the daemon, so when it is team-signed the
+// promoted daemon must be too (codesign.Keep). An ad-hoc or unsigned
+// companion, or one off macOS, leaves the Ed25519 check as the only gate.
+func keepSigned(self string, exec func(bin, desired string) error) func(bin, desired string) error {
+	return func(bin, desired string) error {
+		if err := codesign.Keep(self, bin); err != nil {
+			return err
+		}
+		return exec(bin, desired)
+	}
+}
+
 // watchdogExecTimeout bounds the companion's blocking watchdog handoff (#106-b3).
 // A genuinely hung `daemon watchdog` would otherwise keep the companion one-shot
 // alive forever — launchd never fires a fresh pass (the wedged-rail class #106-b2
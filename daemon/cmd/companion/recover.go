@@ -13,17 +13,19 @@ import (
 )
 
 // recover is the companion's single recovery pass (FEATURE 18 / ADR-0020),
-// injectable for unit testing. verify is the signature check (sig.VerifyFile in
-// production); execDaemon runs the promoted daemon binary's idempotent
+// injectable for unit testing. verify is the signature check (verifyBackup in
+// production, which unpacks first); execDaemon runs the promoted daemon binary's idempotent
 // `watchdog` subcommand.
 //
 // Steps:
-//  1. Heartbeat fresh (< StaleThreshold) → the daemon is alive → no-op.
+//  1. Heartbeat fresh (< StaleThreshold) → the daemon is alive → only keep the
+//     spare copy of the backup in step (syncSpare); no restore.
 //  2. Stale (or heartbeat missing) → read the pinned desired version (reject if
 //     not valid semver), signature-verify the offline backup; an invalid/
-//     tampered backup is REFUSED WITHOUT promoting (path-free error).
-//  3. Atomically place the verified backup at the promote path (0755), then
-//     hand off to `daemon watchdog -v <desired>`.
+//     tampered backup is put back from a verifying spare, or else REFUSED
+//     WITHOUT promoting (path-free error).
+//  3. Atomically place the verified backup, unpacked, at the promote path
+//     (0755), then hand off to `daemon watchdog -v <desired>`.
 //
 // ANTI-FIGHT (critical): restoration goes through the daemon's IDEMPOTENT
 // watchdog, which no-ops when the mesh is already complete
@@ -49,6 +51,7 @@ func recover(
 	//    error) falls through to the restore path (treated as stale).
 	if fi, err := os.Stat(dir.Heartbeat()); err == nil {
 		if !companion.DecideStale(fi.ModTime(), now) {
+			syncSpare(dir, verify)
 			touchRan(dir, now) // completed pass (no-op path): record the rail is firing
 			return nil
 		}
@@ -62,14 +65,18 @@ func recover(
 	}
 	// Signature-verify the offline backup BEFORE it can be promoted + exec'd as
 	// root. A genuine backup is a signed daemon binary and verifies; a tampered/
-	// poisoned copy fails → refuse WITHOUT promoting. PATH-FREE message.
+	// poisoned copy fails → put back from the spare, or refuse WITHOUT
+	// promoting. PATH-FREE messages.
 	if ok, verr := verify(dir.Backup()); verr != nil || !ok {
-		return fmt.Errorf("companion: offline backup failed signature verification; refusing to promote")
+		if !restoreFromSpare(dir, verify) {
+			return fmt.Errorf("companion: offline backup failed signature verification; refusing to promote")
+		}
+		os.Stderr.WriteString("companion: offline backup failed verification; restored it from the spare\n")
 	}
 
-	// 3. Atomically place the verified backup, then hand off to the idempotent
-	//    watchdog rebuild.
-	if err := placeExecutable(dir.Backup(), dir.Promote()); err != nil {
+	// 3. Atomically place the verified backup, unpacked, then hand off to the
+	//    idempotent watchdog rebuild.
+	if err := placeUnpacked(dir.Backup(), dir.Promote()); err != nil {
 		return fmt.Errorf("companion: place backup failed")
 	}
 	if err := execDaemon(dir.Promote(), desired); err != nil {
@@ -79,6 +86,46 @@ func recover(
 	return nil
 }
 
+// syncSpare refreshes the spare from the backup when they are out of step (the
+// spare missing, or its size or mtime not the backup's) and the backup verifies.
+// In step — every pass between daemon updates — it costs two stats and no
+// verification. A backup that does not verify never reaches the spare: the
+// spare only ever holds signed bytes. The spare is a copy of the packed file
+// as is. Best-effort, like touchRan.
+func syncSpare(dir companion.Dir, verify func(path string) (bool, error)) {
+	bfi, err := os.Stat(dir.Backup())
+	if err != nil {
+		return
+	}
+	if sfi, err := os.Stat(dir.Spare()); err == nil && sfi.Size() == bfi.Size() && sfi.ModTime().Equal(bfi.ModTime()) {
+		return
+	}
+	if ok, verr := verify(dir.Backup()); verr != nil || !ok {
+		return
+	}
+	if placeExecutable(dir.Backup(), dir.Spare()) == nil {
+		_ = os.Chtimes(dir.Spare(), bfi.ModTime(), bfi.ModTime())
+	}
+}
+
+// restoreFromSpare puts a verifying spare back at the backup path, reporting
+// whether the backup is now usable. A missing or unverifiable spare leaves the
+// backup as it is.
+func restoreFromSpare(dir companion.Dir, verify func(path string) (bool, error)) bool {
+	sfi, err := os.Stat(dir.Spare())
+	if err != nil {
+		return false
+	}
+	if ok, verr := verify(dir.Spare()); verr != nil || !ok {
+		return false
+	}
+	if placeExecutable(dir.Spare(), dir.Backup()) != nil {
+		return false
+	}
+	_ = os.Chtimes(dir.Backup(), sfi.ModTime(), sfi.ModTime()) // back in step
+	return true
+}
+
 // touchRan sets the RanMarker's mtime to now (best-effort), recording that a
 // recovery pass FIRED. It is stamped at the START of every pass (#106-b1) AND on
 // completion, so the marker stays fresh even DURING a legitimately long watchdog
@@ -107,6 +154,21 @@ func readTrimmed(path string) (string, error) {
 	return strings.TrimSpace(string(b)), nil
 }
 
+// placeUnpacked writes the daemon the packed backup at src holds to dst, by
+// way of a sibling temp file like placeExecutable, with 0755.
+func placeUnpacked(src, dst string) error {
+	b, err := companion.ReadBackup(src)
+	if err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o755); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
 // placeExecutable copies src to dst atomically (temp + rename, same dir → same
 // filesystem) with 0755, so a crash mid-copy can't leave a half-written binary
 // that exec would run.
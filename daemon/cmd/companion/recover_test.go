@@ -116,18 +116,80 @@ func TestRecoverFreshHeartbeatNoOp(t *testing.T) {
 	writeFile(t, dir.Backup(), "SIGNED-DAEMON")
 
 	rec := &recorder{}
+	pass := func() {
+		t.Helper()
+		err := recover(dir, time.Now(),
+			func(p string) (bool, error) { rec.verified = append(rec.verified, p); return true, nil },
+			func(bin, desired string) error { rec.execCalls++; return nil },
+		)
+		if err != nil {
+			t.Fatalf("recover (fresh) = %v, want nil", err)
+		}
+	}
+	// The first pass takes the spare copy, verifying the backup once; a pass
+	// with the two in step verifies nothing.
+	pass()
+	pass()
+	if len(rec.verified) != 1 || rec.verified[0] != dir.Backup() {
+		t.Fatalf("verify calls on fresh heartbeats = %v, want one of the backup", rec.verified)
+	}
+	if b, err := os.ReadFile(dir.Spare()); err != nil || string(b) != "SIGNED-DAEMON" {
+		t.Fatalf("spare = %q, %v", b, err)
+	}
+	if rec.execCalls != 0 {
+		t.Fatalf("execDaemon ran on a fresh heartbeat; want 0, got %d", rec.execCalls)
+	}
+}
+
+// TestRecoverFreshHeartbeatKeepsAnUnverifiedBackupOutOfTheSpare: a tampered
+// backup seen while the daemon is alive never overwrites the signed spare.
+func TestRecoverFreshHeartbeatKeepsAnUnverifiedBackupOutOfTheSpare(t *testing.T) {
+	dir := companionTestDir(t)
+	writeFile(t, dir.Heartbeat(), "")
+	writeFile(t, dir.Backup(), "TAMPERED-LONGER")
+	writeFile(t, dir.Spare(), "SIGNED-DAEMON")
+
 	err := recover(dir, time.Now(),
-		func(p string) (bool, error) { rec.verified = append(rec.verified, p); return true, nil },
-		func(bin, desired string) error { rec.execCalls++; return nil },
+		func(p string) (bool, error) { return p == dir.Spare(), nil },
+		func(bin, desired string) error { return nil },
 	)
 	if err != nil {
 		t.Fatalf("recover (fresh) = %v, want nil", err)
 	}
-	if len(rec.verified) != 0 {
-		t.Fatalf("verify ran on a fresh heartbeat; want 0, got %v", rec.verified)
+	if b, _ := os.ReadFile(dir.Spare()); string(b) != "SIGNED-DAEMON" {
+		t.Fatalf("spare overwritten with an unverified backup: %q", b)
 	}
-	if rec.execCalls != 0 {
-		t.Fatalf("execDaemon ran on a fresh heartbeat; want 0, got %d", rec.execCalls)
+}
+
+// TestRecoverStaleBadBackupRestoresFromSpare: with the daemon down and the
+// backup tampered, the companion's own spare still restores it — and the pass
+// promotes the spare's signed bytes.
+func TestRecoverStaleBadBackupRestoresFromSpare(t *testing.T) {
+	dir := companionTestDir(t)
+	staleMtime := time.Now().Add(-companion.StaleThreshold - time.Minute)
+	writeFile(t, dir.Heartbeat(), "")
+	if err := os.Chtimes(dir.Heartbeat(), staleMtime, staleMtime); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir.Desired(), goodDesired)
+	writeFile(t, dir.Backup(), "TAMPERED")
+	writeFile(t, dir.Spare(), "SIGNED-DAEMON")
+
+	rec := &recorder{}
+	err := recover(dir, time.Now(),
+		func(p string) (bool, error) { return p == dir.Spare(), nil },
+		func(bin, desired string) error { rec.execCalls++; rec.execBin = bin; return nil },
+	)
+	if err != nil {
+		t.Fatalf("recover (stale, spare) = %v, want nil", err)
+	}
+	if rec.execCalls != 1 {
+		t.Fatalf("execDaemon calls = %d, want 1", rec.execCalls)
+	}
+	for _, p := range []string{dir.Backup(), dir.Promote()} {
+		if b, _ := os.ReadFile(p); string(b) != "SIGNED-DAEMON" {
+			t.Fatalf("%s holds %q, want the spare's bytes", filepath.Base(p), b)
+		}
 	}
 }
 
@@ -179,6 +241,32 @@ func TestRecoverStaleValidBackupExecs(t *testing.T) {
 	}
 }
 
+// TestRecoverPromotesPackedBackupUnpacked: the backup on disk is packed; the
+// promote path must get the daemon it holds, executable, not the packed blob.
+func TestRecoverPromotesPackedBackupUnpacked(t *testing.T) {
+	dir := companionTestDir(t)
+	writeFile(t, dir.Desired(), goodDesired)
+	packed, err := companion.Pack([]byte("SIGNED-DAEMON-BYTES"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir.Backup(), string(packed))
+
+	ok := func(string) (bool, error) { return true, nil }
+	if err := recover(dir, time.Now(), ok, func(string, string) error { return nil }); err != nil {
+		t.Fatalf("recover = %v, want nil", err)
+	}
+	b, err := os.ReadFile(dir.Promote())
+	if err != nil || string(b) != "SIGNED-DAEMON-BYTES" {
+		t.Fatalf("promoted = %q, %v; want the unpacked daemon", b, err)
+	}
+	if fi, err := os.Stat(dir.Promote()); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0o755 {
+		t.Fatalf("promoted mode = %v, want 0755", fi.Mode().Perm())
+	}
+}
+
 // TestRecoverStaleBadBackupNoPromote: a stale heartbeat but a backup that FAILS
 // signature verification → recover must NOT promote and must NOT exec; it
 // returns an error. Closes the poisoned-offline-restore hole (acceptance #4).
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// doAPI is `daemon api`: show, enable, re-key or turn off the platform's
+// localhost admin API (JSON: status, history, policies, health, scan, events).
+//
+//	daemon api                                  — show the address (token hidden)
+//	daemon api --listen 127.0.0.1:7600          — serve there; prints the token
+//	daemon api --rotate                         — issue a new token; prints it
+//	daemon api --show-token                     — print the current token
+//	daemon api --off                            — stop serving and drop the token
+//	daemon api --debug on|off                   — pprof + runtime stats on the unix socket
+//
+// Address and token live in the daemon's masked version.json and reach the
+// platform child in its environment, so a change applies on the next
+// platform start. Clients send "Authorization: Bearer <token>". The debug
+// routes are independent of --listen: they are only ever served on the
+// platform's 0600 unix socket, never on TCP.
+func doAPI(args []string) int {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	listen := fs.String("listen", "", "loopback host:port to serve the admin API on")
+	off := fs.Bool("off", false, "stop serving the admin API and drop its token")
+	rotate := fs.Bool("rotate", false, "replace the token")
+	show := fs.Bool("show-token", false, "print the current token")
+	debug := fs.String("debug", "", "on|off: serve pprof and runtime stats on the platform's unix admin socket")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *debug != "" && *debug != "on" && *debug != "off" {
+		fmt.Fprintln(os.Stderr, "api: --debug takes on or off")
+		return 2
+	}
+	if *off && (*listen != "" || *rotate) {
+		fmt.Fprintln(os.Stderr, "api: --off takes no --listen or --rotate")
+		return 2
+	}
+	if *listen != "" {
+		if err := validAPIAddr(*listen); err != nil {
+			fmt.Fprintln(os.Stderr, "api: --listen:", err)
+			return 2
+		}
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "api: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	if *debug != "" {
+		if err := st.WriteAdminDebug(*debug == "on"); err != nil {
+			fmt.Fprintln(os.Stdout, "  api: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	return configureAPI(st, *listen, *off, *rotate, *show, os.Stdout)
+}
+
+// configureAPI applies the parsed flags to st and prints the result. The
+// token is printed only when it was just issued or --show-token asked.
+// Returns the exit code.
+func configureAPI(st *core.Store, listen string, off, rotate, show bool, out io.Writer) int {
+	addr, old := st.AdminAPI()
+	switch {
+	case off:
+		addr = ""
+	case listen != "":
+		addr = listen
+	}
+	if rotate && addr == "" {
+		fmt.Fprintln(out, "  api: not enabled; pass --listen 127.0.0.1:PORT")
+		return 1
+	}
+	if off || listen != "" || rotate {
+		if err := st.WriteAdminAPI(addr, rotate); err != nil {
+			fmt.Fprintln(out, "  api: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	addr, token := st.AdminAPI()
+	if st.AdminDebug() {
+		fmt.Fprintln(out, "  api: debug routes on (unix socket only)")
+	}
+	if addr == "" {
+		fmt.Fprintln(out, "  api: off")
+		return 0
+	}
+	fmt.Fprintf(out, "  api: http://%s/v1/ (platform picks a change up on its next start)\n", addr)
+	if show || token != old {
+		fmt.Fprintln(out, "  api: token", token)
+	}
+	return 0
+}
+
+// validAPIAddr mirrors the platform's adminapi.ValidAddr (separate module):
+// host:port with a loopback host, since the API has no TLS.
+func validAPIAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return errors.New("must be host:port")
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return errors.New("port must be a number")
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return errors.New("host must be a loopback address (127.0.0.1, ::1 or localhost)")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestConfigureAPI(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+
+	if code := configureAPI(st, "127.0.0.1:7600", false, false, false, &out); code != 0 {
+		t.Fatalf("enable: code %d", code)
+	}
+	_, token := st.AdminAPI()
+	if token == "" || !strings.Contains(out.String(), token) {
+		t.Fatalf("enable must print the new token:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := configureAPI(st, "", false, false, false, &out); code != 0 || strings.Contains(out.String(), token) {
+		t.Fatalf("plain listing must hide the token (code %d):\n%s", code, out.String())
+	}
+
+	out.Reset()
+	if code := configureAPI(st, "", true, false, false, &out); code != 0 {
+		t.Fatalf("off: code %d", code)
+	}
+	if addr, _ := st.AdminAPI(); addr != "" {
+		t.Fatalf("still serving on %q after --off", addr)
+	}
+	if code := configureAPI(st, "", false, true, false, &out); code != 1 {
+		t.Fatalf("--rotate while off: code %d, want 1", code)
+	}
+
+	out.Reset()
+	if err := st.WriteAdminDebug(true); err != nil {
+		t.Fatal(err)
+	}
+	if code := configureAPI(st, "", false, false, false, &out); code != 0 || !strings.Contains(out.String(), "debug routes on") {
+		t.Fatalf("debug must show while the TCP API is off (code %d):\n%s", code, out.String())
+	}
+}
+
+func TestValidAPIAddr(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:7600": true,
+		"localhost:7600": true,
+		"0.0.0.0:7600":   false,
+		"example.com:80": false,
+		"127.0.0.1":      false,
+	} {
+		if err := validAPIAddr(addr); (err == nil) != ok {
+			t.Errorf("validAPIAddr(%q) = %v, want ok=%v", addr, err, ok)
+		}
+	}
+}
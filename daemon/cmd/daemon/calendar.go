@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// doCalendar is `daemon calendar`: show, set or clear the calendar whose
+// tagged entries (e.g. "#focus") switch the platform's calendar profile on.
+//
+//	daemon calendar                                — show the calendar host
+//	daemon calendar --ics https://host/cal.ics     — watch that calendar (webcal:// too)
+//	daemon calendar --off                          — stop watching
+//
+// The URL lives in the daemon's masked version.json (a private calendar URL
+// is a credential) and reaches the platform child in its environment, so a
+// change applies on the next platform start. The tag and the profile come
+// from the signed config. Under a strict lock (`daemon lock`) --off refuses.
+func doCalendar(args []string) int {
+	fs := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	ics := fs.String("ics", "", "ICS URL (http, https or webcal) of the calendar to watch")
+	off := fs.Bool("off", false, "stop watching the calendar")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *off && *ics != "" {
+		fmt.Fprintln(os.Stderr, "calendar: --off takes no --ics")
+		return 2
+	}
+	if *ics != "" && !validCalendarURL(*ics) {
+		fmt.Fprintln(os.Stderr, "calendar: --ics must be an absolute http, https or webcal URL")
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "calendar: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	return configureCalendar(&core.Store{Dir: workdir}, *ics, *off, os.Stdout)
+}
+
+// configureCalendar applies the parsed flags to st and prints the result,
+// naming only the calendar's host. Returns the exit code.
+func configureCalendar(st *core.Store, ics string, off bool, out io.Writer) int {
+	if off && refuseWhileLocked(st, "calendar --off", out) {
+		return 1
+	}
+	if off || ics != "" {
+		if err := st.WriteCalendarURL(ics); err != nil {
+			fmt.Fprintln(out, "  calendar: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	cur := st.CalendarURL()
+	if cur == "" {
+		fmt.Fprintln(out, "  calendar: off")
+		return 0
+	}
+	u, _ := url.Parse(cur)
+	fmt.Fprintln(out, "  calendar: watching", u.Host, "(platform picks a change up on its next start)")
+	return 0
+}
+
+// validCalendarURL mirrors the platform's calendar.ValidURL (separate
+// module).
+func validCalendarURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "webcal")
+}
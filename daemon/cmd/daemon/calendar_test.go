@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestConfigureCalendar(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	const ics = "https://calendar.example.com/private-SECRET/basic.ics"
+	if code := configureCalendar(st, ics, false, &out); code != 0 {
+		t.Fatalf("set: code %d", code)
+	}
+	if st.CalendarURL() != ics || strings.Contains(out.String(), "SECRET") {
+		t.Fatalf("stored %q; printed:\n%s", st.CalendarURL(), out.String())
+	}
+
+	applyLock(st, 1, time.Now(), strings.NewReader("lock for 1 days\n"), &bytes.Buffer{})
+	out.Reset()
+	if code := configureCalendar(st, "", true, &out); code != 1 || st.CalendarURL() != ics {
+		t.Fatalf("--off under a strict lock: code %d, url %q", code, st.CalendarURL())
+	}
+}
+
+func TestValidCalendarURL(t *testing.T) {
+	for raw, ok := range map[string]bool{
+		"https://example.com/a.ics": true,
+		"webcal://example.com/a":    true,
+		"ftp://example.com/a.ics":   false,
+		"/local/a.ics":              false,
+	} {
+		if validCalendarURL(raw) != ok {
+			t.Errorf("validCalendarURL(%q) != %v", raw, ok)
+		}
+	}
+}
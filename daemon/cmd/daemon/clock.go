@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/trustclock"
+)
+
+// ntpSyncInterval is how often the trusted clock syncs with clock.ntp, and
+// ntpRetryInterval how long a failed sync waits before the next attempt:
+// an offline laptop must not spend every credit pass on a timeout.
+const (
+	ntpSyncInterval  = time.Hour
+	ntpRetryInterval = 10 * time.Minute
+	ntpTimeout       = 3 * time.Second
+)
+
+// skewReportStep is how far the system clock must move against the trusted
+// one before the change is logged again.
+const skewReportStep = 10 * time.Minute
+
+// clockTender advances the trusted clock on the lock holder's credit pass
+// and hands the platform its skew.
+type clockTender struct {
+	query func(ctx context.Context, server string) (time.Time, error)
+	post  func(sock string, skew time.Duration) error
+	// tried is the system time of the last sync attempt; skew the skew last
+	// logged.
+	tried time.Time
+	skew  time.Duration
+}
+
+func newClockTender() *clockTender {
+	return &clockTender{query: trustclock.Query, post: postClock}
+}
+
+// tend credits ran of monotonic time and slept of observed sleep at the
+// system time sys, syncs with the configured time server when due, logs a
+// clock that moved against the trusted one, and posts the skew to the
+// running platform.
+func (t *clockTender) tend(st *core.Store, sys time.Time, ran, slept time.Duration, log *slog.Logger) {
+	if err := st.UpdateClock(func(c trustclock.Clock) trustclock.Clock { return c.Credit(sys, ran, slept) }); err != nil {
+		log.Warn("clock credit", "err", fmt.Sprintf("%T", err))
+		return
+	}
+	if srv := st.NTP(); srv != "" && st.Clock().SyncDue(sys, ntpSyncInterval) && sys.Sub(t.tried) >= ntpRetryInterval {
+		t.tried = sys
+		ctx, cancel := context.WithTimeout(context.Background(), ntpTimeout)
+		at, err := t.query(ctx, srv)
+		cancel()
+		if err != nil {
+			log.Warn("time server sync failed", "err", fmt.Sprintf("%T", err))
+		} else if err := st.UpdateClock(func(c trustclock.Clock) trustclock.Clock { return c.Sync(time.Now(), at) }); err != nil {
+			log.Warn("clock sync", "err", fmt.Sprintf("%T", err))
+		}
+	}
+	skew := st.Clock().Skew(sys)
+	if d := skew - t.skew; d >= skewReportStep || d <= -skewReportStep {
+		log.Warn("system clock differs from the trusted clock; time gates follow the trusted one",
+			"skew", skew.Round(time.Second).String())
+		t.skew = skew
+	}
+	if err := t.post(st.AdminSocketPath(), skew); err != nil {
+		log.Debug("post clock skew", "err", fmt.Sprintf("%T", err))
+	}
+}
+
+// postClock posts the skew to the platform's /v1/clock on the unix socket
+// at sock, like postHold.
+func postClock(sock string, skew time.Duration) error {
+	body, _ := json.Marshal(map[string]int64{"skew_s": int64(skew / time.Second)})
+	c := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := c.Post("http://platform/v1/clock", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.New("platform socket unavailable")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("platform clock: %s", resp.Status)
+	}
+	return nil
+}
+
+// validTimeServer reports whether s names a time server: a host, optionally
+// with a port, and nothing else.
+func validTimeServer(s string) bool {
+	host := s
+	if h, p, err := net.SplitHostPort(s); err == nil {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return false
+		}
+		host = h
+	}
+	return host != "" && !strings.ContainsAny(host, "/ @?#")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestClockTender(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := 0
+	var serverTime time.Time
+	var queryErr error
+	var posted []time.Duration
+	ct := &clockTender{
+		query: func(context.Context, string) (time.Time, error) { queries++; return serverTime, queryErr },
+		post:  func(_ string, skew time.Duration) error { posted = append(posted, skew); return nil },
+	}
+	t0 := time.Now()
+
+	ct.tend(st, t0, 0, 0, log)
+	if queries != 0 || st.Clock().Seen.IsZero() {
+		t.Fatalf("first pass: %d queries, clock %+v", queries, st.Clock())
+	}
+	// The clock is pushed forward a week: the gates must not follow it.
+	jump := t0.Add(7 * 24 * time.Hour)
+	ct.tend(st, jump, time.Minute, 0, log)
+	if skew := posted[len(posted)-1]; skew < 5*24*time.Hour {
+		t.Fatalf("posted skew %s, want the jump", skew)
+	}
+
+	// With a time server the trusted clock takes its reading.
+	if err := st.WriteNTP("time.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	queryErr = errors.New("offline")
+	ct.tend(st, jump.Add(time.Minute), time.Minute, 0, log)
+	ct.tend(st, jump.Add(2*time.Minute), time.Minute, 0, log)
+	if queries != 1 {
+		t.Fatalf("a failed sync retried at once: %d queries", queries)
+	}
+	queryErr, serverTime = nil, time.Now()
+	ct.tend(st, jump.Add(ntpRetryInterval+time.Minute), time.Minute, 0, log)
+	if queries != 2 {
+		t.Fatalf("sync not retried after the interval: %d queries", queries)
+	}
+	if d := st.Now().Sub(time.Now()); d > time.Minute || d < -time.Minute {
+		t.Fatalf("trusted clock %s off the server after a sync", d)
+	}
+}
+
+func TestValidTimeServer(t *testing.T) {
+	for _, s := range []string{"time.apple.com", "pool.ntp.org:123", "10.0.0.1", "[::1]:123"} {
+		if !validTimeServer(s) {
+			t.Errorf("%q rejected", s)
+		}
+	}
+	for _, s := range []string{"", "udp://x", "a b", "host:", "x/y"} {
+		if validTimeServer(s) {
+			t.Errorf("%q accepted", s)
+		}
+	}
+}
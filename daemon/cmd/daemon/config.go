@@ -0,0 +1,528 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/crash"
+	"github.com/eliteGoblin/focusd/daemon/internal/fetch"
+	"github.com/eliteGoblin/focusd/daemon/internal/heartbeat"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+	"github.com/eliteGoblin/focusd/daemon/internal/platformsvc"
+	"github.com/eliteGoblin/focusd/shared/tracing"
+)
+
+// configKey is one runtime setting `daemon config` reads and writes. The
+// settings live in the masked version.json like every other verb's; this is
+// one front door to them, with the same checks those verbs make.
+type configKey struct {
+	name string
+	// show renders the value for printing: URLs as scheme and host only,
+	// since a webhook or collector path can carry a credential.
+	show func(st *core.Store) string
+	// check validates v before anything is written.
+	check func(v string) error
+	// loosens reports whether writing v weakens protection; such a change
+	// is refused under a strict lock. nil ⇒ never.
+	loosens func(st *core.Store, v string) bool
+	write   func(st *core.Store, v string) error
+}
+
+// configKeys are the settings `daemon config` manages, in listing order.
+// The lock, the calendar, the admin API and the decoys keep their own verbs:
+// each has a confirmation or a secret that a key=value write would skip.
+var configKeys = []configKey{
+	{
+		name:  "channel",
+		show:  func(st *core.Store) string { return st.Channel() },
+		check: oneOf("channel", core.ChannelStable, core.ChannelBeta, core.ChannelPinned),
+		write: func(st *core.Store, v string) error { return st.WriteChannel(v) },
+	},
+	{
+		name: "update.window",
+		show: func(st *core.Store) string { return st.UpdateWindow().String() },
+		check: func(v string) error {
+			if _, err := core.ParseWindow(v); err != nil {
+				return errors.New("update.window must be HH:MM-HH:MM in 24h local time, e.g. 02:00-05:00")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error {
+			w, _ := core.ParseWindow(v)
+			return st.WriteUpdateWindow(w)
+		},
+	},
+	{
+		name: "update.mirror",
+		show: func(st *core.Store) string { m, _ := st.Network(); return showURL(m) },
+		check: func(v string) error {
+			if v != "" && !fetch.ValidMirror(v) {
+				return errors.New("update.mirror must be an https URL with no query")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { _, p := st.Network(); return st.WriteNetwork(v, p) },
+	},
+	{
+		name: "update.proxy",
+		show: func(st *core.Store) string { _, p := st.Network(); return showURL(p) },
+		check: func(v string) error {
+			if v != "" && !fetch.ValidProxy(v) {
+				return errors.New("update.proxy must be an http, https or socks5 URL")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { m, _ := st.Network(); return st.WriteNetwork(m, v) },
+	},
+	{
+		name: "notify.webhooks",
+		show: func(st *core.Store) string {
+			var hs []string
+			for _, h := range storeHooks(st) {
+				hs = append(hs, h.Redacted())
+			}
+			return strings.Join(hs, ", ")
+		},
+		check: func(v string) error {
+			_, err := parseHookList(v)
+			return err
+		},
+		loosens: func(st *core.Store, v string) bool {
+			hooks, _ := parseHookList(v)
+			return replacesHooks(st, hooks)
+		},
+		write: func(st *core.Store, v string) error {
+			hooks, _ := parseHookList(v)
+			raw := make([]string, 0, len(hooks))
+			for _, h := range hooks {
+				raw = append(raw, h.String())
+			}
+			return st.WriteWebhooks(raw)
+		},
+	},
+	{
+		name: "notify.otlp",
+		show: func(st *core.Store) string { return showURL(st.TraceEndpoint()) },
+		check: func(v string) error {
+			if v != "" && !tracing.ValidEndpoint(v) {
+				return errors.New("notify.otlp must be an http or https URL with no query")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { return st.WriteTraceEndpoint(v) },
+	},
+	{
+		name: "notify.heartbeat",
+		show: func(st *core.Store) string { return showURL(st.HeartbeatEndpoint()) },
+		check: func(v string) error {
+			if v != "" && !heartbeat.ValidEndpoint(v) {
+				return errors.New("notify.heartbeat must be an absolute http or https URL")
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool { cur := st.HeartbeatEndpoint(); return cur != "" && v != cur },
+		write:   func(st *core.Store, v string) error { return st.WriteHeartbeatEndpoint(v) },
+	},
+	{
+		name: "notify.hung-after",
+		show: func(st *core.Store) string { return st.HungAfter().String() },
+		check: func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil || (d != 0 && d < core.MinHungAfter) {
+				return fmt.Errorf("notify.hung-after must be a duration of at least %s, or 0 for the default", core.MinHungAfter)
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool {
+			d, _ := time.ParseDuration(v)
+			return effectiveHungAfter(d) > st.HungAfter()
+		},
+		write: func(st *core.Store, v string) error {
+			d, _ := time.ParseDuration(v)
+			return st.WriteHungAfter(d)
+		},
+	},
+	{
+		name: "log.level",
+		show: func(st *core.Store) string { l, _ := st.Logging(); return l },
+		check: func(v string) error {
+			if _, ok := parseLogLevel(v); v != "" && !ok {
+				return errors.New("log.level must be debug, info, warn or error")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error {
+			_, f := st.Logging()
+			return st.WriteLogging(strings.ToLower(v), f)
+		},
+	},
+	{
+		name:  "log.format",
+		show:  func(st *core.Store) string { _, f := st.Logging(); return f },
+		check: oneOf("log.format", "", "text", "json"),
+		write: func(st *core.Store, v string) error { l, _ := st.Logging(); return st.WriteLogging(l, v) },
+	},
+	{
+		name: "log.mode",
+		show: func(st *core.Store) string { return fmt.Sprintf("%04o", st.LogMode()) },
+		check: func(v string) error {
+			if _, err := parseLogMode(v); err != nil {
+				return err
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { m, _ := parseLogMode(v); return st.WriteLogMode(m) },
+	},
+	{
+		// Shipping is the off-box copy of the history, so stopping it or
+		// re-keying it away from the reader both loosen.
+		name: "log.ship",
+		show: func(st *core.Store) string { u, _ := st.LogShip(); return showURL(u) },
+		check: func(v string) error {
+			if v != "" && !validShipURL(v) {
+				return errors.New("log.ship must be an https URL, or empty to stop shipping")
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool { u, _ := st.LogShip(); return u != "" && v != u },
+		write:   func(st *core.Store, v string) error { _, k := st.LogShip(); return st.WriteLogShip(v, k) },
+	},
+	{
+		name: "log.ship-key",
+		show: func(st *core.Store) string { _, k := st.LogShip(); return k },
+		check: func(v string) error {
+			if v != "" && !validShipKey(v) {
+				return errors.New("log.ship-key must be a base64 X25519 public key (`platform ship keygen`)")
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool { _, k := st.LogShip(); return k != "" && v != k },
+		write:   func(st *core.Store, v string) error { u, _ := st.LogShip(); return st.WriteLogShip(u, v) },
+	},
+	{
+		// Crash reports are for whoever debugs the daemon, not oversight:
+		// stopping them weakens nothing.
+		name: "crash.report",
+		show: func(st *core.Store) string { return showURL(st.CrashDSN()) },
+		check: func(v string) error {
+			if _, err := crash.ParseDSN(v); v != "" && err != nil {
+				return errors.New("crash.report must be a Sentry DSN, https://KEY@HOST/PROJECT, or empty to keep reports local")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { return st.WriteCrashDSN(v) },
+	},
+	{
+		// A time server can move the trusted clock forward, so naming one
+		// or changing it loosens; turning syncing off only tightens.
+		name: "clock.ntp",
+		show: func(st *core.Store) string { return st.NTP() },
+		check: func(v string) error {
+			if v != "" && !validTimeServer(v) {
+				return errors.New("clock.ntp must be a host or host:port, or empty for none")
+			}
+			return nil
+		},
+		loosens: func(_ *core.Store, v string) bool { return v != "" },
+		write:   func(st *core.Store, v string) error { return st.WriteNTP(v) },
+	},
+	{
+		name: "identity.rotate-every",
+		show: func(st *core.Store) string { return st.RotateEvery().String() },
+		check: func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil || (d != 0 && d < core.MinRotateEvery) {
+				return fmt.Errorf("identity.rotate-every must be a duration of at least %s, or 0 for never", core.MinRotateEvery)
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool {
+			d, _ := time.ParseDuration(v)
+			cur := st.RotateEvery()
+			return cur > 0 && (d == 0 || d > cur)
+		},
+		write: func(st *core.Store, v string) error {
+			d, _ := time.ParseDuration(v)
+			return st.WriteRotateEvery(d)
+		},
+	},
+	{
+		// The passages only change what is typed, never the waits, and a
+		// custom set is held to the built-ins' length (uninstallgate).
+		name: "uninstall.passages",
+		show: func(st *core.Store) string {
+			if v := st.Passages(); !filepath.IsAbs(v) {
+				return showURL(v)
+			}
+			return "file"
+		},
+		check: func(v string) error {
+			if v != "" && !validPassageSource(v) {
+				return errors.New("uninstall.passages must be an absolute file path or an https URL, or empty for the built-in passages")
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { return st.WritePassages(v) },
+	},
+	{
+		// Removing or replacing the partner drops the code the partner
+		// would hold, so both loosen; the outgoing partner is told.
+		name: "uninstall.partner",
+		show: func(st *core.Store) string {
+			if h, ok := partnerHook(st); ok {
+				return h.Redacted()
+			}
+			return ""
+		},
+		check: func(v string) error {
+			if _, err := notify.ParseHook(v); v != "" && err != nil {
+				return errors.New("uninstall.partner must be one FORMAT=URL webhook, FORMAT slack|discord|ntfy|json, or empty for none")
+			}
+			return nil
+		},
+		loosens: func(st *core.Store, v string) bool { cur := st.Partner(); return cur != "" && v != cur },
+		write:   replacePartner,
+	},
+	{
+		name: "priority",
+		show: func(st *core.Store) string { return strconv.Itoa(st.Nice()) },
+		check: func(v string) error {
+			if n, err := strconv.Atoi(v); err != nil || n < 0 || n > core.MaxNice {
+				return fmt.Errorf("priority must be a nice value 0..%d", core.MaxNice)
+			}
+			return nil
+		},
+		write: func(st *core.Store, v string) error { n, _ := strconv.Atoi(v); return st.WriteNice(n) },
+	},
+}
+
+// validShipURL mirrors platform logship.ValidURL: an absolute https URL.
+func validShipURL(v string) bool {
+	u, err := url.Parse(v)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// validShipKey accepts a base64 X25519 public key: 32 bytes. The platform
+// parses it again before use.
+func validShipKey(v string) bool {
+	b, err := base64.StdEncoding.DecodeString(v)
+	return err == nil && len(b) == 32
+}
+
+// oneOf checks v against a fixed set; "" in allowed lets the key be cleared.
+func oneOf(name string, allowed ...string) func(string) error {
+	return func(v string) error {
+		if slices.Contains(allowed, v) {
+			return nil
+		}
+		named := slices.DeleteFunc(slices.Clone(allowed), func(a string) bool { return a == "" })
+		return fmt.Errorf("%s must be one of %s", name, strings.Join(named, ", "))
+	}
+}
+
+// parseHookList parses a comma-separated FORMAT=URL list ("" ⇒ none).
+func parseHookList(v string) ([]notify.Hook, error) {
+	var hooks []notify.Hook
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		h, err := notify.ParseHook(s)
+		if err != nil {
+			return nil, errors.New("notify.webhooks must be a comma-separated FORMAT=URL list, FORMAT slack|discord|ntfy|json")
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+// parseLogMode reads an octal file mode for log.mode; "" is the default.
+func parseLogMode(v string) (os.FileMode, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 8, 32)
+	if m := os.FileMode(n); err == nil && slices.Contains(core.LogModes, m) {
+		return m, nil
+	}
+	return 0, errors.New("log.mode must be 0600, 0640 or 0644")
+}
+
+// showURL is a URL's scheme and host, "…" standing for any path.
+func showURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "?"
+	}
+	s := u.Scheme + "://" + u.Host
+	if strings.Trim(u.Path, "/") != "" {
+		s += "/…"
+	}
+	return s
+}
+
+func lookupConfigKey(name string) (configKey, bool) {
+	for _, k := range configKeys {
+		if k.name == name {
+			return k, true
+		}
+	}
+	return configKey{}, false
+}
+
+// doConfig is `daemon config`: read and write the runtime settings the other
+// verbs persist, from one place.
+//
+//	daemon config [--workdir D]                 — every key and its value
+//	daemon config [--workdir D] get KEY         — one value, bare, for scripts
+//	daemon config [--workdir D] set KEY VALUE   — validate, write, reload the mesh
+//
+// A set is checked first and refused whole on a bad value, and a change that
+// weakens protection (clearing the webhooks or the heartbeat, a longer
+// notify.hung-after) is refused under a strict lock, as its own verb would.
+// After the write the running workers get SIGHUP: they re-read the settings
+// on the spot, and restart the platform when its environment would differ,
+// so the answer to "did it pick it up" is yes. An empty VALUE clears a key.
+func doConfig(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	rest := fs.Args()
+	verb := "list"
+	if len(rest) > 0 {
+		verb, rest = rest[0], rest[1:]
+	}
+	switch {
+	case verb == "list" && len(rest) == 0, verb == "get" && len(rest) <= 1, verb == "set" && len(rest) == 2:
+	default:
+		fmt.Fprintln(os.Stderr, "usage: daemon config [--workdir D] [get [KEY] | set KEY VALUE]")
+		return 2
+	}
+	if len(rest) > 0 {
+		if _, ok := lookupConfigKey(rest[0]); !ok {
+			fmt.Fprintf(os.Stderr, "config: unknown key %q (see: daemon config)\n", rest[0])
+			return 2
+		}
+	}
+	if verb == "set" {
+		k, _ := lookupConfigKey(rest[0])
+		if err := k.check(rest[1]); err != nil {
+			fmt.Fprintln(os.Stderr, "config:", err)
+			return 2
+		}
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "config: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	switch {
+	case verb == "set":
+		return setConfig(st, rest[0], rest[1], reloadMesh, os.Stdout)
+	case len(rest) == 1:
+		k, _ := lookupConfigKey(rest[0])
+		fmt.Fprintln(os.Stdout, k.show(st))
+		return 0
+	}
+	printConfig(st, os.Stdout)
+	return 0
+}
+
+func printConfig(st *core.Store, out io.Writer) {
+	for _, k := range configKeys {
+		v := k.show(st)
+		if v == "" {
+			v = "(unset)"
+		}
+		fmt.Fprintf(out, "  %-18s %s\n", k.name, v)
+	}
+}
+
+// setConfig writes one checked value and asks the mesh of st to reload.
+// The result goes to out, a refusal or failure to stderr. Returns the exit
+// code.
+func setConfig(st *core.Store, name, v string, reload func(workdir string) (int, error), out io.Writer) int {
+	k, _ := lookupConfigKey(name)
+	if k.loosens != nil && k.loosens(st, v) && refuseWhileLocked(st, "config set "+name, os.Stderr) {
+		return 1
+	}
+	if err := k.write(st, v); err != nil {
+		fmt.Fprintln(os.Stderr, "  config: write failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	shown := k.show(st)
+	if shown == "" {
+		shown = "(unset)"
+	}
+	fmt.Fprintf(out, "  config: %s = %s\n", name, shown)
+	n, err := reload(st.Dir)
+	switch {
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "  config: reload failed; the mesh picks it up when it next starts")
+	case n == 0:
+		fmt.Fprintln(out, "  config: no running mesh for this install; it applies at the next start")
+	default:
+		fmt.Fprintf(out, "  config: %d worker(s) reloaded\n", n)
+	}
+	return 0
+}
+
+// reloadMesh sends SIGHUP to the mesh workers of the install at workdir, and
+// returns how many took it. An install other than the discovered one (an
+// explicit --workdir, a test root) has no mesh to reload.
+func reloadMesh(workdir string) (int, error) {
+	if !osSupportsLaunchd() {
+		return 0, nil
+	}
+	cur, err := osadapter.FindCurrentInstall(mode.Resolve(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if cur.Workdir == "" || cur.Workdir != workdir {
+		return 0, nil
+	}
+	spec := osadapter.Spec{Mode: cur.Mode, Roster: cur.Roster}
+	n := 0
+	for _, r := range []osadapter.Role{osadapter.RoleA, osadapter.RoleB} {
+		if osadapter.ReloadJob(cur.Mode, spec.Label(r)) == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// reloadPlatform is a worker's SIGHUP: when it supervises the platform and
+// the child's settings changed since it started, stop the child so the tick
+// that follows starts it with the new ones. The stop is deliberate, so its
+// exit is forgotten rather than counted as a crash. Everything else the loop
+// reads from the store it re-reads on that tick anyway.
+func reloadPlatform(e *core.Executor, log *slog.Logger) {
+	p, ok := e.Plat.(*platformsvc.ProcSvc)
+	if !ok || !e.HoldsPlatformLock() || !p.SettingsChanged() {
+		log.Info("reload: settings re-read")
+		return
+	}
+	log.Info("reload: platform settings changed; restarting it")
+	_ = p.Stop()
+	p.ClearExit()
+}
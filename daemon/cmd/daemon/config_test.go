@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestConfigKeysRejectBadValues(t *testing.T) {
+	bad := map[string]string{
+		"channel":               "nightly",
+		"update.window":         "25:00-03:00",
+		"update.mirror":         "http://mirror.example.com",
+		"notify.webhooks":       "carrier-pigeon=https://x",
+		"notify.hung-after":     "10s",
+		"log.level":             "loud",
+		"log.format":            "xml",
+		"priority":              "40",
+		"log.mode":              "0666",
+		"identity.rotate-every": "1h",
+		"clock.ntp":             "udp://time.example.com/x",
+		"uninstall.passages":    "http://example.com/mine.txt",
+		"log.ship":              "http://ship.example.com/in",
+		"log.ship-key":          "c2hvcnQ=",
+		"crash.report":          "http://key@errors.example.com/7",
+	}
+	for name, v := range bad {
+		k, ok := lookupConfigKey(name)
+		if !ok {
+			t.Fatalf("key %s missing", name)
+		}
+		if k.check(v) == nil {
+			t.Errorf("%s accepted %q", name, v)
+		}
+	}
+	if run([]string{"config", "--workdir", t.TempDir(), "set", "channel", "nightly"}) != 2 {
+		t.Fatal("a bad value must be a usage error")
+	}
+	if run([]string{"config", "--workdir", t.TempDir(), "get", "no.such.key"}) != 2 {
+		t.Fatal("an unknown key must be a usage error")
+	}
+}
+
+func TestSetConfigWritesAndReloads(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var reloaded string
+	reload := func(wd string) (int, error) { reloaded = wd; return 2, nil }
+	var out bytes.Buffer
+	if code := setConfig(st, "channel", "beta", reload, &out); code != 0 || st.Channel() != core.ChannelBeta {
+		t.Fatalf("code %d, channel %q\n%s", code, st.Channel(), out.String())
+	}
+	if reloaded != st.Dir || !strings.Contains(out.String(), "2 worker(s) reloaded") {
+		t.Fatalf("reload not asked for this install (%q)\n%s", reloaded, out.String())
+	}
+
+	out.Reset()
+	noMesh := func(string) (int, error) { return 0, nil }
+	if code := setConfig(st, "notify.hung-after", "10m", noMesh, &out); code != 0 || st.HungAfter() != 10*time.Minute {
+		t.Fatalf("hung-after: code %d, %s\n%s", code, st.HungAfter(), out.String())
+	}
+	if !strings.Contains(out.String(), "applies at the next start") {
+		t.Fatalf("no-mesh note missing:\n%s", out.String())
+	}
+}
+
+func TestSetConfigRefusesLooseningUnderLock(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	noMesh := func(string) (int, error) { return 0, nil }
+	var out bytes.Buffer
+	setConfig(st, "notify.heartbeat", "https://beat.example.com/in", noMesh, &out)
+	applyLock(st, 1, time.Now(), strings.NewReader("lock for 1 days\n"), &bytes.Buffer{})
+
+	out.Reset()
+	if code := setConfig(st, "notify.heartbeat", "", noMesh, &out); code != 1 || st.HeartbeatEndpoint() == "" {
+		t.Fatalf("cleared the heartbeat under a lock: code %d\n%s", code, out.String())
+	}
+	out.Reset()
+	if code := setConfig(st, "log.level", "debug", noMesh, &out); code != 0 {
+		t.Fatalf("a neutral change refused under a lock: code %d\n%s", code, out.String())
+	}
+}
+
+// TestSetConfigRefusesRepointingUnderLock: swapping a configured alert or
+// log sink for another defeats it like clearing it, so the lock refuses it.
+func TestSetConfigRefusesRepointingUnderLock(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	noMesh := func(string) (int, error) { return 0, nil }
+	set := func(k, v string) int { return setConfig(st, k, v, noMesh, io.Discard) }
+	for k, v := range map[string]string{
+		"notify.webhooks":  "slack=https://hooks.example.com/mine",
+		"notify.heartbeat": "https://beat.example.com/in",
+		"log.ship":         "https://ship.example.com/in",
+	} {
+		if code := set(k, v); code != 0 {
+			t.Fatalf("%s: set before the lock: code %d", k, code)
+		}
+	}
+	applyLock(st, 1, time.Now(), strings.NewReader("lock for 1 days\n"), &bytes.Buffer{})
+
+	if set("notify.webhooks", "slack=https://hooks.example.com/other") != 1 ||
+		len(st.Webhooks()) != 1 || !strings.HasSuffix(st.Webhooks()[0], "/mine") {
+		t.Fatalf("re-pointed the webhooks under a lock: %v", st.Webhooks())
+	}
+	if set("notify.heartbeat", "https://other.example.com/in") != 1 || st.HeartbeatEndpoint() != "https://beat.example.com/in" {
+		t.Fatalf("re-pointed the heartbeat under a lock: %q", st.HeartbeatEndpoint())
+	}
+	if set("log.ship", "https://other.example.com/in") != 1 {
+		t.Fatal("re-pointed log shipping under a lock")
+	}
+	if u, _ := st.LogShip(); u != "https://ship.example.com/in" {
+		t.Fatalf("log.ship = %q after a refusal", u)
+	}
+	if set("notify.heartbeat", "https://beat.example.com/in") != 0 {
+		t.Fatal("re-setting the same heartbeat refused under a lock")
+	}
+}
+
+func TestPrintConfigShowsHostsOnly(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	if err := st.WriteTraceEndpoint("https://collector.example.com:4318/tenant/s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printConfig(st, &out)
+	if strings.Contains(out.String(), "s3cret") || !strings.Contains(out.String(), "https://collector.example.com:4318/…") {
+		t.Fatalf("URL not reduced to its host:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "update.window      (unset)") {
+		t.Fatalf("unset key not marked:\n%s", out.String())
+	}
+}
@@ -71,7 +71,10 @@ func TestRunSwitchHasNoConvergeVerb(t *testing.T) {
 		"version": true, "-v": true, "--version": true,
 		"run": true, "once": true, "update": true, "ensure": true,
 		"install": true, "uninstall": true, "watchdog": true,
-		"self-update": true, "status": true,
+		"self-update": true, "status": true, "notify": true, "diag": true, "api": true,
+		"lock": true, "calendar": true, "verify": true, "self-test": true,
+		"priority": true, "decoys": true, "logging": true, "log-level": true, "healthcheck": true, "config": true,
+		"rotate-identity": true,
 	}
 	for v := range verbs {
 		if !allowed[v] {
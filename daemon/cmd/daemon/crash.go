@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/crash"
+)
+
+// crashSubmitTimeout bounds one report's submission; a slow collector must
+// not hold up a worker's boot or the watchdog pass.
+const crashSubmitTimeout = 10 * time.Second
+
+// armCrashes points this process's crash output at the install's crash
+// directory for as long as it runs. A failure to arm costs the report,
+// never the run. The returned func disarms.
+func armCrashes(st *core.Store, log *slog.Logger) func() {
+	disarm, err := crash.Arm(st.CrashDir())
+	if err != nil {
+		log.Warn("crash reports off", "err", fmt.Sprintf("%T", err))
+		return func() {}
+	}
+	return disarm
+}
+
+// collectCrashes sweeps the crash directory, records each new crash in the
+// store for `daemon status`, and submits it when crash.report names a DSN.
+// It returns how many it found. Both the restarted worker and the watchdog
+// sweep; the sweep's rename makes sure each crash is counted once.
+func collectCrashes(st *core.Store, submit func(context.Context, crash.DSN, crash.Report) error, log *slog.Logger) int {
+	reports, err := crash.Sweep(st.CrashDir(), crash.Alive)
+	if err != nil {
+		log.Warn("crash sweep", "err", fmt.Sprintf("%T", err))
+		return 0
+	}
+	if len(reports) == 0 {
+		return 0
+	}
+	cs := make([]core.Crash, 0, len(reports))
+	for _, r := range reports {
+		cs = append(cs, core.Crash{At: r.At.UTC(), Kind: r.Kind, Summary: r.Summary})
+		log.Error("worker crashed", "kind", r.Kind, "at", r.At.UTC().Format(time.RFC3339))
+	}
+	if err := st.RecordCrashes(cs...); err != nil {
+		log.Warn("crash not recorded", "err", fmt.Sprintf("%T", err))
+	}
+	if v := st.CrashDSN(); v != "" {
+		dsn, err := crash.ParseDSN(v)
+		if err != nil {
+			log.Warn("crash reports not sent: bad DSN")
+			return len(reports)
+		}
+		for _, r := range reports {
+			ctx, cancel := context.WithTimeout(context.Background(), crashSubmitTimeout)
+			if err := submit(ctx, dsn, r); err != nil {
+				log.Warn("crash report not sent", "err", fmt.Sprintf("%T", err))
+			}
+			cancel()
+		}
+	}
+	return len(reports)
+}
+
+// submitCrash sends one report through the proxy-aware client the passages
+// use.
+func submitCrash(st *core.Store) func(context.Context, crash.DSN, crash.Report) error {
+	return func(ctx context.Context, dsn crash.DSN, r crash.Report) error {
+		return crash.Submit(ctx, passageClient(st), dsn, r, version)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/crash"
+)
+
+// TestCollectCrashes: a dead worker's crash file is recorded once and
+// submitted only while crash.report is set.
+func TestCollectCrashes(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sent := 0
+	submit := func(context.Context, crash.DSN, crash.Report) error { sent++; return nil }
+	plant := func(name string) {
+		if err := os.MkdirAll(st.CrashDir(), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		body := "panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/main.go:3 +0x1\n"
+		if err := os.WriteFile(filepath.Join(st.CrashDir(), name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plant("00000000000000000001-0.arm")
+	if n := collectCrashes(st, submit, log); n != 1 || sent != 0 {
+		t.Fatalf("collect = %d, sent %d; want 1 recorded, none sent", n, sent)
+	}
+	if n := collectCrashes(st, submit, log); n != 0 {
+		t.Fatalf("second sweep found %d; a crash must be counted once", n)
+	}
+
+	if err := st.WriteCrashDSN("https://key@errors.example.com/7"); err != nil {
+		t.Fatal(err)
+	}
+	plant("00000000000000000002-0.arm")
+	if n := collectCrashes(st, submit, log); n != 1 || sent != 1 {
+		t.Fatalf("collect = %d, sent %d; want 1 and 1", n, sent)
+	}
+	if cs := st.Crashes(); len(cs) != 2 || cs[1].Kind != "panic" {
+		t.Fatalf("recorded %+v", cs)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+// traceCheckInterval throttles the sibling debugger check: finding the
+// sibling's PID costs a `launchctl print`, too much for every 2s tick, and
+// a few seconds under a debugger is not enough to take a worker apart.
+const traceCheckInterval = 5 * time.Second
+
+// peerRole is the run worker that role watches for a debugger: A and B
+// watch each other, since a stopped process cannot report on itself. The
+// ensure job is not long-lived and has no peer.
+func peerRole(role string) (osadapter.Role, bool) {
+	switch osadapter.Role(role) {
+	case osadapter.RoleA:
+		return osadapter.RoleB, true
+	case osadapter.RoleB:
+		return osadapter.RoleA, true
+	}
+	return "", false
+}
+
+// tendTraced restarts the sibling worker when a debugger is attached to it:
+// the kernel flags a ptrace-attached process, and `launchctl kickstart -k`
+// kills it out from under the debugger and starts a clean one. It is logged
+// and sent as a debugger_attached event. Reports whether a restart was
+// attempted.
+func tendTraced(peer osadapter.Role, label string,
+	pid func(label string) int, traced func(pid int) (bool, error), restart func(label string) error,
+	send func(notify.Event), log *slog.Logger) bool {
+	p := pid(label)
+	if p <= 0 {
+		return false
+	}
+	if on, err := traced(p); err != nil || !on {
+		return false
+	}
+	restarted := restart(label) == nil
+	log.Warn("debugger attached to mesh worker", "role", string(peer), "restarted", restarted)
+	msg := "a debugger was attached to a protection worker; it was restarted"
+	if !restarted {
+		msg = "a debugger was attached to a protection worker; restarting it failed"
+	}
+	send(notify.Event{Kind: notify.DebuggerAttached, At: time.Now(), Message: msg,
+		Details: map[string]string{"role": string(peer), "restarted": fmt.Sprint(restarted)}})
+	return true
+}
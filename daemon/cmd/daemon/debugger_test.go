@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+func TestPeerRole(t *testing.T) {
+	if p, ok := peerRole("a"); !ok || p != osadapter.RoleB {
+		t.Fatalf("a watches %q", p)
+	}
+	if p, ok := peerRole("b"); !ok || p != osadapter.RoleA {
+		t.Fatalf("b watches %q", p)
+	}
+	if _, ok := peerRole("ensure"); ok {
+		t.Fatal("ensure has no peer")
+	}
+}
+
+func TestTendTraced(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var events []notify.Event
+	send := func(ev notify.Event) { events = append(events, ev) }
+	var restarted []string
+	restart := func(l string) error { restarted = append(restarted, l); return nil }
+	pid := func(string) int { return 42 }
+	clean := func(int) (bool, error) { return false, nil }
+	attached := func(p int) (bool, error) { return p == 42, nil }
+
+	if tendTraced(osadapter.RoleB, "lbl", pid, clean, restart, send, log) || len(restarted)+len(events) != 0 {
+		t.Fatal("an untraced sibling must be left alone")
+	}
+	if tendTraced(osadapter.RoleB, "lbl", func(string) int { return 0 }, attached, restart, send, log) {
+		t.Fatal("a sibling that is not running has nothing to check")
+	}
+	if tendTraced(osadapter.RoleB, "lbl", pid, func(int) (bool, error) { return true, errors.New("gone") }, restart, send, log) {
+		t.Fatal("an unreadable flag is not an attach")
+	}
+
+	if !tendTraced(osadapter.RoleB, "lbl", pid, attached, restart, send, log) {
+		t.Fatal("traced sibling not handled")
+	}
+	if len(restarted) != 1 || restarted[0] != "lbl" {
+		t.Fatalf("restarted %v, want the sibling's label", restarted)
+	}
+	if len(events) != 1 || events[0].Kind != notify.DebuggerAttached || events[0].Details["restarted"] != "true" {
+		t.Fatalf("events %+v", events)
+	}
+
+	events = nil
+	tendTraced(osadapter.RoleA, "lbl", pid, attached, func(string) error { return errors.New("denied") }, send, log)
+	if len(events) != 1 || events[0].Details["restarted"] != "false" {
+		t.Fatalf("a failed restart must still be reported: %+v", events)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/lockin"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+// decoyTightenLock is how much strict lock a removed decoy adds when
+// tightening is on: a day, enough to outlast the moment.
+const decoyTightenLock = 24 * time.Hour
+
+// doDecoys is `daemon decoys`: show the decoy launchd entries, or choose
+// whether removing one tightens the lock.
+//
+//	daemon decoys                 — how many decoys the install keeps
+//	daemon decoys --tighten on    — a removed decoy extends the strict lock a day
+//
+// The decoys are look-alike launchd entries beside the mesh; the labels are
+// never printed. A removed one is put back on the next tick and reported as
+// a decoy_removed event. Under a strict lock --tighten off refuses.
+func doDecoys(args []string) int {
+	fs := flag.NewFlagSet("decoys", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	tighten := fs.String("tighten", "", "on|off: whether removing a decoy extends the strict lock by a day")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tighten != "" && *tighten != "on" && *tighten != "off" {
+		fmt.Fprintln(os.Stderr, "decoys: --tighten must be on or off")
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "decoys: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	if *tighten == "off" && st.DecoyTighten() && refuseWhileLocked(st, "decoys", os.Stderr) {
+		return 1
+	}
+	return configureDecoys(st, *tighten, os.Stdout)
+}
+
+// configureDecoys applies --tighten (when set) and prints the decoy state.
+func configureDecoys(st *core.Store, tighten string, out io.Writer) int {
+	if tighten != "" {
+		if err := st.WriteDecoyTighten(tighten == "on"); err != nil {
+			fmt.Fprintln(out, "  decoys: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	if n := len(st.Decoys()); n == 0 {
+		fmt.Fprintln(out, "  decoys: none yet (a mesh worker makes them on its next tick)")
+	} else {
+		fmt.Fprintf(out, "  decoys: %d launchd look-alike(s) beside the mesh\n", n)
+	}
+	if st.DecoyTighten() {
+		fmt.Fprintln(out, "  decoys: removing one extends the strict lock by", lockin.FormatLeft(decoyTightenLock))
+	} else {
+		fmt.Fprintln(out, "  decoys: removal is reported, not acted on (--tighten on to extend the lock)")
+	}
+	return 0
+}
+
+// tendDecoys is the lock holder's per-tick decoy pass: make the decoys the
+// first time, then put back any that were removed, log and report it, and
+// extend the strict lock when tightening is on. The first install is not a
+// removal.
+func tendDecoys(st *core.Store, spec osadapter.Spec, now time.Time,
+	ensure func(osadapter.Spec, []core.Decoy) (int, error), send func(notify.Event), log *slog.Logger) {
+	ds := st.Decoys()
+	fresh := len(ds) == 0
+	if fresh {
+		ds = osadapter.NewDecoys(osadapter.DecoyCount, spec.Workdir)
+		if err := st.WriteDecoys(ds); err != nil {
+			log.Warn("record decoys", "err", fmt.Sprintf("%T", err))
+			return
+		}
+	}
+	restored, err := ensure(spec, ds)
+	if err != nil {
+		log.Warn("ensure-decoys", "err", err)
+	}
+	if fresh || restored == 0 {
+		return
+	}
+	log.Warn("decoy removed", "count", restored)
+	send(notify.Event{Kind: notify.DecoyRemoved, At: now,
+		Message: fmt.Sprintf("%d decoy launchd job(s) removed and put back", restored),
+		Details: map[string]string{"count": fmt.Sprint(restored)}})
+	if !st.DecoyTighten() {
+		return
+	}
+	if err := st.UpdateStrictLock(func(l lockin.Lock) lockin.Lock { return l.Extend(now, decoyTightenLock) }); err != nil {
+		log.Warn("decoy tighten", "err", fmt.Sprintf("%T", err))
+		return
+	}
+	log.Info("strict lock extended after a decoy removal", "left", lockin.FormatLeft(st.StrictLock().Remaining(now)))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+func TestTendDecoys(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	spec := osadapter.Spec{Workdir: st.Dir}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	now := time.Now()
+	var events []notify.Event
+	send := func(ev notify.Event) { events = append(events, ev) }
+	restore := 0
+	ensure := func(_ osadapter.Spec, ds []core.Decoy) (int, error) {
+		if restore < 0 {
+			return len(ds), nil // first install: every decoy is new
+		}
+		return restore, nil
+	}
+
+	restore = -1
+	tendDecoys(st, spec, now, ensure, send, log)
+	if len(st.Decoys()) != osadapter.DecoyCount || len(events) != 0 {
+		t.Fatalf("first pass: %d decoys, %d events; want %d and none", len(st.Decoys()), len(events), osadapter.DecoyCount)
+	}
+	made := st.Decoys()
+
+	restore = 1
+	tendDecoys(st, spec, now, ensure, send, log)
+	if len(events) != 1 || events[0].Kind != notify.DecoyRemoved || st.StrictLock().Active(now) {
+		t.Fatalf("removal without tightening: events %v, lock %v", events, st.StrictLock())
+	}
+	if st.Decoys()[0] != made[0] {
+		t.Fatal("decoys must keep their identity across passes")
+	}
+
+	if err := st.WriteDecoyTighten(true); err != nil {
+		t.Fatal(err)
+	}
+	tendDecoys(st, spec, now, ensure, send, log)
+	if left := st.StrictLock().Remaining(now); left < decoyTightenLock {
+		t.Fatalf("tightening must extend the lock a day, left %s", left)
+	}
+
+	restore = 0
+	events = nil
+	tendDecoys(st, spec, now, ensure, send, log)
+	if len(events) != 0 {
+		t.Fatalf("intact decoys reported: %v", events)
+	}
+}
+
+func TestConfigureDecoys(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	if code := configureDecoys(st, "", &out); code != 0 || !strings.Contains(out.String(), "none yet") {
+		t.Fatalf("code %d\n%s", code, out.String())
+	}
+	out.Reset()
+	if code := configureDecoys(st, "on", &out); code != 0 || !st.DecoyTighten() || !strings.Contains(out.String(), "extends the strict lock") {
+		t.Fatalf("--tighten on: code %d\n%s", code, out.String())
+	}
+	if run([]string{"decoys", "--tighten", "maybe", "--workdir", t.TempDir()}) != 2 {
+		t.Fatal("--tighten maybe must be a usage error")
+	}
+}
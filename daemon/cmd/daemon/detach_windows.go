@@ -0,0 +1,6 @@
+package main
+
+import "os/exec"
+
+// detach is a no-op on windows, where there is no launchd mesh to rotate.
+func detach(*exec.Cmd) {}
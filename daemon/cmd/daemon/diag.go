@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/diag"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
+	"github.com/eliteGoblin/focusd/daemon/internal/platformsvc"
+	"github.com/eliteGoblin/focusd/daemon/internal/status"
+	"github.com/eliteGoblin/focusd/daemon/internal/status/redact"
+)
+
+// platformEventsName mirrors the platform's eventlog.FileName (the JSONL
+// protection-event stream beside state.db); separate modules, so a literal.
+const platformEventsName = "svc.jsonl"
+
+// diagSources are the IO seams collectDiag reads through, so a test can
+// build a bundle without launchd or a live install.
+type diagSources struct {
+	status    func() []byte            // `daemon status --json` output
+	meshPrint func() ([]string, error) // scrubbed `launchctl print` per mesh job
+}
+
+// doDiag is `daemon diag export`: write a redacted diagnostics bundle for a
+// bug report.
+//
+//	daemon diag export [--out FILE] [--lines N] [--workdir DIR]
+//
+// The bundle holds the status report (mesh, versions, restore-chain health),
+// a sanitized config summary, the tail of the daemon and platform logs and
+// the platform event stream, and launchd's view of each mesh job. Disguised
+// paths and labels are scrubbed before anything is written; webhook and
+// collector URLs are never read into it. The output file is the operator's
+// choice (default: a timestamped name in the current directory) and is the
+// only path printed.
+func doDiag(args []string) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(os.Stderr, "usage: daemon diag export [--out FILE] [--lines N] [--workdir DIR]")
+		return 2
+	}
+	now := time.Now()
+	fs := flag.NewFlagSet("diag export", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	out := fs.String("out", "diag-"+now.Format("20060102-150405")+".tar.gz", "bundle file to write")
+	lines := fs.Int("lines", diag.DefaultLines, "trailing lines kept per log")
+	if err := fs.Parse(args[1:]); err != nil || *lines <= 0 {
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "diag: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	src := diagSources{
+		status: func() []byte {
+			snap, pd := status.Gather(*wd, true)
+			snap.BakedFallback = defaultPlatformVersion
+			pv, pok := pd.Verdict()
+			var b bytes.Buffer
+			status.RenderJSON(snap, status.Combine(status.Assess(snap), pv, pok), pd, &b)
+			return b.Bytes()
+		},
+		meshPrint: func() ([]string, error) { return osadapter.MeshPrint(mode.Resolve()) },
+	}
+	files := collectDiag(workdir, *lines, src, now)
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "diag: cannot create the output file:", err)
+		return 1
+	}
+	werr := diag.WriteTarGz(f, strings.TrimSuffix(filepath.Base(*out), ".tar.gz"), files, now)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		os.Remove(*out)
+		fmt.Fprintln(os.Stderr, "diag: write failed:", werr)
+		return 1
+	}
+	fmt.Printf("  diag: wrote %s (%d files)\n", *out, len(files))
+	return 0
+}
+
+// collectDiag assembles the bundle members for the install at workdir. Every
+// member is redaction-safe on return; a source that cannot be read becomes a
+// short "unavailable" note rather than failing the export.
+func collectDiag(workdir string, lines int, src diagSources, now time.Time) []diag.File {
+	platWD := platdir.Read(workdir)
+	if platWD == "" {
+		platWD = workdir // legacy single-root layout
+	}
+	scrub := func(s string) string { return redact.Scrub(s, workdir, platWD) }
+
+	manifest, _ := json.MarshalIndent(map[string]string{
+		"created_at":     now.UTC().Format(time.RFC3339),
+		"daemon_version": version,
+		"os":             runtime.GOOS,
+		"arch":           runtime.GOARCH,
+		"mode":           string(mode.Resolve()),
+	}, "", "  ")
+	cfg, _ := json.MarshalIndent(diag.SanitizedConfig(&core.Store{Dir: workdir}), "", "  ")
+	files := []diag.File{
+		{Name: "manifest.json", Data: manifest},
+		{Name: "status.json", Data: []byte(scrub(string(src.status())))},
+		{Name: "config.json", Data: cfg},
+	}
+
+	for _, l := range []struct{ name, path string }{
+		{"logs/daemon.log", osadapter.LogPath(workdir)},
+		{"logs/platform.log", filepath.Join(platWD, platformsvc.PlatformLogName)},
+		{"logs/events.jsonl", filepath.Join(platWD, platformEventsName)},
+	} {
+		b, err := diag.Tail(l.path, lines)
+		if err != nil {
+			b = []byte("unavailable: " + scrub(err.Error()) + "\n")
+		}
+		files = append(files, diag.File{Name: l.name, Data: []byte(scrub(string(b)))})
+	}
+
+	var lc strings.Builder
+	prints, err := src.meshPrint()
+	if err != nil {
+		fmt.Fprintf(&lc, "unavailable: %s\n", scrub(err.Error()))
+	}
+	for i, p := range prints {
+		fmt.Fprintf(&lc, "--- mesh job %d ---\n%s\n", i+1, p)
+	}
+	return append(files, diag.File{Name: "launchctl.txt", Data: []byte(lc.String())})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+// TestCollectDiagIsRedacted pins that no member of the bundle carries the
+// workdir path or a webhook URL, even when the logs and errors contain them.
+func TestCollectDiagIsRedacted(t *testing.T) {
+	wd := t.TempDir()
+	st := &core.Store{Dir: wd}
+	if err := st.WriteDesired("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	_ = st.WriteWebhooks([]string{"slack=https://hooks.slack.com/services/SECRET"})
+	log := "level=WARN msg=ensure-all err=\"open " + wd + "/bin: denied\"\nlevel=INFO msg=\"mesh recreated\"\n"
+	if err := os.WriteFile(filepath.Join(wd, osadapter.DaemonLogName), []byte(log), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src := diagSources{
+		status:    func() []byte { return []byte(`{"verdict":"HEALTHY","workdir":"` + wd + `"}`) },
+		meshPrint: func() ([]string, error) { return nil, errors.New("stat " + wd + ": denied") },
+	}
+	files := collectDiag(wd, 50, src, time.Now())
+
+	names := map[string]string{}
+	for _, f := range files {
+		names[f.Name] = string(f.Data)
+		if strings.Contains(string(f.Data), wd) || strings.Contains(string(f.Data), "SECRET") {
+			t.Errorf("%s leaks a secret:\n%s", f.Name, f.Data)
+		}
+	}
+	for _, want := range []string{"manifest.json", "status.json", "config.json", "logs/daemon.log", "logs/platform.log", "logs/events.jsonl", "launchctl.txt"} {
+		if _, ok := names[want]; !ok {
+			t.Errorf("bundle missing %s", want)
+		}
+	}
+	if !strings.Contains(names["logs/daemon.log"], "mesh recreated") || !strings.Contains(names["config.json"], `"slack"`) {
+		t.Errorf("bundle lost diagnostic content:\n%s\n%s", names["logs/daemon.log"], names["config.json"])
+	}
+	if !strings.HasPrefix(names["logs/platform.log"], "unavailable:") {
+		t.Errorf("missing platform log should be noted, got %q", names["logs/platform.log"])
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+// The exit-code contract of the commands scripts branch on — `daemon
+// status`, `daemon verify`, `daemon self-test` and `daemon update` — shared
+// with `platform scan`. Cron jobs and MDM scripts read these, so a number
+// never changes meaning; a new outcome gets a new number. Each command
+// also takes --quiet: nothing on stdout, errors still on stderr, and the
+// exit code is the whole answer.
+//
+// The other verbs keep the older convention: 0 done, 1 failed, 2 usage.
+// `daemon healthcheck` alone answers in the monitoring-plugin convention
+// (0 ok, 1 warning, 2 critical) that Nagios and its kin expect.
+const (
+	// exitOK: healthy, intact, up to date, or the requested change applied.
+	exitOK = 0
+	// exitError: the command could not find out or do its job (install
+	// unreadable, release resolve failed).
+	exitError = 1
+	// exitDegraded: running, but not fully well (status DEGRADED, a verify
+	// or self-test check failed).
+	exitDegraded = 2
+	// exitNotRunning: nothing is protecting (status DOWN, no install found).
+	exitNotRunning = 3
+	// exitUpdateAvailable: `update --check` found a release this install
+	// would move to.
+	exitUpdateAvailable = 4
+	// exitUsage: bad flags or arguments (sysexits EX_USAGE), kept clear of
+	// the health codes above.
+	exitUsage = 64
+)
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+// A foreground run is a contributor's attached debug run; launchd's mesh
+// workers must never start that way.
+func TestLoopRefusesForegroundMesh(t *testing.T) {
+	if got := loop([]string{"--foreground", "--mesh", "--workdir", t.TempDir()}, true); got != 2 {
+		t.Fatalf("run --foreground --mesh = %d, want 2", got)
+	}
+}
+
+func TestParseForeground(t *testing.T) {
+	if o := parse("run", []string{"--workdir", t.TempDir()}); o.foreground {
+		t.Fatal("foreground set without --foreground")
+	}
+	if o := parse("run", []string{"--foreground", "--workdir", t.TempDir()}); !o.foreground {
+		t.Fatal("--foreground not parsed")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/status"
+)
+
+// healthBudget bounds the whole check: a monitor that fires every minute
+// must never stack up behind a wedged launchctl.
+const healthBudget = 900 * time.Millisecond
+
+// doHealthcheck is `daemon healthcheck`, for launchd, Nagios and cron: are
+// the mesh jobs loaded, is the heartbeat fresh, is the platform up. One
+// summary line, and exit 0 (ok), 1 (warning), 2 (critical) or 3 (unknown),
+// the plugin convention rather than the status contract in exitcode.go,
+// since the tools that run it read it that way. A check that overruns its
+// budget is critical; one given bad arguments is unknown, never exitUsage,
+// which a monitor would misread.
+//
+//	daemon healthcheck
+//	*/5 * * * * daemon healthcheck >/dev/null || notify-me
+func doHealthcheck(args []string) int {
+	return runHealthcheck(args, status.ProbeHealth, os.Stdout)
+}
+
+// runHealthcheck parses args and runs the check, for doHealthcheck and tests.
+func runHealthcheck(args []string, probe func() status.Probe, out io.Writer) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		fmt.Fprintf(out, "%s - usage: daemon healthcheck\n", status.HealthUnknown)
+		return int(status.HealthUnknown)
+	}
+	return healthcheck(probe, healthBudget, out)
+}
+
+// healthcheck runs probe within budget and prints the summary.
+func healthcheck(probe func() status.Probe, budget time.Duration, out io.Writer) int {
+	done := make(chan status.Probe, 1)
+	go func() { done <- probe() }()
+	select {
+	case p := <-done:
+		h, line := status.AssessHealth(p)
+		fmt.Fprintln(out, line)
+		return int(h)
+	case <-time.After(budget):
+		fmt.Fprintf(out, "%s - check did not finish in %s\n", status.HealthCrit, budget)
+		return int(status.HealthCrit)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/status"
+)
+
+// TestHealthcheck: the exit code is the verdict, the output one line, and a
+// probe that overruns its budget is critical rather than a hang.
+func TestHealthcheck(t *testing.T) {
+	well := status.Probe{MeshLoaded: 3, MeshTotal: 3, MeshFound: true,
+		HeartbeatAge: time.Second, HeartbeatSeen: true, PlatformUp: true}
+	var out bytes.Buffer
+	if code := healthcheck(func() status.Probe { return well }, time.Second, &out); code != 0 || strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("healthy: code %d, output %q", code, out.String())
+	}
+	out.Reset()
+	down := well
+	down.PlatformUp = false
+	if code := healthcheck(func() status.Probe { return down }, time.Second, &out); code != 2 {
+		t.Fatalf("platform down: code %d, output %q", code, out.String())
+	}
+	out.Reset()
+	release := make(chan struct{})
+	defer close(release)
+	slow := func() status.Probe { <-release; return well }
+	if code := healthcheck(slow, 10*time.Millisecond, &out); code != 2 || !strings.Contains(out.String(), "did not finish") {
+		t.Fatalf("overrun: code %d, output %q", code, out.String())
+	}
+}
+
+// TestHealthcheckUsageIsUnknown: bad arguments exit 3, UNKNOWN, so a
+// monitor keeps to 0/1/2/3 and never sees exitUsage.
+func TestHealthcheckUsageIsUnknown(t *testing.T) {
+	for _, args := range [][]string{{"--bogus"}, {"extra"}} {
+		var out bytes.Buffer
+		probed := false
+		code := runHealthcheck(args, func() status.Probe { probed = true; return status.Probe{} }, &out)
+		if code != 3 || probed || !strings.HasPrefix(out.String(), "UNKNOWN - ") {
+			t.Fatalf("%v: code %d, probed %v, output %q", args, code, probed, out.String())
+		}
+	}
+}
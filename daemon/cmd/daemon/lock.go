@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/lockin"
+)
+
+// doLock is `daemon lock`: show the strict lock, or start one.
+//
+//	daemon lock             — show whether a lock holds and for how long
+//	daemon lock --days 30   — lock for 30 days after a typed confirmation
+//
+// While the lock holds, uninstall (the whole cooldown gate), `update
+// --rollback`, stopping the heartbeat, clearing the webhooks or the calendar
+// and break tokens all refuse. There is no unlock: a second lock can only lengthen it, and
+// the end is anchored against clock changes (see package lockin).
+func doLock(args []string) int {
+	fs := flag.NewFlagSet("lock", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	days := fs.Int("days", 0, fmt.Sprintf("lock for this many days (1-%d); cannot be undone", lockin.MaxDays))
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *days < 0 || *days > lockin.MaxDays {
+		fmt.Fprintf(os.Stderr, "lock: --days must be between 1 and %d\n", lockin.MaxDays)
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "lock: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	return applyLock(st, *days, st.Now(), os.Stdin, os.Stdout)
+}
+
+// applyLock shows the lock (days 0) or, once the typed phrase matches,
+// locks st for days. Returns the exit code.
+func applyLock(st *core.Store, days int, now time.Time, in io.Reader, out io.Writer) int {
+	cur := st.StrictLock()
+	if days == 0 {
+		if !cur.Active(now) {
+			fmt.Fprintln(out, "  lock: off")
+			return 0
+		}
+		fmt.Fprintln(out, "  lock: on,", lockin.FormatLeft(cur.Remaining(now)), "left")
+		return 0
+	}
+	phrase := fmt.Sprintf("lock for %d days", days)
+	fmt.Fprintf(out, "  For %d days, uninstall, rollback, stopping the heartbeat or webhooks and break tokens will all refuse.\n", days)
+	fmt.Fprintln(out, "  Nothing can end the lock early, including changing the clock.")
+	fmt.Fprintf(out, "  Type %q to confirm: ", phrase)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.TrimSpace(line) != phrase {
+		fmt.Fprintln(out, "  lock: not confirmed; nothing changed")
+		return 1
+	}
+	length := time.Duration(days) * 24 * time.Hour
+	if err := st.UpdateStrictLock(func(l lockin.Lock) lockin.Lock { return l.Extend(now, length) }); err != nil {
+		fmt.Fprintln(out, "  lock: write failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	fmt.Fprintln(out, "  lock: on,", lockin.FormatLeft(st.StrictLock().Remaining(now)), "left")
+	return 0
+}
+
+// refuseWhileLocked prints why verb is refused and reports true while the
+// strict lock holds.
+func refuseWhileLocked(st *core.Store, verb string, out io.Writer) bool {
+	left := st.StrictLock().Remaining(st.Now())
+	if left <= 0 {
+		return false
+	}
+	fmt.Fprintf(out, "%s: refused — strict lock on for %s more (`daemon lock`); it cannot be lifted early\n", verb, lockin.FormatLeft(left))
+	return true
+}
+
+// lockCreditInterval is how often the platform-lock holder credits running
+// time to an active strict lock and re-posts it to the platform.
+const lockCreditInterval = time.Minute
+
+// creditStrictLock credits ran (monotonic) and slept (observed sleep) to an
+// active lock and hands the time left to the running platform over its
+// admin socket, so a lock taken while the platform runs reaches its break
+// refusal within a minute.
+func creditStrictLock(st *core.Store, now time.Time, ran, slept time.Duration) error {
+	if !st.StrictLock().Active(now) {
+		return nil
+	}
+	if err := st.UpdateStrictLock(func(l lockin.Lock) lockin.Lock { return l.Credit(now, ran, slept) }); err != nil {
+		return err
+	}
+	return postHold(st.AdminSocketPath(), st.StrictLock().Remaining(now))
+}
+
+// postHold posts the lock's time left to the platform's /v1/hold on the
+// unix socket at sock. The path is never put in an error.
+func postHold(sock string, left time.Duration) error {
+	body, _ := json.Marshal(map[string]int64{"seconds": int64(left / time.Second)})
+	c := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := c.Post("http://platform/v1/hold", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.New("platform socket unavailable")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("platform hold: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestApplyLockNeedsThePhrase(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	now := time.Now()
+	var out bytes.Buffer
+
+	if code := applyLock(st, 30, now, strings.NewReader("lock for 3 days\n"), &out); code != 1 {
+		t.Fatalf("wrong phrase: code %d", code)
+	}
+	if st.StrictLock().Active(now) {
+		t.Fatal("lock taken without confirmation")
+	}
+	if refuseWhileLocked(st, "uninstall", &out) {
+		t.Fatal("refused with no lock")
+	}
+
+	out.Reset()
+	if code := applyLock(st, 30, now, strings.NewReader("lock for 30 days\n"), &out); code != 0 {
+		t.Fatalf("confirmed: code %d\n%s", code, out.String())
+	}
+	if left := st.StrictLock().Remaining(now); left != 30*24*time.Hour {
+		t.Fatalf("remaining %v", left)
+	}
+	out.Reset()
+	if !refuseWhileLocked(st, "uninstall", &out) || !strings.Contains(out.String(), "cannot be lifted") {
+		t.Fatalf("not refused under the lock:\n%s", out.String())
+	}
+}
+
+func TestCreditStrictLockCountsOnlyWhileLocked(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	now := time.Now()
+	if err := creditStrictLock(st, now, time.Minute, 0); err != nil {
+		t.Fatalf("no lock: %v", err)
+	}
+	if st.HaveConfig() {
+		t.Fatal("crediting without a lock wrote the store")
+	}
+	applyLock(st, 1, now, strings.NewReader("lock for 1 days\n"), &bytes.Buffer{})
+	// No platform socket here: the credit still lands, the hand-off fails.
+	if err := creditStrictLock(st, now.Add(time.Minute), time.Minute, 0); err == nil {
+		t.Fatal("post to a missing socket succeeded")
+	}
+	if got := st.StrictLock().Credited(); got != time.Minute {
+		t.Fatalf("credited %v, want 1m", got)
+	}
+}
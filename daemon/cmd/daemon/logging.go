@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// The daemon's log level and encoding. --log-level and --log-format are
+// global: they may sit anywhere on the command line and apply to that one
+// run. `daemon logging` persists them in version.json for the mesh, which
+// launchd starts with no flags, and for the platform child. A flag wins
+// over the persisted value, which wins over the command's own default.
+var logFlags struct{ level, format string }
+
+// stripLogFlags removes the global log flags from args, in any of the
+// forms the flag package accepts (-f v, --f v, --f=v), and records them.
+// An unknown level or format is an error.
+func stripLogFlags(args []string) ([]string, error) {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name, val, hasVal := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || (name != "log-level" && name != "log-format") {
+			out = append(out, args[i])
+			continue
+		}
+		if !hasVal {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			val = args[i]
+		}
+		if name == "log-level" {
+			if _, ok := parseLogLevel(val); !ok {
+				return nil, errors.New("--log-level must be debug, info, warn or error")
+			}
+			logFlags.level = val
+		} else {
+			if !validLogFormat(val) {
+				return nil, errors.New("--log-format must be text or json")
+			}
+			logFlags.format = val
+		}
+	}
+	return out, nil
+}
+
+func parseLogLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
+
+func validLogFormat(s string) bool { return s == "text" || s == "json" }
+
+// newLogger builds a command's stderr logger: def is its level when neither
+// a flag nor the persisted setting names one. st may be nil (no install
+// known yet).
+func newLogger(def slog.Level, st *core.Store) *slog.Logger {
+	log, _ := newLevelLogger(def, st)
+	return log
+}
+
+// newLevelLogger is newLogger with its level exposed, for the loop, which
+// moves it while a `daemon log-level` window is open.
+func newLevelLogger(def slog.Level, st *core.Store) (*slog.Logger, *slog.LevelVar) {
+	level, format := logFlags.level, logFlags.format
+	if st != nil {
+		l, f := st.Logging()
+		if level == "" {
+			level = l
+		}
+		if format == "" {
+			format = f
+		}
+	}
+	lv := new(slog.LevelVar)
+	lv.Set(def)
+	if l, ok := parseLogLevel(level); ok {
+		lv.Set(l)
+	}
+	opts := &slog.HandlerOptions{Level: lv}
+	w := io.Writer(os.Stderr)
+	if st != nil && st.LogSink() == sinkSyslog {
+		// Left open for the life of the process, like stderr. A system log
+		// that cannot be reached is skipped: run.log is the record.
+		if sw, err := openSyslog(); err == nil {
+			w = io.MultiWriter(w, sw)
+		}
+	}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts)), lv
+	}
+	return slog.New(slog.NewTextHandler(w, opts)), lv
+}
+
+// sinkSyslog is the `daemon logging --sink` value that also writes to the
+// system log; on macOS that is the unified log (`log stream`, Console).
+const sinkSyslog = "syslog"
+
+func validLogSink(s string) bool { return s == "" || s == "file" || s == sinkSyslog }
+
+// doLogging is `daemon logging`: show or persist the log level and format
+// the mesh and the platform child use.
+//
+//	daemon logging                             — show the persisted settings
+//	daemon logging --level debug --format json — persist them
+//	daemon logging --level "" --format ""      — back to the defaults
+//	daemon logging --sink syslog               — also write to the system log
+//
+// The mesh reads them when it starts and the platform child gets them in
+// its environment at its next start, so a change applies after the next
+// restart (`daemon ensure`, a reboot, a version swap).
+func doLogging(args []string) int {
+	fs := flag.NewFlagSet("logging", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	level := fs.String("level", "", "persist the log level: debug|info|warn|error (empty value clears)")
+	format := fs.String("format", "", "persist the log encoding: text|json (empty value clears)")
+	sink := fs.String("sink", "", "persist the log sink: file|syslog (empty value clears)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	levelSet, formatSet, sinkSet := false, false, false
+	fs.Visit(func(f *flag.Flag) {
+		levelSet = levelSet || f.Name == "level"
+		formatSet = formatSet || f.Name == "format"
+		sinkSet = sinkSet || f.Name == "sink"
+	})
+	if _, ok := parseLogLevel(*level); *level != "" && !ok {
+		fmt.Fprintln(os.Stderr, "logging: --level must be debug, info, warn or error")
+		return 2
+	}
+	if *format != "" && !validLogFormat(*format) {
+		fmt.Fprintln(os.Stderr, "logging: --format must be text or json")
+		return 2
+	}
+	if !validLogSink(*sink) {
+		fmt.Fprintln(os.Stderr, "logging: --sink must be file or syslog")
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "logging: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	if levelSet || formatSet {
+		l, f := st.Logging()
+		if levelSet {
+			l = strings.ToLower(*level)
+		}
+		if formatSet {
+			f = *format
+		}
+		if err := st.WriteLogging(l, f); err != nil {
+			fmt.Fprintln(os.Stdout, "  logging: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	if sinkSet {
+		// "file" is the default spelled out; keep version.json to one form.
+		s := *sink
+		if s == "file" {
+			s = ""
+		}
+		if err := st.WriteLogSink(s); err != nil {
+			fmt.Fprintln(os.Stdout, "  logging: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	printLogging(st, levelSet || formatSet || sinkSet, os.Stdout)
+	return 0
+}
+
+func printLogging(st *core.Store, changed bool, out io.Writer) {
+	l, f := st.Logging()
+	if l == "" {
+		l = "default (info)"
+	}
+	if f == "" {
+		f = "default (text)"
+	}
+	s := "file"
+	if st.LogSink() == sinkSyslog {
+		s = "file + syslog"
+	}
+	fmt.Fprintf(out, "  logging: level %s, format %s, sink %s\n", l, f, s)
+	if changed {
+		fmt.Fprintln(out, "  logging: the mesh and the platform pick this up when they next start")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestStripLogFlags(t *testing.T) {
+	t.Cleanup(func() { logFlags.level, logFlags.format = "", "" })
+	rest, err := stripLogFlags([]string{"--log-level", "debug", "status", "--json", "-log-format=json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rest, []string{"status", "--json"}) || logFlags.level != "debug" || logFlags.format != "json" {
+		t.Fatalf("rest %v, flags %+v", rest, logFlags)
+	}
+	for _, bad := range [][]string{{"--log-level", "loud"}, {"--log-format=xml"}, {"status", "--log-level"}} {
+		if _, err := stripLogFlags(bad); err == nil {
+			t.Errorf("%v: want an error", bad)
+		}
+	}
+}
+
+// TestNewLoggerPrecedence: a flag beats the persisted setting, which beats
+// the command's default.
+func TestNewLoggerPrecedence(t *testing.T) {
+	t.Cleanup(func() { logFlags.level, logFlags.format = "", "" })
+	st := &core.Store{Dir: t.TempDir()}
+	ctx := context.Background()
+	if newLogger(slog.LevelWarn, st).Enabled(ctx, slog.LevelInfo) {
+		t.Fatal("default warn logs info")
+	}
+	if err := st.WriteLogging("debug", "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !newLogger(slog.LevelWarn, st).Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("persisted debug not honored")
+	}
+	if _, ok := newLogger(slog.LevelWarn, st).Handler().(*slog.JSONHandler); !ok {
+		t.Fatal("persisted json format not honored")
+	}
+	logFlags.level, logFlags.format = "error", "text"
+	l := newLogger(slog.LevelWarn, st)
+	if l.Enabled(ctx, slog.LevelWarn) {
+		t.Fatal("flag error level lost to the persisted setting")
+	}
+	if _, ok := l.Handler().(*slog.TextHandler); !ok {
+		t.Fatal("flag text format lost to the persisted setting")
+	}
+}
+
+// TestDoLoggingSink: --sink persists syslog, "file" is stored as the
+// default, and an unknown sink is refused.
+func TestDoLoggingSink(t *testing.T) {
+	wd := t.TempDir()
+	st := &core.Store{Dir: wd}
+	if doLogging([]string{"--workdir", wd, "--sink", "journald"}) != 2 {
+		t.Fatal("unknown sink accepted")
+	}
+	if doLogging([]string{"--workdir", wd, "--sink", "syslog"}) != 0 || st.LogSink() != sinkSyslog {
+		t.Fatalf("sink = %q, want syslog", st.LogSink())
+	}
+	if doLogging([]string{"--workdir", wd, "--sink", "file"}) != 0 || st.LogSink() != "" {
+		t.Fatalf("sink = %q, want the default", st.LogSink())
+	}
+}
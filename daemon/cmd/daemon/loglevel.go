@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// Bounds of a `daemon log-level` window. maxLogWindow MUST match platform
+// logging.MaxOverride, or the platform refuses a window the mesh took.
+const (
+	defaultLogWindow = 10 * time.Minute
+	maxLogWindow     = 24 * time.Hour
+)
+
+// doLogLevel is `daemon log-level`: run the mesh and the platform at a
+// level for a window, without the restart `daemon logging` needs, so a
+// reproduction keeps the state it is about. Both fall back to their
+// configured level when the window closes.
+//
+//	daemon log-level                      — the running levels
+//	daemon log-level debug [--for 30m]    — debug for the window (default 10m)
+//	daemon log-level reset                — close the window now
+//
+// The mesh reads the window from version.json on its next tick; the
+// platform gets it at once over its admin socket and keeps its own timer,
+// so a platform restart inside the window comes back at its configured
+// level. It exits 1 when the level was not applied: the store could not be
+// written, or a running platform refused it. A platform that is not running
+// has nothing to apply it to and is only reported.
+func doLogLevel(args []string) int {
+	var level string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		level, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("log-level", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	window := fs.Duration("for", defaultLogWindow, "how long the level lasts (at most 24h)")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return 2
+	}
+	show := level == ""
+	if !show && level != "reset" {
+		if _, ok := parseLogLevel(level); !ok {
+			fmt.Fprintln(os.Stderr, "log-level: level must be debug, info, warn, error or reset")
+			return 2
+		}
+		if *window < time.Second || *window > maxLogWindow {
+			fmt.Fprintln(os.Stderr, "log-level: --for must be between 1s and 24h")
+			return 2
+		}
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "log-level: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	var req *levelRequest
+	switch {
+	case show:
+	case level == "reset":
+		req = &levelRequest{}
+	default:
+		req = &levelRequest{Level: strings.ToLower(level), ForS: int64(*window / time.Second)}
+	}
+	if req != nil {
+		w := core.LogWindow{}
+		if req.Level != "" {
+			w = core.LogWindow{Level: req.Level, Until: time.Now().Add(*window).UTC()}
+		}
+		if err := st.WriteLogWindow(w); err != nil {
+			fmt.Fprintln(os.Stderr, "log-level: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	pl, perr := platformLogLevel(st.AdminSocketPath(), req)
+	printLogLevel(os.Stdout, st.LogWindow(), time.Now(), pl, perr)
+	if perr != nil && !errors.Is(perr, errPlatformDown) {
+		fmt.Fprintln(os.Stderr, "log-level:", perr)
+		return 1
+	}
+	return 0
+}
+
+// levelRequest / platformLevel mirror the platform's /v1/log-level bodies
+// (adminapi.LogLevelRequest / LogLevel).
+type levelRequest struct {
+	Level string `json:"level"`
+	ForS  int64  `json:"for_s"`
+}
+
+type platformLevel struct {
+	Level string `json:"level"`
+	Base  string `json:"base"`
+	Until string `json:"until,omitempty"`
+}
+
+// platformLogLevel reads the running platform's level from its admin socket
+// at sock or, with req, sets it, like postClock.
+func platformLogLevel(sock string, req *levelRequest) (platformLevel, error) {
+	c := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+	var resp *http.Response
+	var err error
+	if req == nil {
+		resp, err = c.Get("http://platform/v1/log-level")
+	} else {
+		body, _ := json.Marshal(req)
+		resp, err = c.Post("http://platform/v1/log-level", "application/json", bytes.NewReader(body))
+	}
+	if err != nil {
+		return platformLevel{}, errPlatformDown
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return platformLevel{}, fmt.Errorf("platform log level: %s", resp.Status)
+	}
+	var pl platformLevel
+	if err := json.NewDecoder(resp.Body).Decode(&pl); err != nil {
+		return platformLevel{}, errors.New("platform log level: bad reply")
+	}
+	return pl, nil
+}
+
+var errPlatformDown = errors.New("platform socket unavailable")
+
+func printLogLevel(out io.Writer, w core.LogWindow, now time.Time, pl platformLevel, perr error) {
+	if w.Open(now) {
+		fmt.Fprintf(out, "  log-level: mesh %s until %s (from its next tick)\n", w.Level, w.Until.Local().Format("15:04:05"))
+	} else {
+		fmt.Fprintln(out, "  log-level: mesh at its configured level")
+	}
+	switch {
+	case errors.Is(perr, errPlatformDown):
+		fmt.Fprintln(out, "  log-level: platform not running")
+	case perr != nil:
+		// doLogLevel reports it on stderr.
+	case pl.Until == "":
+		fmt.Fprintf(out, "  log-level: platform %s (configured)\n", pl.Level)
+	default:
+		until := pl.Until
+		if t, err := time.Parse(time.RFC3339, pl.Until); err == nil {
+			until = t.Local().Format("15:04:05")
+		}
+		fmt.Fprintf(out, "  log-level: platform %s until %s, then %s\n", pl.Level, until, pl.Base)
+	}
+}
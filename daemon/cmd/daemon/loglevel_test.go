@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// TestDoLogLevelWindow: a level opens a window the mesh reads, reset closes
+// it, and a bad level or window is refused. With no platform running the
+// mesh's window is still recorded.
+func TestDoLogLevelWindow(t *testing.T) {
+	wd := t.TempDir()
+	st := &core.Store{Dir: wd}
+	for _, bad := range [][]string{{"loud"}, {"debug", "--for", "48h"}, {"debug", "--for", "0s"}} {
+		if doLogLevel(append(bad, "--workdir", wd)) != 2 {
+			t.Errorf("%v accepted", bad)
+		}
+	}
+	if doLogLevel([]string{"debug", "--for", "5m", "--workdir", wd}) != 0 {
+		t.Fatal("debug window refused")
+	}
+	w := st.LogWindow()
+	if !w.Open(time.Now()) || w.Level != "debug" || w.Until.After(time.Now().Add(5*time.Minute)) {
+		t.Fatalf("window = %+v, want debug for 5m", w)
+	}
+	if doLogLevel([]string{"reset", "--workdir", wd}) != 0 || st.LogWindow().Open(time.Now()) {
+		t.Fatalf("window after reset = %+v", st.LogWindow())
+	}
+}
+
+// TestDoLogLevelPlatformRefusal: a running platform that refuses the level
+// fails the command, though the mesh's window is recorded.
+func TestDoLogLevelPlatformRefusal(t *testing.T) {
+	wd := t.TempDir()
+	st := &core.Store{Dir: wd}
+	sock := st.AdminSocketPath()
+	if err := os.MkdirAll(filepath.Dir(sock), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("no unix socket:", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "no", http.StatusBadRequest)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+	if doLogLevel([]string{"debug", "--workdir", wd}) != 1 {
+		t.Fatal("a refused level must exit 1")
+	}
+	if !st.LogWindow().Open(time.Now()) {
+		t.Fatal("the mesh window was not recorded")
+	}
+}
+
+func TestPrintLogLevel(t *testing.T) {
+	now := time.Now()
+	var b bytes.Buffer
+	printLogLevel(&b, core.LogWindow{Level: "debug", Until: now.Add(time.Minute)}, now,
+		platformLevel{Level: "debug", Base: "info", Until: now.Add(time.Minute).UTC().Format(time.RFC3339)}, nil)
+	if out := b.String(); !strings.Contains(out, "mesh debug until") || !strings.Contains(out, "then info") {
+		t.Fatalf("output:\n%s", out)
+	}
+	b.Reset()
+	printLogLevel(&b, core.LogWindow{Level: "debug", Until: now.Add(-time.Minute)}, now, platformLevel{}, errPlatformDown)
+	if out := b.String(); !strings.Contains(out, "configured level") || !strings.Contains(out, "not running") {
+		t.Fatalf("output:\n%s", out)
+	}
+}
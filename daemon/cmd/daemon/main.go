@@ -3,14 +3,19 @@
 // Ed25519-verify, start; roll back a crash-looping version).
 //
 //	daemon run     [--workdir D] [--interval 10s] [--github owner/repo --asset NAME | --release-dir D]
+//	daemon run --foreground --workdir D ...   debug run: attached, debug logs,
+//	               platform output echoed, no disguise (never with --mesh)
 //	daemon once    same flags; one reconcile tick then exit
-//	daemon update  re-resolve latest now and roll forward
+//	daemon update  re-resolve latest (per --channel) now and roll forward
 //	daemon version print daemon version
+//	daemon self-test   the boot-time checks, on demand (see doSelfTest)
 //	daemon install / uninstall   (darwin launchd; see osadapter)
 package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -26,12 +31,17 @@ import (
 
 	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/fetch"
+	"github.com/eliteGoblin/focusd/daemon/internal/heartbeat"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
 	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
 	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
 	"github.com/eliteGoblin/focusd/daemon/internal/platformsvc"
 	"github.com/eliteGoblin/focusd/daemon/internal/relocate"
+	"github.com/eliteGoblin/focusd/daemon/internal/status"
+	"github.com/eliteGoblin/focusd/daemon/internal/trustclock"
 	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 var version = "dev"
@@ -85,6 +95,15 @@ func run(args []string) int {
 			return 2
 		}
 	}
+	args, err := stripLogFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "daemon:", err)
+		return 2
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
 	switch args[0] {
 	case "version", "-v", "--version":
 		fmt.Println("focusd-daemon", version)
@@ -107,6 +126,34 @@ func run(args []string) int {
 		return doSelfUpdate(args[1:])
 	case "status":
 		return doStatus(args[1:])
+	case "notify":
+		return doNotify(args[1:])
+	case "diag":
+		return doDiag(args[1:])
+	case "api":
+		return doAPI(args[1:])
+	case "lock":
+		return doLock(args[1:])
+	case "calendar":
+		return doCalendar(args[1:])
+	case "verify":
+		return doVerify(args[1:])
+	case "priority":
+		return doPriority(args[1:])
+	case "decoys":
+		return doDecoys(args[1:])
+	case "logging":
+		return doLogging(args[1:])
+	case "log-level":
+		return doLogLevel(args[1:])
+	case "healthcheck":
+		return doHealthcheck(args[1:])
+	case "config":
+		return doConfig(args[1:])
+	case "rotate-identity":
+		return doRotateIdentity(args[1:])
+	case "self-test", "--self-test":
+		return doSelfTest(args[1:])
 	default:
 		fmt.Fprintln(os.Stderr, "unknown command:", args[0])
 		usage()
@@ -115,7 +162,7 @@ func run(args []string) int {
 }
 
 func usage() {
-	fmt.Fprintln(os.Stderr, "usage: daemon run|once|update|version|install|uninstall|watchdog|self-update|status [flags]")
+	fmt.Fprintln(os.Stderr, "usage: daemon run|once|update|version|install|uninstall|watchdog|self-update|status|healthcheck|verify|self-test|notify|diag|api|lock|calendar|priority|decoys|logging|log-level|config|rotate-identity [flags] [--log-level L] [--log-format text|json]")
 }
 
 type opts struct {
@@ -140,6 +187,11 @@ type opts struct {
 	testMode        bool
 	mesh            bool
 	roster          []string
+	// foreground is `daemon run --foreground`: a contributor's debug run.
+	// Debug-level logs, the platform child's output echoed to stderr, the
+	// child under its real name and everything kept under --workdir (no
+	// salt, no separate platform-workdir).
+	foreground bool
 }
 
 func (o opts) spec(self string) osadapter.Spec {
@@ -192,6 +244,7 @@ func parse(name string, args []string) opts {
 	// flag.Parse from choking on an old plist's --roster and dropping the
 	// trailing --mesh/--r that follow it.
 	rs := fs.String("roster", "", "(backward-compat) comma-joined 3-label mesh roster from old plists")
+	fg := fs.Bool("foreground", false, "debug run: attached, debug logs, platform output echoed here, no disguise (not with --mesh)")
 	// Track whether the caller explicitly passed --workdir so a mesh role
 	// can prefer an explicit value over the os.Executable()-derived one.
 	wdSet := false
@@ -237,7 +290,7 @@ func parse(name string, args []string) opts {
 	return opts{
 		workdir: workdir, interval: *iv, github: *gh, asset: platformAsset(),
 		releaseDir: *rd, healthy: *hd, unhealthy: *ud, role: *rl,
-		testMode: testMode, mesh: *mesh, roster: roster,
+		testMode: testMode, mesh: *mesh, roster: roster, foreground: *fg,
 	}
 }
 
@@ -305,6 +358,11 @@ const workerHealInterval = 2 * time.Second
 // recovered within the same ~60s window — recovery timing is unchanged.
 const companionHeartbeatInterval = 15 * time.Second
 
+// heartbeatInterval is how often the platform-lock holder sends the signed
+// server heartbeat (`daemon notify --heartbeat`). Each beat carries it, so the
+// server's "overdue" threshold follows any change.
+const heartbeatInterval = 5 * time.Minute
+
 // platformAsset is the protection-engine release asset name for THIS
 // daemon's OS/arch. Releases are named platform-{GOOS}-{GOARCH}, so the
 // name is FULLY DETERMINED — it is DERIVED, never an operator knob.
@@ -379,8 +437,12 @@ func defaultWorkdir() string {
 	return h + "/Library/Application Support/focusd-daemon"
 }
 
-func build(o opts) (*core.Executor, *slog.Logger) {
-	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+func build(o opts) (*core.Executor, *slog.Logger, *slog.LevelVar) {
+	level := slog.LevelInfo
+	if o.foreground {
+		level = slog.LevelDebug
+	}
+	log, lv := newLevelLogger(level, &core.Store{Dir: o.workdir})
 	// FEATURE 21 (HF1): the daemon's durable state lives under the daemon-home
 	// (o.workdir); the platform's disposable binaries + process live under the
 	// separate platform-workdir when one has been resolved (loop/install). An
@@ -400,24 +462,48 @@ func build(o opts) (*core.Executor, *slog.Logger) {
 	// deliberately stays on the legacy, deterministic layout so e2e is
 	// self-contained. Best-effort: a write failure degrades to the legacy layout
 	// (empty salt ⇒ PlatformArgv0/BinPath fall back) rather than blocking.
-	if o.modeVal() != mode.Test {
+	if o.modeVal() != mode.Test && !o.foreground {
 		_, _ = st.EnsureInstallSalt()
 	}
 	var f core.Fetcher
 	if o.releaseDir != "" {
 		f = &fetch.Local{Dir: o.releaseDir}
 	} else {
-		f = &fetch.GitHub{Repo: o.github, Asset: o.asset}
+		f = githubFetcher(o.github, o.asset, st)
 	}
 	p := platformsvc.New(platWD)
 	// HF4: set the disguised argv[0] for the platform child (empty in test mode /
 	// no-salt ⇒ ProcSvc keeps the legacy visible argv).
 	p.Argv0 = st.PlatformArgv0()
+	if o.foreground {
+		// A debug run shows the child as itself and echoes its output here.
+		p.Argv0 = ""
+		p.Echo = os.Stderr
+	}
 	// HF4 (P3): publish the child's pid to a salt-independent liveness pidfile in
 	// the DAEMON-HOME (o.workdir survives a platform-workdir wipe; a `focusd
 	// status` discovers this same root). This is the primary up/down signal, so a
 	// pgrep miss after a salt divergence can never falsely report DOWN.
 	p.PidFile = st.PidFilePath()
+	// The platform child exports its run spans to the same OTLP endpoint.
+	p.TraceEndpoint = st.TraceEndpoint
+	// ...and serves the admin API (and its debug routes) when `daemon api`
+	// enabled it.
+	p.AdminAPI = st.AdminAPI
+	p.AdminDebug = st.AdminDebug
+	// ...and refuses break tokens while a strict lock (`daemon lock`) holds.
+	p.StrictLock = func() time.Duration { return st.StrictLock().Remaining(st.Now()) }
+	// ...and keeps its sessions, breaks and pauses on the trusted clock.
+	p.ClockSkew = func() time.Duration { return st.Clock().Skew(time.Now()) }
+	// ...and watches the user's calendar for tagged focus blocks.
+	p.Calendar = st.CalendarURL
+	// ...and ships its event stream, sealed, when log.ship is set.
+	p.LogShip = st.LogShip
+	// ...and logs at the level, in the format and to the sinks `daemon
+	// logging` set, into a file no wider than log.mode.
+	p.Logging = st.Logging
+	p.LogSink = st.LogSink
+	p.LogMode = st.LogMode
 	if o.healthy > 0 {
 		p.Healthy = o.healthy
 	}
@@ -453,18 +539,25 @@ func build(o opts) (*core.Executor, *slog.Logger) {
 	if o.mesh && o.modeVal() != mode.Test {
 		e.ReapForeign = osadapter.ReapForeignPlatforms
 	}
-	return e, log
+	return e, log, lv
 }
 
 func loop(args []string, once bool) int {
 	o := parse("run", args)
+	if o.foreground && o.mesh {
+		fmt.Fprintln(os.Stderr, "run: --foreground is a debug run and never part of the mesh; drop --mesh")
+		return 2
+	}
 	// FEATURE 21 (HF1): resolve — and self-heal — the disposable
 	// platform-workdir from the pointer in daemon-home BEFORE building the
 	// executor. A wiped platform-workdir (pointer target gone) is re-created
 	// fresh here, so the very next tick re-fetches + restarts the platform
-	// while the daemon-home (binary + state) is untouched.
-	o.platformWorkdir = resolvePlatformWorkdir(o.modeVal(), o.workdir)
-	e, log := build(o)
+	// while the daemon-home (binary + state) is untouched. A foreground run
+	// keeps everything under --workdir instead, where a contributor looks.
+	if !o.foreground {
+		o.platformWorkdir = resolvePlatformWorkdir(o.modeVal(), o.workdir)
+	}
+	e, log, logLevel := build(o)
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	// NOTE: we deliberately do NOT release the singleton lock early on
@@ -479,6 +572,21 @@ func loop(args []string, once bool) int {
 	self, _ := os.Executable()
 	spec := o.spec(self)
 
+	// Boot self-test: state, keys, plist templates, backup and log sink are
+	// checked once before the first tick. Failures are logged and recorded
+	// for `daemon status`; the loop runs regardless.
+	if !once {
+		if o.mesh {
+			hardenLog(o.workdir, o.platformWorkdir, log)
+		}
+		recordSelfTest(&core.Store{Dir: o.workdir}, selfTest(selfTestInputFor(o, spec)), time.Now(), log)
+		// Crash reports (package crash): record what the worker before
+		// this one left, then arm this one's.
+		cst := &core.Store{Dir: o.workdir}
+		collectCrashes(cst, submitCrash(cst), log)
+		defer armCrashes(cst, log)()
+	}
+
 	// FEATURE 22 follow-up (in-mesh binary re-materialize): retain a read-only fd
 	// to our OWN binary for the process lifetime. On Unix an open fd keeps the
 	// inode alive, so pread from it returns the original release bytes even after
@@ -502,14 +610,126 @@ func loop(args []string, once bool) int {
 	// (non-test), throttling the retire to the foreign-platform reap cadence.
 	var deadGenTicks int
 
+	// lastPublish throttles the published status (system mode, lock holder)
+	// to status.PublishInterval; zero publishes on the first tick.
+	var lastPublish time.Time
+
+	// lastTraceCheck throttles the sibling debugger check to
+	// traceCheckInterval.
+	var lastTraceCheck time.Time
+
+	// clock keeps the trusted clock the time gates read (see clockTender).
+	clock := newClockTender()
+
+	// Protection-event webhooks (`daemon notify`). Hooks are re-read from the
+	// store at delivery, so configuring them needs no restart; with none
+	// configured the worker idles.
+	hookStore := &core.Store{Dir: o.workdir}
+	notifier := notify.New(func() []notify.Hook { return storeHooks(hookStore) }, log)
+	go notifier.Run(ctx)
+	var lastKind core.Kind
+
+	// OTel spans for the update and self-repair flows, exported to the OTLP
+	// endpoint from `daemon notify --otlp` (or the OTEL_EXPORTER_OTLP_*
+	// environment). Steady ticks are discarded, so at rest nothing is sent.
+	tracer := tracing.New(func() string { return traceURL(hookStore) }, "focusd-daemon", log)
+	go tracer.Run(ctx)
+	defer tracer.Shutdown()
+
+	// Dead-man heartbeat to the focusd server (`daemon notify --heartbeat`).
+	// Only the lock holder beats, so the mesh sends one stream per machine;
+	// restores counts this worker's restores since its last beat. With no
+	// endpoint configured the sender drops each beat unsent.
+	beats := heartbeat.New(func() (string, ed25519.PrivateKey) { return heartbeatConfig(hookStore) }, log)
+	go beats.Run(ctx)
+	var lastBeat time.Time
+	var restores int
+
+	// Strict-lock credit (`daemon lock`): only the lock holder counts running
+	// time, so the mesh credits each minute once. A standby keeps its mark
+	// current, so taking over never credits time another worker counted.
+	// The sleep meter is read on the same passes, so what it reports is the
+	// sleep between two credits.
+	lastCredit := time.Now()
+	var sleeps trustclock.SleepMeter
+
+	// Scheduling priority (`daemon priority`): re-read every tick so a change
+	// applies without a restart. A platform already running keeps the value
+	// it started with; the next one it forks inherits the new one. A failed
+	// set (lowering nice needs root) is logged once per value.
+	nice := -1
+	applyNice := func() {
+		if n := hookStore.Nice(); n != nice {
+			nice = n
+			if err := osadapter.SetNice(n); err != nil {
+				log.Warn("set priority", "err", fmt.Sprintf("%T", err))
+				return
+			}
+			log.Info("priority set", "nice", n)
+		}
+	}
+
+	// Log window (`daemon log-level`): re-read every tick, like the
+	// priority, so the level moves without a restart and falls back to
+	// the configured one when the window closes.
+	baseLevel := logLevel.Level()
+	applyLogWindow := func() {
+		want := baseLevel
+		if w := hookStore.LogWindow(); w.Open(time.Now()) {
+			if l, ok := parseLogLevel(w.Level); ok {
+				want = l
+			}
+		}
+		if want != logLevel.Level() {
+			logLevel.Set(want)
+			log.Info("log level set", "level", want.String())
+		}
+	}
+
 	tick := func() {
 		// Steady-state ticks no longer emit a per-tick "tick" beacon (FEATURE 24 /
 		// HF-disguise): non-steady actions are already logged by the executor, and
 		// the mesh/companion calls below log only on change/error — so real events
 		// stay recorded while the daemon log falls silent at rest. Errors are still
 		// logged.
-		if _, err := e.Tick(ctx); err != nil {
+		applyNice()
+		applyLogWindow()
+		tctx, span := tracer.Start(ctx, "reconcile", tracing.String("role", o.role))
+		a, err := e.Tick(tctx)
+		if err != nil {
 			log.Error("tick error", "err", err)
+			span.Fail(fmt.Sprintf("%T", err))
+		}
+		// Only the lock holder acts; a standby's yielded EnsureRunning is noise.
+		if err != nil || (a.Kind != core.Steady && e.HoldsPlatformLock()) {
+			span.Set(tracing.String("action", string(a.Kind)), tracing.String("target", a.Target))
+			span.End()
+		} else {
+			span.Discard()
+		}
+		// Notify once per rollback, not on every tick it takes to complete;
+		// only the lock holder's action is real (a standby's cannot apply).
+		if a.Kind == core.Rollback && lastKind != core.Rollback && e.HoldsPlatformLock() {
+			notifier.Notify(notify.Event{Kind: notify.UpdateRolledBack,
+				Message: "platform update rolled back to " + a.Target,
+				Details: map[string]string{"version": a.Target}})
+		}
+		lastKind = a.Kind
+		if now := time.Now(); e.HoldsPlatformLock() && now.Sub(lastBeat) >= heartbeatInterval {
+			beats.Pulse(heartbeat.Beat{At: now.UTC(), IntervalS: int64(heartbeatInterval / time.Second),
+				Version: hookStore.Desired(), Restores: restores})
+			lastBeat, restores = now, 0
+		}
+		if now := time.Now(); !e.HoldsPlatformLock() {
+			lastCredit = now
+			sleeps.Since()
+		} else if ran := now.Sub(lastCredit); ran >= lockCreditInterval {
+			slept := sleeps.Since()
+			clock.tend(hookStore, now, ran, slept, log)
+			if err := creditStrictLock(hookStore, hookStore.Now(), ran, slept); err != nil {
+				log.Warn("strict lock credit", "err", fmt.Sprintf("%T", err))
+			}
+			lastCredit = now
 		}
 		// Mesh self-heal: only when launched as part of an installed
 		// mesh (--mesh, set solely by the installer). A plain
@@ -522,7 +742,16 @@ func loop(args []string, once bool) int {
 			// cheap stat at rest). On a real re-materialize it returns the fresh
 			// disguised path, which we adopt so the NEXT tick stats the new file
 			// (no heal loop) and EnsureAll/EnsureCompanion below use it.
+			_, bspan := tracer.Start(ctx, "restore.binary", tracing.String("role", o.role))
 			newSelf, changed, berr := osadapter.EnsureBinaryPresent(spec, osadapter.Role(o.role), e.HoldsPlatformLock(), selfFD)
+			if berr != nil {
+				bspan.Fail(fmt.Sprintf("%T", berr))
+			}
+			if changed || berr != nil {
+				bspan.End()
+			} else {
+				bspan.Discard()
+			}
 			// Adopt the fresh path whenever the binary was PLACED — INDEPENDENT of
 			// berr. binpresent's contract: changed=true with a non-nil err means the
 			// binary WAS placed but the launchd re-bootstrap partially failed; the
@@ -540,10 +769,58 @@ func loop(args []string, once bool) int {
 			} else if changed {
 				log.Info("daemon binary re-materialized") // no path (redaction-safe)
 			}
-			if rec, eerr := osadapter.EnsureAll(spec); eerr != nil {
+			if changed {
+				restores++
+				notifier.Notify(notify.Event{Kind: notify.BinaryRestored,
+					Message: "deleted daemon binary restored"})
+			}
+			_, mspan := tracer.Start(ctx, "restore.mesh", tracing.String("role", o.role))
+			rec, eerr := osadapter.EnsureAll(spec)
+			if eerr != nil || len(rec) > 0 {
+				mspan.Set(tracing.Int("roles_restored", int64(len(rec))))
+				if eerr != nil {
+					mspan.Fail(fmt.Sprintf("%T", eerr))
+				}
+				mspan.End()
+			} else {
+				mspan.Discard()
+			}
+			if eerr != nil {
 				log.Warn("ensure-all", "err", eerr)
 			} else if len(rec) > 0 {
 				log.Info("mesh recreated", "roles", rec)
+				// Only the worker that found the job missing gets here, so
+				// the mesh members never double-notify.
+				restores++
+				notifier.Notify(notify.Event{Kind: notify.MeshRestored,
+					Message: fmt.Sprintf("%d missing launchd job(s) restored", len(rec)),
+					Details: map[string]string{"count": fmt.Sprint(len(rec))}})
+			}
+			// FEATURE 10 decoys: the lock holder alone, so a removal is put
+			// back, reported and (opt-in) tightened once.
+			if e.HoldsPlatformLock() && spec.Mode != mode.Test {
+				tendDecoys(hookStore, spec, hookStore.Now(), osadapter.EnsureDecoys, notifier.Notify, log)
+				tendRotation(hookStore, time.Now(), spawnRotation(self, o.workdir, hookStore.LogMode()), log)
+			}
+			// A debugger on the sibling worker is answered by restarting it.
+			// Not gated on the lock: the traced worker may be the holder.
+			if peer, ok := peerRole(o.role); ok && spec.Mode != mode.Test {
+				if now := time.Now(); now.Sub(lastTraceCheck) >= traceCheckInterval {
+					lastTraceCheck = now
+					tendTraced(peer, spec.Label(peer),
+						func(l string) int { return osadapter.JobPID(spec.Mode, l) },
+						osadapter.Traced,
+						func(l string) error { return osadapter.RestartJob(spec.Mode, l) },
+						notifier.Notify, log)
+				}
+			}
+			// FEATURE 09: a system install publishes its status for a
+			// non-root `daemon status`; the lock holder alone writes it.
+			if now := time.Now(); spec.Mode == mode.System && e.HoldsPlatformLock() && now.Sub(lastPublish) >= status.PublishInterval {
+				if perr := publishStatus(hookStore, now, status.Gather, writePublished); perr != nil {
+					log.Warn("publish status", "err", fmt.Sprintf("%T", perr))
+				}
+				lastPublish = now
 			}
 			// FEATURE 18 / ADR-0020: out-of-band COMPANION mutual guarding — the
 			// mesh's own reconcile keeps the companion rail up (idempotent) AND
@@ -592,6 +869,11 @@ func loop(args []string, once bool) int {
 	if once {
 		return 0
 	}
+	// SIGHUP (`daemon config set`) re-reads the settings now rather than on
+	// the next tick, restarting the platform if its environment changed.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 	t := time.NewTicker(o.interval)
 	defer t.Stop()
 	for {
@@ -599,6 +881,12 @@ func loop(args []string, once bool) int {
 		case <-ctx.Done():
 			log.Info("daemon stopping")
 			return 0
+		case <-hup:
+			if o.mesh {
+				hardenLog(o.workdir, o.platformWorkdir, log)
+			}
+			reloadPlatform(e, log)
+			tick()
 		case <-t.C:
 			tick()
 		}
@@ -609,8 +897,38 @@ func loop(args []string, once bool) int {
 // forms:
 //
 //	daemon update vX.Y.Z   — write desired=vX.Y.Z, no network call.
-//	daemon update          — resolve "Latest" from GitHub ONCE; write.
-//	                         Exits non-zero on resolve failure. No retry.
+//	daemon update          — resolve the channel's newest tag from GitHub
+//	                         ONCE; write. Exits non-zero on resolve failure.
+//	                         No retry. A staged release ("Rollout: N%" in
+//	                         its notes) that has not reached this install
+//	                         leaves desired unchanged (rollout.go).
+//
+// --channel stable|beta|pinned selects (and persists, in version.json) what
+// the no-version form resolves: stable = GitHub "Latest", beta = newest
+// release including pre-releases, pinned = never resolve (an explicit tag is
+// the only way to move). The channel only ever affects this command — the
+// reconcile loop does not auto-update on any channel.
+//
+// --window HH:MM-HH:MM persists an update maintenance window: outside it the
+// reconcile loop keeps a healthy running platform on its current version and
+// swaps to the new desired one on the first tick inside the window (the swap
+// is a stop/start, so protection blips). Cold starts and rollbacks ignore the
+// window. `--window ""` clears it. On its own it changes only the setting.
+//
+// --check is read-only: it resolves what the no-version form WOULD write (or
+// inspects an explicit tag), prints it with a summary of that release's notes,
+// and writes nothing — not desired, not --channel, not --mirror/--proxy. It
+// exits 4 when there is an update to apply, 0 when there is none.
+//
+// --quiet prints nothing but errors. Exit codes follow exitcode.go: 0 done
+// or up to date · 1 failed (resolve, write) · 4 update available (--check)
+// · 64 usage.
+//
+// --mirror / --proxy persist download-network overrides (a release mirror
+// base URL, a proxy URL) that every later fetch — reconcile, self-update,
+// this command — uses. An explicit empty value clears one. They may be set
+// with or without a version; a version-less call with only these flags
+// still resolves on the channel afterwards.
 //
 // The reconcile loop sees the new desired on its next tick and downloads
 // + swaps via the normal EnsureRunning path (which is fetch-then-stop
@@ -629,8 +947,52 @@ func doUpdate(args []string) int {
 	// target the operator chose.
 	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
 	gh := fs.String("github", defaultGithubRepo, "owner/repo (for `update` with no version arg)")
-	_ = fs.Parse(args)
+	channel := fs.String("channel", "", "update channel to select and persist: stable|beta|pinned (default: the persisted channel)")
+	mirror := fs.String("mirror", "", "persist an https release-mirror base URL for downloads (empty value clears)")
+	proxy := fs.String("proxy", "", "persist a proxy URL for downloads (empty value clears; unset uses HTTPS_PROXY)")
+	window := fs.String("window", "", "persist an update maintenance window HH:MM-HH:MM (local time) for version swaps (empty value clears)")
+	check := fs.Bool("check", false, "report the available update and its release notes; change nothing")
+	history := fs.Bool("history", false, "print the update history (versions that became last-known-good); change nothing")
+	rollback := fs.Bool("rollback", false, "set desired back to the previous healthy version and pin the channel")
+	quiet := fs.Bool("quiet", false, "print nothing but errors; branch on the exit code")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
 	explicit := fs.Arg(0) // optional positional version, e.g. v1.2.3
+	mirrorSet, proxySet, windowSet := false, false, false
+	fs.Visit(func(f *flag.Flag) {
+		mirrorSet = mirrorSet || f.Name == "mirror"
+		proxySet = proxySet || f.Name == "proxy"
+		windowSet = windowSet || f.Name == "window"
+	})
+	win, werr := core.ParseWindow(*window)
+	if werr != nil {
+		fmt.Fprintln(os.Stderr, "update: --window must be HH:MM-HH:MM in 24h local time, e.g. 02:00-05:00")
+		return exitUsage
+	}
+	if mirrorSet && *mirror != "" && !fetch.ValidMirror(*mirror) {
+		fmt.Fprintln(os.Stderr, "update: --mirror must be an https URL with no query, e.g. https://mirror.example.com/gh")
+		return exitUsage
+	}
+	if proxySet && *proxy != "" && !fetch.ValidProxy(*proxy) {
+		fmt.Fprintln(os.Stderr, "update: --proxy must be an http, https or socks5 URL, e.g. http://proxy.corp:3128")
+		return exitUsage
+	}
+	if *channel != "" && !core.ValidChannel(*channel) {
+		fmt.Fprintln(os.Stderr, "update: --channel must be one of stable, beta, pinned")
+		return exitUsage
+	}
+	if *rollback && (explicit != "" || *channel != "" || *check) {
+		fmt.Fprintln(os.Stderr, "update: --rollback takes no version, --channel or --check")
+		return exitUsage
+	}
+	if *channel == core.ChannelPinned && explicit == "" && !*check {
+		fmt.Fprintln(os.Stderr, "update: --channel pinned needs a version to pin: daemon update --channel pinned vX.Y.Z")
+		return exitUsage
+	}
 
 	// Resolve the target workdir. A real install relocates to a disguised,
 	// random path, so by default we DISCOVER the running mesh's workdir
@@ -646,11 +1008,77 @@ func doUpdate(args []string) int {
 		return 1
 	}
 
+	var out io.Writer = os.Stdout
+	if *quiet {
+		out = io.Discard
+		logFlags.level = "error"
+	}
 	o := opts{workdir: workdir, github: *gh, asset: platformAsset()}
-	_, log := build(o)
+	_, log, _ := build(o)
 
 	st := &core.Store{Dir: o.workdir}
 
+	if *history {
+		return printHistory(st, out)
+	}
+	if *rollback {
+		if refuseWhileLocked(st, "update --rollback", os.Stderr) {
+			return 1
+		}
+		return rollbackUpdate(st, out)
+	}
+
+	if *check {
+		ch := *channel
+		if ch == "" {
+			ch = st.Channel()
+		}
+		if explicit != "" && !isValidVersionTag(explicit) {
+			fmt.Fprintln(os.Stderr, "update: version must be a strict semver tag like v0.9.0 or v1.2.3-rc.1")
+			return exitUsage
+		}
+		return checkUpdate(context.Background(), githubFetcher(o.github, o.asset, st),
+			st.Desired(), ch, explicit, st.InstallSalt(), out)
+	}
+
+	if mirrorSet || proxySet {
+		m, p := st.Network()
+		if mirrorSet {
+			m = *mirror
+		}
+		if proxySet {
+			p = *proxy
+		}
+		if err := st.WriteNetwork(m, p); err != nil {
+			log.Error("write network settings failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+		log.Info("download network set", "mirror", m != "", "proxy", p != "")
+	}
+
+	if windowSet {
+		if err := st.WriteUpdateWindow(win); err != nil {
+			log.Error("write update window failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+		if win.IsZero() {
+			log.Info("update window cleared; version swaps apply immediately")
+		} else {
+			log.Info("update window set", "window", win.String(), "note", "version swaps outside it wait")
+		}
+		if explicit == "" && *channel == "" && !mirrorSet && !proxySet {
+			return 0 // a pure settings change; don't also hit the network
+		}
+	}
+
+	if *channel != "" {
+		if err := st.WriteChannel(*channel); err != nil {
+			log.Error("write channel failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+		log.Info("channel set", "channel", *channel)
+	}
+
 	if explicit != "" {
 		// Strict tag validator — accepting any "v…" string would let
 		// `v../etc/passwd` reach Store.WriteDesired and then become part
@@ -658,7 +1086,7 @@ func doUpdate(args []string) int {
 		if !isValidVersionTag(explicit) {
 			log.Error("update: version must be a strict semver tag like v0.9.0 or v1.2.3-rc.1",
 				"got", explicit)
-			return 2
+			return exitUsage
 		}
 		if err := st.WriteDesired(explicit); err != nil {
 			// Don't log raw err: the store path can be the disguised workdir.
@@ -669,25 +1097,66 @@ func doUpdate(args []string) int {
 		return 0
 	}
 
-	// No version given → one-shot GH "Latest". On any failure, exit
-	// non-zero immediately; no retry, no tick loop. The reconcile loop
-	// never re-tries this resolve on its own.
+	// No version given → one-shot resolve on the persisted channel. On any
+	// failure, exit non-zero immediately; no retry, no tick loop. The
+	// reconcile loop never re-tries this resolve on its own.
+	ch := st.Channel()
+	if ch == core.ChannelPinned {
+		log.Info("channel is pinned; desired left unchanged",
+			"desired", st.Desired(), "hint", "daemon update vX.Y.Z, or --channel stable|beta")
+		return 0
+	}
 	ctx := context.Background()
-	f := &fetch.GitHub{Repo: o.github, Asset: o.asset}
-	v, err := f.ResolveLatest(ctx)
+	f := githubFetcher(o.github, o.asset, st)
+	resolve := f.ResolveLatest
+	if ch == core.ChannelBeta {
+		resolve = f.ResolveNewest
+	}
+	v, err := resolve(ctx)
 	if err != nil {
-		log.Error("resolve latest from GitHub failed", "err", err,
+		log.Error("resolve from GitHub failed", "channel", ch, "err", err,
 			"hint", "pass an explicit version: daemon update vX.Y.Z")
 		return 1
 	}
+	// Same strict validator as the explicit form: a release tag is remote
+	// input and becomes part of the on-disk binary path.
+	if !isValidVersionTag(v) {
+		log.Error("resolved release tag is not a strict semver tag; refusing", "got", v)
+		return 1
+	}
+	if v != st.Desired() {
+		notes, err := f.ReleaseNotes(ctx, v)
+		if err != nil {
+			log.Error("read release notes failed; cannot tell if the release is staged", "err", err,
+				"hint", "retry, or pass the version explicitly: daemon update "+v)
+			return 1
+		}
+		if held, pct := heldBack(notes, st.InstallSalt(), v); held {
+			log.Info("staged rollout has not reached this install; desired left unchanged",
+				"version", v, "rollout_pct", pct, "hint", "apply it anyway: daemon update "+v)
+			return 0
+		}
+	}
 	if err := st.WriteDesired(v); err != nil {
 		log.Error("write desired failed", "err", err)
 		return 1
 	}
-	log.Info("desired written", "version", v, "note", "resolved from GitHub")
+	log.Info("desired written", "version", v, "channel", ch, "note", "resolved from GitHub")
 	return 0
 }
 
+// githubFetcher builds the release fetcher with the operator's persisted
+// download-network overrides (`daemon update --mirror/--proxy`) from st, and
+// the masked on-disk ETag cache so repeated `update` / `update --check` runs
+// revalidate instead of spending the unauthenticated API quota.
+func githubFetcher(repo, asset string, st *core.Store) *fetch.GitHub {
+	mirror, proxy := st.Network()
+	return &fetch.GitHub{
+		Repo: repo, Asset: asset, Mirror: mirror, Proxy: proxy,
+		Cache: &fetch.FileCache{Path: st.APICachePath(), Seal: st.MaskState, Open: st.UnmaskState},
+	}
+}
+
 // resolveUpdateWorkdir picks the workdir `daemon update` writes to. An
 // explicit (non-default) --workdir is always honored. Otherwise, if a
 // install. Precedence: an explicit (non-empty) --workdir is always
@@ -791,6 +1260,11 @@ func doInstall(args []string) int {
 		Interval:       workerHealInterval,
 		EnsureInterval: osadapter.EnsureBackstopInterval,
 	}
+	// A reinstall keeps the recovery-code record of the install it replaces.
+	var prevRecovery core.Recovery
+	if pwd, derr := discoverInstallWorkdir(); derr == nil && pwd != "" && m != mode.Test {
+		prevRecovery = (&core.Store{Dir: pwd}).Recovery()
+	}
 	if err := installMesh(self, &spec, *desired); err != nil {
 		// Fail fast, no silent downgrade (FEATURE 08 / ADR-0010). If the
 		// operator clearly intended the full (system) install — they ran
@@ -807,6 +1281,9 @@ func doInstall(args []string) int {
 	for _, line := range installCoverageNotice(m) {
 		fmt.Println(line)
 	}
+	if m != mode.Test {
+		issueRecoveryCode(&core.Store{Dir: spec.Workdir}, prevRecovery, os.Stdout)
+	}
 
 	// FEATURE 18 / ADR-0020: stand up the out-of-band COMPANION rail AFTER the
 	// mesh is up — a SEPARATE minimal binary in its OWN fixed disguised folder
@@ -1005,6 +1482,7 @@ func doUninstall(args []string) int {
 	fs := flag.NewFlagSet("uninstall", flag.ContinueOnError)
 	wantTest := registerTestMode(fs) // --test-mode only under -tags e2e
 	abort := fs.Bool("abort", false, "discard uninstall-cooldown progress and keep the protection")
+	recovery := fs.Bool("recovery-code", false, "skip the cooldown and any strict lock with the one-time emergency recovery code")
 	_ = fs.Parse(args)
 	if wantTest() {
 		// e2e/test installs bypass the commitment gate entirely so CI
@@ -1028,21 +1506,48 @@ func doUninstall(args []string) int {
 		fmt.Fprintln(os.Stderr, "uninstall: cannot resolve home directory:", herr)
 		return 1
 	}
+	// The gate's progress lives in the install's store; gpath is where an
+	// older install kept it.
 	gpath := uninstallgate.StatePath(mode.Resolve(), home)
+	lwd, lerr := resolveUpdateWorkdir("", defaultWorkdir(), discoverInstallWorkdir)
 	if *abort {
-		if err := uninstallgate.Clear(gpath); err != nil {
-			fmt.Fprintln(os.Stderr, "uninstall --abort:", err)
+		err := uninstallgate.Clear(gpath)
+		if lerr == nil {
+			if serr := (&core.Store{Dir: lwd}).ClearUninstallGate(); serr != nil {
+				err = serr
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "uninstall --abort: could not reset the gate (re-run with sudo?)")
 			return 1
 		}
 		fmt.Println("uninstall aborted — cooldown reset, protection kept.")
 		return 0
 	}
-	if code, proceed := runUninstallGate(gpath); !proceed {
-		return code
+	// A strict lock (`daemon lock`) closes the gate entirely: not even
+	// step 1 can start while it holds. Only the recovery code passes it.
+	if lerr != nil {
+		fmt.Fprintln(os.Stderr, "uninstall: could not locate the install to check the strict lock; re-run with sudo")
+		return 1
+	}
+	gst := &core.Store{Dir: lwd}
+	if *recovery {
+		// The emergency code stands in for the whole gate, lock included.
+		if !redeemRecoveryCode(gst, os.Stdin, os.Stdout) {
+			return 1
+		}
+	} else {
+		if refuseWhileLocked(gst, "uninstall", os.Stderr) {
+			return 1
+		}
+		adoptGateFile(gst, gpath)
+		if code, proceed := runUninstallGate(gst); !proceed {
+			return code
+		}
 	}
 
 	// Gate satisfied — labels are randomized, find ours by Ed25519 sig.
-	removed, err := osadapter.UninstallProd()
+	removed, dirs, err := osadapter.UninstallProd()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "uninstall:", err)
 		return 1
@@ -1064,7 +1569,7 @@ func doUninstall(args []string) int {
 	// Redact the disguised labels (consistent with install/self-update): the
 	// removed set is exactly the strings a targeted bootout would need. Count
 	// only. (go-reviewer L4 / security-reviewer MEDIUM.)
-	fmt.Printf("uninstalled (prod): %d entries removed\n", len(removed))
+	fmt.Printf("uninstalled (prod): %d entries removed, %d directories deleted\n", len(removed), dirs)
 	return 0
 }
 
@@ -1072,10 +1577,15 @@ func doUninstall(args []string) int {
 // returns (exitCode, proceed): proceed=true means all steps are done and
 // the caller should perform the real teardown; proceed=false means the
 // caller should return exitCode now (waiting, rejected, or step accepted
-// but more steps remain).
-func runUninstallGate(gpath string) (code int, proceed bool) {
-	st := uninstallgate.Load(gpath, time.Now())
-	o := uninstallgate.Evaluate(st, time.Now())
+// but more steps remain). With an accountability partner set, a code sent
+// to the partner is asked for after the third step (partnerApproval).
+// Progress is kept sealed in the install's store
+// and timed by its trusted clock, so the cool-offs cannot be skipped by
+// setting the system clock forward.
+func runUninstallGate(gs *core.Store) (code int, proceed bool) {
+	now := gs.Now
+	st := gs.UninstallGate(now())
+	o := uninstallgate.Evaluate(st, now())
 
 	if o.Kind == uninstallgate.Wait {
 		fmt.Printf("Uninstall is on a cooldown. Come back in %s.\n",
@@ -1084,7 +1594,7 @@ func runUninstallGate(gpath string) (code int, proceed bool) {
 	}
 
 	if o.Kind == uninstallgate.Transcribe {
-		ref := uninstallgate.Passage(o.Step)
+		ref := gatePassage(gs, o.Step)
 		fmt.Printf("Uninstall step %d of %d.\n\n"+
 			"Type the passage below EXACTLY, by hand. This is intentional "+
 			"friction: if the urge to uninstall is impulsive it will fade "+
@@ -1105,12 +1615,18 @@ func runUninstallGate(gpath string) (code int, proceed bool) {
 			fmt.Fprintln(os.Stderr, "not accepted:", why, "(no progress lost — try again)")
 			return 1, false
 		}
-		st = uninstallgate.Advance(st, time.Now())
-		if err := uninstallgate.Save(gpath, st); err != nil {
-			fmt.Fprintln(os.Stderr, "gate save:", err)
+		st = uninstallgate.Advance(st, now())
+		if err := gs.WriteUninstallGate(st); err != nil {
+			fmt.Fprintln(os.Stderr, "uninstall: step not recorded (store not writable; re-run with sudo?)")
 			return 1, false
 		}
-		o = uninstallgate.Evaluate(st, time.Now())
+		if h, ok := partnerHook(gs); ok && st.Step == 1 {
+			if err := tellPartner(h, notify.Event{Kind: notify.GateStarted,
+				Message: "the first uninstall step was just completed on this Mac"}); err != nil {
+				fmt.Fprintln(os.Stderr, "uninstall: your partner could not be told (step still recorded)")
+			}
+		}
+		o = uninstallgate.Evaluate(st, now())
 		if o.Kind != uninstallgate.Proceed {
 			fmt.Printf("Step accepted. Come back in %s to continue.\n",
 				o.Remaining.Round(time.Minute))
@@ -1119,5 +1635,25 @@ func runUninstallGate(gpath string) (code int, proceed bool) {
 		// step 3 just completed → fall through to teardown
 	}
 
-	return 0, true // o.Kind == Proceed
+	// o.Kind == Proceed: the partner's code, when one is set, is the last
+	// step.
+	return partnerApproval(gs, st, os.Stdin)
+}
+
+// adoptGateFile moves progress an older install kept in the standalone gate
+// file into the store, then removes the file. Progress already in the store
+// wins; a file that does not verify is just removed, like any tampered
+// state.
+func adoptGateFile(gs *core.Store, gpath string) {
+	if _, err := os.Stat(gpath); err != nil {
+		return
+	}
+	if !gs.HasUninstallGate() {
+		if g := uninstallgate.Load(gpath, gs.Now()); g.Step > 0 {
+			if err := gs.WriteUninstallGate(g); err != nil {
+				return // keep the file for the next attempt
+			}
+		}
+	}
+	_ = uninstallgate.Clear(gpath)
 }
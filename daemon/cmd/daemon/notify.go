@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/heartbeat"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/shared/tracing"
+)
+
+// hookList collects repeated --webhook flags.
+type hookList []notify.Hook
+
+func (l *hookList) String() string { return fmt.Sprint(len(*l)) }
+
+func (l *hookList) Set(s string) error {
+	h, err := notify.ParseHook(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, h)
+	return nil
+}
+
+// storeHooks parses the persisted hooks, skipping any that no longer parse
+// (a hand-edited or older file must not break delivery to the rest).
+func storeHooks(st *core.Store) []notify.Hook {
+	var hooks []notify.Hook
+	for _, s := range st.Webhooks() {
+		if h, err := notify.ParseHook(s); err == nil {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// replacesHooks reports whether setting hooks would change a configured
+// set: re-pointing the alerts silences them as surely as clearing them.
+func replacesHooks(st *core.Store, hooks []notify.Hook) bool {
+	cur := st.Webhooks()
+	if len(cur) == 0 {
+		return false
+	}
+	next := make([]string, 0, len(hooks))
+	for _, h := range hooks {
+		next = append(next, h.String())
+	}
+	return !slices.Equal(cur, next)
+}
+
+// traceURL is the OTLP traces URL the daemon exports spans to: the endpoint
+// stored by `daemon notify --otlp`, else the standard OTel environment, else
+// "" (tracing off). Re-read at every flush, so a change needs no restart.
+func traceURL(st *core.Store) string {
+	if ep := st.TraceEndpoint(); ep != "" {
+		return tracing.TracesURL("", ep)
+	}
+	return tracing.EnvURL()
+}
+
+// heartbeatConfig is the heartbeat endpoint and signing key from st, or ""
+// and nil when the heartbeat is off (no endpoint, or no usable key).
+func heartbeatConfig(st *core.Store) (string, ed25519.PrivateKey) {
+	endpoint := st.HeartbeatEndpoint()
+	if endpoint == "" {
+		return "", nil
+	}
+	key, err := heartbeat.KeyFromSeed(st.HeartbeatSeed())
+	if err != nil {
+		return "", nil
+	}
+	return endpoint, key
+}
+
+// doNotify is `daemon notify`: show, replace, clear or test the webhooks the
+// reconcile loop notifies on protection events.
+//
+//	daemon notify                                 — list hooks (format + host only)
+//	daemon notify --webhook slack=URL [--webhook ntfy=URL]
+//	                                              — replace the configured set
+//	daemon notify --clear                         — remove every hook
+//	daemon notify --test                          — send a test event now
+//	daemon notify --otlp http://collector:4318    — export OTel spans there ("" turns tracing off)
+//	daemon notify --heartbeat https://server/beat — send the signed dead-man heartbeat there ("" stops it)
+//	daemon notify --hung-after 5m                 — restart workers hung this long (0 restores the default)
+//
+// The hooks live in the daemon's masked version.json, so the running mesh
+// picks a change up on its next event without a restart. The OTLP endpoint
+// sits beside them: the daemon reads it at every span flush, and hands it to
+// the platform child on its next start. So does the heartbeat endpoint;
+// setting it prints the device id and public key to enroll with the server.
+// --hung-after is how long the companion lets the mesh heartbeat stay stale
+// with the workers still loaded before it restarts them and sends a
+// daemon_hung event. Under a strict lock (`daemon lock`) --clear, changing
+// configured webhooks, stopping or re-pointing the heartbeat and raising
+// --hung-after refuse.
+func doNotify(args []string) int {
+	fs := flag.NewFlagSet("notify", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	var hooks hookList
+	fs.Var(&hooks, "webhook", "FORMAT=URL with FORMAT slack|discord|ntfy|json; repeat for several; replaces the configured set")
+	clear := fs.Bool("clear", false, "remove every configured webhook")
+	test := fs.Bool("test", false, "send a test notification to the configured webhooks")
+	otlp := fs.String("otlp", "", "persist an OTLP/HTTP endpoint for trace export, e.g. http://localhost:4318 (empty value clears)")
+	hb := fs.String("heartbeat", "", "persist the focusd server endpoint for the signed dead-man heartbeat (empty value stops it)")
+	hung := fs.Duration("hung-after", 0, "restart the mesh workers when their heartbeat is stale this long while loaded, e.g. 5m (0 restores the default)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	otlpSet, hbSet, hungSet := false, false, false
+	fs.Visit(func(f *flag.Flag) {
+		otlpSet = otlpSet || f.Name == "otlp"
+		hbSet = hbSet || f.Name == "heartbeat"
+		hungSet = hungSet || f.Name == "hung-after"
+	})
+	if hungSet && *hung != 0 && *hung < core.MinHungAfter {
+		fmt.Fprintln(os.Stderr, "notify: --hung-after must be at least", core.MinHungAfter)
+		return 2
+	}
+	if otlpSet && *otlp != "" && !tracing.ValidEndpoint(*otlp) {
+		fmt.Fprintln(os.Stderr, "notify: --otlp must be an http or https URL with no query, e.g. http://localhost:4318")
+		return 2
+	}
+	if hbSet && *hb != "" && !heartbeat.ValidEndpoint(*hb) {
+		fmt.Fprintln(os.Stderr, "notify: --heartbeat must be an absolute http or https URL")
+		return 2
+	}
+	if *clear && len(hooks) > 0 {
+		fmt.Fprintln(os.Stderr, "notify: --clear takes no --webhook")
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "notify: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	st := &core.Store{Dir: workdir}
+	raisesHung := hungSet && effectiveHungAfter(*hung) > st.HungAfter()
+	movesHooks := (*clear || len(hooks) > 0) && replacesHooks(st, hooks)
+	cur := st.HeartbeatEndpoint()
+	movesBeat := hbSet && cur != "" && *hb != cur
+	if movesHooks || movesBeat || raisesHung {
+		if refuseWhileLocked(st, "notify", os.Stderr) {
+			return 1
+		}
+	}
+	if otlpSet {
+		if code := setTraceEndpoint(st, *otlp, os.Stdout); code != 0 {
+			return code
+		}
+	}
+	if hbSet {
+		if code := setHeartbeat(st, *hb, os.Stdout); code != 0 {
+			return code
+		}
+	}
+	if hungSet {
+		if code := setHungAfter(st, *hung, os.Stdout); code != 0 {
+			return code
+		}
+	}
+	log := newLogger(slog.LevelWarn, st)
+	return configureNotify(st, hooks, *clear, *test, notify.New(func() []notify.Hook { return storeHooks(st) }, log), os.Stdout)
+}
+
+// configureNotify applies the parsed flags to st and prints the result to
+// out, a failure to stderr. Returns the exit code.
+func configureNotify(st *core.Store, hooks []notify.Hook, clear, test bool, n *notify.Notifier, out io.Writer) int {
+	if clear || len(hooks) > 0 {
+		var raw []string
+		for _, h := range hooks {
+			raw = append(raw, h.String())
+		}
+		if err := st.WriteWebhooks(raw); err != nil {
+			fmt.Fprintln(os.Stderr, "  notify: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	current := storeHooks(st)
+	if len(current) == 0 {
+		fmt.Fprintln(out, "  notify: no webhooks configured")
+	}
+	for _, h := range current {
+		fmt.Fprintln(out, "  webhook:", h.Redacted())
+	}
+	if !test {
+		return 0
+	}
+	if len(current) == 0 {
+		return 1
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ev := notify.Event{Kind: notify.Test, At: time.Now(), Message: "test notification"}
+	if err := n.Deliver(ctx, ev); err != nil {
+		fmt.Fprintln(os.Stderr, "  notify: test failed:", strings.TrimSpace(err.Error()))
+		return 1
+	}
+	fmt.Fprintln(out, "  notify: test delivered")
+	return 0
+}
+
+// setTraceEndpoint persists the OTLP endpoint and prints the result (the host
+// only: a collector URL can carry a credential in its path). Returns the exit
+// code.
+func setTraceEndpoint(st *core.Store, endpoint string, out io.Writer) int {
+	if err := st.WriteTraceEndpoint(endpoint); err != nil {
+		fmt.Fprintln(os.Stderr, "  tracing: write failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	if endpoint == "" {
+		fmt.Fprintln(out, "  tracing: off (unless OTEL_EXPORTER_OTLP_ENDPOINT is set)")
+		return 0
+	}
+	u, _ := url.Parse(endpoint)
+	fmt.Fprintln(out, "  tracing: OTLP export to", u.Host, "(platform picks it up on its next start)")
+	return 0
+}
+
+// setHeartbeat persists the heartbeat endpoint and prints the result: the
+// host only, plus the device id and public key the server must be told about
+// (neither is secret). Returns the exit code.
+func setHeartbeat(st *core.Store, endpoint string, out io.Writer) int {
+	if err := st.WriteHeartbeatEndpoint(endpoint); err != nil {
+		fmt.Fprintln(os.Stderr, "  heartbeat: write failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	if endpoint == "" {
+		fmt.Fprintln(out, "  heartbeat: off (the server will report this device overdue)")
+		return 0
+	}
+	key, err := heartbeat.KeyFromSeed(st.HeartbeatSeed())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "  heartbeat: signing key unreadable")
+		return 1
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	u, _ := url.Parse(endpoint)
+	fmt.Fprintln(out, "  heartbeat: signed beats to", u.Host, "every", heartbeatInterval)
+	fmt.Fprintln(out, "  heartbeat: device", heartbeat.DeviceID(pub))
+	fmt.Fprintln(out, "  heartbeat: public key", heartbeat.PublicKeyString(pub))
+	return 0
+}
+
+// effectiveHungAfter is the threshold a --hung-after value stores: 0 means
+// the default.
+func effectiveHungAfter(d time.Duration) time.Duration {
+	if d == 0 {
+		return core.DefaultHungAfter
+	}
+	return d
+}
+
+// setHungAfter persists the hung-worker threshold and prints it. Returns the
+// exit code.
+func setHungAfter(st *core.Store, d time.Duration, out io.Writer) int {
+	if err := st.WriteHungAfter(d); err != nil {
+		fmt.Fprintln(os.Stderr, "  hung-after: write failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	fmt.Fprintln(out, "  hung-after: workers restarted after", st.HungAfter(), "without a heartbeat")
+	return 0
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/shared/tracing"
+)
+
+func TestConfigureNotify(t *testing.T) {
+	var posts int
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { posts++ }))
+	defer srv.Close()
+
+	st := &core.Store{Dir: t.TempDir()}
+	n := notify.New(func() []notify.Hook { return storeHooks(st) }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var out bytes.Buffer
+
+	if code := configureNotify(st, nil, false, true, n, &out); code != 1 {
+		t.Fatalf("--test with no hooks: code = %d, want 1", code)
+	}
+
+	h, err := notify.ParseHook("slack=" + srv.URL + "/services/SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if code := configureNotify(st, []notify.Hook{h}, false, true, n, &out); code != 0 {
+		t.Fatalf("set+test: code = %d, out=%s", code, out.String())
+	}
+	if posts != 1 || len(st.Webhooks()) != 1 {
+		t.Fatalf("posts=%d stored=%v, want 1 post and 1 stored hook", posts, st.Webhooks())
+	}
+	if strings.Contains(out.String(), "SECRET") {
+		t.Fatalf("listing must not print the hook URL:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := configureNotify(st, nil, true, false, n, &out); code != 0 || st.Webhooks() != nil {
+		t.Fatalf("--clear: code = %d, stored = %v", code, st.Webhooks())
+	}
+}
+
+// TestNotifyRefusesRepointingUnderLock: under a strict lock a configured
+// webhook or heartbeat cannot be swapped for another, only kept.
+func TestNotifyRefusesRepointingUnderLock(t *testing.T) {
+	dir := t.TempDir()
+	st := &core.Store{Dir: dir}
+	if err := st.WriteWebhooks([]string{"slack=https://hooks.example.com/mine"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.WriteHeartbeatEndpoint("https://beat.example.com/in"); err != nil {
+		t.Fatal(err)
+	}
+	applyLock(st, 1, time.Now(), strings.NewReader("lock for 1 days\n"), &bytes.Buffer{})
+
+	notify := func(args ...string) int { return run(append([]string{"notify", "--workdir", dir}, args...)) }
+	if notify("--webhook", "slack=https://hooks.example.com/other") != 1 || st.Webhooks()[0] != "slack=https://hooks.example.com/mine" {
+		t.Fatalf("re-pointed the webhook under a lock: %v", st.Webhooks())
+	}
+	if notify("--heartbeat", "https://other.example.com/in") != 1 || st.HeartbeatEndpoint() != "https://beat.example.com/in" {
+		t.Fatalf("re-pointed the heartbeat under a lock: %q", st.HeartbeatEndpoint())
+	}
+	if notify("--clear") != 1 || len(st.Webhooks()) != 1 {
+		t.Fatal("cleared the webhooks under a lock")
+	}
+	if code := notify("--webhook", "slack=https://hooks.example.com/mine"); code != 0 {
+		t.Fatalf("keeping the same webhook refused under a lock: code %d", code)
+	}
+}
+
+func TestSetTraceEndpoint(t *testing.T) {
+	t.Setenv(tracing.EndpointEnv, "")
+	t.Setenv(tracing.TracesEndpointEnv, "")
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	if code := setTraceEndpoint(st, "https://otel.example/SECRET", &out); code != 0 {
+		t.Fatalf("code = %d", code)
+	}
+	if got := traceURL(st); got != "https://otel.example/SECRET/v1/traces" {
+		t.Fatalf("traceURL = %q", got)
+	}
+	if strings.Contains(out.String(), "SECRET") || !strings.Contains(out.String(), "otel.example") {
+		t.Fatalf("output must show the host only:\n%s", out.String())
+	}
+	if code := setTraceEndpoint(st, "", &out); code != 0 || traceURL(st) != "" {
+		t.Fatalf("clear: code = %d, traceURL = %q", code, traceURL(st))
+	}
+}
+
+func TestSetHeartbeat(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	if code := setHeartbeat(st, "https://hb.example/beat/SECRET", &out); code != 0 {
+		t.Fatalf("code = %d", code)
+	}
+	endpoint, key := heartbeatConfig(st)
+	if endpoint != "https://hb.example/beat/SECRET" || key == nil {
+		t.Fatalf("config = %q, key set %v", endpoint, key != nil)
+	}
+	if strings.Contains(out.String(), "SECRET") || !strings.Contains(out.String(), "public key") {
+		t.Fatalf("output must show the host and enrolment key only:\n%s", out.String())
+	}
+	if code := setHeartbeat(st, "", &out); code != 0 {
+		t.Fatalf("clear: code = %d", code)
+	}
+	if endpoint, key := heartbeatConfig(st); endpoint != "" || key != nil {
+		t.Fatal("cleared heartbeat still configured")
+	}
+}
+
+func TestSetHungAfter(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	if st.HungAfter() != core.DefaultHungAfter {
+		t.Fatalf("default = %s", st.HungAfter())
+	}
+	var out bytes.Buffer
+	if code := setHungAfter(st, 5*time.Minute, &out); code != 0 || st.HungAfter() != 5*time.Minute {
+		t.Fatalf("set: code = %d, HungAfter = %s", code, st.HungAfter())
+	}
+	if code := setHungAfter(st, 10*time.Second, &out); code != 1 || st.HungAfter() != 5*time.Minute {
+		t.Fatalf("below the floor: code = %d, HungAfter = %s", code, st.HungAfter())
+	}
+	if code := setHungAfter(st, 0, &out); code != 0 || st.HungAfter() != core.DefaultHungAfter {
+		t.Fatalf("reset: code = %d, HungAfter = %s", code, st.HungAfter())
+	}
+}
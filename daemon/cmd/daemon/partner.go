@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+)
+
+// partnerTimeout bounds one delivery to the partner, retries included.
+const partnerTimeout = time.Minute
+
+// partnerHook is the accountability partner's webhook, if one is set and
+// still parses.
+func partnerHook(st *core.Store) (notify.Hook, bool) {
+	h, err := notify.ParseHook(st.Partner())
+	return h, err == nil
+}
+
+// tellPartner delivers ev to the one hook h, synchronously. The partner's
+// events go to the partner's hook alone: one of them carries the code.
+func tellPartner(h notify.Hook, ev notify.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), partnerTimeout)
+	defer cancel()
+	ev.At = time.Now()
+	n := notify.New(func() []notify.Hook { return []notify.Hook{h} }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return n.Deliver(ctx, ev)
+}
+
+// replacePartner writes the new partner hook, first telling the outgoing
+// partner, so dropping partner approval is never silent. A partner that
+// cannot be reached does not block the change; the user is told.
+func replacePartner(st *core.Store, v string) error {
+	if h, err := notify.ParseHook(v); err == nil {
+		v = h.String()
+	}
+	if old, ok := partnerHook(st); ok && old.String() != v {
+		if err := tellPartner(old, notify.Event{Kind: notify.PartnerRemoved,
+			Message: "you are no longer the accountability partner for this Mac's uninstall gate"}); err != nil {
+			fmt.Fprintln(os.Stderr, "  partner: the outgoing partner could not be told")
+		}
+	}
+	return st.WritePartner(v)
+}
+
+// partnerApproval is the step after the third passage when a partner is
+// set. The first call sends a code to the partner; the next reads it back.
+// It reports whether uninstall may proceed, and the exit code if not.
+func partnerApproval(gs *core.Store, st uninstallgate.State, in io.Reader) (code int, proceed bool) {
+	h, ok := partnerHook(gs)
+	if !ok || !st.Approved.IsZero() {
+		return 0, true
+	}
+	if st.Code == "" {
+		otp, hash, err := uninstallgate.NewCode()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "uninstall: no code issued (%T); try again\n", err)
+			return 1, false
+		}
+		if err := tellPartner(h, notify.Event{Kind: notify.GateCode,
+			Message: "uninstall code " + otp + " — give it only if you agree focusd should come off this Mac"}); err != nil {
+			fmt.Fprintln(os.Stderr, "uninstall: your partner could not be reached; no progress lost, try again later")
+			return 1, false
+		}
+		st.Code, st.LastSeen = hash, gs.Now()
+		if err := gs.WriteUninstallGate(st); err != nil {
+			fmt.Fprintln(os.Stderr, "uninstall: code not recorded (store not writable; re-run with sudo?)")
+			return 1, false
+		}
+		fmt.Println("A one-time code went to your accountability partner. Run `daemon uninstall` again and enter it.")
+		return 0, false
+	}
+	fmt.Print("Enter the code your partner was sent: ")
+	typed, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.TrimSpace(typed) == "" {
+		fmt.Fprintln(os.Stderr, "\nuninstall: no code entered (no progress lost)")
+		return 1, false
+	}
+	st, ok = uninstallgate.Approve(st, typed, gs.Now())
+	if err := gs.WriteUninstallGate(st); err != nil {
+		fmt.Fprintln(os.Stderr, "uninstall: not recorded (store not writable; re-run with sudo?)")
+		return 1, false
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, "uninstall: code not accepted. It is spent; run again to send your partner a new one")
+		return 1, false
+	}
+	if err := tellPartner(h, notify.Event{Kind: notify.GateOpened,
+		Message: "your code was entered; focusd is being uninstalled"}); err != nil {
+		fmt.Fprintln(os.Stderr, "uninstall: your partner could not be told the code was used")
+	}
+	return 0, true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+)
+
+// partnerInbox is a json webhook standing in for the partner.
+type partnerInbox struct {
+	mu  sync.Mutex
+	got []notify.Event
+}
+
+func (p *partnerInbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var ev notify.Event
+	_ = json.NewDecoder(r.Body).Decode(&ev)
+	p.mu.Lock()
+	p.got = append(p.got, ev)
+	p.mu.Unlock()
+}
+
+func (p *partnerInbox) last() notify.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.got[len(p.got)-1]
+}
+
+// TestPartnerApproval: after the third step a code goes to the partner;
+// a wrong entry spends it, a fresh one goes out, and the right one lets
+// uninstall proceed and tells the partner.
+func TestPartnerApproval(t *testing.T) {
+	inbox := &partnerInbox{}
+	srv := httptest.NewServer(inbox)
+	defer srv.Close()
+	gs := &core.Store{Dir: t.TempDir()}
+	if err := gs.WritePartner("json=" + srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	done := uninstallgate.State{Step: uninstallgate.TotalSteps, T1: past, T2: past, T3: past, LastSeen: past}
+	codeIn := regexp.MustCompile(`[0-9A-Z]{5}-[0-9A-Z]{5}`)
+
+	issue := func() string {
+		t.Helper()
+		if code, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader("")); code != 0 || proceed {
+			t.Fatalf("issuing a code: code %d, proceed %v", code, proceed)
+		}
+		ev := inbox.last()
+		if ev.Kind != notify.GateCode || !codeIn.MatchString(ev.Message) {
+			t.Fatalf("partner got %+v", ev)
+		}
+		return codeIn.FindString(ev.Message)
+	}
+
+	if err := gs.WriteUninstallGate(done); err != nil {
+		t.Fatal(err)
+	}
+	first := issue()
+	if gs.UninstallGate(time.Now()).Code == "" {
+		t.Fatal("the issued code was not recorded")
+	}
+	if _, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader("\n")); proceed {
+		t.Fatal("no code entered must not proceed")
+	}
+	if _, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader("AAAAA-AAAAA\n")); proceed {
+		t.Fatal("a wrong code must not proceed")
+	}
+	if gs.UninstallGate(time.Now()).Code != "" {
+		t.Fatal("a wrong code must spend the pending one")
+	}
+
+	issue()
+	if _, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader(first+"\n")); proceed {
+		t.Fatal("a spent code must not proceed")
+	}
+	third := issue()
+	if code, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader(third+"\n")); code != 0 || !proceed {
+		t.Fatalf("the right code: code %d, proceed %v", code, proceed)
+	}
+	if inbox.last().Kind != notify.GateOpened {
+		t.Fatalf("partner not told the code was used: %+v", inbox.last())
+	}
+	if code, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader("")); code != 0 || !proceed {
+		t.Fatal("an approved gate must stay open")
+	}
+}
+
+// TestPartnerApprovalUnreachable: a partner that cannot be reached leaves
+// nothing recorded, so the next run tries again.
+func TestPartnerApprovalUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	gs := &core.Store{Dir: t.TempDir()}
+	if err := gs.WritePartner("json=" + srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := gs.WriteUninstallGate(uninstallgate.State{Step: uninstallgate.TotalSteps, T1: past, T2: past, LastSeen: past}); err != nil {
+		t.Fatal(err)
+	}
+	if code, proceed := partnerApproval(gs, gs.UninstallGate(time.Now()), strings.NewReader("")); code != 1 || proceed {
+		t.Fatalf("code %d, proceed %v", code, proceed)
+	}
+	if gs.UninstallGate(time.Now()).Code != "" {
+		t.Fatal("an undelivered code must not be recorded")
+	}
+}
+
+// TestReplacePartnerTellsTheOldOne: replacing the partner tells the one
+// going, and the change counts as loosening.
+func TestReplacePartnerTellsTheOldOne(t *testing.T) {
+	inbox := &partnerInbox{}
+	srv := httptest.NewServer(inbox)
+	defer srv.Close()
+	gs := &core.Store{Dir: t.TempDir()}
+	k, _ := lookupConfigKey("uninstall.partner")
+	if k.loosens(gs, "json="+srv.URL) {
+		t.Fatal("naming a first partner only tightens")
+	}
+	if err := replacePartner(gs, "json="+srv.URL); err != nil || len(inbox.got) != 0 {
+		t.Fatalf("err %v, %d events", err, len(inbox.got))
+	}
+	if !k.loosens(gs, "") || !k.loosens(gs, "json=https://me.example.com/hook") {
+		t.Fatal("removing or replacing the partner must loosen")
+	}
+	if err := replacePartner(gs, ""); err != nil || gs.Partner() != "" {
+		t.Fatalf("err %v, partner %q", err, gs.Partner())
+	}
+	if inbox.last().Kind != notify.PartnerRemoved {
+		t.Fatalf("outgoing partner got %+v", inbox.last())
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+)
+
+// passageFetchTimeout bounds the fetch of a remote passage set; the gate
+// falls back to the built-ins rather than hang at the prompt.
+const passageFetchTimeout = 15 * time.Second
+
+// errNoPassages: the source was read but held no passage of usable length.
+var errNoPassages = errors.New("no passage of usable length")
+
+// validPassageSource reports whether v can name a passage set: an absolute
+// file path, or an https URL with a host.
+func validPassageSource(v string) bool {
+	if filepath.IsAbs(v) {
+		return true
+	}
+	u, err := url.Parse(v)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.Fragment == ""
+}
+
+// loadPassages reads the passage set at src: a file, or an https URL
+// fetched through client. At most uninstallgate.MaxSourceBytes are read.
+func loadPassages(ctx context.Context, src string, client *http.Client) ([]string, error) {
+	var r io.Reader
+	if filepath.IsAbs(src) {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, passageFetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("passages: HTTP %d", resp.StatusCode)
+		}
+		r = resp.Body
+	}
+	b, err := io.ReadAll(io.LimitReader(r, uninstallgate.MaxSourceBytes))
+	if err != nil {
+		return nil, err
+	}
+	set := uninstallgate.ParsePassages(string(b))
+	if len(set) == 0 {
+		return nil, errNoPassages
+	}
+	return set, nil
+}
+
+// passageClient is the HTTP client for a remote passage set, through the
+// proxy `daemon update --proxy` recorded when there is one.
+func passageClient(st *core.Store) *http.Client {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if _, p := st.Network(); p != "" {
+		if u, err := url.Parse(p); err == nil {
+			tr.Proxy = http.ProxyURL(u)
+		}
+	}
+	return &http.Client{Transport: tr}
+}
+
+// gatePassage is the passage for this attempt at step: one drawn at random
+// from the configured set, or the built-in one when none is configured or
+// the set cannot be read. Falling back keeps the ritual as long; it only
+// loses the personal text, which the note says.
+func gatePassage(st *core.Store, step int) string {
+	src := st.Passages()
+	if src == "" {
+		return uninstallgate.Passage(step)
+	}
+	set, err := loadPassages(context.Background(), src, passageClient(st))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "uninstall: your passages could not be read (%s); using the built-in one\n", passageErr(err))
+		return uninstallgate.Passage(step)
+	}
+	return uninstallgate.Pick(set, step)
+}
+
+// passageErr is a path-free reason for a failed load.
+func passageErr(err error) string {
+	switch {
+	case errors.Is(err, errNoPassages):
+		return fmt.Sprintf("no passage of %d–%d characters", uninstallgate.MinPassageRunes, uninstallgate.MaxPassageRunes)
+	case errors.Is(err, os.ErrNotExist):
+		return "file not found"
+	case errors.Is(err, os.ErrPermission):
+		return "file not readable"
+	}
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+)
+
+func TestLoadPassages(t *testing.T) {
+	mine := strings.Repeat("I installed this because I wanted my evenings back. ", 25)
+	path := filepath.Join(t.TempDir(), "passages.txt")
+	if err := os.WriteFile(path, []byte(mine+"\n---\n"+mine), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if set, err := loadPassages(context.Background(), path, nil); err != nil || len(set) != 2 {
+		t.Fatalf("file: %d passages, err %v", len(set), err)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			w.Write([]byte("just do it"))
+			return
+		}
+		if r.URL.Path != "/mine" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(mine))
+	}))
+	defer srv.Close()
+	if set, err := loadPassages(context.Background(), srv.URL+"/mine", srv.Client()); err != nil || len(set) != 1 {
+		t.Fatalf("remote: %d passages, err %v", len(set), err)
+	}
+	if _, err := loadPassages(context.Background(), srv.URL+"/short", srv.Client()); !errors.Is(err, errNoPassages) {
+		t.Fatalf("short set: err %v", err)
+	}
+	if _, err := loadPassages(context.Background(), srv.URL+"/gone", srv.Client()); err == nil {
+		t.Fatal("a 404 must fail the load")
+	}
+}
+
+// TestGatePassageFallsBack: a source that cannot be read still gives the
+// built-in passage, so a broken setting never skips or blocks a step.
+func TestGatePassageFallsBack(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	if gatePassage(st, 2) != uninstallgate.Passage(2) {
+		t.Fatal("no source configured must use the built-in passage")
+	}
+	if err := st.WritePassages(filepath.Join(t.TempDir(), "missing.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if gatePassage(st, 3) != uninstallgate.Passage(3) {
+		t.Fatal("an unreadable source must fall back to the built-in passage")
+	}
+}
+
+func TestValidPassageSource(t *testing.T) {
+	for v, want := range map[string]bool{
+		"/Users/me/why.txt":            true,
+		"https://example.com/why.txt":  true,
+		"why.txt":                      false,
+		"http://example.com/why.txt":   false,
+		"https:///why.txt":             false,
+		"https://example.com/why#frag": false,
+	} {
+		if validPassageSource(v) != want {
+			t.Errorf("validPassageSource(%q) = %v", v, !want)
+		}
+	}
+}
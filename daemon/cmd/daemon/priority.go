@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// doPriority is `daemon priority`: show or set how far the mesh — and the
+// platform and plugin scans it starts — yields to foreground work.
+//
+//	daemon priority             — show the nice value
+//	daemon priority --nice 10   — set it (0..19; higher yields more)
+//
+// The mesh runs as a Background launchd job at nice DefaultNice by default;
+// the companion runs as a Standard job at nice 0, so it stays ahead of what
+// it guards. Workers pick a change up on their next tick; the platform on
+// its next start.
+func doPriority(args []string) int {
+	fs := flag.NewFlagSet("priority", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "explicit daemon work directory (default: discover the running install)")
+	nice := fs.Int("nice", -1, "mesh nice value, 0..19")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	set := false
+	fs.Visit(func(f *flag.Flag) { set = set || f.Name == "nice" })
+	if set && (*nice < 0 || *nice > core.MaxNice) {
+		fmt.Fprintf(os.Stderr, "priority: --nice must be 0..%d\n", core.MaxNice)
+		return 2
+	}
+	workdir, derr := resolveUpdateWorkdir(*wd, defaultWorkdir(), discoverInstallWorkdir)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "priority: could not locate the install; re-run with sudo or pass --workdir")
+		return 1
+	}
+	return configurePriority(&core.Store{Dir: workdir}, *nice, os.Stdout)
+}
+
+// configurePriority writes nice when it is >= 0, then prints the setting.
+func configurePriority(st *core.Store, nice int, out io.Writer) int {
+	if nice >= 0 {
+		if err := st.WriteNice(nice); err != nil {
+			fmt.Fprintln(out, "  priority: write failed (store not writable; re-run with sudo?)")
+			return 1
+		}
+	}
+	n := st.Nice()
+	fmt.Fprintf(out, "  priority: nice %d (mesh, platform and scans; companion stays at 0)\n", n)
+	if n < core.DefaultNice {
+		fmt.Fprintln(out, "  priority: below the default; a user install may lack the rights to lower it")
+	}
+	return 0
+}
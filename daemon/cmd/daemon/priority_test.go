@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestConfigurePriority(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	if code := configurePriority(st, -1, &out); code != 0 || !strings.Contains(out.String(), "nice 5") {
+		t.Fatalf("default: code %d\n%s", code, out.String())
+	}
+	out.Reset()
+	if code := configurePriority(st, 12, &out); code != 0 || st.Nice() != 12 || !strings.Contains(out.String(), "nice 12") {
+		t.Fatalf("set: code %d, nice %d\n%s", code, st.Nice(), out.String())
+	}
+	out.Reset()
+	if code := configurePriority(st, 0, &out); code != 0 || st.Nice() != 0 || !strings.Contains(out.String(), "lack the rights") {
+		t.Fatalf("nice 0 must be kept and warned about: code %d, nice %d\n%s", code, st.Nice(), out.String())
+	}
+	if st.WriteNice(core.MaxNice+1) == nil || st.WriteNice(-1) == nil {
+		t.Fatal("WriteNice must refuse values outside 0..MaxNice")
+	}
+	if run([]string{"priority", "--nice", "20", "--workdir", t.TempDir()}) != 2 {
+		t.Fatal("--nice 20 must be a usage error")
+	}
+}
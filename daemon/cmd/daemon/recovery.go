@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
+)
+
+// issueRecoveryCode gives a fresh install its emergency recovery code,
+// printed once and kept only as a salted scrypt hash. prev is the record of the install
+// being replaced: a reinstall carries it over rather than issuing a new
+// code, so reinstalling never refills a spent one.
+func issueRecoveryCode(st *core.Store, prev core.Recovery, out io.Writer) {
+	if !prev.Issued.IsZero() {
+		if err := st.WriteRecovery(prev); err != nil {
+			fmt.Fprintln(out, "  recovery code: not carried over (store not writable)")
+		}
+		return
+	}
+	salt, err := st.EnsureInstallSalt()
+	if err != nil || salt == "" {
+		fmt.Fprintln(out, "  recovery code: none issued (the install salt could not be read or created)")
+		return
+	}
+	code, hash, err := uninstallgate.NewRecoveryCode(salt)
+	if err != nil {
+		fmt.Fprintln(out, "  recovery code: none issued (no code could be generated)")
+		return
+	}
+	if err := st.WriteRecovery(core.Recovery{Hash: hash, Salt: salt, Issued: time.Now()}); err != nil {
+		fmt.Fprintln(out, "  recovery code: none issued (the install store is not writable)")
+		return
+	}
+	fmt.Fprintln(out, "  Emergency recovery code:", code)
+	fmt.Fprintln(out, "  It is shown only this once. Give it to someone you trust, not to yourself.")
+	fmt.Fprintln(out, "  `daemon uninstall --recovery-code` with it skips the cooldown and any strict")
+	fmt.Fprintln(out, "  lock, one time only, and tells your partner and webhooks that it was used.")
+}
+
+// redeemRecoveryCode asks for the recovery code and, when it matches,
+// spends it and reports the use to the partner and the webhooks before
+// the caller tears down. A wrong code spends nothing: at 100 bits, behind
+// scrypt, it cannot be guessed one run at a time.
+func redeemRecoveryCode(st *core.Store, in io.Reader, out io.Writer) bool {
+	r := st.Recovery()
+	if r.Hash == "" {
+		if r.Used.IsZero() {
+			fmt.Fprintln(out, "uninstall: this install has no recovery code")
+		} else {
+			fmt.Fprintln(out, "uninstall: the recovery code was already used", r.Used.Local().Format(time.DateTime))
+		}
+		return false
+	}
+	fmt.Fprint(out, "Enter the emergency recovery code: ")
+	typed, _ := bufio.NewReader(in).ReadString('\n')
+	if !uninstallgate.RecoveryCodeMatches(r.Hash, r.Salt, strings.TrimSpace(typed)) {
+		fmt.Fprintln(out, "\nuninstall: recovery code not accepted")
+		return false
+	}
+	r.Hash, r.Used = "", time.Now()
+	if err := st.WriteRecovery(r); err != nil {
+		fmt.Fprintln(out, "uninstall: the code could not be marked used (store not writable; re-run with sudo?)")
+		return false
+	}
+	ev := notify.Event{Kind: notify.RecoveryUsed,
+		Message: "the emergency recovery code was used; focusd is being uninstalled without the cooldown"}
+	hooks := storeHooks(st)
+	if h, ok := partnerHook(st); ok {
+		hooks = append(hooks, h)
+	}
+	for _, h := range hooks {
+		if err := tellPartner(h, ev); err != nil {
+			fmt.Fprintf(out, "uninstall: %s webhook could not be told of the recovery\n", h.Format)
+		}
+	}
+	fmt.Fprintln(out, "Recovery code accepted and spent; the cooldown and any strict lock are skipped.")
+	return true
+}
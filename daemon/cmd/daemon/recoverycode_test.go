@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
+)
+
+// TestRecoveryCode: the code is shown once at install, a reinstall carries
+// the record over without a new code, and a use spends it and is reported.
+func TestRecoveryCode(t *testing.T) {
+	inbox := &partnerInbox{}
+	srv := httptest.NewServer(inbox)
+	defer srv.Close()
+	st := &core.Store{Dir: t.TempDir()}
+	if err := st.WriteWebhooks([]string{"json=" + srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	issueRecoveryCode(st, core.Recovery{}, &out)
+	code := regexp.MustCompile(`[0-9A-Z]{5}(-[0-9A-Z]{5}){3}`).FindString(out.String())
+	if code == "" || st.Recovery().Hash == "" || strings.Contains(st.Recovery().Hash, code) {
+		t.Fatalf("code %q, record %+v\n%s", code, st.Recovery(), out.String())
+	}
+
+	re := &core.Store{Dir: t.TempDir()}
+	out.Reset()
+	issueRecoveryCode(re, st.Recovery(), &out)
+	if out.Len() != 0 || re.Recovery() != st.Recovery() {
+		t.Fatalf("a reinstall must carry the record over silently: %+v\n%s", re.Recovery(), out.String())
+	}
+
+	if redeemRecoveryCode(st, strings.NewReader("AAAAA-AAAAA-AAAAA-AAAAA\n"), &out) || st.Recovery().Hash == "" {
+		t.Fatal("a wrong code must neither pass nor spend the code")
+	}
+	if !redeemRecoveryCode(st, strings.NewReader(strings.ToLower(code)+"\n"), &out) {
+		t.Fatalf("the right code was refused\n%s", out.String())
+	}
+	if r := st.Recovery(); r.Hash != "" || r.Used.IsZero() {
+		t.Fatalf("a used code must be spent: %+v", r)
+	}
+	if inbox.last().Kind != notify.RecoveryUsed {
+		t.Fatalf("use not reported: %+v", inbox.last())
+	}
+	out.Reset()
+	if redeemRecoveryCode(st, strings.NewReader(code+"\n"), &out) || !strings.Contains(out.String(), "already used") {
+		t.Fatalf("a spent code passed again\n%s", out.String())
+	}
+
+	// A spent code is not refilled by a reinstall either.
+	out.Reset()
+	again := &core.Store{Dir: t.TempDir()}
+	issueRecoveryCode(again, st.Recovery(), &out)
+	if out.Len() != 0 || again.Recovery().Hash != "" {
+		t.Fatal("a reinstall issued a new code after the first was spent")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+// A release can be staged: a line "Rollout: N%" in its notes means only N% of
+// installs take it when `daemon update` resolves on a channel, so a bad
+// release surfaces on a few machines before it reaches every one. The
+// maintainer raises N (or drops the line) as confidence grows. Which installs
+// are in is fixed per install and release — see rolloutBucket. An explicit
+// `daemon update vX.Y.Z` is never held back; nor is the reconcile loop, which
+// never resolves on its own.
+
+// rolloutPercent returns the staged-rollout percentage a release body
+// declares; ok is false when it declares none (a full release). A malformed
+// value counts as none — the marker can only hold an update back, so a typo
+// fails open to today's behavior.
+func rolloutPercent(notes string) (pct int, ok bool) {
+	sc := bufio.NewScanner(strings.NewReader(notes))
+	for sc.Scan() {
+		k, v, found := strings.Cut(strings.TrimSpace(sc.Text()), ":")
+		if !found || !strings.EqualFold(strings.TrimSpace(k), "rollout") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "%")))
+		if err != nil || n < 0 || n > 100 {
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+// rolloutBucket places this install at 0..99 for tag. It hashes the install
+// salt with the tag, so it is stable across runs of one release (raising N
+// only ever adds installs) but a different slice goes first each release.
+func rolloutBucket(salt, tag string) int {
+	sum := sha256.Sum256([]byte("rollout\x00" + salt + "\x00" + tag))
+	return int(binary.BigEndian.Uint64(sum[:8]) % 100)
+}
+
+// heldBack reports whether the rollout notes declares for tag leaves this
+// install out, and the percentage (-1 when the release is not staged).
+func heldBack(notes, salt, tag string) (bool, int) {
+	pct, ok := rolloutPercent(notes)
+	if !ok {
+		return false, -1
+	}
+	return rolloutBucket(salt, tag) >= pct, pct
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRolloutPercent(t *testing.T) {
+	cases := []struct {
+		notes  string
+		pct    int
+		staged bool
+	}{
+		{"## Changes\n- faster\n", 0, false},
+		{"Rollout: 25%\n- faster", 25, true},
+		{"- faster\n  rollout:10 %\n", 10, true},
+		{"ROLLOUT: 0%", 0, true},
+		{"Rollout: 100", 100, true},
+		{"Rollout: 150%", 0, false}, // malformed fails open
+		{"Rollout: soon", 0, false},
+		{"- rollout of the new UI: 10%", 0, false}, // only a line that is the marker
+	}
+	for _, c := range cases {
+		pct, staged := rolloutPercent(c.notes)
+		if pct != c.pct || staged != c.staged {
+			t.Errorf("rolloutPercent(%q) = %d, %v; want %d, %v", c.notes, pct, staged, c.pct, c.staged)
+		}
+	}
+}
+
+// TestRolloutBucket: one install's place is stable for a release, so a
+// re-run never flips it; across installs the buckets spread, so N% takes
+// roughly N% of machines.
+func TestRolloutBucket(t *testing.T) {
+	if rolloutBucket("salt", "v1.3.0") != rolloutBucket("salt", "v1.3.0") {
+		t.Fatal("bucket not stable")
+	}
+	in := 0
+	for i := 0; i < 1000; i++ {
+		b := rolloutBucket(fmt.Sprintf("install-%d", i), "v1.3.0")
+		if b < 0 || b > 99 {
+			t.Fatalf("bucket %d out of range", b)
+		}
+		if held, _ := heldBack("Rollout: 20%", fmt.Sprintf("install-%d", i), "v1.3.0"); !held {
+			in++
+		}
+	}
+	if in < 120 || in > 280 {
+		t.Fatalf("20%% rollout took %d of 1000 installs", in)
+	}
+	if held, pct := heldBack("- no marker", "salt", "v1.3.0"); held || pct != -1 {
+		t.Fatalf("unstaged release: held %v pct %d", held, pct)
+	}
+	if held, _ := heldBack("Rollout: 0%", "salt", "v1.3.0"); !held {
+		t.Fatal("0% rollout let an install in")
+	}
+}
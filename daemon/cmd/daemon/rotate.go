@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+	"github.com/eliteGoblin/focusd/daemon/internal/sig"
+)
+
+// doRotateIdentity is `daemon rotate-identity`: move the running install to a
+// fresh disguise — a new random binary name (the daemon's process name) and
+// three new launchd labels — for when the current one has been seen, say in
+// a screenshot.
+//
+//	daemon rotate-identity [--dry-run] [--keep-old] [--healthy-timeout 15s] [--probe-interval 1s]
+//
+// It is self-update without the download: the installed binary's own bytes,
+// re-verified, go through the same swap (rotatedSpec, SelfUpdateProd). The
+// new mesh is bootstrapped and health-polled before the old one is booted
+// out, the masked roster is rewritten for the new labels, and a failed
+// health poll leaves the old mesh running. Nothing new is printed: printing
+// the new names would undo the point.
+//
+// The platform child's disguised name is derived from the install salt,
+// which also keys the state masks, so it is not rotated here.
+//
+// With identity.rotate-every set (off by default; see tendRotation) the
+// mesh also runs it on its own, so a long-lived install does not keep one
+// set of names for good.
+func doRotateIdentity(args []string) int {
+	if !osSupportsLaunchd() {
+		fmt.Fprintln(os.Stderr, "rotate-identity: unsupported on", runtime.GOOS, "(darwin/launchd only)")
+		return 1
+	}
+	fs := flag.NewFlagSet("rotate-identity", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "find and verify the install, print the plan; change nothing")
+	keepOld := fs.Bool("keep-old", false, "leave the old plists and binary on disk after success")
+	ht := fs.Duration("healthy-timeout", 15*time.Second, "post-bootstrap health poll window")
+	pi := fs.Duration("probe-interval", 1*time.Second, "health poll cadence")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	m := mode.Resolve()
+	cur, err := osadapter.FindCurrentInstall(m, sig.VerifyFile)
+	if err != nil || cur.BinaryPath == "" || cur.Workdir == "" {
+		fmt.Fprintf(os.Stderr, "rotate-identity: no %s-mode install found (%s for the other mode)\n", m, sudoHintFor(m))
+		return 1
+	}
+	// Re-verify the bytes actually read: the discovery verified the file,
+	// not what a later read returns.
+	bin, err := os.ReadFile(cur.BinaryPath)
+	if ok, verr := sig.VerifyBytes(bin); err != nil || verr != nil || !ok {
+		fmt.Fprintln(os.Stderr, "rotate-identity: installed binary does not verify; run `daemon verify`")
+		return 1
+	}
+	newSpec, ok := rotatedSpec(m, cur, cur.Workdir, defaultGithubRepo)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "rotate-identity: rotated path matches current (try again)")
+		return 1
+	}
+	if *dryRun {
+		fmt.Println("rotate-identity --dry-run")
+		fmt.Println("  current mesh  ", len(cur.Labels), "labels (redacted)")
+		fmt.Println("  new identity   binary name and", len(osadapter.AllRoles), "labels (generated, redacted)")
+		fmt.Println("  workdir        unchanged")
+		fmt.Println("  --keep-old    ", *keepOld)
+		fmt.Println("  then: place binary, bootstrap new mesh, health-poll, boot out old mesh")
+		return 0
+	}
+	if err := osadapter.SelfUpdateProd(cur, newSpec, bin, *ht, *pi, *keepOld); err != nil {
+		fmt.Fprintf(os.Stderr, "rotate-identity: swap failed (%T); the old identity stays in place\n", err)
+		return 1
+	}
+	if err := (&core.Store{Dir: cur.Workdir}).WriteRotatedAt(time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "rotate-identity: rotation not recorded (%T); the schedule counts from the last one\n", err)
+	}
+	fmt.Printf("rotate-identity ok: new binary name and %d new launchd labels (not shown)\n", len(osadapter.AllRoles))
+	return 0
+}
+
+// tendRotation is the lock holder's per-tick check on the automatic
+// identity rotation: once identity.rotate-every has passed since the last
+// rotation it records the attempt and starts `rotate-identity` as a
+// detached child, which swaps this very mesh out from under the caller. A
+// schedule never recorded starts now rather than rotating a fresh install.
+// The attempt is recorded first, so a rotation that fails waits out a whole
+// period instead of being retried every tick.
+func tendRotation(st *core.Store, now time.Time, spawn func() error, log *slog.Logger) {
+	every := st.RotateEvery()
+	if every <= 0 {
+		return
+	}
+	last := st.RotatedAt()
+	if !last.IsZero() && now.Sub(last) < every {
+		return
+	}
+	if err := st.WriteRotatedAt(now); err != nil {
+		log.Warn("record rotation", "err", fmt.Sprintf("%T", err))
+		return
+	}
+	if last.IsZero() {
+		return
+	}
+	if err := spawn(); err != nil {
+		log.Warn("scheduled rotation not started", "err", fmt.Sprintf("%T", err))
+		return
+	}
+	log.Info("scheduled identity rotation started", "every", every.String())
+}
+
+// spawnRotation starts self as a detached `rotate-identity`, the verb
+// carried in the environment rather than on its argv, its output appended
+// to the daemon log.
+func spawnRotation(self, workdir string, logMode os.FileMode) func() error {
+	return func() error {
+		cmd := exec.Command(self)
+		cmd.Env = append(os.Environ(), osadapter.RotateEnv())
+		f, err := os.OpenFile(osadapter.LogPath(workdir), os.O_CREATE|os.O_WRONLY|os.O_APPEND, logMode)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cmd.Stdout, cmd.Stderr = f, f
+		detach(cmd)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestTendRotation(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	spawned := 0
+	spawn := func() error { spawned++; return nil }
+	now := time.Now()
+
+	tendRotation(st, now, spawn, log)
+	if spawned != 0 || !st.RotatedAt().IsZero() {
+		t.Fatal("rotation must be off until identity.rotate-every is set")
+	}
+	const every = 7 * 24 * time.Hour
+	if err := st.WriteRotateEvery(every); err != nil {
+		t.Fatal(err)
+	}
+
+	tendRotation(st, now, spawn, log)
+	if spawned != 0 || st.RotatedAt().IsZero() {
+		t.Fatalf("a fresh install must start the schedule, not rotate: spawned %d, at %v", spawned, st.RotatedAt())
+	}
+
+	tendRotation(st, now.Add(every-time.Hour), spawn, log)
+	if spawned != 0 {
+		t.Fatal("rotated before the period passed")
+	}
+
+	due := now.Add(every + time.Minute)
+	tendRotation(st, due, spawn, log)
+	if spawned != 1 {
+		t.Fatalf("due rotation spawned %d times", spawned)
+	}
+
+	// A failed spawn still waits out the next period.
+	fail := func() error { spawned++; return errors.New("boom") }
+	later := due.Add(every + time.Minute)
+	tendRotation(st, later, fail, log)
+	tendRotation(st, later.Add(time.Hour), fail, log)
+	if spawned != 2 {
+		t.Fatalf("failed rotation retried: spawned %d", spawned)
+	}
+
+	if err := st.WriteRotateEvery(0); err != nil {
+		t.Fatal(err)
+	}
+	tendRotation(st, later.Add(30*every), spawn, log)
+	if spawned != 2 {
+		t.Fatal("rotate-every 0 must turn the schedule off")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/codesign"
 	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/fetch"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
@@ -173,56 +174,32 @@ func runSelfUpdate(o selfUpdateOpts) int {
 	if o.releaseDir != "" {
 		f = &fetch.Local{Dir: o.releaseDir}
 	} else {
-		f = &fetch.GitHub{Repo: o.github, Asset: asset}
+		f = githubFetcher(o.github, asset, &core.Store{Dir: workdir})
 	}
 	ctx := context.Background()
 	if err := f.DownloadVerified(ctx, o.tag, asset, tmpDL); err != nil {
 		fmt.Fprintln(os.Stderr, "self-update: download:", err)
 		return 1
 	}
+	// The release key is the gate; the Apple signature is a ratchet on top:
+	// a team-signed install only moves to the same team, still notarized.
+	if err := codesign.Keep(cur.BinaryPath, tmpDL); err != nil {
+		fmt.Fprintln(os.Stderr, "self-update: release is not code-signed like the installed daemon; refusing")
+		return 1
+	}
 	newBin, rerr := os.ReadFile(tmpDL)
 	if rerr != nil {
 		fmt.Fprintln(os.Stderr, "self-update: read verified bytes:", rerr)
 		return 1
 	}
 
-	// Build the new Spec: rotated SelfPath + a NEW independent-label
-	// roster; same workdir. Reusing osadapter.Spec.Label downstream, the
-	// new roster (FEATURE 10 / ADR-0014: distinct vendor families, no
-	// shared base, no role token) is the disguised label set that lets the
-	// old and new meshes coexist during the swap.
-	newPath := filepath.Join(workdir, relocate.RandomBinaryName())
-	if newPath == cur.BinaryPath {
+	newSpec, ok := rotatedSpec(invokeMode, cur, workdir, o.github)
+	if !ok {
 		// Defensive: 4-hex collision is astronomical but explicit
 		// safety is cheaper than a silent AMFI block.
 		fmt.Fprintln(os.Stderr, "self-update: rotated path matches current (try again)")
 		return 1
 	}
-	newRoster := relocate.GenerateRoster()
-	// FEATURE 10 / ADR-0014: the worker heal cadence is a ~2s SECURITY
-	// constant (it closes the manual-removal whack-a-mole loophole), NOT an
-	// operator preference. FORCE it on every self-update so (a) migrating an
-	// OLD pre-F10 mesh that ran at 10s upgrades to the fast heal, and (b) a
-	// tuned/stale --interval can't carry a slow cadence forward and reopen
-	// the gap. (Supersedes the earlier "preserve operator interval" note —
-	// that predated the security framing.)
-	interval := workerHealInterval
-	newSpec := osadapter.Spec{
-		Mode:     invokeMode,
-		SelfPath: newPath,
-		Workdir:  workdir,
-		Github:   o.github,
-		// The PLATFORM asset is derived (platform-{os}-{arch}), NOT the
-		// daemon asset `asset` used above to download the daemon binary.
-		// Baking the daemon asset here was the self-heal bug: the rebuilt
-		// mesh fetched a non-existent platform asset → 404 → no recovery.
-		Asset:    platformAsset(),
-		Interval: interval,
-		// Keep the ensurer's launchd StartInterval the slower backstop,
-		// decoupled from the fast worker --interval (FEATURE 10 / ADR-0014).
-		EnsureInterval: osadapter.EnsureBackstopInterval,
-		Roster:         newRoster,
-	}
 
 	if o.dryRun {
 		printDryRun(cur, newSpec, o)
@@ -245,10 +222,46 @@ func runSelfUpdate(o selfUpdateOpts) int {
 	}
 	// Do NOT print the disguised roster labels — they are exactly the
 	// strings a targeted bootout needs (FEATURE 10 honest-limitations).
-	fmt.Printf("self-update ok: %s → %s\n", cur.BinaryPath, newPath)
+	fmt.Printf("self-update ok: %s → %s\n", cur.BinaryPath, newSpec.SelfPath)
 	return 0
 }
 
+// rotatedSpec is the Spec of the generation that replaces cur: a fresh
+// random binary path and a NEW independent-label roster in the same
+// workdir. The new roster (FEATURE 10 / ADR-0014: distinct vendor families,
+// no shared base, no role token) is the disguised label set that lets the
+// old and new meshes coexist during the swap. ok is false on the
+// (astronomical) path collision with the current binary.
+func rotatedSpec(m mode.Mode, cur osadapter.CurInstall, workdir, github string) (osadapter.Spec, bool) {
+	newPath := filepath.Join(workdir, relocate.RandomBinaryName())
+	if newPath == cur.BinaryPath {
+		return osadapter.Spec{}, false
+	}
+	// FEATURE 10 / ADR-0014: the worker heal cadence is a ~2s SECURITY
+	// constant (it closes the manual-removal whack-a-mole loophole), NOT an
+	// operator preference. FORCE it on every rotation so (a) migrating an
+	// OLD pre-F10 mesh that ran at 10s upgrades to the fast heal, and (b) a
+	// tuned/stale --interval can't carry a slow cadence forward and reopen
+	// the gap. (Supersedes the earlier "preserve operator interval" note —
+	// that predated the security framing.)
+	return osadapter.Spec{
+		Mode:     m,
+		SelfPath: newPath,
+		Workdir:  workdir,
+		Github:   github,
+		// The PLATFORM asset is derived (platform-{os}-{arch}), NOT the
+		// daemon asset self-update downloads the daemon binary with.
+		// Baking the daemon asset here was the self-heal bug: the rebuilt
+		// mesh fetched a non-existent platform asset → 404 → no recovery.
+		Asset:    platformAsset(),
+		Interval: workerHealInterval,
+		// Keep the ensurer's launchd StartInterval the slower backstop,
+		// decoupled from the fast worker --interval (FEATURE 10 / ADR-0014).
+		EnsureInterval: osadapter.EnsureBackstopInterval,
+		Roster:         relocate.GenerateRoster(),
+	}, true
+}
+
 // printDryRun emits the intended operations for the operator to
 // review without performing any launchctl or filesystem mutations.
 func printDryRun(cur osadapter.CurInstall, newSpec osadapter.Spec, o selfUpdateOpts) {
@@ -4,10 +4,13 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
 )
 
@@ -290,3 +293,28 @@ func captureStdout(t *testing.T) (read func() string, restore func()) {
 	restore = func() { os.Stdout = orig }
 	return
 }
+
+// TestRotatedSpec: a rotation keeps the workdir and forces the fast heal
+// cadence, but the binary path and every label are new.
+func TestRotatedSpec(t *testing.T) {
+	wd := t.TempDir()
+	cur := osadapter.CurInstall{
+		BinaryPath: filepath.Join(wd, "old"),
+		Roster:     []string{"com.example.a.1", "com.example.b.2", "com.example.c.3"},
+	}
+	spec, ok := rotatedSpec(mode.User, cur, wd, defaultGithubRepo)
+	if !ok {
+		t.Fatal("rotation refused")
+	}
+	if spec.Workdir != wd || filepath.Dir(spec.SelfPath) != wd || spec.SelfPath == cur.BinaryPath {
+		t.Fatalf("binary not rotated within the workdir: %+v", spec)
+	}
+	if spec.Interval != workerHealInterval || len(spec.Roster) != len(osadapter.AllRoles) {
+		t.Fatalf("interval %s, roster %d", spec.Interval, len(spec.Roster))
+	}
+	for _, l := range spec.Roster {
+		if slices.Contains(cur.Roster, l) {
+			t.Fatalf("label %q carried over", l)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/codesign"
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+	"github.com/eliteGoblin/focusd/daemon/internal/platformsvc"
+	"github.com/eliteGoblin/focusd/daemon/internal/sig"
+)
+
+// doSelfTest is `daemon self-test` (also `daemon --self-test`): the checks a
+// mesh worker runs at boot, on demand.
+//
+//	daemon self-test [--json] [--quiet] [--workdir D] [run flags]
+//
+// It takes the same flags as `daemon run` and reports whether the
+// daemon-home state can be read and written, the signing keys load, each
+// launchd plist renders to well-formed XML, the running binary carries a
+// valid release signature (mesh workers and real installs), the companion's
+// offline backup verifies (darwin, real installs), and the daemon log
+// accepts writes. The result, with the binary's Apple code signature, is
+// recorded for `daemon status`. Check names are fixed nouns.
+//
+// Exit codes (exitcode.go): 0 every check passed · 2 a check failed.
+func doSelfTest(args []string) int {
+	asJSON, quiet := false, false
+	args = slices.DeleteFunc(slices.Clone(args), func(a string) bool {
+		switch a {
+		case "--json", "-json":
+			asJSON = true
+		case "--quiet", "-quiet":
+			quiet = true
+		default:
+			return false
+		}
+		return true
+	})
+	o := parse("self-test", args)
+	self, _ := os.Executable()
+	checks := selfTest(selfTestInputFor(o, o.spec(self)))
+	recordSelfTest(&core.Store{Dir: o.workdir}, checks, time.Now(), nil)
+	var out io.Writer = os.Stdout
+	if quiet {
+		out = io.Discard
+	}
+	return reportVerify(checks, asJSON, out)
+}
+
+// selfTestInput is what selfTest probes. A nil backup or an empty logPath
+// or self leaves that check out.
+type selfTestInput struct {
+	store   *core.Store
+	spec    osadapter.Spec
+	pubKey  func() (ed25519.PublicKey, error)
+	backup  func() osadapter.BackupHealth
+	logPath string
+	// self is the running binary; verify checks its release trailer and
+	// codesign reads its Apple signature.
+	self     string
+	verify   func(path string) (bool, error)
+	codesign func(path string) (codesign.Info, error)
+}
+
+// selfTestInputFor wires the real probes for o. The backup check needs a
+// companion, which only a darwin non-test install has; the log sink is
+// checked when launchd redirects into it (a mesh worker) or it already
+// exists. The own-binary check is left out of a by-hand test-mode run,
+// which is usually an unsigned `go build`.
+func selfTestInputFor(o opts, spec osadapter.Spec) selfTestInput {
+	in := selfTestInput{store: &core.Store{Dir: o.workdir}, spec: spec, pubKey: sig.PublicKey}
+	if runtime.GOOS == "darwin" && spec.Mode != mode.Test {
+		in.backup = func() osadapter.BackupHealth { return osadapter.CompanionBackupHealth(spec.Mode) }
+	}
+	if o.mesh || spec.Mode != mode.Test {
+		in.self, in.verify, in.codesign = spec.SelfPath, sig.VerifyFile, codesign.Of
+	}
+	logPath := osadapter.LogPath(o.workdir)
+	if _, err := os.Stat(logPath); o.mesh || err == nil {
+		in.logPath = logPath
+	}
+	return in
+}
+
+// selfTest runs every check in a fixed order and never stops early, so one
+// broken piece does not hide another.
+func selfTest(in selfTestInput) []osadapter.Check {
+	checks := []osadapter.Check{
+		selfCheck("daemon state", stateProbe(in.store)),
+		selfCheck("signing keys", keyProbe(in.store, in.pubKey)),
+		selfCheck("launchd templates", templateProbe(in.spec)),
+	}
+	if in.self != "" {
+		checks = append(checks, signatureCheck(in))
+	}
+	if in.backup != nil {
+		checks = append(checks, selfCheck("offline backup", backupProbe(in.backup())))
+	}
+	if in.logPath != "" {
+		checks = append(checks, selfCheck("log sink", logProbe(in.logPath, in.store.LogMode())))
+	}
+	return checks
+}
+
+func selfCheck(name string, err error) osadapter.Check {
+	if err != nil {
+		return osadapter.Check{Name: name, Note: err.Error()}
+	}
+	return osadapter.Check{Name: name, OK: true}
+}
+
+// signatureCheckName is the own-binary check; when it passes its Note
+// carries the Apple code-signature summary, which recordSelfTest keeps.
+const signatureCheckName = "code signature"
+
+// signatureCheck requires the running binary's release trailer to verify
+// and, where the binary is team-signed, its Apple seal to validate. Ad-hoc
+// and unsigned binaries pass: the release key is the trust root, the Apple
+// signature is recorded for the update ratchet (codesign.Keep).
+func signatureCheck(in selfTestInput) osadapter.Check {
+	if ok, err := in.verify(in.self); err != nil || !ok {
+		return osadapter.Check{Name: signatureCheckName, Note: "release signature does not verify"}
+	}
+	info, err := in.codesign(in.self)
+	if err != nil {
+		return osadapter.Check{Name: signatureCheckName, OK: true} // no Apple signature off macOS
+	}
+	if info.TeamID != "" && !info.Intact {
+		return osadapter.Check{Name: signatureCheckName, Note: "Apple code signature does not validate"}
+	}
+	return osadapter.Check{Name: signatureCheckName, OK: true, Note: info.String()}
+}
+
+// The probes return errors with fixed, path-free messages: they are printed,
+// logged and recorded verbatim.
+
+func stateProbe(st *core.Store) error {
+	if err := os.MkdirAll(st.Dir, 0o755); err != nil {
+		return errors.New("daemon-home cannot be created")
+	}
+	f, err := os.CreateTemp(st.Dir, ".probe-*")
+	if err != nil {
+		return errors.New("daemon-home is not writable")
+	}
+	f.Close()
+	os.Remove(f.Name())
+	if st.HaveConfig() && !st.VersionStateOK() {
+		return errors.New("version state does not open with the install key")
+	}
+	return nil
+}
+
+func keyProbe(st *core.Store, pubKey func() (ed25519.PublicKey, error)) error {
+	if _, err := pubKey(); err != nil {
+		return errors.New("release signing key does not load")
+	}
+	if st.HeartbeatEndpoint() != "" && st.HeartbeatSeed() == nil {
+		return errors.New("heartbeat signing key is garbled")
+	}
+	return nil
+}
+
+func templateProbe(spec osadapter.Spec) error {
+	for i, r := range osadapter.AllRoles {
+		d := xml.NewDecoder(strings.NewReader(osadapter.Plist(spec, r)))
+		d.Strict = true
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("plist %d of %d is not well-formed XML", i+1, len(osadapter.AllRoles))
+			}
+		}
+	}
+	return nil
+}
+
+func backupProbe(h osadapter.BackupHealth) error {
+	switch {
+	case !h.Present:
+		return errors.New("missing")
+	case !h.Verified:
+		return errors.New("does not verify")
+	}
+	return nil
+}
+
+func logProbe(path string, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, perm)
+	if err != nil {
+		return errors.New("daemon log is not writable")
+	}
+	return f.Close()
+}
+
+// hardenLog narrows the daemon log launchd redirects into, and the
+// platform log, to the store's log.mode. launchd creates its file with the
+// job's umask, usually 0644, and reopens it as it is; a worker fixes the
+// bits at boot and on SIGHUP. An empty platWD is the single-root layout.
+func hardenLog(workdir, platWD string, log *slog.Logger) {
+	if platWD == "" {
+		platWD = workdir
+	}
+	perm := (&core.Store{Dir: workdir}).LogMode()
+	for _, p := range []string{osadapter.LogPath(workdir), filepath.Join(platWD, platformsvc.PlatformLogName)} {
+		if err := os.Chmod(p, perm); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("log mode", "err", fmt.Sprintf("%T", err))
+		}
+	}
+}
+
+// recordSelfTest persists the failed check names and the code-signature
+// summary for `daemon status` and, given a logger, logs each failure (or one
+// line when all passed). A failed self-test never stops the daemon: a partly
+// broken daemon still protects.
+func recordSelfTest(st *core.Store, checks []osadapter.Check, now time.Time, log *slog.Logger) {
+	var failed []string
+	var signature string
+	for _, c := range checks {
+		if c.OK && c.Name == signatureCheckName {
+			signature = c.Note
+		}
+		if !c.OK {
+			failed = append(failed, c.Name)
+			if log != nil {
+				log.Error("self-test failed", "check", c.Name, "why", c.Note)
+			}
+		}
+	}
+	if log != nil && len(failed) == 0 {
+		log.Info("self-test passed", "checks", len(checks), "signature", signature)
+	}
+	if err := st.RecordSelfTest(core.SelfTest{At: now, Failed: failed, Signature: signature}); err != nil && log != nil {
+		log.Warn("record self-test", "err", fmt.Sprintf("%T", err))
+	}
+}
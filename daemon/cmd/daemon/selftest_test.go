@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/codesign"
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+func failedNames(checks []osadapter.Check) []string {
+	var out []string
+	for _, c := range checks {
+		if !c.OK {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
+
+func TestSelfTest(t *testing.T) {
+	home := t.TempDir()
+	st := &core.Store{Dir: home}
+	in := selfTestInput{
+		store:   st,
+		spec:    osadapter.Spec{Mode: mode.Test, SelfPath: filepath.Join(home, "daemon"), Workdir: home},
+		pubKey:  func() (ed25519.PublicKey, error) { return make(ed25519.PublicKey, ed25519.PublicKeySize), nil },
+		backup:  func() osadapter.BackupHealth { return osadapter.BackupHealth{Present: true, Verified: true} },
+		logPath: filepath.Join(home, osadapter.DaemonLogName),
+	}
+	checks := selfTest(in)
+	if len(checks) != 5 || failedNames(checks) != nil {
+		t.Fatalf("healthy install: %+v", checks)
+	}
+
+	in.pubKey = func() (ed25519.PublicKey, error) { return nil, errors.New("bad pem") }
+	in.backup = func() osadapter.BackupHealth { return osadapter.BackupHealth{Present: true} }
+	in.logPath = filepath.Join(home, "no-such-dir", osadapter.DaemonLogName)
+	got := failedNames(selfTest(in))
+	if !slices.Equal(got, []string{"signing keys", "offline backup", "log sink"}) {
+		t.Fatalf("failed = %v", got)
+	}
+
+	// A version.json that no longer opens with the install key.
+	if _, err := st.EnsureInstallSalt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, core.VersionFile), []byte(`{"desired":"v1.0.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c := selfTest(in)[0]; c.OK || c.Name != "daemon state" {
+		t.Fatalf("unmasked version state must fail: %+v", c)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(home, ".probe-*")); len(entries) != 0 {
+		t.Fatalf("probe file left behind: %v", entries)
+	}
+}
+
+func TestSelfTestSignature(t *testing.T) {
+	home := t.TempDir()
+	verified := true
+	info := codesign.Info{Signed: true, TeamID: "ABCDE12345", Intact: true, Notarized: true}
+	in := selfTestInput{
+		store:    &core.Store{Dir: home},
+		spec:     osadapter.Spec{Mode: mode.Test, SelfPath: filepath.Join(home, "daemon"), Workdir: home},
+		pubKey:   func() (ed25519.PublicKey, error) { return make(ed25519.PublicKey, ed25519.PublicKeySize), nil },
+		self:     filepath.Join(home, "daemon"),
+		verify:   func(string) (bool, error) { return verified, nil },
+		codesign: func(string) (codesign.Info, error) { return info, nil },
+	}
+	checks := selfTest(in)
+	c := checks[len(checks)-1]
+	if !c.OK || c.Name != signatureCheckName || c.Note != "team ABCDE12345, notarized" {
+		t.Fatalf("signed binary: %+v", c)
+	}
+	recordSelfTest(in.store, checks, time.Now(), nil)
+	if got, _ := in.store.LastSelfTest(); got.Signature != "team ABCDE12345, notarized" {
+		t.Fatalf("signature not recorded: %+v", got)
+	}
+
+	info.Intact = false
+	if got := failedNames(selfTest(in)); !slices.Equal(got, []string{signatureCheckName}) {
+		t.Fatalf("broken seal: failed = %v", got)
+	}
+	info = codesign.Info{Signed: true, Adhoc: true}
+	verified = false
+	if got := failedNames(selfTest(in)); !slices.Equal(got, []string{signatureCheckName}) {
+		t.Fatalf("unverified release trailer: failed = %v", got)
+	}
+	verified = true
+	in.codesign = func(string) (codesign.Info, error) { return codesign.Info{}, errors.New("no codesign") }
+	if c := selfTest(in)[3]; !c.OK || c.Note != "" {
+		t.Fatalf("no Apple signature to read must pass quietly: %+v", c)
+	}
+}
+
+func TestHardenLog(t *testing.T) {
+	home := t.TempDir()
+	logPath := osadapter.LogPath(home)
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	hardenLog(home, "", log)
+	if fi, _ := os.Stat(logPath); fi.Mode().Perm() != core.DefaultLogMode {
+		t.Fatalf("default: mode %v", fi.Mode().Perm())
+	}
+	st := &core.Store{Dir: home}
+	if code := setConfig(st, "log.mode", "0640", func(string) (int, error) { return 0, nil }, io.Discard); code != 0 {
+		t.Fatalf("set log.mode: code %d", code)
+	}
+	hardenLog(home, "", log)
+	if fi, _ := os.Stat(logPath); fi.Mode().Perm() != 0o640 {
+		t.Fatalf("configured: mode %v", fi.Mode().Perm())
+	}
+	hardenLog(home, filepath.Join(home, "gone"), log) // a missing platform log is not an error
+}
+
+func TestRecordSelfTest(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	now := time.Now()
+	recordSelfTest(st, []osadapter.Check{{Name: "daemon state", OK: true}, {Name: "log sink", Note: "daemon log is not writable"}}, now, nil)
+	got, ok := st.LastSelfTest()
+	if !ok || !slices.Equal(got.Failed, []string{"log sink"}) {
+		t.Fatalf("recorded %+v, %v", got, ok)
+	}
+	recordSelfTest(st, []osadapter.Check{{Name: "daemon state", OK: true}}, now, nil)
+	if got, _ := st.LastSelfTest(); got.Failed != nil {
+		t.Fatalf("a passing run must clear the failures: %+v", got)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"flag"
 	"os"
+	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/fetch"
+	"github.com/eliteGoblin/focusd/daemon/internal/heartbeat"
 	"github.com/eliteGoblin/focusd/daemon/internal/status"
 )
 
@@ -15,7 +21,7 @@ import (
 //
 // Surface:
 //
-//	daemon status [--json] [--no-color] [--workdir DIR]
+//	daemon status [--json] [--no-color] [--workdir DIR] [--check-remote] [--quiet]
 //
 // --workdir is an optional override for the discovered (disguised) workdir;
 // normally the install is discovered by Ed25519 signature and the operator
@@ -23,34 +29,117 @@ import (
 // identifiers (no --show-paths / --debug) — that would reopen the leak this
 // command closes (feature 09 non-goals).
 //
-// Exit codes: 0 healthy/unknown · 1 degraded · 2 down · 3 internal error.
+// --check-remote adds one bounded network probe to the restore-chain section:
+// can the release download the install would fall back on actually be reached
+// right now? Off by default so a plain `status` stays offline and fast.
+//
+// A system install's daemon-home is root-only, so run without sudo status
+// falls back to the snapshot the system daemon publishes (publishStatus):
+// the same daemon facts and verdict, marked with their age, without the
+// platform detail.
+//
+// --quiet prints nothing; the exit code carries the verdict.
+//
+// Exit codes (exitcode.go): 0 healthy/unknown · 2 degraded · 3 down ·
+// 64 usage.
 func doStatus(args []string) int {
 	fs := flag.NewFlagSet("status", flag.ContinueOnError)
 	jsonOut := fs.Bool("json", false, "emit machine-readable JSON")
 	noColor := fs.Bool("no-color", false, "suppress ANSI colour")
 	wd := fs.String("workdir", "", "override the discovered workdir (rarely needed)")
+	checkRemote := fs.Bool("check-remote", false, "probe the GitHub release download the restore chain falls back on")
+	quiet := fs.Bool("quiet", false, "print nothing; branch on the exit code")
 	if err := fs.Parse(args); err != nil {
 		// --help/-h is a clean request, not a failure → exit 0. Any genuine
-		// parse error is internal/usage (3), not DOWN (2).
+		// parse error is usage, not DOWN.
 		if errors.Is(err, flag.ErrHelp) {
-			return 0
+			return exitOK
 		}
-		return 3
+		return exitUsage
 	}
 
 	snap, pd := status.Gather(*wd, *jsonOut)
+	snap.BakedFallback = defaultPlatformVersion
+	// A non-root status finding no install of its own may be looking at a
+	// system install it cannot read: show what the system daemon published.
+	var published *status.Result
+	if !snap.Found && *wd == "" && os.Geteuid() != 0 {
+		if ps, pres, ok := status.ReadPublished(time.Now()); ok {
+			snap, pd, published = ps, status.PlatformDetail{}, &pres
+		}
+	}
+	if *checkRemote {
+		snap.RemoteChecked = true
+		snap.RemoteReachable = probeRemote(restoreTag(snap)) == nil
+	}
 
 	// OVERALL folds the daemon's own facts with the delegated platform verdict
 	// (worst-wins). An UNAVAILABLE platform stays a note and never by itself
-	// worsens the daemon verdict (architect's rule).
+	// worsens the daemon verdict (architect's rule). A published snapshot
+	// carries the verdict the system daemon already folded.
 	platformVerdict, platformOK := pd.Verdict()
 	res := status.Combine(status.Assess(snap), platformVerdict, platformOK)
+	if published != nil {
+		res = *published
+	}
 
 	color := !*noColor && os.Getenv("NO_COLOR") == ""
-	if *jsonOut {
+	switch {
+	case *quiet:
+	case *jsonOut:
 		status.RenderJSON(snap, res, pd, os.Stdout)
-	} else {
+	default:
 		status.RenderText(snap, res, pd, os.Stdout, color)
 	}
 	return status.ExitCode(res.Verdict)
 }
+
+// remoteProbeTimeout bounds the --check-remote probe so an offline box or a
+// black-holed proxy can't hang `status`.
+const remoteProbeTimeout = 5 * time.Second
+
+// probeRemote is the --check-remote probe: a HEAD on the release download for
+// tag, using the same repo + per-arch asset the fetcher would.
+func probeRemote(tag string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteProbeTimeout)
+	defer cancel()
+	g := &fetch.GitHub{Repo: defaultGithubRepo}
+	return g.Reachable(ctx, tag, platformAsset())
+}
+
+// restoreTag picks the version a restore would actually download: the pinned
+// desired version, else the companion's restore pin, else the baked floor.
+func restoreTag(s status.Snapshot) string {
+	for _, v := range []string{s.Desired, s.BackupPin} {
+		if isValidVersionTag(v) {
+			return v
+		}
+	}
+	return defaultPlatformVersion
+}
+
+// publishStatus gathers the daemon's own status for the install at st and
+// writes it, signed with the device key, through write (status.Publish at
+// the fixed published path). Run by the lock-holding system worker every
+// status.PublishInterval so a non-root `daemon status` has something to read.
+func publishStatus(st *core.Store, now time.Time,
+	gather func(string, bool) (status.Snapshot, status.PlatformDetail),
+	write func(status.Snapshot, status.Result, time.Time, ed25519.PrivateKey) error) error {
+	seed, err := st.DeviceSeed()
+	if err != nil {
+		return err
+	}
+	key, err := heartbeat.KeyFromSeed(seed)
+	if err != nil {
+		return err
+	}
+	snap, pd := gather(st.Dir, true)
+	snap.BakedFallback = defaultPlatformVersion
+	v, ok := pd.Verdict()
+	return write(snap, status.Combine(status.Assess(snap), v, ok), now, key)
+}
+
+// writePublished is publishStatus's production write.
+func writePublished(s status.Snapshot, res status.Result, at time.Time, key ed25519.PrivateKey) error {
+	return status.Publish(status.PublishedPath(), s, res, at, key)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/status"
+)
+
+// TestRestoreTag verifies --check-remote probes the version a restore would
+// really download: the pinned desired version first, then the companion's
+// restore pin, then the baked floor — skipping anything that is not a tag.
+func TestRestoreTag(t *testing.T) {
+	cases := []struct {
+		name string
+		snap status.Snapshot
+		want string
+	}{
+		{"desired wins", status.Snapshot{Desired: "v1.2.3", BackupPin: "v1.0.0"}, "v1.2.3"},
+		{"pin when no desired", status.Snapshot{BackupPin: "v1.0.0"}, "v1.0.0"},
+		{"garbage desired skipped", status.Snapshot{Desired: "latest", BackupPin: "v1.0.0"}, "v1.0.0"},
+		{"baked floor last", status.Snapshot{}, defaultPlatformVersion},
+	}
+	for _, c := range cases {
+		if got := restoreTag(c.snap); got != c.want {
+			t.Errorf("%s: restoreTag = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// TestPublishStatus verifies the system worker publishes its own gathered
+// facts with the folded verdict, signed with the device key, and that the
+// result decodes as a published snapshot.
+func TestPublishStatus(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var gotDir string
+	gather := func(wd string, _ bool) (status.Snapshot, status.PlatformDetail) {
+		gotDir = wd
+		return status.Snapshot{Mode: "system", Found: true, MeshLoaded: 3, MeshTotal: 3, ProcCount: 1,
+			Desired: "v1.2.0", Good: "v1.2.0", GenerationsUnknown: true}, status.PlatformDetail{Available: true, ExitCode: 1}
+	}
+	var written []byte
+	at := time.Now()
+	write := func(s status.Snapshot, res status.Result, at time.Time, key ed25519.PrivateKey) error {
+		var err error
+		written, err = status.EncodePublished(s, res, at, key)
+		return err
+	}
+	if err := publishStatus(st, at, gather, write); err != nil {
+		t.Fatal(err)
+	}
+	if gotDir != st.Dir {
+		t.Fatal("gathered a different install than the store's")
+	}
+	s, res, err := status.DecodePublished(written, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Published || s.BakedFallback != defaultPlatformVersion {
+		t.Fatalf("published=%v baked=%q", s.Published, s.BakedFallback)
+	}
+	if res.Verdict != status.Degraded {
+		t.Fatalf("verdict %s, want the degraded platform folded in", res.Verdict)
+	}
+	seed, _ := st.DeviceSeed()
+	if err := publishStatus(st, at, gather, write); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := st.DeviceSeed(); !bytes.Equal(again, seed) {
+		t.Fatal("publishing rotated the device key")
+	}
+}
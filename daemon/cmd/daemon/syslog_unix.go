@@ -0,0 +1,18 @@
+//go:build !windows
+
+package main
+
+import (
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+)
+
+// openSyslog dials the system log under the process's own (disguised)
+// name: `log stream --predicate 'process == "NAME"'` filters to it. There
+// is no named subsystem: that takes os_log's C API, which the cgo-free
+// builds do without, and a fixed subsystem would name the product.
+func openSyslog() (io.Writer, error) {
+	return syslog.New(syslog.LOG_NOTICE|syslog.LOG_DAEMON, filepath.Base(os.Args[0]))
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"errors"
+	"io"
+)
+
+// openSyslog: Windows has no syslog; the log files stay the only sink.
+func openSyslog() (io.Writer, error) {
+	return nil, errors.New("no system log on windows")
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
 )
 
@@ -24,9 +25,9 @@ func withEmptyStdin(t *testing.T, fn func()) {
 }
 
 func TestRunUninstallGate_FreshDoesNotProceed(t *testing.T) {
-	gpath := filepath.Join(t.TempDir(), "gate")
+	gs := &core.Store{Dir: t.TempDir()}
 	withEmptyStdin(t, func() {
-		code, proceed := runUninstallGate(gpath)
+		code, proceed := runUninstallGate(gs)
 		if proceed {
 			t.Fatal("a fresh gate must never proceed to teardown")
 		}
@@ -35,36 +36,63 @@ func TestRunUninstallGate_FreshDoesNotProceed(t *testing.T) {
 		}
 	})
 	// No progress recorded (rejected input must not advance).
-	if uninstallgate.Load(gpath, time.Now()).Step != 0 {
+	if gs.UninstallGate(time.Now()).Step != 0 {
 		t.Fatal("rejected transcription must not advance the gate")
 	}
 }
 
 func TestRunUninstallGate_WaitDoesNotProceed(t *testing.T) {
-	gpath := filepath.Join(t.TempDir(), "gate")
+	gs := &core.Store{Dir: t.TempDir()}
 	now := time.Now()
 	// Step 1 done just now → inside the 2h cool-off.
-	if err := uninstallgate.Save(gpath, uninstallgate.State{Step: 1, T1: now, LastSeen: now}); err != nil {
+	if err := gs.WriteUninstallGate(uninstallgate.State{Step: 1, T1: now, LastSeen: now}); err != nil {
 		t.Fatal(err)
 	}
-	code, proceed := runUninstallGate(gpath)
+	code, proceed := runUninstallGate(gs)
 	if proceed || code != 1 {
 		t.Fatalf("during cool-off must wait (1,false), got (%d,%v)", code, proceed)
 	}
 }
 
 func TestRunUninstallGate_CompleteProceeds(t *testing.T) {
-	gpath := filepath.Join(t.TempDir(), "gate")
+	gs := &core.Store{Dir: t.TempDir()}
 	past := time.Now().Add(-time.Hour)
 	// A genuinely completed state has both step timestamps set (Evaluate
 	// rejects "Step done without timestamp" as a crafted bypass).
-	if err := uninstallgate.Save(gpath, uninstallgate.State{
+	if err := gs.WriteUninstallGate(uninstallgate.State{
 		Step: uninstallgate.TotalSteps, T1: past, T2: past, LastSeen: past,
 	}); err != nil {
 		t.Fatal(err)
 	}
-	code, proceed := runUninstallGate(gpath)
+	code, proceed := runUninstallGate(gs)
 	if !proceed || code != 0 {
 		t.Fatalf("completed gate must proceed (0,true), got (%d,%v)", code, proceed)
 	}
 }
+
+// TestAdoptGateFile: progress an older install kept in the standalone file
+// moves into the store once, and the file goes.
+func TestAdoptGateFile(t *testing.T) {
+	gs := &core.Store{Dir: t.TempDir()}
+	gpath := filepath.Join(t.TempDir(), "gate")
+	now := time.Now()
+	if err := uninstallgate.Save(gpath, uninstallgate.State{Step: 1, T1: now, LastSeen: now}); err != nil {
+		t.Fatal(err)
+	}
+	adoptGateFile(gs, gpath)
+	if g := gs.UninstallGate(time.Now()); g.Step != 1 || !g.T1.Equal(now.Truncate(0)) && g.T1.Unix() != now.Unix() {
+		t.Fatalf("adopted %+v", g)
+	}
+	if _, err := os.Stat(gpath); !os.IsNotExist(err) {
+		t.Fatal("the old gate file must be removed once adopted")
+	}
+
+	// A stale file never overrides progress already in the store.
+	if err := uninstallgate.Save(gpath, uninstallgate.State{Step: 2, T1: now, T2: now, LastSeen: now}); err != nil {
+		t.Fatal(err)
+	}
+	adoptGateFile(gs, gpath)
+	if g := gs.UninstallGate(time.Now()); g.Step != 1 {
+		t.Fatalf("store progress replaced by the file: %+v", g)
+	}
+}
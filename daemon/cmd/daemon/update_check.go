@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// releaseChecker is the slice of fetch.GitHub `update --check` needs; a seam
+// so the check is unit-tested without the network.
+type releaseChecker interface {
+	ResolveLatest(ctx context.Context) (string, error)
+	ResolveNewest(ctx context.Context) (string, error)
+	ReleaseNotes(ctx context.Context, tag string) (string, error)
+}
+
+// maxNoteLines caps how much of a release body `update --check` prints; the
+// full notes stay one click away on the release page.
+const maxNoteLines = 20
+
+// checkUpdate is `daemon update --check`: report what an update would move
+// desired to, with that release's notes, WITHOUT writing anything. target is
+// an explicit tag to inspect ("" ⇒ resolve on channel); salt places this
+// install in a staged rollout (rollout.go). Returns the exit code
+// (exitcode.go): 4 when there is a release to move to, 0 when up to date,
+// pinned or held back by the rollout, 1 when the resolve itself fails.
+// Release notes are best-effort — a failed notes fetch is a note, not an error.
+func checkUpdate(ctx context.Context, rc releaseChecker, desired, channel, target, salt string, out io.Writer) int {
+	if desired == "" {
+		desired = "none"
+	}
+	fmt.Fprintf(out, "  %-10s %s\n", "channel", channel)
+	fmt.Fprintf(out, "  %-10s %s\n", "desired", desired)
+
+	if target == "" {
+		if channel == core.ChannelPinned {
+			fmt.Fprintln(out, "  pinned: nothing to resolve (inspect a tag with: daemon update --check vX.Y.Z)")
+			return exitOK
+		}
+		resolve := rc.ResolveLatest
+		if channel == core.ChannelBeta {
+			resolve = rc.ResolveNewest
+		}
+		v, err := resolve(ctx)
+		if err != nil {
+			fmt.Fprintln(out, "  could not resolve the newest release:", err)
+			return exitError
+		}
+		if !isValidVersionTag(v) {
+			fmt.Fprintln(out, "  newest release tag is not a strict semver tag; ignoring it")
+			return exitError
+		}
+		target = v
+	}
+
+	if target == desired {
+		fmt.Fprintf(out, "  %-10s %s (up to date)\n", "newest", target)
+		return exitOK
+	}
+	fmt.Fprintf(out, "  %-10s %s — update available (apply: daemon update %s)\n", "newest", target, target)
+
+	code := exitUpdateAvailable
+	notes, err := rc.ReleaseNotes(ctx, target)
+	if held, pct := heldBack(notes, salt, target); err == nil && pct >= 0 {
+		state := "this install is in it"
+		if held {
+			state = "not yet this install; `daemon update` leaves desired alone"
+			code = exitOK
+		}
+		fmt.Fprintf(out, "  %-10s %d%% — %s\n", "rollout", pct, state)
+	}
+	switch {
+	case err != nil:
+		fmt.Fprintln(out, "  release notes unavailable:", err)
+	case strings.TrimSpace(notes) == "":
+		fmt.Fprintln(out, "  (release has no notes)")
+	default:
+		fmt.Fprintf(out, "release notes (%s)\n", target)
+		io.WriteString(out, summarizeNotes(notes, maxNoteLines))
+	}
+	return code
+}
+
+// summarizeNotes indents the first maxLines non-blank lines of a release body
+// and appends a truncation marker when more remain. Release bodies are remote
+// input printed to a terminal, so control characters (ANSI escapes included)
+// are stripped rather than passed through.
+func summarizeNotes(body string, maxLines int) string {
+	var b strings.Builder
+	shown, more := 0, false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRightFunc(stripControl(line), unicode.IsSpace)
+		if line == "" {
+			continue
+		}
+		if shown == maxLines {
+			more = true
+			break
+		}
+		b.WriteString("  " + line + "\n")
+		shown++
+	}
+	if more {
+		b.WriteString("  … (truncated; see the release page for the full notes)\n")
+	}
+	return b.String()
+}
+
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+type fakeChecker struct {
+	latest, newest string
+	notes          string
+	notesErr       error
+	notesAsked     string
+}
+
+func (f *fakeChecker) ResolveLatest(context.Context) (string, error) { return f.latest, nil }
+func (f *fakeChecker) ResolveNewest(context.Context) (string, error) { return f.newest, nil }
+func (f *fakeChecker) ReleaseNotes(_ context.Context, tag string) (string, error) {
+	f.notesAsked = tag
+	return f.notes, f.notesErr
+}
+
+func TestCheckUpdate(t *testing.T) {
+	cases := []struct {
+		name      string
+		rc        *fakeChecker
+		desired   string
+		channel   string
+		target    string
+		wantCode  int
+		want      []string
+		wantNotes string // tag ReleaseNotes must be asked for ("" = not asked)
+	}{
+		{
+			name: "stable update available shows notes", rc: &fakeChecker{latest: "v1.3.0", newest: "v1.4.0-rc.1", notes: "- faster"},
+			desired: "v1.2.0", channel: core.ChannelStable, wantCode: exitUpdateAvailable,
+			want:      []string{"v1.3.0 — update available", "release notes (v1.3.0)", "  - faster"},
+			wantNotes: "v1.3.0",
+		},
+		{
+			name: "beta resolves pre-releases", rc: &fakeChecker{latest: "v1.3.0", newest: "v1.4.0-rc.1"},
+			desired: "v1.3.0", channel: core.ChannelBeta, wantCode: exitUpdateAvailable,
+			want:      []string{"v1.4.0-rc.1 — update available", "(release has no notes)"},
+			wantNotes: "v1.4.0-rc.1",
+		},
+		{
+			name: "up to date skips notes", rc: &fakeChecker{latest: "v1.2.0"},
+			desired: "v1.2.0", channel: core.ChannelStable,
+			want: []string{"v1.2.0 (up to date)"},
+		},
+		{
+			name: "pinned resolves nothing", rc: &fakeChecker{latest: "v9.9.9"},
+			desired: "v1.2.0", channel: core.ChannelPinned,
+			want: []string{"pinned: nothing to resolve"},
+		},
+		{
+			name: "explicit tag inspected even when pinned", rc: &fakeChecker{notes: "x"},
+			desired: "v1.2.0", channel: core.ChannelPinned, target: "v1.5.0", wantCode: exitUpdateAvailable,
+			want:      []string{"v1.5.0 — update available"},
+			wantNotes: "v1.5.0",
+		},
+		{
+			name: "notes failure is not fatal", rc: &fakeChecker{latest: "v1.3.0", notesErr: errors.New("status 404")},
+			desired: "", channel: core.ChannelStable, wantCode: exitUpdateAvailable,
+			want:      []string{"desired    none", "release notes unavailable"},
+			wantNotes: "v1.3.0",
+		},
+		{
+			name: "staged release reports this install's place", rc: &fakeChecker{latest: "v1.3.0", notes: "Rollout: 0%\n- faster"},
+			desired: "v1.2.0", channel: core.ChannelStable,
+			want:      []string{"rollout    0% — not yet this install", "  - faster"},
+			wantNotes: "v1.3.0",
+		},
+		{
+			name: "full rollout includes every install", rc: &fakeChecker{latest: "v1.3.0", notes: "rollout: 100%"},
+			desired: "v1.2.0", channel: core.ChannelStable, wantCode: exitUpdateAvailable,
+			want:      []string{"rollout    100% — this install is in it"},
+			wantNotes: "v1.3.0",
+		},
+		{
+			name: "non-semver tag refused", rc: &fakeChecker{latest: "../evil"},
+			desired: "v1.2.0", channel: core.ChannelStable, wantCode: exitError,
+			want: []string{"not a strict semver tag"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if code := checkUpdate(context.Background(), c.rc, c.desired, c.channel, c.target, "salt", &out); code != c.wantCode {
+				t.Fatalf("exit = %d, want %d\n%s", code, c.wantCode, out.String())
+			}
+			for _, w := range c.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("output missing %q:\n%s", w, out.String())
+				}
+			}
+			if c.rc.notesAsked != c.wantNotes {
+				t.Errorf("notes fetched for %q, want %q", c.rc.notesAsked, c.wantNotes)
+			}
+		})
+	}
+}
+
+func TestSummarizeNotes(t *testing.T) {
+	body := "## Changes\r\n\n- one\x1b[31m red\x1b[0m\n- two\n- three\n"
+	got := summarizeNotes(body, 3)
+	want := "  ## Changes\n  - one[31m red[0m\n  - two\n  … (truncated; see the release page for the full notes)\n"
+	if got != want {
+		t.Fatalf("summarizeNotes =\n%q\nwant\n%q", got, want)
+	}
+	if strings.ContainsRune(got, '\x1b') {
+		t.Fatal("escape character passed through")
+	}
+	if got := summarizeNotes("- only\n", 3); got != "  - only\n" {
+		t.Fatalf("short body = %q", got)
+	}
+}
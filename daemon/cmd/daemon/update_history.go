@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// printHistory is `daemon update --history`: the recorded promotions to
+// last-known-good, newest first, marking the current good and desired.
+func printHistory(st *core.Store, out io.Writer) int {
+	h := st.History()
+	if len(h) == 0 {
+		fmt.Fprintln(out, "  no update history recorded yet")
+		return 0
+	}
+	good, desired := st.Good(), st.Desired()
+	for i := len(h) - 1; i >= 0; i-- {
+		e := h[i]
+		var tags string
+		if e.Version == good {
+			tags += " good"
+		}
+		if e.Version == desired {
+			tags += " desired"
+		}
+		fmt.Fprintf(out, "  %-20s %-12s%s\n", e.At.Local().Format(time.DateTime), e.Version, tags)
+	}
+	return 0
+}
+
+// rollbackUpdate is `daemon update --rollback`: point desired back at the
+// newest earlier version that was healthy (Store.PreviousGood) and pin the
+// channel so a later no-argument `daemon update` does not walk straight back
+// onto the regression. The reconcile loop performs the actual swap — from the
+// kept per-version binary when it is still on disk, else a verified download.
+// Returns the exit code.
+func rollbackUpdate(st *core.Store, out io.Writer) int {
+	prev := st.PreviousGood()
+	if prev == "" {
+		fmt.Fprintln(out, "  rollback: no earlier healthy version in the update history")
+		return 1
+	}
+	if err := st.WriteDesired(prev); err != nil {
+		// Don't print raw err: the store path can be the disguised workdir.
+		fmt.Fprintln(out, "  rollback: write desired failed (store not writable; re-run with sudo?)")
+		return 1
+	}
+	if err := st.WriteChannel(core.ChannelPinned); err != nil {
+		fmt.Fprintln(out, "  rollback: desired set but pinning the channel failed; a plain `daemon update` may move forward again")
+		return 1
+	}
+	cur := st.Good()
+	if cur == "" {
+		cur = "none"
+	}
+	fmt.Fprintf(out, "  rollback: desired %s (was good %s); channel pinned\n", prev, cur)
+	fmt.Fprintln(out, "  resume updates with: daemon update --channel stable")
+	return 0
+}
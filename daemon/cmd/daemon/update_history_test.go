@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestRollbackUpdate(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	var out bytes.Buffer
+	if code := rollbackUpdate(st, &out); code != 1 {
+		t.Fatalf("empty history: code = %d, want 1", code)
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		_ = st.RecordGood(v, at)
+		_ = st.WriteGood(v)
+	}
+	_ = st.WriteDesired("v1.1.0")
+	out.Reset()
+	if code := rollbackUpdate(st, &out); code != 0 {
+		t.Fatalf("code = %d, out=%s", code, out.String())
+	}
+	if st.Desired() != "v1.0.0" || st.Channel() != core.ChannelPinned {
+		t.Fatalf("after rollback desired=%q channel=%q, want v1.0.0 pinned", st.Desired(), st.Channel())
+	}
+	if !strings.Contains(out.String(), "v1.0.0") {
+		t.Fatalf("output must name the rollback target:\n%s", out.String())
+	}
+
+	out.Reset()
+	printHistory(st, &out)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "v1.1.0") || !strings.Contains(lines[0], "good") ||
+		!strings.Contains(lines[1], "desired") {
+		t.Fatalf("history must list newest first with good/desired tags:\n%s", out.String())
+	}
+}
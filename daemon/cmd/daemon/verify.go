@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+// doVerify is `daemon verify`: a single end-to-end "is my protection
+// intact" check of the discovered install.
+//
+//	daemon verify [--json] [--quiet]
+//
+// It checks the daemon and good platform binaries' signatures, that the
+// companion's offline backup verifies and matches the running daemon
+// byte for byte, that version.json opens with the install key, that the
+// masked roster reads back whole, and that each mesh plist equals the one
+// install would generate today. It repairs nothing. Check names are fixed
+// nouns, so the output carries no disguised path or label.
+//
+// Exit codes (exitcode.go): 0 intact · 1 the install could not be read
+// (re-run with sudo for a system install) · 2 a check failed · 3 no install
+// was found · 64 usage.
+func doVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "emit the checks as JSON")
+	quiet := fs.Bool("quiet", false, "print nothing; branch on the exit code")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	checks, err := osadapter.VerifyInstall(mode.Resolve())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verify: could not read the install; re-run with sudo for a system install")
+		return exitError
+	}
+	var out io.Writer = os.Stdout
+	if *quiet {
+		out = io.Discard
+	}
+	return reportVerify(checks, *asJSON, out)
+}
+
+// reportVerify prints checks and returns the exit code: 0 only when there
+// is at least one check and every check passed, 3 when there are none.
+func reportVerify(checks []osadapter.Check, asJSON bool, out io.Writer) int {
+	failed := 0
+	for _, c := range checks {
+		if !c.OK {
+			failed++
+		}
+	}
+	intact := len(checks) > 0 && failed == 0
+	if asJSON {
+		if checks == nil {
+			checks = []osadapter.Check{}
+		}
+		b, _ := json.MarshalIndent(struct {
+			Intact bool              `json:"intact"`
+			Checks []osadapter.Check `json:"checks"`
+		}{intact, checks}, "", "  ")
+		fmt.Fprintln(out, string(b))
+	} else {
+		if len(checks) == 0 {
+			fmt.Fprintln(out, "  verify: no install found")
+		}
+		for _, c := range checks {
+			switch {
+			case c.OK && c.Note != "":
+				fmt.Fprintf(out, "  ok    %s (%s)\n", c.Name, c.Note)
+			case c.OK:
+				fmt.Fprintf(out, "  ok    %s\n", c.Name)
+			default:
+				fmt.Fprintf(out, "  FAIL  %s: %s\n", c.Name, c.Note)
+			}
+		}
+		if len(checks) > 0 {
+			fmt.Fprintf(out, "  %d of %d checks failed\n", failed, len(checks))
+		}
+	}
+	switch {
+	case len(checks) == 0:
+		return exitNotRunning
+	case !intact:
+		return exitDegraded
+	}
+	return exitOK
+}
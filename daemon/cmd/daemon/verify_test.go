@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
+)
+
+func TestReportVerify(t *testing.T) {
+	var out bytes.Buffer
+	ok := []osadapter.Check{{Name: "daemon binary", OK: true}, {Name: "roster", OK: true}}
+	if code := reportVerify(ok, false, &out); code != 0 || !strings.Contains(out.String(), "0 of 2 checks failed") {
+		t.Fatalf("intact: code %d\n%s", code, out.String())
+	}
+
+	out.Reset()
+	bad := append(ok, osadapter.Check{Name: "plist role a", Note: "missing"})
+	if code := reportVerify(bad, false, &out); code != exitDegraded || !strings.Contains(out.String(), "FAIL  plist role a: missing") {
+		t.Fatalf("mismatch: code %d\n%s", code, out.String())
+	}
+
+	out.Reset()
+	if code := reportVerify(nil, true, &out); code != exitNotRunning || !strings.Contains(out.String(), `"checks": []`) {
+		t.Fatalf("no install must not read intact: code %d\n%s", code, out.String())
+	}
+}
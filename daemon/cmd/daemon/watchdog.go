@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
 	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
 )
@@ -47,7 +52,10 @@ func doWatchdog(args []string) int {
 		return 1
 	}
 	m := mode.Resolve()
-	return runWatchdog(self, m, *desired,
+	// find is wrapped so the hung-worker check below reuses its result.
+	var cur osadapter.CurInstall
+	var ferr error
+	code := runWatchdog(self, m, *desired,
 		// HIGH-2: Ed25519-verify the running copy BEFORE it can reinstall as
 		// root. The copy lives outside the mesh workdir and is launched by a
 		// root cron line; an attacker who swaps it between fires would get
@@ -56,12 +64,88 @@ func doWatchdog(args []string) int {
 		// the same verifier the install discovery uses.
 		sig.VerifyFile,
 		func() (osadapter.CurInstall, error) {
-			return osadapter.FindCurrentInstall(m, sig.VerifyFile)
+			cur, ferr = osadapter.FindCurrentInstall(m, sig.VerifyFile)
+			return cur, ferr
 		},
 		func(spec *osadapter.Spec) error {
 			return installMesh(self, spec, *desired)
 		},
 	)
+	if meshComplete(cur, ferr) && cur.Workdir != "" {
+		st := &core.Store{Dir: cur.Workdir}
+		age, ok := osadapter.CompanionHeartbeatAge(m)
+		log := newLogger(slog.LevelWarn, st)
+		n := notify.New(func() []notify.Hook { return storeHooks(st) }, log)
+		send := func(ev notify.Event) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = n.Deliver(ctx, ev)
+		}
+		// A booted-out mesh has a stale heartbeat too; reloading it is the
+		// whole fix, so the hung check waits for the next pass.
+		if reloadBootedOut(cur, osadapter.ReloadBootedOut, send) == 0 {
+			restartHung(m, cur, age, ok, st.HungAfter(), osadapter.RestartJob, send)
+		}
+		// A worker that crashes and is never restarted leaves its report
+		// for this pass.
+		collectCrashes(st, submitCrash(st), log)
+	}
+	return code
+}
+
+// reloadBootedOut puts back mesh jobs that `launchctl bootout` unloaded while
+// their plists stayed. meshComplete counts plists, so to it such a mesh is
+// whole; a worker puts back a booted-out sibling within a tick, so this only
+// matters once every worker is gone. Sends one MeshRestored event and
+// returns how many jobs were reloaded.
+func reloadBootedOut(cur osadapter.CurInstall, reload func(osadapter.CurInstall) (int, error), send func(notify.Event)) int {
+	n, err := reload(cur)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watchdog: reload booted-out job failed")
+	}
+	if n == 0 {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "watchdog: %d booted-out mesh job(s) reloaded\n", n)
+	send(notify.Event{Kind: notify.MeshRestored, At: time.Now(),
+		Message: fmt.Sprintf("%d booted-out launchd job(s) reloaded", n),
+		Details: map[string]string{"count": fmt.Sprint(n)}})
+	return n
+}
+
+// restartHung restarts the mesh workers when the mesh is loaded but its
+// heartbeat has been stale for at least after: the processes exist, so
+// KeepAlive leaves them be, yet none is ticking (every worker touches the
+// heartbeat, so one healthy worker keeps it fresh). It then touches the
+// heartbeat itself, so a restart that does not take is retried only after
+// another full window rather than on every companion pass, and sends one
+// DaemonHung event. A missing heartbeat is a mesh that never beat (a fresh
+// install), not a hang. Returns how many workers were restarted.
+func restartHung(
+	m mode.Mode, cur osadapter.CurInstall,
+	age time.Duration, beat bool, after time.Duration,
+	restart func(m mode.Mode, label string) error,
+	send func(notify.Event),
+) int {
+	if !beat || age < after {
+		return 0
+	}
+	spec := osadapter.Spec{Mode: m, Roster: cur.Roster}
+	restarted := 0
+	for _, r := range []osadapter.Role{osadapter.RoleA, osadapter.RoleB} {
+		if err := restart(m, spec.Label(r)); err != nil {
+			fmt.Fprintln(os.Stderr, "watchdog: restart hung worker failed")
+			continue
+		}
+		restarted++
+	}
+	_ = osadapter.TouchCompanionHeartbeat(m)
+	fmt.Fprintf(os.Stderr, "watchdog: heartbeat stale for %s with the mesh loaded; restarted %d worker(s)\n",
+		age.Round(time.Second), restarted)
+	send(notify.Event{Kind: notify.DaemonHung, At: time.Now(),
+		Message: fmt.Sprintf("protection workers stopped checking in for %s; %d restarted", age.Round(time.Second), restarted),
+		Details: map[string]string{"stale_s": fmt.Sprint(int64(age / time.Second)), "restarted": fmt.Sprint(restarted)}})
+	return restarted
 }
 
 // runWatchdog is the injectable core: find the current install, and if the
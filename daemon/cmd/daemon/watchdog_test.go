@@ -4,8 +4,10 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+	"github.com/eliteGoblin/focusd/daemon/internal/notify"
 	"github.com/eliteGoblin/focusd/daemon/internal/osadapter"
 )
 
@@ -223,3 +225,83 @@ func TestRunWatchdogRebuildFailureNonZero(t *testing.T) {
 		t.Fatalf("exit = 0 for a failed rebuild, want non-zero")
 	}
 }
+
+// TestRestartHung: a loaded mesh whose heartbeat is stale past the threshold
+// gets both workers restarted and one daemon_hung event; a fresh or missing
+// heartbeat is left alone.
+func TestRestartHung(t *testing.T) {
+	var cur osadapter.CurInstall
+	spec := osadapter.Spec{Mode: mode.Test, Roster: cur.Roster}
+	cases := []struct {
+		name     string
+		age      time.Duration
+		beat     bool
+		fail     bool
+		restarts int
+		events   int
+	}{
+		{"fresh heartbeat", 30 * time.Second, true, false, 0, 0},
+		{"no heartbeat yet", time.Hour, false, false, 0, 0},
+		{"hung", 3 * time.Minute, true, false, 2, 1},
+		{"hung, restart fails", 3 * time.Minute, true, true, 0, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var labels []string
+			var events []notify.Event
+			got := restartHung(mode.Test, cur, tc.age, tc.beat, 2*time.Minute,
+				func(_ mode.Mode, label string) error {
+					labels = append(labels, label)
+					if tc.fail {
+						return errors.New("launchctl")
+					}
+					return nil
+				},
+				func(ev notify.Event) { events = append(events, ev) })
+			if got != tc.restarts || len(events) != tc.events {
+				t.Fatalf("restarted %d, events %d; want %d, %d", got, len(events), tc.restarts, tc.events)
+			}
+			if tc.events == 0 {
+				if len(labels) != 0 {
+					t.Fatalf("restart attempted: %v", labels)
+				}
+				return
+			}
+			want := []string{spec.Label(osadapter.RoleA), spec.Label(osadapter.RoleB)}
+			if strings.Join(labels, ",") != strings.Join(want, ",") {
+				t.Fatalf("labels = %v, want the two workers %v", labels, want)
+			}
+			if events[0].Kind != notify.DaemonHung {
+				t.Fatalf("event kind = %q", events[0].Kind)
+			}
+		})
+	}
+}
+
+// TestReloadBootedOut: reloaded jobs raise one MeshRestored event; none
+// reloaded (an intact mesh, or a failed reload) raises nothing.
+func TestReloadBootedOut(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		n      int
+		err    error
+		events int
+	}{
+		{"intact", 0, nil, 0},
+		{"whole mesh booted out", 3, nil, 1},
+		{"reload failed", 0, errors.New("launchctl"), 0},
+		{"partly reloaded", 1, errors.New("launchctl"), 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []notify.Event
+			got := reloadBootedOut(fullMesh(), func(osadapter.CurInstall) (int, error) { return tc.n, tc.err },
+				func(ev notify.Event) { events = append(events, ev) })
+			if got != tc.n || len(events) != tc.events {
+				t.Fatalf("reloaded %d, events %d; want %d, %d", got, len(events), tc.n, tc.events)
+			}
+			if tc.events > 0 && events[0].Kind != notify.MeshRestored {
+				t.Fatalf("event kind = %q", events[0].Kind)
+			}
+		})
+	}
+}
@@ -3,3 +3,9 @@ module github.com/eliteGoblin/focusd/daemon
 go 1.25.6
 
 require golang.org/x/sys v0.42.0
+
+require golang.org/x/crypto v0.49.0
+
+require github.com/eliteGoblin/focusd/shared v0.0.0
+
+replace github.com/eliteGoblin/focusd/shared => ../shared
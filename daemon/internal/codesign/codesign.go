@@ -0,0 +1,119 @@
+// Package codesign reads a binary's Apple code signature: whether it is
+// signed at all, ad-hoc (the Go linker's signature) or by a Developer ID
+// team, whether the seal still validates, and whether Gatekeeper accepts it
+// as notarized. It complements package sig, which stays the trust root:
+// a focusd release verifies against the Ed25519 key whatever Apple says.
+//
+// What this adds is a ratchet. Once an install runs a team-signed daemon,
+// an update or a restore must bring the same team (and keep notarization),
+// so a stripped or re-signed binary cannot be slipped in even if it carries
+// a valid release trailer from an older, unsigned build.
+package codesign
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrDowngrade: the replacement is not signed like the binary it replaces.
+var ErrDowngrade = errors.New("codesign: replacement is not signed like the installed binary")
+
+// Info is what codesign reports for one file. The zero Info is an unsigned
+// binary.
+type Info struct {
+	Signed bool
+	// Adhoc: signed without an identity (the Go linker's default).
+	Adhoc bool
+	// TeamID is the Developer ID team; "" when ad-hoc or unsigned.
+	TeamID string
+	// Intact: `codesign --verify` accepts the seal.
+	Intact bool
+	// Notarized: Gatekeeper assesses it as a notarized Developer ID binary.
+	Notarized bool
+}
+
+// String is a short, path-free summary for status and logs.
+func (i Info) String() string {
+	switch {
+	case !i.Signed:
+		return "unsigned"
+	case i.TeamID == "":
+		return "ad-hoc"
+	}
+	s := "team " + i.TeamID
+	if i.Notarized {
+		s += ", notarized"
+	}
+	if !i.Intact {
+		s += ", seal broken"
+	}
+	return s
+}
+
+// Replaceable reports whether next may replace have. Only a team-signed
+// binary sets a bar: next must carry the same team with an intact seal,
+// and stay notarized if have was. Ad-hoc and unsigned installs accept
+// anything — the Ed25519 check is their gate.
+func Replaceable(have, next Info) error {
+	if have.TeamID == "" {
+		return nil
+	}
+	if next.TeamID != have.TeamID || !next.Intact || (have.Notarized && !next.Notarized) {
+		return ErrDowngrade
+	}
+	return nil
+}
+
+// Keep is Replaceable for two files on disk: the installed binary cur and
+// the candidate next. When cur's signature cannot be read (no codesign on
+// this OS) there is nothing to keep; when next's cannot, a team-signed cur
+// is not replaced.
+func Keep(cur, next string) error {
+	have, err := Of(cur)
+	if err != nil || have.TeamID == "" {
+		return nil
+	}
+	got, err := Of(next)
+	if err != nil {
+		return ErrDowngrade
+	}
+	return Replaceable(have, got)
+}
+
+// parseDisplay reads `codesign -dv --verbose=2` output (codesign writes it
+// to stderr) into the identity fields of an Info.
+func parseDisplay(out string) Info {
+	var i Info
+	for _, line := range strings.Split(out, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "Signature":
+			i.Signed = true
+			i.Adhoc = v == "adhoc"
+		case "Authority":
+			i.Signed = true
+		case "TeamIdentifier":
+			if v != "not set" {
+				i.TeamID = v
+			}
+		case "CodeDirectory v":
+			i.Signed = true
+			if strings.Contains(v, "adhoc") {
+				i.Adhoc = true
+			}
+		}
+	}
+	if i.Adhoc {
+		i.TeamID = ""
+	}
+	return i
+}
+
+// notarizedSource reports whether spctl's assessment names a notarized
+// Developer ID source.
+func notarizedSource(out string) bool {
+	return strings.Contains(out, "source=Notarized Developer ID")
+}
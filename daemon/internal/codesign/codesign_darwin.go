@@ -0,0 +1,42 @@
+package codesign
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// probeTimeout bounds each tool run; spctl may consult Gatekeeper's online
+// check, which must never hold up a boot or an update.
+const probeTimeout = 10 * time.Second
+
+// Of reads path's code signature with codesign, then, for a signed file,
+// checks the seal and asks spctl about notarization.
+func Of(path string) (Info, error) {
+	out, err := tool("/usr/bin/codesign", "-dv", "--verbose=2", path)
+	if err != nil {
+		if strings.Contains(out, "not signed at all") {
+			return Info{}, nil
+		}
+		return Info{}, err
+	}
+	i := parseDisplay(out)
+	if !i.Signed {
+		return i, nil
+	}
+	_, verr := tool("/usr/bin/codesign", "--verify", path)
+	i.Intact = verr == nil
+	if i.TeamID != "" {
+		sp, _ := tool("/usr/sbin/spctl", "--assess", "--type", "open", "--context", "context:primary-signature", "-v", path)
+		i.Notarized = notarizedSource(sp)
+	}
+	return i, nil
+}
+
+func tool(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return string(out), err
+}
@@ -0,0 +1,11 @@
+//go:build !darwin
+
+package codesign
+
+import "errors"
+
+// ErrUnsupported: Apple code signatures only exist on macOS.
+var ErrUnsupported = errors.New("codesign: macOS only")
+
+// Of is unsupported off macOS.
+func Of(string) (Info, error) { return Info{}, ErrUnsupported }
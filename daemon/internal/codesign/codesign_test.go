@@ -0,0 +1,63 @@
+package codesign
+
+import "testing"
+
+func TestParseDisplay(t *testing.T) {
+	adhoc := `Executable=/tmp/x
+Identifier=a.out
+Format=Mach-O thin (arm64)
+CodeDirectory v=20400 size=1234 flags=0x20002(adhoc,linker-signed) hashes=33+0 location=embedded
+Signature=adhoc
+TeamIdentifier=not set
+`
+	if i := parseDisplay(adhoc); !i.Signed || !i.Adhoc || i.TeamID != "" || i.String() != "ad-hoc" {
+		t.Fatalf("ad-hoc parsed as %+v", i)
+	}
+	team := `Executable=/tmp/x
+CodeDirectory v=20500 size=1234 flags=0x10000(runtime) hashes=33+7 location=embedded
+Authority=Developer ID Application: Someone (ABCDE12345)
+Authority=Developer ID Certification Authority
+Authority=Apple Root CA
+TeamIdentifier=ABCDE12345
+`
+	i := parseDisplay(team)
+	if !i.Signed || i.Adhoc || i.TeamID != "ABCDE12345" {
+		t.Fatalf("team parsed as %+v", i)
+	}
+	i.Intact, i.Notarized = true, true
+	if i.String() != "team ABCDE12345, notarized" {
+		t.Fatalf("String = %q", i.String())
+	}
+	if i := parseDisplay(""); i.Signed || i.String() != "unsigned" {
+		t.Fatalf("empty parsed as %+v", i)
+	}
+	if !notarizedSource("/tmp/x: accepted\nsource=Notarized Developer ID\n") || notarizedSource("/tmp/x: rejected\n") {
+		t.Fatal("spctl source misread")
+	}
+}
+
+func TestReplaceable(t *testing.T) {
+	team := Info{Signed: true, TeamID: "ABCDE12345", Intact: true}
+	notarized := team
+	notarized.Notarized = true
+	cases := []struct {
+		name       string
+		have, next Info
+		ok         bool
+	}{
+		{"unsigned install takes anything", Info{}, Info{}, true},
+		{"ad-hoc install takes unsigned", Info{Signed: true, Adhoc: true}, Info{}, true},
+		{"team to same team", team, team, true},
+		{"team to unsigned", team, Info{}, false},
+		{"team to ad-hoc", team, Info{Signed: true, Adhoc: true}, false},
+		{"team to other team", team, Info{Signed: true, TeamID: "ZZZZZ99999", Intact: true}, false},
+		{"team to broken seal", team, Info{Signed: true, TeamID: "ABCDE12345"}, false},
+		{"notarized to un-notarized", notarized, team, false},
+		{"notarized to notarized", notarized, notarized, true},
+	}
+	for _, c := range cases {
+		if err := Replaceable(c.have, c.next); (err == nil) != c.ok {
+			t.Errorf("%s: err %v", c.name, err)
+		}
+	}
+}
@@ -9,9 +9,10 @@
 // signature-verified offline copy of the daemon and hands off to the daemon's
 // idempotent `watchdog` rebuild — restoring protection with NO network.
 //
-// This package is PURE + OS-agnostic (Linux-CI-testable): it only builds paths
-// and decides staleness. Every filesystem + launchd side effect lives in
-// cmd/companion (the binary) and internal/osadapter (the daemon-side wiring).
+// This package is PURE + OS-agnostic (Linux-CI-testable): it builds paths,
+// decides staleness and packs the backup (pack.go; ReadBackup is its one file
+// read). Every other filesystem + launchd side effect lives in cmd/companion
+// (the binary) and internal/osadapter (the daemon-side wiring).
 package companion
 
 import (
@@ -92,6 +93,14 @@ func (d Dir) Backup() string {
 	return filepath.Join(d.root, ".com.apple.MobileAsset.softwareupdate")
 }
 
+// Spare is the companion's OWN copy of the backup: refreshed only from a backup
+// that verifies, and put back in its place when the backup stops verifying — so
+// the offline restore survives a tampered backup even while the daemon, which
+// otherwise rewrites the backup, is down. Only the companion writes it.
+func (d Dir) Spare() string {
+	return filepath.Join(d.root, ".com.apple.MobileAsset.softwareupdate.1")
+}
+
 // Desired is the pinned platform version the restored daemon should rebuild
 // with (the watchdog needs an explicit -v; it does not resolve "latest").
 func (d Dir) Desired() string {
@@ -49,6 +49,7 @@ func TestDirPathsUnderRootDistinct(t *testing.T) {
 		"label":     d.LabelFile(),
 		"log":       d.Log(),
 		"ranmarker": d.RanMarker(),
+		"spare":     d.Spare(),
 	}
 	seen := map[string]string{}
 	for name, p := range paths {
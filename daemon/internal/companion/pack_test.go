@@ -0,0 +1,67 @@
+package companion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestPackRoundTrip: a packed backup unpacks to the same bytes, carries its
+// size in the header, and no longer contains the plain bytes it was built
+// from (the point of packing).
+func TestPackRoundTrip(t *testing.T) {
+	bin := []byte(strings.Repeat("\xcf\xfa\xed\xfe daemon text ", 4096))
+	packed, err := Pack(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed) >= len(bin) {
+		t.Fatalf("packed %d bytes, want fewer than %d", len(packed), len(bin))
+	}
+	if bytes.Contains(packed, []byte("daemon text")) || bytes.Contains(packed, []byte("\xcf\xfa\xed\xfe")) {
+		t.Fatal("packed copy still contains plain daemon bytes")
+	}
+	if n, ok := UnpackedSize(packed); !ok || n != int64(len(bin)) {
+		t.Fatalf("UnpackedSize = %d, %v; want %d, true", n, ok, len(bin))
+	}
+	got, err := Unpack(packed)
+	if err != nil || !bytes.Equal(got, bin) {
+		t.Fatalf("Unpack: err %v, equal %v", err, bytes.Equal(got, bin))
+	}
+}
+
+// TestUnpackLegacyPlainCopy: a backup written before packing is returned as
+// is, so an existing install keeps a usable backup across the upgrade.
+func TestUnpackLegacyPlainCopy(t *testing.T) {
+	plain := []byte("SIGNED-DAEMON")
+	if _, ok := UnpackedSize(plain); ok {
+		t.Fatal("plain copy read as packed")
+	}
+	if got, err := Unpack(plain); err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("Unpack(plain) = %q, %v", got, err)
+	}
+}
+
+// TestUnpackRejectsDamage: a truncated body or a header claiming more bytes
+// than the body holds is an error, never a short binary.
+func TestUnpackRejectsDamage(t *testing.T) {
+	packed, err := Pack(bytes.Repeat([]byte("daemon"), 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Unpack(packed[:len(packed)/2]); err == nil {
+		t.Fatal("truncated pack unpacked without error")
+	}
+	short, err := Pack([]byte("daemon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Claim a larger size: unmask, rewrite the size, remask.
+	h := bytes.Clone(short)
+	mask(h)
+	h[packHeaderLen-1] = 0xff
+	mask(h)
+	if _, err := Unpack(h); err == nil {
+		t.Fatal("size mismatch unpacked without error")
+	}
+}
@@ -12,6 +12,11 @@ type State struct {
 	Running    string          // running platform version; "" = none running
 	Good       string          // last-known-good version; "" = none yet
 	Bad        map[string]bool // versions that crash-looped → never run
+	// SwapDeferred: we are outside the operator's update maintenance window,
+	// so a HEALTHY running platform must not be stopped just to move it to a
+	// new desired version. Cold starts and rollbacks are never deferred —
+	// they restore protection rather than interrupt it.
+	SwapDeferred bool
 }
 
 // Kind is the action the executor must perform.
@@ -81,7 +86,13 @@ func Decide(s State) Action {
 		return Action{Kind: Steady, Target: target, Note: "running desired"}
 	}
 
-	// 5. Running a different version → Recreate to the desired one.
+	// 5. Running a different version → Recreate to the desired one, unless
+	// the swap is deferred to the maintenance window. A known-bad running
+	// version is never kept alive by the window.
+	if s.SwapDeferred && !s.Bad[s.Running] {
+		return Action{Kind: Steady, Target: s.Running,
+			Note: "update " + s.Running + " → " + target + " deferred until maintenance window"}
+	}
 	return Action{Kind: EnsureRunning, Target: target,
 		Note: "running " + s.Running + " ≠ desired " + target + " → switch"}
 }
@@ -89,3 +89,21 @@ func TestDecideIsTotalAndDeterministic(t *testing.T) {
 		t.Fatalf("unknown kind %q", a.Kind)
 	}
 }
+
+// Outside the maintenance window a healthy running version is kept; the swap
+// waits. Cold start and a bad running version are never deferred.
+func TestDecideSwapDeferredOutsideWindow(t *testing.T) {
+	got := Decide(State{HaveConfig: true, Desired: "v2", Running: "v1", SwapDeferred: true})
+	if got.Kind != Steady || got.Target != "v1" {
+		t.Fatalf("deferred swap ⇒ Steady on v1, got %+v", got)
+	}
+	got = Decide(State{HaveConfig: true, Desired: "v2", SwapDeferred: true})
+	if got.Kind != EnsureRunning || got.Target != "v2" {
+		t.Fatalf("nothing running must start despite the window, got %+v", got)
+	}
+	got = Decide(State{HaveConfig: true, Desired: "v2", Running: "v1",
+		Bad: map[string]bool{"v1": true}, SwapDeferred: true})
+	if got.Kind != EnsureRunning || got.Target != "v2" {
+		t.Fatalf("bad running version must be replaced despite the window, got %+v", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 // Seams — real implementations hit GitHub / launchd / processes; tests
@@ -284,12 +285,18 @@ func (e *Executor) Tick(ctx context.Context) (Action, error) {
 		Running:    running,
 		Good:       e.Store.Good(),
 		Bad:        e.Store.BadSet(),
+		// Re-read every tick so a `daemon update --window` takes effect
+		// without a restart, and a deferred swap fires on the first tick
+		// inside the window.
+		SwapDeferred: !e.Store.UpdateWindow().Contains(e.nowOrDefault()),
 	}
 
 	// Promote: a healthy running version that equals desired becomes good.
 	if running != "" && running == st.Desired && st.Good != running &&
 		e.Plat.HealthyFor(running) {
 		_ = e.Store.WriteGood(running)
+		// Best-effort: the history only feeds `daemon update --rollback`.
+		_ = e.Store.RecordGood(running, e.nowOrDefault())
 		st.Good = running
 	}
 
@@ -400,11 +407,16 @@ func (e *Executor) apply(ctx context.Context, a Action) error {
 			if now := e.nowOrDefault(); v == e.fetchRetryVersion && now.Before(e.fetchRetryAfter) {
 				return fmt.Errorf("ensure binary %s: deferred until %s (fetch cooldown)", v, e.fetchRetryAfter.Format(time.RFC3339))
 			}
-			if err := e.Fetch.EnsureBinary(ctx, e.Store, v); err != nil {
+			fctx, fspan := tracing.Start(ctx, "platform.fetch",
+				tracing.String("version", v), tracing.Bool("tamper_revert", e.Store.HaveBin(v)))
+			if err := e.Fetch.EnsureBinary(fctx, e.Store, v); err != nil {
+				fspan.Fail(fmt.Sprintf("%T", err))
+				fspan.End()
 				e.fetchRetryAfter = e.nowOrDefault().Add(fetchRetryCooldown)
 				e.fetchRetryVersion = v
 				return fmt.Errorf("ensure binary %s: %w", v, err)
 			}
+			fspan.End()
 			e.fetchRetryAfter = time.Time{} // success: clear the cooldown
 			e.fetchRetryVersion = ""
 			// A genuine, signature-verified binary for v is now on disk (freshly
@@ -451,10 +463,16 @@ func (e *Executor) apply(ctx context.Context, a Action) error {
 		// next tick's CrashedQuickly check keys off the right version
 		// (otherwise a crashing prev would never be detected because
 		// the detector would still be watching the dead target).
+		_, sspan := tracing.Start(ctx, "platform.start",
+			tracing.String("version", v), tracing.String("previous", prevRunning))
+		defer sspan.End()
 		if err := e.Plat.Start(e.Store.BinPath(v), v); err != nil {
+			sspan.Fail(fmt.Sprintf("%T", err))
 			switch {
 			case prevRunning != "" && prevRunning != v && e.Store.HaveBin(prevRunning) && e.binGenuine(prevRunning):
-				if rbErr := e.Plat.Start(e.Store.BinPath(prevRunning), prevRunning); rbErr == nil {
+				rbErr := e.Plat.Start(e.Store.BinPath(prevRunning), prevRunning)
+				sspan.Set(tracing.Bool("rolled_back", rbErr == nil))
+				if rbErr == nil {
 					e.lastTarget = prevRunning
 					e.lastStartAt = e.nowOrDefault() // rollback (re)started the platform
 					if e.Log != nil {
@@ -480,6 +480,9 @@ func TestExecutorSteadyAndPromoteGood(t *testing.T) {
 	if st.Good() != "v1" {
 		t.Fatalf("healthy desired must be promoted to good, got %q", st.Good())
 	}
+	if h := st.History(); len(h) != 1 || h[0].Version != "v1" {
+		t.Fatalf("promotion must be recorded in the update history, got %+v", h)
+	}
 }
 
 func TestExecutorCrashLoopMarksBadThenRollback(t *testing.T) {
@@ -555,6 +558,32 @@ func TestExecutorSwitchStopsOldStartsNew(t *testing.T) {
 	}
 }
 
+// A swap requested outside the update maintenance window waits: the running
+// platform is left alone until the clock enters the window, then switches.
+func TestExecutorSwapWaitsForUpdateWindow(t *testing.T) {
+	e, st, _, p := newExec(t)
+	st.WriteDesired("v2")
+	w, _ := ParseWindow("02:00-05:00")
+	if err := st.WriteUpdateWindow(w); err != nil {
+		t.Fatal(err)
+	}
+	p.running = "v1"
+	clock := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	e.now = func() time.Time { return clock }
+
+	a, err := e.Tick(context.Background())
+	if err != nil || a.Kind != Steady || p.stopped != 0 || p.running != "v1" {
+		t.Fatalf("outside window: want Steady on v1 untouched, got %+v err=%v stopped=%d running=%q",
+			a, err, p.stopped, p.running)
+	}
+
+	clock = time.Date(2026, 3, 2, 2, 30, 0, 0, time.Local)
+	a, err = e.Tick(context.Background())
+	if err != nil || a.Kind != EnsureRunning || p.running != "v2" {
+		t.Fatalf("inside window: want switch to v2, got %+v err=%v running=%q", a, err, p.running)
+	}
+}
+
 func TestExecutorObserveErrorPropagates(t *testing.T) {
 	st := &Store{Dir: t.TempDir()}
 	e := NewExecutor(st, &fakeFetch{}, &errPlat{}, &fakeLock{acquireOK: true}, nil)
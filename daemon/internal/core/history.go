@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile is the basename (in the daemon-home) of the update history: one
+// entry per version that became last-known-good. Masked like version.json
+// (MaskState) since every entry names a platform version.
+const HistoryFile = ".hist"
+
+// maxHistory bounds the history so a long-lived install's file stays small.
+const maxHistory = 20
+
+// HistoryEntry records one promotion to last-known-good.
+type HistoryEntry struct {
+	Version string    `json:"v"`
+	At      time.Time `json:"at"`
+}
+
+func (s *Store) historyPath() string { return filepath.Join(s.Dir, HistoryFile) }
+
+// History returns the recorded promotions, oldest first. A missing or garbled
+// file reads as empty — the history is advisory and never gates a tick.
+func (s *Store) History() []HistoryEntry {
+	b, err := os.ReadFile(s.historyPath())
+	if err != nil {
+		return nil
+	}
+	var h []HistoryEntry
+	if json.Unmarshal(s.UnmaskState(b), &h) != nil {
+		return nil
+	}
+	return h
+}
+
+// RecordGood appends v to the history unless it is already the newest entry,
+// keeping the last maxHistory promotions.
+func (s *Store) RecordGood(v string, at time.Time) error {
+	h := s.History()
+	if n := len(h); n > 0 && h[n-1].Version == v {
+		return nil
+	}
+	h = append(h, HistoryEntry{Version: v, At: at.UTC()})
+	if len(h) > maxHistory {
+		h = h[len(h)-maxHistory:]
+	}
+	b, _ := json.Marshal(h)
+	return atomicWrite(s.historyPath(), s.MaskState(b))
+}
+
+// PreviousGood is the rollback target for `daemon update --rollback`: the
+// newest recorded good version other than the current one that has not since
+// been marked bad. "" when the history holds no such version.
+func (s *Store) PreviousGood() string {
+	cur, bad := s.Good(), s.BadSet()
+	h := s.History()
+	for i := len(h) - 1; i >= 0; i-- {
+		if v := h[i].Version; v != cur && !bad[v] {
+			return v
+		}
+	}
+	return ""
+}
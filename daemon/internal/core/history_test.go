@@ -0,0 +1,58 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreHistory(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if s.History() != nil || s.PreviousGood() != "" {
+		t.Fatal("fresh store must have no history and no rollback target")
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, v := range []string{"v1", "v2", "v2", "v3"} {
+		if err := s.RecordGood(v, at); err != nil {
+			t.Fatal(err)
+		}
+		_ = s.WriteGood(v)
+	}
+	h := s.History()
+	if len(h) != 3 || h[0].Version != "v1" || h[2].Version != "v3" || !h[2].At.Equal(at) {
+		t.Fatalf("history = %+v, want v1,v2,v3 (repeat collapsed)", h)
+	}
+	if got := s.PreviousGood(); got != "v2" {
+		t.Fatalf("PreviousGood = %q, want v2", got)
+	}
+	_ = s.MarkBad("v2")
+	if got := s.PreviousGood(); got != "v1" {
+		t.Fatalf("PreviousGood must skip a bad version, got %q", got)
+	}
+
+	for i := 0; i < maxHistory+5; i++ {
+		_ = s.RecordGood(string(rune('a'+i%2))+"x", at)
+	}
+	if n := len(s.History()); n != maxHistory {
+		t.Fatalf("history len = %d, want capped at %d", n, maxHistory)
+	}
+}
+
+func TestStoreHistoryMasked(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if _, err := s.EnsureInstallSalt(); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.RecordGood("v0.16.0", time.Now())
+	raw, err := os.ReadFile(s.historyPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("v0.16")) {
+		t.Fatal("history file must not carry the version in plaintext")
+	}
+	if h := s.History(); len(h) != 1 || h[0].Version != "v0.16.0" {
+		t.Fatalf("masked history must roundtrip, got %+v", h)
+	}
+}
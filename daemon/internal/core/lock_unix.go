@@ -50,6 +50,34 @@ func (l *FileLock) TryAcquire(path string) (bool, error) {
 	return true, nil
 }
 
+// Acquire opens (creating if needed) path and waits for an exclusive flock.
+// It is for short critical sections, such as a read-modify-write of a
+// shared file; hold it only for that and Release it.
+func (l *FileLock) Acquire(path string) error {
+	if l.f != nil {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		if pe, ok := err.(*os.PathError); ok {
+			return fmt.Errorf("open lockfile: %w", pe.Err)
+		}
+		return errors.New("open lockfile failed")
+	}
+	for {
+		err = unix.Flock(int(f.Fd()), unix.LOCK_EX)
+		if !errors.Is(err, unix.EINTR) {
+			break
+		}
+	}
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.f = f
+	return nil
+}
+
 // Release drops the lock and closes the fd. Idempotent: calling it without a
 // held lock (or twice) is a no-op.
 func (l *FileLock) Release() error {
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SelfTestFile is the basename (in the daemon-home) of the last boot
+// self-test's result. Masked like the history: a check name is a fixed noun,
+// but the file should not be the one plaintext thing in the folder.
+const SelfTestFile = ".chk"
+
+// SelfTest is the outcome of one `daemon self-test` run: when it ran and the
+// names of the checks that failed (none ⇒ passed).
+type SelfTest struct {
+	At     time.Time `json:"at"`
+	Failed []string  `json:"failed,omitempty"`
+	// Signature is the running binary's Apple code-signature summary
+	// ("ad-hoc", "team ABCDE12345, notarized"); "" when not read.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (s *Store) selfTestPath() string { return filepath.Join(s.Dir, SelfTestFile) }
+
+// LastSelfTest returns the recorded self-test; ok is false when none was
+// recorded or the file does not read back.
+func (s *Store) LastSelfTest() (t SelfTest, ok bool) {
+	b, err := os.ReadFile(s.selfTestPath())
+	if err != nil {
+		return SelfTest{}, false
+	}
+	if json.Unmarshal(s.UnmaskState(b), &t) != nil || t.At.IsZero() {
+		return SelfTest{}, false
+	}
+	return t, true
+}
+
+// RecordSelfTest replaces the recorded self-test with t.
+func (s *Store) RecordSelfTest(t SelfTest) error {
+	t.At = t.At.UTC()
+	b, _ := json.Marshal(t)
+	return atomicWrite(s.selfTestPath(), s.MaskState(b))
+}
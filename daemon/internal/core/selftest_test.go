@@ -0,0 +1,30 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreSelfTest(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if _, ok := s.LastSelfTest(); ok {
+		t.Fatal("fresh store must have no self-test")
+	}
+	if _, err := s.EnsureInstallSalt(); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	if err := s.RecordSelfTest(SelfTest{At: at, Failed: []string{"log sink"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := s.LastSelfTest()
+	if !ok || !got.At.Equal(at) || len(got.Failed) != 1 || got.Failed[0] != "log sink" {
+		t.Fatalf("LastSelfTest = %+v, %v", got, ok)
+	}
+	raw, _ := os.ReadFile(s.selfTestPath())
+	if bytes.Contains(raw, []byte("log sink")) {
+		t.Fatal("self-test file must be masked")
+	}
+}
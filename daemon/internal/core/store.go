@@ -5,16 +5,21 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/lockin"
 	"github.com/eliteGoblin/focusd/daemon/internal/relocate"
+	"github.com/eliteGoblin/focusd/daemon/internal/trustclock"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
 )
 
 // Store is the daemon's tiny on-disk state under the workdir. The
@@ -96,8 +101,164 @@ const InstallSaltFile = relocate.SaltBasename
 // salt diverged from the running child's argv.
 const PlatformPidFile = ".seq"
 
+// CrashDirName is the daemon-home directory the workers' crash reports land
+// in (package crash). Neutral and dot-hidden like the pidfile.
+const CrashDirName = ".cr"
+
+// APICacheFile is the basename (in the daemon-home) of the release-API ETag
+// cache `daemon update` keeps so repeated checks revalidate instead of
+// spending the unauthenticated rate limit. Its content is masked like the
+// version state (MaskState) — release JSON names the repo, a grep hook.
+const APICacheFile = ".etag"
+
+// APICachePath is where the release-API ETag cache lives.
+func (s *Store) APICachePath() string { return filepath.Join(s.Dir, APICacheFile) }
+
+// MaskState / UnmaskState expose the FEATURE-26 salt-keyed mask for other
+// daemon-home files whose plaintext would be greppable. No salt ⇒ identity.
+func (s *Store) MaskState(b []byte) []byte { return s.maskVer(b) }
+
+func (s *Store) UnmaskState(b []byte) []byte {
+	data, _ := s.unmaskVer(b)
+	return data
+}
+
 type versionConfig struct {
 	Desired string `json:"desired"`
+	// Channel is the update channel a no-argument `daemon update` resolves
+	// against (ChannelStable/Beta/Pinned). Omitted when never set, so a
+	// pre-channel version.json reads as the stable default.
+	Channel string `json:"channel,omitempty"`
+	// Mirror / Proxy are the operator's download-network overrides (release
+	// mirror base URL, proxy URL). Persisted here rather than in the plist so
+	// every mesh member and every rotated generation picks them up; omitted
+	// when unset ⇒ github.com and the process environment.
+	Mirror string `json:"mirror,omitempty"`
+	Proxy  string `json:"proxy,omitempty"`
+	// Window is the update maintenance window ("HH:MM-HH:MM", local time)
+	// outside which a running platform is not swapped to a new desired
+	// version. Omitted ⇒ always open.
+	Window string `json:"window,omitempty"`
+	// Webhooks are the notification hooks, each "FORMAT=URL" (see package
+	// notify). Kept in the masked file because a hook URL is a credential.
+	Webhooks []string `json:"webhooks,omitempty"`
+	// OTLP is the base OTLP/HTTP endpoint spans are exported to (see package
+	// tracing); the daemon also hands it to the platform child. Omitted ⇒
+	// the OTEL_EXPORTER_OTLP_* environment, else tracing off.
+	OTLP string `json:"otlp,omitempty"`
+	// Heartbeat is the focusd server endpoint the lock holder POSTs its
+	// signed dead-man heartbeat to (see package heartbeat); HeartbeatKey is
+	// the base64 32-byte Ed25519 seed that signs it. The key outlives a
+	// cleared endpoint so re-enabling keeps the device's enrolled identity.
+	Heartbeat    string `json:"heartbeat,omitempty"`
+	HeartbeatKey string `json:"heartbeat_key,omitempty"`
+	// API is the loopback address the platform serves its admin API on
+	// (`daemon api`); APIToken is the bearer token clients present. Omitted
+	// ⇒ not served.
+	API      string `json:"api,omitempty"`
+	APIToken string `json:"api_token,omitempty"`
+	// APIDebug adds the profiling routes (pprof, runtime stats) to the
+	// platform's unix admin socket (`daemon api --debug on`). Never on TCP.
+	APIDebug bool `json:"api_debug,omitempty"`
+	// Calendar is the ICS URL whose tagged entries switch the platform's
+	// calendar profile on (`daemon calendar`). A private calendar URL is a
+	// credential, so it lives here.
+	Calendar string `json:"calendar,omitempty"`
+	// HungAfter is how long, in seconds, the companion heartbeat may stay
+	// stale while the mesh is loaded before the workers count as hung and
+	// are restarted (`daemon notify --hung-after`). 0 ⇒ DefaultHungAfter.
+	HungAfter int64 `json:"hung_after_s,omitempty"`
+	// Decoys are the decoy launchd entries installed beside the mesh
+	// (FEATURE 10); DecoyTighten makes removing one extend the strict lock
+	// (`daemon decoys --tighten`).
+	Decoys       []Decoy `json:"decoys,omitempty"`
+	DecoyTighten bool    `json:"decoy_tighten,omitempty"`
+	// Nice is the mesh's scheduling nice value (`daemon priority`); nil ⇒
+	// DefaultNice.
+	Nice *int `json:"nice,omitempty"`
+	// LogLevel / LogFormat are the mesh's and the platform child's log
+	// level (debug|info|warn|error) and encoding (text|json), set with
+	// `daemon logging`. Omitted ⇒ each process's built-in default.
+	LogLevel  string `json:"log_level,omitempty"`
+	LogFormat string `json:"log_format,omitempty"`
+	// LogSink adds a sink beside the log files (`daemon logging --sink`):
+	// "syslog" also writes to the system log. Omitted ⇒ the files alone.
+	LogSink string `json:"log_sink,omitempty"`
+	// LogWindow is a temporary log level for the mesh (`daemon
+	// log-level`); nil ⇒ none.
+	LogWindow *LogWindow `json:"log_window,omitempty"`
+	// LogMode is the daemon log's file mode (`daemon config set
+	// log.mode`); 0 ⇒ DefaultLogMode.
+	LogMode uint32 `json:"log_mode,omitempty"`
+	// RotateEvery is how often, in seconds, the mesh moves itself to a
+	// fresh disguise (`daemon config set identity.rotate-every`); 0 ⇒ never,
+	// the default. RotatedAt is the last rotation, or when the schedule
+	// started, in unix seconds.
+	RotateEvery int64 `json:"rotate_every_s,omitempty"`
+	RotatedAt   int64 `json:"rotated_at,omitempty"`
+	// Lock is the strict-mode lock (`daemon lock`). Kept here, in the file
+	// the daemon cannot run without, so deleting it is not a way out.
+	Lock *lockin.Lock `json:"lock,omitempty"`
+	// Clock is the tamper-resistant clock the time gates read (package
+	// trustclock); NTP is the time server it syncs with (`daemon config
+	// set clock.ntp`), "" for none.
+	Clock *trustclock.Clock `json:"clock,omitempty"`
+	NTP   string            `json:"ntp,omitempty"`
+	// Gate is the uninstall gate's progress, HMAC-sealed so a hand edit
+	// resets it (package uninstallgate).
+	Gate *uninstallgate.Sealed `json:"uninstall_gate,omitempty"`
+	// Passages is where the gate's transcription passages come from (`daemon
+	// config set uninstall.passages`): a file path or an https URL, "" for
+	// the built-in ones.
+	Passages string `json:"passages,omitempty"`
+	// Partner is the accountability partner's webhook (FORMAT=URL, as
+	// notify.ParseHook reads it): with one set, uninstall also waits on a
+	// code sent there. "" for none.
+	Partner string `json:"partner,omitempty"`
+	// Recovery is the emergency recovery code's record (`daemon uninstall
+	// --recovery-code`).
+	Recovery *Recovery `json:"recovery,omitempty"`
+	// ShipURL and ShipKey are where the platform ships its event stream and
+	// the base64 X25519 key it seals each batch to (`daemon config set
+	// log.ship` / `log.ship-key`). Shipping runs only with both set.
+	ShipURL string `json:"ship_url,omitempty"`
+	ShipKey string `json:"ship_key,omitempty"`
+	// CrashDSN is the Sentry-compatible DSN crash reports are submitted to
+	// (`daemon config set crash.report`); "" keeps them local.
+	CrashDSN string `json:"crash_dsn,omitempty"`
+	// Crashes are the latest swept crashes, oldest first, at most
+	// MaxCrashes.
+	Crashes []Crash `json:"crashes,omitempty"`
+}
+
+// Recovery is the one-time emergency code issued at install: its hash
+// while unused, the install salt the hash was made under, when it was
+// issued, and when it was used. A code is issued once per install and
+// never again, used or not; the salt travels with the record so a
+// reinstall under a fresh salt still checks it.
+type Recovery struct {
+	Hash   string    `json:"hash,omitempty"`
+	Salt   string    `json:"salt,omitempty"`
+	Issued time.Time `json:"issued"`
+	Used   time.Time `json:"used,omitempty"`
+}
+
+// Update channels. Only a no-argument `daemon update` consults the channel —
+// the reconcile loop never resolves "latest" on its own, so a channel can
+// never move the running version without an operator action.
+const (
+	ChannelStable = "stable" // GitHub's "latest" release (pre-releases excluded)
+	ChannelBeta   = "beta"   // newest published release, pre-releases included
+	ChannelPinned = "pinned" // never resolve; only an explicit tag moves desired
+)
+
+// ValidChannel reports whether c names a known update channel.
+func ValidChannel(c string) bool {
+	switch c {
+	case ChannelStable, ChannelBeta, ChannelPinned:
+		return true
+	}
+	return false
 }
 
 // FEATURE 26 (bundle 4) — version grep-hook mask.
@@ -188,6 +349,16 @@ func (s *Store) stateDBPath() string {
 	return filepath.Join(s.platformRoot(), PlatformStateDBName)
 }
 
+// PlatformSocketName is the platform's admin socket beside state.db. MUST
+// match platform adminapi.SocketName.
+const PlatformSocketName = "svc.sock"
+
+// AdminSocketPath is the platform's admin socket (platform-workdir, like
+// state.db).
+func (s *Store) AdminSocketPath() string {
+	return filepath.Join(s.platformRoot(), PlatformSocketName)
+}
+
 // WorkdirIntact reports whether the PLATFORM-workdir is present on disk AND
 // initialised: the platform-workdir directory exists and the platform's state DB
 // is present inside it. A false result while a platform process still claims to
@@ -353,30 +524,679 @@ func (s *Store) HaveConfig() bool {
 	return err == nil
 }
 
-// Desired returns the configured desired version ("" if none). It un-masks a
-// FEATURE-26 masked version.json and still accepts a legacy plaintext one.
-func (s *Store) Desired() string {
+// VersionStateOK reports whether version.json opens with this install's key
+// and decodes: masked under the salt when one exists, plaintext only for a
+// legacy/test store without one. A missing file is not OK.
+func (s *Store) VersionStateOK() bool {
 	b, err := os.ReadFile(s.versionPath())
 	if err != nil {
-		return ""
+		return false
 	}
-	data, _ := s.unmaskVer(b) // masked → payload; legacy/plaintext → raw bytes
+	data, masked := s.unmaskVer(b)
+	if !masked && s.verMaskKey() != nil {
+		return false
+	}
+	var c versionConfig
+	return json.Unmarshal(data, &c) == nil
+}
+
+// readVersionConfig loads version.json. It un-masks a FEATURE-26 masked file
+// and still accepts a legacy plaintext one; a missing/garbled file reads as the
+// zero config.
+func (s *Store) readVersionConfig() versionConfig {
 	var c versionConfig
+	b, err := os.ReadFile(s.versionPath())
+	if err != nil {
+		return c
+	}
+	data, _ := s.unmaskVer(b) // masked → payload; legacy/plaintext → raw bytes
 	if json.Unmarshal(data, &c) != nil {
-		return ""
+		return versionConfig{}
 	}
-	return c.Desired
+	return c
 }
 
-// WriteDesired atomically records the desired version (masked when a salt exists).
-func (s *Store) WriteDesired(v string) error {
+func (s *Store) writeVersionConfig(c versionConfig) error {
 	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
 		return err
 	}
-	b, _ := json.Marshal(versionConfig{Desired: v})
+	b, _ := json.Marshal(c)
 	return atomicWrite(s.versionPath(), s.maskVer(b))
 }
 
+// versionLockFile is the basename of the file updateVersionConfig locks.
+// version.json itself cannot carry the lock: atomicWrite replaces its inode.
+const versionLockFile = VersionFile + ".lock"
+
+// updateVersionConfig applies fn to version.json under an exclusive lock, so
+// the daemon's per-minute clock and lock credit and a CLI's write (`daemon
+// lock`, `daemon config set`) cannot interleave and lose one of them. fn's
+// error aborts the write.
+func (s *Store) updateVersionConfig(fn func(c *versionConfig) error) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	var l FileLock
+	if err := l.Acquire(filepath.Join(s.Dir, versionLockFile)); err != nil {
+		return err
+	}
+	defer l.Release()
+	c := s.readVersionConfig()
+	if err := fn(&c); err != nil {
+		return err
+	}
+	return s.writeVersionConfig(c)
+}
+
+// Desired returns the configured desired version ("" if none).
+func (s *Store) Desired() string { return s.readVersionConfig().Desired }
+
+// WriteDesired atomically records the desired version (masked when a salt
+// exists), preserving the persisted update channel.
+func (s *Store) WriteDesired(v string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Desired = v
+		return nil
+	})
+}
+
+// Channel returns the persisted update channel, ChannelStable when none (or
+// an unknown value) was recorded.
+func (s *Store) Channel() string {
+	if c := s.readVersionConfig().Channel; ValidChannel(c) {
+		return c
+	}
+	return ChannelStable
+}
+
+// Network returns the persisted release mirror and proxy ("" when unset).
+// Values are validated by the CLI before WriteNetwork; the fetcher treats
+// them as opaque.
+func (s *Store) Network() (mirror, proxy string) {
+	c := s.readVersionConfig()
+	return c.Mirror, c.Proxy
+}
+
+// WriteNetwork persists the release mirror and proxy ("" clears either).
+func (s *Store) WriteNetwork(mirror, proxy string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Mirror, c.Proxy = mirror, proxy
+		return nil
+	})
+}
+
+// UpdateWindow returns the persisted update maintenance window. A missing or
+// unparsable value reads as the zero (always-open) Window, so a garbled file
+// can never wedge an update forever.
+func (s *Store) UpdateWindow() Window {
+	w, err := ParseWindow(s.readVersionConfig().Window)
+	if err != nil {
+		return Window{}
+	}
+	return w
+}
+
+// WriteUpdateWindow persists the update maintenance window (zero clears it).
+func (s *Store) WriteUpdateWindow(w Window) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Window = w.String()
+		return nil
+	})
+}
+
+// Webhooks returns the persisted notification hooks ("FORMAT=URL"), nil when
+// none. The CLI validates them before WriteWebhooks; readers re-parse and
+// skip anything malformed.
+func (s *Store) Webhooks() []string { return s.readVersionConfig().Webhooks }
+
+// WriteWebhooks replaces the notification hooks (nil clears them).
+func (s *Store) WriteWebhooks(hooks []string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Webhooks = hooks
+		return nil
+	})
+}
+
+// TraceEndpoint returns the persisted OTLP endpoint, "" when unset.
+func (s *Store) TraceEndpoint() string { return s.readVersionConfig().OTLP }
+
+// WriteTraceEndpoint persists the OTLP endpoint ("" clears it).
+func (s *Store) WriteTraceEndpoint(endpoint string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.OTLP = endpoint
+		return nil
+	})
+}
+
+// HeartbeatEndpoint returns the persisted heartbeat endpoint, "" when unset.
+func (s *Store) HeartbeatEndpoint() string { return s.readVersionConfig().Heartbeat }
+
+// HeartbeatSeed returns the heartbeat signing seed, nil when none has been
+// generated (or the stored one is garbled).
+func (s *Store) HeartbeatSeed() []byte {
+	seed, err := base64.StdEncoding.DecodeString(s.readVersionConfig().HeartbeatKey)
+	if err != nil || len(seed) != heartbeatSeedLen {
+		return nil
+	}
+	return seed
+}
+
+// heartbeatSeedLen is the Ed25519 seed size (ed25519.SeedSize).
+const heartbeatSeedLen = 32
+
+// WriteHeartbeatEndpoint persists the heartbeat endpoint ("" clears it). The
+// first non-empty write also generates the signing seed.
+func (s *Store) WriteHeartbeatEndpoint(endpoint string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Heartbeat = endpoint
+		if endpoint != "" && s.HeartbeatSeed() == nil {
+			seed, err := newHeartbeatSeed()
+			if err != nil {
+				return err
+			}
+			c.HeartbeatKey = base64.StdEncoding.EncodeToString(seed)
+		}
+		return nil
+	})
+}
+
+// DeviceSeed returns the heartbeat signing seed, generating and persisting
+// one when there is none. The published status signs with it too, so a
+// system install that never configured a heartbeat still gets a key.
+func (s *Store) DeviceSeed() ([]byte, error) {
+	if seed := s.HeartbeatSeed(); seed != nil {
+		return seed, nil
+	}
+	seed, err := newHeartbeatSeed()
+	if err != nil {
+		return nil, err
+	}
+	return seed, s.updateVersionConfig(func(c *versionConfig) error {
+		c.HeartbeatKey = base64.StdEncoding.EncodeToString(seed)
+		return nil
+	})
+}
+
+func newHeartbeatSeed() ([]byte, error) {
+	seed := make([]byte, heartbeatSeedLen)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// AdminAPI returns the admin API address and token, "" addr when off.
+func (s *Store) AdminAPI() (addr, token string) {
+	c := s.readVersionConfig()
+	if c.API == "" || c.APIToken == "" {
+		return "", ""
+	}
+	return c.API, c.APIToken
+}
+
+// WriteAdminAPI persists the admin API address ("" turns it off and drops
+// the token). A token is generated when there is none, or when rotate.
+func (s *Store) WriteAdminAPI(addr string, rotate bool) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.API = addr
+		switch {
+		case addr == "":
+			c.APIToken = ""
+		case c.APIToken == "" || rotate:
+			buf := make([]byte, 32)
+			if _, err := rand.Read(buf); err != nil {
+				return err
+			}
+			c.APIToken = base64.RawURLEncoding.EncodeToString(buf)
+		}
+		return nil
+	})
+}
+
+// AdminDebug reports whether the admin socket serves the debug routes.
+func (s *Store) AdminDebug() bool { return s.readVersionConfig().APIDebug }
+
+// WriteAdminDebug turns the admin socket's debug routes on or off.
+func (s *Store) WriteAdminDebug(on bool) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.APIDebug = on
+		return nil
+	})
+}
+
+// Logging returns the persisted log level and format, "" for each unset.
+func (s *Store) Logging() (level, format string) {
+	c := s.readVersionConfig()
+	return c.LogLevel, c.LogFormat
+}
+
+// WriteLogging persists the log level and format ("" clears one).
+func (s *Store) WriteLogging(level, format string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.LogLevel, c.LogFormat = level, format
+		return nil
+	})
+}
+
+// LogSink returns the persisted extra log sink, "" for none.
+func (s *Store) LogSink() string { return s.readVersionConfig().LogSink }
+
+// WriteLogSink persists the extra log sink ("" clears it).
+func (s *Store) WriteLogSink(sink string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.LogSink = sink
+		return nil
+	})
+}
+
+// LogWindow is a log level the mesh runs at until Until, when it falls back
+// to its configured one.
+type LogWindow struct {
+	Level string    `json:"level"`
+	Until time.Time `json:"until"`
+}
+
+// Open reports whether the window still applies at now.
+func (w LogWindow) Open(now time.Time) bool { return w.Level != "" && now.Before(w.Until) }
+
+// LogWindow returns the persisted log window, zero when none is set.
+func (s *Store) LogWindow() LogWindow {
+	if w := s.readVersionConfig().LogWindow; w != nil {
+		return *w
+	}
+	return LogWindow{}
+}
+
+// WriteLogWindow persists the log window; the zero window clears it.
+func (s *Store) WriteLogWindow(w LogWindow) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.LogWindow = nil
+		if w.Level != "" {
+			c.LogWindow = &w
+		}
+		return nil
+	})
+}
+
+// DefaultLogMode keeps the daemon log to its owner: it names versions,
+// hosts and failures a second local user has no business reading.
+const DefaultLogMode os.FileMode = 0o600
+
+// LogModes are the file modes the daemon log may be given: owner read-write
+// always, never writable or executable by anyone else.
+var LogModes = []os.FileMode{0o600, 0o640, 0o644}
+
+// LogMode returns the daemon log's file mode.
+func (s *Store) LogMode() os.FileMode {
+	if m := os.FileMode(s.readVersionConfig().LogMode); slices.Contains(LogModes, m) {
+		return m
+	}
+	return DefaultLogMode
+}
+
+// WriteLogMode persists the daemon log's file mode; 0 restores the default.
+func (s *Store) WriteLogMode(m os.FileMode) error {
+	if m != 0 && !slices.Contains(LogModes, m) {
+		return fmt.Errorf("log mode must be one of %04o, %04o or %04o", LogModes[0], LogModes[1], LogModes[2])
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.LogMode = uint32(m)
+		return nil
+	})
+}
+
+// CalendarURL returns the persisted calendar ICS URL, "" when unset.
+func (s *Store) CalendarURL() string { return s.readVersionConfig().Calendar }
+
+// WriteCalendarURL persists the calendar ICS URL ("" clears it).
+func (s *Store) WriteCalendarURL(u string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Calendar = u
+		return nil
+	})
+}
+
+// Decoy is one decoy launchd entry: its label and the stand-in binary its
+// plist names. Kept only in the masked version.json — the one record of
+// which look-alike entries are not the mesh.
+type Decoy struct {
+	Label string `json:"label"`
+	Bin   string `json:"bin"`
+}
+
+// Decoys returns the recorded decoy entries, nil before the first are made.
+func (s *Store) Decoys() []Decoy { return s.readVersionConfig().Decoys }
+
+// WriteDecoys replaces the recorded decoy entries.
+func (s *Store) WriteDecoys(d []Decoy) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Decoys = d
+		return nil
+	})
+}
+
+// DecoyTighten reports whether a removed decoy extends the strict lock.
+func (s *Store) DecoyTighten() bool { return s.readVersionConfig().DecoyTighten }
+
+// WriteDecoyTighten turns decoy-removal tightening on or off.
+func (s *Store) WriteDecoyTighten(on bool) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.DecoyTighten = on
+		return nil
+	})
+}
+
+// DefaultHungAfter / MinHungAfter bound the hung-worker threshold. The
+// floor sits well above the 15s heartbeat cadence and the companion's 30s
+// stale mark, so a slow tick is never mistaken for a hang.
+const (
+	DefaultHungAfter = 2 * time.Minute
+	MinHungAfter     = time.Minute
+)
+
+// HungAfter returns the configured hung-worker threshold.
+func (s *Store) HungAfter() time.Duration {
+	if n := s.readVersionConfig().HungAfter; n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return DefaultHungAfter
+}
+
+// WriteHungAfter persists the hung-worker threshold; 0 restores the default.
+func (s *Store) WriteHungAfter(d time.Duration) error {
+	if d != 0 && d < MinHungAfter {
+		return fmt.Errorf("hung-after must be at least %s", MinHungAfter)
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.HungAfter = int64(d / time.Second)
+		return nil
+	})
+}
+
+// MinRotateEvery bounds the automatic identity rotation, which is off
+// until asked for: never so often that the swap's protection blip becomes
+// routine.
+const MinRotateEvery = 24 * time.Hour
+
+// RotateEvery returns the automatic identity rotation period; 0 is off.
+func (s *Store) RotateEvery() time.Duration {
+	return time.Duration(s.readVersionConfig().RotateEvery) * time.Second
+}
+
+// WriteRotateEvery persists the rotation period: 0 turns rotation off,
+// anything else must be at least MinRotateEvery. Turning it on starts a
+// fresh schedule, so an old stamp does not make the first rotation due at
+// once.
+func (s *Store) WriteRotateEvery(d time.Duration) error {
+	if d != 0 && d < MinRotateEvery {
+		return fmt.Errorf("rotate-every must be at least %s, or 0 for never", MinRotateEvery)
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		if c.RotateEvery == 0 {
+			c.RotatedAt = 0
+		}
+		c.RotateEvery = int64(d / time.Second)
+		return nil
+	})
+}
+
+// RotatedAt returns when the identity last rotated, zero when never
+// recorded.
+func (s *Store) RotatedAt() time.Time {
+	if n := s.readVersionConfig().RotatedAt; n > 0 {
+		return time.Unix(n, 0)
+	}
+	return time.Time{}
+}
+
+// WriteRotatedAt records t as the last rotation.
+func (s *Store) WriteRotatedAt(t time.Time) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.RotatedAt = t.Unix()
+		return nil
+	})
+}
+
+// DefaultNice keeps the mesh, the platform and its scans behind foreground
+// work, while the companion (nice 0) stays ahead of them.
+const DefaultNice = 5
+
+// MaxNice is the highest nice value: the lowest priority.
+const MaxNice = 19
+
+// Nice returns the configured mesh nice value, DefaultNice when unset.
+func (s *Store) Nice() int {
+	if n := s.readVersionConfig().Nice; n != nil {
+		return *n
+	}
+	return DefaultNice
+}
+
+// WriteNice persists the mesh nice value. Only 0..MaxNice: a negative value
+// needs root and would let the mesh outrank the companion.
+func (s *Store) WriteNice(n int) error {
+	if n < 0 || n > MaxNice {
+		return fmt.Errorf("nice must be 0..%d", MaxNice)
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Nice = &n
+		return nil
+	})
+}
+
+// StrictLock returns the persisted strict-mode lock, the zero Lock when none.
+func (s *Store) StrictLock() lockin.Lock {
+	if l := s.readVersionConfig().Lock; l != nil {
+		return *l
+	}
+	return lockin.Lock{}
+}
+
+// UpdateStrictLock replaces the lock with fn applied to the current one.
+func (s *Store) UpdateStrictLock(fn func(lockin.Lock) lockin.Lock) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		var cur lockin.Lock
+		if c.Lock != nil {
+			cur = *c.Lock
+		}
+		next := fn(cur)
+		c.Lock = &next
+		return nil
+	})
+}
+
+// Clock returns the persisted trusted clock, the zero Clock when never
+// credited.
+func (s *Store) Clock() trustclock.Clock {
+	if c := s.readVersionConfig().Clock; c != nil {
+		return *c
+	}
+	return trustclock.Clock{}
+}
+
+// UpdateClock replaces the clock with fn applied to the current one.
+func (s *Store) UpdateClock(fn func(trustclock.Clock) trustclock.Clock) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		var cur trustclock.Clock
+		if c.Clock != nil {
+			cur = *c.Clock
+		}
+		next := fn(cur)
+		c.Clock = &next
+		return nil
+	})
+}
+
+// Now is the trusted time: what every time gate compares against instead
+// of the system clock.
+func (s *Store) Now() time.Time { return s.Clock().Now(time.Now()) }
+
+// UninstallGate returns the uninstall gate's progress as of now, the zero
+// State (step 1) when none is recorded or the seal does not verify.
+func (s *Store) UninstallGate(now time.Time) uninstallgate.State {
+	if g := s.readVersionConfig().Gate; g != nil {
+		return uninstallgate.Unseal(*g, now)
+	}
+	return uninstallgate.State{}
+}
+
+// HasUninstallGate reports whether any gate progress is recorded.
+func (s *Store) HasUninstallGate() bool { return s.readVersionConfig().Gate != nil }
+
+// WriteUninstallGate records the gate's progress.
+func (s *Store) WriteUninstallGate(g uninstallgate.State) error {
+	sealed, err := uninstallgate.Seal(g)
+	if err != nil {
+		return err
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Gate = &sealed
+		return nil
+	})
+}
+
+// ClearUninstallGate drops the gate's progress (`daemon uninstall --abort`).
+func (s *Store) ClearUninstallGate() error {
+	if !s.HasUninstallGate() {
+		return nil
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Gate = nil
+		return nil
+	})
+}
+
+// Passages returns the uninstall gate's passage source, "" for the
+// built-in passages.
+func (s *Store) Passages() string { return s.readVersionConfig().Passages }
+
+// WritePassages persists the passage source; "" restores the built-ins.
+func (s *Store) WritePassages(src string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Passages = src
+		return nil
+	})
+}
+
+// Recovery returns the recovery code's record; the zero Recovery when none
+// was ever issued.
+func (s *Store) Recovery() Recovery {
+	if r := s.readVersionConfig().Recovery; r != nil {
+		return *r
+	}
+	return Recovery{}
+}
+
+// WriteRecovery records the recovery code's state.
+func (s *Store) WriteRecovery(r Recovery) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Recovery = &r
+		return nil
+	})
+}
+
+// LogShip returns the event-shipping URL and the recipient's public key.
+func (s *Store) LogShip() (url, key string) {
+	c := s.readVersionConfig()
+	return c.ShipURL, c.ShipKey
+}
+
+// WriteLogShip persists the event-shipping URL and key; "" clears either.
+func (s *Store) WriteLogShip(url, key string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.ShipURL, c.ShipKey = url, key
+		return nil
+	})
+}
+
+// CrashDir is where the workers' crash reports land.
+func (s *Store) CrashDir() string { return filepath.Join(s.Dir, CrashDirName) }
+
+// CrashDSN returns the crash-report DSN, "" for none.
+func (s *Store) CrashDSN() string { return s.readVersionConfig().CrashDSN }
+
+// WriteCrashDSN persists the crash-report DSN; "" stops submitting.
+func (s *Store) WriteCrashDSN(dsn string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.CrashDSN = dsn
+		return nil
+	})
+}
+
+// Crash is one swept crash as status sees it: when, and the redacted first
+// line of the runtime's output.
+type Crash struct {
+	At      time.Time `json:"at"`
+	Kind    string    `json:"kind"`
+	Summary string    `json:"summary,omitempty"`
+}
+
+// MaxCrashes is how many crashes the store remembers.
+const MaxCrashes = 20
+
+// Crashes returns the remembered crashes, oldest first.
+func (s *Store) Crashes() []Crash { return s.readVersionConfig().Crashes }
+
+// RecordCrashes appends cs, keeping the latest MaxCrashes.
+func (s *Store) RecordCrashes(cs ...Crash) error {
+	if len(cs) == 0 {
+		return nil
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Crashes = append(c.Crashes, cs...)
+		if n := len(c.Crashes) - MaxCrashes; n > 0 {
+			c.Crashes = c.Crashes[n:]
+		}
+		return nil
+	})
+}
+
+// CrashesSince counts the remembered crashes at or after t.
+func CrashesSince(cs []Crash, t time.Time) int {
+	n := 0
+	for _, c := range cs {
+		if !c.At.Before(t) {
+			n++
+		}
+	}
+	return n
+}
+
+// Partner returns the accountability partner's webhook, "" for none.
+func (s *Store) Partner() string { return s.readVersionConfig().Partner }
+
+// WritePartner persists the partner's webhook; "" removes it.
+func (s *Store) WritePartner(hook string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Partner = hook
+		return nil
+	})
+}
+
+// NTP returns the time server the clock syncs with, "" for none.
+func (s *Store) NTP() string { return s.readVersionConfig().NTP }
+
+// WriteNTP persists the time server; "" turns syncing off.
+func (s *Store) WriteNTP(server string) error {
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.NTP = server
+		return nil
+	})
+}
+
+// WriteChannel persists the update channel alongside the desired version.
+// It refuses an unknown channel rather than recording something Channel()
+// would silently read back as stable.
+func (s *Store) WriteChannel(ch string) error {
+	if !ValidChannel(ch) {
+		return fmt.Errorf("unknown update channel %q", ch)
+	}
+	return s.updateVersionConfig(func(c *versionConfig) error {
+		c.Channel = ch
+		return nil
+	})
+}
+
 // Good / WriteGood track the last-known-good version (masked content, FEATURE 26).
 func (s *Store) Good() string {
 	b, err := os.ReadFile(s.goodPath())
@@ -1,10 +1,17 @@
 package core
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/lockin"
+	"github.com/eliteGoblin/focusd/daemon/internal/trustclock"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
 )
 
 func TestStoreRoundtrips(t *testing.T) {
@@ -192,3 +199,291 @@ func TestAtomicWriteCreatesDirs(t *testing.T) {
 		t.Fatalf("write good through nested dirs failed: %v", err)
 	}
 }
+
+// TestStoreChannel: the update channel rides in version.json next to the
+// desired version — each write preserves the other, a fresh or pre-channel
+// store reads as stable, and an unknown channel is refused.
+func TestStoreChannel(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if got := s.Channel(); got != ChannelStable {
+		t.Fatalf("fresh store channel = %q, want %q", got, ChannelStable)
+	}
+	if err := s.WriteDesired("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteChannel(ChannelBeta); err != nil {
+		t.Fatal(err)
+	}
+	if s.Channel() != ChannelBeta || s.Desired() != "v1.0.0" {
+		t.Fatalf("after WriteChannel: channel=%q desired=%q", s.Channel(), s.Desired())
+	}
+	if err := s.WriteDesired("v1.1.0-rc.1"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Channel() != ChannelBeta || s.Desired() != "v1.1.0-rc.1" {
+		t.Fatalf("WriteDesired dropped channel: channel=%q desired=%q", s.Channel(), s.Desired())
+	}
+	if err := s.WriteChannel("nightly"); err == nil {
+		t.Fatal("WriteChannel accepted an unknown channel")
+	}
+	if s.Channel() != ChannelBeta {
+		t.Fatalf("rejected write changed channel to %q", s.Channel())
+	}
+
+	// A pre-channel plaintext version.json still reads as stable.
+	legacy := &Store{Dir: t.TempDir()}
+	if err := os.WriteFile(filepath.Join(legacy.Dir, VersionFile), []byte(`{"desired":"v0.9.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Channel() != ChannelStable || legacy.Desired() != "v0.9.0" {
+		t.Fatalf("legacy: channel=%q desired=%q", legacy.Channel(), legacy.Desired())
+	}
+}
+
+func TestStoreNetwork(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if m, p := s.Network(); m != "" || p != "" {
+		t.Fatalf("fresh store network = %q, %q", m, p)
+	}
+	if err := s.WriteDesired("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteNetwork("https://mirror.example.com", "http://proxy:3128"); err != nil {
+		t.Fatal(err)
+	}
+	if m, p := s.Network(); m != "https://mirror.example.com" || p != "http://proxy:3128" || s.Desired() != "v1.0.0" {
+		t.Fatalf("network roundtrip: %q, %q (desired %q)", m, p, s.Desired())
+	}
+	if err := s.WriteDesired("v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := s.Network(); m == "" {
+		t.Fatal("WriteDesired dropped the mirror")
+	}
+	if err := s.WriteNetwork("", ""); err != nil {
+		t.Fatal(err)
+	}
+	if m, p := s.Network(); m != "" || p != "" {
+		t.Fatalf("cleared network = %q, %q", m, p)
+	}
+}
+
+func TestStoreWebhooks(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if s.Webhooks() != nil {
+		t.Fatal("fresh store should have no webhooks")
+	}
+	if err := s.WriteDesired("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	hooks := []string{"slack=https://hooks.slack.com/services/T/B/X", "ntfy=https://ntfy.sh/t"}
+	if err := s.WriteWebhooks(hooks); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Webhooks(); len(got) != 2 || got[1] != hooks[1] || s.Desired() != "v1.0.0" {
+		t.Fatalf("webhooks roundtrip = %v (desired %q)", got, s.Desired())
+	}
+	if err := s.WriteWebhooks(nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Webhooks() != nil {
+		t.Fatal("cleared webhooks still present")
+	}
+}
+
+func TestStoreTraceEndpoint(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if err := s.WriteWebhooks([]string{"json=https://h.example/x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteTraceEndpoint("http://localhost:4318"); err != nil {
+		t.Fatal(err)
+	}
+	if s.TraceEndpoint() != "http://localhost:4318" || len(s.Webhooks()) != 1 {
+		t.Fatalf("endpoint = %q, webhooks = %v", s.TraceEndpoint(), s.Webhooks())
+	}
+	if err := s.WriteTraceEndpoint(""); err != nil || s.TraceEndpoint() != "" {
+		t.Fatalf("clear: err=%v endpoint=%q", err, s.TraceEndpoint())
+	}
+}
+
+func TestStoreHeartbeatKeyOutlivesEndpoint(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if s.HeartbeatSeed() != nil {
+		t.Fatal("seed before any endpoint was set")
+	}
+	if err := s.WriteHeartbeatEndpoint("https://hb.example/v1/beat"); err != nil {
+		t.Fatal(err)
+	}
+	seed := s.HeartbeatSeed()
+	if len(seed) != 32 || s.HeartbeatEndpoint() != "https://hb.example/v1/beat" {
+		t.Fatalf("seed len %d, endpoint %q", len(seed), s.HeartbeatEndpoint())
+	}
+	if err := s.WriteHeartbeatEndpoint(""); err != nil || s.HeartbeatEndpoint() != "" {
+		t.Fatalf("clear: err=%v endpoint=%q", err, s.HeartbeatEndpoint())
+	}
+	if err := s.WriteHeartbeatEndpoint("https://hb.example/v2/beat"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.HeartbeatSeed(), seed) {
+		t.Fatal("re-enabling the heartbeat rotated the device key")
+	}
+}
+
+func TestStoreDeviceSeedGeneratedOnceAndShared(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	seed, err := s.DeviceSeed()
+	if err != nil || len(seed) != 32 {
+		t.Fatalf("seed len %d, err %v", len(seed), err)
+	}
+	again, err := s.DeviceSeed()
+	if err != nil || !bytes.Equal(again, seed) {
+		t.Fatal("a second DeviceSeed rotated the key")
+	}
+	if err := s.WriteHeartbeatEndpoint("https://hb.example/v1/beat"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.HeartbeatSeed(), seed) {
+		t.Fatal("the heartbeat signs with a different key than the device seed")
+	}
+}
+
+func TestStoreAdminAPIToken(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if err := s.WriteAdminAPI("127.0.0.1:7600", false); err != nil {
+		t.Fatal(err)
+	}
+	addr, tok := s.AdminAPI()
+	if addr != "127.0.0.1:7600" || len(tok) < 40 {
+		t.Fatalf("addr %q, token %q", addr, tok)
+	}
+	if err := s.WriteAdminAPI("127.0.0.1:7601", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, again := s.AdminAPI(); again != tok {
+		t.Fatal("changing the address rotated the token")
+	}
+	if err := s.WriteAdminAPI("127.0.0.1:7601", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, rotated := s.AdminAPI(); rotated == tok || rotated == "" {
+		t.Fatal("--rotate kept the old token")
+	}
+	if err := s.WriteAdminAPI("", false); err != nil {
+		t.Fatal(err)
+	}
+	if addr, tok := s.AdminAPI(); addr != "" || tok != "" {
+		t.Fatalf("off: addr %q, token %q", addr, tok)
+	}
+}
+
+func TestStoreUpdateWindow(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if !s.UpdateWindow().IsZero() {
+		t.Fatal("fresh store should have the always-open window")
+	}
+	w, _ := ParseWindow("02:00-05:00")
+	if err := s.WriteUpdateWindow(w); err != nil {
+		t.Fatal(err)
+	}
+	if s.UpdateWindow() != w {
+		t.Fatalf("window roundtrip = %+v", s.UpdateWindow())
+	}
+	if err := s.WriteUpdateWindow(Window{}); err != nil {
+		t.Fatal(err)
+	}
+	if !s.UpdateWindow().IsZero() {
+		t.Fatal("zero window should clear")
+	}
+}
+
+func TestStoreUninstallGate(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	now := time.Now()
+	if s.HasUninstallGate() || s.UninstallGate(now).Step != 0 {
+		t.Fatal("fresh store should have no gate progress")
+	}
+	if err := s.WriteUninstallGate(uninstallgate.State{Step: 1, T1: now, LastSeen: now}); err != nil {
+		t.Fatal(err)
+	}
+	if g := s.UninstallGate(now); g.Step != 1 || g.T1.Unix() != now.Unix() {
+		t.Fatalf("gate roundtrip = %+v", g)
+	}
+
+	// Hand-edited progress fails the seal and starts over.
+	c := s.readVersionConfig()
+	c.Gate.State = []byte(`{"step":3,"last_seen":"2020-01-01T00:00:00Z"}`)
+	if err := s.writeVersionConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if g := s.UninstallGate(now); g.Step != 0 {
+		t.Fatalf("tampered gate honoured: %+v", g)
+	}
+
+	if err := s.ClearUninstallGate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.HasUninstallGate() {
+		t.Fatal("abort should clear the gate")
+	}
+}
+
+// TestStoreCrashes: crashes append oldest first, the store keeps the latest
+// MaxCrashes, and CrashesSince counts a window.
+func TestStoreCrashes(t *testing.T) {
+	st := &Store{Dir: t.TempDir()}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range MaxCrashes + 5 {
+		if err := st.RecordCrashes(Crash{At: base.Add(time.Duration(i) * time.Hour), Kind: "panic"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cs := st.Crashes()
+	if len(cs) != MaxCrashes || !cs[0].At.Equal(base.Add(5*time.Hour)) {
+		t.Fatalf("kept %d, oldest %v", len(cs), cs[0].At)
+	}
+	if n := CrashesSince(cs, base.Add(22*time.Hour)); n != 3 {
+		t.Fatalf("since = %d, want 3", n)
+	}
+}
+
+// TestStoreConcurrentUpdatesLoseNothing: the daemon's clock and lock credit
+// and a CLI's lock extension each read, change and rewrite version.json;
+// run side by side, none of them may overwrite another with a stale copy.
+func TestStoreConcurrentUpdatesLoseNothing(t *testing.T) {
+	dir := t.TempDir()
+	const n = 40
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			st := &Store{Dir: dir}
+			if err := st.UpdateStrictLock(func(l lockin.Lock) lockin.Lock { l.Ran++; return l }); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			st := &Store{Dir: dir}
+			if err := st.UpdateClock(func(c trustclock.Clock) trustclock.Clock { c.Allow++; return c }); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			st := &Store{Dir: dir}
+			if err := st.UpdateStrictLock(func(l lockin.Lock) lockin.Lock { l.Length++; return l }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	st := &Store{Dir: dir}
+	if l := st.StrictLock(); l.Ran != n || l.Length != n {
+		t.Fatalf("lock ran %d length %d, want %d each", l.Ran, l.Length, n)
+	}
+	if c := st.Clock(); c.Allow != n {
+		t.Fatalf("clock allow %d, want %d", c.Allow, n)
+	}
+}
@@ -144,3 +144,27 @@ func TestNoSaltKeepsLegacyLayout(t *testing.T) {
 		t.Fatalf("no-salt bad marker must use the legacy sanitised name: %v", err)
 	}
 }
+
+// TestVersionStateOK: version.json must open with the install's key; a
+// plaintext or foreign-keyed file in a salted store is not intact.
+func TestVersionStateOK(t *testing.T) {
+	s := saltedStore(t)
+	if s.VersionStateOK() {
+		t.Fatal("missing version.json reported intact")
+	}
+	if err := s.WriteDesired("v0.16.7"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.VersionStateOK() {
+		t.Fatal("freshly written version.json not intact")
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, VersionFile), []byte(`{"desired":"v0.16.7"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if s.VersionStateOK() {
+		t.Fatal("plaintext version.json in a salted store reported intact")
+	}
+	if legacy := (&Store{Dir: t.TempDir()}); legacy.WriteDesired("v0.16.7") != nil || !legacy.VersionStateOK() {
+		t.Fatal("legacy plaintext store without a salt must read intact")
+	}
+}
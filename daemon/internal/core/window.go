@@ -0,0 +1,84 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a daily local-time span during which the reconcile loop may SWAP
+// a running platform for a newly-desired version. A swap is a stop/start, so
+// protection briefly drops; an operator can confine that to a quiet hour
+// (e.g. 02:00-05:00) instead of whenever `daemon update` happened to run.
+//
+// Start/End are offsets from local midnight. End < Start wraps past midnight
+// (22:00-02:00). The zero Window is "always open" — today's behaviour.
+type Window struct {
+	Start, End time.Duration
+}
+
+// IsZero reports whether w is the always-open default.
+func (w Window) IsZero() bool { return w.Start == 0 && w.End == 0 }
+
+// Contains reports whether t (in its own location) falls inside w.
+func (w Window) Contains(t time.Time) bool {
+	if w.IsZero() {
+		return true
+	}
+	y, m, d := t.Date()
+	off := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	if w.Start <= w.End {
+		return off >= w.Start && off < w.End
+	}
+	return off >= w.Start || off < w.End
+}
+
+// String renders w in the "HH:MM-HH:MM" form ParseWindow accepts ("" when zero).
+func (w Window) String() string {
+	if w.IsZero() {
+		return ""
+	}
+	hm := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return hm(w.Start) + "-" + hm(w.End)
+}
+
+// ParseWindow parses "HH:MM-HH:MM" (24h, local time). "" parses to the zero,
+// always-open Window. A window whose start equals its end is rejected — it
+// would read as either never or always open.
+func ParseWindow(s string) (Window, error) {
+	if s == "" {
+		return Window{}, nil
+	}
+	a, b, ok := strings.Cut(s, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("window %q: want HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(a)
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", s, err)
+	}
+	end, err := parseClock(b)
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", s, err)
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("window %q: start and end are equal", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || len(h) != 2 || len(m) != 2 {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	hh, err1 := strconv.Atoi(h)
+	mm, err2 := strconv.Atoi(m)
+	if err1 != nil || err2 != nil || hh < 0 || hh > 23 || mm < 0 || mm > 59 {
+		return 0, fmt.Errorf("%q is not a valid 24h time", s)
+	}
+	return time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute, nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	for _, c := range []struct {
+		in      string
+		want    Window
+		wantErr bool
+	}{
+		{"", Window{}, false},
+		{"02:00-05:00", Window{2 * time.Hour, 5 * time.Hour}, false},
+		{"22:30-01:15", Window{22*time.Hour + 30*time.Minute, time.Hour + 15*time.Minute}, false},
+		{"02:00", Window{}, true},
+		{"2:00-05:00", Window{}, true},
+		{"24:00-05:00", Window{}, true},
+		{"02:60-05:00", Window{}, true},
+		{"03:00-03:00", Window{}, true},
+	} {
+		got, err := ParseWindow(c.in)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("ParseWindow(%q) = %+v, %v; want %+v, err=%v", c.in, got, err, c.want, c.wantErr)
+		}
+		if err == nil && got.String() != c.in {
+			t.Errorf("String() = %q, want round-trip %q", got.String(), c.in)
+		}
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.Local) }
+	night, _ := ParseWindow("02:00-05:00")
+	wrap, _ := ParseWindow("22:00-02:00")
+	for _, c := range []struct {
+		name string
+		w    Window
+		t    time.Time
+		want bool
+	}{
+		{"zero always open", Window{}, at(19, 0), true},
+		{"inside", night, at(3, 0), true},
+		{"start inclusive", night, at(2, 0), true},
+		{"end exclusive", night, at(5, 0), false},
+		{"evening outside", night, at(20, 30), false},
+		{"wrap late", wrap, at(23, 0), true},
+		{"wrap early", wrap, at(1, 59), true},
+		{"wrap outside", wrap, at(12, 0), false},
+	} {
+		if got := c.w.Contains(c.t); got != c.want {
+			t.Errorf("%s: Contains = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
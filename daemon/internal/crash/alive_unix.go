@@ -0,0 +1,18 @@
+//go:build !windows
+
+package crash
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Alive reports whether pid runs; EPERM is a live process owned by someone
+// else.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,17 @@
+package crash
+
+import "os"
+
+// Alive reports whether pid runs: FindProcess fails for a gone one on
+// Windows.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
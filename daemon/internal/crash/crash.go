@@ -0,0 +1,210 @@
+// Package crash keeps the daemon's crash reports. A long-running worker
+// arms a file in the install's crash directory with runtime/debug's
+// SetCrashOutput, so a fatal panic in any goroutine lands there as well as
+// on stderr, stack and all. The next sweep (the restarted worker, or the
+// watchdog pass) turns every armed file whose process is gone into a
+// report: empty means a clean exit and is removed; anything else is a
+// crash, kept on disk and handed back to be recorded and, when a
+// Sentry-compatible endpoint is configured, submitted (see Submit).
+//
+// A report file holds the runtime's output verbatim and stays 0600 in the
+// daemon-home. What leaves it — the store's record, status, the submitted
+// event — is the redacted Summary and the frames' functions and lines.
+package crash
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report file suffixes: an armed file belongs to a process that may still
+// be running; a kept one is a crash already swept.
+const (
+	armedExt = ".arm"
+	keptExt  = ".crash"
+)
+
+// MaxReports is how many swept reports the directory keeps; the oldest go
+// first.
+const MaxReports = 20
+
+// Report is one swept crash.
+type Report struct {
+	// At is when the runtime wrote the report (the file's mtime).
+	At time.Time
+	// Kind is "panic" or "fatal error"; Summary the first line of the
+	// runtime's output with anything path-like blanked.
+	Kind    string
+	Summary string
+	// Frames are the crashing goroutine's calls, innermost first.
+	Frames []Frame
+}
+
+// Frame is one call in a crashing goroutine: the function, and the source
+// file's base name and line.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Arm makes this process's crash output land in dir until disarm is called.
+// disarm removes the armed file, so a clean exit leaves nothing behind.
+func Arm(dir string) (disarm func(), err error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(dir, fmt.Sprintf("%020d-%d%s", time.Now().UnixNano(), os.Getpid(), armedExt))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		os.Remove(name)
+		return nil, err
+	}
+	return func() {
+		_ = debug.SetCrashOutput(nil, debug.CrashOptions{})
+		os.Remove(name)
+	}, nil
+}
+
+// Sweep turns the armed files in dir whose process is gone into reports,
+// removing the empty ones, and prunes the kept reports to MaxReports. alive
+// reports whether a pid still runs (Alive in production). A missing dir is
+// no crash.
+func Sweep(dir string, alive func(pid int) bool) ([]Report, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Report
+	var kept []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasSuffix(name, keptExt) {
+			kept = append(kept, name)
+			continue
+		}
+		base, ok := strings.CutSuffix(name, armedExt)
+		if !ok {
+			continue
+		}
+		_, pidStr, _ := strings.Cut(base, "-")
+		if pid, err := strconv.Atoi(pidStr); err == nil && alive(pid) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if fi.Size() == 0 {
+			os.Remove(path)
+			continue
+		}
+		// The rename claims the report: a second sweeper racing this one
+		// fails it and skips, so each crash is recorded once.
+		if os.Rename(path, filepath.Join(dir, base+keptExt)) != nil {
+			continue
+		}
+		kept = append(kept, base+keptExt)
+		b, err := os.ReadFile(filepath.Join(dir, base+keptExt))
+		if err != nil {
+			continue
+		}
+		r := Parse(b)
+		r.At = fi.ModTime()
+		out = append(out, r)
+	}
+	slices.Sort(kept)
+	for len(kept) > MaxReports {
+		os.Remove(filepath.Join(dir, kept[0]))
+		kept = kept[1:]
+	}
+	return out, nil
+}
+
+// Parse reads the runtime's crash output: the first line's kind and
+// message, and the first goroutine's frames.
+func Parse(b []byte) Report {
+	var r Report
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	var fn string
+	inGoroutine := false
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case r.Kind == "" && strings.TrimSpace(line) != "":
+			r.Kind, r.Summary = "panic", line
+			for _, k := range []string{"panic: ", "fatal error: "} {
+				if msg, ok := strings.CutPrefix(line, k); ok {
+					r.Kind, r.Summary = strings.TrimSuffix(k, ": "), msg
+				}
+			}
+			r.Summary = Redact(r.Summary)
+		case strings.HasPrefix(line, "goroutine "):
+			if inGoroutine {
+				return r
+			}
+			inGoroutine = true
+		case !inGoroutine:
+		case line == "":
+			if len(r.Frames) > 0 {
+				return r
+			}
+		case strings.HasPrefix(line, "\t"):
+			if fn == "" {
+				continue
+			}
+			file, lineNo := parseLocation(strings.TrimSpace(line))
+			r.Frames = append(r.Frames, Frame{Function: fn, File: file, Line: lineNo})
+			fn = ""
+		default:
+			fn = strings.TrimPrefix(line, "created by ")
+			if i := strings.LastIndex(fn, "("); i > 0 {
+				fn = fn[:i]
+			}
+			if i := strings.Index(fn, " in goroutine "); i > 0 {
+				fn = fn[:i]
+			}
+		}
+	}
+	return r
+}
+
+// parseLocation reads "/src/x/main.go:42 +0x1d" as ("main.go", 42).
+func parseLocation(s string) (string, int) {
+	s, _, _ = strings.Cut(s, " ")
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return filepath.Base(s), 0
+	}
+	n, _ := strconv.Atoi(s[i+1:])
+	return filepath.Base(s[:i]), n
+}
+
+// Redact blanks every word of s that looks like a path: a panic's message
+// is often an error, and an error often names the disguised install.
+func Redact(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		if strings.ContainsAny(w, `/\`) {
+			words[i] = "<path>"
+		}
+	}
+	return strings.Join(words, " ")
+}
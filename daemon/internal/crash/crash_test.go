@@ -0,0 +1,169 @@
+package crash
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `panic: open /Users/me/Library/Application Support/x/state: permission denied
+
+goroutine 7 [running]:
+main.(*worker).tick(0xc000010000)
+	/build/cmd/daemon/main.go:812 +0x1d
+created by main.loop in goroutine 1
+	/build/cmd/daemon/main.go:640 +0x85
+
+goroutine 1 [select]:
+main.loop()
+	/build/cmd/daemon/main.go:700 +0x9
+`
+
+func TestParse(t *testing.T) {
+	r := Parse([]byte(sample))
+	if r.Kind != "panic" || strings.Contains(r.Summary, "/") || !strings.Contains(r.Summary, "permission denied") {
+		t.Fatalf("kind %q summary %q", r.Kind, r.Summary)
+	}
+	want := []Frame{{"main.(*worker).tick", "main.go", 812}, {"main.loop", "main.go", 640}}
+	if len(r.Frames) != len(want) {
+		t.Fatalf("frames = %+v", r.Frames)
+	}
+	for i := range want {
+		if r.Frames[i] != want[i] {
+			t.Errorf("frame %d = %+v, want %+v", i, r.Frames[i], want[i])
+		}
+	}
+	if r := Parse([]byte("fatal error: concurrent map writes\n")); r.Kind != "fatal error" || r.Summary != "concurrent map writes" {
+		t.Fatalf("fatal error: %+v", r)
+	}
+}
+
+// TestSweep: a gone process's empty file is a clean exit and goes; its
+// non-empty one becomes a kept report; a live process's file is left.
+func TestSweep(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("00000000000000000001-100.arm", "")
+	write("00000000000000000002-200.arm", sample)
+	write("00000000000000000003-300.arm", sample)
+	alive := func(pid int) bool { return pid == 300 }
+	got, err := Sweep(dir, alive)
+	if err != nil || len(got) != 1 || got[0].Kind != "panic" || got[0].At.IsZero() {
+		t.Fatalf("sweep = %+v, %v", got, err)
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for i := range names {
+		names[i] = filepath.Base(names[i])
+	}
+	if strings.Join(names, " ") != "00000000000000000002-200.crash 00000000000000000003-300.arm" {
+		t.Fatalf("left %v", names)
+	}
+	if again, _ := Sweep(dir, alive); len(again) != 0 {
+		t.Fatalf("second sweep re-reported %+v", again)
+	}
+	if got, err := Sweep(filepath.Join(dir, "none"), alive); got != nil || err != nil {
+		t.Fatalf("missing dir: %+v, %v", got, err)
+	}
+}
+
+func TestArmLeavesNothingOnCleanExit(t *testing.T) {
+	dir := t.TempDir()
+	disarm, err := Arm(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := filepath.Glob(filepath.Join(dir, "*.arm")); len(names) != 1 {
+		t.Fatalf("armed files = %v", names)
+	}
+	disarm()
+	if names, _ := filepath.Glob(filepath.Join(dir, "*")); len(names) != 0 {
+		t.Fatalf("left %v", names)
+	}
+}
+
+// TestArmCapturesPanic crashes a child copy of the test binary with its
+// output armed, and sweeps the report it leaves.
+func TestArmCapturesPanic(t *testing.T) {
+	if dir := os.Getenv("CRASH_TEST_DIR"); dir != "" {
+		if _, err := Arm(dir); err != nil {
+			os.Exit(3)
+		}
+		go func() { panic("boom in a goroutine") }()
+		select {}
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestArmCapturesPanic$")
+	cmd.Env = append(os.Environ(), "CRASH_TEST_DIR="+dir)
+	if err := cmd.Run(); err == nil {
+		t.Fatal("child did not crash")
+	}
+	got, err := Sweep(dir, Alive)
+	if err != nil || len(got) != 1 || got[0].Summary != "boom in a goroutine" || len(got[0].Frames) == 0 {
+		t.Fatalf("sweep = %+v, %v", got, err)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	for s, ok := range map[string]bool{
+		"https://abc@o1.ingest.example.com/42": true,
+		"https://abc@errors.example.com/sub/7": true,
+		"http://abc@errors.example.com/7":      false,
+		"https://errors.example.com/7":         false,
+		"https://abc@errors.example.com/":      false,
+		"not a url":                            false,
+	} {
+		if _, err := ParseDSN(s); (err == nil) != ok {
+			t.Errorf("ParseDSN(%q) = %v, want ok=%v", s, err, ok)
+		}
+	}
+	d, _ := ParseDSN("https://abc@errors.example.com/sub/7")
+	if u := d.envelopeURL(); u != "https://errors.example.com/sub/api/7/envelope/" {
+		t.Fatalf("envelope URL %q", u)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	var path, auth string
+	var lines []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("X-Sentry-Auth")
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+	}))
+	defer srv.Close()
+	dsn, err := ParseDSN("https://k3y@" + strings.TrimPrefix(srv.URL, "https://") + "/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Parse([]byte(sample))
+	r.At = time.Now()
+	if err := Submit(context.Background(), srv.Client(), dsn, r, "v1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/42/envelope/" || !strings.Contains(auth, "sentry_key=k3y") || len(lines) != 3 {
+		t.Fatalf("path %q auth %q lines %d", path, auth, len(lines))
+	}
+	var ev event
+	if err := json.Unmarshal([]byte(lines[2]), &ev); err != nil {
+		t.Fatal(err)
+	}
+	ex := ev.Exception.Values[0]
+	if ev.Release != "v1.2.3" || ex.Type != "panic" || strings.Contains(lines[2], "/Users/") ||
+		ex.Stacktrace.Frames[0].Function != "main.loop" {
+		t.Fatalf("event %s", lines[2])
+	}
+}
@@ -0,0 +1,114 @@
+package crash
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DSN is a parsed Sentry DSN, https://KEY@HOST[/PREFIX]/PROJECT: the form
+// Sentry, GlitchTip and the other Sentry-compatible servers hand out.
+type DSN struct {
+	key     string
+	base    string // scheme://host/prefix
+	project string
+}
+
+// ParseDSN accepts an https DSN with a public key and a project id. Plain
+// http is refused: the report crosses the network with the key beside it.
+func ParseDSN(s string) (DSN, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return DSN{}, errors.New("crash: DSN must be https://KEY@HOST/PROJECT")
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	project, prefix := path[i+1:], ""
+	if i >= 0 {
+		prefix = "/" + path[:i]
+	}
+	if project == "" {
+		return DSN{}, errors.New("crash: DSN has no project id")
+	}
+	return DSN{key: u.User.Username(), base: "https://" + u.Host + prefix, project: project}, nil
+}
+
+// envelopeURL is where the DSN's server takes envelopes.
+func (d DSN) envelopeURL() string { return d.base + "/api/" + d.project + "/envelope/" }
+
+// event is the slice of Sentry's event payload a crash fills in.
+type event struct {
+	EventID   string `json:"event_id"`
+	Timestamp string `json:"timestamp"`
+	Platform  string `json:"platform"`
+	Level     string `json:"level"`
+	Release   string `json:"release,omitempty"`
+	Exception struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+}
+
+type exception struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+}
+
+// Submit posts r to dsn's server as one envelope. release is the daemon's
+// version. Only the redacted summary and the frames leave the machine; the
+// report file stays where it is.
+func Submit(ctx context.Context, c *http.Client, dsn DSN, r Report, release string) error {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	ev := event{EventID: hex.EncodeToString(id), Timestamp: r.At.UTC().Format(time.RFC3339),
+		Platform: "go", Level: "fatal", Release: release}
+	ex := exception{Type: r.Kind, Value: r.Summary}
+	// Sentry lists frames outermost first; the runtime innermost first.
+	for i := len(r.Frames) - 1; i >= 0; i-- {
+		f := r.Frames[i]
+		ex.Stacktrace.Frames = append(ex.Stacktrace.Frames, sentryFrame{Function: f.Function, Filename: f.File, Lineno: f.Line})
+	}
+	ev.Exception.Values = []exception{ex}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	head, _ := json.Marshal(map[string]string{"event_id": ev.EventID, "sent_at": time.Now().UTC().Format(time.RFC3339)})
+	item, _ := json.Marshal(map[string]any{"type": "event", "length": len(payload)})
+	var body bytes.Buffer
+	for _, part := range [][]byte{head, item, payload} {
+		body.Write(part)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dsn.envelopeURL(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client=crash/1.0, sentry_key="+dsn.key)
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("crash: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Package diag builds the `daemon diag export` bundle: a gzipped tarball of
+// recent logs, a sanitized config summary, the status snapshot, launchd job
+// state and restore-chain health, meant to be attached to a bug report.
+//
+// Everything in the bundle is already redaction-safe when it is added — the
+// config summary carries settings as booleans and formats, never URLs, and
+// free text (logs, launchctl output) goes through redact.Scrub — so the
+// archive writer itself never needs to know what a secret is.
+package diag
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// DefaultLines is how many trailing log lines a bundle keeps per log.
+const DefaultLines = 500
+
+// maxTailBytes bounds how much of a log Tail reads (from the end), so a huge
+// log cannot balloon the export.
+const maxTailBytes = 1 << 20
+
+// File is one bundle member.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Config is the sanitized view of the daemon's version.json: versions and
+// which settings are in effect, but no URL, hook or key.
+type Config struct {
+	Desired       string   `json:"desired"`
+	Good          string   `json:"good"`
+	Bad           []string `json:"bad,omitempty"`
+	Channel       string   `json:"channel"`
+	UpdateWindow  string   `json:"update_window,omitempty"`
+	MirrorSet     bool     `json:"mirror_set"`
+	ProxySet      bool     `json:"proxy_set"`
+	WebhookFormat []string `json:"webhook_formats,omitempty"`
+	TracingSet    bool     `json:"tracing_set"`
+	HeartbeatSet  bool     `json:"heartbeat_set"`
+	AdminAPISet   bool     `json:"admin_api_set"`
+}
+
+// SanitizedConfig summarizes st for the bundle. Webhooks are reduced to
+// their format (the part before "="); the URL is a credential.
+func SanitizedConfig(st *core.Store) Config {
+	mirror, proxy := st.Network()
+	c := Config{
+		Desired:      st.Desired(),
+		Good:         st.Good(),
+		Channel:      st.Channel(),
+		MirrorSet:    mirror != "",
+		ProxySet:     proxy != "",
+		TracingSet:   st.TraceEndpoint() != "",
+		HeartbeatSet: st.HeartbeatEndpoint() != "",
+	}
+	if addr, _ := st.AdminAPI(); addr != "" {
+		c.AdminAPISet = true
+	}
+	if w := st.UpdateWindow(); !w.IsZero() {
+		c.UpdateWindow = w.String()
+	}
+	for v := range st.BadSet() {
+		c.Bad = append(c.Bad, v)
+	}
+	slices.Sort(c.Bad)
+	for _, h := range st.Webhooks() {
+		format, _, _ := strings.Cut(h, "=")
+		c.WebhookFormat = append(c.WebhookFormat, format)
+	}
+	return c
+}
+
+// Tail returns the last n lines of the file at path (at most the final
+// maxTailBytes of it). A missing file is an error the caller reports in the
+// bundle rather than a failed export.
+func Tail(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	off := max(fi.Size()-maxTailBytes, 0)
+	b, err := io.ReadAll(io.NewSectionReader(f, off, fi.Size()-off))
+	if err != nil {
+		return nil, err
+	}
+	if off > 0 {
+		// Drop the partial first line the byte cut landed in.
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			b = b[i+1:]
+		}
+	}
+	lines := bytes.SplitAfter(b, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return bytes.Join(lines, nil), nil
+}
+
+// WriteTarGz writes files as a gzipped tarball under a top-level dir, each
+// entry 0644 and stamped now.
+func WriteTarGz(w io.Writer, dir string, files []File, now time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    dir + "/" + f.Name,
+			Mode:    0o644,
+			Size:    int64(len(f.Data)),
+			ModTime: now,
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,86 @@
+package diag
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestSanitizedConfigDropsURLs(t *testing.T) {
+	st := &core.Store{Dir: t.TempDir()}
+	if err := st.WriteDesired("v1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	_ = st.WriteNetwork("https://mirror.example/SECRET", "")
+	_ = st.WriteWebhooks([]string{"slack=https://hooks.slack.com/services/SECRET", "ntfy=https://ntfy.sh/SECRET"})
+	_ = st.WriteTraceEndpoint("http://collector:4318/SECRET")
+	_ = st.MarkBad("v1.1.0")
+
+	c := SanitizedConfig(st)
+	if c.Desired != "v1.2.0" || !c.MirrorSet || c.ProxySet || !c.TracingSet {
+		t.Errorf("config = %+v", c)
+	}
+	if strings.Join(c.WebhookFormat, ",") != "slack,ntfy" || strings.Join(c.Bad, ",") != "v1.1.0" {
+		t.Errorf("webhooks = %v, bad = %v", c.WebhookFormat, c.Bad)
+	}
+	if strings.Contains(fmt.Sprintf("%+v", c), "SECRET") {
+		t.Errorf("sanitized config carries a URL: %+v", c)
+	}
+}
+
+func TestTailKeepsLastLines(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "x.log")
+	var b strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	if err := os.WriteFile(p, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Tail(p, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "line 8\nline 9\nline 10\n" {
+		t.Errorf("Tail = %q", got)
+	}
+	if _, err := Tail(filepath.Join(t.TempDir(), "missing"), 3); err == nil {
+		t.Error("missing file should be an error")
+	}
+}
+
+func TestWriteTarGzRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	files := []File{{Name: "a.json", Data: []byte("{}")}, {Name: "logs/b.log", Data: []byte("hi\n")}}
+	if err := WriteTarGz(&buf, "diag-x", files, time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, h.Name)
+	}
+	if strings.Join(names, ",") != "diag-x/a.json,diag-x/logs/b.log" {
+		t.Errorf("entries = %v", names)
+	}
+}
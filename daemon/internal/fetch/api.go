@@ -0,0 +1,200 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxAPIBody caps one REST response — release listings are a few hundred KiB
+// at most.
+const maxAPIBody = 4 << 20
+
+// RateLimitError is returned when api.github.com refuses a request for rate
+// limiting (403/429 with the quota exhausted, or a Retry-After). Reset is when
+// the quota refills (zero if GitHub didn't say). Callers should stop retrying
+// until then rather than burn the next hour's quota too.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "fetch/github: api.github.com rate limit exceeded"
+	if !e.Reset.IsZero() {
+		msg += " until " + e.Reset.Local().Format("15:04")
+	}
+	return msg + " (set GITHUB_TOKEN for a higher limit)"
+}
+
+// rateLimited classifies resp as a rate-limit refusal. GitHub answers an
+// exhausted primary quota with 403 + X-RateLimit-Remaining: 0 (reset in
+// X-RateLimit-Reset, unix seconds) and a secondary limit with 403/429 +
+// Retry-After (seconds). A plain 403 without either is a real "forbidden".
+func rateLimited(resp *http.Response, now time.Time) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			return &RateLimitError{Reset: now.Add(time.Duration(n) * time.Second)}
+		}
+		return &RateLimitError{}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if n, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return &RateLimitError{Reset: time.Unix(n, 0)}
+		}
+		return &RateLimitError{}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{}
+	}
+	return nil
+}
+
+// apiJSON GETs a REST endpoint and decodes it into v. With a Cache it sends
+// If-None-Match for a previously-seen ETag and decodes the cached body on 304
+// — GitHub does not count a 304 against the rate limit, so repeated
+// `update --check` runs stay cheap. A rate-limit refusal surfaces as a
+// *RateLimitError; if a cached body exists it is served instead, stale but
+// honest about being the last thing GitHub said.
+func (g *GitHub) apiJSON(ctx context.Context, what, api string, v any) error {
+	var cached CacheEntry
+	var haveCached bool
+	if g.Cache != nil {
+		cached, haveCached = g.Cache.Get(api)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if tok := os.Getenv("GITHUB_TOKEN"); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	if haveCached && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch/github: %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCached:
+		body = cached.Body
+	case resp.StatusCode == http.StatusOK:
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxAPIBody))
+		if err != nil {
+			return fmt.Errorf("fetch/github: %s: read response: %w", what, err)
+		}
+		if g.Cache != nil {
+			if etag := resp.Header.Get("ETag"); etag != "" {
+				g.Cache.Put(api, CacheEntry{ETag: etag, Body: body})
+			}
+		}
+	default:
+		if rl := rateLimited(resp, time.Now()); rl != nil {
+			if !haveCached {
+				return rl
+			}
+			body = cached.Body
+			break
+		}
+		return fmt.Errorf("fetch/github: %s status %d", what, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("fetch/github: %s: decode response: %w", what, err)
+	}
+	return nil
+}
+
+// IsRateLimited reports whether err is (or wraps) a *RateLimitError.
+func IsRateLimited(err error) bool {
+	var rl *RateLimitError
+	return errors.As(err, &rl)
+}
+
+// CacheEntry is one cached REST response: its ETag and raw body.
+type CacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// APICache stores REST responses by URL for conditional requests.
+type APICache interface {
+	Get(url string) (CacheEntry, bool)
+	Put(url string, e CacheEntry)
+}
+
+// FileCache is an APICache persisted as one small JSON file, so the ETags
+// survive between one-shot CLI runs. Best-effort throughout: an unreadable or
+// garbled file is an empty cache, and a failed write is ignored — the cache
+// only ever saves a request, it never gates one.
+//
+// Entries are keyed by a digest of the URL, and Seal/Open (when set) transform
+// the whole file on write/read — the daemon wires the store's salt-keyed mask
+// so neither the repo-bearing URLs nor the release JSON sit on disk in a
+// greppable form.
+type FileCache struct {
+	Path       string
+	Seal, Open func([]byte) []byte
+
+	mu sync.Mutex
+}
+
+func cacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *FileCache) load() map[string]CacheEntry {
+	m := map[string]CacheEntry{}
+	if b, err := os.ReadFile(c.Path); err == nil {
+		if c.Open != nil {
+			b = c.Open(b)
+		}
+		_ = json.Unmarshal(b, &m)
+	}
+	return m
+}
+
+func (c *FileCache) Get(url string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.load()[cacheKey(url)]
+	return e, ok
+}
+
+func (c *FileCache) Put(url string, e CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := c.load()
+	m[cacheKey(url)] = e
+	b, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	if c.Seal != nil {
+		b = c.Seal(b)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o700); err != nil {
+		return
+	}
+	tmp := c.Path + ".tmp"
+	if os.WriteFile(tmp, b, 0o600) == nil {
+		_ = os.Rename(tmp, c.Path)
+	}
+}
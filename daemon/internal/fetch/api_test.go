@@ -0,0 +1,133 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func respond(code int, body string, hdr map[string]string) *http.Response {
+	h := make(http.Header)
+	for k, v := range hdr {
+		h.Set(k, v)
+	}
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body)), Header: h}
+}
+
+// TestAPIJSON_ETagRevalidates: the second call sends If-None-Match and a 304
+// is answered from the cache — including across FileCache instances, since
+// the point is to survive between one-shot CLI runs.
+func TestAPIJSON_ETagRevalidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	var sawINM []string
+	rt := &recordingTransport{
+		serve: func(r *http.Request) (*http.Response, error) {
+			sawINM = append(sawINM, r.Header.Get("If-None-Match"))
+			if r.Header.Get("If-None-Match") == `"abc"` {
+				return respond(304, "", nil), nil
+			}
+			return respond(200, `{"tag_name":"v1.2.3"}`, map[string]string{"ETag": `"abc"`}), nil
+		},
+	}
+	for i := 0; i < 2; i++ {
+		g := &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: rt}, Cache: &FileCache{Path: path}}
+		v, err := g.ResolveLatest(context.Background())
+		if err != nil || v != "v1.2.3" {
+			t.Fatalf("call %d: ResolveLatest = %q, %v", i, v, err)
+		}
+	}
+	if len(sawINM) != 2 || sawINM[0] != "" || sawINM[1] != `"abc"` {
+		t.Fatalf("If-None-Match per call = %q, want [\"\" \"abc\"]", sawINM)
+	}
+}
+
+func TestAPIJSON_RateLimit(t *testing.T) {
+	reset := time.Now().Add(40 * time.Minute).Truncate(time.Second)
+	limited := func(*http.Request) (*http.Response, error) {
+		return respond(403, `{"message":"API rate limit exceeded"}`, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+		}), nil
+	}
+
+	// No cache: a typed, recognisable error carrying the reset time.
+	g := &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: &recordingTransport{serve: limited}}}
+	_, err := g.ResolveLatest(context.Background())
+	if !IsRateLimited(err) {
+		t.Fatalf("err = %v, want a rate-limit error", err)
+	}
+	if rl := err.(*RateLimitError); !rl.Reset.Equal(reset) {
+		t.Fatalf("reset = %v, want %v", rl.Reset, reset)
+	}
+
+	// With a cached answer the last-known body is served instead.
+	cache := &FileCache{Path: filepath.Join(t.TempDir(), "cache")}
+	api := "https://api.github.com/repos/" + testRepo + "/releases/latest"
+	cache.Put(api, CacheEntry{ETag: `"x"`, Body: []byte(`{"tag_name":"v1.0.0"}`)})
+	g = &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: &recordingTransport{serve: limited}}, Cache: cache}
+	if v, err := g.ResolveLatest(context.Background()); err != nil || v != "v1.0.0" {
+		t.Fatalf("rate-limited with cache: %q, %v; want cached v1.0.0", v, err)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cases := []struct {
+		name  string
+		code  int
+		hdr   map[string]string
+		want  bool
+		reset time.Time
+	}{
+		{"plain forbidden is not a rate limit", 403, nil, false, time.Time{}},
+		{"exhausted primary quota", 403, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000600"}, true, time.Unix(1700000600, 0)},
+		{"secondary limit retry-after", 403, map[string]string{"Retry-After": "60"}, true, now.Add(time.Minute)},
+		{"429 without headers", 429, nil, true, time.Time{}},
+		{"404 is not", 404, map[string]string{"X-RateLimit-Remaining": "0"}, false, time.Time{}},
+	}
+	for _, c := range cases {
+		rl := rateLimited(respond(c.code, "", c.hdr), now)
+		if (rl != nil) != c.want {
+			t.Errorf("%s: got %v, want limited=%v", c.name, rl, c.want)
+			continue
+		}
+		if rl != nil && !rl.Reset.Equal(c.reset) {
+			t.Errorf("%s: reset = %v, want %v", c.name, rl.Reset, c.reset)
+		}
+	}
+}
+
+// TestFileCache_Sealed: with Seal/Open wired, neither the URL nor the body is
+// readable in the file, and a round trip still returns the entry.
+func TestFileCache_Sealed(t *testing.T) {
+	flip := func(b []byte) []byte {
+		out := make([]byte, len(b))
+		for i := range b {
+			out[i] = b[i] ^ 0x5a
+		}
+		return out
+	}
+	path := filepath.Join(t.TempDir(), "cache")
+	c := &FileCache{Path: path, Seal: flip, Open: flip}
+	url := "https://api.github.com/repos/" + testRepo + "/releases/latest"
+	c.Put(url, CacheEntry{ETag: `"e"`, Body: []byte(`{"tag_name":"v1"}`)})
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"focusd", "tag_name", "etag"} {
+		if strings.Contains(string(raw), leak) {
+			t.Fatalf("cache file contains %q in the clear", leak)
+		}
+	}
+	if e, ok := (&FileCache{Path: path, Seal: flip, Open: flip}).Get(url); !ok || e.ETag != `"e"` {
+		t.Fatalf("round trip = %+v, %v", e, ok)
+	}
+}
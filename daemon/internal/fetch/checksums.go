@@ -0,0 +1,56 @@
+package fetch
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ChecksumsAsset is the sha256 manifest a release may carry alongside its
+// binaries (`shasum -a 256` output; the daemon release workflow publishes it).
+const ChecksumsAsset = "checksums.txt"
+
+// maxChecksums caps the manifest read — a few lines per os/arch in practice.
+const maxChecksums = 64 << 10
+
+// parseChecksums reads `shasum -a 256` / `sha256sum` output into
+// filename → lowercase hex digest. The binary-mode "*" marker is stripped;
+// malformed lines are ignored rather than failing the whole manifest.
+func parseChecksums(r io.Reader) map[string]string {
+	sums := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) != 2 || len(f[0]) != 64 {
+			continue
+		}
+		sums[strings.TrimPrefix(f[1], "*")] = strings.ToLower(f[0])
+	}
+	return sums
+}
+
+// releaseChecksum returns the digest the release's checksums.txt lists for
+// asset. ok=false means there is nothing to cross-check against — the release
+// has no manifest (404), the manifest could not be fetched, or it does not
+// list this asset. That is NOT a failure: the Ed25519 signature is the trust
+// root and is checked regardless; the manifest only adds an early, cheap
+// "these are the bytes the release meant to publish" cross-check.
+func (g *GitHub) releaseChecksum(ctx context.Context, tag, asset string) (sum string, ok bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.downloadURL(tag, ChecksumsAsset), nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", false
+	}
+	sum, ok = parseChecksums(io.LimitReader(resp.Body, maxChecksums))[asset]
+	return sum, ok
+}
@@ -0,0 +1,86 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	a := strings.Repeat("a", 64)
+	b := strings.Repeat("B", 64)
+	in := a + "  daemon-darwin-arm64\n" +
+		b + " *platform-darwin-arm64\n" +
+		"garbage line\n" +
+		"abc  short-digest\n"
+	got := parseChecksums(strings.NewReader(in))
+	if len(got) != 2 {
+		t.Fatalf("parsed %d entries, want 2: %v", len(got), got)
+	}
+	if got["daemon-darwin-arm64"] != a {
+		t.Errorf("text-mode entry = %q", got["daemon-darwin-arm64"])
+	}
+	if got["platform-darwin-arm64"] != strings.ToLower(b) {
+		t.Errorf("binary-mode entry = %q (want '*' stripped, lowercased)", got["platform-darwin-arm64"])
+	}
+}
+
+// checksumServer serves body for the asset and manifest for checksums.txt
+// (404 when manifest is "").
+func checksumServer(body []byte, manifest string) *recordingTransport {
+	return &recordingTransport{
+		serve: func(r *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(r.URL.Path, "/"+ChecksumsAsset) {
+				if manifest == "" {
+					return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+				}
+				return okBody([]byte(manifest)), nil
+			}
+			return okBody(body), nil
+		},
+	}
+}
+
+// TestDownloadVerified_ChecksumMismatchRefused: a listed-but-wrong digest is
+// refused before the signature is even consulted — key-free, so it runs on
+// every CI runner — and nothing is placed.
+func TestDownloadVerified_ChecksumMismatchRefused(t *testing.T) {
+	manifest := strings.Repeat("0", 64) + "  " + testAsset + "\n"
+	rt := checksumServer([]byte("platform bytes"), manifest)
+	g := &GitHub{Repo: testRepo, Asset: testAsset, HTTP: &http.Client{Transport: rt}}
+	dst := filepath.Join(t.TempDir(), "platform")
+	err := g.DownloadVerified(context.Background(), testTag, testAsset, dst)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("err = %v, want checksum mismatch", err)
+	}
+	if _, statErr := os.Stat(dst); !os.IsNotExist(statErr) {
+		t.Fatalf("file must NOT be placed on checksum mismatch (stat err=%v)", statErr)
+	}
+}
+
+// TestDownloadVerified_ChecksumMatchOrAbsentFallsToSignature: a matching
+// digest, an absent manifest, or a manifest that doesn't list the asset all
+// proceed to the signature check (which then refuses these unsigned bytes —
+// proving the checksum never short-circuits verification).
+func TestDownloadVerified_ChecksumMatchOrAbsentFallsToSignature(t *testing.T) {
+	body := []byte("platform bytes")
+	sum := sha256.Sum256(body)
+	for name, manifest := range map[string]string{
+		"match":    hex.EncodeToString(sum[:]) + "  " + testAsset + "\n",
+		"absent":   "",
+		"unlisted": strings.Repeat("0", 64) + "  some-other-asset\n",
+	} {
+		g := &GitHub{Repo: testRepo, Asset: testAsset, HTTP: &http.Client{Transport: checksumServer(body, manifest)}}
+		dst := filepath.Join(t.TempDir(), "platform")
+		err := g.DownloadVerified(context.Background(), testTag, testAsset, dst)
+		if err == nil || strings.Contains(err.Error(), "checksum") {
+			t.Fatalf("%s: err = %v, want a signature (not checksum) refusal", name, err)
+		}
+	}
+}
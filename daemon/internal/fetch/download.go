@@ -0,0 +1,157 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAsset caps one release asset so a malicious/misconfigured release can't
+// push an unbounded stream into the daemon.
+const maxAsset = 512 << 20 // 512 MiB ceiling
+
+// downloadAttempts is how many times one asset download is tried before
+// giving up. Each retry RESUMES from the bytes already on disk (HTTP Range),
+// so a connection that drops at 90% costs the last 10%, not the whole file.
+const downloadAttempts = 4
+
+// downloadBackoff is the first retry delay; it doubles per attempt
+// (1s, 2s, 4s), keeping a whole failed download well inside one reconcile
+// fetch-cooldown window.
+const downloadBackoff = time.Second
+
+// errPermanent marks a download failure no retry can fix (e.g. 404: the
+// release or asset does not exist).
+type errPermanent struct{ error }
+
+func (e errPermanent) Unwrap() error { return e.error }
+
+// fetchResumable downloads dlURL into f, retrying transient failures with
+// exponential backoff and resuming each retry with a Range request from the
+// current length of f. A server that ignores Range (plain 200) restarts the
+// file from zero; a 206 whose Content-Range does not start where we asked is
+// treated the same way rather than trusted. Every request is PLAIN — octet-
+// stream Accept and no Authorization bearer (see DownloadVerified).
+func (g *GitHub) fetchResumable(ctx context.Context, dlURL string, f *os.File) error {
+	sleep := g.sleep
+	if sleep == nil {
+		sleep = sleepCtx
+	}
+	var last error
+	for attempt := 0; attempt < downloadAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, downloadBackoff<<(attempt-1)); err != nil {
+				return err
+			}
+		}
+		last = g.fetchOnce(ctx, dlURL, f)
+		if last == nil {
+			return nil
+		}
+		var perm errPermanent
+		if errors.As(last, &perm) || ctx.Err() != nil {
+			return last
+		}
+	}
+	return fmt.Errorf("fetch/github: download failed after %d attempts: %w", downloadAttempts, last)
+}
+
+// fetchOnce is one download attempt, resuming from f's current size.
+func (g *GitHub) fetchOnce(ctx context.Context, dlURL string, f *os.File) error {
+	have, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errPermanent{err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dlURL, nil)
+	if err != nil {
+		return errPermanent{err}
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	if have > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(have, 10)+"-")
+	}
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && have > 0 && rangeStart(resp.Header.Get("Content-Range")) == have:
+		// Resume: append after what we already have.
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent:
+		// Fresh (or a Range the server didn't honour as asked): start over.
+		if err := f.Truncate(0); err != nil {
+			return errPermanent{err}
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errPermanent{err}
+		}
+		have = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// Our partial is already the whole file (or junk) — drop it and retry.
+		_ = f.Truncate(0)
+		return fmt.Errorf("fetch/github: download range not satisfiable")
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("fetch/github: download status %d", resp.StatusCode)
+	default:
+		return errPermanent{fmt.Errorf("fetch/github: download status %d", resp.StatusCode)}
+	}
+
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxAsset-have+1))
+	if have+n > maxAsset {
+		return errPermanent{fmt.Errorf("fetch/github: asset exceeds %d bytes", int64(maxAsset))}
+	}
+	return err
+}
+
+// rangeStart parses the first byte offset of a "bytes a-b/total" header;
+// -1 when absent or malformed.
+func rangeStart(cr string) int64 {
+	rest, ok := strings.CutPrefix(cr, "bytes ")
+	if !ok {
+		return -1
+	}
+	a, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(a, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// fileSHA256 is the hex sha256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,188 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func noSleep(context.Context, time.Duration) error { return nil }
+
+// failingReader yields its data and then a transport-style error, standing in
+// for a connection that drops mid-body.
+type failingReader struct{ r io.Reader }
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func tempFile(t *testing.T) *os.File {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "dl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func readAll(t *testing.T, f *os.File) string {
+	t.Helper()
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFetchResumable(t *testing.T) {
+	const full = "0123456789abcdefghij"
+	cut := func(s string) io.ReadCloser { return io.NopCloser(&failingReader{strings.NewReader(s)}) }
+	resp := func(code int, body io.ReadCloser, h http.Header) *http.Response {
+		if h == nil {
+			h = make(http.Header)
+		}
+		return &http.Response{StatusCode: code, Body: body, Header: h}
+	}
+
+	cases := []struct {
+		name      string
+		serve     func(call int, r *http.Request) *http.Response
+		wantErr   bool
+		wantCalls int
+		wantRange []string
+	}{
+		{
+			name: "resumes with Range after a mid-stream drop",
+			serve: func(call int, r *http.Request) *http.Response {
+				if call == 0 {
+					return resp(200, cut(full[:8]), nil)
+				}
+				h := http.Header{"Content-Range": {fmt.Sprintf("bytes 8-%d/%d", len(full)-1, len(full))}}
+				return resp(206, io.NopCloser(strings.NewReader(full[8:])), h)
+			},
+			wantCalls: 2,
+			wantRange: []string{"", "bytes=8-"},
+		},
+		{
+			name: "server ignoring Range restarts from zero",
+			serve: func(call int, r *http.Request) *http.Response {
+				if call == 0 {
+					return resp(200, cut(full[:8]), nil)
+				}
+				return resp(200, io.NopCloser(strings.NewReader(full)), nil)
+			},
+			wantCalls: 2,
+			wantRange: []string{"", "bytes=8-"},
+		},
+		{
+			name: "mismatched Content-Range is not trusted",
+			serve: func(call int, r *http.Request) *http.Response {
+				if call == 0 {
+					return resp(200, cut(full[:8]), nil)
+				}
+				h := http.Header{"Content-Range": {fmt.Sprintf("bytes 0-%d/%d", len(full)-1, len(full))}}
+				return resp(206, io.NopCloser(strings.NewReader(full)), h)
+			},
+			wantCalls: 2,
+			wantRange: []string{"", "bytes=8-"},
+		},
+		{
+			name: "5xx is retried",
+			serve: func(call int, r *http.Request) *http.Response {
+				if call < 2 {
+					return resp(503, io.NopCloser(strings.NewReader("")), nil)
+				}
+				return resp(200, io.NopCloser(strings.NewReader(full)), nil)
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "404 fails without retry",
+			serve: func(call int, r *http.Request) *http.Response {
+				return resp(404, io.NopCloser(strings.NewReader("")), nil)
+			},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name: "gives up after max attempts",
+			serve: func(call int, r *http.Request) *http.Response {
+				return resp(502, io.NopCloser(strings.NewReader("")), nil)
+			},
+			wantErr:   true,
+			wantCalls: downloadAttempts,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ranges []string
+			rt := &recordingTransport{serve: func(r *http.Request) (*http.Response, error) {
+				call := len(ranges)
+				ranges = append(ranges, r.Header.Get("Range"))
+				return tc.serve(call, r), nil
+			}}
+			g := &GitHub{HTTP: &http.Client{Transport: rt}, sleep: noSleep}
+			f := tempFile(t)
+			err := g.fetchResumable(context.Background(), "https://example.invalid/asset", f)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(ranges) != tc.wantCalls {
+				t.Fatalf("calls = %d, want %d", len(ranges), tc.wantCalls)
+			}
+			for i, want := range tc.wantRange {
+				if ranges[i] != want {
+					t.Errorf("call %d Range = %q, want %q", i, ranges[i], want)
+				}
+			}
+			if !tc.wantErr {
+				if got := readAll(t, f); got != full {
+					t.Fatalf("file = %q, want %q", got, full)
+				}
+			}
+		})
+	}
+}
+
+// TestFetchResumable_BackoffDoubles pins the retry schedule and that a
+// cancelled context stops the loop instead of sleeping through it.
+func TestFetchResumable_BackoffDoubles(t *testing.T) {
+	rt := &recordingTransport{serve: func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("dial tcp: network is unreachable")
+	}}
+	var waits []time.Duration
+	g := &GitHub{HTTP: &http.Client{Transport: rt}, sleep: func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}}
+	if err := g.fetchResumable(context.Background(), "https://example.invalid/asset", tempFile(t)); err == nil {
+		t.Fatal("expected error")
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if fmt.Sprint(waits) != fmt.Sprint(want) {
+		t.Fatalf("backoff = %v, want %v", waits, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g.sleep = nil
+	start := time.Now()
+	if err := g.fetchResumable(ctx, "https://example.invalid/asset", tempFile(t)); err == nil {
+		t.Fatal("expected error on cancelled context")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("cancelled context must not wait out the backoff")
+	}
+}
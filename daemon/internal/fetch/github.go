@@ -2,11 +2,11 @@ package fetch
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/eliteGoblin/focusd/daemon/internal/core"
@@ -19,44 +19,78 @@ type GitHub struct {
 	Repo  string // "owner/name"
 	Asset string // exact asset filename in the release (per os/arch)
 	HTTP  *http.Client
+	// Mirror, when set, replaces https://github.com as the base of every
+	// release DOWNLOAD (asset, checksums.txt, reachability probe); the mirror
+	// must serve the same {repo}/releases/download/{tag}/{asset} layout. Tag
+	// resolution (ResolveLatest/ResolveNewest) stays on api.github.com —
+	// mirrors carry assets, not the REST API. Trust is unchanged: a mirror can
+	// only withhold bytes, never substitute them, because every download is
+	// still Ed25519-verified.
+	Mirror string
+	// Proxy, when set, is the proxy URL for every request. Empty ⇒ the
+	// standard HTTPS_PROXY / HTTP_PROXY / NO_PROXY environment, which a
+	// launchd-spawned mesh member does not inherit from a login shell — hence
+	// an explicit, persisted value (see `daemon update --proxy`).
+	Proxy string
+	// Cache, when set, enables ETag conditional requests for the REST calls
+	// (tag resolution, release notes). nil ⇒ every call is unconditional.
+	Cache APICache
+	// sleep is the retry-backoff clock seam (nil ⇒ a real, ctx-aware timer);
+	// tests inject an instant one.
+	sleep func(context.Context, time.Duration) error
 }
 
 func (g *GitHub) client() *http.Client {
 	if g.HTTP != nil {
 		return g.HTTP
 	}
-	return &http.Client{Timeout: 60 * time.Second}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if u, err := url.Parse(g.Proxy); err == nil && g.Proxy != "" {
+		tr.Proxy = http.ProxyURL(u)
+	}
+	return &http.Client{Timeout: 60 * time.Second, Transport: tr}
 }
 
-type ghRelease struct {
-	TagName string `json:"tag_name"`
+// downloadURL is the release-download URL for asset at tag, on the mirror
+// when one is configured.
+func (g *GitHub) downloadURL(tag, asset string) string {
+	base := "https://github.com"
+	if g.Mirror != "" {
+		base = strings.TrimRight(g.Mirror, "/")
+	}
+	return fmt.Sprintf("%s/%s/releases/download/%s/%s", base, g.Repo, tag, asset)
 }
 
-func (g *GitHub) get(ctx context.Context, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// ValidMirror reports whether s is usable as GitHub.Mirror: an absolute
+// https URL with a host and no query or fragment (the path is appended to).
+func ValidMirror(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.RawQuery == "" && u.Fragment == ""
+}
+
+// ValidProxy reports whether s is usable as GitHub.Proxy: an http, https or
+// socks5 URL with a host.
+func ValidProxy(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
 	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	if tok := os.Getenv("GITHUB_TOKEN"); tok != "" {
-		req.Header.Set("Authorization", "Bearer "+tok)
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return true
 	}
-	return g.client().Do(req)
+	return false
+}
+
+type ghRelease struct {
+	TagName string `json:"tag_name"`
 }
 
 func (g *GitHub) ResolveLatest(ctx context.Context) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", g.Repo)
-	resp, err := g.get(ctx, url)
-	if err != nil {
-		return "", fmt.Errorf("fetch/github: latest: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("fetch/github: latest status %d", resp.StatusCode)
-	}
+	api := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", g.Repo)
 	var rel ghRelease
-	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
-		return "", fmt.Errorf("fetch/github: latest: decode response: %w", err)
+	if err := g.apiJSON(ctx, "latest", api, &rel); err != nil {
+		return "", err
 	}
 	if rel.TagName == "" {
 		return "", fmt.Errorf("fetch/github: empty tag")
@@ -64,6 +98,42 @@ func (g *GitHub) ResolveLatest(ctx context.Context) (string, error) {
 	return rel.TagName, nil
 }
 
+// ResolveNewest returns the tag of the newest published release INCLUDING
+// pre-releases (the beta channel). GitHub's /releases/latest deliberately
+// skips pre-releases, so this lists the most recent page instead (newest
+// first) and takes the first non-draft entry. Like ResolveLatest it is only
+// reached from an explicit `daemon update`, never from the reconcile loop.
+func (g *GitHub) ResolveNewest(ctx context.Context) (string, error) {
+	api := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=20", g.Repo)
+	var rels []struct {
+		TagName string `json:"tag_name"`
+		Draft   bool   `json:"draft"`
+	}
+	if err := g.apiJSON(ctx, "newest", api, &rels); err != nil {
+		return "", err
+	}
+	for _, r := range rels {
+		if !r.Draft && r.TagName != "" {
+			return r.TagName, nil
+		}
+	}
+	return "", fmt.Errorf("fetch/github: no published release")
+}
+
+// ReleaseNotes returns the body (changelog markdown) of the release tagged
+// tag — "" when the release has none. Display-only: it is shown by
+// `daemon update --check` and never influences what gets installed.
+func (g *GitHub) ReleaseNotes(ctx context.Context, tag string) (string, error) {
+	api := fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", g.Repo, url.PathEscape(tag))
+	var rel struct {
+		Body string `json:"body"`
+	}
+	if err := g.apiJSON(ctx, "notes", api, &rel); err != nil {
+		return "", err
+	}
+	return rel.Body, nil
+}
+
 // EnsureBinary downloads + Ed25519-verifies the configured asset for
 // version and places it at st.BinPath(version). Thin wrapper around
 // DownloadVerified, kept so existing reconcile callers don't change.
@@ -75,7 +145,8 @@ func (g *GitHub) EnsureBinary(ctx context.Context, st *core.Store, version strin
 // via the DIRECT release-download URL, Ed25519-verifies it, and
 // atomically writes the verified bytes (mode 0755) to dstPath. Returns
 // nil only when the bytes at dstPath are signed by the embedded focusd
-// public key.
+// public key (and, when the release carries a checksums.txt listing the
+// asset, match its sha256).
 //
 // ADR-0015: the install is always pinned to a concrete tag, so the asset
 // is reachable at
@@ -95,7 +166,7 @@ func (g *GitHub) EnsureBinary(ctx context.Context, st *core.Store, version strin
 // disguised binary basename in <workdir>). Pure boundary primitive —
 // no launchd, no relocation, no Spec.
 func (g *GitHub) DownloadVerified(ctx context.Context, tag, asset, dstPath string) error {
-	dlURL := fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", g.Repo, tag, asset)
+	dlURL := g.downloadURL(tag, asset)
 
 	// HF4 (FEATURE 24, C2): neutral temp-file prefix. The download temp is the
 	// only runtime disk path that would otherwise carry a literal "focusd" token
@@ -111,32 +182,30 @@ func (g *GitHub) DownloadVerified(ctx context.Context, tag, asset, dstPath strin
 	// Direct release download: dlURL 302s to signed objects.github CDN.
 	// Use a PLAIN request — octet-stream Accept and NO Authorization
 	// bearer forwarded to the redirect target (the repo is public, and a
-	// bearer to the CDN/S3 leg can break the signed-URL download).
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dlURL, nil)
-	if err != nil {
-		tmp.Close()
-		return err
-	}
-	req.Header.Set("Accept", "application/octet-stream")
-	dl, err := g.client().Do(req)
-	if err != nil {
-		tmp.Close()
-		return err
-	}
-	defer dl.Body.Close()
-	if dl.StatusCode != 200 {
-		tmp.Close()
-		return fmt.Errorf("fetch/github: download status %d", dl.StatusCode)
-	}
-	// Cap the body so a malicious/misconfigured release can't push an
-	// unbounded stream into the daemon.
-	const maxAsset = 512 << 20 // 512 MiB ceiling
-	if _, err := io.Copy(tmp, io.LimitReader(dl.Body, maxAsset)); err != nil {
+	// bearer to the CDN/S3 leg can break the signed-URL download). Transient
+	// failures are retried with backoff, each retry resuming from the bytes
+	// already in tmp (see fetchResumable).
+	if err := g.fetchResumable(ctx, dlURL, tmp); err != nil {
 		tmp.Close()
 		return err
 	}
 	tmp.Close()
 
+	// Cross-check against the release's checksums.txt when it lists this
+	// asset. A mismatch means the bytes are not what the release published —
+	// refuse before even reading the signature trailer. No manifest (or no
+	// entry) falls through to the signature check alone, which is always
+	// authoritative.
+	if want, listed := g.releaseChecksum(ctx, tag, asset); listed {
+		got, err := fileSHA256(tmpPath)
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("fetch/github: %s checksum mismatch — refusing", tag)
+		}
+	}
+
 	ok, err := sig.VerifyFile(tmpPath)
 	if err != nil {
 		return fmt.Errorf("fetch/github: verify: %w", err)
@@ -146,3 +215,28 @@ func (g *GitHub) DownloadVerified(ctx context.Context, tag, asset, dstPath strin
 	}
 	return placeVerified(tmpPath, dstPath)
 }
+
+// Reachable reports whether the direct release-download URL for asset at tag
+// answers 200 — i.e. whether the network fallback rung of the restore chain
+// would actually be able to fetch the binary right now. It issues a HEAD
+// against the same github.com path DownloadVerified uses (following the 302
+// to the release CDN), so it never touches the rate-limited api.github.com and
+// never downloads the body. A nil error means reachable; the error otherwise
+// names only the status code or transport failure, never a local path.
+func (g *GitHub) Reachable(ctx context.Context, tag, asset string) error {
+	dlURL := g.downloadURL(tag, asset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, dlURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch/github: reachable: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("fetch/github: reachable status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -204,9 +204,158 @@ func TestDownloadVerified_Non200(t *testing.T) {
 			}, nil
 		},
 	}
-	g := &GitHub{Repo: testRepo, Asset: testAsset, HTTP: &http.Client{Transport: rt}}
+	g := &GitHub{Repo: testRepo, Asset: testAsset, HTTP: &http.Client{Transport: rt}, sleep: noSleep}
 	dst := filepath.Join(t.TempDir(), "platform")
 	if err := g.DownloadVerified(context.Background(), testTag, testAsset, dst); err == nil {
 		t.Fatal("expected error on non-200 download")
 	}
 }
+
+// TestReachable_HeadOnDirectPath asserts the restore-chain probe is a HEAD on
+// the direct release-download path (never api.github.com) and maps the status
+// code to reachable / unreachable.
+func TestReachable_HeadOnDirectPath(t *testing.T) {
+	for _, tc := range []struct {
+		code    int
+		wantErr bool
+	}{
+		{200, false},
+		{404, true},
+		{503, true},
+	} {
+		var method string
+		rt := &recordingTransport{
+			serve: func(r *http.Request) (*http.Response, error) {
+				method = r.Method
+				return &http.Response{
+					StatusCode: tc.code,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}, nil
+			},
+		}
+		g := &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: rt}}
+		err := g.Reachable(context.Background(), testTag, testAsset)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("status %d: err = %v, wantErr %v", tc.code, err, tc.wantErr)
+		}
+		if method != http.MethodHead {
+			t.Fatalf("status %d: method = %q, want HEAD", tc.code, method)
+		}
+		wantPath := "/" + testRepo + "/releases/download/" + testTag + "/" + testAsset
+		if rt.hosts[0] != "github.com" || rt.paths[0] != wantPath {
+			t.Fatalf("status %d: hit %s%s, want github.com%s", tc.code, rt.hosts[0], rt.paths[0], wantPath)
+		}
+	}
+}
+
+// TestResolveNewest_SkipsDrafts: the beta channel takes the first non-draft
+// release from the newest-first listing, pre-release or not.
+func TestResolveNewest_SkipsDrafts(t *testing.T) {
+	listing := `[
+		{"tag_name":"v2.0.0-rc.2","draft":true,"prerelease":true},
+		{"tag_name":"v2.0.0-rc.1","draft":false,"prerelease":true},
+		{"tag_name":"v1.9.0","draft":false,"prerelease":false}
+	]`
+	rt := &recordingTransport{
+		serve: func(r *http.Request) (*http.Response, error) { return okBody([]byte(listing)), nil },
+	}
+	g := &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: rt}}
+	got, err := g.ResolveNewest(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveNewest: %v", err)
+	}
+	if got != "v2.0.0-rc.1" {
+		t.Fatalf("ResolveNewest = %q, want v2.0.0-rc.1", got)
+	}
+	if rt.paths[0] != "/repos/"+testRepo+"/releases" {
+		t.Fatalf("path = %q, want the releases listing", rt.paths[0])
+	}
+
+	rt.serve = func(r *http.Request) (*http.Response, error) { return okBody([]byte(`[]`)), nil }
+	if _, err := g.ResolveNewest(context.Background()); err == nil {
+		t.Fatal("empty listing must be an error")
+	}
+}
+
+// TestMirror_RoutesDownloadsOnly: with a mirror set, the asset download and
+// its checksums.txt go to the mirror host under the same path layout.
+func TestMirror_RoutesDownloadsOnly(t *testing.T) {
+	rt := &recordingTransport{
+		serve: func(r *http.Request) (*http.Response, error) { return okBody([]byte("unsigned")), nil },
+	}
+	g := &GitHub{Repo: testRepo, Asset: testAsset, Mirror: "https://mirror.example.com/gh/", HTTP: &http.Client{Transport: rt}}
+	_ = g.DownloadVerified(context.Background(), testTag, testAsset, filepath.Join(t.TempDir(), "platform"))
+	if len(rt.hosts) == 0 {
+		t.Fatal("no request made")
+	}
+	for i, h := range rt.hosts {
+		if h != "mirror.example.com" {
+			t.Fatalf("request %d went to %q, want the mirror — hosts: %v", i, h, rt.hosts)
+		}
+	}
+	want := "/gh/" + testRepo + "/releases/download/" + testTag + "/" + testAsset
+	if rt.paths[0] != want {
+		t.Fatalf("first path = %q, want %q", rt.paths[0], want)
+	}
+}
+
+func TestValidMirrorAndProxy(t *testing.T) {
+	for s, want := range map[string]bool{
+		"https://mirror.example.com":      true,
+		"https://mirror.example.com/gh":   true,
+		"http://mirror.example.com":       false, // plaintext mirror refused
+		"https://mirror.example.com/?x=1": false,
+		"mirror.example.com":              false,
+		"":                                false,
+	} {
+		if got := ValidMirror(s); got != want {
+			t.Errorf("ValidMirror(%q) = %v, want %v", s, got, want)
+		}
+	}
+	for s, want := range map[string]bool{
+		"http://proxy.corp:3128":  true,
+		"https://proxy.corp":      true,
+		"socks5://127.0.0.1:1080": true,
+		"ftp://proxy.corp":        false,
+		"proxy.corp:3128":         false,
+		"":                        false,
+	} {
+		if got := ValidProxy(s); got != want {
+			t.Errorf("ValidProxy(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+// TestClient_ProxyConfigured: an explicit Proxy wins over the environment.
+func TestClient_ProxyConfigured(t *testing.T) {
+	g := &GitHub{Proxy: "http://proxy.corp:3128"}
+	tr, ok := g.client().Transport.(*http.Transport)
+	if !ok || tr.Proxy == nil {
+		t.Fatal("client transport has no proxy func")
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://github.com/x", nil)
+	u, err := tr.Proxy(req)
+	if err != nil || u == nil || u.Host != "proxy.corp:3128" {
+		t.Fatalf("proxy = %v, %v; want proxy.corp:3128", u, err)
+	}
+}
+
+func TestReleaseNotes(t *testing.T) {
+	rt := &recordingTransport{
+		serve: func(r *http.Request) (*http.Response, error) {
+			return okBody([]byte(`{"tag_name":"v1.2.3","body":"- fixed a thing\n- added a thing"}`)), nil
+		},
+	}
+	g := &GitHub{Repo: testRepo, HTTP: &http.Client{Transport: rt}}
+	got, err := g.ReleaseNotes(context.Background(), testTag)
+	if err != nil {
+		t.Fatalf("ReleaseNotes: %v", err)
+	}
+	if got != "- fixed a thing\n- added a thing" {
+		t.Fatalf("notes = %q", got)
+	}
+	if want := "/repos/" + testRepo + "/releases/tags/" + testTag; rt.paths[0] != want {
+		t.Fatalf("path = %q, want %q", rt.paths[0], want)
+	}
+}
@@ -0,0 +1,177 @@
+// Package heartbeat sends the daemon's dead-man heartbeat: a small signed
+// POST to an operator-configured focusd server, made by the platform-lock
+// holder every few minutes. The server side — noticing that beats stopped
+// and telling the accountability partner — is what local protection cannot
+// do for itself: a determined user can always erase everything on the
+// machine, but not the fact that it went quiet.
+//
+// Each install signs with its own Ed25519 key (the seed lives in the masked
+// version.json; see core.Store.HeartbeatSeed), enrolled with the server by
+// its public key. The release signing key is never involved — the daemon
+// cannot sign with it. The endpoint URL may carry a token, so it is never
+// logged; failures name the error type only.
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Request headers. The signature covers the exact request body.
+const (
+	DeviceHeader    = "X-Focusd-Device"
+	SignatureHeader = "X-Focusd-Signature"
+)
+
+// Beat is one heartbeat; it is the JSON request body.
+type Beat struct {
+	Device string    `json:"device"`
+	At     time.Time `json:"at"`
+	// IntervalS is the sender's beat interval in seconds, so the server can
+	// judge "overdue" without out-of-band configuration.
+	IntervalS int64 `json:"interval_s"`
+	// Version is the desired platform version ("" before the first update).
+	Version string `json:"version,omitempty"`
+	// Restores counts protection restores (binary or launchd job) since the
+	// previous beat — a device that keeps beating but keeps being repaired
+	// is being fought with.
+	Restores int `json:"restores"`
+}
+
+// KeyFromSeed builds the signing key from a stored 32-byte seed.
+func KeyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("heartbeat: seed is %d bytes, want %d", len(seed), ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// DeviceID is the short, stable id the server files beats under: the first
+// 16 hex digits of the public key's SHA-256.
+func DeviceID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// PublicKeyString is the form a device is enrolled with (standard base64).
+func PublicKeyString(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// Sign returns the base64 signature of body.
+func Sign(key ed25519.PrivateKey, body []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))
+}
+
+// Verify is the server's check: sig is Sign(key, body) for pub's key.
+func Verify(pub ed25519.PublicKey, body []byte, sig string) bool {
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	return err == nil && len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, body, raw)
+}
+
+// ValidEndpoint reports whether s is an absolute http(s) URL with a host.
+func ValidEndpoint(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Config returns the current endpoint and signing key; an empty endpoint or
+// nil key means heartbeats are off.
+type Config func() (endpoint string, key ed25519.PrivateKey)
+
+// Sender posts queued beats. It holds at most one pending beat: a beat that
+// cannot be sent is superseded by the next rather than retried, since a
+// fresh beat says everything a stale one would.
+type Sender struct {
+	Config Config
+	Client *http.Client
+	Log    *slog.Logger
+
+	queue chan Beat
+}
+
+// New builds a Sender; call Run to start delivery.
+func New(cfg Config, log *slog.Logger) *Sender {
+	return &Sender{
+		Config: cfg,
+		Client: &http.Client{Timeout: 15 * time.Second},
+		Log:    log,
+		queue:  make(chan Beat, 1),
+	}
+}
+
+// Pulse enqueues b without blocking; it is dropped if a beat is pending.
+func (s *Sender) Pulse(b Beat) {
+	select {
+	case s.queue <- b:
+	default:
+	}
+}
+
+// Run sends queued beats until ctx is done.
+func (s *Sender) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case b := <-s.queue:
+			if err := s.Send(ctx, b); err != nil && !errors.Is(err, errOff) {
+				s.Log.Warn("heartbeat failed", "err", fmt.Sprintf("%T", errors.Unwrap(err)))
+			}
+		}
+	}
+}
+
+// errOff is returned by Send when no endpoint or key is configured.
+var errOff = errors.New("heartbeat: not configured")
+
+// Send signs and POSTs b synchronously, filling in the device id. Errors
+// never carry the endpoint URL.
+func (s *Sender) Send(ctx context.Context, b Beat) error {
+	endpoint, key := s.Config()
+	if endpoint == "" || key == nil {
+		return errOff
+	}
+	b.Device = DeviceID(key.Public().(ed25519.PublicKey))
+	body, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("heartbeat: bad endpoint")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeviceHeader, b.Device)
+	req.Header.Set(SignatureHeader, Sign(key, body))
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapper: its text embeds the endpoint.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat: %w", statusError(resp.StatusCode))
+	}
+	return nil
+}
+
+// statusError is a non-2xx reply.
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("HTTP %d", int(e)) }
@@ -0,0 +1,93 @@
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	key, err := KeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSendSignsBody(t *testing.T) {
+	key := testKey(t)
+	pub := key.Public().(ed25519.PublicKey)
+	var got Beat
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify(pub, body, r.Header.Get(SignatureHeader)) {
+			t.Error("signature does not verify against the device key")
+		}
+		if r.Header.Get(DeviceHeader) != DeviceID(pub) {
+			t.Errorf("device header = %q", r.Header.Get(DeviceHeader))
+		}
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	s := New(func() (string, ed25519.PrivateKey) { return srv.URL + "/beat", key }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.Send(context.Background(), Beat{At: at, IntervalS: 300, Version: "v1.2.0", Restores: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Device != DeviceID(pub) || !got.At.Equal(at) || got.IntervalS != 300 || got.Restores != 2 {
+		t.Fatalf("server saw %+v", got)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	key := testKey(t)
+	pub := key.Public().(ed25519.PublicKey)
+	sig := Sign(key, []byte(`{"restores":0}`))
+	if Verify(pub, []byte(`{"restores":9}`), sig) {
+		t.Fatal("tampered body verified")
+	}
+	if Verify(pub, []byte(`{"restores":0}`), "not-base64!") {
+		t.Fatal("garbage signature verified")
+	}
+}
+
+func TestSendErrorOmitsEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	url := srv.URL + "/beat?token=SECRET"
+	srv.Close() // connection refused
+	key := testKey(t)
+	s := New(func() (string, ed25519.PrivateKey) { return url, key }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	err := s.Send(context.Background(), Beat{At: time.Now()})
+	if err == nil || strings.Contains(err.Error(), "SECRET") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSendOffWhenUnconfigured(t *testing.T) {
+	s := New(func() (string, ed25519.PrivateKey) { return "", nil }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := s.Send(context.Background(), Beat{}); err != errOff {
+		t.Fatalf("err = %v, want errOff", err)
+	}
+}
+
+func TestPulseNeverBlocks(t *testing.T) {
+	s := New(func() (string, ed25519.PrivateKey) { return "", nil }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for i := 0; i < 5; i++ {
+		s.Pulse(Beat{Restores: i}) // no Run: only the first is held
+	}
+	if b := <-s.queue; b.Restores != 0 {
+		t.Fatalf("pending beat = %+v, want the first", b)
+	}
+}
@@ -0,0 +1,103 @@
+// Package lockin is the strict-mode lock (`daemon lock --days N`): for the
+// chosen period every weakening path — uninstall, rollback, stopping the
+// heartbeat, break tokens — refuses, and nothing turns the lock off early.
+//
+// The end is anchored against clock tampering. A lock ends only when BOTH
+// the wall clock has passed Until AND the daemon has credited its full
+// length. Credit comes from the monotonic clock while the daemon runs (Ran)
+// and the sleep the kernel counted (Slept), plus wall-clock gaps neither saw
+// (shutdown) up to an allowance tied to Ran (Gap). So moving the clock
+// forward earns at most the allowance, and moving it back only prolongs the
+// lock. The cost is honest:
+// a machine left off for weeks comes back still locked for the remainder.
+package lockin
+
+import (
+	"fmt"
+	"time"
+)
+
+// gapSlack and gapRatio bound the unobserved time a lock may credit: a day,
+// plus the time the daemon was actually seen running. Sleep is observed and
+// needs none of it, so the allowance only has shutdowns to cover; a clock
+// pushed forward every day at most doubles the pace of a lock.
+const (
+	gapSlack = 24 * time.Hour
+	gapRatio = 1
+)
+
+// MaxDays caps a single lock. A typo must not lock the machine for years.
+const MaxDays = 365
+
+// Lock is the persisted lock. The zero Lock is "never locked".
+type Lock struct {
+	// Until is the wall-clock end; Length is the time that must be credited.
+	Until  time.Time `json:"until"`
+	Length int64     `json:"length_s"`
+	// Ran is monotonic time the daemon saw pass and Slept the sleep the
+	// kernel counted; Gap is credited unobserved wall time. LastSeen is the
+	// latest wall time credited: the clock never moves it backwards.
+	Ran      int64     `json:"ran_s"`
+	Slept    int64     `json:"slept_s,omitempty"`
+	Gap      int64     `json:"gap_s"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+func secs(d time.Duration) int64 { return int64(d / time.Second) }
+
+// Credited is the time counted toward Length so far.
+func (l Lock) Credited() time.Duration { return time.Duration(l.Ran+l.Slept+l.Gap) * time.Second }
+
+// Remaining is how long the lock still holds at now: the longer of the wall
+// clock's view and the credit still owed. Zero once it has ended.
+func (l Lock) Remaining(now time.Time) time.Duration {
+	owed := time.Duration(l.Length)*time.Second - l.Credited()
+	return max(l.Until.Sub(now), owed, 0)
+}
+
+// Active reports whether the lock holds at now.
+func (l Lock) Active(now time.Time) bool { return l.Remaining(now) > 0 }
+
+// Extend locks for length from now. An active lock is only ever lengthened:
+// the result holds at least as long as both the old lock and the new one.
+func (l Lock) Extend(now time.Time, length time.Duration) Lock {
+	if !l.Active(now) {
+		return Lock{Until: now.Add(length), Length: secs(length), LastSeen: now}
+	}
+	rem := max(l.Remaining(now), length)
+	l.Length = secs(l.Credited() + rem)
+	if end := now.Add(length); end.After(l.Until) {
+		l.Until = end
+	}
+	if now.After(l.LastSeen) {
+		l.LastSeen = now
+	}
+	return l
+}
+
+// Credit records that ran of monotonic time passed awake, and slept asleep,
+// up to now. Any wall time since LastSeen beyond both counts as a gap,
+// within the allowance.
+func (l Lock) Credit(now time.Time, ran, slept time.Duration) Lock {
+	ran, slept = max(ran, 0), max(slept, 0)
+	l.Ran += secs(ran)
+	l.Slept += secs(slept)
+	if wall := now.Sub(l.LastSeen); !l.LastSeen.IsZero() && wall > ran+slept {
+		allow := gapSlack + gapRatio*time.Duration(l.Ran)*time.Second - time.Duration(l.Gap)*time.Second
+		l.Gap += secs(min(wall-ran-slept, max(allow, 0)))
+	}
+	if now.After(l.LastSeen) {
+		l.LastSeen = now
+	}
+	return l
+}
+
+// FormatLeft renders a remaining duration coarsely: days and hours, or hours
+// and minutes under a day.
+func FormatLeft(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd %dh", d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	}
+	return fmt.Sprintf("%dh %dm", d/time.Hour, d%time.Hour/time.Minute)
+}
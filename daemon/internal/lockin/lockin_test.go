@@ -0,0 +1,112 @@
+package lockin
+
+import (
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+func TestLockEndsWhenWallAndCreditBothPass(t *testing.T) {
+	l := Lock{}.Extend(t0, 48*time.Hour)
+	if !l.Active(t0) || l.Remaining(t0) != 48*time.Hour {
+		t.Fatalf("fresh lock: remaining %v", l.Remaining(t0))
+	}
+	// Running honestly for two days, crediting every minute.
+	now := t0
+	for i := 0; i < 48*60; i++ {
+		now = now.Add(time.Minute)
+		l = l.Credit(now, time.Minute, 0)
+	}
+	if l.Active(now) {
+		t.Fatalf("lock still active after its length: %+v", l)
+	}
+}
+
+func TestClockForwardEarnsOnlyTheAllowance(t *testing.T) {
+	l := Lock{}.Extend(t0, 30*24*time.Hour)
+	// Jump the clock 40 days ahead, one minute of real running.
+	jump := t0.Add(40 * 24 * time.Hour)
+	l = l.Credit(jump, time.Minute, 0)
+	if !l.Active(jump) {
+		t.Fatal("a forward clock jump ended the lock")
+	}
+	if got := l.Credited(); got > gapSlack+4*time.Minute {
+		t.Fatalf("credited %v for a jump, want about a day", got)
+	}
+	// Repeating the jump earns nothing more.
+	again := jump.Add(40 * 24 * time.Hour)
+	before := l.Credited()
+	l = l.Credit(again, time.Minute, 0)
+	if l.Credited()-before > 4*time.Minute {
+		t.Fatalf("second jump credited %v", l.Credited()-before)
+	}
+}
+
+func TestObservedSleepCountsInFull(t *testing.T) {
+	l := Lock{}.Extend(t0, 7*24*time.Hour)
+	// Eight hours up, sixteen asleep, for a week: on time, no allowance used.
+	now := t0
+	for range 7 {
+		now = now.Add(8 * time.Hour)
+		l = l.Credit(now, 8*time.Hour, 0)
+		now = now.Add(16 * time.Hour)
+		l = l.Credit(now, 0, 16*time.Hour)
+	}
+	if l.Active(now) || l.Gap != 0 {
+		t.Fatalf("an honest week did not end the lock: %+v", l)
+	}
+}
+
+func TestDailyJumpsAtMostDoubleThePace(t *testing.T) {
+	l := Lock{}.Extend(t0, 30*24*time.Hour)
+	// Each real day: a day of running, then the clock pushed ten days on.
+	now := t0
+	for day := 1; day <= 14; day++ {
+		for range 24 {
+			now = now.Add(time.Hour)
+			l = l.Credit(now, time.Hour, 0)
+		}
+		now = now.Add(10 * 24 * time.Hour)
+		l = l.Credit(now, time.Minute, 0)
+		if !l.Active(now) {
+			t.Fatalf("a 30-day lock ended after %d real days", day)
+		}
+	}
+}
+
+func TestClockBackwardOnlyProlongs(t *testing.T) {
+	l := Lock{}.Extend(t0, time.Hour)
+	back := t0.Add(-24 * time.Hour)
+	l = l.Credit(back, time.Minute, 0)
+	if !l.LastSeen.Equal(t0) || l.Gap != 0 {
+		t.Fatalf("backward clock moved LastSeen or credited a gap: %+v", l)
+	}
+	if got := l.Remaining(back); got < 24*time.Hour {
+		t.Fatalf("remaining %v, want at least the wall-clock day", got)
+	}
+}
+
+func TestExtendNeverShortens(t *testing.T) {
+	l := Lock{}.Extend(t0, 10*24*time.Hour)
+	l2 := l.Extend(t0.Add(time.Hour), time.Hour)
+	if l2.Remaining(t0.Add(time.Hour)) < l.Remaining(t0.Add(time.Hour)) {
+		t.Fatalf("re-lock shortened: %v < %v", l2.Remaining(t0.Add(time.Hour)), l.Remaining(t0.Add(time.Hour)))
+	}
+	l3 := l.Extend(t0, 20*24*time.Hour)
+	if l3.Remaining(t0) != 20*24*time.Hour {
+		t.Fatalf("re-lock longer: remaining %v", l3.Remaining(t0))
+	}
+}
+
+func TestFormatLeft(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		30 * 24 * time.Hour:             "30d 0h",
+		26*time.Hour + 10*time.Minute:   "1d 2h",
+		90*time.Minute + 20*time.Second: "1h 30m",
+	} {
+		if got := FormatLeft(d); got != want {
+			t.Errorf("FormatLeft(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
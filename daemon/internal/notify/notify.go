@@ -0,0 +1,324 @@
+// Package notify delivers significant protection events — a deleted daemon
+// binary or launchd job restored, a burst of such restores, an automatic
+// update rollback — to operator-configured webhooks (Slack, Discord, ntfy, or
+// plain JSON).
+//
+// Delivery is best-effort and off the reconcile path: Notify only enqueues,
+// a single worker POSTs with retry + exponential backoff, and a full queue
+// drops the event rather than stall a heal tick. Webhook URLs carry secrets
+// (a Slack hook URL IS the credential), so they are never logged; failures
+// name the hook's format only. Event payloads never carry a path.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind names an event.
+type Kind string
+
+const (
+	// BinaryRestored: a mesh worker re-materialized the deleted daemon binary.
+	BinaryRestored Kind = "binary_restored"
+	// MeshRestored: one or more launchd jobs (plists) were missing and recreated.
+	MeshRestored Kind = "mesh_restored"
+	// RepeatedRestores: restoreBurst restores inside restoreWindow — someone
+	// is repeatedly killing or deleting the protection.
+	RepeatedRestores Kind = "repeated_restores"
+	// UpdateRolledBack: a crash-looping platform version was rolled back to
+	// the last-known-good one.
+	UpdateRolledBack Kind = "update_rolled_back"
+	// DaemonHung: the mesh workers were loaded but had stopped checking in,
+	// so the companion's watchdog pass restarted them.
+	DaemonHung Kind = "daemon_hung"
+	// DecoyRemoved: a decoy launchd entry was deleted or booted out — someone
+	// is hunting for the mesh. The entry was put back.
+	DecoyRemoved Kind = "decoy_removed"
+	// DebuggerAttached: a mesh worker found a debugger attached to its
+	// sibling and restarted it.
+	DebuggerAttached Kind = "debugger_attached"
+	// GateStarted, GateCode and GateOpened go to the accountability
+	// partner alone: the first uninstall step was done, here is the code
+	// that approves the uninstall, and the code was entered.
+	GateStarted Kind = "uninstall_gate_started"
+	GateCode    Kind = "uninstall_gate_code"
+	GateOpened  Kind = "uninstall_gate_opened"
+	// PartnerRemoved: the partner was replaced or removed; sent to the
+	// outgoing partner.
+	PartnerRemoved Kind = "partner_removed"
+	// RecoveryUsed: the emergency recovery code was used to skip the
+	// uninstall gate and any strict lock.
+	RecoveryUsed Kind = "recovery_code_used"
+	// Test is sent by `daemon notify --test`.
+	Test Kind = "test"
+)
+
+// Event is one notification. It is also the body of a FormatJSON hook.
+type Event struct {
+	Kind    Kind              `json:"kind"`
+	At      time.Time         `json:"at"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Webhook payload formats.
+const (
+	FormatJSON    = "json"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+	FormatNtfy    = "ntfy"
+)
+
+// Hook is one configured webhook.
+type Hook struct {
+	Format string
+	URL    string
+}
+
+// ParseHook parses the persisted/CLI form FORMAT=URL. The URL must be
+// absolute http(s) with a host; an ntfy URL must name its topic.
+func ParseHook(s string) (Hook, error) {
+	format, raw, ok := strings.Cut(s, "=")
+	if !ok {
+		return Hook{}, errors.New("webhook must be FORMAT=URL")
+	}
+	switch format {
+	case FormatJSON, FormatSlack, FormatDiscord, FormatNtfy:
+	default:
+		return Hook{}, fmt.Errorf("unknown webhook format %q", format)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return Hook{}, errors.New("webhook URL must be an absolute http(s) URL")
+	}
+	if format == FormatNtfy && strings.Trim(u.Path, "/") == "" {
+		return Hook{}, errors.New("ntfy webhook URL must include the topic, e.g. https://ntfy.sh/mytopic")
+	}
+	return Hook{Format: format, URL: raw}, nil
+}
+
+// String is the persisted form (FORMAT=URL). It contains the secret URL.
+func (h Hook) String() string { return h.Format + "=" + h.URL }
+
+// Redacted is safe to print: the format and host only.
+func (h Hook) Redacted() string {
+	host := "?"
+	if u, err := url.Parse(h.URL); err == nil {
+		host = u.Host
+	}
+	return h.Format + " (" + host + ")"
+}
+
+// request builds the POST for ev in h's format.
+func (h Hook) request(ctx context.Context, ev Event) (*http.Request, error) {
+	text := "focusd: " + ev.Message
+	var body any
+	target := h.URL
+	switch h.Format {
+	case FormatSlack:
+		body = map[string]string{"text": text}
+	case FormatDiscord:
+		body = map[string]string{"content": text}
+	case FormatNtfy:
+		// ntfy's JSON publish form: POST to the server root with the topic
+		// in the body (posting JSON to the topic URL would send it as text).
+		u, err := url.Parse(h.URL)
+		if err != nil {
+			return nil, err
+		}
+		topic := strings.Trim(u.Path, "/")
+		u.Path, u.RawQuery = "/", ""
+		target = u.String()
+		body = map[string]any{"topic": topic, "title": "focusd", "message": ev.Message, "tags": []string{string(ev.Kind)}}
+	default:
+		body = ev
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+const (
+	// deliverAttempts / deliverBackoff: a hook is tried up to deliverAttempts
+	// times, waiting deliverBackoff, then double that, between tries.
+	deliverAttempts = 4
+	deliverBackoff  = 2 * time.Second
+	// queueSize bounds undelivered events; beyond it new events are dropped.
+	queueSize = 32
+	// restoreBurst restores within restoreWindow raise RepeatedRestores.
+	restoreBurst  = 3
+	restoreWindow = 10 * time.Minute
+)
+
+// errPermanent marks a response a retry cannot fix (4xx other than 429).
+var errPermanent = errors.New("permanent")
+
+// Notifier queues events and delivers them to the current hooks.
+type Notifier struct {
+	// Hooks returns the configured hooks at delivery time, so a
+	// `daemon notify` change applies without a restart.
+	Hooks  func() []Hook
+	Client *http.Client
+	Log    *slog.Logger
+
+	queue    chan Event
+	sleep    func(context.Context, time.Duration) error
+	now      func() time.Time
+	mu       sync.Mutex
+	restores []time.Time
+}
+
+// New builds a Notifier; call Run to start delivery.
+func New(hooks func() []Hook, log *slog.Logger) *Notifier {
+	return &Notifier{
+		Hooks:  hooks,
+		Client: &http.Client{Timeout: 10 * time.Second},
+		Log:    log,
+		queue:  make(chan Event, queueSize),
+		sleep:  sleepCtx,
+		now:    time.Now,
+	}
+}
+
+// Notify enqueues ev without blocking. A restore event also feeds the burst
+// detector, which enqueues one RepeatedRestores per burst.
+func (n *Notifier) Notify(ev Event) {
+	if ev.At.IsZero() {
+		ev.At = n.now()
+	}
+	n.enqueue(ev)
+	if ev.Kind == BinaryRestored || ev.Kind == MeshRestored {
+		if count, burst := n.recordRestore(ev.At); burst {
+			n.enqueue(Event{Kind: RepeatedRestores, At: ev.At,
+				Message: fmt.Sprintf("protection restored %d times in %s — something keeps removing it", count, restoreWindow),
+				Details: map[string]string{"count": fmt.Sprint(count), "window": restoreWindow.String()}})
+		}
+	}
+}
+
+func (n *Notifier) enqueue(ev Event) {
+	select {
+	case n.queue <- ev:
+	default:
+		n.Log.Warn("notify queue full; event dropped", "kind", ev.Kind)
+	}
+}
+
+// recordRestore tracks restores in the sliding window. It reports a burst
+// once the window holds restoreBurst, then starts counting afresh so a
+// sustained attack notifies once per burst, not once per tick.
+func (n *Notifier) recordRestore(at time.Time) (int, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	kept := n.restores[:0]
+	for _, t := range n.restores {
+		if at.Sub(t) < restoreWindow {
+			kept = append(kept, t)
+		}
+	}
+	n.restores = append(kept, at)
+	if len(n.restores) < restoreBurst {
+		return 0, false
+	}
+	count := len(n.restores)
+	n.restores = n.restores[:0]
+	return count, true
+}
+
+// Run delivers queued events until ctx is done.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-n.queue:
+			_ = n.Deliver(ctx, ev)
+		}
+	}
+}
+
+// Deliver sends ev to every hook synchronously, with retries. It returns
+// the first failure; a failing hook never stops delivery to the others.
+func (n *Notifier) Deliver(ctx context.Context, ev Event) error {
+	var first error
+	for _, h := range n.Hooks() {
+		if err := n.deliverOne(ctx, h, ev); err != nil {
+			n.Log.Warn("webhook delivery failed", "format", h.Format, "kind", ev.Kind)
+			if first == nil {
+				first = fmt.Errorf("%s webhook: %w", h.Format, err)
+			}
+		}
+	}
+	return first
+}
+
+func (n *Notifier) deliverOne(ctx context.Context, h Hook, ev Event) error {
+	wait := deliverBackoff
+	var err error
+	for attempt := 1; attempt <= deliverAttempts; attempt++ {
+		if err = n.post(ctx, h, ev); err == nil || errors.Is(err, errPermanent) {
+			return err
+		}
+		if attempt == deliverAttempts {
+			break
+		}
+		if serr := n.sleep(ctx, wait); serr != nil {
+			return serr
+		}
+		wait *= 2
+	}
+	return err
+}
+
+func (n *Notifier) post(ctx context.Context, h Hook, ev Event) error {
+	req, err := h.request(ctx, ev)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapper: its text embeds the secret URL.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: HTTP %d", errPermanent, resp.StatusCode)
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func noSleep(context.Context, time.Duration) error { return nil }
+
+func testNotifier(hooks ...Hook) *Notifier {
+	n := New(func() []Hook { return hooks }, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	n.sleep = noSleep
+	return n
+}
+
+// recorder is a webhook endpoint that fails the first `fail` requests with
+// `status`, then accepts, keeping every body it saw.
+type recorder struct {
+	mu     sync.Mutex
+	fail   int
+	status int
+	paths  []string
+	bodies []map[string]any
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var body map[string]any
+	_ = json.NewDecoder(req.Body).Decode(&body)
+	r.paths = append(r.paths, req.URL.Path)
+	r.bodies = append(r.bodies, body)
+	if len(r.bodies) <= r.fail {
+		w.WriteHeader(r.status)
+	}
+}
+
+func TestParseHook(t *testing.T) {
+	for _, tc := range []struct {
+		in string
+		ok bool
+	}{
+		{"slack=https://hooks.slack.com/services/T/B/X", true},
+		{"discord=https://discord.com/api/webhooks/1/x", true},
+		{"ntfy=https://ntfy.sh/focus-alerts", true},
+		{"json=http://127.0.0.1:9000/hook", true},
+		{"ntfy=https://ntfy.sh/", false}, // no topic
+		{"teams=https://example.com/x", false},
+		{"slack=ftp://example.com/x", false},
+		{"slack=/relative", false},
+		{"https://hooks.slack.com/x", false}, // no FORMAT=
+	} {
+		if _, err := ParseHook(tc.in); (err == nil) != tc.ok {
+			t.Errorf("ParseHook(%q) err=%v, want ok=%v", tc.in, err, tc.ok)
+		}
+	}
+}
+
+func TestRedactedHidesSecret(t *testing.T) {
+	h, _ := ParseHook("slack=https://hooks.slack.com/services/T/B/SECRET")
+	if r := h.Redacted(); strings.Contains(r, "SECRET") || !strings.Contains(r, "hooks.slack.com") {
+		t.Fatalf("Redacted() = %q", r)
+	}
+}
+
+func TestDeliverFormats(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	n := testNotifier(
+		Hook{FormatSlack, srv.URL + "/slack"},
+		Hook{FormatDiscord, srv.URL + "/discord"},
+		Hook{FormatNtfy, srv.URL + "/alerts"},
+		Hook{FormatJSON, srv.URL + "/json"},
+	)
+	ev := Event{Kind: MeshRestored, At: time.Unix(0, 0), Message: "launchd job restored"}
+	if err := n.Deliver(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 4 {
+		t.Fatalf("got %d posts, want 4", len(rec.bodies))
+	}
+	if got := rec.bodies[0]["text"]; got != "focusd: launchd job restored" {
+		t.Errorf("slack text = %v", got)
+	}
+	if got := rec.bodies[1]["content"]; got != "focusd: launchd job restored" {
+		t.Errorf("discord content = %v", got)
+	}
+	if rec.paths[2] != "/" || rec.bodies[2]["topic"] != "alerts" {
+		t.Errorf("ntfy posted to %q with topic %v, want root + topic", rec.paths[2], rec.bodies[2]["topic"])
+	}
+	if rec.bodies[3]["kind"] != string(MeshRestored) {
+		t.Errorf("json kind = %v", rec.bodies[3]["kind"])
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	cases := []struct {
+		name      string
+		fail      int
+		status    int
+		wantPosts int
+		wantErr   bool
+	}{
+		{"recovers after 5xx", 2, http.StatusBadGateway, 3, false},
+		{"retries 429", 1, http.StatusTooManyRequests, 2, false},
+		{"gives up after attempts", 10, http.StatusInternalServerError, deliverAttempts, true},
+		{"4xx is permanent", 10, http.StatusNotFound, 1, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{fail: tc.fail, status: tc.status}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+			n := testNotifier(Hook{FormatJSON, srv.URL})
+			var waits []time.Duration
+			n.sleep = func(_ context.Context, d time.Duration) error { waits = append(waits, d); return nil }
+			err := n.Deliver(context.Background(), Event{Kind: Test, Message: "x"})
+			if (err != nil) != tc.wantErr || len(rec.bodies) != tc.wantPosts {
+				t.Fatalf("err=%v posts=%d, want err=%v posts=%d", err, len(rec.bodies), tc.wantErr, tc.wantPosts)
+			}
+			for i, w := range waits {
+				if want := deliverBackoff << i; w != want {
+					t.Errorf("wait %d = %v, want %v", i, w, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliverErrorOmitsURL(t *testing.T) {
+	n := testNotifier(Hook{FormatSlack, "http://127.0.0.1:1/services/SECRET"})
+	err := n.Deliver(context.Background(), Event{Kind: Test, Message: "x"})
+	if err == nil || strings.Contains(err.Error(), "SECRET") {
+		t.Fatalf("err = %v; want a failure that does not echo the URL", err)
+	}
+}
+
+func TestNotifyRestoreBurst(t *testing.T) {
+	n := testNotifier()
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	kinds := func() []Kind {
+		var ks []Kind
+		for len(n.queue) > 0 {
+			ks = append(ks, (<-n.queue).Kind)
+		}
+		return ks
+	}
+
+	// Two restores, then one outside the window: no burst.
+	n.Notify(Event{Kind: MeshRestored, At: base})
+	n.Notify(Event{Kind: BinaryRestored, At: base.Add(time.Minute)})
+	n.Notify(Event{Kind: MeshRestored, At: base.Add(restoreWindow + 2*time.Minute)})
+	if got := kinds(); len(got) != 3 {
+		t.Fatalf("queued %v, want the three restores only", got)
+	}
+
+	// Two more inside the window complete a burst of three, reported once.
+	n.Notify(Event{Kind: MeshRestored, At: base.Add(restoreWindow + 3*time.Minute)})
+	n.Notify(Event{Kind: MeshRestored, At: base.Add(restoreWindow + 4*time.Minute)})
+	got := kinds()
+	if len(got) != 3 || got[2] != RepeatedRestores {
+		t.Fatalf("queued %v, want two restores then %s", got, RepeatedRestores)
+	}
+
+	// Non-restore events never count toward a burst.
+	for i := 0; i < restoreBurst; i++ {
+		n.Notify(Event{Kind: UpdateRolledBack, At: base})
+	}
+	for _, k := range kinds() {
+		if k == RepeatedRestores {
+			t.Fatal("rollback events must not raise a restore burst")
+		}
+	}
+}
+
+func TestNotifyDropsWhenFull(t *testing.T) {
+	n := testNotifier()
+	for i := 0; i < queueSize+5; i++ {
+		n.Notify(Event{Kind: Test, Message: "x"}) // must never block
+	}
+	if len(n.queue) != queueSize {
+		t.Fatalf("queue len %d, want %d", len(n.queue), queueSize)
+	}
+}
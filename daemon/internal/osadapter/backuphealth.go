@@ -0,0 +1,94 @@
+package osadapter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/companion"
+	"github.com/eliteGoblin/focusd/daemon/internal/sig"
+)
+
+// BackupHealth is the companion's offline daemon backup as `daemon status`
+// reports it. Only primitives cross this boundary — never the disguised backup
+// path — so the status renderer can show whether the restore chain would work
+// without learning where it lives.
+type BackupHealth struct {
+	// Present: a backup file exists. Verified: it passes Ed25519 verification.
+	// Present && !Verified is the "corrupt" case (truncated, tampered, or an
+	// unsigned stand-in) — the companion would refuse to promote it.
+	Present  bool
+	Verified bool
+	// RefreshedAt is the backup's mtime (zero when absent). The backup is only
+	// rewritten when its bytes change, so this is "last changed", which is
+	// what matters for "does the copy track the running generation".
+	RefreshedAt time.Time
+	// Pin is the platform version the companion will rebuild with on restore
+	// ("" when unset or not a valid tag). Version strings are not disguised.
+	Pin string
+}
+
+// backupHealth is the seam-injected core of CompanionBackupHealth: stat + verify
+// the backup and read the companion's desired pin. OS-agnostic (it only reads
+// files under a companion.Dir), so it is unit-tested off-darwin.
+func backupHealth(dir companion.Dir, verify func(string) (bool, error)) BackupHealth {
+	var h BackupHealth
+	if fi, err := os.Stat(dir.Backup()); err == nil && !fi.IsDir() {
+		h.Present = true
+		h.RefreshedAt = fi.ModTime()
+		if ok, err := verify(dir.Backup()); err == nil && ok {
+			h.Verified = true
+		}
+	}
+	if b, err := os.ReadFile(dir.Desired()); err == nil {
+		if v := strings.TrimSpace(string(b)); companion.IsValidVersion(v) {
+			h.Pin = v
+		}
+	}
+	return h
+}
+
+// verifyBackup is sig.VerifyFile for the companion's backup or spare, which
+// are stored packed (companion.Pack).
+func verifyBackup(path string) (bool, error) {
+	b, err := companion.ReadBackup(path)
+	if err != nil {
+		return false, err
+	}
+	return sig.VerifyBytes(b)
+}
+
+// backupSize is the size of the daemon the backup at path holds, read from
+// the packed header alone (a legacy plain copy: its file size). ok is false
+// when there is no backup.
+func backupSize(path string) (int64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	head := make([]byte, 16) // magic + unpacked size
+	n, _ := io.ReadFull(f, head)
+	if size, ok := companion.UnpackedSize(head[:n]); ok {
+		return size, true
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		return 0, false
+	}
+	return fi.Size(), true
+}
+
+// backupDiffers reports whether the backup at path is absent or holds other
+// bytes than want. The header's size gate skips the unpack when the sizes
+// differ (an update); an unreadable backup reads as differing, so the
+// caller rewrites it.
+func backupDiffers(path string, want []byte) bool {
+	if size, ok := backupSize(path); !ok || size != int64(len(want)) {
+		return true
+	}
+	got, err := companion.ReadBackup(path)
+	return err != nil || !bytes.Equal(got, want)
+}
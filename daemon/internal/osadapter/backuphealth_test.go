@@ -0,0 +1,85 @@
+package osadapter
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/companion"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+)
+
+func TestBackupHealth(t *testing.T) {
+	dir := companion.For(mode.User, t.TempDir())
+	if err := os.MkdirAll(dir.Root(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	verifyOK := func(string) (bool, error) { return true, nil }
+	verifyBad := func(string) (bool, error) { return false, nil }
+	verifyErr := func(string) (bool, error) { return false, errors.New("read") }
+
+	// Empty folder: nothing present; the verifier is irrelevant.
+	if h := backupHealth(dir, verifyOK); h.Present || h.Verified || !h.RefreshedAt.IsZero() || h.Pin != "" {
+		t.Fatalf("empty folder: got %+v, want zero", h)
+	}
+
+	if err := os.WriteFile(dir.Backup(), []byte("daemon"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir.Desired(), []byte("v1.2.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h := backupHealth(dir, verifyOK)
+	if !h.Present || !h.Verified || h.RefreshedAt.IsZero() || h.Pin != "v1.2.3" {
+		t.Fatalf("verified backup: got %+v", h)
+	}
+
+	// Present but failing verification (or erroring) is the corrupt case.
+	for name, v := range map[string]func(string) (bool, error){"bad": verifyBad, "err": verifyErr} {
+		if h := backupHealth(dir, v); !h.Present || h.Verified {
+			t.Fatalf("%s verify: got %+v, want present && !verified", name, h)
+		}
+	}
+
+	// A garbage pin is dropped rather than echoed.
+	if err := os.WriteFile(dir.Desired(), []byte("latest"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if h := backupHealth(dir, verifyOK); h.Pin != "" {
+		t.Fatalf("invalid pin: got %q, want empty", h.Pin)
+	}
+}
+
+// TestBackupDiffers: the daemon's refresh compares the bytes a packed backup
+// holds, so an identical daemon is not rewritten and a legacy plain copy of
+// the same daemon still counts as current.
+func TestBackupDiffers(t *testing.T) {
+	path := t.TempDir() + "/backup"
+	want := bytes.Repeat([]byte("daemon"), 100)
+	if !backupDiffers(path, want) {
+		t.Fatal("missing backup: want differs")
+	}
+	packed, err := companion.Pack(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, packed, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := backupSize(path); !ok || n != int64(len(want)) {
+		t.Fatalf("backupSize = %d, %v; want %d", n, ok, len(want))
+	}
+	if backupDiffers(path, want) {
+		t.Fatal("packed copy of the same daemon: want no difference")
+	}
+	if !backupDiffers(path, append(bytes.Clone(want), 'x')) {
+		t.Fatal("other daemon: want differs")
+	}
+	if err := os.WriteFile(path, want, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if backupDiffers(path, want) {
+		t.Fatal("legacy plain copy of the same daemon: want no difference")
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/eliteGoblin/focusd/daemon/internal/companion"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
 	"github.com/eliteGoblin/focusd/daemon/internal/relocate"
-	"github.com/eliteGoblin/focusd/daemon/internal/sig"
 )
 
 // FEATURE 18 / ADR-0020: the out-of-band recovery COMPANION — the daemon-side
@@ -89,16 +88,18 @@ func EnsureCompanion(m mode.Mode, daemonSelf, desired string) error {
 	// only when missing. This is the BACKSTOP for a daemon-binary swap that did not
 	// route through self-update; the byte-exact authority is RefreshCompanionBackup
 	// (self_update.go), which copies the freshly verified rotated bytes. Gate on a
-	// cheap size compare so a healthy steady-state tick (~2s cadence) does two stats
-	// instead of reading the multi-MB daemon binary on every pass: a rebuilt daemon
-	// changes size, so an equal size means the backup is already current. daemonSelf
-	// is a signed daemon binary, so a byte-for-byte copy stays a valid sig.VerifyFile
-	// target; an unreadable/empty daemonSelf leaves a good backup INTACT rather than
-	// clobbering it.
+	// cheap size compare so a healthy steady-state tick (~2s cadence) does a stat and
+	// a 16-byte header read instead of reading the multi-MB daemon binary on every
+	// pass: a rebuilt daemon changes size, so an equal size means the backup is
+	// already current. The backup is stored packed (companion.Pack) and unpacks to
+	// the signed bytes, so it stays a valid verifyBackup target; an unreadable/empty
+	// daemonSelf leaves a good backup INTACT rather than clobbering it.
 	if sfi, serr := os.Stat(daemonSelf); serr == nil && sfi.Size() > 0 {
-		if bfi, berr := os.Stat(dir.Backup()); berr != nil || bfi.Size() != sfi.Size() {
+		if size, ok := backupSize(dir.Backup()); !ok || size != sfi.Size() {
 			if data, rerr := os.ReadFile(daemonSelf); rerr == nil && len(data) > 0 {
-				_ = companionWriteFile(dir.Backup(), data, 0o755)
+				if packed, perr := companion.Pack(data); perr == nil {
+					_ = companionWriteFile(dir.Backup(), packed, 0o644)
+				}
 			}
 		}
 	}
@@ -322,9 +323,13 @@ func RefreshCompanionBackup(m mode.Mode, signedDaemonBytes []byte, desired strin
 	}
 	// Refresh the offline backup only when it is ABSENT or DIFFERS from the freshly
 	// verified daemon bytes — the copy tracks the rotated binary without a needless
-	// multi-MB rewrite on a no-op self-update.
-	if fileContentDiffers(dir.Backup(), signedDaemonBytes) {
-		if err := companionWriteFile(dir.Backup(), signedDaemonBytes, 0o755); err != nil {
+	// multi-MB rewrite on a no-op self-update. Stored packed, like the backstop.
+	if backupDiffers(dir.Backup(), signedDaemonBytes) {
+		packed, err := companion.Pack(signedDaemonBytes)
+		if err != nil {
+			return err
+		}
+		if err := companionWriteFile(dir.Backup(), packed, 0o644); err != nil {
 			return err
 		}
 	}
@@ -371,7 +376,14 @@ const companionRanRecentlyWindow = 90 * time.Second
 //     presence).
 func CompanionStatus(m mode.Mode) (present, backupOK, ranRecently bool) {
 	dir := companionDir(m)
-	return companionStatus(dir, launchctlCtl{m: m}.loaded, sig.VerifyFile, time.Now())
+	return companionStatus(dir, launchctlCtl{m: m}.loaded, verifyBackup, time.Now())
+}
+
+// CompanionBackupHealth reports the companion's offline daemon backup for the
+// status backup section: present / verified / last-changed time + restore pin.
+// Primitives only — the backup path never leaves this package.
+func CompanionBackupHealth(m mode.Mode) BackupHealth {
+	return backupHealth(companionDir(m), verifyBackup)
 }
 
 // companionStatus is the seam-injected core of CompanionStatus, split out so the
@@ -431,6 +443,19 @@ func TouchCompanionHeartbeat(m mode.Mode) error {
 	return nil
 }
 
+// CompanionHeartbeatAge is how long ago a mesh worker last touched the companion
+// heartbeat; ok is false when there is none (never beaten, or test mode).
+func CompanionHeartbeatAge(m mode.Mode) (age time.Duration, ok bool) {
+	if m == mode.Test {
+		return 0, false
+	}
+	fi, err := os.Stat(companionDir(m).Heartbeat())
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(fi.ModTime()), true
+}
+
 // CompanionPlist renders the out-of-band companion's launchd plist (FEATURE 18 /
 // ADR-0020). It is DELIBERATELY NOT a mesh worker:
 //   - ProgramArguments is the companion binary ALONE (no role / --mesh argv).
@@ -438,7 +463,7 @@ func TouchCompanionHeartbeat(m mode.Mode) error {
 //     DiscoverAllGenerations never buckets it as a mesh generation (live or
 //     dead), and FEATURE 17/19 cleanup can never retire or sweep it.
 //   - RunAtLoad + StartInterval (a one-shot pass per interval), NOT KeepAlive.
-//   - ProcessType Background.
+//   - ProcessType Standard, one step above the mesh's Background.
 //
 // Its binary is also NOT mesh-signed, so it never passes sig.VerifyFile and is
 // invisible to FindCurrentInstall by construction. Pure → unit-tested.
@@ -453,7 +478,9 @@ func CompanionPlist(label, companionBin, logPath string, intervalSec int) string
 	sb.WriteString("  </array>\n")
 	sb.WriteString("  <key>RunAtLoad</key><true/>\n")
 	fmt.Fprintf(&sb, "  <key>StartInterval</key><integer>%d</integer>\n", intervalSec)
-	sb.WriteString("  <key>ProcessType</key><string>Background</string>\n")
+	// Standard, not Background like the mesh: the guardian must not be the
+	// job the OS throttles first when the machine is busy.
+	sb.WriteString("  <key>ProcessType</key><string>Standard</string>\n")
 	fmt.Fprintf(&sb, "  <key>StandardErrorPath</key><string>%s</string>\n", logPath)
 	fmt.Fprintf(&sb, "  <key>StandardOutPath</key><string>%s</string>\n", logPath)
 	sb.WriteString("</dict></plist>\n")
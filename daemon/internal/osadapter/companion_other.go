@@ -2,7 +2,11 @@
 
 package osadapter
 
-import "github.com/eliteGoblin/focusd/daemon/internal/mode"
+import (
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+)
 
 // FEATURE 18 / ADR-0020: the out-of-band companion is launchd/darwin-only. On
 // non-darwin these are no-ops so GOOS=linux/windows compile (mirrors
@@ -13,5 +17,7 @@ func EnsureCompanion(mode.Mode, string, string) error        { return nil }
 func RefreshCompanionBackup(mode.Mode, []byte, string) error { return nil }
 func RemoveCompanion(mode.Mode) error                        { return nil }
 func TouchCompanionHeartbeat(mode.Mode) error                { return nil }
+func CompanionHeartbeatAge(mode.Mode) (time.Duration, bool)  { return 0, false }
 
 func CompanionStatus(mode.Mode) (present, backupOK, ranRecently bool) { return false, false, false }
+func CompanionBackupHealth(mode.Mode) BackupHealth                    { return BackupHealth{} }
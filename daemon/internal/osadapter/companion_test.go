@@ -50,6 +50,9 @@ func TestCompanionPlistNotMeshWorker(t *testing.T) {
 	if strings.Contains(pl, MeshEnvKey) {
 		t.Fatalf("companion plist must not contain the mesh env key:\n%s", pl)
 	}
+	if !strings.Contains(pl, "<key>ProcessType</key><string>Standard</string>") {
+		t.Fatalf("companion must run a QoS step above the Background mesh:\n%s", pl)
+	}
 }
 
 // writeTempPlist writes content to a temp .plist and returns the path.
@@ -13,12 +13,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
 	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
+	"github.com/eliteGoblin/focusd/daemon/internal/status/redact"
 )
 
 // launchctlCtl + laFS are mode-aware: user → gui/<uid> + ~/Library/
@@ -97,6 +99,36 @@ func EnsureAll(s Spec) ([]Role, error) {
 	return ensureAll(s, launchctlCtl{m: s.Mode}, laFS{m: s.Mode}, rs)
 }
 
+// ReloadBootedOut re-bootstraps the jobs of the discovered install cur that
+// were booted out while their plists stayed (see rebootstrapBootedOut).
+func ReloadBootedOut(cur CurInstall) (int, error) {
+	return rebootstrapBootedOut(cur.Labels, cur.PlistPaths, launchctlCtl{m: cur.Mode}, time.Sleep)
+}
+
+// RestartJob kills and relaunches a loaded launchd job in place
+// (`launchctl kickstart -k`): the cure for a worker that is alive but no
+// longer ticking, which KeepAlive alone never restarts.
+func RestartJob(m mode.Mode, label string) error {
+	return exec.Command("launchctl", "kickstart", "-k", launchctlCtl{m: m}.domain()+"/"+label).Run()
+}
+
+// JobPID is the PID of a loaded launchd job's running process, 0 when the
+// job is not loaded or not running.
+func JobPID(m mode.Mode, label string) int {
+	out, err := exec.Command("launchctl", "print", launchctlCtl{m: m}.domain()+"/"+label).Output()
+	if err != nil {
+		return 0
+	}
+	return printPID(string(out))
+}
+
+// ReloadJob sends a loaded launchd job SIGHUP (`launchctl kill HUP`): a
+// mesh worker re-reads its settings without a restart (`daemon config
+// set`).
+func ReloadJob(m mode.Mode, label string) error {
+	return exec.Command("launchctl", "kill", "HUP", launchctlCtl{m: m}.domain()+"/"+label).Run()
+}
+
 // IsLoaded reports whether a role's launchd entry is registered.
 func IsLoaded(testMode bool, r Role) bool {
 	return launchctlCtl{m: modeFromTestFlag(testMode)}.loaded(LabelFor(testMode, r))
@@ -287,24 +319,58 @@ func MeshStatus(m mode.Mode) (loaded, total int, found bool, err error) {
 	if ferr != nil {
 		return 0, 0, false, ferr
 	}
-	c := launchctlCtl{m: m}
-	loaded, total, found = meshStatusCounts(cur, c.loaded)
+	loaded, total, found = MeshStatusOf(cur)
 	return loaded, total, found, nil
 }
 
+// MeshStatusOf is MeshStatus for an install the caller already found, so a
+// caller that also needs cur does not verify the binaries twice.
+func MeshStatusOf(cur CurInstall) (loaded, total int, found bool) {
+	return meshStatusCounts(cur, launchctlCtl{m: cur.Mode}.loaded)
+}
+
+// VerifyInstall runs the `daemon verify` checks against the install
+// discovered for m. It returns no checks when no genuine install is found;
+// only check names and fixed notes leave here, never a path or label.
+func VerifyInstall(m mode.Mode) ([]Check, error) {
+	cur, err := FindCurrentInstall(m, sig.VerifyFile)
+	if err != nil || cur.BinaryPath == "" {
+		return nil, err
+	}
+	home, _ := os.UserHomeDir()
+	// Same containment as status: an unsafe pointer is not followed, so the
+	// platform binary check then fails rather than verifying a foreign path.
+	platWD := platdir.Read(cur.Workdir)
+	if platWD != "" && !platdir.SafeTarget(platWD, mode.SupportRoot(m, home), cur.Workdir) {
+		platWD = ""
+	}
+	in := verifyInput{cur: cur, platWD: platWD, comp: companionDir(m), verifyBackup: verifyBackup, plistPath: laFS{m: m}.plistPath}
+	return verifyInstall(in, sig.VerifyFile), nil
+}
+
 // UninstallProd removes a disguised user/system install whose labels are
 // randomized/unknown. It uses FindCurrentInstall for the scan, then
-// bootouts + removes plists + pkills the binary. Owner-driven teardown
-// of a hidden install without needing the random names.
-func UninstallProd() (removed []string, err error) {
+// bootouts + removes plists + pkills the binary and its platform child,
+// retires any dead generations, and finally deletes the install's
+// platform-workdir and daemon-home (binaries, state.db, version.json).
+// Owner-driven teardown of a hidden install without needing the random
+// names. dirs counts the directories deleted; the paths never leave here.
+func UninstallProd() (removed []string, dirs int, err error) {
 	m := mode.Resolve()
 	cur, ferr := FindCurrentInstall(m, sig.VerifyFile)
 	if ferr != nil {
-		return nil, ferr
+		return nil, 0, ferr
 	}
 	if cur.BinaryPath == "" {
-		return nil, nil
+		return nil, 0, nil
+	}
+	home, herr := os.UserHomeDir()
+	if herr != nil {
+		return nil, 0, herr
 	}
+	supportRoot := mode.SupportRoot(m, home)
+	// Read the pointer BEFORE anything is deleted: it lives in daemon-home.
+	platformWorkdir := platdir.Read(cur.Workdir)
 	// Best-effort process kill is fine for uninstall (we are tearing
 	// the whole install down, no surviving daemon will see argv overlap).
 	_ = exec.Command("pkill", "-f", cur.BinaryPath).Run()
@@ -321,7 +387,27 @@ func UninstallProd() (removed []string, err error) {
 	if cur.Workdir != "" {
 		_ = newWorkdirRoster(cur.Workdir).removeRoster()
 	}
-	return removed, nil
+	// The decoys are recorded only in daemon-home: take them down before it
+	// goes.
+	if cur.Workdir != "" {
+		RemoveDecoys(m, (&core.Store{Dir: cur.Workdir}).Decoys())
+	}
+	// The mesh is gone, so nothing respawns the platform: stop it, then sweep
+	// any dead generations a past workdir-delete left launchd-active.
+	killGenerationPlatform(supportRoot)(cur.Workdir)
+	if _, dead, derr := DiscoverAllGenerations(m, sig.VerifyFile); derr == nil {
+		retireGenerations(nil, dead, cur.BinaryPath, supportRoot,
+			c.bootout, os.Remove, pkillBinary, killGenerationPlatform(supportRoot), os.RemoveAll)
+	}
+	for _, dir := range uninstallDirs(cur.Workdir, platformWorkdir, supportRoot) {
+		if os.RemoveAll(dir) == nil {
+			dirs++
+		}
+	}
+	// kill-steam's app-location tombstones carry schg; with the platform
+	// gone nothing else would ever lift them.
+	clearTombstones("/Applications", func(p string) error { return syscall.Chflags(p, 0) })
+	return removed, dirs, nil
 }
 
 // Generation is one distinct, on-disk focusd mesh install identified by its
@@ -952,17 +1038,7 @@ func (p launchctlProber) hasPID(label string) bool {
 		return false
 	}
 	// `launchctl print` output is verbose; look for "pid = N" (N > 0).
-	// Format: "    pid = 12345" or "state = running"+"pid = N".
-	for _, line := range strings.Split(string(out), "\n") {
-		l := strings.TrimSpace(line)
-		if strings.HasPrefix(l, "pid = ") {
-			v := strings.TrimSpace(strings.TrimPrefix(l, "pid = "))
-			if v != "" && v != "0" {
-				return true
-			}
-		}
-	}
-	return false
+	return printPID(string(out)) > 0
 }
 
 // binPlacerFS writes raw bytes atomically with exec mode. On macOS the
@@ -1045,3 +1121,23 @@ func between(s, a, b string) string {
 	}
 	return strings.TrimSpace(s[i : i+j])
 }
+
+// MeshPrint returns `launchctl print` output for each discovered mesh job,
+// scrubbed for a diagnostics bundle: the disguised labels, workdir and binary
+// path are replaced inside this boundary (redact.Scrub), so none of them
+// reaches the caller. A job launchd no longer knows yields its error text.
+func MeshPrint(m mode.Mode) ([]string, error) {
+	cur, err := FindCurrentInstall(m, sig.VerifyFile)
+	if err != nil {
+		return nil, err
+	}
+	c := launchctlCtl{m: m}
+	secrets := append([]string{cur.Workdir, cur.BinaryPath}, cur.Labels...)
+	secrets = append(secrets, cur.Roster...)
+	out := make([]string, 0, len(cur.Labels))
+	for _, lbl := range cur.Labels {
+		b, _ := exec.Command("launchctl", "print", c.domain()+"/"+lbl).CombinedOutput()
+		out = append(out, redact.Scrub(string(b), secrets...))
+	}
+	return out, nil
+}
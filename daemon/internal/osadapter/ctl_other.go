@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
 	"github.com/eliteGoblin/focusd/daemon/internal/mode"
 )
 
@@ -14,11 +15,17 @@ import (
 // later behind this same package API.
 var ErrUnsupported = errors.New("osadapter: launchd lifecycle is macOS-only")
 
-func Install(Spec) error               { return ErrUnsupported }
-func Uninstall(bool) error             { return ErrUnsupported }
-func EnsureAll(Spec) ([]Role, error)   { return nil, ErrUnsupported }
-func IsLoaded(bool, Role) bool         { return false }
-func UninstallProd() ([]string, error) { return nil, ErrUnsupported }
+func Install(Spec) error                           { return ErrUnsupported }
+func Uninstall(bool) error                         { return ErrUnsupported }
+func EnsureAll(Spec) ([]Role, error)               { return nil, ErrUnsupported }
+func IsLoaded(bool, Role) bool                     { return false }
+func RestartJob(mode.Mode, string) error           { return ErrUnsupported }
+func ReloadJob(mode.Mode, string) error            { return ErrUnsupported }
+func JobPID(mode.Mode, string) int                 { return 0 }
+func ReloadBootedOut(CurInstall) (int, error)      { return 0, ErrUnsupported }
+func EnsureDecoys(Spec, []core.Decoy) (int, error) { return 0, nil }
+func RemoveDecoys(mode.Mode, []core.Decoy)         {}
+func UninstallProd() ([]string, int, error)        { return nil, 0, ErrUnsupported }
 
 // EnsureBinaryPresent is a no-op on non-darwin (no launchd mesh binary to
 // re-materialize). Returns ("", false, nil) so the reconcile loop wires it
@@ -33,6 +40,12 @@ func FindCurrentInstall(mode.Mode, Verifier) (CurInstall, error) {
 	return CurInstall{}, ErrUnsupported
 }
 
+// VerifyInstall has no launchd install to check on non-darwin.
+func VerifyInstall(mode.Mode) ([]Check, error) { return nil, ErrUnsupported }
+
+// MeshPrint has no launchd to ask on non-darwin.
+func MeshPrint(mode.Mode) ([]string, error) { return nil, ErrUnsupported }
+
 // Generation mirrors the darwin definition so cross-platform code that
 // references it compiles on non-darwin (where discovery is unsupported).
 type Generation struct {
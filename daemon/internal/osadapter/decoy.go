@@ -0,0 +1,99 @@
+package osadapter
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/relocate"
+)
+
+// Decoy launchd entries (FEATURE 10). Beside the three mesh entries the lock
+// holder keeps DecoyCount look-alikes: labels from the roster styles, a plist
+// naming a disguised binary in the daemon-home, the same process type and log
+// paths. Nothing launches them — no RunAtLoad, KeepAlive or StartInterval —
+// so `launchctl list` shows each the way it shows the ensurer between runs.
+// Someone deleting entries to find the real one hits a decoy, and its
+// removal is what the daemon reports.
+//
+// HONEST SCOPE — friction, like the roster: a reader who opens every plist
+// sees the decoys carry no launch trigger.
+
+// DecoyCount is how many decoy entries an install keeps.
+const DecoyCount = 2
+
+// decoyStandIn is copied to each decoy's binary path, so the path a decoy
+// names exists like the mesh's does. It is never run.
+const decoyStandIn = "/usr/bin/true"
+
+// errDecoy is the fixed, label-free error ensureDecoys wraps: a decoy label
+// must not reach a log line.
+var errDecoy = errors.New("osadapter: restore decoy")
+
+// NewDecoys makes n decoy records whose binaries live in workdir.
+func NewDecoys(n int, workdir string) []core.Decoy {
+	ds := make([]core.Decoy, 0, n)
+	for _, label := range relocate.GenerateDecoys(n) {
+		ds = append(ds, core.Decoy{Label: label, Bin: filepath.Join(workdir, relocate.RandomBinaryName())})
+	}
+	return ds
+}
+
+// DecoyPlist renders a decoy's plist: a mesh worker's shape (Program split,
+// display argv[0], Background, the daemon log) with no launch trigger and no
+// mesh marker, so DiscoverAllGenerations never groups it. Pure → unit-tested.
+func DecoyPlist(d core.Decoy, workdir string) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	sb.WriteString("<plist version=\"1.0\"><dict>\n")
+	fmt.Fprintf(&sb, "  <key>Label</key><string>%s</string>\n", d.Label)
+	fmt.Fprintf(&sb, "  <key>Program</key><string>%s</string>\n", d.Bin)
+	sb.WriteString("  <key>ProgramArguments</key><array>\n")
+	fmt.Fprintf(&sb, "    <string>%s</string>\n", relocate.DaemonArgv0(d.Label))
+	sb.WriteString("  </array>\n")
+	sb.WriteString("  <key>ProcessType</key><string>Background</string>\n")
+	fmt.Fprintf(&sb, "  <key>StandardErrorPath</key><string>%s</string>\n", LogPath(workdir))
+	fmt.Fprintf(&sb, "  <key>StandardOutPath</key><string>%s</string>\n", LogPath(workdir))
+	sb.WriteString("</dict></plist>\n")
+	return sb.String()
+}
+
+// ensureDecoys puts back every decoy whose plist or binary is gone or whose
+// job is not loaded, and returns how many it restored. exists and place are
+// the stat and stand-in-copy seams.
+func ensureDecoys(ds []core.Decoy, workdir string, c controller, fs fsio,
+	exists func(string) bool, place func(dst string) error) (restored int, err error) {
+	for _, d := range ds {
+		pp := fs.plistPath(d.Label)
+		binOK := exists(d.Bin)
+		if binOK && exists(pp) && c.loaded(d.Label) {
+			continue
+		}
+		if !binOK {
+			if perr := place(d.Bin); perr != nil {
+				return restored, fmt.Errorf("%w: binary: %T", errDecoy, perr)
+			}
+		}
+		if werr := fs.write(pp, DecoyPlist(d, workdir)); werr != nil {
+			return restored, fmt.Errorf("%w: plist: %T", errDecoy, werr)
+		}
+		if berr := robustReload(c, d.Label, pp, time.Sleep); berr != nil {
+			return restored, fmt.Errorf("%w: bootstrap: %T", errDecoy, berr)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// removeDecoys boots out and deletes every decoy (best-effort; uninstall).
+func removeDecoys(ds []core.Decoy, c controller, fs fsio, removeBin func(string) error) {
+	for _, d := range ds {
+		_ = c.bootout(d.Label)
+		_ = fs.remove(fs.plistPath(d.Label))
+		_ = removeBin(d.Bin)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build darwin
+
+package osadapter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+)
+
+// EnsureDecoys installs or puts back the decoy entries ds for spec's install
+// and returns how many it restored. Test mode keeps no decoys.
+func EnsureDecoys(s Spec, ds []core.Decoy) (int, error) {
+	if s.isTest() || len(ds) == 0 {
+		return 0, nil
+	}
+	// An ambiguous stat counts as present: only a clean ENOENT is a removal.
+	exists := func(p string) bool {
+		ok, err := fileExists(p)
+		return ok || err != nil
+	}
+	return ensureDecoys(ds, s.Workdir, launchctlCtl{m: s.Mode}, laFS{m: s.Mode}, exists, placeStandIn)
+}
+
+// RemoveDecoys boots out and deletes the decoy entries ds (uninstall).
+func RemoveDecoys(m mode.Mode, ds []core.Decoy) {
+	removeDecoys(ds, launchctlCtl{m: m}, laFS{m: m}, os.Remove)
+}
+
+// placeStandIn copies decoyStandIn to dst (temp + rename, 0755).
+func placeStandIn(dst string) error {
+	in, err := os.Open(decoyStandIn)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,72 @@
+package osadapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+func TestDecoyPlistHasNoTriggerOrMeshMarker(t *testing.T) {
+	d := core.Decoy{Label: "com.apple.metadata.sync", Bin: "/wd/bundle.payload.blob.0a1b2c3d4e"}
+	p := DecoyPlist(d, "/wd")
+	for _, want := range []string{d.Label, "<key>Program</key><string>" + d.Bin, "Background", "/wd/" + DaemonLogName} {
+		if !strings.Contains(p, want) {
+			t.Fatalf("decoy plist lacks %q:\n%s", want, p)
+		}
+	}
+	for _, never := range []string{"RunAtLoad", "KeepAlive", "StartInterval", MeshEnvKey} {
+		if strings.Contains(p, never) {
+			t.Fatalf("decoy plist must not carry %s:\n%s", never, p)
+		}
+	}
+}
+
+func TestEnsureDecoysRestoresOnlyWhatIsGone(t *testing.T) {
+	ds := []core.Decoy{{Label: "one", Bin: "/wd/x"}, {Label: "two", Bin: "/wd/y"}}
+	c, fs := newFakeCtl(), newFakeFS()
+	bins := map[string]bool{}
+	exists := func(p string) bool { _, ok := fs.files[p]; return ok || bins[p] }
+	place := func(p string) error { bins[p] = true; return nil }
+
+	if n, err := ensureDecoys(ds, "/wd", c, fs, exists, place); err != nil || n != 2 {
+		t.Fatalf("first pass: restored %d, err %v", n, err)
+	}
+	if n, _ := ensureDecoys(ds, "/wd", c, fs, exists, place); n != 0 {
+		t.Fatalf("intact decoys restored again: %d", n)
+	}
+
+	delete(fs.files, fs.plistPath("one")) // plist deleted
+	delete(c.loadedSet, "two")            // booted out
+	if n, err := ensureDecoys(ds, "/wd", c, fs, exists, place); err != nil || n != 2 {
+		t.Fatalf("after removals: restored %d, err %v", n, err)
+	}
+	delete(bins, "/wd/y") // stand-in binary deleted
+	if n, _ := ensureDecoys(ds, "/wd", c, fs, exists, place); n != 1 || !bins["/wd/y"] {
+		t.Fatalf("deleted binary: restored %d, placed %v", n, bins["/wd/y"])
+	}
+
+	fs.writeFailOn = fs.plistPath("one")
+	delete(fs.files, fs.plistPath("one"))
+	_, err := ensureDecoys(ds, "/wd", c, fs, exists, place)
+	if err == nil || strings.Contains(err.Error(), "one") {
+		t.Fatalf("write failure must surface without the label: %v", err)
+	}
+
+	removeDecoys(ds, c, fs, func(p string) error { delete(bins, p); return nil })
+	if len(c.loadedSet) != 0 || len(bins) != 0 {
+		t.Fatalf("removeDecoys left loaded %v, bins %v", c.loadedSet, bins)
+	}
+}
+
+func TestNewDecoysLiveInTheWorkdir(t *testing.T) {
+	ds := NewDecoys(DecoyCount, "/wd")
+	if len(ds) != DecoyCount {
+		t.Fatalf("got %d decoys", len(ds))
+	}
+	for _, d := range ds {
+		if !strings.HasPrefix(d.Bin, "/wd/") || d.Label == "" {
+			t.Fatalf("bad decoy %+v", d)
+		}
+	}
+}
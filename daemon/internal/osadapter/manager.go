@@ -219,3 +219,26 @@ func ensureAll(s Spec, c controller, fs fsio, rs rosterIO) (recreated []Role, er
 	}
 	return recreated, nil
 }
+
+// rebootstrapBootedOut reloads every discovered mesh job whose plist is still
+// on disk but whose label launchd no longer holds — a `launchctl bootout`
+// that left the file alone. KeepAlive does not survive a bootout and the
+// plist check sees nothing missing, so without this a mesh booted out whole
+// stays down until the next login. labels and plistPaths are aligned (a
+// CurInstall's scan order). Returns how many it reloaded; the first failure
+// is returned after the rest are tried.
+func rebootstrapBootedOut(labels, plistPaths []string, c controller, sleep func(time.Duration)) (reloaded int, err error) {
+	for i, label := range labels {
+		if i >= len(plistPaths) || c.loaded(label) {
+			continue
+		}
+		if rerr := robustReload(c, label, plistPaths[i], sleep); rerr != nil {
+			if err == nil {
+				err = fmt.Errorf("osadapter: rebootstrap booted-out job: %w", rerr)
+			}
+			continue
+		}
+		reloaded++
+	}
+	return reloaded, err
+}
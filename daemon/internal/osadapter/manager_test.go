@@ -354,3 +354,29 @@ func TestUninstallAllRemovesEverything(t *testing.T) {
 		t.Fatal("uninstall must remove the masked roster file")
 	}
 }
+
+// TestRebootstrapBootedOut: only jobs launchd no longer holds are reloaded,
+// from their existing plists, and a failure does not stop the rest.
+func TestRebootstrapBootedOut(t *testing.T) {
+	labels := []string{"x", "y", "z"}
+	paths := []string{"/p/x.plist", "/p/y.plist", "/p/z.plist"}
+	c := newFakeCtl()
+	c.loadedSet["y"] = true
+	n, err := rebootstrapBootedOut(labels, paths, c, func(time.Duration) {})
+	if err != nil || n != 2 {
+		t.Fatalf("reloaded %d, err %v; want 2", n, err)
+	}
+	if !c.loadedSet["x"] || !c.loadedSet["z"] {
+		t.Fatalf("booted-out jobs not reloaded: %v", c.loadedSet)
+	}
+	if n, _ := rebootstrapBootedOut(labels, paths, c, func(time.Duration) {}); n != 0 {
+		t.Fatalf("loaded mesh reloaded %d jobs", n)
+	}
+
+	c = newFakeCtl()
+	c.bootstrapFailOn = "/p/x.plist"
+	n, err = rebootstrapBootedOut(labels, paths, c, func(time.Duration) {})
+	if err == nil || n != 2 {
+		t.Fatalf("one failure: reloaded %d, err %v; want 2 and an error", n, err)
+	}
+}
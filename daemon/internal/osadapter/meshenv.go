@@ -16,8 +16,18 @@ import (
 //   - "run:a"  → worker role A → argv: run --r a --mesh
 //   - "run:b"  → worker role B → argv: run --r b --mesh
 //   - "ensure" → ensurer       → argv: ensure
+//   - "rotate" → a scheduled identity rotation the mesh spawned → argv:
+//     rotate-identity
 const MeshEnvKey = "APP_LAUNCH_CONTEXT"
 
+// meshEnvRotate is the MeshEnvKey value a mesh worker starts its scheduled
+// `rotate-identity` child with, so the verb is not on the child's argv.
+const meshEnvRotate = "rotate"
+
+// RotateEnv is the environment entry that makes a bare start of the daemon
+// binary run `rotate-identity`.
+func RotateEnv() string { return MeshEnvKey + "=" + meshEnvRotate }
+
 // meshEnvRunPrefix tags a WORKER role value ("run:a" / "run:b"). The ensurer
 // value ("ensure") deliberately lacks it: like the pre-19 `ensure` argv (which
 // carried no --mesh), an ensure-only plist must NOT corroborate a real
@@ -49,6 +59,9 @@ func decodeMeshEnv(val string) []string {
 	if val == string(RoleEnsure) {
 		return []string{"ensure"}
 	}
+	if val == meshEnvRotate {
+		return []string{"rotate-identity"}
+	}
 	if role := strings.TrimPrefix(val, meshEnvRunPrefix); role != val && role != "" {
 		if role != string(RoleA) && role != string(RoleB) {
 			return nil
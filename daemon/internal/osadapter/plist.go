@@ -2,6 +2,7 @@ package osadapter
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -45,6 +46,11 @@ func args(s Spec, r Role) []string {
 // so a filesystem grep for 'daemon' finds nothing tied to the supervisor.
 const DaemonLogName = "run.log"
 
+// LogPath is the daemon log of the install in workdir: the per-mode
+// daemon-home, never a shared temp folder. Every plist that redirects into
+// the log, and every reader of it, derives the path here.
+func LogPath(workdir string) string { return filepath.Join(workdir, DaemonLogName) }
+
 // envKV is one launchd EnvironmentVariables entry.
 type envKV struct{ Key, Value string }
 
@@ -158,8 +164,8 @@ func Plist(s Spec, r Role) string {
 	sb.WriteString("  <key>ProcessType</key><string>Background</string>\n")
 	// HF4 (FEATURE 24): neutral log basename ("run.log", not "daemon.log") so a
 	// filesystem grep for 'daemon' does not hit the supervisor's own log file.
-	fmt.Fprintf(&sb, "  <key>StandardErrorPath</key><string>%s/%s</string>\n", s.Workdir, DaemonLogName)
-	fmt.Fprintf(&sb, "  <key>StandardOutPath</key><string>%s/%s</string>\n", s.Workdir, DaemonLogName)
+	fmt.Fprintf(&sb, "  <key>StandardErrorPath</key><string>%s</string>\n", LogPath(s.Workdir))
+	fmt.Fprintf(&sb, "  <key>StandardOutPath</key><string>%s</string>\n", LogPath(s.Workdir))
 	sb.WriteString("</dict></plist>\n")
 	return sb.String()
 }
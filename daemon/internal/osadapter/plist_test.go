@@ -1,6 +1,7 @@
 package osadapter
 
 import (
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -184,12 +185,25 @@ func TestArgvFromEnvRoundTrip(t *testing.T) {
 	}
 }
 
+// TestDecodeMeshEnvRotate: the scheduled rotation child gets its verb from
+// the env alone, like the mesh roles.
+func TestDecodeMeshEnvRotate(t *testing.T) {
+	k, v, _ := strings.Cut(RotateEnv(), "=")
+	if k != MeshEnvKey {
+		t.Fatalf("RotateEnv key %q, want %q", k, MeshEnvKey)
+	}
+	if got := decodeMeshEnv(v); !reflect.DeepEqual(got, []string{"rotate-identity"}) {
+		t.Fatalf("decodeMeshEnv(%q) = %v", v, got)
+	}
+}
+
 // TestDecodeMeshEnvSafeOnGarbage asserts a bad/missing env value yields NO
 // synthesized argv (nil) — never a partial argv that could mis-dispatch. The
 // caller then falls through to usage() rather than respawning into a wrong
 // subcommand.
 func TestDecodeMeshEnvSafeOnGarbage(t *testing.T) {
-	// Strict inverse of encodeRole: only "ensure" / "run:a" / "run:b" decode.
+	// Strict inverse of encodeRole: only "ensure" / "run:a" / "run:b" decode
+	// (plus "rotate", which no plist carries; see TestDecodeMeshEnvRotate).
 	// Any unknown role ("run:a:b", "run:zzz") yields nil so a bad value can
 	// never synthesize a partial argv that mis-dispatches into a crash-loop.
 	for _, v := range []string{"", "run:", "run", "ensur", "garbage", "RUN:A", ":a", "run:a:b", "run:zzz"} {
@@ -0,0 +1,13 @@
+//go:build unix
+
+package osadapter
+
+import "syscall"
+
+// SetNice sets this process's nice value. The platform child and its plugin
+// runs are forked after it and inherit it. On darwin the value applies to
+// the whole process; Linux applies it to the calling thread, which is enough
+// for the children it starts.
+func SetNice(n int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, n)
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package osadapter
+
+// SetNice is a no-op: Windows has no nice values and the mesh is launchd-only.
+func SetNice(int) error { return nil }
@@ -0,0 +1,21 @@
+package osadapter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// printPID reads the job's PID from `launchctl print` output: the first
+// "pid = N" line, or 0 when the job is loaded but not running.
+func printPID(out string) int {
+	for _, line := range strings.Split(out, "\n") {
+		v, ok := strings.CutPrefix(strings.TrimSpace(line), "pid = ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+//go:build darwin
+
+package osadapter
+
+import "golang.org/x/sys/unix"
+
+// pTraced is P_TRACED from <sys/proc.h>: the kernel sets it while a
+// debugger holds the process through ptrace, which lldb's attach uses.
+const pTraced = 0x800
+
+// Traced reports whether pid is being debugged. Only ptrace attachment
+// shows here: dtrace probes and a bare task-port attach leave the flag
+// clear, and reading a process's exception ports needs the Mach API, which
+// a CGO-free build cannot call.
+func Traced(pid int) (bool, error) {
+	kp, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return false, err
+	}
+	return kp.Proc.P_flag&pTraced != 0, nil
+}
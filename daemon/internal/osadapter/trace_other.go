@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package osadapter
+
+// Traced has no kinfo_proc to read off macOS.
+func Traced(int) (bool, error) { return false, ErrUnsupported }
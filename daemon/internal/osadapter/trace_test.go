@@ -0,0 +1,15 @@
+package osadapter
+
+import "testing"
+
+func TestPrintPID(t *testing.T) {
+	running := "gui/501/com.example.x = {\n\tactive count = 1\n\tstate = running\n\tpid = 4242\n}\n"
+	if got := printPID(running); got != 4242 {
+		t.Fatalf("running job pid = %d", got)
+	}
+	for _, out := range []string{"", "state = not running\n", "\tpid = 0\n", "\tpid = x\n"} {
+		if got := printPID(out); got != 0 {
+			t.Errorf("printPID(%q) = %d, want 0", out, got)
+		}
+	}
+}
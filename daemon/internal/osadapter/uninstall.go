@@ -0,0 +1,64 @@
+package osadapter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
+)
+
+// uninstallDirs returns the directories a gate-satisfied prod uninstall
+// deletes, in order: the platform-workdir (platform binaries, plugins,
+// state.db) and then the daemon-home (the daemon binary, version.json, the
+// roster, good/bad markers). Each is subject to the same containment guard
+// as generation retirement — strictly under supportRoot — and the
+// platform-workdir must also carry its content sentinel, so a corrupt pointer
+// cannot aim the RemoveAll at an unrelated folder. With no keep generation
+// (everything is going) the keep argument is empty.
+//
+// Pure → unit-tested on Linux CI.
+func uninstallDirs(daemonHome, platformWorkdir, supportRoot string) []string {
+	var dirs []string
+	if platformWorkdir != "" && platformWorkdir != daemonHome &&
+		platdir.IsPlatformWorkdir(platformWorkdir) &&
+		safeToRemoveWorkdir(platformWorkdir, supportRoot, "") {
+		dirs = append(dirs, platformWorkdir)
+	}
+	if safeToRemoveWorkdir(daemonHome, supportRoot, "") {
+		dirs = append(dirs, daemonHome)
+	}
+	return dirs
+}
+
+// tombstoneBody mirrors kill-steam's placeholder marker (its package
+// uninstaller): the plugin ships on its own, so the constant is duplicated
+// rather than imported.
+const tombstoneBody = "reserved\n"
+
+// clearTombstones frees the app locations kill-steam locked: every file in
+// appsDir named *.app holding exactly the marker is unlocked and deleted.
+// The plugin expires its own placeholders, but once it is uninstalled
+// nothing would, and a system-immutable file outlives everything else.
+// Returns how many were removed; a file that won't go is left.
+func clearTombstones(appsDir string, unlock func(string) error) int {
+	entries, err := os.ReadDir(appsDir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !strings.HasSuffix(e.Name(), ".app") {
+			continue
+		}
+		p := filepath.Join(appsDir, e.Name())
+		if b, rerr := os.ReadFile(p); rerr != nil || !bytes.Equal(b, []byte(tombstoneBody)) {
+			continue
+		}
+		if unlock(p) == nil && os.Remove(p) == nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,67 @@
+package osadapter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
+)
+
+// TestUninstallDirs: the prod uninstall removes the platform-workdir and then
+// the daemon-home, but only dirs strictly under the support root, and the
+// platform-workdir only when it carries its sentinel.
+func TestUninstallDirs(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, ".a1b2c3")
+	pw := filepath.Join(root, ".d4e5f6")
+	unmarked := filepath.Join(root, "Some App")
+	for _, d := range []string{home, pw, unmarked} {
+		if err := os.MkdirAll(d, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	platdir.MarkPlatformWorkdir(pw)
+
+	if got := uninstallDirs(home, pw, root); !reflect.DeepEqual(got, []string{pw, home}) {
+		t.Fatalf("uninstallDirs = %v, want [platform-workdir daemon-home]", got)
+	}
+	if got := uninstallDirs(home, unmarked, root); !reflect.DeepEqual(got, []string{home}) {
+		t.Fatalf("an unmarked pointer target must survive: %v", got)
+	}
+	if got := uninstallDirs(home, "", root); !reflect.DeepEqual(got, []string{home}) {
+		t.Fatalf("legacy single-root: %v", got)
+	}
+	if got := uninstallDirs(root, pw, root); !reflect.DeepEqual(got, []string{pw}) {
+		t.Fatalf("the support root itself must never be removed: %v", got)
+	}
+	if got := uninstallDirs(home, pw, filepath.Join(root, "elsewhere")); len(got) != 0 {
+		t.Fatalf("dirs outside the support root must survive: %v", got)
+	}
+}
+
+// TestClearTombstones: only *.app files holding kill-steam's marker go; a
+// real bundle or a user's own file of that name stays.
+func TestClearTombstones(t *testing.T) {
+	apps := t.TempDir()
+	if err := os.Mkdir(filepath.Join(apps, "Safari.app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{
+		"Steam.app": tombstoneBody, "Notes.app": "mine\n", "reserved.txt": tombstoneBody,
+	} {
+		if err := os.WriteFile(filepath.Join(apps, name), []byte(body), 0o444); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var unlocked []string
+	n := clearTombstones(apps, func(p string) error { unlocked = append(unlocked, filepath.Base(p)); return nil })
+	if n != 1 || !reflect.DeepEqual(unlocked, []string{"Steam.app"}) {
+		t.Fatalf("cleared %d %v, want only Steam.app", n, unlocked)
+	}
+	left, _ := os.ReadDir(apps)
+	if len(left) != 3 {
+		t.Fatalf("left %d entries, want 3", len(left))
+	}
+}
@@ -0,0 +1,106 @@
+package osadapter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/companion"
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// Check is one line of `daemon verify`. Name is a fixed, path-free noun and
+// Note a fixed reason (on a passing check, an optional path-free detail),
+// so a check can be printed or sent as JSON without leaking a disguised
+// path or label.
+type Check struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	Note string `json:"note,omitempty"`
+}
+
+// verifyInput is what verifyInstall inspects: the discovered install, its
+// platform-workdir ("" for a legacy single-root install), the companion
+// folder and how to verify its packed backup, and the plist path for a
+// label.
+type verifyInput struct {
+	cur          CurInstall
+	platWD       string
+	comp         companion.Dir
+	verifyBackup Verifier
+	plistPath    func(label string) string
+}
+
+// verifyInstall re-derives every piece of the install it can and compares
+// it with what is on disk. It only reads; the mesh repairs what it finds
+// broken, this reports it.
+func verifyInstall(in verifyInput, verify Verifier) []Check {
+	cur := in.cur
+	var checks []Check
+	add := func(name string, ok bool, note string) {
+		c := Check{Name: name, OK: ok}
+		if !ok {
+			c.Note = note
+		}
+		checks = append(checks, c)
+	}
+
+	selfOK, _ := verify(cur.BinaryPath)
+	add("daemon binary", selfOK, "signature does not verify")
+	selfSum, selfErr := fileSHA256(cur.BinaryPath)
+
+	st := &core.Store{Dir: cur.Workdir}
+	if in.platWD != "" && in.platWD != cur.Workdir {
+		st.PlatformDir = in.platWD
+	}
+	if good := st.Good(); good == "" {
+		add("platform binary", false, "no good version recorded")
+	} else {
+		ok, _ := verify(st.BinPath(good))
+		add("platform binary", ok, "missing or signature does not verify")
+	}
+
+	backupOK, _ := in.verifyBackup(in.comp.Backup())
+	add("offline backup", backupOK, "missing or signature does not verify")
+	if backupOK {
+		b, err := companion.ReadBackup(in.comp.Backup())
+		sum := sha256.Sum256(b)
+		add("offline backup matches daemon", selfErr == nil && err == nil && bytes.Equal(sum[:], selfSum),
+			"differs from the running daemon (it refreshes within a companion interval after an update)")
+	}
+
+	add("version state", st.VersionStateOK(), "does not open with the install key")
+	labels, rerr := core.ReadRoster(st.RosterPath())
+	add("roster", rerr == nil && len(labels) == len(AllRoles), "unreadable or incomplete")
+
+	spec := Spec{
+		Mode: cur.Mode, SelfPath: cur.BinaryPath, Workdir: cur.Workdir,
+		Roster: cur.Roster, EnsureInterval: EnsureBackstopInterval,
+	}
+	for _, r := range AllRoles {
+		name := "plist role " + string(r)
+		got, err := os.ReadFile(in.plistPath(spec.Label(r)))
+		switch {
+		case err != nil:
+			add(name, false, "missing")
+		default:
+			add(name, string(got) == Plist(spec, r), "differs from the generated template")
+		}
+	}
+	return checks
+}
+
+// fileSHA256 hashes the file at path.
+func fileSHA256(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
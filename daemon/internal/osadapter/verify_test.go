@@ -0,0 +1,81 @@
+package osadapter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/companion"
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+)
+
+// TestVerifyInstall: an install laid down the way install writes it passes
+// every check; a hand-edited plist and a stale backup each fail only their own.
+func TestVerifyInstall(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	laDir := filepath.Join(root, "LaunchAgents")
+	comp := companion.DirFromBinary(filepath.Join(root, "companion", "bin"))
+	for _, d := range []string{home, laDir, comp.Root()} {
+		if err := os.MkdirAll(d, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cur := CurInstall{
+		Mode: mode.User, BinaryPath: filepath.Join(home, "daemon"), Workdir: home,
+		Roster: []string{"com.acme.sync", "org.north.helper", "io.kite.agent"},
+	}
+	write(cur.BinaryPath, "daemon-bytes")
+	packed, err := companion.Pack([]byte("daemon-bytes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(comp.Backup(), string(packed))
+	st := &core.Store{Dir: home}
+	if err := st.WriteDesired("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.WriteGood("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	write(st.BinPath("v1.0.0"), "platform-bytes")
+	if err := core.WriteRoster(st.RosterPath(), cur.Roster); err != nil {
+		t.Fatal(err)
+	}
+	spec := Spec{Mode: cur.Mode, SelfPath: cur.BinaryPath, Workdir: home, Roster: cur.Roster}
+	plistPath := func(label string) string { return filepath.Join(laDir, label+".plist") }
+	for _, r := range AllRoles {
+		write(plistPath(spec.Label(r)), Plist(spec, r))
+	}
+	exists := func(p string) (bool, error) { _, err := os.Stat(p); return err == nil, nil }
+	in := verifyInput{cur: cur, comp: comp, verifyBackup: exists, plistPath: plistPath}
+
+	failed := func() []string {
+		var names []string
+		for _, c := range verifyInstall(in, exists) {
+			if !c.OK {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+	if f := failed(); len(f) != 0 {
+		t.Fatalf("fresh install failed %v", f)
+	}
+
+	write(plistPath(spec.Label(RoleB)), Plist(spec, RoleB)+"<!-- edited -->")
+	write(comp.Backup(), "older-daemon-bytes")
+	if f := failed(); len(f) != 2 || f[0] != "offline backup matches daemon" || f[1] != "plist role b" {
+		t.Fatalf("failed = %v", f)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/eliteGoblin/focusd/daemon/internal/relocate"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 // TestPlatformStartCommandHasZeroLeaks is the HF4 (FEATURE 24 / P0) greppability
@@ -93,3 +95,176 @@ func TestLegacyStartUnchangedWithoutSalt(t *testing.T) {
 		t.Errorf("legacy env must be nil (inherit), got %v", env)
 	}
 }
+
+// TestChildEnvCarriesTraceEndpoint pins that a configured OTLP endpoint reaches
+// the platform child in both argv branches, replacing any inherited value.
+func TestChildEnvCarriesTraceEndpoint(t *testing.T) {
+	t.Setenv(tracing.EndpointEnv, "http://stale:4318")
+	ep := func() string { return "http://collector:4318" }
+	for _, p := range []*ProcSvc{
+		{Workdir: "/tmp/wd", TraceEndpoint: ep},
+		{Workdir: "/tmp/wd", Argv0: "worker", TraceEndpoint: ep},
+	} {
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got []string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, tracing.EndpointEnv+"=") {
+				got = append(got, kv)
+			}
+		}
+		if len(got) != 1 || got[0] != tracing.EndpointEnv+"=http://collector:4318" {
+			t.Errorf("argv0=%q: trace env = %v", p.Argv0, got)
+		}
+	}
+}
+
+// TestChildEnvCarriesAdminAPI pins the admin API hand-off: address, token
+// and debug switch when configured, and nothing inherited when it is off.
+func TestChildEnvCarriesAdminAPI(t *testing.T) {
+	t.Setenv(AdminAddrEnvKey, "127.0.0.1:1")
+	t.Setenv(AdminTokenEnvKey, "stale")
+	t.Setenv(AdminDebugEnvKey, "1")
+	for _, tc := range []struct {
+		addr, token string
+		debug       bool
+		want        []string
+	}{
+		{"127.0.0.1:7600", "tok", true, []string{AdminAddrEnvKey + "=127.0.0.1:7600", AdminTokenEnvKey + "=tok", AdminDebugEnvKey + "=1"}},
+		{"", "", false, nil},
+	} {
+		p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+			AdminAPI:   func() (string, string) { return tc.addr, tc.token },
+			AdminDebug: func() bool { return tc.debug }}
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got []string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, "APP_ADMIN_") {
+				got = append(got, kv)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("addr=%q: admin env = %v, want %v", tc.addr, got, tc.want)
+		}
+	}
+}
+
+// TestChildEnvCarriesStrictLock pins the strict-lock hand-off: whole seconds
+// left while locked, and no inherited value once it has ended.
+func TestChildEnvCarriesStrictLock(t *testing.T) {
+	t.Setenv(StrictLockEnvKey, "999999")
+	for _, tc := range []struct {
+		left time.Duration
+		want string
+	}{
+		{90*time.Minute + 500*time.Millisecond, StrictLockEnvKey + "=5400"},
+		{0, ""},
+	} {
+		p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+			StrictLock: func() time.Duration { return tc.left }}
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, StrictLockEnvKey+"=") {
+				got = kv
+			}
+		}
+		if got != tc.want {
+			t.Errorf("left=%v: lock env = %q, want %q", tc.left, got, tc.want)
+		}
+	}
+}
+
+// TestChildEnvCarriesClockSkew pins the trusted-clock hand-off: whole
+// seconds either way, and no inherited value when the clocks agree.
+func TestChildEnvCarriesClockSkew(t *testing.T) {
+	t.Setenv(ClockSkewEnvKey, "86400")
+	for _, tc := range []struct {
+		skew time.Duration
+		want string
+	}{
+		{48*time.Hour + 300*time.Millisecond, ClockSkewEnvKey + "=172800"},
+		{-90 * time.Second, ClockSkewEnvKey + "=-90"},
+		{0, ""},
+	} {
+		p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+			ClockSkew: func() time.Duration { return tc.skew }}
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, ClockSkewEnvKey+"=") {
+				got = kv
+			}
+		}
+		if got != tc.want {
+			t.Errorf("skew=%v: clock env = %q, want %q", tc.skew, got, tc.want)
+		}
+	}
+}
+
+// TestChildEnvCarriesLogShip: shipping is handed over only with both the
+// URL and the key, and a stale inherited pair is scrubbed.
+func TestChildEnvCarriesLogShip(t *testing.T) {
+	t.Setenv(LogShipURLEnvKey, "https://stale.example.com")
+	t.Setenv(LogShipKeyEnvKey, "stale")
+	for _, tc := range []struct {
+		url, key string
+		want     int
+	}{
+		{"https://ship.example.com/in", "a2V5", 2},
+		{"https://ship.example.com/in", "", 0},
+		{"", "", 0},
+	} {
+		p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+			LogShip: func() (string, string) { return tc.url, tc.key }}
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got []string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, LogShipURLEnvKey+"=") || strings.HasPrefix(kv, LogShipKeyEnvKey+"=") {
+				got = append(got, kv)
+			}
+		}
+		if len(got) != tc.want || (tc.want > 0 && got[0] != LogShipURLEnvKey+"="+tc.url) {
+			t.Errorf("url=%q key=%q: ship env = %q", tc.url, tc.key, got)
+		}
+	}
+}
+
+// TestChildEnvCarriesLogging pins the `daemon logging` hand-off: each set
+// value replaces an inherited one, and an unset one is scrubbed so the child
+// falls back to its configured level.
+func TestChildEnvCarriesLogging(t *testing.T) {
+	t.Setenv(LogLevelEnvKey, "error")
+	t.Setenv(LogFormatEnvKey, "json")
+	p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+		Logging: func() (string, string) { return "debug", "" }}
+	_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+	var got []string
+	for _, kv := range env {
+		if strings.HasPrefix(kv, LogLevelEnvKey+"=") || strings.HasPrefix(kv, LogFormatEnvKey+"=") {
+			got = append(got, kv)
+		}
+	}
+	if len(got) != 1 || got[0] != LogLevelEnvKey+"=debug" {
+		t.Fatalf("log env = %v, want [%s=debug]", got, LogLevelEnvKey)
+	}
+}
+
+// TestChildEnvCarriesLogSink: a set sink replaces an inherited one; an unset
+// one is scrubbed so the child logs to its file alone.
+func TestChildEnvCarriesLogSink(t *testing.T) {
+	t.Setenv(LogSinkEnvKey, "stale")
+	for _, tc := range []struct{ sink, want string }{{"syslog", LogSinkEnvKey + "=syslog"}, {"", ""}} {
+		p := &ProcSvc{Workdir: "/tmp/wd", Argv0: "worker",
+			LogSink: func() string { return tc.sink }}
+		_, env := p.childArgvEnv("/tmp/wd/bin/v1/platform")
+		var got string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, LogSinkEnvKey+"=") {
+				got = kv
+			}
+		}
+		if got != tc.want {
+			t.Errorf("sink %q: env %q, want %q", tc.sink, got, tc.want)
+		}
+	}
+}
@@ -5,14 +5,18 @@ package platformsvc
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 // ProcSvc manages a single platform child process.
@@ -36,6 +40,48 @@ type ProcSvc struct {
 	// disguise salt diverged from the running child's argv. Empty ⇒ no pidfile
 	// (dev runs / unit tests), preserving the legacy pgrep-only status path.
 	PidFile string
+	// TraceEndpoint, when set, returns the OTLP endpoint to hand the child as
+	// OTEL_EXPORTER_OTLP_ENDPOINT (read at every Start, so a changed setting
+	// reaches the next platform start). nil or "" ⇒ the inherited environment.
+	TraceEndpoint func() string
+	// AdminAPI, when set, returns the loopback address and bearer token the
+	// child serves its admin API with (`daemon api`), handed over as
+	// AdminAddrEnvKey / AdminTokenEnvKey at every Start. "" addr ⇒ not served.
+	AdminAPI func() (addr, token string)
+	// AdminDebug, when set, reports whether the child's unix admin socket
+	// serves the debug routes (`daemon api --debug`), handed over as
+	// AdminDebugEnvKey at every Start.
+	AdminDebug func() bool
+	// StrictLock, when set, returns how long the strict lock (`daemon lock`)
+	// still holds, handed over as StrictLockEnvKey at every Start so the
+	// child refuses break tokens. 0 ⇒ not locked.
+	StrictLock func() time.Duration
+	// ClockSkew, when set, returns how far the system clock is ahead of the
+	// trusted one, handed over as ClockSkewEnvKey at every Start so the
+	// child's sessions, breaks and pauses keep trusted time. 0 ⇒ none.
+	ClockSkew func() time.Duration
+	// Calendar, when set, returns the calendar ICS URL (`daemon calendar`),
+	// handed over as CalendarEnvKey at every Start. "" ⇒ no calendar.
+	Calendar func() string
+	// LogShip, when set, returns where the event stream is shipped and the
+	// public key it is sealed to (`daemon config set log.ship`), handed over
+	// as LogShipURLEnvKey / LogShipKeyEnvKey at every Start. "" ⇒ not
+	// shipped.
+	LogShip func() (url, key string)
+	// Echo, when set, also receives the child's stdout and stderr, beside
+	// the log file (`daemon run --foreground` passes the terminal).
+	Echo io.Writer
+	// Logging, when set, returns the log level and format (`daemon
+	// logging`), handed over as LogLevelEnvKey / LogFormatEnvKey at every
+	// Start. "" ⇒ the child's configured default.
+	Logging func() (level, format string)
+	// LogSink, when set, returns the extra log sink (`daemon logging
+	// --sink`), handed over as LogSinkEnvKey at every Start. "" ⇒ the log
+	// file alone.
+	LogSink func() string
+	// LogMode, when set, returns the file mode for PlatformLogName, applied
+	// at every Start (`daemon config set log.mode`). nil ⇒ 0600.
+	LogMode func() os.FileMode
 
 	mu        sync.Mutex
 	cmd       *exec.Cmd
@@ -44,6 +90,7 @@ type ProcSvc struct {
 	exited    bool
 	exitedAt  time.Time
 	exitCh    chan struct{} // closed by the SINGLE waiter when cmd exits
+	settings  []string      // the child's settings env at its Start (see SettingsChanged)
 }
 
 // WorkdirEnvKey carries the platform-workdir to the child in its environment
@@ -62,6 +109,45 @@ const WorkdirEnvKey = "APP_STATE_DIR"
 // WorkdirEnvKey precedent; TestPlatformStartCommandHasZeroLeaks pins the effect.
 const MeshEnvKey = "APP_LAUNCH_CONTEXT"
 
+// AdminAddrEnvKey / AdminTokenEnvKey carry the admin API address and token to
+// the child. MUST match platform adminapi.AddrEnv / TokenEnv — duplicated
+// across the module boundary like WorkdirEnvKey, and as neutral.
+const (
+	AdminAddrEnvKey  = "APP_ADMIN_ADDR"
+	AdminTokenEnvKey = "APP_ADMIN_TOKEN"
+	AdminDebugEnvKey = "APP_ADMIN_DEBUG" // platform adminapi.DebugEnv
+)
+
+// StrictLockEnvKey carries the strict lock's remaining whole seconds. MUST
+// match platform app.StrictLockEnv.
+const StrictLockEnvKey = "APP_HOLD_S"
+
+// ClockSkewEnvKey carries how many whole seconds the system clock is ahead
+// of the daemon's trusted clock. MUST match platform app.ClockSkewEnv.
+const ClockSkewEnvKey = "APP_SKEW_S"
+
+// CalendarEnvKey carries the calendar ICS URL. MUST match platform
+// calendar.URLEnv.
+const CalendarEnvKey = "APP_CAL_URL"
+
+// LogShipURLEnvKey / LogShipKeyEnvKey carry the event-shipping URL and the
+// recipient's public key. MUST match platform logship.URLEnv / KeyEnv.
+const (
+	LogShipURLEnvKey = "APP_SHIP_URL"
+	LogShipKeyEnvKey = "APP_SHIP_KEY"
+)
+
+// LogLevelEnvKey / LogFormatEnvKey carry the log level and format. MUST
+// match platform logging.LevelEnv / FormatEnv.
+const (
+	LogLevelEnvKey  = "APP_LOG_LEVEL"
+	LogFormatEnvKey = "APP_LOG_FORMAT"
+)
+
+// LogSinkEnvKey carries the extra log sink. MUST match platform
+// logging.SinkEnv.
+const LogSinkEnvKey = "APP_LOG_SINK"
+
 // PlatformLogName is the engine log file under the workdir. The engine's
 // stdout+stderr (its slog stream, plugin job output, errors/warnings) are
 // captured here so the engine is OBSERVABLE. Previously the child's stdio
@@ -99,11 +185,106 @@ func (p *ProcSvc) RunningVersion() (string, error) {
 //     marker (MeshEnvKey) is scrubbed too, so the child does not re-expose it.
 //   - legacy (Argv0 empty): argv = [binPath, "--workdir", <workdir>] and env nil
 //     (inherit) — byte-for-byte the pre-HF4 behavior (dev runs, tests, e2e).
+//
+// A configured TraceEndpoint, AdminAPI, AdminDebug, StrictLock, Calendar, LogShip, Logging or LogSink is added to the env in either branch
+// (the legacy branch then passes the full inherited environment plus it),
+// replacing any inherited value.
 func (p *ProcSvc) childArgvEnv(binPath string) (args, env []string) {
+	extra, keys := p.childSettings()
 	if p.Argv0 != "" {
-		return []string{p.Argv0, "run"}, scrubEnv(os.Environ(), WorkdirEnvKey, MeshEnvKey)
+		env = scrubEnv(os.Environ(), append([]string{WorkdirEnvKey, MeshEnvKey}, keys...)...)
+		return []string{p.Argv0, "run"}, append(env, extra...)
 	}
-	return []string{binPath, "--workdir", p.Workdir}, nil
+	if len(keys) > 0 {
+		env = append(scrubEnv(os.Environ(), keys...), extra...)
+	}
+	return []string{binPath, "--workdir", p.Workdir}, env
+}
+
+// childSettings renders the configured operator settings as KEY=VALUE env
+// entries, with the keys to scrub from the inherited environment.
+func (p *ProcSvc) childSettings() (extra, keys []string) {
+	if p.TraceEndpoint != nil {
+		if ep := p.TraceEndpoint(); ep != "" {
+			extra = append(extra, tracing.EndpointEnv+"="+ep)
+			keys = append(keys, tracing.EndpointEnv)
+		}
+	}
+	if p.AdminAPI != nil {
+		if addr, token := p.AdminAPI(); addr != "" {
+			extra = append(extra, AdminAddrEnvKey+"="+addr, AdminTokenEnvKey+"="+token)
+		}
+		// Scrubbed even when off, so a stale inherited token never serves.
+		keys = append(keys, AdminAddrEnvKey, AdminTokenEnvKey)
+	}
+	if p.AdminDebug != nil {
+		if p.AdminDebug() {
+			extra = append(extra, AdminDebugEnvKey+"=1")
+		}
+		keys = append(keys, AdminDebugEnvKey)
+	}
+	if p.StrictLock != nil {
+		if d := p.StrictLock(); d > 0 {
+			extra = append(extra, StrictLockEnvKey+"="+strconv.FormatInt(int64(d/time.Second), 10))
+		}
+		keys = append(keys, StrictLockEnvKey)
+	}
+	if p.ClockSkew != nil {
+		if d := p.ClockSkew() / time.Second; d != 0 {
+			extra = append(extra, ClockSkewEnvKey+"="+strconv.FormatInt(int64(d), 10))
+		}
+		keys = append(keys, ClockSkewEnvKey)
+	}
+	if p.Calendar != nil {
+		if u := p.Calendar(); u != "" {
+			extra = append(extra, CalendarEnvKey+"="+u)
+		}
+		keys = append(keys, CalendarEnvKey)
+	}
+	if p.LogShip != nil {
+		if u, k := p.LogShip(); u != "" && k != "" {
+			extra = append(extra, LogShipURLEnvKey+"="+u, LogShipKeyEnvKey+"="+k)
+		}
+		keys = append(keys, LogShipURLEnvKey, LogShipKeyEnvKey)
+	}
+	if p.Logging != nil {
+		level, format := p.Logging()
+		if level != "" {
+			extra = append(extra, LogLevelEnvKey+"="+level)
+		}
+		if format != "" {
+			extra = append(extra, LogFormatEnvKey+"="+format)
+		}
+		keys = append(keys, LogLevelEnvKey, LogFormatEnvKey)
+	}
+	if p.LogSink != nil {
+		if s := p.LogSink(); s != "" {
+			extra = append(extra, LogSinkEnvKey+"="+s)
+		}
+		keys = append(keys, LogSinkEnvKey)
+	}
+	return extra, keys
+}
+
+// reloadable is childSettings minus the strict-lock countdown, which changes
+// every minute and reaches a running child over its admin socket anyway.
+func (p *ProcSvc) reloadable() []string {
+	extra, _ := p.childSettings()
+	return slices.DeleteFunc(extra, func(kv string) bool {
+		return strings.HasPrefix(kv, StrictLockEnvKey+"=")
+	})
+}
+
+// SettingsChanged reports whether the live child started with different
+// settings than the ones configured now, so only a restart would hand it
+// the new ones (`daemon config set`). False when no child is running.
+func (p *ProcSvc) SettingsChanged() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cmd == nil || p.exited {
+		return false
+	}
+	return !slices.Equal(p.settings, p.reloadable())
 }
 
 // scrubEnv returns a copy of env with every "KEY=..." entry whose key is in keys
@@ -164,8 +345,17 @@ func (p *ProcSvc) Start(binPath, v string) error {
 	// NOT block protection from starting, so we degrade to the prior
 	// (discarded) behavior rather than refuse to run. The common path — the
 	// workdir is writable (it already holds state.db) — always succeeds.
+	logMode := os.FileMode(0o600)
+	if p.LogMode != nil {
+		logMode = p.LogMode()
+	}
 	logf, lerr := os.OpenFile(filepath.Join(p.Workdir, PlatformLogName),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND, logMode)
+	if lerr == nil {
+		// OpenFile's mode only applies on create; an older log keeps its
+		// bits until told otherwise.
+		_ = logf.Chmod(logMode)
+	}
 	if lerr != nil {
 		// Observability must not fail SILENTLY. Record why on the daemon's
 		// own stderr (captured to daemon.log) before degrading to discarded
@@ -175,6 +365,13 @@ func (p *ProcSvc) Start(binPath, v string) error {
 		c.Stdout = logf
 		c.Stderr = logf
 	}
+	if p.Echo != nil {
+		var out io.Writer = p.Echo
+		if logf != nil {
+			out = io.MultiWriter(logf, p.Echo)
+		}
+		c.Stdout, c.Stderr = out, out
+	}
 	if err := c.Start(); err != nil {
 		if logf != nil {
 			logf.Close()
@@ -188,6 +385,7 @@ func (p *ProcSvc) Start(binPath, v string) error {
 	p.exited = false
 	p.exitedAt = time.Time{}
 	p.exitCh = exitCh
+	p.settings = p.reloadable()
 
 	// P3 (HF4): publish the child's pid to the salt-independent liveness pidfile
 	// (under p.mu, so a concurrent Start's write and this one are serialized).
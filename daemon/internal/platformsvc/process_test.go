@@ -1,10 +1,12 @@
 package platformsvc
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -73,6 +75,32 @@ func TestStartAppendsAcrossRestarts(t *testing.T) {
 	}
 }
 
+// TestStartNarrowsLogMode: the log is kept to its owner by default, an
+// older world-readable log is narrowed at the next Start, and LogMode
+// widens it no further than asked.
+func TestStartNarrowsLogMode(t *testing.T) {
+	wd := t.TempDir()
+	script := filepath.Join(wd, "fake-engine")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(wd, PlatformLogName)
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := New(wd)
+	for _, want := range []os.FileMode{0o600, 0o640} {
+		if err := p.Start(script, "v1"); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		<-p.exitCh
+		if fi, err := os.Stat(logPath); err != nil || fi.Mode().Perm() != want {
+			t.Fatalf("log mode = %v, want %v (err %v)", fi.Mode().Perm(), want, err)
+		}
+		p.LogMode = func() os.FileMode { return 0o640 }
+	}
+}
+
 // --- P3 (HF4) salt-independent liveness pidfile mechanics -------------------
 
 // TestWritePidFile pins the atomic temp+rename write: the pid lands in the file
@@ -270,3 +298,81 @@ func TestClearExitNoopWhileLive(t *testing.T) {
 		t.Fatalf("ClearExit must not disturb a live child, RunningVersion=%q", v)
 	}
 }
+
+// TestStartEchoesChildOutput: with Echo set (`daemon run --foreground`) the
+// child's output reaches Echo and still lands in the log file.
+func TestStartEchoesChildOutput(t *testing.T) {
+	wd := t.TempDir()
+	script := filepath.Join(wd, "fake-engine")
+	body := "#!/bin/sh\necho ENGINE_STDOUT_LINE\necho ENGINE_STDERR_LINE >&2\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var echo syncBuffer
+	p := New(wd)
+	p.Echo = &echo
+	if err := p.Start(script, "v1"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	select {
+	case <-p.exitCh:
+	case <-time.After(3 * time.Second):
+		t.Fatal("engine did not exit in time")
+	}
+
+	for _, want := range []string{"ENGINE_STDOUT_LINE", "ENGINE_STDERR_LINE"} {
+		if !strings.Contains(echo.String(), want) {
+			t.Errorf("echo missing %s; got %q", want, echo.String())
+		}
+	}
+	b, _ := os.ReadFile(filepath.Join(wd, PlatformLogName))
+	if !strings.Contains(string(b), "ENGINE_STDOUT_LINE") {
+		t.Errorf("log file lost the output when echoing; got %q", b)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the exec copier goroutines.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+// TestSettingsChanged: a live child reports stale settings once what it
+// would be started with differs from what it was started with.
+func TestSettingsChanged(t *testing.T) {
+	wd := t.TempDir()
+	script := filepath.Join(wd, "fake-engine")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nsleep 5\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	level := "info"
+	p := New(wd)
+	p.Logging = func() (string, string) { return level, "" }
+	if p.SettingsChanged() {
+		t.Fatal("no child, yet settings changed")
+	}
+	if err := p.Start(script, "v1"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer p.Stop()
+	if p.SettingsChanged() {
+		t.Fatal("fresh child already stale")
+	}
+	level = "debug"
+	if !p.SettingsChanged() {
+		t.Fatal("log level change not seen")
+	}
+}
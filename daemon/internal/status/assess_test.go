@@ -205,12 +205,12 @@ func TestCombine_FoldsPlatformVerdict(t *testing.T) {
 		wantExit        int
 	}{
 		{
-			name:            "daemon healthy + platform degraded => DEGRADED/exit1",
+			name:            "daemon healthy + platform degraded => DEGRADED/exit2",
 			daemon:          healthy,
 			platformVerdict: Degraded,
 			platformOK:      true,
 			wantVerdict:     Degraded,
-			wantExit:        1,
+			wantExit:        2,
 		},
 		{
 			name:            "daemon healthy + platform unavailable => stays daemon verdict (exit0)",
@@ -221,20 +221,20 @@ func TestCombine_FoldsPlatformVerdict(t *testing.T) {
 			wantExit:        0,
 		},
 		{
-			name:            "daemon down + platform healthy => daemon DOWN wins (exit2)",
+			name:            "daemon down + platform healthy => daemon DOWN wins (exit3)",
 			daemon:          Result{Verdict: Down, Note: "x"},
 			platformVerdict: Healthy,
 			platformOK:      true,
 			wantVerdict:     Down,
-			wantExit:        2,
+			wantExit:        3,
 		},
 		{
-			name:            "daemon healthy + platform down => DOWN/exit2",
+			name:            "daemon healthy + platform down => DOWN/exit3",
 			daemon:          healthy,
 			platformVerdict: Down,
 			platformOK:      true,
 			wantVerdict:     Down,
-			wantExit:        2,
+			wantExit:        3,
 		},
 		{
 			name:            "daemon unknown + platform healthy => daemon UNKNOWN wins over healthy",
@@ -304,8 +304,8 @@ func TestExitCode(t *testing.T) {
 	cases := map[Verdict]int{
 		Healthy:  0,
 		Unknown:  0, // unknown folds into healthy-for-exit
-		Degraded: 1,
-		Down:     2,
+		Degraded: 2,
+		Down:     3,
 	}
 	for v, want := range cases {
 		if got := ExitCode(v); got != want {
@@ -313,3 +313,21 @@ func TestExitCode(t *testing.T) {
 		}
 	}
 }
+
+// TestAssess_RestoreChainNeverDegrades: a broken restore chain is reported in
+// its own section but must not move the verdict of a healthy install.
+func TestAssess_RestoreChainNeverDegrades(t *testing.T) {
+	healthy := Snapshot{
+		Mode:       "system",
+		MeshLoaded: 3, MeshTotal: 3,
+		ProcCount: 1,
+		Desired:   "v1", Good: "v1",
+		Found:         true,
+		BackupChecked: true,
+		BackupPresent: true, // present but unverified → corrupt
+		RemoteChecked: true, // and the release is unreachable
+	}
+	if got := Assess(healthy).Verdict; got != Healthy {
+		t.Fatalf("verdict = %s; want HEALTHY (restore chain is render-only)", got)
+	}
+}
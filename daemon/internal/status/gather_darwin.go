@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -23,6 +24,7 @@ import (
 	"github.com/eliteGoblin/focusd/daemon/internal/platdir"
 	"github.com/eliteGoblin/focusd/daemon/internal/sig"
 	"github.com/eliteGoblin/focusd/daemon/internal/status/redact"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
 )
 
 // sigVerifier is the signature-check seam for the platform binary about to be
@@ -121,6 +123,17 @@ func Gather(workdirOverride string, jsonMode bool) (Snapshot, PlatformDetail) {
 	s.WatchdogCron = railPresent
 	s.WatchdogCopyOK = copyOK
 
+	// --- Restore chain: the companion's offline copy + restore pin ---
+	// Same boundary discipline: only bools, a time and a version cross.
+	bh := osadapter.CompanionBackupHealth(m)
+	s.BackupChecked = true
+	s.BackupPresent = bh.Present
+	s.BackupVerified = bh.Verified
+	s.BackupPin = bh.Pin
+	if bh.Present {
+		s.BackupAge = time.Since(bh.RefreshedAt)
+	}
+
 	// --- Discover the install (workdir + binary path stay tokenised) ---
 	var workdirTok redact.Token
 	if workdirOverride != "" {
@@ -158,6 +171,12 @@ func Gather(workdirOverride string, jsonMode bool) (Snapshot, PlatformDetail) {
 		s.Desired = desired
 		s.Good = good
 		s.VersionsUnknown = vUnknown
+		s.StrictLockLeft = strictLockLeft(workdirTok)
+		s.GateStep, s.GateWait, s.GatePartner = gateProgress(workdirTok)
+		if t, ok := lastSelfTest(workdirTok); ok {
+			s.SelfTestChecked, s.SelfTestFailed, s.SelfTestSignature = true, t.Failed, t.Signature
+		}
+		s.Crashes24h, s.LastCrashAge = recentCrashes(workdirTok, time.Now())
 
 		// Warming up: no good version yet AND install is younger than the
 		// warmup window (derive age from version.json mtime, inside Use).
@@ -185,6 +204,26 @@ func Gather(workdirOverride string, jsonMode bool) (Snapshot, PlatformDetail) {
 	return s, pd
 }
 
+// ProbeHealth reads the healthcheck's three facts: the launchd jobs, the
+// companion heartbeat and the supervised platform child, and the install's
+// hung-worker threshold. The install path never leaves this function.
+func ProbeHealth() Probe {
+	m := mode.Resolve()
+	var p Probe
+	cur, err := osadapter.FindCurrentInstall(m, sig.VerifyFile)
+	if err != nil {
+		p.MeshUnknown = true
+	} else {
+		p.MeshLoaded, p.MeshTotal, p.MeshFound = osadapter.MeshStatusOf(cur)
+	}
+	p.HeartbeatAge, p.HeartbeatSeen = osadapter.CompanionHeartbeatAge(m)
+	if cur.Workdir != "" {
+		p.PlatformUp = platformPidUp(cur.Workdir)
+		p.HungAfter = (&core.Store{Dir: cur.Workdir}).HungAfter()
+	}
+	return p
+}
+
 // readVersions reads desired + good from the store under the tokenised
 // workdir. vUnknown=true when the workdir is unreadable (permission/absent) —
 // distinct from "readable but no good promoted yet" (good=="").
@@ -209,6 +248,58 @@ func readVersions(workdir redact.Token) (desired, good string, vUnknown bool) {
 	return v.desired, v.good, v.vUnknown
 }
 
+// strictLockLeft reads how long the strict lock still holds from the store.
+func strictLockLeft(workdir redact.Token) time.Duration {
+	return redact.Use(workdir, func(raw string) time.Duration {
+		st := &core.Store{Dir: raw}
+		return st.StrictLock().Remaining(st.Now())
+	})
+}
+
+// gateProgress reads the uninstall gate's progress from the store: steps
+// done, the wait before the next one opens, and whether the partner's code
+// is all that is left.
+func gateProgress(workdir redact.Token) (int, time.Duration, bool) {
+	type progress struct {
+		step    int
+		wait    time.Duration
+		partner bool
+	}
+	p := redact.Use(workdir, func(raw string) progress {
+		st := &core.Store{Dir: raw}
+		now := st.Now()
+		g := st.UninstallGate(now)
+		switch o := uninstallgate.Evaluate(g, now); o.Kind {
+		case uninstallgate.Wait:
+			return progress{step: g.Step, wait: o.Remaining}
+		case uninstallgate.Proceed:
+			return progress{step: g.Step, partner: st.Partner() != "" && g.Approved.IsZero()}
+		}
+		return progress{step: g.Step}
+	})
+	return p.step, p.wait, p.partner
+}
+
+// lastSelfTest reads the recorded boot self-test from the store.
+func lastSelfTest(workdir redact.Token) (core.SelfTest, bool) {
+	return redactUse2(workdir, func(raw string) (core.SelfTest, bool) {
+		return (&core.Store{Dir: raw}).LastSelfTest()
+	})
+}
+
+// recentCrashes counts the worker crashes the store recorded in the day
+// before now, and how long ago the latest one was.
+func recentCrashes(workdir redact.Token, now time.Time) (int, time.Duration) {
+	return redactUse2(workdir, func(raw string) (int, time.Duration) {
+		cs := (&core.Store{Dir: raw}).Crashes()
+		n := core.CrashesSince(cs, now.Add(-24*time.Hour))
+		if n == 0 {
+			return 0, 0
+		}
+		return n, now.Sub(cs[len(cs)-1].At)
+	})
+}
+
 // installAge returns how long ago version.json was last written, used to tell
 // "warming up" from "down". The path stays inside the Use closure.
 func installAge(workdir redact.Token) (time.Duration, bool) {
@@ -486,3 +577,31 @@ func redactUse2[A any, B any](t redact.Token, fn func(raw string) (A, B)) (A, B)
 	})
 	return p.a, p.b
 }
+
+// ReadPublished reads the system daemon's published status (publish.go) as
+// of now. ok is false when there is none, or it is not root-owned and
+// root-only-writable — a file anyone could have written says nothing — or
+// it does not verify.
+func ReadPublished(now time.Time) (Snapshot, Result, bool) {
+	f, err := os.Open(PublishedPath())
+	if err != nil {
+		return Snapshot{}, Result{}, false
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() || fi.Mode().Perm()&0o022 != 0 {
+		return Snapshot{}, Result{}, false
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); !ok || st.Uid != 0 {
+		return Snapshot{}, Result{}, false
+	}
+	b, err := io.ReadAll(io.LimitReader(f, maxPublished))
+	if err != nil {
+		return Snapshot{}, Result{}, false
+	}
+	s, res, err := DecodePublished(b, now)
+	if err != nil {
+		return Snapshot{}, Result{}, false
+	}
+	return s, res, true
+}
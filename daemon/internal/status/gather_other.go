@@ -2,7 +2,10 @@
 
 package status
 
-import "runtime"
+import (
+	"runtime"
+	"time"
+)
 
 // Gather on non-darwin platforms has no launchd mesh / disguised install to
 // inspect. It returns an honest "unknown" snapshot and an unavailable
@@ -18,3 +21,15 @@ func Gather(workdirOverride string, jsonMode bool) (Snapshot, PlatformDetail) {
 		PlatformUnavailable: true,
 	}, PlatformDetail{Available: false}
 }
+
+// ReadPublished has nothing to read off darwin: only a macOS system install
+// publishes its status.
+func ReadPublished(time.Time) (Snapshot, Result, bool) {
+	return Snapshot{}, Result{}, false
+}
+
+// ProbeHealth has no launchd mesh to probe off darwin: the mesh reads as
+// unreadable, never as down.
+func ProbeHealth() Probe {
+	return Probe{MeshUnknown: true}
+}
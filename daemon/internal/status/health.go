@@ -0,0 +1,114 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/core"
+)
+
+// Health is `daemon healthcheck`'s answer, numbered as the Nagios plugin
+// convention (and launchd and cron wrappers) read exit codes. The probe
+// yields the first three; HealthUnknown is a check that could not run.
+type Health int
+
+const (
+	HealthOK      Health = 0
+	HealthWarn    Health = 1
+	HealthCrit    Health = 2
+	HealthUnknown Health = 3
+)
+
+func (h Health) String() string {
+	switch h {
+	case HealthOK:
+		return "OK"
+	case HealthWarn:
+		return "WARNING"
+	case HealthUnknown:
+		return "UNKNOWN"
+	}
+	return "CRITICAL"
+}
+
+// Probe is the healthcheck's primitive-only view: the three facts cheap
+// enough to read in well under a second. No signature walk of the backup,
+// no generation scan and no `platform status` exec; `daemon status` does
+// those.
+type Probe struct {
+	// Mesh: launchd jobs loaded out of the roles expected. MeshFound is
+	// false with no install; MeshUnknown when the probe failed (a system
+	// install read without sudo).
+	MeshLoaded  int
+	MeshTotal   int
+	MeshFound   bool
+	MeshUnknown bool
+
+	// HeartbeatAge is how long ago a mesh worker last touched the
+	// companion heartbeat; HeartbeatSeen is false when there is none.
+	HeartbeatAge  time.Duration
+	HeartbeatSeen bool
+
+	// PlatformUp: the supervised platform child is alive.
+	PlatformUp bool
+
+	// HungAfter is the install's hung-worker threshold (Store.HungAfter);
+	// 0 ⇒ core.DefaultHungAfter.
+	HungAfter time.Duration
+}
+
+// HeartbeatWarnAfter is when a quiet heartbeat starts to warn: a worker
+// touches it every 15s, so a minute is four missed beats.
+const HeartbeatWarnAfter = time.Minute
+
+// AssessHealth classifies p. A heartbeat older than the hung-worker
+// threshold is critical, so the monitor and the watchdog's restart agree
+// on what hung means. The summary is one line of counts and ages, never a
+// path or label.
+func AssessHealth(p Probe) (Health, string) {
+	critAfter := p.HungAfter
+	if critAfter <= 0 {
+		critAfter = core.DefaultHungAfter
+	}
+	h := HealthOK
+	raise := func(to Health) { h = max(h, to) }
+	var parts []string
+
+	switch {
+	case p.MeshUnknown:
+		raise(HealthWarn)
+		parts = append(parts, "mesh unreadable (re-run with sudo?)")
+	case !p.MeshFound:
+		raise(HealthCrit)
+		parts = append(parts, "no install")
+	default:
+		if p.MeshLoaded == 0 {
+			raise(HealthCrit)
+		} else if p.MeshLoaded < p.MeshTotal {
+			raise(HealthWarn)
+		}
+		parts = append(parts, fmt.Sprintf("mesh %d/%d loaded", p.MeshLoaded, p.MeshTotal))
+	}
+
+	switch {
+	case !p.HeartbeatSeen:
+		raise(HealthCrit)
+		parts = append(parts, "no heartbeat")
+	default:
+		if p.HeartbeatAge >= critAfter {
+			raise(HealthCrit)
+		} else if p.HeartbeatAge >= HeartbeatWarnAfter {
+			raise(HealthWarn)
+		}
+		parts = append(parts, "heartbeat "+p.HeartbeatAge.Round(time.Second).String()+" ago")
+	}
+
+	if p.PlatformUp {
+		parts = append(parts, "platform up")
+	} else {
+		raise(HealthCrit)
+		parts = append(parts, "platform down")
+	}
+	return h, h.String() + " - " + strings.Join(parts, ", ")
+}
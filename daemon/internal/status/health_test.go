@@ -0,0 +1,39 @@
+package status
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssessHealth(t *testing.T) {
+	well := Probe{MeshLoaded: 3, MeshTotal: 3, MeshFound: true,
+		HeartbeatAge: 5 * time.Second, HeartbeatSeen: true, PlatformUp: true, HungAfter: 2 * time.Minute}
+	cases := []struct {
+		name string
+		edit func(*Probe)
+		want Health
+	}{
+		{"healthy", func(*Probe) {}, HealthOK},
+		{"one job unloaded", func(p *Probe) { p.MeshLoaded = 2 }, HealthWarn},
+		{"mesh unreadable", func(p *Probe) { p.MeshUnknown = true }, HealthWarn},
+		{"heartbeat late", func(p *Probe) { p.HeartbeatAge = 90 * time.Second }, HealthWarn},
+		{"heartbeat hung", func(p *Probe) { p.HeartbeatAge = 3 * time.Minute }, HealthCrit},
+		{"hung-after raised", func(p *Probe) { p.HeartbeatAge, p.HungAfter = 3*time.Minute, 5*time.Minute }, HealthWarn},
+		{"no heartbeat", func(p *Probe) { p.HeartbeatSeen = false }, HealthCrit},
+		{"no jobs loaded", func(p *Probe) { p.MeshLoaded = 0 }, HealthCrit},
+		{"no install", func(p *Probe) { p.MeshFound = false }, HealthCrit},
+		{"platform down", func(p *Probe) { p.PlatformUp = false }, HealthCrit},
+	}
+	for _, tc := range cases {
+		p := well
+		tc.edit(&p)
+		got, line := AssessHealth(p)
+		if got != tc.want {
+			t.Errorf("%s: %v (%s), want %v", tc.name, got, line, tc.want)
+		}
+		if strings.Contains(line, "\n") || !strings.HasPrefix(line, tc.want.String()+" - ") {
+			t.Errorf("%s: summary %q is not one line led by the verdict", tc.name, line)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package status
+
+// publish.go: the published status (feature 09). A system install's
+// daemon-home is root-only, so a plain `daemon status` from the logged-in
+// user stopped at "unknown (re-run with sudo)". The lock-holding system
+// worker now writes its own Snapshot and verdict, signed with the device
+// key, to a world-readable file at a fixed path; a non-root status that
+// finds no install of its own reads that instead. A Snapshot holds only
+// primitives by construction, so publishing it leaks nothing disguised.
+// The platform passthrough is not published: only its verdict, already
+// folded into the published one.
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/heartbeat"
+	"github.com/eliteGoblin/focusd/daemon/internal/mode"
+)
+
+// PublishedName is the published status file's basename, directly under
+// the system Application Support root (the companion's disguise family).
+const PublishedName = ".com.apple.MobileAsset.state"
+
+// PublishedPath is where a system install publishes its status.
+func PublishedPath() string {
+	return filepath.Join(mode.SupportRoot(mode.System, ""), PublishedName)
+}
+
+// PublishInterval is how often the system worker republishes; a snapshot
+// older than publishedStale no longer says anything about now.
+const (
+	PublishInterval = 2 * time.Minute
+	publishedStale  = 5 * PublishInterval
+)
+
+// maxPublished bounds a read of the published file; a real one is a few KB.
+const maxPublished = 64 << 10
+
+// published is the signed payload.
+type published struct {
+	At       time.Time `json:"at"`
+	Snapshot Snapshot  `json:"snapshot"`
+	Verdict  Verdict   `json:"verdict"`
+	Note     string    `json:"note"`
+}
+
+// envelope is the file: the payload bytes, the device public key and the
+// signature over the payload.
+type envelope struct {
+	Body []byte `json:"body"`
+	Key  string `json:"key"`
+	Sig  string `json:"sig"`
+}
+
+// EncodePublished signs s and res, taken at at, with key.
+func EncodePublished(s Snapshot, res Result, at time.Time, key ed25519.PrivateKey) ([]byte, error) {
+	body, err := json.Marshal(published{At: at.UTC(), Snapshot: s, Verdict: res.Verdict, Note: res.Note})
+	if err != nil {
+		return nil, err
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	return json.Marshal(envelope{Body: body, Key: heartbeat.PublicKeyString(pub), Sig: heartbeat.Sign(key, body)})
+}
+
+// DecodePublished verifies b and returns the snapshot and verdict it
+// carries, marked Published with its age at now. A snapshot past
+// publishedStale keeps its facts but its verdict turns UNKNOWN: the daemon
+// that wrote it may be gone.
+func DecodePublished(b []byte, now time.Time) (Snapshot, Result, error) {
+	var env envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return Snapshot{}, Result{}, errors.New("published status is not readable")
+	}
+	pub, err := base64.StdEncoding.DecodeString(env.Key)
+	if err != nil || !heartbeat.Verify(ed25519.PublicKey(pub), env.Body, env.Sig) {
+		return Snapshot{}, Result{}, errors.New("published status signature does not verify")
+	}
+	var p published
+	if err := json.Unmarshal(env.Body, &p); err != nil || p.At.IsZero() {
+		return Snapshot{}, Result{}, errors.New("published status is not readable")
+	}
+	s := p.Snapshot
+	s.Published = true
+	s.PublishedAge = max(now.Sub(p.At), 0)
+	res := Result{Verdict: p.Verdict, Note: p.Note}
+	if s.PublishedAge > publishedStale {
+		res = Result{Unknown, fmt.Sprintf("published status is stale (written %s); re-run with sudo for a live read", agoLine(s.PublishedAge))}
+	}
+	return s, res, nil
+}
+
+// Publish writes the signed status to path, world-readable, replacing it
+// atomically so a reader never sees half a file.
+func Publish(path string, s Snapshot, res Result, at time.Time, key ed25519.PrivateKey) error {
+	b, err := EncodePublished(s, res, at, key)
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.tmp.%d", path, os.Getpid())
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	// WriteFile's mode is cut by the umask; the point is that others read it.
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package status
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+}
+
+func TestPublished_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := realisticSnapshot()
+	b, err := EncodePublished(in, Result{Degraded, "1 role down"}, at, testKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, res, err := DecodePublished(b, at.Add(3*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Published || s.PublishedAge != 3*time.Minute {
+		t.Fatalf("published=%v age=%v", s.Published, s.PublishedAge)
+	}
+	if s.MeshLoaded != in.MeshLoaded || s.Desired != in.Desired || s.Good != in.Good {
+		t.Fatalf("facts changed in transit: %+v", s)
+	}
+	if res != (Result{Degraded, "1 role down"}) {
+		t.Fatalf("res = %+v", res)
+	}
+}
+
+func TestPublished_TamperedBodyRejected(t *testing.T) {
+	at := time.Now()
+	b, _ := EncodePublished(realisticSnapshot(), Result{Degraded, "1 role down"}, at, testKey(t))
+	var env envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		t.Fatal(err)
+	}
+	env.Body = bytes.Replace(env.Body, []byte(Degraded), []byte(Healthy), 1)
+	forged, _ := json.Marshal(env)
+	if _, _, err := DecodePublished(forged, at); err == nil {
+		t.Fatal("a doctored body verified")
+	}
+	if _, _, err := DecodePublished([]byte("{}"), at); err == nil {
+		t.Fatal("an empty envelope verified")
+	}
+}
+
+func TestPublished_StaleReadsUnknown(t *testing.T) {
+	at := time.Now()
+	b, _ := EncodePublished(realisticSnapshot(), Result{Healthy, "all good"}, at, testKey(t))
+	s, res, err := DecodePublished(b, at.Add(publishedStale+time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verdict != Unknown || !strings.Contains(res.Note, "stale") {
+		t.Fatalf("stale snapshot read as %+v", res)
+	}
+	if s.MeshLoaded != 2 {
+		t.Fatal("a stale snapshot should keep its facts")
+	}
+}
+
+func TestPublish_WorldReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), PublishedName)
+	at := time.Now()
+	if err := Publish(path, realisticSnapshot(), Result{Healthy, "ok"}, at, testKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o644 {
+		t.Fatalf("mode %v, want 0644", fi.Mode().Perm())
+	}
+	b, _ := os.ReadFile(path)
+	if _, _, err := DecodePublished(b, at); err != nil {
+		t.Fatalf("published file does not decode: %v", err)
+	}
+}
+
+func TestRender_PublishedSource(t *testing.T) {
+	s := realisticSnapshot()
+	s.Published, s.PublishedAge = true, 4*time.Minute
+	var buf bytes.Buffer
+	RenderText(s, Result{Healthy, "ok"}, PlatformDetail{}, &buf, false)
+	out := buf.String()
+	if !strings.Contains(out, "published by the system daemon 4m ago") || !strings.Contains(out, "not published (re-run with sudo)") {
+		t.Fatalf("published render missing its source lines:\n%s", out)
+	}
+	for _, f := range forbidden {
+		if strings.Contains(out, f) {
+			t.Fatalf("published render leaks %q", f)
+		}
+	}
+}
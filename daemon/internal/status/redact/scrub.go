@@ -0,0 +1,33 @@
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pathRE matches an absolute or home-relative filesystem path: a "/" or "~/"
+// run up to whitespace, a quote, or a key=value / list separator. It also
+// catches the path half of a URL ("https:" survives, the host and path do
+// not) — over-redacting a URL is the safe direction.
+var pathRE = regexp.MustCompile(`~?/[^\s"'=,;()\[\]{}<>]+`)
+
+// Scrub rewrites free text (a log tail, `launchctl print` output) for
+// sharing: every occurrence of a known secret (a workdir, a launchd label) is
+// replaced by Placeholder, then every remaining path-like run is too. It is
+// the text-side counterpart of Token for output the daemon did not render
+// itself and so cannot keep primitive-only. Empty secrets are ignored.
+func Scrub(text string, secrets ...string) string {
+	// Longest first, so a workdir is replaced before a label it contains.
+	s := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		if v != "" {
+			s = append(s, v)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool { return len(s[i]) > len(s[j]) })
+	for _, v := range s {
+		text = strings.ReplaceAll(text, v, Placeholder)
+	}
+	return pathRE.ReplaceAllString(text, Placeholder)
+}
@@ -0,0 +1,23 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScrubRemovesSecretsAndPaths(t *testing.T) {
+	in := `time=x level=WARN msg=ensure-all err="open /Users/me/Library/x7/run.log: denied" label=com.apple.qx7 home=~/Library/x7
+state = running
+program = /Library/Application Support/x7/bin`
+	got := Scrub(in, "com.apple.qx7", "", "x7")
+	for _, leak := range []string{"/Users", "com.apple.qx7", "Library", "x7"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("scrubbed text still contains %q:\n%s", leak, got)
+		}
+	}
+	for _, keep := range []string{"level=WARN", "msg=ensure-all", "state = running"} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("scrub removed diagnostic text %q:\n%s", keep, got)
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/daemon/internal/lockin"
+	"github.com/eliteGoblin/focusd/daemon/internal/uninstallgate"
 )
 
 // ANSI colours; suppressed when color=false (NO_COLOR / --no-color).
@@ -99,6 +104,9 @@ func RenderText(s Snapshot, res Result, pd PlatformDetail, out io.Writer, color
 
 	fmt.Fprintln(out, "focusd daemon status")
 	fmt.Fprintf(out, "  %-22s %s\n", "mode", s.Mode)
+	if s.Published {
+		fmt.Fprintf(out, "  %-22s %s\n", "source", "published by the system daemon "+agoLine(s.PublishedAge)+" (sudo for a live read)")
+	}
 
 	// Engine (mesh roles).
 	fmt.Fprintf(out, "  %-22s %s\n", "protection engine", engineLine(s))
@@ -115,6 +123,29 @@ func RenderText(s Snapshot, res Result, pd PlatformDetail, out io.Writer, color
 		fmt.Fprintf(out, "  %-22s %s\n", "generations", generationsLine(s))
 	}
 
+	// Strict lock (`daemon lock`): shown only while it holds.
+	if s.StrictLockLeft > 0 {
+		fmt.Fprintf(out, "  %-22s %s\n", "strict lock", "on, "+lockin.FormatLeft(s.StrictLockLeft)+" left")
+	}
+
+	// Uninstall gate: shown only once a step is done.
+	if s.GateStep > 0 {
+		fmt.Fprintf(out, "  %-22s %s\n", "uninstall gate", gateLine(s))
+	}
+
+	// Last boot self-test: shown whenever one was recorded, failures named.
+	if s.SelfTestChecked {
+		fmt.Fprintf(out, "  %-22s %s\n", "self-test", selfTestLine(s))
+	}
+	if s.SelfTestSignature != "" {
+		fmt.Fprintf(out, "  %-22s %s\n", "code signature", s.SelfTestSignature)
+	}
+
+	// Worker crashes: shown only when one was recorded in the last day.
+	if s.Crashes24h > 0 {
+		fmt.Fprintf(out, "  %-22s %d in the last 24h, last %s\n", "crashes", s.Crashes24h, agoLine(s.LastCrashAge))
+	}
+
 	// Out-of-band watchdog rail liveness (FEATURE 12 / ADR-0016). PRESENT-ONLY:
 	// the watchdog is a best-effort, flaky secondary rail — it must never read
 	// as a problem on the CURRENT-state status. We print the line ONLY when the
@@ -126,6 +157,22 @@ func RenderText(s Snapshot, res Result, pd PlatformDetail, out io.Writer, color
 		fmt.Fprintf(out, "  %-22s %s\n", "out-of-band watchdog", "present")
 	}
 
+	// Restore chain: each rung a wiped install falls back on. Unlike the
+	// watchdog line this shows problems (a corrupt copy, an unreachable
+	// release) — the point is to learn the chain is broken before it is
+	// needed — but it still never drives OVERALL.
+	if s.BackupChecked {
+		fmt.Fprintln(out, "restore chain")
+		fmt.Fprintf(out, "  %-22s %s\n", "offline copy", backupLine(s))
+		if s.BackupPin != "" {
+			fmt.Fprintf(out, "  %-22s %s\n", "restore pin", s.BackupPin)
+		}
+		if s.BakedFallback != "" {
+			fmt.Fprintf(out, "  %-22s %s\n", "baked fallback", s.BakedFallback)
+		}
+		fmt.Fprintf(out, "  %-22s %s\n", "release download", remoteLine(s))
+	}
+
 	// Platform passthrough section.
 	fmt.Fprintln(out, "platform protections")
 	if pd.Available && pd.TextOutput != "" {
@@ -134,6 +181,8 @@ func RenderText(s Snapshot, res Result, pd PlatformDetail, out io.Writer, color
 		if pd.TextOutput[len(pd.TextOutput)-1] != '\n' {
 			fmt.Fprintln(out)
 		}
+	} else if s.Published {
+		fmt.Fprintf(out, "  %-22s %s\n", "(detail)", "not published (re-run with sudo)")
 	} else {
 		fmt.Fprintf(out, "  %-22s %s\n", "(detail)", "unavailable (platform process not reporting)")
 	}
@@ -195,6 +244,77 @@ func watchdogPresent(s Snapshot) bool {
 	return s.WatchdogChecked && s.WatchdogCron && s.WatchdogCopyOK
 }
 
+// gateLine is the uninstall gate's progress: steps done of the three, and
+// when the next opens.
+func gateLine(s Snapshot) string {
+	done := fmt.Sprintf("step %d of %d done", s.GateStep, uninstallgate.TotalSteps)
+	switch {
+	case s.GatePartner:
+		return done + ", waiting on the partner's code"
+	case s.GateStep >= uninstallgate.TotalSteps:
+		return done + ", uninstall open"
+	case s.GateWait > 0:
+		return done + ", next in " + lockin.FormatLeft(s.GateWait)
+	}
+	return done + ", next step open"
+}
+
+func selfTestLine(s Snapshot) string {
+	if len(s.SelfTestFailed) == 0 {
+		return "passed"
+	}
+	return "FAILED: " + strings.Join(s.SelfTestFailed, ", ")
+}
+
+// backupState buckets the offline copy: missing, corrupt (present but fails
+// signature verification), or verified. "" when the section was not checked.
+func backupState(s Snapshot) string {
+	switch {
+	case !s.BackupChecked:
+		return ""
+	case !s.BackupPresent:
+		return "missing"
+	case !s.BackupVerified:
+		return "corrupt"
+	default:
+		return "verified"
+	}
+}
+
+func backupLine(s Snapshot) string {
+	st := backupState(s)
+	if !s.BackupPresent {
+		return st
+	}
+	return fmt.Sprintf("%s (changed %s)", st, agoLine(s.BackupAge))
+}
+
+// agoLine renders an elapsed duration at a coarse, human grain. Negative
+// (clock skew) reads as just now.
+func agoLine(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+func remoteLine(s Snapshot) string {
+	switch {
+	case !s.RemoteChecked:
+		return "not checked (--check-remote)"
+	case s.RemoteReachable:
+		return "reachable"
+	default:
+		return "unreachable"
+	}
+}
+
 func versionLine(s Snapshot) string {
 	if s.VersionsUnknown {
 		return "unknown (re-run with sudo)"
@@ -213,23 +333,56 @@ func versionLine(s Snapshot) string {
 // daemonJSON is the daemon-owned half of the combined JSON. All primitives,
 // no disguised identifier — safe to marshal directly.
 type daemonJSON struct {
-	Mode               string `json:"mode"`
-	MeshLoaded         int    `json:"mesh_loaded"`
-	MeshTotal          int    `json:"mesh_total"`
-	MeshUnknown        bool   `json:"mesh_unknown"`
-	ProcCount          int    `json:"proc_count"`
-	OtherGenerations   int    `json:"other_generations"`
-	GenerationsUnknown bool   `json:"generations_unknown"`
-	Desired            string `json:"desired"`
-	Good               string `json:"good"`
-	VersionsUnknown    bool   `json:"versions_unknown"`
-	WarmingUp          bool   `json:"warming_up"`
-	Found              bool   `json:"found"`
-	WatchdogChecked    bool   `json:"watchdog_checked"`
-	WatchdogCron       bool   `json:"watchdog_rail"` // rail presence; mechanism name deliberately not exposed
-	WatchdogCopyOK     bool   `json:"watchdog_copy_ok"`
-	Verdict            string `json:"verdict"`
-	Note               string `json:"note"`
+	Mode               string       `json:"mode"`
+	MeshLoaded         int          `json:"mesh_loaded"`
+	MeshTotal          int          `json:"mesh_total"`
+	MeshUnknown        bool         `json:"mesh_unknown"`
+	ProcCount          int          `json:"proc_count"`
+	OtherGenerations   int          `json:"other_generations"`
+	GenerationsUnknown bool         `json:"generations_unknown"`
+	Desired            string       `json:"desired"`
+	Good               string       `json:"good"`
+	VersionsUnknown    bool         `json:"versions_unknown"`
+	WarmingUp          bool         `json:"warming_up"`
+	Found              bool         `json:"found"`
+	WatchdogChecked    bool         `json:"watchdog_checked"`
+	WatchdogCron       bool         `json:"watchdog_rail"` // rail presence; mechanism name deliberately not exposed
+	WatchdogCopyOK     bool         `json:"watchdog_copy_ok"`
+	Backup             backupJSON   `json:"backup"`
+	StrictLockS        int64        `json:"strict_lock_s"`
+	GateStep           int          `json:"uninstall_gate_step"`
+	GateWaitS          int64        `json:"uninstall_gate_wait_s"`
+	GatePartner        bool         `json:"uninstall_gate_partner"`
+	SelfTest           selfTestJSON `json:"self_test"`
+	Crashes24h         int          `json:"crashes_24h"`
+	Published          bool         `json:"published"`
+	PublishedAgeS      int64        `json:"published_age_s"`
+	Verdict            string       `json:"verdict"`
+	Note               string       `json:"note"`
+}
+
+// backupJSON is the restore-chain section of the machine report. State is
+// "verified" | "corrupt" | "missing", or "" when not checked (non-darwin).
+// RefreshedAgeSeconds is only meaningful when Present.
+type backupJSON struct {
+	Checked             bool   `json:"checked"`
+	Present             bool   `json:"present"`
+	Verified            bool   `json:"verified"`
+	State               string `json:"state"`
+	RefreshedAgeSeconds int64  `json:"refreshed_age_seconds"`
+	RestorePin          string `json:"restore_pin"`
+	BakedFallback       string `json:"baked_fallback"`
+	RemoteChecked       bool   `json:"remote_checked"`
+	RemoteReachable     bool   `json:"remote_reachable"`
+}
+
+// selfTestJSON is the last recorded boot self-test; Failed lists check
+// names (fixed nouns), empty when it passed or none was recorded;
+// Signature is the binary's Apple code-signature summary when read.
+type selfTestJSON struct {
+	Checked   bool     `json:"checked"`
+	Failed    []string `json:"failed"`
+	Signature string   `json:"signature,omitempty"`
 }
 
 // combinedJSON is the structural composition of the daemon snapshot and the
@@ -262,8 +415,27 @@ func RenderJSON(s Snapshot, res Result, pd PlatformDetail, out io.Writer) {
 			WatchdogChecked:    s.WatchdogChecked,
 			WatchdogCron:       s.WatchdogCron,
 			WatchdogCopyOK:     s.WatchdogCopyOK,
-			Verdict:            string(res.Verdict),
-			Note:               res.Note,
+			Backup: backupJSON{
+				Checked:             s.BackupChecked,
+				Present:             s.BackupPresent,
+				Verified:            s.BackupVerified,
+				State:               backupState(s),
+				RefreshedAgeSeconds: int64(s.BackupAge / time.Second),
+				RestorePin:          s.BackupPin,
+				BakedFallback:       s.BakedFallback,
+				RemoteChecked:       s.RemoteChecked,
+				RemoteReachable:     s.RemoteReachable,
+			},
+			StrictLockS:   int64(s.StrictLockLeft / time.Second),
+			GateStep:      s.GateStep,
+			GateWaitS:     int64(s.GateWait / time.Second),
+			GatePartner:   s.GatePartner,
+			SelfTest:      selfTestJSON{Checked: s.SelfTestChecked, Failed: nonNil(s.SelfTestFailed), Signature: s.SelfTestSignature},
+			Crashes24h:    s.Crashes24h,
+			Published:     s.Published,
+			PublishedAgeS: int64(s.PublishedAge / time.Second),
+			Verdict:       string(res.Verdict),
+			Note:          res.Note,
 		},
 		Overall: string(res.Verdict),
 	}
@@ -284,6 +456,14 @@ func RenderJSON(s Snapshot, res Result, pd PlatformDetail, out io.Writer) {
 	fmt.Fprintln(out)
 }
 
+// nonNil keeps an empty list a JSON [] rather than null.
+func nonNil(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
+
 // isValidJSON guards the embed: a non-empty, well-formed platform report. If
 // the platform somehow emitted garbage, we embed null rather than splice
 // invalid bytes into the composed document.
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 )
 
 // forbidden are substrings that would indicate a disguised identifier leaked
@@ -425,3 +426,247 @@ func TestRender_UnknownLinesHonest(t *testing.T) {
 	}
 	assertNoLeak(t, "unknown text", out)
 }
+
+// TestRender_RestoreChain verifies the restore-chain section: omitted when not
+// checked (non-darwin), and otherwise naming each rung's state — including the
+// problem states, which (unlike the watchdog line) are shown on purpose.
+func TestRender_RestoreChain(t *testing.T) {
+	base := realisticSnapshot()
+	base.BackupChecked = true
+	base.BakedFallback = "v0.16.3"
+	cases := []struct {
+		name   string
+		mutate func(Snapshot) Snapshot
+		want   []string
+	}{
+		{
+			name:   "unchecked → section omitted",
+			mutate: func(s Snapshot) Snapshot { s.BackupChecked = false; return s },
+		},
+		{
+			name:   "missing copy, remote not probed",
+			mutate: func(s Snapshot) Snapshot { return s },
+			want:   []string{"restore chain", "missing", "baked fallback", "v0.16.3", "not checked (--check-remote)"},
+		},
+		{
+			name: "corrupt copy, remote unreachable",
+			mutate: func(s Snapshot) Snapshot {
+				s.BackupPresent, s.BackupAge = true, 3*time.Hour
+				s.RemoteChecked = true
+				return s
+			},
+			want: []string{"corrupt (changed 3h ago)", "unreachable"},
+		},
+		{
+			name: "verified copy with pin, remote reachable",
+			mutate: func(s Snapshot) Snapshot {
+				s.BackupPresent, s.BackupVerified, s.BackupAge = true, true, 30*time.Second
+				s.BackupPin = "v1.1.0"
+				s.RemoteChecked, s.RemoteReachable = true, true
+				return s
+			},
+			want: []string{"verified (changed just now)", "restore pin", "v1.1.0", "reachable"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := c.mutate(base)
+			var txt bytes.Buffer
+			RenderText(s, Assess(s), PlatformDetail{Available: false}, &txt, false)
+			out := txt.String()
+			if has := strings.Contains(out, "restore chain"); has != (len(c.want) > 0) {
+				t.Fatalf("restore chain section present=%v, want %v:\n%s", has, len(c.want) > 0, out)
+			}
+			for _, w := range c.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("missing %q:\n%s", w, out)
+				}
+			}
+			assertNoLeak(t, "restore chain text", out)
+		})
+	}
+}
+
+// TestRenderJSON_BackupFields verifies the machine report carries the restore
+// chain as a nested object with a bucketed state string.
+func TestRenderJSON_BackupFields(t *testing.T) {
+	s := realisticSnapshot()
+	s.BackupChecked, s.BackupPresent = true, true
+	s.BackupAge = 90 * time.Second
+	s.BackupPin = "v1.1.0"
+
+	var buf bytes.Buffer
+	RenderJSON(s, Assess(s), PlatformDetail{Available: false}, &buf)
+
+	var top struct {
+		Daemon struct {
+			Backup backupJSON `json:"backup"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &top); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	b := top.Daemon.Backup
+	if b.State != "corrupt" || b.RefreshedAgeSeconds != 90 || b.RestorePin != "v1.1.0" || b.RemoteChecked {
+		t.Errorf("backup = %+v", b)
+	}
+	assertNoLeak(t, "backup json", buf.String())
+}
+
+func TestAgoLine(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		-time.Minute:     "just now",
+		59 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		3 * time.Hour:    "3h ago",
+		72 * time.Hour:   "3d ago",
+	} {
+		if got := agoLine(d); got != want {
+			t.Errorf("agoLine(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// TestRender_StrictLockLine: the lock line appears only while a lock holds,
+// and the JSON carries the seconds left either way.
+func TestRender_StrictLockLine(t *testing.T) {
+	s := realisticSnapshot()
+	var txt bytes.Buffer
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if strings.Contains(txt.String(), "strict lock") {
+		t.Fatalf("lock line without a lock:\n%s", txt.String())
+	}
+	s.StrictLockLeft = 3*24*time.Hour + 5*time.Hour
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "on, 3d 5h left") {
+		t.Fatalf("lock line missing:\n%s", txt.String())
+	}
+	if Assess(s) != Assess(realisticSnapshot()) {
+		t.Fatal("a strict lock changed the verdict")
+	}
+	var js bytes.Buffer
+	RenderJSON(s, Assess(s), PlatformDetail{}, &js)
+	var c struct {
+		Daemon struct {
+			StrictLockS int64 `json:"strict_lock_s"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(js.Bytes(), &c); err != nil || c.Daemon.StrictLockS != int64((77*time.Hour)/time.Second) {
+		t.Fatalf("strict_lock_s = %d, err %v", c.Daemon.StrictLockS, err)
+	}
+}
+
+// TestRender_GateLine: the uninstall gate line appears once a step is done,
+// with the wait before the next, and never moves the verdict.
+func TestRender_GateLine(t *testing.T) {
+	s := realisticSnapshot()
+	var txt bytes.Buffer
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if strings.Contains(txt.String(), "uninstall gate") {
+		t.Fatalf("gate line with no uninstall under way:\n%s", txt.String())
+	}
+	s.GateStep, s.GateWait = 1, 90*time.Minute
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "step 1 of 3 done, next in 1h 30m") {
+		t.Fatalf("gate line missing:\n%s", txt.String())
+	}
+	if Assess(s) != Assess(realisticSnapshot()) {
+		t.Fatal("gate progress changed the verdict")
+	}
+	s.GateStep, s.GateWait, s.GatePartner = 3, 0, true
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "step 3 of 3 done, waiting on the partner's code") {
+		t.Fatalf("partner wait missing:\n%s", txt.String())
+	}
+	s.GateStep, s.GateWait, s.GatePartner = 1, 90*time.Minute, false
+	var js bytes.Buffer
+	RenderJSON(s, Assess(s), PlatformDetail{}, &js)
+	var c struct {
+		Daemon struct {
+			Step  int   `json:"uninstall_gate_step"`
+			WaitS int64 `json:"uninstall_gate_wait_s"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(js.Bytes(), &c); err != nil || c.Daemon.Step != 1 || c.Daemon.WaitS != 5400 {
+		t.Fatalf("gate json = %+v, err %v", c.Daemon, err)
+	}
+}
+
+// TestRender_SelfTestLine: the recorded boot self-test is shown only when one
+// was found, names the failed checks, and never moves the verdict.
+func TestRender_SelfTestLine(t *testing.T) {
+	s := realisticSnapshot()
+	var txt bytes.Buffer
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if strings.Contains(txt.String(), "self-test") {
+		t.Fatalf("self-test line without a recorded run:\n%s", txt.String())
+	}
+	s.SelfTestChecked = true
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "passed") || strings.Contains(txt.String(), "code signature") {
+		t.Fatalf("passing self-test line missing:\n%s", txt.String())
+	}
+	s.SelfTestFailed = []string{"offline backup", "log sink"}
+	s.SelfTestSignature = "team ABCDE12345, notarized"
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "FAILED: offline backup, log sink") || !strings.Contains(txt.String(), "team ABCDE12345, notarized") {
+		t.Fatalf("failed self-test line missing:\n%s", txt.String())
+	}
+	if Assess(s) != Assess(realisticSnapshot()) {
+		t.Fatal("a failed self-test changed the verdict")
+	}
+	var js bytes.Buffer
+	RenderJSON(s, Assess(s), PlatformDetail{}, &js)
+	var c struct {
+		Daemon struct {
+			SelfTest struct {
+				Checked   bool     `json:"checked"`
+				Failed    []string `json:"failed"`
+				Signature string   `json:"signature"`
+			} `json:"self_test"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(js.Bytes(), &c); err != nil || !c.Daemon.SelfTest.Checked || len(c.Daemon.SelfTest.Failed) != 2 || c.Daemon.SelfTest.Signature == "" {
+		t.Fatalf("self_test = %+v, err %v", c.Daemon.SelfTest, err)
+	}
+}
+
+// TestRender_CrashLine: recent worker crashes are shown when there are any,
+// and only a crash loop moves the verdict.
+func TestRender_CrashLine(t *testing.T) {
+	s := Snapshot{Found: true, MeshLoaded: 3, MeshTotal: 3, ProcCount: 1, Desired: "v1", Good: "v1"}
+	healthy := Assess(s)
+	var txt bytes.Buffer
+	RenderText(s, healthy, PlatformDetail{}, &txt, false)
+	if strings.Contains(txt.String(), "crashes") {
+		t.Fatalf("crash line with none recorded:\n%s", txt.String())
+	}
+	s.Crashes24h, s.LastCrashAge = 1, 2*time.Hour
+	txt.Reset()
+	RenderText(s, Assess(s), PlatformDetail{}, &txt, false)
+	if !strings.Contains(txt.String(), "1 in the last 24h, last "+agoLine(2*time.Hour)) {
+		t.Fatalf("crash line missing:\n%s", txt.String())
+	}
+	if Assess(s) != healthy {
+		t.Fatal("a single crash changed the verdict")
+	}
+	s.Crashes24h = RepeatedCrashes
+	if res := Assess(s); res.Verdict != Degraded || !strings.Contains(res.Note, "crashed 3 times") {
+		t.Fatalf("crash loop = %+v, want DEGRADED", res)
+	}
+	var js bytes.Buffer
+	RenderJSON(s, Assess(s), PlatformDetail{}, &js)
+	var c struct {
+		Daemon struct {
+			Crashes24h int `json:"crashes_24h"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(js.Bytes(), &c); err != nil || c.Daemon.Crashes24h != RepeatedCrashes {
+		t.Fatalf("crashes_24h = %d, err %v", c.Daemon.Crashes24h, err)
+	}
+}
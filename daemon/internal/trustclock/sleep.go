@@ -0,0 +1,25 @@
+package trustclock
+
+import "time"
+
+// SleepMeter turns the kernel's count of time asleep since boot into the
+// sleep between credit passes. Both clocks behind the count are immune to
+// setting the system clock, so the sleep it reports is observed, not
+// claimed, and Credit counts it in full.
+type SleepMeter struct {
+	last time.Duration
+	ok   bool
+}
+
+// Since returns how long the machine slept since the previous call. It is
+// zero on the first call, after a reboot resets the count, and where the
+// kernel keeps none; that time can only come out of the allowance.
+func (m *SleepMeter) Since() time.Duration {
+	cur, ok := asleep()
+	prev, had := m.last, m.ok
+	m.last, m.ok = cur, ok
+	if !ok || !had || cur < prev {
+		return 0
+	}
+	return cur - prev
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package trustclock
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// asleep is the time slept since boot: CLOCK_MONOTONIC_RAW keeps counting
+// while the Mac sleeps, CLOCK_UPTIME_RAW does not.
+func asleep() (time.Duration, bool) {
+	var all, awake unix.Timespec
+	if unix.ClockGettime(unix.CLOCK_MONOTONIC_RAW, &all) != nil || unix.ClockGettime(unix.CLOCK_UPTIME_RAW, &awake) != nil {
+		return 0, false
+	}
+	return max(time.Duration(all.Nano()-awake.Nano()), 0), true
+}
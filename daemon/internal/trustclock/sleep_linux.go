@@ -0,0 +1,19 @@
+//go:build linux
+
+package trustclock
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// asleep is the time suspended since boot: CLOCK_BOOTTIME counts suspend,
+// CLOCK_MONOTONIC does not.
+func asleep() (time.Duration, bool) {
+	var all, awake unix.Timespec
+	if unix.ClockGettime(unix.CLOCK_BOOTTIME, &all) != nil || unix.ClockGettime(unix.CLOCK_MONOTONIC, &awake) != nil {
+		return 0, false
+	}
+	return max(time.Duration(all.Nano()-awake.Nano()), 0), true
+}
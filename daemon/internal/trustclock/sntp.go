@@ -0,0 +1,62 @@
+package trustclock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+// ntpEpochOffset is the seconds from the NTP epoch (1900) to the Unix one.
+const ntpEpochOffset = 2208988800
+
+var errBadReply = errors.New("trustclock: unusable time server reply")
+
+// Query asks the SNTP server at addr ("host" or "host:port") for the time,
+// corrected by half the round trip. A reply that is not a synchronized
+// server's (a kiss-o'-death, stratum 0, an unsynchronized leap indicator) or
+// that does not echo the request is refused.
+func Query(ctx context.Context, addr string) (time.Time, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "123")
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	req := make([]byte, 48)
+	req[0] = 0x23 // LI 0, version 4, mode 3 (client)
+	sent := time.Now()
+	binary.BigEndian.PutUint64(req[40:], ntpStamp(sent))
+	if _, err := conn.Write(req); err != nil {
+		return time.Time{}, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	rtt := time.Since(sent)
+	if n < 48 || resp[0]&0x07 != 4 || resp[0]>>6 == 3 || resp[1] == 0 || resp[1] > 15 ||
+		binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]) {
+		return time.Time{}, errBadReply
+	}
+	return fromNTP(binary.BigEndian.Uint64(resp[40:])).Add(rtt / 2), nil
+}
+
+func ntpStamp(t time.Time) uint64 {
+	s := uint64(t.Unix() + ntpEpochOffset)
+	f := uint64(t.Nanosecond()) << 32 / 1e9
+	return s<<32 | f
+}
+
+func fromNTP(v uint64) time.Time {
+	s, f := int64(v>>32)-ntpEpochOffset, int64((v&0xffffffff)*1e9>>32)
+	return time.Unix(s, f)
+}
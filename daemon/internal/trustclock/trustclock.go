@@ -0,0 +1,104 @@
+// Package trustclock is the daemon's tamper-resistant clock. Every
+// time-gated feature — the strict lock, the uninstall gate's cool-offs, and
+// through the platform the focus sessions, breaks and pauses — asks it for
+// the time instead of the system clock, so setting the clock forward does
+// not fast-forward through a wait.
+//
+// The trusted time advances by the monotonic time the daemon sees pass
+// and the sleep the kernel counted (Credit), plus wall-clock gaps neither
+// saw (shutdown, a kernel without a sleep count) out of a bounded allowance
+// that running time earns back one for one. A forward jump beyond the
+// allowance is ignored, and a backward one never moves the trusted time
+// back. With a network time server configured (Sync), the trusted time is
+// reset to the server's, which also recovers the gap after a long shutdown
+// the allowance could not cover. Without one the cost is honest: a machine
+// left off for more than the allowance comes back with its waits behind.
+package trustclock
+
+import "time"
+
+// The unobserved-time allowance: a fresh clock may credit a day, running
+// time earns itself back, and the balance never exceeds three days, so a
+// long-lived install cannot bank weeks of jump. Sleep is observed and needs
+// none of it; at one for one, pushing the clock forward every day at most
+// doubles the pace of a wait.
+const (
+	InitialAllow = 24 * time.Hour
+	MaxAllow     = 72 * time.Hour
+	allowRatio   = 1
+)
+
+// Fresh is how far past the last credit Now trusts the system clock
+// outright: the daemon credits every minute, so a few minutes since the
+// last credit is running time, not a gap.
+const Fresh = 5 * time.Minute
+
+// Clock is the persisted clock. The zero Clock has never been credited and
+// trusts the system clock.
+type Clock struct {
+	// At is the trusted time as of Seen, the latest system wall time
+	// credited; the system clock never moves Seen backwards.
+	At   time.Time `json:"at"`
+	Seen time.Time `json:"seen"`
+	// Allow is the unobserved time, in seconds, still creditable.
+	Allow int64 `json:"allow_s"`
+	// Synced is the trusted time of the last network sync; zero if never.
+	Synced time.Time `json:"synced,omitempty"`
+}
+
+func secs(d time.Duration) int64 { return int64(d / time.Second) }
+
+// Now is the trusted time when the system clock reads sys: At plus the
+// wall time since Seen, of which only Fresh plus the allowance counts.
+func (c Clock) Now(sys time.Time) time.Time {
+	if c.Seen.IsZero() {
+		return sys
+	}
+	el := sys.Sub(c.Seen)
+	if el <= 0 {
+		return c.At
+	}
+	return c.At.Add(min(el, Fresh+time.Duration(c.Allow)*time.Second))
+}
+
+// Skew is how far the system clock reading sys is ahead of the trusted
+// time; negative when it is behind.
+func (c Clock) Skew(sys time.Time) time.Duration { return sys.Sub(c.Now(sys)) }
+
+// Credit records that ran of monotonic time passed awake, and slept asleep
+// (SleepMeter), up to the system wall time sys. Only running time earns
+// allowance. Wall time since Seen beyond both is a gap, credited out of
+// the allowance.
+func (c Clock) Credit(sys time.Time, ran, slept time.Duration) Clock {
+	if c.Seen.IsZero() {
+		return Clock{At: sys, Seen: sys, Allow: secs(InitialAllow)}
+	}
+	ran, slept = max(ran, 0), max(slept, 0)
+	c.At = c.At.Add(ran + slept)
+	c.Allow = min(c.Allow+allowRatio*secs(ran), secs(MaxAllow))
+	if wall := sys.Sub(c.Seen); wall > ran+slept {
+		g := min(secs(wall-ran-slept), c.Allow)
+		c.At = c.At.Add(time.Duration(g) * time.Second)
+		c.Allow -= g
+	}
+	if sys.After(c.Seen) {
+		c.Seen = sys
+	}
+	return c
+}
+
+// Sync resets the trusted time to net, a network time server's reading
+// taken when the system clock read sys.
+func (c Clock) Sync(sys, net time.Time) Clock {
+	if c.Seen.IsZero() {
+		c.Allow = secs(InitialAllow)
+	}
+	c.At, c.Seen, c.Synced = net, sys, net
+	return c
+}
+
+// SyncDue reports whether a network sync is owed at sys: every interval of
+// trusted time.
+func (c Clock) SyncDue(sys time.Time, interval time.Duration) bool {
+	return c.Synced.IsZero() || c.Now(sys).Sub(c.Synced) >= interval
+}
@@ -0,0 +1,152 @@
+package trustclock
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestForwardJumpEarnsOnlyTheAllowance(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0)
+	if !c.Now(t0).Equal(t0) {
+		t.Fatalf("a fresh clock must start at the system time, got %v", c.Now(t0))
+	}
+	// A minute of running, then the clock jumps ten days ahead.
+	c = c.Credit(t0.Add(time.Minute), time.Minute, 0)
+	jump := t0.Add(10 * 24 * time.Hour)
+	c = c.Credit(jump, time.Minute, 0)
+	got := c.Now(jump).Sub(t0)
+	if want := 2*time.Minute + InitialAllow + allowRatio*2*time.Minute; got > want {
+		t.Fatalf("jump credited %s, want at most %s", got, want)
+	}
+	if c.Skew(jump) < 8*24*time.Hour {
+		t.Fatalf("skew %s, want the jump to show", c.Skew(jump))
+	}
+	// The allowance is spent: a second jump earns nothing beyond running time.
+	before := c.Now(jump)
+	c = c.Credit(jump.Add(48*time.Hour), time.Minute, 0)
+	if d := c.Now(jump.Add(48 * time.Hour)).Sub(before); d > 5*time.Minute {
+		t.Fatalf("second jump credited %s", d)
+	}
+}
+
+func TestBackwardJumpNeverRewinds(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0).Credit(t0.Add(time.Hour), time.Hour, 0)
+	back := t0.Add(-24 * time.Hour)
+	c = c.Credit(back, time.Minute, 0)
+	if got := c.Now(back); got.Before(t0.Add(time.Hour + time.Minute)) {
+		t.Fatalf("trusted time went back to %v", got)
+	}
+}
+
+func TestSleepIsCreditedAndAllowanceRefills(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0)
+	// Eight hours up, then a sixteen-hour sleep, for a week.
+	now := t0
+	for range 7 {
+		now = now.Add(8 * time.Hour)
+		c = c.Credit(now, 8*time.Hour, 0)
+		now = now.Add(16 * time.Hour)
+		c = c.Credit(now, 0, 16*time.Hour)
+	}
+	if d := c.Skew(now); d != 0 {
+		t.Fatalf("an honest laptop drifted %s", d)
+	}
+	if c.Allow > int64(MaxAllow/time.Second) {
+		t.Fatalf("allowance %ds above the cap", c.Allow)
+	}
+}
+
+func TestDailyJumpsAtMostDoubleThePace(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0)
+	// Each real day: a day of running, then the clock pushed ten days on.
+	sys := t0
+	const days = 10
+	for range days {
+		for range 24 {
+			sys = sys.Add(time.Hour)
+			c = c.Credit(sys, time.Hour, 0)
+		}
+		sys = sys.Add(10 * 24 * time.Hour)
+		c = c.Credit(sys, time.Minute, 0)
+	}
+	if got, want := c.Now(sys).Sub(t0), 2*days*24*time.Hour+InitialAllow+time.Hour; got > want {
+		t.Fatalf("%d real days credited %s, want at most %s", days, got, want)
+	}
+}
+
+func TestSleepMeterStartsAtZero(t *testing.T) {
+	var m SleepMeter
+	if d := m.Since(); d != 0 {
+		t.Fatalf("first reading credited %s of sleep", d)
+	}
+	if d := m.Since(); d < 0 || d > time.Second {
+		t.Fatalf("back-to-back readings credited %s of sleep", d)
+	}
+}
+
+func TestNowBoundsAnUncreditedReader(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0)
+	if got := c.Now(t0.Add(time.Minute)); !got.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("a minute since the last credit is running time, got %v", got)
+	}
+	far := t0.Add(30 * 24 * time.Hour)
+	if got := c.Now(far).Sub(t0); got > Fresh+InitialAllow {
+		t.Fatalf("reader credited %s", got)
+	}
+	if (Clock{}).Now(far) != far {
+		t.Fatal("the zero clock trusts the system clock")
+	}
+}
+
+func TestSync(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := Clock{}.Credit(t0, 0, 0)
+	net := t0.Add(14 * 24 * time.Hour)
+	sys := t0.Add(14 * 24 * time.Hour)
+	c = c.Credit(sys, 0, 0).Sync(sys, net)
+	if c.Skew(sys) != 0 || c.SyncDue(sys, time.Hour) {
+		t.Fatalf("sync did not take: skew %s", c.Skew(sys))
+	}
+	c = c.Credit(sys.Add(2*time.Hour), 2*time.Hour, 0)
+	if !c.SyncDue(sys.Add(2*time.Hour), time.Hour) {
+		t.Fatal("sync not due after the interval")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no udp:", err)
+	}
+	defer pc.Close()
+	want := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	go func() {
+		buf := make([]byte, 48)
+		n, from, err := pc.ReadFrom(buf)
+		if err != nil || n != 48 {
+			return
+		}
+		resp := make([]byte, 48)
+		resp[0], resp[1] = 0x24, 2 // version 4, server; stratum 2
+		copy(resp[24:32], buf[40:48])
+		binary.BigEndian.PutUint64(resp[40:], ntpStamp(want))
+		_, _ = pc.WriteTo(resp, from)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	got, err := Query(ctx, pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := got.Sub(want); d < 0 || d > time.Second {
+		t.Fatalf("Query = %v, want %v", got, want)
+	}
+}
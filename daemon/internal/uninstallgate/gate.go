@@ -61,14 +61,20 @@ func Passage(step int) string {
 }
 
 // State is the persisted progress. Step is the number of steps already
-// completed (0..3). T1/T2 are when steps 1/2 were completed; the waits
-// are measured from them. LastSeen is the wall clock at the last write,
-// used purely to detect a backwards clock.
+// completed (0..3). T1/T2/T3 are when steps 1/2/3 were completed — the
+// record of each accepted passage `daemon status` shows; the waits are
+// measured from T1 and T2. LastSeen is the wall clock at the last write,
+// used purely to detect a backwards clock. Code and Approved are the
+// partner approval (partner.go): the hash of the code awaiting entry, and
+// when a good one was entered.
 type State struct {
 	Step     int       `json:"step"`
 	T1       time.Time `json:"t1,omitempty"`
 	T2       time.Time `json:"t2,omitempty"`
+	T3       time.Time `json:"t3,omitempty"`
 	LastSeen time.Time `json:"last_seen"`
+	Code     string    `json:"code,omitempty"`
+	Approved time.Time `json:"approved,omitempty"`
 }
 
 // Kind is what the caller should do next.
@@ -136,7 +142,7 @@ func Advance(s State, now time.Time) State {
 	case 2:
 		s.Step, s.T2 = 2, now
 	case 3:
-		s.Step = 3
+		s.Step, s.T3 = 3, now
 	}
 	s.LastSeen = now
 	return s
@@ -121,7 +121,7 @@ func TestFullHappyPathWithFakeClock(t *testing.T) {
 		t.Fatalf("want transcribe 3, got %+v", o)
 	}
 	s = Advance(s, now)
-	if s.Step != TotalSteps {
+	if s.Step != TotalSteps || !s.T3.Equal(now) {
 		t.Fatalf("after advance 3: %+v", s)
 	}
 	if o := Evaluate(s, now); o.Kind != Proceed {
@@ -0,0 +1,80 @@
+package uninstallgate
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Partner approval: with an accountability partner configured, finishing
+// the third passage is not enough. A one-time code goes to the partner, and
+// uninstall proceeds only once the user types it back. Only the code's hash
+// is kept in the sealed state. The hash is fast, so it protects the code
+// only for as long as the code lives: it is spent by the next attempt,
+// right or wrong, and a fresh one goes to the partner.
+
+// codeAlphabet is Crockford's base32: no I, L, O or U to misread.
+const codeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// codeLen is the code's length in symbols, shown as two groups of five.
+const codeLen = 10
+
+// NewCode returns a fresh one-time code, formatted for reading aloud, and
+// the hash to keep in State.Code.
+func NewCode() (code, hash string, err error) {
+	code, err = randomCode(codeLen)
+	if err != nil {
+		return "", "", err
+	}
+	return code, codeHash(code), nil
+}
+
+// randomCode returns n random symbols in dash-separated groups of five.
+func randomCode(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i := range b {
+		if i > 0 && i%5 == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(codeAlphabet[b[i]%byte(len(codeAlphabet))])
+	}
+	return sb.String(), nil
+}
+
+// normalizeCode reads a code as typed: case, spaces and dashes are ignored, and
+// the letters Crockford reads as digits are folded into them.
+func normalizeCode(typed string) string {
+	r := strings.NewReplacer("-", "", " ", "", "O", "0", "I", "1", "L", "1")
+	return r.Replace(strings.ToUpper(strings.TrimSpace(typed)))
+}
+
+// codeHash hashes a code as typed.
+func codeHash(typed string) string {
+	sum := sha256.Sum256([]byte(normalizeCode(typed)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CodeMatches reports whether typed is the code hash was made from.
+func CodeMatches(hash, typed string) bool {
+	return hash != "" && subtle.ConstantTimeCompare([]byte(codeHash(typed)), []byte(hash)) == 1
+}
+
+// Approve checks typed against the pending code. A match records the
+// approval; either way the code is spent, so a wrong guess costs a fresh
+// code sent to the partner rather than another free try.
+func Approve(s State, typed string, now time.Time) (State, bool) {
+	ok := CodeMatches(s.Code, typed)
+	s.Code = ""
+	if ok {
+		s.Approved = now
+	}
+	s.LastSeen = now
+	return s, ok
+}
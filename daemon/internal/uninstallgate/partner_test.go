@@ -0,0 +1,59 @@
+package uninstallgate
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApprove(t *testing.T) {
+	code, hash, err := NewCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != codeLen+1 || code[codeLen/2] != '-' {
+		t.Fatalf("code %q not shaped XXXXX-XXXXX", code)
+	}
+	if strings.Contains(hash, strings.ReplaceAll(code, "-", "")) {
+		t.Fatal("the code must not be recoverable from what is stored")
+	}
+	now := time.Now()
+	s := State{Step: TotalSteps, Code: hash}
+
+	if got, ok := Approve(s, "0000000000", now); ok || got.Code != "" || !got.Approved.IsZero() {
+		t.Fatalf("a wrong code must fail and spend the code: %+v", got)
+	}
+	typed := " " + strings.ToLower(strings.ReplaceAll(code, "-", " ")) + "\n"
+	got, ok := Approve(s, typed, now)
+	if !ok || !got.Approved.Equal(now) || got.Code != "" {
+		t.Fatalf("a good code typed loosely must approve: %+v", got)
+	}
+	if _, ok := Approve(got, code, now); ok {
+		t.Fatal("a spent code must not approve again")
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	if _, _, err := NewRecoveryCode(""); err == nil {
+		t.Fatal("a recovery code needs an install salt")
+	}
+	code, hash, err := NewRecoveryCode("salt-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != recoveryCodeLen+3 || strings.Count(code, "-") != 3 {
+		t.Fatalf("code %q not shaped XXXXX-XXXXX-XXXXX-XXXXX", code)
+	}
+	if hash == codeHash(code) {
+		t.Fatal("the recovery code must not be kept as a bare hash")
+	}
+	if !RecoveryCodeMatches(hash, "salt-a", strings.ToLower(code)) {
+		t.Fatal("the code typed loosely must match")
+	}
+	if RecoveryCodeMatches(hash, "salt-b", code) {
+		t.Fatal("the hash must depend on the install salt")
+	}
+	if RecoveryCodeMatches(hash, "salt-a", "00000-00000-00000-00000") {
+		t.Fatal("a wrong code matched")
+	}
+}
@@ -0,0 +1,62 @@
+package uninstallgate
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Emergency recovery code: unlike a partner code it is not spent by a wrong
+// entry and it lives as long as the install, so its hash must hold up on
+// its own to anyone who reads the store. It is 100 bits long, and kept only
+// as an scrypt hash salted with the install salt it was issued under.
+
+// recoveryCodeLen is the recovery code's length in symbols, shown as four
+// groups of five.
+const recoveryCodeLen = 20
+
+// scrypt cost: about 32 MiB and a tenth of a second per try on a laptop.
+const (
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	scryptLen = 32
+)
+
+// NewRecoveryCode returns a fresh recovery code, formatted for writing
+// down, and its hash under salt.
+func NewRecoveryCode(salt string) (code, hash string, err error) {
+	if salt == "" {
+		return "", "", errors.New("uninstallgate: no install salt")
+	}
+	code, err = randomCode(recoveryCodeLen)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err = recoveryHash(salt, code)
+	if err != nil {
+		return "", "", err
+	}
+	return code, hash, nil
+}
+
+// RecoveryCodeMatches reports whether typed is the recovery code hash was
+// made from under salt.
+func RecoveryCodeMatches(hash, salt, typed string) bool {
+	if hash == "" || salt == "" {
+		return false
+	}
+	got, err := recoveryHash(salt, typed)
+	return err == nil && subtle.ConstantTimeCompare([]byte(got), []byte(hash)) == 1
+}
+
+// recoveryHash derives the stored form of a recovery code as typed.
+func recoveryHash(salt, typed string) (string, error) {
+	k, err := scrypt.Key([]byte(normalizeCode(typed)), []byte("focusd recovery code\x00"+salt), scryptN, scryptR, scryptP, scryptLen)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k), nil
+}
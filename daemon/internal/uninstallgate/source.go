@@ -0,0 +1,57 @@
+package uninstallgate
+
+import (
+	"math/rand/v2"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on a custom passage set (`daemon config set uninstall.passages`).
+// The floor keeps a personal passage about as long as the built-in ones, so
+// swapping the text in never shortens the ritual; the ceiling bounds the
+// edit-distance comparison Accept runs over it.
+const (
+	MinPassageRunes = 1000
+	MaxPassageRunes = 4000
+	MaxSourceBytes  = 256 << 10
+)
+
+// separator is the line that divides one passage from the next in a set.
+const separator = "---"
+
+// ParsePassages splits a passage set: passages separated by a line holding
+// only "---", each trimmed. A passage outside MinPassageRunes..
+// MaxPassageRunes is dropped rather than trusted, so a set of short notes
+// yields nothing and the caller falls back to the built-ins.
+func ParsePassages(text string) []string {
+	var (
+		set []string
+		cur []string
+	)
+	flush := func() {
+		p := strings.TrimSpace(strings.Join(cur, "\n"))
+		if n := utf8.RuneCountInString(p); n >= MinPassageRunes && n <= MaxPassageRunes {
+			set = append(set, p)
+		}
+		cur = cur[:0]
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == separator {
+			flush()
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return set
+}
+
+// Pick returns the passage for one attempt at step: a random one from set,
+// or the built-in Passage(step) when set is empty. Each attempt draws
+// afresh, so a custom set cannot be learned off by heart step by step.
+func Pick(set []string, step int) string {
+	if len(set) == 0 {
+		return Passage(step)
+	}
+	return set[rand.IntN(len(set))]
+}
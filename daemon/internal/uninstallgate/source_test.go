@@ -0,0 +1,36 @@
+package uninstallgate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePassages(t *testing.T) {
+	long := strings.Repeat("I chose this for a reason. ", 50)
+	tooLong := strings.Repeat("x", MaxPassageRunes+1)
+	text := long + "\r\n---\r\nshort note\n  ---  \n" + tooLong + "\n---\n\n" + strings.ToUpper(long) + "\n"
+	set := ParsePassages(text)
+	if len(set) != 2 {
+		t.Fatalf("got %d passages, want the 2 in range", len(set))
+	}
+	if set[0] != strings.TrimSpace(long) || set[1] != strings.TrimSpace(strings.ToUpper(long)) {
+		t.Fatal("passages not trimmed or out of order")
+	}
+	if ParsePassages("a\n---\nb") != nil {
+		t.Fatal("a set of short notes must yield nothing")
+	}
+}
+
+func TestPick(t *testing.T) {
+	if Pick(nil, 2) != Passage(2) {
+		t.Fatal("an empty set must use the built-in passage for the step")
+	}
+	set := []string{"a", "b", "c"}
+	seen := map[string]bool{}
+	for range 200 {
+		seen[Pick(set, 1)] = true
+	}
+	if len(seen) != len(set) {
+		t.Fatalf("picks not spread over the set: %v", seen)
+	}
+}
@@ -26,17 +26,18 @@ var hmacSecret = []byte("focusd/uninstallgate/v1 — casual integrity tag, not a
 // purpose: the 3 invocations over ~6h must find it without a scan.
 const stateFile = ".com.apple.diagnostics.ug"
 
-// StatePath is where the gate state lives for an install mode: a single
-// hidden file under that mode's Application Support root (user →
-// ~/Library, system → /Library), so user and system installs never share
-// gate state.
+// StatePath is where the gate state used to live for an install mode: a
+// single hidden file under that mode's Application Support root (user →
+// ~/Library, system → /Library). The state now lives sealed in the
+// install's masked store; a file left by an older install is adopted once
+// and removed.
 func StatePath(m mode.Mode, home string) string {
 	return filepath.Join(mode.SupportRoot(m, home), stateFile)
 }
 
-// envelope is what is actually written: the JSON state plus an HMAC over
+// Sealed is what is actually stored: the JSON state plus an HMAC over
 // that exact JSON.
-type envelope struct {
+type Sealed struct {
 	State json.RawMessage `json:"state"`
 	MAC   string          `json:"mac"`
 }
@@ -47,20 +48,21 @@ func mac(payload []byte) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// Load reads the gate state. ANY failure — missing file, unreadable,
-// corrupt JSON, HMAC mismatch (hand-edited), or a clock that moved
-// backwards since the last write — returns the zero State, i.e. "start
-// over from step 1". Tampering therefore only costs the user their own
-// progress; it never advances them and never hard-blocks.
-func Load(path string, now time.Time) State {
-	raw, err := os.ReadFile(path)
+// Seal signs s for storage.
+func Seal(s State) (Sealed, error) {
+	payload, err := json.Marshal(s)
 	if err != nil {
-		return State{}
-	}
-	var env envelope
-	if json.Unmarshal(raw, &env) != nil {
-		return State{}
+		return Sealed{}, err
 	}
+	return Sealed{State: payload, MAC: mac(payload)}, nil
+}
+
+// Unseal verifies and decodes a sealed state. ANY failure — corrupt JSON,
+// HMAC mismatch (hand-edited), or a clock that moved backwards since the
+// last write — returns the zero State, i.e. "start over from step 1".
+// Tampering therefore only costs the user their own progress; it never
+// advances them and never hard-blocks.
+func Unseal(env Sealed, now time.Time) State {
 	if !hmac.Equal([]byte(env.MAC), []byte(mac(env.State))) {
 		return State{} // hand-edited / corrupt → reset
 	}
@@ -74,17 +76,31 @@ func Load(path string, now time.Time) State {
 	return s
 }
 
+// Load reads a gate state file; a missing or unreadable one, and anything
+// Unseal refuses, is the zero State.
+func Load(path string, now time.Time) State {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return State{}
+	}
+	var env Sealed
+	if json.Unmarshal(raw, &env) != nil {
+		return State{}
+	}
+	return Unseal(env, now)
+}
+
 // Save writes the state HMAC-signed, 0600, creating the parent dir. The
 // caller is expected to have set LastSeen (Advance does).
 func Save(path string, s State) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
-	payload, err := json.Marshal(s)
+	env, err := Seal(s)
 	if err != nil {
 		return err
 	}
-	out, err := json.Marshal(envelope{State: payload, MAC: mac(payload)})
+	out, err := json.Marshal(env)
 	if err != nil {
 		return err
 	}
@@ -54,7 +54,7 @@ func TestLoadTamperedStateResets(t *testing.T) {
 	}
 	// Hand-edit the state payload but keep the (now stale) MAC.
 	raw, _ := os.ReadFile(p)
-	var env envelope
+	var env Sealed
 	json.Unmarshal(raw, &env)
 	env.State = json.RawMessage(`{"step":3,"last_seen":"2026-05-18T09:00:00Z"}`)
 	bad, _ := json.Marshal(env)
@@ -96,7 +96,7 @@ func TestLoadValidMACButBrokenStateResets(t *testing.T) {
 	// valid State (e.g. a future/garbled schema). Must reset, not crash.
 	p := filepath.Join(t.TempDir(), stateFile)
 	payload := []byte(`["not", "an", "object"]`)
-	env := envelope{State: payload, MAC: mac(payload)}
+	env := Sealed{State: payload, MAC: mac(payload)}
 	out, _ := json.Marshal(env)
 	if err := os.WriteFile(p, out, 0o600); err != nil {
 		t.Fatal(err)
@@ -123,7 +123,7 @@ func TestSaveThenTamperDetectionIsStable(t *testing.T) {
 	p := filepath.Join(t.TempDir(), stateFile)
 	Save(p, State{Step: 1, T1: t0, LastSeen: t0})
 	raw, _ := os.ReadFile(p)
-	var env envelope
+	var env Sealed
 	json.Unmarshal(raw, &env)
 	if len(env.MAC) == 0 {
 		t.Fatal("expected a MAC")
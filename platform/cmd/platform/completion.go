@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// commands are the verbs `platform completion` offers, in usage order. Keep
+// in step with the switch in main.
+var commands = []string{
+	"version", "validate", "status", "run", "metrics", "report", "stats", "events", "watch",
+	"scan", "session", "break", "policies", "pause", "discover", "block", "completion", "ship",
+	"log-level",
+}
+
+// runCompletion is `platform completion bash|zsh|fish`: print a completion
+// script for the shell. Verbs complete statically; policy ids (`scan
+// --policy`, `break`, `policies test`) complete against `platform policies
+// --ids` each time, so they follow the enforced policy of the binary on PATH
+// rather than the one the script was generated with.
+//
+//	source <(platform completion bash)
+//	platform completion zsh > "${fpath[1]}/_platform"
+//	platform completion fish > ~/.config/fish/completions/platform.fish
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: platform completion bash|zsh|fish")
+		return 2
+	}
+	script, ok := completionScript(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "completion: unsupported shell %q (bash, zsh or fish)\n", args[0])
+		return 2
+	}
+	fmt.Print(script)
+	return 0
+}
+
+func completionScript(shell string) (string, bool) {
+	verbs := strings.Join(commands, " ")
+	switch shell {
+	case "bash":
+		return strings.ReplaceAll(bashCompletion, "@VERBS@", verbs), true
+	case "zsh":
+		// zsh runs the bash function through bashcompinit; one script to keep
+		// right instead of two.
+		return "#compdef platform\nautoload -U +X bashcompinit && bashcompinit\n" +
+			strings.ReplaceAll(bashCompletion, "@VERBS@", verbs), true
+	case "fish":
+		return strings.ReplaceAll(fishCompletion, "@VERBS@", verbs), true
+	}
+	return "", false
+}
+
+const bashCompletion = `# platform bash completion (platform completion bash)
+_platform_ids() {
+	"${COMP_WORDS[0]}" policies --ids 2>/dev/null
+}
+_platform() {
+	local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "@VERBS@" -- "$cur"))
+		return
+	fi
+	if [ "$prev" = "--policy" ]; then
+		COMPREPLY=($(compgen -W "$(_platform_ids)" -- "$cur"))
+		return
+	fi
+	case "${COMP_WORDS[1]}" in
+	break)
+		[ "$COMP_CWORD" -eq 2 ] && COMPREPLY=($(compgen -W "$(_platform_ids)" -- "$cur"))
+		;;
+	policies)
+		if [ "$COMP_CWORD" -eq 2 ]; then
+			COMPREPLY=($(compgen -W "export import test" -- "$cur"))
+		elif [ "$COMP_CWORD" -eq 3 ] && [ "${COMP_WORDS[2]}" = "test" ]; then
+			COMPREPLY=($(compgen -W "$(_platform_ids)" -- "$cur"))
+		fi
+		;;
+	session)
+		[ "$COMP_CWORD" -eq 2 ] && COMPREPLY=($(compgen -W "start" -- "$cur"))
+		;;
+	completion)
+		[ "$COMP_CWORD" -eq 2 ] && COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+		;;
+	esac
+}
+complete -o default -F _platform platform
+`
+
+const fishCompletion = `# platform fish completion (platform completion fish)
+function __platform_ids
+	platform policies --ids 2>/dev/null
+end
+complete -c platform -n __fish_use_subcommand -f -a "@VERBS@"
+complete -c platform -n '__fish_seen_subcommand_from scan' -l policy -x -a '(__platform_ids)'
+complete -c platform -n '__fish_seen_subcommand_from break' -f -a '(__platform_ids)'
+complete -c platform -n '__fish_seen_subcommand_from policies; and not __fish_seen_subcommand_from export import test' -f -a 'export import test'
+complete -c platform -n '__fish_seen_subcommand_from policies; and __fish_seen_subcommand_from test' -f -a '(__platform_ids)'
+complete -c platform -n '__fish_seen_subcommand_from session' -f -a start
+complete -c platform -n '__fish_seen_subcommand_from completion' -f -a 'bash zsh fish'
+`
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestCompletionCoversEveryCommand: the completion verbs are a hand-kept
+// copy of main's switch; a verb added there and not here would silently not
+// complete.
+func TestCompletionCoversEveryCommand(t *testing.T) {
+	src, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range regexp.MustCompile(`(?m)^\tcase "([a-z]+)"`).FindAllStringSubmatch(string(src), -1) {
+		if !strings.Contains(" "+strings.Join(commands, " ")+" ", " "+m[1]+" ") && m[1] != "help" {
+			t.Errorf("command %q is missing from the completion verbs", m[1])
+		}
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, sh := range []string{"bash", "zsh", "fish"} {
+		s, ok := completionScript(sh)
+		if !ok {
+			t.Fatalf("%s: no script", sh)
+		}
+		if strings.Contains(s, "@VERBS@") || !strings.Contains(s, "discover block completion") {
+			t.Errorf("%s: verbs not filled in", sh)
+		}
+		if !strings.Contains(s, "policies --ids") {
+			t.Errorf("%s: policy ids are not completed live", sh)
+		}
+	}
+	if _, ok := completionScript("tcsh"); ok {
+		t.Error("tcsh accepted")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/adminapi"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+)
+
+// defaultLevelWindow is how long `platform log-level LEVEL` lasts without
+// --for: long enough to reproduce something, short enough to forget.
+const defaultLevelWindow = 10 * time.Minute
+
+// runLogLevel moves the running platform's log level for a window, over the
+// admin socket, without a restart that would lose the state being debugged.
+// The platform falls back to its configured level when the window closes.
+//
+//	platform log-level                   — the running level and any window
+//	platform log-level debug [--for 30m] — run at debug for the window
+//	platform log-level reset             — close the window now
+func runLogLevel(args []string) int {
+	var level string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		level, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("log-level", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "daemon-managed workdir; locates the admin socket")
+	window := fs.Duration("for", defaultLevelWindow, "how long the level lasts (at most "+logging.MaxOverride.String()+")")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return 2
+	}
+	show, d := level == "", *window
+	switch {
+	case show:
+	case level == "reset":
+		level, d = "", 0
+	default:
+		if _, ok := logging.ParseLevel(level); !ok {
+			fmt.Fprintln(os.Stderr, "log-level: level must be debug, info, warn, error or reset")
+			return 2
+		}
+		if d < time.Second || d > logging.MaxOverride {
+			fmt.Fprintf(os.Stderr, "log-level: --for must be between 1s and %s\n", logging.MaxOverride)
+			return 2
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c := adminClient(*wd)
+	var l adminapi.LogLevel
+	var err error
+	if show {
+		l, err = c.LogLevel(ctx)
+	} else {
+		l, err = c.SetLogLevel(ctx, level, d)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "log-level:", adminErr(err))
+		return 1
+	}
+	if *asJSON {
+		return printJSON(l)
+	}
+	printLogLevel(os.Stdout, l)
+	return 0
+}
+
+func printLogLevel(w io.Writer, l adminapi.LogLevel) {
+	if l.Until == "" {
+		fmt.Fprintf(w, "log level: %s (configured)\n", l.Level)
+		return
+	}
+	fmt.Fprintf(w, "log level: %s until %s, then %s\n", l.Level, l.Until, l.Base)
+}
@@ -11,21 +11,44 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/eliteGoblin/focusd/platform/internal/adminapi"
 	"github.com/eliteGoblin/focusd/platform/internal/bundle"
 	"github.com/eliteGoblin/focusd/platform/internal/core/app"
+	"github.com/eliteGoblin/focusd/platform/internal/core/calendar"
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logship"
+	"github.com/eliteGoblin/focusd/platform/internal/core/pause"
+	"github.com/eliteGoblin/focusd/platform/internal/core/plugin"
+	"github.com/eliteGoblin/focusd/platform/internal/core/scheduler"
 	"github.com/eliteGoblin/focusd/platform/internal/core/snapshot"
 	"github.com/eliteGoblin/focusd/platform/internal/core/state"
 	"github.com/eliteGoblin/focusd/platform/internal/defaultconfig"
+	"github.com/eliteGoblin/focusd/platform/internal/discover"
+	"github.com/eliteGoblin/focusd/platform/internal/metrics"
 	"github.com/eliteGoblin/focusd/platform/internal/osadapter"
+	"github.com/eliteGoblin/focusd/platform/internal/report"
+	"github.com/eliteGoblin/focusd/platform/internal/stats"
 	"github.com/eliteGoblin/focusd/platform/internal/status"
 )
 
@@ -60,6 +83,36 @@ func main() {
 		os.Exit(runStatus(args))
 	case "run":
 		os.Exit(runRun(args))
+	case "metrics":
+		os.Exit(runMetrics(args))
+	case "report":
+		os.Exit(runReport(args))
+	case "stats":
+		os.Exit(runStats(args))
+	case "events":
+		os.Exit(runEvents(args))
+	case "watch":
+		os.Exit(runWatch(args))
+	case "scan":
+		os.Exit(runScan(args))
+	case "session":
+		os.Exit(runSession(args))
+	case "break":
+		os.Exit(runBreak(args))
+	case "policies":
+		os.Exit(runPolicies(args))
+	case "pause":
+		os.Exit(runPause(args))
+	case "discover":
+		os.Exit(runDiscover(args))
+	case "block":
+		os.Exit(runBlock(args))
+	case "completion":
+		os.Exit(runCompletion(args))
+	case "ship":
+		os.Exit(runShip(args))
+	case "log-level":
+		os.Exit(runLogLevel(args))
 	case "-h", "--help", "help":
 		usage()
 	default:
@@ -77,6 +130,31 @@ usage:
   platform validate [--config PATH] [--state-db PATH] [--plugin-dir DIR] [--mode user|system]
   platform status   [--workdir DIR] [--state-db PATH] [--mode user|system] [--json] [--no-color]
   platform run      [--workdir DIR] [--state-db PATH] [--plugin-dir DIR] [--mode user|system]
+  platform metrics  [--workdir DIR] [--state-db PATH] [--textfile PATH]
+  platform report   [--workdir DIR] [--state-db PATH] [--days N] [--json]
+                    [--smtp HOST:PORT --mail-from ADDR --mail-to ADDR[,ADDR] [--smtp-user USER]]
+  platform stats    [--workdir DIR] [--state-db PATH] [--json]
+  platform events   [--workdir DIR]    (stream from the running platform)
+  platform watch    [--workdir DIR] [--json]    (the same stream, one line per event)
+  platform policies [--config PATH] [--json | --ids]
+  platform policies export > FILE     (the signed default config)
+  platform policies import FILE       (validate and diff; never applied)
+  platform policies test ID --root DIR [--procs FILE] [--plugin-dir DIR] [--json]
+                    (simulate a job against a sandbox; nothing is touched)
+  platform scan     [--workdir DIR] [--policy ID] [--json]
+                    (run every enabled job now, or only ID and wait for it)
+  platform session  [start DURATION] [--workdir DIR] [--state-db PATH]
+  platform break    [JOB] [--workdir DIR] [--state-db PATH]
+  platform pause    [--reason TEXT [--for DURATION]] [--workdir DIR] [--state-db PATH]
+  platform discover [--root DIR] [--json] [--draft FILE [--yes]]
+                    (installed distraction apps: covered, or a suggested job)
+  platform block    NAME [--root DIR] [--draft FILE]
+                    (the job that would block NAME and what it kills; --draft writes it)
+  platform completion bash|zsh|fish   (shell completion; policy ids complete live)
+  platform ship keygen                (a key pair for reading shipped events)
+  platform ship open --key FILE < ENVELOPE   (decrypt one shipped batch)
+  platform log-level [LEVEL [--for DURATION] | reset] [--workdir DIR] [--json]
+                    (move the running log level for a window; it reverts on its own)
 `)
 }
 
@@ -146,6 +224,9 @@ func parseCommon(name string, honorConfigFlag bool, args []string) app.Options {
 		StateDBPath: *db,
 		PluginDir:   *pdir,
 		ForceMode:   osadapter.RunMode(*mode),
+		LogLevel:    os.Getenv(logging.LevelEnv),
+		LogFormat:   os.Getenv(logging.FormatEnv),
+		LogSink:     os.Getenv(logging.SinkEnv),
 	}
 	if honorConfigFlag {
 		opts.ConfigPath = *cfg
@@ -301,6 +382,33 @@ func runStatus(args []string) int {
 		fmt.Fprintln(os.Stderr, "status: cannot read configuration")
 		return 1
 	}
+	// Sweep-health stays on the DB read-only (see collectStatus). An unopenable
+	// DB degrades to "no sweep signal" rather than failing status.
+	var db *state.DB
+	if dbPath != "" {
+		if d, derr := state.OpenReadOnly(dbPath); derr == nil {
+			defer d.Close()
+			db = d
+		}
+	}
+	rep := collectStatus(string(mode), cfg, dbPath, db)
+
+	color := !*noColor && os.Getenv("NO_COLOR") == ""
+	if *jsonOut {
+		status.RenderJSON(rep, os.Stdout)
+	} else {
+		status.RenderText(rep, os.Stdout, color)
+	}
+	if rep.Overall == status.Healthy || rep.Overall == status.Unknown {
+		return 0
+	}
+	return 1
+}
+
+// collectStatus builds the status report for cfg's jobs: last runs from the
+// snapshot beside dbPath, sweep health from db (nil => no signal). Shared by
+// `platform status` and the admin API.
+func collectStatus(mode string, cfg *config.Config, dbPath string, db *state.DB) status.Report {
 	jobs := make([]status.JobInput, 0, len(cfg.Jobs))
 	for _, j := range cfg.Jobs {
 		jobs = append(jobs, status.JobInput{ID: j.ID, Enabled: j.Enabled})
@@ -351,28 +459,1194 @@ func runStatus(args []string) int {
 	// unopenable DB degrades to "not failing" (no signal) rather than crashing
 	// status — it can never produce the run-history flip this fix addresses.
 	var sweepFailing status.SweepFailingFn // nil => no sweep-health signal
-	if dbPath != "" {
-		if db, derr := state.OpenReadOnly(dbPath); derr == nil {
-			defer db.Close()
-			sweepFailing = func() bool {
-				_, failing, serr := db.Events.SweepFailingSince(5 * time.Minute)
-				return serr == nil && failing
+	if db != nil {
+		sweepFailing = func() bool {
+			_, failing, serr := db.Events.SweepFailingSince(5 * time.Minute)
+			return serr == nil && failing
+		}
+	}
+
+	now := time.Now().UTC()
+	rep := status.Collect(mode, jobs, lastRun, sweepFailing, now)
+	rep.Profiles = cfg.ActiveProfiles(time.Now())
+	// A focus session is one rarely-written row, so it reads from the DB like
+	// sweep health. A read error just omits the line.
+	if db != nil {
+		// Sessions, breaks and pauses run on the daemon's trusted clock.
+		tnow := db.Clock.Now(now)
+		if b, ok, err := db.Events.ActiveCalendarBlock(now); err == nil && ok {
+			rep.Calendar = &status.CalendarEntry{Entry: b.Entry, Profile: b.Profile,
+				RemainingS: int64(b.EndsAt.Sub(now) / time.Second)}
+			if !slices.Contains(rep.Profiles, b.Profile) {
+				rep.Profiles = append(rep.Profiles, b.Profile)
 			}
 		}
+		if s, ok, err := db.Sessions.Active(tnow); err == nil && ok {
+			rep.Session = &status.FocusSession{RemainingS: int64(s.EndsAt.Sub(tnow) / time.Second)}
+		}
+		if breaks, err := db.Breaks.Active(tnow); err == nil {
+			for _, b := range breaks {
+				rep.Breaks = append(rep.Breaks, status.JobBreak{ID: b.JobID, RemainingS: int64(b.EndsAt.Sub(tnow) / time.Second)})
+			}
+		}
+		if r, err := db.Events.ReducedCadence(); err == nil && r != "" && cfg.Platform.Backoff.Watched() {
+			rep.Cadence = &status.Cadence{Reason: r, Factor: cfg.Platform.Backoff.Factor}
+			if strings.HasPrefix(r, "idle") {
+				// Idle stretches to the cap, not by the factor.
+				rep.Cadence.Factor = 0
+			}
+		}
+		if p, ok, err := db.Pauses.Active(tnow); err == nil && ok && len(cfg.Pause.Jobs) > 0 {
+			rep.Pause = &status.Pause{Jobs: cfg.Pause.Jobs, RemainingS: int64(p.EndsAt.Sub(tnow) / time.Second)}
+		}
 	}
+	return rep
+}
 
-	rep := status.Collect(string(mode), jobs, lastRun, sweepFailing, time.Now().UTC())
+// adminSource is what both admin listeners read: the live status, the run
+// history, the configured jobs, a scan trigger and the event stream. The
+// debug routes follow DebugEnv, which the daemon sets from `daemon api
+// --debug`.
+func adminSource(a *app.App, sched *scheduler.Scheduler) adminapi.Source {
+	return adminapi.Source{
+		Version:      version,
+		Started:      time.Now(),
+		Policies:     adminapi.PoliciesFrom(a.Config, adminapi.SourceBuiltin),
+		PolicySHA256: defaultconfig.Digest(),
+		Status:       func() status.Report { return collectStatus(string(a.Mode), a.Config, a.DBPath, a.State) },
+		Recent:       a.State.Runs.Recent,
+		Scan:         sched.RunNow,
+		ScanPolicy: func(id string) (adminapi.PolicyResult, bool) {
+			out, ok := sched.RunJob(id)
+			return adminapi.PolicyResult{Job: id, Status: out.Status, DurationMS: out.DurationMS,
+				ExitCode: out.ExitCode, TimedOut: out.TimedOut, Actions: eventlog.ParseActions(out.Stdout)}, ok
+		},
+		Events: a.EventLog().Subscribe,
+		Hold:   a.HoldStrictLock,
+		Clock:  a.SetClockSkew,
+		Level:  a.LogLevel,
+		Debug:  os.Getenv(adminapi.DebugEnv) == "1",
+	}
+}
 
-	color := !*noColor && os.Getenv("NO_COLOR") == ""
+// serveAdminAPI runs the localhost admin API for the lifetime of ctx. It is
+// optional: a bad address or a failed listen is a WARN, never a failed run.
+func serveAdminAPI(ctx context.Context, a *app.App, src adminapi.Source, addr, token string) {
+	if err := adminapi.Serve(ctx, addr, token, src, a.Log); err != nil {
+		a.Log.Warn("admin api not serving", "err", err)
+	}
+}
+
+// serveAdminSocket runs the admin API on the unix socket beside state.db,
+// which `platform events` and `platform scan` talk to. Like the TCP
+// listener, a failure only costs the thin clients, never enforcement.
+func serveAdminSocket(ctx context.Context, a *app.App, src adminapi.Source) {
+	if a.DBPath == ":memory:" {
+		return
+	}
+	path := filepath.Join(filepath.Dir(a.DBPath), adminapi.SocketName)
+	if err := adminapi.ServeUnix(ctx, path, src, a.Log); err != nil {
+		a.Log.Warn("admin socket not serving", "err", fmt.Sprintf("%T", errors.Unwrap(err)))
+	}
+}
+
+// adminClient is the thin client for the running platform's admin socket.
+func adminClient(wd string) *adminapi.Client {
+	return adminapi.NewClient(filepath.Join(resolveWorkdir(wd), adminapi.SocketName))
+}
+
+// runEvents streams protection events from the running platform, one JSON
+// object per line, until interrupted. It is `platform watch --json`.
+func runEvents(args []string) int {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "daemon-managed workdir; locates the admin socket")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	enc := json.NewEncoder(os.Stdout)
+	return tailEvents("events", *wd, func(ev eventlog.Event) error { return enc.Encode(ev) })
+}
+
+// runWatch is `platform watch`: the live event stream as one readable line
+// per event — kills, removals, restores, relaunches of a killed guard — until
+// interrupted. --json prints the raw NDJSON instead, for piping.
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "daemon-managed workdir; locates the admin socket")
+	asJSON := fs.Bool("json", false, "print one JSON object per event")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		return tailEvents("watch", *wd, func(ev eventlog.Event) error { return enc.Encode(ev) })
+	}
+	return tailEvents("watch", *wd, func(ev eventlog.Event) error {
+		_, err := fmt.Println(ev.Text())
+		return err
+	})
+}
+
+// tailEvents feeds each event from the admin socket to fn until SIGINT or
+// SIGTERM, which is a clean exit.
+func tailEvents(name, wd string, fn func(eventlog.Event) error) int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err := adminClient(wd).Events(ctx, fn)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, adminErr(err))
+		return 1
+	}
+	return 0
+}
+
+// runPolicies lists the enforced jobs from the signed embedded config — or,
+// with --config, from a draft file — with their schedules, options, pattern
+// lists, overlays and source. --json is the same list /v1/policies serves;
+// --ids is just the enabled ids, for shell completion.
+// It reads no state and needs no running platform. `export`, `import` and
+// `test` are handled by runPoliciesExport, runPoliciesImport and
+// runPoliciesTest.
+func runPolicies(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "export":
+			return runPoliciesExport(args[1:])
+		case "import":
+			return runPoliciesImport(args[1:])
+		case "test":
+			return runPoliciesTest(args[1:])
+		}
+	}
+	fs := flag.NewFlagSet("policies", flag.ContinueOnError)
+	cfgPath := fs.String("config", "", "describe this config file instead of the signed default (dev inspection)")
+	asJSON := fs.Bool("json", false, "print the policies as JSON")
+	idsOnly := fs.Bool("ids", false, "print only the enabled job ids, one per line (for shell completion)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	var (
+		cfg    *config.Config
+		err    error
+		source = adminapi.SourceBuiltin
+	)
+	if *cfgPath != "" {
+		cfg, err = config.Load(*cfgPath)
+		source = adminapi.SourceConfig
+	} else {
+		cfg, err = defaultconfig.Load()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "policies: cannot read configuration:", err)
+		return 1
+	}
+	policies := adminapi.PoliciesFrom(cfg, source)
+	if *idsOnly {
+		for _, p := range policies {
+			if p.Enabled {
+				fmt.Println(p.ID)
+			}
+		}
+		return 0
+	}
+	if *asJSON {
+		return printJSON(policies)
+	}
+	if source == adminapi.SourceBuiltin {
+		fmt.Printf("  policy set sha256:%s (pinned by the signed release)\n", defaultconfig.Digest()[:12])
+	}
+	for _, p := range policies {
+		on := "enabled"
+		if !p.Enabled {
+			on = "disabled"
+		}
+		fmt.Printf("  %-30s %s · %s · %s · %s\n", p.ID, p.Plugin, p.Schedule, on, p.Source)
+		for _, k := range slices.Sorted(maps.Keys(p.Options)) {
+			fmt.Printf("  %-30s   %s: %v\n", "", k, p.Options[k])
+		}
+		for _, pat := range p.Patterns {
+			fmt.Printf("  %-30s   %s: %d entries\n", "", pat.Kind, len(pat.Values))
+		}
+		var extra []string
+		if len(p.Overlays) > 0 {
+			extra = append(extra, "overlays "+strings.Join(p.Overlays, ", "))
+		}
+		if p.Breakable {
+			extra = append(extra, "breakable")
+		}
+		if p.Pausable {
+			extra = append(extra, "pausable")
+		}
+		if p.Locked {
+			extra = append(extra, "locked")
+		}
+		if len(extra) > 0 {
+			fmt.Printf("  %-30s   %s\n", "", strings.Join(extra, " · "))
+		}
+	}
+	return 0
+}
+
+// runPoliciesExport writes the signed embedded config.yaml to stdout, byte
+// for byte, as the starting point for a curated blocklist to share.
+func runPoliciesExport(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: platform policies export > FILE")
+		return 2
+	}
+	if _, err := os.Stdout.Write(defaultconfig.Bytes()); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// runPoliciesImport validates a shared policy file and shows how its jobs
+// differ from the signed default. It never applies the file: the signed
+// embedded config stays the only policy source on the run path, so a file
+// cannot loosen enforcement — removals least of all. Adopting it means
+// shipping it as the default of a signed release.
+func runPoliciesImport(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: platform policies import FILE")
+		return 2
+	}
+	draft, err := config.Load(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "policies import:", err)
+		return 1
+	}
+	builtin, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "policies import: cannot read the signed default:", err)
+		return 1
+	}
+	d := config.DiffJobs(builtin, draft)
+	fmt.Printf("  valid: %d jobs\n", len(draft.Jobs))
+	if d.Empty() {
+		fmt.Println("  same jobs as the signed default")
+	}
+	for _, id := range d.Added {
+		fmt.Printf("  + %s\n", id)
+	}
+	for _, id := range d.Changed {
+		fmt.Printf("  ~ %s\n", id)
+	}
+	for _, id := range d.Removed {
+		fmt.Printf("  - %s (a removal loosens protection)\n", id)
+	}
+	if locked := config.LockViolations(builtin, draft); len(locked) > 0 {
+		for _, id := range locked {
+			fmt.Printf("  ! %s is locked: it stays enabled and locked until a full uninstall\n", id)
+		}
+		fmt.Println("  rejected: this file drops or unlocks a locked job")
+		return 1
+	}
+	fmt.Println("  not applied: policy comes only from the signed default; ship this file in a signed release to enforce it")
+	return 0
+}
+
+// policyTestWait bounds a simulation whose job sets no timeout.
+const policyTestWait = 30 * time.Second
+
+// runPoliciesTest simulates one job of the signed default against a sandbox:
+// it runs the job's plugin as `<plugin> test --root DIR [--procs FILE]` with
+// the job's config on stdin, and prints what the plugin says it would stop
+// or remove. Nothing is killed or deleted and nothing is recorded. Only a
+// plugin with path or process patterns implements `test`; the rest exit 2.
+//
+//	platform policies test ID --root DIR [--procs FILE] [--plugin-dir DIR] [--json]
+func runPoliciesTest(args []string) int {
+	var id string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("policies test", flag.ContinueOnError)
+	root := fs.String("root", "", "sandbox directory laid out like / (e.g. Applications, Users)")
+	procs := fs.String("procs", "", "process fixture: one process name per line")
+	pdir := fs.String("plugin-dir", "", "plugin scan dir (default: the mode's plugin dir)")
+	modeFlag := fs.String("mode", "", "force run mode: user|system")
+	asJSON := fs.Bool("json", false, "print the plugin's result as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if id == "" && fs.NArg() == 1 {
+		id = fs.Arg(0)
+	}
+	if id == "" || *root == "" {
+		fmt.Fprintln(os.Stderr, "usage: platform policies test ID --root DIR [--procs FILE] [--plugin-dir DIR] [--json]")
+		return 2
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "policies test: cannot read configuration:", err)
+		return 1
+	}
+	idx := slices.IndexFunc(cfg.Jobs, func(j config.Job) bool { return j.ID == id })
+	if idx < 0 {
+		fmt.Fprintf(os.Stderr, "policies test: no job %q in the signed default\n", id)
+		return 1
+	}
+	job := cfg.Jobs[idx]
+
+	adapter := osadapter.NewAdapter()
+	mode := osadapter.RunMode(*modeFlag)
+	if mode == "" {
+		mode = adapter.DetectRunMode()
+	}
+	dir := *pdir
+	if dir == "" {
+		if dir, err = adapter.DefaultPluginDir(mode); err != nil {
+			fmt.Fprintln(os.Stderr, "policies test: cannot resolve the plugin dir; pass --plugin-dir")
+			return 1
+		}
+	}
+	found, err := plugin.NewDiscoverer(mode).Discover(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "policies test: plugin discovery failed:", err)
+		return 1
+	}
+	pi := slices.IndexFunc(found, func(d plugin.Discovered) bool {
+		return d.Manifest != nil && d.Manifest.ID == job.Plugin
+	})
+	switch {
+	case pi < 0:
+		fmt.Fprintf(os.Stderr, "policies test: plugin %q is not installed\n", job.Plugin)
+		return 1
+	case !found[pi].OK:
+		fmt.Fprintf(os.Stderr, "policies test: plugin %q is not runnable in %s mode: %s\n", job.Plugin, mode, found[pi].Reason)
+		return 1
+	}
+
+	input, _ := json.Marshal(plugin.JobInput{JobID: job.ID, PluginID: job.Plugin, Config: job.Config})
+	wait := job.Timeout.Std()
+	if wait <= 0 {
+		wait = policyTestWait
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	argv := []string{"test", "--root", *root}
+	if *procs != "" {
+		argv = append(argv, "--procs", *procs)
+	}
+	cmd := exec.CommandContext(ctx, found[pi].BinaryPath, argv...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 2 {
+		fmt.Fprintf(os.Stderr, "policies test: plugin %q has no simulation for this input\n", job.Plugin)
+		return 1
+	}
+	res, perr := plugin.ParseResult(stdout)
+	if err != nil || perr != nil {
+		fmt.Fprintf(os.Stderr, "policies test: simulation failed (%T)\n", err)
+		return 1
+	}
+	if *asJSON {
+		return printJSON(res)
+	}
+	fmt.Printf("  %s: %s\n", job.ID, res.Message)
+	for _, k := range slices.Sorted(maps.Keys(res.Details)) {
+		list, ok := res.Details[k].([]any)
+		if !ok || len(list) == 0 {
+			continue
+		}
+		fmt.Printf("  %s:\n", k)
+		for _, v := range list {
+			fmt.Printf("    %v\n", v)
+		}
+	}
+	return 0
+}
+
+// scanPolicyWait bounds how long `platform scan --policy` waits for the run.
+// It outlasts any sane job timeout; the run itself is bounded by its own.
+const scanPolicyWait = 5 * time.Minute
+
+// Exit codes of `platform scan`, the numbers of the daemon's scripting
+// contract (daemon/cmd/daemon/exitcode.go) so one script can branch on both.
+const (
+	scanExitOK         = 0  // fired, or the one job ran clean
+	scanExitError      = 1  // the request failed
+	scanExitDegraded   = 2  // the job ran and did not end ok
+	scanExitNotRunning = 3  // no platform to ask (no admin socket)
+	scanExitUsage      = 64 // bad flags, or no enabled policy by that id
+)
+
+// runScan asks the running platform to run every enabled job now, or with
+// --policy to run one job and wait for how it ended: handy when iterating on
+// one policy without firing every other job's removals.
+//
+//	platform scan [--json] [--quiet]               fire every enabled job
+//	platform scan --policy ID [--json] [--quiet]   run ID now and print its result
+//
+// --quiet prints nothing; the exit code says how it went.
+func runScan(args []string) int {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	wd := fs.String("workdir", "", "daemon-managed workdir; locates the admin socket")
+	policy := fs.String("policy", "", "run only this job id and wait for its result")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	quiet := fs.Bool("quiet", false, "print nothing; branch on the exit code")
+	if err := fs.Parse(args); err != nil {
+		return scanExitUsage
+	}
+	if *policy == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		n, err := adminClient(*wd).Scan(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "scan:", adminErr(err))
+			return scanExitCode(err)
+		}
+		switch {
+		case *quiet:
+		case *asJSON:
+			return printJSON(adminapi.ScanResult{Triggered: n})
+		default:
+			fmt.Printf("scan: triggered %d job(s)\n", n)
+		}
+		return scanExitOK
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scanPolicyWait)
+	defer cancel()
+	res, err := adminClient(*wd).ScanPolicy(ctx, *policy)
+	if errors.Is(err, adminapi.ErrNoPolicy) {
+		fmt.Fprintf(os.Stderr, "scan: no enabled policy %q\n", *policy)
+		return scanExitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scan:", adminErr(err))
+		return scanExitCode(err)
+	}
+	switch {
+	case *quiet:
+	case *asJSON:
+		if code := printJSON(adminapi.ScanResult{Triggered: 1, Policy: &res}); code != 0 {
+			return code
+		}
+	default:
+		fmt.Printf("scan: %s %s in %s%s\n", res.Job, res.Status,
+			time.Duration(res.DurationMS)*time.Millisecond, actionSummary(res.Actions))
+	}
+	if res.Status != state.RunStatusOK {
+		return scanExitDegraded
+	}
+	return scanExitOK
+}
+
+// scanExitCode tells a platform that is not running from a request that
+// failed.
+func scanExitCode(err error) int {
+	if errors.Is(err, adminapi.ErrUnavailable) {
+		return scanExitNotRunning
+	}
+	return scanExitError
+}
+
+// runDiscover is `platform discover`: find installed catalog apps (app
+// bundles, Homebrew casks, Steam library folders, login items) and say
+// which the signed default covers and what job would cover the rest.
+//
+//	platform discover [--root DIR] [--json] [--draft FILE [--yes]]
+//
+// --draft asks, per suggestion, whether to take the job (--yes takes all)
+// and writes the signed default plus those jobs to FILE: a draft for
+// `platform policies import`. Nothing is applied.
+func runDiscover(args []string) int {
+	fs := flag.NewFlagSet("discover", flag.ContinueOnError)
+	root := fs.String("root", "/", "directory laid out like / to scan")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	draftPath := fs.String("draft", "", "write a draft config with the accepted suggestions to this file")
+	yes := fs.Bool("yes", false, "with --draft, accept every suggestion without asking")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "discover: cannot read the signed default:", err)
+		return 1
+	}
+	found := discover.Scan(*root, cfg)
+	if *asJSON {
+		if found == nil {
+			found = []discover.Found{}
+		}
+		return printJSON(found)
+	}
+	if len(found) == 0 {
+		fmt.Println("  discover: no catalog apps found")
+		return 0
+	}
+	for _, f := range found {
+		switch {
+		case f.CoveredBy != "":
+			fmt.Printf("  %-22s covered by %s (%s)\n", f.Name, f.CoveredBy, strings.Join(f.Sources, ", "))
+		case f.Suggested != "":
+			fmt.Printf("  %-22s not covered; suggest %s (%s)\n", f.Name, f.Suggested, strings.Join(f.Sources, ", "))
+		default:
+			fmt.Printf("  %-22s not covered (%s)\n", f.Name, strings.Join(f.Sources, ", "))
+		}
+	}
+	if *draftPath == "" {
+		return 0
+	}
+	var picks []discover.Found
+	in := bufio.NewReader(os.Stdin)
+	for _, f := range found {
+		if f.Suggested == "" {
+			continue
+		}
+		if !*yes {
+			fmt.Printf("  add %s? [y/N] ", f.Suggested)
+			line, _ := in.ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(line)); a != "y" && a != "yes" {
+				continue
+			}
+		}
+		picks = append(picks, f)
+	}
+	draft, err := discover.DraftWith(defaultconfig.Bytes(), picks)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "discover:", err)
+		return 1
+	}
+	if err := os.WriteFile(*draftPath, draft, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "discover: write draft failed:", err)
+		return 1
+	}
+	fmt.Printf("  draft: %d job(s) added; check it with `platform policies import FILE`\n", len(picks))
+	return 0
+}
+
+// runBlock is `platform block NAME`: the quick path from "this app is a time
+// sink" to a job. NAME is a catalog app (by name, bundle or cask) or any
+// app bundle installed under --root. It prints the job that would block it
+// and what that job kills and deletes; --draft writes the signed default
+// plus the job to FILE, like `discover --draft`. Nothing is applied: the
+// enforced policy only changes with a signed release.
+//
+//	platform block NAME [--root DIR] [--draft FILE]
+func runBlock(args []string) int {
+	fs := flag.NewFlagSet("block", flag.ContinueOnError)
+	root := fs.String("root", "/", "directory laid out like / to look for the app under")
+	draftPath := fs.String("draft", "", "write a draft config with the job to this file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "usage: platform block NAME [--root DIR] [--draft FILE]")
+		return 2
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "block: cannot read the signed default:", err)
+		return 1
+	}
+	f, ok := discover.Lookup(*root, cfg, name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "block: %q is not a catalog app or an installed app bundle (see `platform discover`)\n", name)
+		return 2
+	}
+	where := "not installed"
+	if len(f.Sources) > 0 {
+		where = "installed (" + strings.Join(f.Sources, ", ") + ")"
+	}
+	fmt.Printf("  %-10s %s, %s\n", "app", f.Name, where)
+	if f.CoveredBy != "" {
+		fmt.Printf("  %-10s already blocked by %s\n", "job", f.CoveredBy)
+		return 0
+	}
+	job, ok := f.Job()
+	if !ok {
+		fmt.Printf("  %-10s none: the app has no process of its own to kill\n", "job")
+		return 0
+	}
+	procs, _ := job.Config["process_names"].([]string)
+	fmt.Printf("  %-10s %s (%s, %s)\n", "job", job.ID, job.Plugin, job.Schedule)
+	fmt.Printf("  %-10s %s, on sight\n", "kills", strings.Join(procs, ", "))
+	fmt.Printf("  %-10s nothing (the job only kills)\n", "deletes")
+	if *draftPath == "" {
+		fmt.Println("  to ship it: platform block NAME --draft FILE, then `platform policies import FILE`")
+		return 0
+	}
+	draft, err := discover.DraftWith(defaultconfig.Bytes(), []discover.Found{f})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "block:", err)
+		return 1
+	}
+	if err := os.WriteFile(*draftPath, draft, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "block: write draft failed:", err)
+		return 1
+	}
+	fmt.Println("  draft: job added; check it with `platform policies import FILE` (enforced once it ships in a signed release)")
+	return 0
+}
+
+// printJSON writes v to stdout as indented JSON; the return is the exit code.
+func printJSON(v any) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// actionSummary renders the non-zero action counts as " (2 kills, …)", or
+// "" when the run did nothing.
+func actionSummary(a eventlog.Actions) string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		what string
+	}{
+		{a.Kills, "kills"}, {len(a.BlockedApps), "apps blocked"}, {a.Removals, "removals"},
+		{a.BypassKills, "bypass kills"}, {a.Relaunches, "relaunches"},
+	} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.what))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// Bounds on one `platform session start`: long enough to matter, short
+// enough that a typo ("20h" for "2h") cannot lock a machine down for days.
+const (
+	minSession = time.Minute
+	maxSession = 12 * time.Hour
+)
+
+// runSession starts or shows a focus session: a fixed window during which
+// the signed config's session overlay (extra blocked hosts) applies. There
+// is deliberately no stop: once started, only the clock ends a session, and
+// starting again can only extend it.
+//
+//	platform session [--workdir DIR]                  show the active session
+//	platform session start 2h [--workdir DIR]         start (or extend) one
+func runSession(args []string) int {
+	start := len(args) > 0 && args[0] == "start"
+	var durArg string
+	if start {
+		args = args[1:]
+		// Accept the duration before or after the flags.
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			durArg, args = args[0], args[1:]
+		}
+	}
+	fs := flag.NewFlagSet("session", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	var length time.Duration
+	if start {
+		if durArg == "" && fs.NArg() == 1 {
+			durArg = fs.Arg(0)
+		} else if durArg == "" || fs.NArg() != 0 {
+			fmt.Fprintln(os.Stderr, "usage: platform session start DURATION (e.g. 2h, 45m)")
+			return 2
+		}
+		d, err := time.ParseDuration(durArg)
+		if err != nil || d < minSession || d > maxSession {
+			fmt.Fprintf(os.Stderr, "session: duration must be between %s and %s\n", minSession, maxSession)
+			return 2
+		}
+		length = d
+	} else if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: platform session [start DURATION]")
+		return 2
+	}
+
+	workdir := resolveWorkdir(*wd)
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(workdir, "state.db")
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		fmt.Fprintln(os.Stderr, "session: no platform state (is the platform installed?)")
+		return 1
+	}
+	open := state.OpenReadOnly
+	if start {
+		open = state.Open
+	}
+	db, err := open(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "session: cannot open state (re-run with sudo?)")
+		return 1
+	}
+	defer db.Close()
+
+	now := db.Clock.Now(time.Now())
+	var sess state.Session
+	active := true
+	if start {
+		sess, err = db.Sessions.Start(now, length)
+	} else {
+		sess, active, err = db.Sessions.Active(now)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "session: cannot read or write state (re-run with sudo?)")
+		return 1
+	}
+	if !active {
+		fmt.Println("  session: none active")
+		return 0
+	}
+	fmt.Printf("  session: active until %s (%s left); it cannot be ended early\n",
+		sess.EndsAt.Local().Format("15:04"), sess.EndsAt.Sub(now).Round(time.Minute))
+	if start {
+		// Apply now rather than on each job's next tick. Best-effort: the
+		// running platform reads the session on every tick regardless.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := adminapi.NewClient(filepath.Join(filepath.Dir(dbPath), adminapi.SocketName)).Scan(ctx); err != nil {
+			fmt.Println("  session: applies on each job's next run")
+		}
+	}
+	return 0
+}
+
+// runBreak shows the weekly break budget, or redeems one token to pause a
+// job for the configured length. Redeeming asks for a typed confirmation,
+// is refused during a focus session, and is audited in platform_events and
+// the event stream.
+//
+//	platform break [--workdir DIR]          show tokens left and what they can pause
+//	platform break JOB [--workdir DIR]      pause JOB (prompts for confirmation)
+func runBreak(args []string) int {
+	var jobID string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		jobID, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("break", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: platform break [JOB]")
+		return 2
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "break: cannot read configuration")
+		return 1
+	}
+	budget := cfg.Breaks
+	if budget.PerWeek == 0 {
+		fmt.Println("  break: no break tokens configured")
+		return 0
+	}
+	if jobID != "" && !budget.Breakable(jobID) {
+		fmt.Fprintf(os.Stderr, "break: %q cannot take a break (allowed: %s)\n", jobID, strings.Join(budget.Jobs, ", "))
+		return 2
+	}
+
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(resolveWorkdir(*wd), "state.db")
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		fmt.Fprintln(os.Stderr, "break: no platform state (is the platform installed?)")
+		return 1
+	}
+	open := state.OpenReadOnly
+	if jobID != "" {
+		open = state.Open
+	}
+	db, err := open(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "break: cannot open state (re-run with sudo?)")
+		return 1
+	}
+	defer db.Close()
+
+	now := db.Clock.Now(time.Now())
+	used, err := db.Breaks.Used(now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "break: cannot read state")
+		return 1
+	}
+	left := max(budget.PerWeek-used, 0)
+	if jobID == "" {
+		fmt.Printf("  break: %d of %d left this week (%s each; resets Monday): %s\n",
+			left, budget.PerWeek, budget.Length.Std(), strings.Join(budget.Jobs, ", "))
+		return 0
+	}
+	if pauseRefused(db, now, "break") {
+		return 1
+	}
+	if left == 0 {
+		fmt.Fprintln(os.Stderr, "break: no breaks left this week")
+		return 1
+	}
+
+	phrase := "pause " + jobID
+	fmt.Printf("  This spends 1 of your %d remaining break(s) this week: %s stops enforcing for %s.\n",
+		left, jobID, budget.Length.Std())
+	fmt.Printf("  Type %q to confirm: ", phrase)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(line) != phrase {
+		fmt.Println("  break: not confirmed; nothing spent")
+		return 1
+	}
+
+	b, err := db.Breaks.Redeem(jobID, db.Clock.Now(time.Now()), budget.Length.Std(), budget.PerWeek)
+	switch {
+	case errors.Is(err, state.ErrNoBreaksLeft), errors.Is(err, state.ErrOnBreak):
+		fmt.Fprintln(os.Stderr, "break:", err)
+		return 1
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "break: cannot write state (re-run with sudo?)")
+		return 1
+	}
+	_ = db.Events.RecordBreakRedeemed(b, left-1)
+	_ = eventlog.New(filepath.Dir(dbPath)).Append(eventlog.Event{
+		Type: eventlog.TypeBreakRedeemed, Job: jobID, Reason: budget.Length.Std().String()})
+	fmt.Printf("  break: %s paused until %s; %d left this week\n", jobID, b.EndsAt.Local().Format("15:04"), left-1)
+	return 0
+}
+
+// pauseRefused reports whether nothing may pause now — a focus session or
+// a strict lock holds, or state cannot say — printing why under verb.
+func pauseRefused(db *state.DB, now time.Time, verb string) bool {
+	if _, on, err := db.Sessions.Active(now); err != nil || on {
+		fmt.Fprintf(os.Stderr, "%s: not during a focus session\n", verb)
+		return true
+	}
+	until, err := db.Strict.Until()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: cannot read state\n", verb)
+		return true
+	}
+	if now.Before(until) {
+		fmt.Fprintf(os.Stderr, "%s: strict lock on until %s; %ss are disabled\n", verb, until.Local().Format("2006-01-02 15:04"), verb)
+		return true
+	}
+	return false
+}
+
+// maxPauseReason bounds the audited reason.
+const maxPauseReason = 200
+
+// runPause stops the configured pausable jobs for a short, capped time,
+// once the user has transcribed the pause passage and waited out the
+// cooldown. The wait runs in this process: interrupting it records
+// nothing. Refused during a focus session or a strict lock (checked again
+// after the wait); the window and reason are audited.
+//
+//	platform pause [--workdir DIR]                            show the running pause and the rules
+//	platform pause --reason TEXT [--for 15m] [--workdir DIR]  earn a pause
+func runPause(args []string) int {
+	fs := flag.NewFlagSet("pause", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	length := fs.Duration("for", 0, "how long to pause (default and cap: the configured max)")
+	reasonFlag := fs.String("reason", "", "why; recorded in the audit log")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: platform pause [--reason TEXT [--for DURATION]]")
+		return 2
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pause: cannot read configuration")
+		return 1
+	}
+	rules := cfg.Pause
+	if rules.Max == 0 || len(rules.Jobs) == 0 {
+		fmt.Println("  pause: not configured")
+		return 0
+	}
+	reason := strings.TrimSpace(*reasonFlag)
+	start := reason != ""
+	if !start && *length != 0 {
+		fmt.Fprintln(os.Stderr, "pause: --for needs a --reason")
+		return 2
+	}
+	if strings.ContainsAny(reason, "\r\n") || len([]rune(reason)) > maxPauseReason {
+		fmt.Fprintf(os.Stderr, "pause: --reason must be one line of at most %d characters\n", maxPauseReason)
+		return 2
+	}
+	d := *length
+	if d == 0 {
+		d = rules.Max.Std()
+	}
+	if d < time.Minute || d > rules.Max.Std() {
+		fmt.Fprintf(os.Stderr, "pause: --for must be between 1m and %s\n", rules.Max.Std())
+		return 2
+	}
+
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(resolveWorkdir(*wd), "state.db")
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		fmt.Fprintln(os.Stderr, "pause: no platform state (is the platform installed?)")
+		return 1
+	}
+	open := state.OpenReadOnly
+	if start {
+		open = state.Open
+	}
+	db, err := open(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pause: cannot open state (re-run with sudo?)")
+		return 1
+	}
+	defer db.Close()
+
+	cur, paused, err := db.Pauses.Active(db.Clock.Now(time.Now()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pause: cannot read state")
+		return 1
+	}
+	if paused {
+		fmt.Printf("  pause: running until %s; it cannot be extended\n", cur.EndsAt.Local().Format("15:04"))
+		return 0
+	}
+	if !start {
+		fmt.Printf("  pause: up to %s after transcribing a passage and waiting %s: %s\n",
+			rules.Max.Std(), rules.Cooldown.Std(), strings.Join(rules.Jobs, ", "))
+		return 0
+	}
+	if pauseRefused(db, db.Clock.Now(time.Now()), "pause") {
+		return 1
+	}
+
+	fmt.Printf("  Type the passage below by hand, then Ctrl-D on a blank line.\n"+
+		"  Afterwards there is a %s wait before %s of pause begins.\n\n"+
+		"----- BEGIN PASSAGE -----\n%s----- END PASSAGE -----\n\n",
+		rules.Cooldown.Std(), d, pause.Passage())
+	began := time.Now()
+	typed, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pause: could not read input; nothing recorded")
+		return 1
+	}
+	if ok, why := pause.Accept(string(typed), time.Since(began)); !ok {
+		fmt.Fprintln(os.Stderr, "pause:", why+"; nothing recorded")
+		return 1
+	}
+
+	fmt.Printf("  Accepted. The pause begins in %s if this keeps running; Ctrl-C drops it.\n", rules.Cooldown.Std())
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case <-ctx.Done():
+		fmt.Println("\n  pause: dropped; nothing recorded")
+		return 1
+	case <-time.After(rules.Cooldown.Std()):
+	}
+	// A session or lock may have started during the wait.
+	if pauseRefused(db, db.Clock.Now(time.Now()), "pause") {
+		return 1
+	}
+
+	p, err := db.Pauses.Start(db.Clock.Now(time.Now()), d, reason)
+	switch {
+	case errors.Is(err, state.ErrPaused):
+		fmt.Fprintln(os.Stderr, "pause:", err)
+		return 1
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "pause: cannot write state (re-run with sudo?)")
+		return 1
+	}
+	_ = db.Events.RecordPauseStarted(p)
+	_ = eventlog.New(filepath.Dir(dbPath)).Append(eventlog.Event{
+		Type: eventlog.TypePauseStarted, Reason: d.String()})
+	fmt.Printf("  pause: %s paused until %s\n", strings.Join(rules.Jobs, ", "), p.EndsAt.Local().Format("15:04"))
+	return 0
+}
+
+// adminErr keeps thin-client errors generic: the socket path never prints.
+func adminErr(err error) string {
+	if errors.Is(err, adminapi.ErrUnavailable) {
+		return "platform not running (no admin socket)"
+	}
+	return "request failed"
+}
+
+// runMetrics prints the run history as Prometheus text, or writes it to a
+// node_exporter textfile-collector file. It reads the live DB read-only, once:
+// unlike status it is not polled on every tick, so the writer-contention that
+// moved status onto the snapshot does not apply. Errors never name a path.
+func runMetrics(args []string) int {
+	fs := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	textfile := fs.String("textfile", "", "write to this .prom file (atomic) instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(resolveWorkdir(*wd), "state.db")
+	}
+	db, err := state.OpenReadOnly(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "metrics: cannot open state")
+		return 1
+	}
+	defer db.Close()
+
+	sample, err := metrics.Collect(db.Runs, db.Events, time.Now().UTC())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "metrics: cannot read state")
+		return 1
+	}
+	if *textfile == "" {
+		if err := metrics.Write(os.Stdout, sample); err != nil {
+			return 1
+		}
+		return 0
+	}
+	if err := writeTextfile(*textfile, sample); err != nil {
+		fmt.Fprintln(os.Stderr, "metrics: cannot write textfile")
+		return 1
+	}
+	return 0
+}
+
+// runReport summarises the last --days of enforcement (default a week) from
+// the run history, as text or JSON, and optionally mails the text form via
+// an SMTP relay. The relay password comes from FOCUSD_SMTP_PASSWORD, never a
+// flag, so it stays out of argv. Like metrics it reads the live DB once,
+// read-only.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	days := fs.Int("days", 7, "report window in days, ending now")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON")
+	relay := fs.String("smtp", "", "mail the report via this SMTP relay (host:port)")
+	from := fs.String("mail-from", "", "sender address (with --smtp)")
+	to := fs.String("mail-to", "", "comma-separated recipients (with --smtp)")
+	user := fs.String("smtp-user", "", "SMTP username; password from FOCUSD_SMTP_PASSWORD")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *days < 1 {
+		fmt.Fprintln(os.Stderr, "report: --days must be at least 1")
+		return 2
+	}
+	var mailer *report.Mailer
+	if *relay != "" {
+		mailer = &report.Mailer{Addr: *relay, From: *from, User: *user,
+			Password: os.Getenv("FOCUSD_SMTP_PASSWORD")}
+		for _, addr := range strings.Split(*to, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				mailer.To = append(mailer.To, addr)
+			}
+		}
+		if err := mailer.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, "report:", err)
+			return 2
+		}
+	}
+
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(resolveWorkdir(*wd), "state.db")
+	}
+	db, err := state.OpenReadOnly(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "report: cannot open state")
+		return 1
+	}
+	defer db.Close()
+
+	now := time.Now().UTC()
+	rep, err := report.Build(db.Runs, db.Events, now.AddDate(0, 0, -*days), now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "report: cannot read state")
+		return 1
+	}
 	if *jsonOut {
-		status.RenderJSON(rep, os.Stdout)
+		if err := report.RenderJSON(rep, os.Stdout); err != nil {
+			return 1
+		}
 	} else {
-		status.RenderText(rep, os.Stdout, color)
+		report.RenderText(rep, os.Stdout)
 	}
-	if rep.Overall == status.Healthy || rep.Overall == status.Unknown {
+	if mailer != nil {
+		if err := mailer.Send(rep); err != nil {
+			fmt.Fprintln(os.Stderr, "report: mail failed:", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// runStats is `platform stats`: lifetime and rolling enforcement counters,
+// uptime and clean streaks, read-only from the state DB.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	dbFlag := fs.String("state-db", "", "state.db path")
+	wd := fs.String("workdir", "", "daemon-managed workdir; derives state-db path")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	dbPath := *dbFlag
+	if dbPath == "" {
+		dbPath = filepath.Join(resolveWorkdir(*wd), "state.db")
+	}
+	db, err := state.OpenReadOnly(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "stats: cannot open state")
+		return 1
+	}
+	defer db.Close()
+
+	st, err := stats.Build(db.Stats, db.Runs, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "stats: cannot read state")
+		return 1
+	}
+	if *jsonOut {
+		if err := stats.RenderJSON(st, os.Stdout); err != nil {
+			return 1
+		}
 		return 0
 	}
-	return 1
+	stats.RenderText(st, os.Stdout)
+	return 0
+}
+
+// writeTextfile replaces path atomically (temp in the same dir + rename), so
+// the collector never scrapes a half-written file.
+func writeTextfile(path string, s metrics.Sample) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".prom-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // no-op after a successful rename
+	if err := metrics.Write(f, s); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
 func runRun(args []string) int {
@@ -388,20 +1662,54 @@ func runRun(args []string) int {
 		fmt.Fprintln(os.Stderr, "scheduler build failed:", serr)
 		return 1
 	}
+	tctx, tstop := context.WithCancel(context.Background())
+	defer tstop()
+	go a.Tracer.Run(tctx)
+	a.StartCalendar(tctx, os.Getenv(calendar.URLEnv))
+	a.StartLogShip(tctx, os.Getenv(logship.URLEnv), os.Getenv(logship.KeyEnv))
+	a.StartPower(tctx)
+	a.MarkStarted()
 	sched.Start()
-	a.Log.Info("platform running", "jobs_registered", n)
+	a.Log.Info("platform running", "jobs_registered", n, "tracing", a.Tracer != nil)
+	if v := os.Getenv(app.StrictLockEnv); v != "" {
+		if secs, perr := strconv.ParseInt(v, 10, 64); perr == nil {
+			if err := a.HoldStrictLock(time.Duration(secs) * time.Second); err != nil {
+				a.Log.Warn("strict lock not recorded", "err", fmt.Sprintf("%T", err))
+			}
+		}
+	}
+	if v := os.Getenv(app.ClockSkewEnv); v != "" {
+		if secs, perr := strconv.ParseInt(v, 10, 64); perr == nil {
+			if err := a.SetClockSkew(time.Duration(secs) * time.Second); err != nil {
+				a.Log.Warn("clock skew not recorded", "err", fmt.Sprintf("%T", err))
+			}
+		}
+	}
+	src := adminSource(a, sched)
+	go serveAdminSocket(tctx, a, src)
+	if addr := os.Getenv(adminapi.AddrEnv); addr != "" {
+		go serveAdminAPI(tctx, a, src, addr, os.Getenv(adminapi.TokenEnv))
+	}
 
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 	<-sig
 
 	a.Log.Info("shutdown requested; draining in-flight jobs")
-	stopCtx := sched.Stop()
-	select {
-	case <-stopCtx.Done():
-	case <-time.After(30 * time.Second):
+	drained, cancelled := sched.Shutdown(shutdownGrace, shutdownCancelWait)
+	if !drained {
 		a.Log.Warn("shutdown drain timed out")
 	}
-	a.Log.Info("platform stopped")
+	a.MarkStopped(drained)
+	a.Log.Info("platform stopped", "drained", drained, "cancelled", cancelled)
 	return 0
 }
+
+// shutdownGrace is how long in-flight runs may finish after SIGTERM before
+// they are cancelled; shutdownCancelWait bounds the wait for the cancelled
+// runs' rows. Together they stay under the daemon's 2s stop window
+// (platformsvc.ProcSvc.Stop), so its SIGKILL never lands mid-write.
+const (
+	shutdownGrace      = time.Second
+	shutdownCancelWait = 600 * time.Millisecond
+)
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/logship"
+)
+
+// runShip is the reader's side of event shipping (package logship): the
+// platform only ever seals, so whoever reads the shipped batches — the
+// user's partner, or a dashboard acting for them — makes the key pair and
+// opens them here.
+//
+//	platform ship keygen                    — print a new public and private key
+//	platform ship open --key FILE < ENVELOPE — print the batch as JSON
+func runShip(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: platform ship keygen | open --key FILE")
+		return 2
+	}
+	switch args[0] {
+	case "keygen":
+		pub, priv, err := logship.GenerateKey()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ship keygen:", err)
+			return 1
+		}
+		fmt.Println("public: ", pub)
+		fmt.Println("private:", priv)
+		fmt.Println("Give the public key to `daemon config set log.ship-key`; keep the private key off this Mac.")
+		return 0
+	case "open":
+		return runShipOpen(args[1:], os.Stdin, os.Stdout)
+	}
+	fmt.Fprintf(os.Stderr, "ship: unknown subcommand %q\n", args[0])
+	return 2
+}
+
+// runShipOpen decrypts one envelope from in with the private key in --key.
+func runShipOpen(args []string, in io.Reader, out io.Writer) int {
+	fs := flag.NewFlagSet("ship open", flag.ContinueOnError)
+	keyPath := fs.String("key", "", "file holding the base64 private key")
+	if err := fs.Parse(args); err != nil || *keyPath == "" {
+		fmt.Fprintln(os.Stderr, "ship open: --key FILE is required")
+		return 2
+	}
+	raw, err := os.ReadFile(*keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ship open: cannot read the key file")
+		return 1
+	}
+	key, err := logship.ParsePrivateKey(strings.TrimSpace(string(raw)))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ship open:", err)
+		return 1
+	}
+	var env logship.Envelope
+	if err := json.NewDecoder(in).Decode(&env); err != nil {
+		fmt.Fprintln(os.Stderr, "ship open: not an envelope")
+		return 1
+	}
+	plain, err := logship.Open(key, env)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ship open: cannot decrypt (wrong key or damaged envelope)")
+		return 1
+	}
+	_, err = out.Write(append(plain, '\n'))
+	if err != nil {
+		return 1
+	}
+	return 0
+}
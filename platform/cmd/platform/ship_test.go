@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/logship"
+)
+
+// TestShipOpen: a batch sealed by the platform opens with the reader's key,
+// and not without it.
+func TestShipOpen(t *testing.T) {
+	pub, priv, err := logship.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(priv+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	to, _ := logship.ParsePublicKey(pub)
+	env, _ := logship.Seal(to, []byte(`{"events":[]}`))
+	body, _ := json.Marshal(env)
+
+	var out bytes.Buffer
+	if code := runShipOpen([]string{"--key", keyFile}, bytes.NewReader(body), &out); code != 0 || out.String() != "{\"events\":[]}\n" {
+		t.Fatalf("code %d, out %q", code, out.String())
+	}
+	_, other, _ := logship.GenerateKey()
+	if err := os.WriteFile(keyFile, []byte(other), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code := runShipOpen([]string{"--key", keyFile}, bytes.NewReader(body), &out); code != 1 {
+		t.Fatalf("the wrong key: code %d", code)
+	}
+}
@@ -19,3 +19,7 @@ require (
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.11.0 // indirect
 )
+
+require github.com/eliteGoblin/focusd/shared v0.0.0
+
+replace github.com/eliteGoblin/focusd/shared => ../shared
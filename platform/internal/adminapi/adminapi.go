@@ -0,0 +1,504 @@
+// Package adminapi is the platform's admin API: JSON over HTTP for
+// dashboards, the future GUI and thin CLI clients, so they need not shell out
+// to `platform status` and parse it.
+//
+//	GET  /v1/health    liveness: version, uptime, overall verdict
+//	GET  /v1/status    the `platform status --json` report
+//	GET  /v1/history   recent runs across jobs (?limit=N, default 50, max 500)
+//	GET  /v1/policies  the enforced jobs from the signed embedded config
+//	POST /v1/scan      run every enabled job now (can only tighten);
+//	                   ?policy=ID runs that one job and waits for its result
+//	GET  /v1/events    the protection-event stream, one JSON event per line
+//	POST /v1/hold      extend the strict lock ({"seconds": N}; can only tighten)
+//
+// The unix socket alone also serves
+//
+//	POST /v1/clock     the daemon's trusted-clock skew ({"skew_s": N})
+//	GET  /v1/log-level the running log level and any open window
+//	POST /v1/log-level run at a level for a window ({"level": L, "for_s": N})
+//
+// The clock can move time gates either way, and a debug window makes the
+// log far louder, so the bearer token is not enough for either.
+// With Source.Debug set, the unix socket alone also serves
+//
+//	GET  /v1/runtime       goroutines, heap and GC counters (RuntimeStats)
+//	GET  /debug/pprof/...  the standard net/http/pprof profiles
+//
+// The daemon-managed `platform run` serves it on two listeners. One is a
+// unix socket beside state.db (SocketName, mode 0600: the file mode is the
+// authentication), which Client speaks. The other is optional loopback TCP,
+// enabled with `daemon api --listen`, where every request needs
+// "Authorization: Bearer <token>". Both carry the same redaction as status:
+// job ids, statuses, counts and times. Neither returns a path, plugin output
+// or error text.
+package adminapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+	"github.com/eliteGoblin/focusd/platform/internal/status"
+)
+
+// The daemon passes the listen address and bearer token in these variables.
+// Neutral names, matching the child's other APP_* keys, so `ps -E` shows
+// nothing product-specific.
+const (
+	AddrEnv  = "APP_ADMIN_ADDR"
+	TokenEnv = "APP_ADMIN_TOKEN"
+	// DebugEnv = "1" turns on the debug routes (`daemon api --debug on`).
+	DebugEnv = "APP_ADMIN_DEBUG"
+)
+
+const (
+	defaultHistory = 50
+	maxHistory     = 500
+)
+
+// Policy is one enforced job, as configured (see PoliciesFrom).
+type Policy struct {
+	ID           string `json:"id"`
+	Plugin       string `json:"plugin"`
+	Enabled      bool   `json:"enabled"`
+	Schedule     string `json:"schedule"`
+	Timeout      string `json:"timeout,omitempty"`
+	Retry        int    `json:"retry,omitempty"`
+	AllowOverlap bool   `json:"allow_overlap,omitempty"`
+	// Source is where the job was defined: SourceBuiltin or SourceConfig.
+	Source string `json:"source"`
+	// Options are the job's scalar config keys (bypass_mode, anchor, ...).
+	Options map[string]any `json:"options,omitempty"`
+	// Patterns are its list-valued config keys (domains, protectors, ...).
+	Patterns []Patterns `json:"patterns,omitempty"`
+	// Overlays name what can add to the job's config: "web" (always on),
+	// "session" and "profile:<name>".
+	Overlays []string `json:"overlays,omitempty"`
+	// Breakable and Pausable report whether a break token or a
+	// `platform pause` can stop the job.
+	Breakable bool `json:"breakable,omitempty"`
+	Pausable  bool `json:"pausable,omitempty"`
+	// Locked jobs stop only with a full uninstall.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// Run is one recorded run, without its free text (message, stdout, stderr
+// and error text can all carry paths).
+type Run struct {
+	ID         int64            `json:"id"`
+	Job        string           `json:"job"`
+	Plugin     string           `json:"plugin"`
+	Version    string           `json:"plugin_version,omitempty"`
+	Status     string           `json:"status"`
+	StartedAt  string           `json:"started_at"`
+	EndedAt    string           `json:"ended_at,omitempty"`
+	DurationMS int64            `json:"duration_ms"`
+	ExitCode   int              `json:"exit_code"`
+	TimedOut   bool             `json:"timed_out,omitempty"`
+	Trigger    string           `json:"trigger,omitempty"`
+	Actions    eventlog.Actions `json:"actions"`
+}
+
+// Health is the /v1/health body.
+type Health struct {
+	OK      bool           `json:"ok"`
+	Version string         `json:"version"`
+	UptimeS int64          `json:"uptime_s"`
+	Overall status.Verdict `json:"overall"`
+	// PolicySHA256 pins the policy set being enforced (see
+	// defaultconfig.Digest).
+	PolicySHA256 string `json:"policy_sha256,omitempty"`
+}
+
+// SocketName is the unix socket's basename beside state.db. Neutral, like
+// svc.log.
+const SocketName = "svc.sock"
+
+// Source is what the API reads. The funcs are called per request.
+type Source struct {
+	Version  string
+	Started  time.Time
+	Policies []Policy
+	// PolicySHA256 is the digest of the policy set Policies came from.
+	PolicySHA256 string
+	Status       func() status.Report
+	Recent       func(limit int) ([]state.JobRun, error)
+	// Scan fires every enabled job now and returns how many it fired.
+	Scan func() int
+	// ScanPolicy runs one enabled job now and waits for it; false when no
+	// enabled job has that id.
+	ScanPolicy func(id string) (PolicyResult, bool)
+	// Events subscribes to the event stream; the func cancels.
+	Events func() (<-chan eventlog.Event, func())
+	// Hold extends the strict lock to at least d from now.
+	Hold func(d time.Duration) error
+	// Clock records how far the system clock is ahead of the daemon's
+	// trusted one. nil leaves /v1/clock unserved.
+	Clock func(skew time.Duration) error
+	// Level is the running logger's level. nil leaves /v1/log-level
+	// unserved.
+	Level *logging.Level
+	// Debug adds the profiling routes to the unix socket. The TCP listener
+	// never serves them: a profile names source files and functions, and
+	// the socket's file mode is the stronger gate.
+	Debug bool
+}
+
+// RuntimeStats is the /v1/runtime body: enough to tell a leak from a busy
+// loop before reaching for a profile.
+type RuntimeStats struct {
+	Goroutines   int    `json:"goroutines"`
+	HeapAllocB   uint64 `json:"heap_alloc_bytes"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	SysB         uint64 `json:"sys_bytes"`
+	NumGC        uint32 `json:"num_gc"`
+	GCPauseTotal string `json:"gc_pause_total"`
+	UptimeS      int64  `json:"uptime_s"`
+}
+
+// HoldRequest is the /v1/hold body. The daemon, which owns the lock's
+// clock, posts the time left while the platform runs.
+type HoldRequest struct {
+	Seconds int64 `json:"seconds"`
+}
+
+// ClockRequest is the /v1/clock body: the daemon posts its trusted clock's
+// skew on every credit pass.
+type ClockRequest struct {
+	SkewS int64 `json:"skew_s"`
+}
+
+// LogLevel is the /v1/log-level body: the running level, the configured
+// one it falls back to and, while a window is open, when it closes.
+type LogLevel struct {
+	Level string `json:"level"`
+	Base  string `json:"base"`
+	Until string `json:"until,omitempty"`
+}
+
+// LogLevelRequest is the POST /v1/log-level body: run at Level for ForS
+// seconds. ForS 0 closes an open window now.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+	ForS  int64  `json:"for_s"`
+}
+
+// ScanResult is the /v1/scan body. Policy is set for a targeted scan.
+type ScanResult struct {
+	Triggered int           `json:"triggered"`
+	Policy    *PolicyResult `json:"policy,omitempty"`
+}
+
+// PolicyResult is how a targeted scan's run ended, redacted like Run.
+type PolicyResult struct {
+	Job        string           `json:"job"`
+	Status     string           `json:"status"`
+	DurationMS int64            `json:"duration_ms"`
+	ExitCode   int              `json:"exit_code"`
+	TimedOut   bool             `json:"timed_out,omitempty"`
+	Actions    eventlog.Actions `json:"actions"`
+}
+
+// ValidAddr accepts host:port where host is a loopback literal or
+// "localhost". The API has no TLS and must never be reachable off-box.
+func ValidAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return errors.New("address must be host:port")
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return errors.New("port must be a number")
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return errors.New("host must be a loopback address (127.0.0.1, ::1 or localhost)")
+	}
+	return nil
+}
+
+// Handler serves the API for src, admitting only requests bearing token.
+func Handler(token string, src Source) http.Handler {
+	return requireToken(token, routes(src))
+}
+
+func routes(src Source) http.Handler { return newMux(src) }
+
+// socketHandler is the unix socket's API: routes plus /v1/clock and, with
+// src.Debug, the debug routes.
+func socketHandler(src Source) http.Handler {
+	mux := newMux(src)
+	if src.Clock != nil {
+		mux.HandleFunc("POST /v1/clock", func(w http.ResponseWriter, r *http.Request) {
+			var req ClockRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+				http.Error(w, "skew_s must be an integer", http.StatusBadRequest)
+				return
+			}
+			if err := src.Clock(time.Duration(req.SkewS) * time.Second); err != nil {
+				http.Error(w, "clock not recorded", http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	if src.Level != nil {
+		levelRoutes(mux, src.Level)
+	}
+	if src.Debug {
+		debugRoutes(mux, src)
+	}
+	return mux
+}
+
+// levelRoutes adds /v1/log-level to mux.
+func levelRoutes(mux *http.ServeMux, lv *logging.Level) {
+	mux.HandleFunc("GET /v1/log-level", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, levelBody(lv))
+	})
+	mux.HandleFunc("POST /v1/log-level", func(w http.ResponseWriter, r *http.Request) {
+		var req LogLevelRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil || req.ForS < 0 {
+			http.Error(w, "level must be a string and for_s a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		level, ok := logging.ParseLevel(req.Level)
+		if !ok && req.ForS > 0 {
+			http.Error(w, "level must be debug, info, warn or error", http.StatusBadRequest)
+			return
+		}
+		if err := lv.Override(level, time.Duration(req.ForS)*time.Second); err != nil {
+			http.Error(w, "window longer than "+logging.MaxOverride.String(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, levelBody(lv))
+	})
+}
+
+func levelBody(lv *logging.Level) LogLevel {
+	out := LogLevel{Level: levelName(lv.Level()), Base: levelName(lv.Base())}
+	if u := lv.Until(); !u.IsZero() {
+		out.Until = u.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func levelName(l slog.Level) string { return strings.ToLower(l.String()) }
+
+func newMux(src Source) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", func(w http.ResponseWriter, r *http.Request) {
+		rep := src.Status()
+		writeJSON(w, Health{
+			OK:           rep.Overall == status.Healthy || rep.Overall == status.Unknown,
+			Version:      src.Version,
+			UptimeS:      int64(time.Since(src.Started) / time.Second),
+			Overall:      rep.Overall,
+			PolicySHA256: src.PolicySHA256,
+		})
+	})
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, src.Status())
+	})
+	mux.HandleFunc("GET /v1/policies", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, src.Policies)
+	})
+	mux.HandleFunc("GET /v1/history", func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultHistory
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = min(n, maxHistory)
+		}
+		runs, err := src.Recent(limit)
+		if err != nil {
+			http.Error(w, "history unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		out := make([]Run, 0, len(runs))
+		for _, run := range runs {
+			out = append(out, Run{
+				ID: run.ID, Job: run.JobID, Plugin: run.PluginID, Version: run.PluginVersion,
+				Status: run.Status, StartedAt: run.StartedAt, EndedAt: run.EndedAt,
+				DurationMS: run.DurationMS, ExitCode: run.ExitCode, TimedOut: run.TimedOut,
+				Trigger: run.TriggeredBy, Actions: eventlog.ParseActions(run.StdoutJSON),
+			})
+		}
+		writeJSON(w, out)
+	})
+	mux.HandleFunc("POST /v1/scan", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("policy")
+		if id == "" {
+			writeJSON(w, ScanResult{Triggered: src.Scan()})
+			return
+		}
+		res, ok := src.ScanPolicy(id)
+		if !ok {
+			http.Error(w, "no enabled policy with that id", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, ScanResult{Triggered: 1, Policy: &res})
+	})
+	mux.HandleFunc("POST /v1/hold", func(w http.ResponseWriter, r *http.Request) {
+		var req HoldRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil || req.Seconds < 1 {
+			http.Error(w, "seconds must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if err := src.Hold(time.Duration(req.Seconds) * time.Second); err != nil {
+			http.Error(w, "hold not recorded", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /v1/events", func(w http.ResponseWriter, r *http.Request) {
+		streamEvents(w, r, src.Events)
+	})
+	return mux
+}
+
+// debugRoutes adds /v1/runtime and the pprof profiles to mux.
+func debugRoutes(mux *http.ServeMux, src Source) {
+	mux.HandleFunc("GET /v1/runtime", func(w http.ResponseWriter, r *http.Request) {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		writeJSON(w, RuntimeStats{
+			Goroutines:   runtime.NumGoroutine(),
+			HeapAllocB:   m.HeapAlloc,
+			HeapObjects:  m.HeapObjects,
+			SysB:         m.Sys,
+			NumGC:        m.NumGC,
+			GCPauseTotal: time.Duration(m.PauseTotalNs).String(),
+			UptimeS:      int64(time.Since(src.Started) / time.Second),
+		})
+	})
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+}
+
+// streamEvents writes each new event as one JSON line, flushing as it goes,
+// until the client disconnects or the stream closes.
+func streamEvents(w http.ResponseWriter, r *http.Request, subscribe func() (<-chan eventlog.Event, func())) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	events, cancel := subscribe()
+	defer cancel()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, open := <-events:
+			if !open || enc.Encode(ev) != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// requireToken rejects any request without the bearer token, comparing in
+// constant time.
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// Serve listens on addr and serves the token-guarded API until ctx is done.
+// It refuses a non-loopback address or an empty token rather than serving
+// openly.
+func Serve(ctx context.Context, addr, token string, src Source, log *slog.Logger) error {
+	if err := ValidAddr(addr); err != nil {
+		return fmt.Errorf("admin api: %w", err)
+	}
+	if strings.TrimSpace(token) == "" {
+		return errors.New("admin api: no token")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("admin api: listen: %w", err)
+	}
+	log.Info("admin api listening", "addr", ln.Addr().String())
+	return serve(ctx, ln, Handler(token, src), log)
+}
+
+// ServeUnix serves the API on a unix socket at path until ctx is done. The
+// socket is chmod 0600 as soon as it is bound, so only the platform's own
+// user — and root — can connect; there is no token. A stale socket from a previous
+// run is replaced. path is never logged.
+func ServeUnix(ctx context.Context, path string, src Source, log *slog.Logger) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("admin socket: clear stale: %w", err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("admin socket: listen: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return fmt.Errorf("admin socket: chmod: %w", err)
+	}
+	log.Info("admin socket listening", "debug", src.Debug)
+	return serve(ctx, ln, socketHandler(src), log)
+}
+
+func serve(ctx context.Context, ln net.Listener, h http.Handler, log *slog.Logger) error {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 5 * time.Second,
+		ErrorLog:          slog.NewLogLogger(log.Handler(), slog.LevelDebug),
+	}
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(sctx)
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("admin api: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+	"github.com/eliteGoblin/focusd/platform/internal/status"
+)
+
+func testSource(limits *[]int) Source {
+	return Source{
+		Version:      "v1.2.0",
+		Started:      time.Now().Add(-time.Minute),
+		Policies:     []Policy{{ID: "kill-steam-reconcile", Plugin: "kill-steam", Enabled: true, Schedule: "@every 10s"}},
+		PolicySHA256: "ab12",
+		Status: func() status.Report {
+			return status.Report{Mode: "user", Overall: status.Healthy}
+		},
+		Recent: func(limit int) ([]state.JobRun, error) {
+			*limits = append(*limits, limit)
+			return []state.JobRun{{ID: 7, JobID: "kill-steam-reconcile", PluginID: "kill-steam",
+				Status: state.RunStatusOK, Message: "removed /Applications/Steam.app",
+				StdoutJSON: `{"details":{"killed_count":2}}`}}, nil
+		},
+	}
+}
+
+func get(t *testing.T, h http.Handler, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	var limits []int
+	h := Handler("s3cret", testSource(&limits))
+	for _, tok := range []string{"", "wrong"} {
+		if rec := get(t, h, "/v1/status", tok); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("token %q: code %d, want 401", tok, rec.Code)
+		}
+	}
+	if rec := get(t, Handler("", testSource(&limits)), "/v1/status", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("empty configured token must admit nobody, got %d", rec.Code)
+	}
+}
+
+func TestHistoryIsRedactedAndBounded(t *testing.T) {
+	var limits []int
+	h := Handler("s3cret", testSource(&limits))
+	rec := get(t, h, "/v1/history?limit=9999", "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("code %d: %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "/Applications") {
+		t.Fatalf("history leaked run free text:\n%s", rec.Body)
+	}
+	var runs []Run
+	if err := json.Unmarshal(rec.Body.Bytes(), &runs); err != nil || len(runs) != 1 {
+		t.Fatalf("decode: %v (%d runs)", err, len(runs))
+	}
+	if runs[0].Actions.Kills != 2 || limits[0] != maxHistory {
+		t.Fatalf("kills = %d, limit passed = %v", runs[0].Actions.Kills, limits)
+	}
+	if rec := get(t, h, "/v1/history?limit=0", "s3cret"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("limit=0: code %d, want 400", rec.Code)
+	}
+}
+
+func TestHealthAndPolicies(t *testing.T) {
+	var limits []int
+	h := Handler("s3cret", testSource(&limits))
+	var hl Health
+	if err := json.Unmarshal(get(t, h, "/v1/health", "s3cret").Body.Bytes(), &hl); err != nil {
+		t.Fatal(err)
+	}
+	if !hl.OK || hl.Version != "v1.2.0" || hl.UptimeS < 59 || hl.PolicySHA256 != "ab12" {
+		t.Fatalf("health = %+v", hl)
+	}
+	var ps []Policy
+	if err := json.Unmarshal(get(t, h, "/v1/policies", "s3cret").Body.Bytes(), &ps); err != nil || len(ps) != 1 {
+		t.Fatalf("policies = %v, err %v", ps, err)
+	}
+}
+
+func TestPoliciesFromSplitsOptionsAndPatterns(t *testing.T) {
+	cfg, err := config.Parse([]byte(`
+jobs:
+  - id: net
+    plugin: network-block
+    enabled: true
+    schedule: "@every 30m"
+    timeout: 60s
+    config:
+      anchor: a
+      domains: [steampowered.com, steamcommunity.com]
+profiles:
+  work:
+    jobs:
+      net:
+        domains: [reddit.com]
+breaks:
+  per_week: 1
+  length: 10m
+  jobs: [net]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := PoliciesFrom(cfg, SourceConfig)
+	if len(ps) != 1 {
+		t.Fatalf("policies = %+v", ps)
+	}
+	p := ps[0]
+	if p.Source != SourceConfig || p.Timeout != "1m0s" || p.Options["anchor"] != "a" || !p.Breakable || p.Pausable {
+		t.Fatalf("policy = %+v", p)
+	}
+	if len(p.Patterns) != 1 || p.Patterns[0].Kind != "domains" || len(p.Patterns[0].Values) != 2 {
+		t.Fatalf("patterns = %+v", p.Patterns)
+	}
+	if len(p.Overlays) != 1 || p.Overlays[0] != "profile:work" {
+		t.Fatalf("overlays = %v", p.Overlays)
+	}
+}
+
+func TestScanNeedsPOST(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	fired := 0
+	src.Scan = func() int { fired++; return 4 }
+	h := Handler("s3cret", src)
+	if rec := get(t, h, "/v1/scan", "s3cret"); rec.Code != http.StatusMethodNotAllowed || fired != 0 {
+		t.Fatalf("GET /v1/scan: code %d, fired %d", rec.Code, fired)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/scan", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var res ScanResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || res.Triggered != 4 || fired != 1 {
+		t.Fatalf("POST /v1/scan = %s (fired %d)", rec.Body, fired)
+	}
+}
+
+func TestScanPolicyRunsOneJob(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	var ran []string
+	src.ScanPolicy = func(id string) (PolicyResult, bool) {
+		if id != "kill-steam-reconcile" {
+			return PolicyResult{}, false
+		}
+		ran = append(ran, id)
+		return PolicyResult{Job: id, Status: state.RunStatusOK, Actions: eventlog.Actions{Kills: 1}}, true
+	}
+	h := Handler("s3cret", src)
+	post := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/scan"+query, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	var res ScanResult
+	rec := post("?policy=kill-steam-reconcile")
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || res.Policy == nil || res.Policy.Actions.Kills != 1 {
+		t.Fatalf("targeted scan = %d %s", rec.Code, rec.Body)
+	}
+	if rec := post("?policy=nope"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown policy: code %d, want 404", rec.Code)
+	}
+	if len(ran) != 1 {
+		t.Fatalf("ran = %v", ran)
+	}
+}
+
+func TestHoldTakesPositiveSeconds(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	var held []time.Duration
+	src.Hold = func(d time.Duration) error { held = append(held, d); return nil }
+	h := Handler("s3cret", src)
+	for body, code := range map[string]int{
+		`{"seconds":3600}`: http.StatusNoContent,
+		`{"seconds":0}`:    http.StatusBadRequest,
+		`not json`:         http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/hold", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != code {
+			t.Errorf("%s: code %d, want %d", body, rec.Code, code)
+		}
+	}
+	if len(held) != 1 || held[0] != time.Hour {
+		t.Fatalf("held = %v", held)
+	}
+}
+
+func TestClockOnlyOnTheSocket(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	var skews []time.Duration
+	src.Clock = func(d time.Duration) error { skews = append(skews, d); return nil }
+	post := func(h http.Handler, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/clock", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := post(Handler("s3cret", src), `{"skew_s":-60}`); code == http.StatusNoContent {
+		t.Fatal("the TCP listener must not take a clock skew")
+	}
+	if code := post(socketHandler(src), `{"skew_s":-60}`); code != http.StatusNoContent {
+		t.Fatalf("socket: code %d", code)
+	}
+	if code := post(socketHandler(src), `nope`); code != http.StatusBadRequest {
+		t.Fatalf("bad body: code %d", code)
+	}
+	if len(skews) != 1 || skews[0] != -time.Minute {
+		t.Fatalf("skews = %v", skews)
+	}
+}
+
+func TestLogLevelOnlyOnTheSocket(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	src.Level = logging.NewLevel("info")
+	post := func(h http.Handler, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/log-level", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := post(Handler("s3cret", src), `{"level":"debug","for_s":60}`); code == http.StatusOK {
+		t.Fatal("the TCP listener must not move the log level")
+	}
+	for body, code := range map[string]int{
+		`{"level":"loud","for_s":60}`:   http.StatusBadRequest,
+		`{"level":"debug","for_s":-1}`:  http.StatusBadRequest,
+		`{"level":"debug","for_s":1e6}`: http.StatusBadRequest,
+		`{"level":"debug","for_s":600}`: http.StatusOK,
+	} {
+		if got := post(socketHandler(src), body); got != code {
+			t.Errorf("%s: code %d, want %d", body, got, code)
+		}
+	}
+	if src.Level.Level() != slog.LevelDebug {
+		t.Fatalf("level = %v, want debug", src.Level.Level())
+	}
+}
+
+func TestValidAddrIsLoopbackOnly(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:7600": true,
+		"[::1]:7600":     true,
+		"localhost:7600": true,
+		"0.0.0.0:7600":   false,
+		"10.0.0.5:7600":  false,
+		":7600":          false,
+		"127.0.0.1":      false,
+		"127.0.0.1:http": false,
+	} {
+		if err := ValidAddr(addr); (err == nil) != ok {
+			t.Errorf("ValidAddr(%q) = %v, want ok=%v", addr, err, ok)
+		}
+	}
+}
+
+func TestDebugRoutesOnlyOnTheSocketWhenEnabled(t *testing.T) {
+	var limits []int
+	src := testSource(&limits)
+	if rec := get(t, socketHandler(src), "/v1/runtime", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("debug off: /v1/runtime code %d, want 404", rec.Code)
+	}
+	src.Debug = true
+	if rec := get(t, Handler("s3cret", src), "/debug/pprof/", "s3cret"); rec.Code != http.StatusNotFound {
+		t.Fatalf("the TCP handler must never serve pprof, got %d", rec.Code)
+	}
+	h := socketHandler(src)
+	rec := get(t, h, "/v1/runtime", "")
+	var rs RuntimeStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &rs); err != nil || rs.Goroutines < 1 || rs.HeapAllocB == 0 {
+		t.Fatalf("runtime = %+v, err %v (%d)", rs, err, rec.Code)
+	}
+	if rec := get(t, h, "/debug/pprof/goroutine?debug=1", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "goroutine profile") {
+		t.Fatalf("pprof goroutine: %d\n%.200s", rec.Code, rec.Body.String())
+	}
+	if rec := get(t, h, "/v1/status", ""); rec.Code != http.StatusOK {
+		t.Fatalf("debug must keep the normal routes, got %d", rec.Code)
+	}
+}
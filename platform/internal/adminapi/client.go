@@ -0,0 +1,195 @@
+package adminapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/status"
+)
+
+// ErrUnavailable is returned when nothing is listening on the socket — the
+// platform is not running (or predates the socket).
+var ErrUnavailable = errors.New("admin socket unavailable")
+
+// ErrNoPolicy is ScanPolicy's answer for an id with no enabled job.
+var ErrNoPolicy = errors.New("no enabled policy with that id")
+
+// Client is the typed client for the unix-socket API, for CLIs and tools on
+// the same machine.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a client for the socket at path.
+func NewClient(path string) *Client {
+	d := net.Dialer{Timeout: dialTimeout}
+	return &Client{http: &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}}
+}
+
+// Status is GET /v1/status.
+func (c *Client) Status(ctx context.Context) (status.Report, error) {
+	var rep status.Report
+	return rep, c.getJSON(ctx, "/v1/status", &rep)
+}
+
+// Health is GET /v1/health.
+func (c *Client) Health(ctx context.Context) (Health, error) {
+	var h Health
+	return h, c.getJSON(ctx, "/v1/health", &h)
+}
+
+// Policies is GET /v1/policies.
+func (c *Client) Policies(ctx context.Context) ([]Policy, error) {
+	var ps []Policy
+	return ps, c.getJSON(ctx, "/v1/policies", &ps)
+}
+
+// History is GET /v1/history?limit=limit.
+func (c *Client) History(ctx context.Context, limit int) ([]Run, error) {
+	var runs []Run
+	return runs, c.getJSON(ctx, "/v1/history?limit="+strconv.Itoa(limit), &runs)
+}
+
+// Scan is POST /v1/scan: run every enabled job now.
+func (c *Client) Scan(ctx context.Context) (int, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/v1/scan")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	var r ScanResult
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return 0, fmt.Errorf("decode scan: %w", err)
+	}
+	return r.Triggered, nil
+}
+
+// ScanPolicy is POST /v1/scan?policy=id: run one job now and wait for how
+// it ended.
+func (c *Client) ScanPolicy(ctx context.Context, id string) (PolicyResult, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/v1/scan?policy="+url.QueryEscape(id))
+	if errors.Is(err, errNotFound) {
+		return PolicyResult{}, ErrNoPolicy
+	}
+	if err != nil {
+		return PolicyResult{}, err
+	}
+	defer resp.Body.Close()
+	var r ScanResult
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return PolicyResult{}, fmt.Errorf("decode scan: %w", err)
+	}
+	if r.Policy == nil {
+		return PolicyResult{}, errors.New("decode scan: no policy result")
+	}
+	return *r.Policy, nil
+}
+
+// Events streams GET /v1/events, calling fn per event until ctx is done, the
+// platform closes the stream, or fn returns an error (which is returned).
+func (c *Client) Events(ctx context.Context, fn func(eventlog.Event) error) error {
+	resp, err := c.do(ctx, http.MethodGet, "/v1/events")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var ev eventlog.Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return sc.Err()
+}
+
+// LogLevel is GET /v1/log-level.
+func (c *Client) LogLevel(ctx context.Context) (LogLevel, error) {
+	var l LogLevel
+	return l, c.getJSON(ctx, "/v1/log-level", &l)
+}
+
+// SetLogLevel is POST /v1/log-level: run at level for d, after which the
+// platform falls back to its configured level. d 0 closes the window now.
+func (c *Client) SetLogLevel(ctx context.Context, level string, d time.Duration) (LogLevel, error) {
+	body, _ := json.Marshal(LogLevelRequest{Level: level, ForS: int64(d / time.Second)})
+	resp, err := c.send(ctx, http.MethodPost, "/v1/log-level", bytes.NewReader(body))
+	if err != nil {
+		return LogLevel{}, err
+	}
+	defer resp.Body.Close()
+	var l LogLevel
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return LogLevel{}, fmt.Errorf("decode log level: %w", err)
+	}
+	return l, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	resp, err := c.do(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// do sends one request. The host is a placeholder: the transport always
+// dials the socket. A refused or missing socket reads as ErrUnavailable.
+func (c *Client) do(ctx context.Context, method, path string) (*http.Response, error) {
+	return c.send(ctx, method, path, nil)
+}
+
+// send is do with a request body.
+func (c *Client) send(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, "http://platform"+path, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var op *net.OpError
+		if errors.As(err, &op) && op.Op == "dial" {
+			return nil, ErrUnavailable
+		}
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errNotFound
+		}
+		return nil, fmt.Errorf("admin api: HTTP %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// errNotFound is an HTTP 404, which callers map to their own error.
+var errNotFound = errors.New("admin api: HTTP 404")
+
+// dialTimeout bounds how long a CLI waits for a socket that is not there.
+const dialTimeout = 2 * time.Second
@@ -0,0 +1,107 @@
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+)
+
+// shortSocket returns a socket path under a short temp dir: macOS caps unix
+// socket paths near 104 bytes, which t.TempDir() can exceed.
+func shortSocket(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "aa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, SocketName)
+}
+
+func TestClientOverUnixSocket(t *testing.T) {
+	path := shortSocket(t)
+	elog := eventlog.New(t.TempDir())
+	var limits []int
+	src := testSource(&limits)
+	src.Scan = func() int { return 3 }
+	src.ScanPolicy = func(id string) (PolicyResult, bool) {
+		return PolicyResult{Job: id, Status: "ok"}, id == "j1"
+	}
+	src.Events = elog.Subscribe
+	src.Level = logging.NewLevel("info")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ServeUnix(ctx, path, src, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	c := NewClient(path)
+	var err error
+	for i := 0; i < 100; i++ { // wait for the listener
+		if _, err = c.Health(ctx); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("health: %v", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("socket mode = %v (err %v), want 0600", fi.Mode().Perm(), err)
+	}
+	if rep, err := c.Status(ctx); err != nil || rep.Mode != "user" {
+		t.Fatalf("status = %+v, err %v", rep, err)
+	}
+	if n, err := c.Scan(ctx); err != nil || n != 3 {
+		t.Fatalf("scan = %d, err %v", n, err)
+	}
+	if res, err := c.ScanPolicy(ctx, "j1"); err != nil || res.Job != "j1" {
+		t.Fatalf("scan policy = %+v, err %v", res, err)
+	}
+	if _, err := c.ScanPolicy(ctx, "nope"); !errors.Is(err, ErrNoPolicy) {
+		t.Fatalf("unknown policy: err %v, want ErrNoPolicy", err)
+	}
+	if l, err := c.SetLogLevel(ctx, "debug", 10*time.Minute); err != nil || l.Level != "debug" || l.Base != "info" || l.Until == "" {
+		t.Fatalf("set log level = %+v, err %v", l, err)
+	}
+	if l, err := c.SetLogLevel(ctx, "", 0); err != nil || l.Level != "info" || l.Until != "" {
+		t.Fatalf("reset log level = %+v, err %v", l, err)
+	}
+	if l, err := c.LogLevel(ctx); err != nil || l.Level != "info" {
+		t.Fatalf("log level = %+v, err %v", l, err)
+	}
+
+	got := make(chan eventlog.Event, 1)
+	sctx, stop := context.WithCancel(ctx)
+	go c.Events(sctx, func(ev eventlog.Event) error { got <- ev; return nil })
+	deadline := time.After(3 * time.Second)
+	for done := false; !done; {
+		// Append until the stream (subscribed asynchronously) carries one.
+		_ = elog.Append(eventlog.Event{Type: eventlog.TypeRunFailed, Job: "j1"})
+		select {
+		case ev := <-got:
+			if ev.Job != "j1" {
+				t.Fatalf("streamed %+v", ev)
+			}
+			done = true
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no event streamed")
+		}
+	}
+	stop()
+}
+
+func TestClientWithoutSocketIsUnavailable(t *testing.T) {
+	c := NewClient(shortSocket(t))
+	if _, err := c.Status(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,69 @@
+package adminapi
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+)
+
+// Policy sources.
+const (
+	// SourceBuiltin is the signed config embedded in the platform binary,
+	// the only source on the daemon-managed run path.
+	SourceBuiltin = "builtin"
+	// SourceConfig is a config file named on the command line, for
+	// inspecting a draft (`platform policies --config`).
+	SourceConfig = "config"
+)
+
+// Patterns is one list-valued config key of a job and its entries, e.g.
+// network-block's domains.
+type Patterns struct {
+	Kind   string   `json:"kind"`
+	Values []string `json:"values"`
+}
+
+// PoliciesFrom describes cfg's jobs, in config order, as defined by source.
+// Keys come out sorted so the output is stable.
+func PoliciesFrom(cfg *config.Config, source string) []Policy {
+	profiles := slices.Sorted(maps.Keys(cfg.Profiles))
+	out := make([]Policy, 0, len(cfg.Jobs))
+	for _, j := range cfg.Jobs {
+		p := Policy{ID: j.ID, Plugin: j.Plugin, Enabled: j.Enabled, Schedule: j.Schedule,
+			Retry: j.Retry, AllowOverlap: j.AllowOverlap, Source: source,
+			Breakable: cfg.Breaks.Breakable(j.ID), Pausable: cfg.Pause.Pausable(j.ID), Locked: j.Locked}
+		if t := j.Timeout.Std(); t > 0 {
+			p.Timeout = t.String()
+		}
+		for _, k := range slices.Sorted(maps.Keys(j.Config)) {
+			list, ok := j.Config[k].([]any)
+			if !ok {
+				if p.Options == nil {
+					p.Options = map[string]any{}
+				}
+				p.Options[k] = j.Config[k]
+				continue
+			}
+			values := make([]string, len(list))
+			for i, v := range list {
+				values[i] = fmt.Sprint(v)
+			}
+			p.Patterns = append(p.Patterns, Patterns{Kind: k, Values: values})
+		}
+		if len(cfg.WebOverlay(j).Jobs) > 0 {
+			p.Overlays = append(p.Overlays, "web")
+		}
+		if len(cfg.Session.Jobs[j.ID]) > 0 {
+			p.Overlays = append(p.Overlays, "session")
+		}
+		for _, name := range profiles {
+			if len(cfg.Profiles[name].Jobs[j.ID]) > 0 {
+				p.Overlays = append(p.Overlays, "profile:"+name)
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
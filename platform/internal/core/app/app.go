@@ -5,20 +5,28 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/eliteGoblin/focusd/platform/internal/bundle"
+	"github.com/eliteGoblin/focusd/platform/internal/core/calendar"
 	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
 	"github.com/eliteGoblin/focusd/platform/internal/core/logging"
+	"github.com/eliteGoblin/focusd/platform/internal/core/logship"
 	"github.com/eliteGoblin/focusd/platform/internal/core/plugin"
+	"github.com/eliteGoblin/focusd/platform/internal/core/power"
 	"github.com/eliteGoblin/focusd/platform/internal/core/runner"
 	"github.com/eliteGoblin/focusd/platform/internal/core/scheduler"
 	"github.com/eliteGoblin/focusd/platform/internal/core/snapshot"
 	"github.com/eliteGoblin/focusd/platform/internal/core/state"
 	"github.com/eliteGoblin/focusd/platform/internal/osadapter"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 // bundleVerifier adapts the bundle package to both integrity seams: the
@@ -68,6 +76,13 @@ type Options struct {
 	// DefaultLogDir is ever opened. Zero value (dev/direct-run) keeps the
 	// on-disk log for ergonomics.
 	NoLogFile bool
+	// LogLevel / LogFormat override the config's log level and pick the
+	// encoding (`daemon logging`, via logging.LevelEnv / FormatEnv). "" ⇒
+	// the config's level, text.
+	LogLevel  string
+	LogFormat string
+	// LogSink adds a sink beside the file (logging.SinkEnv); "" ⇒ none.
+	LogSink string
 }
 
 // App holds the wired runtime dependencies.
@@ -76,7 +91,16 @@ type App struct {
 	Mode    osadapter.RunMode
 	Config  *config.Config
 	State   *state.DB
-	Log     *slog.Logger
+	// DBPath is the resolved state.db path; the status snapshot sits beside
+	// it. Never logged (it is the disguised workdir).
+	DBPath string
+	Log    *slog.Logger
+	// LogLevel is Log's level, which the admin socket can move for a
+	// window (`daemon log-level`).
+	LogLevel *logging.Level
+	// Tracer exports enforcement-run spans to the OTLP endpoint named by the
+	// standard OTEL_EXPORTER_OTLP_* variables. nil (tracing off) when unset.
+	Tracer *tracing.Tracer
 
 	pluginDir string // resolved scan dir (override or adapter default)
 	logClose  func() error
@@ -85,6 +109,212 @@ type App struct {
 	// `platform status` reads run-state from this tiny atomic file instead of
 	// contending with the constantly-writing live DB. nil for in-memory DBs.
 	snap *snapshot.Store
+	// events is the JSONL protection-event stream beside state.db (package
+	// eventlog). nil for in-memory DBs.
+	events *eventlog.Log
+	// strictUntil is the strict lock's end on the monotonic clock (see
+	// HoldStrictLock), so setting the wall clock forward cannot end it.
+	strictMu    sync.Mutex
+	strictUntil time.Time
+	// skew is how far the system clock is ahead of the daemon's trusted
+	// clock (see SetClockSkew); sessions, breaks and pauses are timed
+	// against now(), which takes it off.
+	skewMu sync.Mutex
+	skew   time.Duration
+	// calendar watches the user's calendar for entries that switch
+	// Config.Calendar.Profile on (see StartCalendar). nil when off.
+	calendar *calendar.Watcher
+	// power probes battery and thermal state for cadence backoff, and
+	// idleness when backoff.idle is on (see StartPower). nil when
+	// platform.backoff is off.
+	power *power.Watcher
+	// sched is the scheduler BuildScheduler made, for the full pass when
+	// the user comes back to an idle machine.
+	sched *scheduler.Scheduler
+}
+
+// StrictLockEnv carries the strict lock's remaining whole seconds from the
+// daemon (`daemon lock`). Neutral, like the other APP_* keys.
+const StrictLockEnv = "APP_HOLD_S"
+
+// ClockSkewEnv carries how many whole seconds the system clock is ahead of
+// the daemon's trusted clock, as of the platform's start.
+const ClockSkewEnv = "APP_SKEW_S"
+
+// SetClockSkew records that the system clock is skew ahead of the daemon's
+// trusted clock, here and in state.db, where the CLI's session, break and
+// pause commands read it.
+func (a *App) SetClockSkew(skew time.Duration) error {
+	a.skewMu.Lock()
+	a.skew = skew
+	a.skewMu.Unlock()
+	return a.State.Clock.SetSkew(skew)
+}
+
+// now is the trusted time: the system clock less the daemon's last
+// reported skew.
+func (a *App) now() time.Time {
+	a.skewMu.Lock()
+	defer a.skewMu.Unlock()
+	return time.Now().Add(-a.skew)
+}
+
+// HoldStrictLock refuses breaks and pauses for at least d from now and records the end
+// in state.db, where `platform break` reads it. It never shortens the lock.
+// The in-process deadline keeps its monotonic reading; the recorded copy is
+// wall time.
+func (a *App) HoldStrictLock(d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	a.strictMu.Lock()
+	if until := time.Now().Add(d); until.After(a.strictUntil) {
+		a.strictUntil = until
+	}
+	until := a.strictUntil
+	a.strictMu.Unlock()
+	return a.State.Strict.Hold(until)
+}
+
+// StartCalendar watches the ICS calendar at url until ctx is done, when the
+// config names a calendar profile; otherwise it does nothing. Call it before
+// the scheduler starts.
+func (a *App) StartCalendar(ctx context.Context, url string) {
+	cal := a.Config.Calendar
+	if url == "" || cal.Profile == "" {
+		return
+	}
+	a.calendar = &calendar.Watcher{URL: url, Tag: cal.Tag, Poll: cal.PollEvery(), Log: a.Log,
+		OnChange: a.calendarChanged}
+	go a.calendar.Run(ctx)
+}
+
+// StartLogShip ships the event stream, sealed to key, to url until ctx is
+// done (package logship). Without both it does nothing; a bad key is
+// logged and shipping stays off.
+func (a *App) StartLogShip(ctx context.Context, url, key string) {
+	if url == "" || key == "" {
+		return
+	}
+	to, err := logship.ParsePublicKey(key)
+	if err != nil || !logship.ValidURL(url) {
+		a.Log.Warn("event shipping off: bad URL or key")
+		return
+	}
+	events, cancel := a.events.Subscribe()
+	s := &logship.Shipper{URL: url, Key: to, Log: a.Log}
+	go func() {
+		defer cancel()
+		s.Run(ctx, events)
+	}()
+}
+
+// calendarChanged logs and audits which calendar entry switched the
+// calendar profile on, and when it ended.
+func (a *App) calendarChanged(e calendar.Entry, on bool) {
+	b := state.CalendarBlock{Profile: a.Config.Calendar.Profile, Entry: e.Summary, EndsAt: e.End.UTC()}
+	if on {
+		a.Log.Info("calendar profile on", "profile", b.Profile, "entry", e.Summary, "until", e.End.Format(time.RFC3339))
+	} else {
+		a.Log.Info("calendar profile off", "profile", b.Profile, "entry", e.Summary)
+	}
+	if err := a.State.Events.RecordCalendarProfile(b, on); err != nil {
+		a.Log.Warn("calendar event not recorded", "err", fmt.Sprintf("%T", err))
+	}
+}
+
+// MarkStarted records this run's start, warning first when the previous
+// run did not shut down cleanly: it crashed, was SIGKILLed, or exited with
+// runs still in flight, so its last rows may be missing.
+func (a *App) MarkStarted() {
+	if clean, known, err := a.State.Events.PreviousShutdown(); err == nil && known && !clean {
+		a.Log.Warn("previous run did not shut down cleanly")
+		_ = a.State.Events.Record(state.SeverityWarn, "unclean_shutdown",
+			"previous platform run did not shut down cleanly", "")
+	}
+	if err := a.State.Events.RecordStarted(); err != nil {
+		a.Log.Warn("start not recorded", "err", fmt.Sprintf("%T", err))
+	}
+}
+
+// MarkStopped records the clean-shutdown flag; drained is Shutdown's.
+func (a *App) MarkStopped(drained bool) {
+	if err := a.State.Events.RecordStopped(drained); err != nil {
+		a.Log.Warn("stop not recorded", "err", fmt.Sprintf("%T", err))
+	}
+}
+
+// StartPower watches the power source and thermal state until ctx is done,
+// when platform.backoff is on, and idleness when backoff.idle is; otherwise
+// it does nothing. A backoff left recorded by a previous run is closed
+// first, so status never reports a reduced cadence from before this start.
+func (a *App) StartPower(ctx context.Context) {
+	b := a.Config.Platform.Backoff
+	if !b.Watched() {
+		return
+	}
+	if r, err := a.State.Events.ReducedCadence(); err == nil && r != "" {
+		_ = a.State.Events.RecordCadence("")
+	}
+	a.power = &power.Watcher{OnChange: a.powerChanged}
+	if !b.Enabled() {
+		// Idle only: battery and heat back nothing off, so skip pmset.
+		a.power.Probe = func(context.Context) power.State { return power.State{} }
+	}
+	if b.Idle {
+		a.power.IdleProbe, a.power.OnReturn = power.ReadIdle, a.userReturned
+	}
+	go a.power.Run(ctx)
+}
+
+// powerChanged logs and audits cadence backing off and recovering.
+func (a *App) powerChanged(s power.State) {
+	if s.Reason() != "" {
+		a.Log.Info("cadence backing off", "reason", s.Reason(), "factor", a.Config.Platform.Backoff.Factor)
+	} else {
+		a.Log.Info("cadence back to normal")
+	}
+	if err := a.State.Events.RecordCadence(s.Reason()); err != nil {
+		a.Log.Warn("cadence event not recorded", "err", fmt.Sprintf("%T", err))
+	}
+}
+
+// constrained reports whether the last power probe asked for backoff.
+func (a *App) constrained() bool {
+	return a.power != nil && a.power.Current().Constrained()
+}
+
+// idle reports whether the last probe found nobody at the machine.
+func (a *App) idle() bool {
+	return a.power != nil && a.power.Current().Idle
+}
+
+// userReturned runs every job at once when an idle machine is in use
+// again, so whatever started while cadence was down is dealt with now.
+func (a *App) userReturned() {
+	if a.sched == nil {
+		return
+	}
+	n := a.sched.RunNow()
+	a.Log.Info("user back; full pass", "jobs", n)
+}
+
+// calendarProfiles is the calendar's profile while a tagged entry is on.
+func (a *App) calendarProfiles(now time.Time) []string {
+	if a.calendar == nil {
+		return nil
+	}
+	if _, on := a.calendar.Current(now); !on {
+		return nil
+	}
+	return []string{a.Config.Calendar.Profile}
+}
+
+// strictLocked reports whether the strict lock holds now.
+func (a *App) strictLocked() bool {
+	a.strictMu.Lock()
+	defer a.strictMu.Unlock()
+	return time.Now().Before(a.strictUntil)
 }
 
 // Bootstrap resolves the runtime in strict order: adapter → run mode →
@@ -164,7 +394,12 @@ func Bootstrap(opts Options) (*App, error) {
 	if opts.NoLogFile {
 		logDir = ""
 	}
-	log, logClose, err := logging.New(cfg.Platform.LogLevel, logDir)
+	level := cfg.Platform.LogLevel
+	if opts.LogLevel != "" {
+		level = opts.LogLevel
+	}
+	logLevel := logging.NewLevel(level)
+	log, logClose, err := logging.New(logLevel, opts.LogFormat, opts.LogSink, logDir)
 	if err != nil {
 		return nil, err
 	}
@@ -187,8 +422,10 @@ func Bootstrap(opts Options) (*App, error) {
 	// Status snapshot lives next to state.db in the workdir. Skip it for an
 	// in-memory DB (no real directory) — the snapshot is a no-op there.
 	var snap *snapshot.Store
+	var events *eventlog.Log
 	if dbPath != ":memory:" {
 		snap = snapshot.NewStore(filepath.Dir(dbPath))
+		events = eventlog.New(filepath.Dir(dbPath))
 	}
 
 	// Redaction (FEATURE 24 / HF-disguise): drop the config + state_db fields —
@@ -213,11 +450,15 @@ func Bootstrap(opts Options) (*App, error) {
 		Adapter:   adapter,
 		Mode:      mode,
 		Config:    cfg,
+		DBPath:    dbPath,
 		State:     db,
 		Log:       log,
+		LogLevel:  logLevel,
+		Tracer:    tracing.FromEnv("focusd-platform", log),
 		pluginDir: pluginDir,
 		logClose:  logClose,
 		snap:      snap,
+		events:    events,
 	}, nil
 }
 
@@ -323,9 +564,31 @@ func (a *App) BuildScheduler() (*scheduler.Scheduler, int, error) {
 	run := runner.NewWithMode(a.State, a.Mode).
 		WithVerifier(bundleVerifier{}).
 		WithLogger(a.Log).
-		WithSnapshot(a.snap)
+		WithSnapshot(a.snap).
+		WithEventLog(a.events).
+		WithTracer(a.Tracer)
 	s := scheduler.New(run, a.State, a.Log, a.Mode).
-		WithSnapshot(a.snap)
+		WithSnapshot(a.snap).
+		WithOverlay(a.jobConfig).
+		WithPause(a.onBreak).
+		WithBackoff(a.Config.Platform.Backoff, a.constrained).
+		WithIdle(a.idle)
+	a.sched = s
+	// A release that changed the policy shows up here, once: state.db holds
+	// the policy the previous start applied. A job whose plugin is missing
+	// is reported as unavailable, not as a change on every start.
+	if d, err := s.AuditPolicy(a.Config.Jobs, byID); err != nil {
+		a.Log.Warn("policy audit failed", "err", fmt.Sprintf("%T", err))
+	} else {
+		if !d.Empty() {
+			a.Log.Info("policy changed", "added", len(d.Added), "changed", len(d.Changed), "removed", len(d.Removed))
+			_ = a.events.Append(eventlog.Event{Type: eventlog.TypePolicyChanged,
+				Reason: fmt.Sprintf("+%d ~%d -%d", len(d.Added), len(d.Changed), len(d.Removed))})
+		}
+		if len(d.Unavailable) > 0 {
+			a.Log.Warn("policy jobs unavailable", "count", len(d.Unavailable))
+		}
+	}
 	n, err := s.Register(a.Config.Jobs, byID)
 	if err != nil {
 		return nil, 0, err
@@ -344,9 +607,56 @@ func (a *App) BuildScheduler() (*scheduler.Scheduler, int, error) {
 		}); err != nil {
 		return nil, 0, err
 	}
+	// state.db maintenance: trims run history and events to their caps and
+	// compacts the file, in an idle window at most once a day.
+	if err := s.RegisterMaintenance(state.DefaultLimits); err != nil {
+		return nil, 0, err
+	}
 	return s, n, nil
 }
 
+// jobConfig is the config j runs with this tick: its own, plus the overlays
+// of the profiles whose windows are open or whose calendar entry is on and,
+// during a focus session, the session's. A failed session read runs without the session overlay —
+// overlays only ever add to the base policy.
+func (a *App) jobConfig(j config.Job) map[string]any {
+	now := a.now()
+	_, session, err := a.State.Sessions.Active(now)
+	if err != nil {
+		a.Log.Warn("session lookup failed; running without session overlay", "job", j.ID, "err", fmt.Sprintf("%T", err))
+	}
+	return a.Config.JobConfig(j, now, session, a.calendarProfiles(now)...)
+}
+
+// onBreak reports whether a redeemed break token or a `platform pause`
+// stops jobID now. A failed read runs the job: a lost break costs the user,
+// never protection. Under a strict lock nothing is paused.
+func (a *App) onBreak(jobID string) bool {
+	if a.strictLocked() {
+		return false
+	}
+	now := a.now()
+	if a.Config.Pause.Pausable(jobID) {
+		_, paused, err := a.State.Pauses.Active(now)
+		if err != nil {
+			a.Log.Warn("pause lookup failed; running job", "job", jobID, "err", fmt.Sprintf("%T", err))
+		} else if paused {
+			return true
+		}
+	}
+	breaks, err := a.State.Breaks.Active(now)
+	if err != nil {
+		a.Log.Warn("break lookup failed; running job", "job", jobID, "err", fmt.Sprintf("%T", err))
+		return false
+	}
+	for _, b := range breaks {
+		if b.JobID == jobID {
+			return true
+		}
+	}
+	return false
+}
+
 // rejectedID returns a redaction-safe identifier for a rejected plugin: the
 // manifest id when available, else the base name of the plugin dir (never
 // the full disguised workdir path). A nil manifest happens when the manifest
@@ -377,8 +687,13 @@ func redactPaths(reason string) string {
 	return strings.Join(fields, " ")
 }
 
-// Close releases the state DB and log file.
+// EventLog returns the JSONL protection-event stream (nil for an in-memory
+// DB), for the admin API to subscribe to.
+func (a *App) EventLog() *eventlog.Log { return a.events }
+
+// Close flushes pending trace spans, then releases the state DB and log file.
 func (a *App) Close() error {
+	a.Tracer.Shutdown()
 	var first error
 	if a.State != nil {
 		if err := a.State.Close(); err != nil {
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/eliteGoblin/focusd/platform/internal/osadapter"
 	"github.com/eliteGoblin/focusd/platform/internal/testutil"
@@ -273,3 +274,56 @@ func TestBootstrapDefaultOpensDiskLog(t *testing.T) {
 		t.Errorf("default Bootstrap opened no log file under %s (entries=%d err=%v); want one", logDir, len(entries), rerr)
 	}
 }
+
+func TestStrictLockOverridesBreak(t *testing.T) {
+	fa := testutil.NewFakeAdapter(t.TempDir())
+	writeUserConfig(t, fa, sampleConfig)
+	a, err := Bootstrap(Options{Adapter: fa})
+	if err != nil {
+		t.Fatalf("Bootstrap: %v", err)
+	}
+	defer a.Close()
+
+	if _, err := a.State.Breaks.Redeem("demo_job", time.Now(), 15*time.Minute, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !a.onBreak("demo_job") {
+		t.Fatal("redeemed break does not pause the job")
+	}
+	if err := a.HoldStrictLock(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if a.onBreak("demo_job") {
+		t.Fatal("break still pauses the job under a strict lock")
+	}
+	if until, err := a.State.Strict.Until(); err != nil || until.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("recorded lock end %v, err %v", until, err)
+	}
+}
+
+func TestPauseStopsOnlyPausableJobs(t *testing.T) {
+	fa := testutil.NewFakeAdapter(t.TempDir())
+	writeUserConfig(t, fa, sampleConfig)
+	a, err := Bootstrap(Options{Adapter: fa})
+	if err != nil {
+		t.Fatalf("Bootstrap: %v", err)
+	}
+	defer a.Close()
+
+	if _, err := a.State.Pauses.Start(time.Now(), 10*time.Minute, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if a.onBreak("demo_job") {
+		t.Fatal("pause stopped a job outside pause.jobs")
+	}
+	a.Config.Pause.Jobs = []string{"demo_job"}
+	if !a.onBreak("demo_job") {
+		t.Fatal("pause does not stop a pausable job")
+	}
+	if err := a.HoldStrictLock(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if a.onBreak("demo_job") {
+		t.Fatal("pause still stops the job under a strict lock")
+	}
+}
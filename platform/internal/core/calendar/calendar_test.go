@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:deep-1\r\n" +
+	"SUMMARY:Deep work #focus\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T110000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"DTSTART:20260302T100000Z\r\n" +
+	"DURATION:PT15M\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:writing\r\n" +
+	"SUMMARY:Writing\r\n" +
+	"DESCRIPTION:long\r\n" +
+	"  block\r\n" +
+	"CATEGORIES:FOCUS,Work\r\n" +
+	"DTSTART;TZID=Europe/London:20260302T140000\r\n" +
+	"DTEND;TZID=Europe/London:20260302T160000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4\r\n" +
+	"EXDATE;TZID=Europe/London:20260304T140000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:broken\r\n" +
+	"SUMMARY:#focus with no start\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("parsed %d events, want 3 (undated one skipped)", len(events))
+	}
+	if got := events[1].End.Sub(events[1].Start); got != 15*time.Minute {
+		t.Fatalf("DURATION gave %v", got)
+	}
+	if !strings.Contains(events[2].Text, "long block") || events[2].Rule == nil {
+		t.Fatalf("folded/recurring event = %+v", events[2])
+	}
+	if _, err := Parse(strings.NewReader("<html>")); err == nil {
+		t.Fatal("non-calendar parsed")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	events, _ := Parse(strings.NewReader(sample))
+	london, _ := time.LoadLocation("Europe/London")
+	for _, tc := range []struct {
+		name string
+		at   time.Time
+		uid  string
+	}{
+		{"tagged single event", time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC), "deep-1"},
+		{"untagged event only", time.Date(2026, 3, 2, 11, 5, 0, 0, time.UTC), ""},
+		{"category, first occurrence", time.Date(2026, 3, 2, 15, 0, 0, 0, london), "writing"},
+		{"excluded occurrence", time.Date(2026, 3, 4, 15, 0, 0, 0, london), ""},
+		{"later occurrence", time.Date(2026, 3, 9, 14, 0, 0, 0, london), "writing"},
+		{"past COUNT", time.Date(2026, 3, 16, 15, 0, 0, 0, london), ""},
+	} {
+		e, ok := Match(events, "#focus", tc.at)
+		if ok != (tc.uid != "") || e.UID != tc.uid {
+			t.Errorf("%s: got %q (ok=%v), want %q", tc.name, e.UID, ok, tc.uid)
+		}
+	}
+}
+
+func TestWatcherReportsChanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, sample)
+	}))
+	defer srv.Close()
+	var changes []string
+	w := &Watcher{URL: srv.URL, Tag: "#focus", Poll: time.Hour, Log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		OnChange: func(e Entry, on bool) {
+			if on {
+				changes = append(changes, "on:"+e.UID)
+			} else {
+				changes = append(changes, "off:"+e.UID)
+			}
+		}}
+	w.refresh(context.Background())
+	for _, at := range []time.Time{
+		time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC),
+	} {
+		w.check(at)
+	}
+	if got := strings.Join(changes, ","); got != "on:deep-1,off:deep-1" {
+		t.Fatalf("changes = %s", got)
+	}
+
+	// A failed fetch keeps what was fetched before.
+	srv.Close()
+	w.refresh(context.Background())
+	if _, ok := w.Current(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)); !ok {
+		t.Fatal("failed fetch dropped the known events")
+	}
+}
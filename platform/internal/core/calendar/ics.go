@@ -0,0 +1,260 @@
+// Package calendar drives policy profiles from the user's calendar: while an
+// event tagged with the configured tag (e.g. "#focus") is on, its profile
+// applies, exactly as a schedule window's would.
+//
+// The source is an iCalendar (ICS) URL, polled. Apple Calendar, Google and
+// Outlook all publish one for a calendar (a webcal:// link is fetched over
+// https). Only what a focus block needs is understood: single events plus
+// DAILY and WEEKLY recurrence (INTERVAL, COUNT, UNTIL, BYDAY, EXDATE).
+package calendar
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one VEVENT. Recurring events keep their rule and are expanded by
+// Occurrence.
+type Event struct {
+	UID        string
+	Summary    string
+	Text       string // summary and description, for tag matching
+	Categories []string
+	Start, End time.Time
+	Rule       *Rule
+	Except     []time.Time
+}
+
+// Rule is the supported subset of RRULE.
+type Rule struct {
+	Freq     string // "DAILY" | "WEEKLY"
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+// maxOccurrences bounds rule expansion: a daily event for 27 years.
+const maxOccurrences = 10000
+
+// maxLine bounds one unfolded line. A calendar is data from the network.
+const maxLine = 64 << 10
+
+// Parse reads the VEVENTs of an ICS document. Events it cannot date are
+// skipped; a document with no calendar at all is an error.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		out     []Event
+		cur     *Event
+		sawCal  bool
+		endSet  bool
+		dur     time.Duration
+		allDay  bool
+		invalid bool
+	)
+	for _, line := range lines {
+		name, params, value := splitProp(line)
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			sawCal = true
+		case name == "BEGIN" && value == "VEVENT":
+			cur, endSet, dur, allDay, invalid = &Event{}, false, 0, false, false
+		case name == "END" && value == "VEVENT" && cur != nil:
+			if !endSet {
+				switch {
+				case dur > 0:
+					cur.End = cur.Start.Add(dur)
+				case allDay:
+					cur.End = cur.Start.AddDate(0, 0, 1)
+				}
+			}
+			if !invalid && !cur.Start.IsZero() && cur.End.After(cur.Start) {
+				out = append(out, *cur)
+			}
+			cur = nil
+		case cur == nil:
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Summary = unescape(value)
+			cur.Text += " " + cur.Summary
+		case name == "DESCRIPTION":
+			cur.Text += " " + unescape(value)
+		case name == "CATEGORIES":
+			for _, c := range strings.Split(value, ",") {
+				cur.Categories = append(cur.Categories, unescape(strings.TrimSpace(c)))
+			}
+		case name == "DTSTART":
+			cur.Start, allDay, err = parseTime(params, value)
+			invalid = invalid || err != nil
+		case name == "DTEND":
+			cur.End, _, err = parseTime(params, value)
+			endSet, invalid = true, invalid || err != nil
+		case name == "DURATION":
+			dur, err = parseDuration(value)
+			invalid = invalid || err != nil
+		case name == "RRULE":
+			cur.Rule, err = parseRule(value)
+			invalid = invalid || err != nil
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if t, _, err := parseTime(params, v); err == nil {
+					cur.Except = append(cur.Except, t)
+				}
+			}
+		}
+	}
+	if !sawCal {
+		return nil, errors.New("calendar: not an iCalendar document")
+	}
+	return out, nil
+}
+
+// unfold joins RFC 5545 folded lines (a continuation starts with a space or
+// tab) and drops empty ones.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 4096), maxLine)
+	var lines []string
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("calendar: read: %w", err)
+	}
+	return lines, nil
+}
+
+// splitProp splits "NAME;K=V;K2=V2:value". Parameter values may be quoted
+// and contain ':'.
+func splitProp(line string) (name string, params map[string]string, value string) {
+	inQuote := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuote = !inQuote
+		}
+		if c == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime reads a DATE or DATE-TIME value: UTC ("Z"), zoned (TZID, an
+// IANA name; unknown zones fall back to local) or floating (local).
+func parseTime(params map[string]string, v string) (time.Time, bool, error) {
+	loc := time.Local
+	if tz := params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	switch {
+	case params["VALUE"] == "DATE" || len(v) == len("20060102"):
+		t, err := time.ParseInLocation("20060102", v, time.Local)
+		return t, true, err
+	case strings.HasSuffix(v, "Z"):
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation("20060102T150405", v, loc)
+		return t, false, err
+	}
+}
+
+var durationRE = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an RFC 5545 DURATION such as "PT1H30M" or "P1D".
+func parseDuration(v string) (time.Duration, error) {
+	m := durationRE.FindStringSubmatch(v)
+	if m == nil || m[1] == "-" {
+		return 0, fmt.Errorf("calendar: bad duration %q", v)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, u := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * u
+		}
+	}
+	return d, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRule reads the supported RRULE subset; anything else is an error, so
+// the event is skipped rather than mis-expanded.
+func parseRule(v string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(v, ";") {
+		k, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			r.Until, _, err = parseTime(nil, val)
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return nil, fmt.Errorf("calendar: unsupported BYDAY %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("calendar: unsupported RRULE part %q", k)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("calendar: bad RRULE %s: %w", k, err)
+		}
+	}
+	if (r.Freq != "DAILY" && r.Freq != "WEEKLY") || r.Interval < 1 {
+		return nil, fmt.Errorf("calendar: unsupported RRULE %q", v)
+	}
+	return r, nil
+}
+
+// unescape undoes RFC 5545 TEXT escaping.
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
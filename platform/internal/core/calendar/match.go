@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+// Entry is the calendar event occurrence driving a profile.
+type Entry struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Tagged reports whether e carries tag: in its summary or description
+// (case-insensitive), or as a category equal to the tag without its '#'.
+func (e Event) Tagged(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(e.Text), strings.ToLower(tag)) {
+		return true
+	}
+	bare := strings.TrimPrefix(tag, "#")
+	for _, c := range e.Categories {
+		if strings.EqualFold(c, bare) || strings.EqualFold(c, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrence returns the occurrence of e that covers now, if any.
+func (e Event) Occurrence(now time.Time) (start, end time.Time, ok bool) {
+	length := e.End.Sub(e.Start)
+	covers := func(s time.Time) bool { return !s.After(now) && now.Before(s.Add(length)) }
+	if e.Rule == nil {
+		return e.Start, e.End, covers(e.Start)
+	}
+	n := 0
+	var hit time.Time
+	e.Rule.each(e.Start, func(s time.Time) bool {
+		if s.After(now) || (!e.Rule.Until.IsZero() && s.After(e.Rule.Until)) {
+			return false
+		}
+		n++
+		if e.Rule.Count > 0 && n > e.Rule.Count {
+			return false
+		}
+		if covers(s) && !e.excluded(s) {
+			hit = s
+		}
+		return true
+	})
+	if hit.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	return hit, hit.Add(length), true
+}
+
+func (e Event) excluded(s time.Time) bool {
+	for _, x := range e.Except {
+		if x.Equal(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// each calls fn with every start the rule generates from dtstart, in order,
+// until fn returns false or maxOccurrences starts have been generated.
+// Starts keep dtstart's wall-clock time across DST changes.
+func (r *Rule) each(dtstart time.Time, fn func(time.Time) bool) {
+	days := r.ByDay
+	if r.Freq == "DAILY" || len(days) == 0 {
+		days = []time.Weekday{dtstart.Weekday()}
+	}
+	emitted := 0
+	for period := 0; emitted < maxOccurrences; period++ {
+		if r.Freq == "DAILY" {
+			emitted++
+			if !fn(dtstart.AddDate(0, 0, period*r.Interval)) {
+				return
+			}
+			continue
+		}
+		// WEEKLY: the week (Sunday-based) of dtstart, every Interval weeks.
+		week := dtstart.AddDate(0, 0, -int(dtstart.Weekday())+7*period*r.Interval)
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if !hasDay(days, wd) {
+				continue
+			}
+			s := week.AddDate(0, 0, int(wd))
+			if s.Before(dtstart) {
+				continue
+			}
+			emitted++
+			if !fn(s) {
+				return
+			}
+		}
+	}
+}
+
+func hasDay(days []time.Weekday, d time.Weekday) bool {
+	for _, x := range days {
+		if x == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the occurrence of a tag-carrying event that covers now. When
+// several do, the one ending last wins, so back-to-back and overlapping
+// blocks read as one.
+func Match(events []Event, tag string, now time.Time) (Entry, bool) {
+	var best Entry
+	found := false
+	for _, e := range events {
+		if !e.Tagged(tag) {
+			continue
+		}
+		start, end, ok := e.Occurrence(now)
+		if ok && (!found || end.After(best.End)) {
+			best, found = Entry{UID: e.UID, Summary: e.Summary, Start: start, End: end}, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URLEnv carries the ICS URL from the daemon (`daemon calendar`). Neutral,
+// like the other APP_* keys; the URL itself is a credential and never logged.
+const URLEnv = "APP_CAL_URL"
+
+// maxBody bounds one fetched calendar.
+const maxBody = 10 << 20
+
+// checkEvery is how often Run re-evaluates the fetched events, so a block
+// starts and ends on time between polls.
+const checkEvery = time.Minute
+
+// Watcher polls one ICS URL and reports the tagged entry in force.
+type Watcher struct {
+	URL    string
+	Tag    string
+	Poll   time.Duration
+	Client *http.Client
+	Log    *slog.Logger
+	// OnChange is called from Run whenever the entry in force changes; on is
+	// false when the last one has ended.
+	OnChange func(e Entry, on bool)
+
+	mu     sync.Mutex
+	events []Event
+	cur    Entry
+	on     bool
+}
+
+// ValidURL accepts an absolute http, https or webcal URL.
+func ValidURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "webcal")
+}
+
+// Current returns the tagged entry covering now in the last fetched events.
+func (w *Watcher) Current(now time.Time) (Entry, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return Match(w.events, w.Tag, now)
+}
+
+// Run fetches now and every Poll, and checks for a changed entry every
+// minute, until ctx is done. A failed fetch keeps the last good events: an
+// unreachable calendar must not end a block early.
+func (w *Watcher) Run(ctx context.Context) {
+	w.refresh(ctx)
+	poll := time.NewTicker(w.Poll)
+	defer poll.Stop()
+	check := time.NewTicker(checkEvery)
+	defer check.Stop()
+	for {
+		w.check(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			w.refresh(ctx)
+		case <-check.C:
+		}
+	}
+}
+
+func (w *Watcher) refresh(ctx context.Context) {
+	events, err := w.fetch(ctx)
+	if err != nil {
+		// fetch's errors never carry the URL, so the text is safe to log.
+		w.Log.Warn("calendar fetch failed; keeping last events", "err", err)
+		return
+	}
+	w.mu.Lock()
+	w.events = events
+	w.mu.Unlock()
+}
+
+// check fires OnChange when the entry in force differs from the last one.
+func (w *Watcher) check(now time.Time) {
+	e, on := w.Current(now)
+	w.mu.Lock()
+	changed := on != w.on || (on && (e.UID != w.cur.UID || !e.Start.Equal(w.cur.Start)))
+	prev := w.cur
+	w.cur, w.on = e, on
+	w.mu.Unlock()
+	if !changed || w.OnChange == nil {
+		return
+	}
+	if on {
+		w.OnChange(e, true)
+	} else {
+		w.OnChange(prev, false)
+	}
+}
+
+func (w *Watcher) fetch(ctx context.Context) ([]Event, error) {
+	raw := w.URL
+	if strings.HasPrefix(raw, "webcal://") {
+		raw = "https://" + strings.TrimPrefix(raw, "webcal://")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, raw, nil)
+	if err != nil {
+		return nil, errors.New("calendar: bad url")
+	}
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// *url.Error would carry the URL, which is a credential.
+		return nil, errors.New("calendar: fetch failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar: fetch: %s", resp.Status)
+	}
+	return Parse(io.LimitReader(resp.Body, maxBody))
+}
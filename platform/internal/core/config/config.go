@@ -9,9 +9,13 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/eliteGoblin/focusd/platform/internal/osadapter"
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
 )
 
@@ -40,6 +44,47 @@ type Config struct {
 	Platform Platform  `yaml:"platform"`
 	Jobs     []Job     `yaml:"jobs"`
 	Services []Service `yaml:"services"` // typed-only; future service plugins
+	// Session is what a focus session (`platform session start`) adds.
+	Session Overlay `yaml:"session"`
+	// Profiles are named overlays that Windows switch on by schedule.
+	Profiles map[string]Overlay `yaml:"profiles"`
+	Windows  []Window           `yaml:"windows"`
+	Calendar Calendar           `yaml:"calendar"`
+	Breaks   Breaks             `yaml:"breaks"`
+	Pause    Pause              `yaml:"pause"`
+	// Web are blocked services' web equivalents, always on (see WebBlock).
+	Web []WebBlock `yaml:"web"`
+}
+
+// WebBlock extends a blocked app to its web equivalent: Domains go into
+// every dns-block job's hosts block and every browser-monitor job's tab
+// blocklist, so the Steam store in a browser is blocked like Steam.app.
+type WebBlock struct {
+	Name    string   `yaml:"name"`
+	Domains []string `yaml:"domains"`
+}
+
+// webKeys maps a plugin to the config key its web domains extend; both keys
+// add to the plugin's own list rather than replace it.
+var webKeys = map[string]string{
+	"dns-block":       "extra_hosts",
+	"browser-monitor": "extra_blocklist",
+}
+
+// WebOverlay is the overlay c.Web puts on j: its domains under the key j's
+// plugin reads, or nothing for a plugin web blocks don't reach.
+func (c *Config) WebOverlay(j Job) Overlay {
+	key, ok := webKeys[j.Plugin]
+	if !ok || len(c.Web) == 0 {
+		return Overlay{}
+	}
+	var domains []any
+	for _, w := range c.Web {
+		for _, d := range w.Domains {
+			domains = append(domains, d)
+		}
+	}
+	return Overlay{Jobs: map[string]map[string]any{j.ID: {key: domains}}}
 }
 
 // Platform holds platform-wide settings.
@@ -55,6 +100,56 @@ type Platform struct {
 	// the per-run guarantee; this sweep bounds the self-heal latency for
 	// plugins that are not currently running. 0 (unset) => DefaultSweepInterval.
 	IntegritySweepInterval Duration `yaml:"integrity_sweep_interval"`
+	// Backoff stretches job cadence on battery or under thermal pressure.
+	Backoff Backoff `yaml:"backoff"`
+}
+
+// Backoff multiplies every job's interval (and the integrity sweep's) by
+// Factor while the machine is on battery or thermally throttled, never
+// past Max: a job already at or over Max keeps its own interval. Factor 0
+// or 1 turns backing off off; Max 0 ⇒ DefaultBackoffMax.
+//
+// Idle goes further while nobody is at the machine (screen locked, or no
+// input for a while): every interval is stretched straight to Max, and
+// every job runs at once when the user is back.
+type Backoff struct {
+	Factor int      `yaml:"factor"`
+	Max    Duration `yaml:"max"`
+	Idle   bool     `yaml:"idle"`
+}
+
+// MaxBackoffFactor bounds Factor, so a constrained laptop still enforces.
+const MaxBackoffFactor = 10
+
+// DefaultBackoffMax caps a stretched interval when max is unset.
+const DefaultBackoffMax = 5 * time.Minute
+
+// Enabled reports whether b ever stretches an interval.
+func (b Backoff) Enabled() bool { return b.Factor > 1 }
+
+// Stretch is every as backed off: Factor times longer, capped at Max, and
+// never shorter than every itself.
+func (b Backoff) Stretch(every time.Duration) time.Duration {
+	if !b.Enabled() {
+		return every
+	}
+	limit := b.Max.Std()
+	if limit <= 0 {
+		limit = DefaultBackoffMax
+	}
+	return max(every, min(every*time.Duration(b.Factor), limit))
+}
+
+// Watched reports whether anything needs the power/idle probe.
+func (b Backoff) Watched() bool { return b.Enabled() || b.Idle }
+
+// IdleStretch is every while idle: Max, or every itself when longer.
+func (b Backoff) IdleStretch(every time.Duration) time.Duration {
+	limit := b.Max.Std()
+	if limit <= 0 {
+		limit = DefaultBackoffMax
+	}
+	return max(every, limit)
 }
 
 // DefaultSweepInterval is the whole-bundle integrity sweep cadence when the
@@ -72,6 +167,188 @@ type Job struct {
 	Retry        int            `yaml:"retry"`
 	AllowOverlap bool           `yaml:"allow_overlap"`
 	Config       map[string]any `yaml:"config"` // opaque, passed to plugin
+	// Locked jobs have no sanctioned off switch: they must stay enabled,
+	// cannot be break or pause targets, and a draft that drops one is
+	// refused (LockViolations). Only the full uninstall removes them.
+	Locked bool `yaml:"locked"`
+	// Adaptive relaxes the job to a quiet interval while nothing is being
+	// blocked; Schedule is then the tight cadence it returns to.
+	Adaptive Adaptive `yaml:"adaptive,omitempty"`
+}
+
+// Adaptive paces a job by activity. Schedule stays the fastest the job
+// runs; while no run has blocked anything for Hold, ticks come at most
+// every Quiet. A block brings back every tick for the next Hold. Quiet 0
+// turns it off; Hold 0 ⇒ DefaultAdaptiveHold.
+type Adaptive struct {
+	Quiet Duration `yaml:"quiet"`
+	Hold  Duration `yaml:"hold"`
+}
+
+// DefaultAdaptiveHold is how long a block keeps an adaptive job tight when
+// hold is unset.
+const DefaultAdaptiveHold = time.Hour
+
+// Enabled reports whether a ever relaxes a job.
+func (a Adaptive) Enabled() bool { return a.Quiet > 0 }
+
+// HoldFor is Hold, or DefaultAdaptiveHold when unset.
+func (a Adaptive) HoldFor() time.Duration {
+	if a.Hold <= 0 {
+		return DefaultAdaptiveHold
+	}
+	return a.Hold.Std()
+}
+
+// Overlay is config laid over jobs' own config: per job id, keys merged
+// into that job's config. A list value is appended to a list already under
+// the key, and any other value replaces it. Overlays should carry only keys
+// the plugin treats as additions (dns-block's extra_hosts), so an overlay can
+// only tighten.
+type Overlay struct {
+	Jobs map[string]map[string]any `yaml:"jobs"`
+}
+
+// Apply returns cfg with o's keys for jobID merged in, or cfg itself when o
+// names no keys for jobID. The result is a fresh map; cfg is never mutated.
+func (o Overlay) Apply(jobID string, cfg map[string]any) map[string]any {
+	extra := o.Jobs[jobID]
+	if len(extra) == 0 {
+		return cfg
+	}
+	out := make(map[string]any, len(cfg)+len(extra))
+	for k, v := range cfg {
+		out[k] = v
+	}
+	for k, v := range extra {
+		prev, okPrev := out[k].([]any)
+		add, okAdd := v.([]any)
+		if okPrev && okAdd {
+			out[k] = append(append([]any(nil), prev...), add...)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Window switches a profile on at every Start (a standard five-field cron
+// expression, local time) for Duration: "0 9 * * 1-5" with 9h is weekdays
+// 9–18.
+type Window struct {
+	Profile  string   `yaml:"profile"`
+	Start    string   `yaml:"start"`
+	Duration Duration `yaml:"duration"`
+}
+
+// maxWindow bounds a window's duration; a week covers every weekly rhythm.
+const maxWindow = 7 * 24 * time.Hour
+
+// active reports whether w covers now: whether it started within the last
+// Duration. An unparsable Start is never active (Validate rejects it).
+func (w Window) active(now time.Time) bool {
+	sched, err := cron.ParseStandard(w.Start)
+	if err != nil {
+		return false
+	}
+	return !sched.Next(now.Add(-w.Duration.Std())).After(now)
+}
+
+// ActiveProfiles returns the profiles whose windows cover now, in window
+// order, each once.
+func (c *Config) ActiveProfiles(now time.Time) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, w := range c.Windows {
+		if !seen[w.Profile] && w.active(now) {
+			seen[w.Profile] = true
+			out = append(out, w.Profile)
+		}
+	}
+	return out
+}
+
+// JobConfig is the config j runs with at now: its own plus the web blocks,
+// then every active profile's overlay, then each of extra not already
+// applied (the calendar's profile), then the session overlay when a focus
+// session is on.
+func (c *Config) JobConfig(j Job, now time.Time, session bool, extra ...string) map[string]any {
+	cfg := c.WebOverlay(j).Apply(j.ID, j.Config)
+	applied := map[string]bool{}
+	for _, p := range append(c.ActiveProfiles(now), extra...) {
+		if !applied[p] {
+			applied[p] = true
+			cfg = c.Profiles[p].Apply(j.ID, cfg)
+		}
+	}
+	if session {
+		cfg = c.Session.Apply(j.ID, cfg)
+	}
+	return cfg
+}
+
+// Calendar switches Profile on while an event tagged Tag is on in the
+// user's calendar, whose ICS URL the daemon hands over (`daemon calendar`).
+// Poll is how often it is fetched; 0 ⇒ DefaultCalendarPoll. An empty
+// Profile turns calendar blocking off.
+type Calendar struct {
+	Tag     string   `yaml:"tag"`
+	Profile string   `yaml:"profile"`
+	Poll    Duration `yaml:"poll"`
+}
+
+// DefaultCalendarPoll is the calendar fetch cadence when poll is unset.
+const DefaultCalendarPoll = 5 * time.Minute
+
+// PollEvery is the effective fetch cadence.
+func (c Calendar) PollEvery() time.Duration {
+	if c.Poll <= 0 {
+		return DefaultCalendarPoll
+	}
+	return c.Poll.Std()
+}
+
+// Breaks is the weekly break-token budget (`platform break`): PerWeek
+// tokens, each pausing one of Jobs for Length. PerWeek 0 turns breaks off.
+type Breaks struct {
+	PerWeek int      `yaml:"per_week"`
+	Length  Duration `yaml:"length"`
+	Jobs    []string `yaml:"jobs"`
+}
+
+// MaxBreak bounds one break. A break is a breather, not a way out.
+const MaxBreak = time.Hour
+
+// Breakable reports whether a token may pause jobID.
+func (b Breaks) Breakable(jobID string) bool {
+	for _, id := range b.Jobs {
+		if id == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pause is the `platform pause` escape valve: after transcribing a passage
+// and waiting Cooldown, the user may stop Jobs for up to Max. Max 0 turns
+// pausing off.
+type Pause struct {
+	Max      Duration `yaml:"max"`
+	Cooldown Duration `yaml:"cooldown"`
+	Jobs     []string `yaml:"jobs"`
+}
+
+// MinPauseCooldown keeps the wait longer than an urge usually lasts.
+const MinPauseCooldown = time.Minute
+
+// Pausable reports whether a pause stops jobID.
+func (p Pause) Pausable(jobID string) bool {
+	for _, id := range p.Jobs {
+		if id == jobID {
+			return true
+		}
+	}
+	return false
 }
 
 // Service represents a future long-running service plugin. Parsed and
@@ -129,6 +406,9 @@ func (c *Config) Validate() error {
 	if c.Platform.IntegritySweepInterval < 0 {
 		return fmt.Errorf("platform.integrity_sweep_interval must be >= 0 (omit for default %s)", DefaultSweepInterval)
 	}
+	if b := c.Platform.Backoff; b.Factor < 0 || b.Factor > MaxBackoffFactor || b.Max < 0 {
+		return fmt.Errorf("platform.backoff: factor must be 0..%d and max >= 0", MaxBackoffFactor)
+	}
 
 	seenJob := make(map[string]struct{})
 	for i, j := range c.Jobs {
@@ -143,6 +423,10 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("job %q: retry must be >= 0", j.ID)
 		case j.Timeout < 0:
 			return fmt.Errorf("job %q: timeout must be >= 0", j.ID)
+		case j.Adaptive.Quiet < 0 || j.Adaptive.Hold < 0:
+			return fmt.Errorf("job %q: adaptive quiet and hold must be >= 0", j.ID)
+		case j.Locked && !j.Enabled:
+			return fmt.Errorf("job %q: a locked job must be enabled", j.ID)
 		}
 		if _, dup := seenJob[j.ID]; dup {
 			return fmt.Errorf("duplicate job id %q", j.ID)
@@ -150,6 +434,77 @@ func (c *Config) Validate() error {
 		seenJob[j.ID] = struct{}{}
 	}
 
+	for id := range c.Session.Jobs {
+		if _, ok := seenJob[id]; !ok {
+			return fmt.Errorf("session.jobs: unknown job %q", id)
+		}
+	}
+	for name, p := range c.Profiles {
+		for id := range p.Jobs {
+			if _, ok := seenJob[id]; !ok {
+				return fmt.Errorf("profile %q: unknown job %q", name, id)
+			}
+		}
+	}
+	for i, w := range c.Windows {
+		if _, ok := c.Profiles[w.Profile]; !ok {
+			return fmt.Errorf("windows[%d]: unknown profile %q", i, w.Profile)
+		}
+		if _, err := cron.ParseStandard(w.Start); err != nil {
+			return fmt.Errorf("windows[%d]: start %q is not a cron expression: %w", i, w.Start, err)
+		}
+		if w.Duration <= 0 || w.Duration.Std() > maxWindow {
+			return fmt.Errorf("windows[%d]: duration must be between 1s and %s", i, maxWindow)
+		}
+	}
+
+	if cal := c.Calendar; cal.Profile != "" {
+		if _, ok := c.Profiles[cal.Profile]; !ok {
+			return fmt.Errorf("calendar: unknown profile %q", cal.Profile)
+		}
+		if cal.Tag == "" {
+			return fmt.Errorf("calendar: tag is required")
+		}
+		if cal.Poll < 0 || (cal.Poll > 0 && cal.Poll.Std() < time.Minute) {
+			return fmt.Errorf("calendar: poll must be at least 1m")
+		}
+	}
+
+	if b := c.Breaks; b.PerWeek < 0 || (b.PerWeek > 0 && (b.Length <= 0 || b.Length.Std() > MaxBreak)) {
+		return fmt.Errorf("breaks: per_week must be >= 0 and length between 1s and %s", MaxBreak)
+	}
+	for _, id := range c.Breaks.Jobs {
+		if _, ok := seenJob[id]; !ok {
+			return fmt.Errorf("breaks.jobs: unknown job %q", id)
+		}
+		if c.locked(id) {
+			return fmt.Errorf("breaks.jobs: job %q is locked", id)
+		}
+	}
+
+	if p := c.Pause; p.Max < 0 || (p.Max > 0 && (p.Max.Std() > MaxBreak || p.Cooldown.Std() < MinPauseCooldown)) {
+		return fmt.Errorf("pause: max must be at most %s and cooldown at least %s", MaxBreak, MinPauseCooldown)
+	}
+	for _, id := range c.Pause.Jobs {
+		if _, ok := seenJob[id]; !ok {
+			return fmt.Errorf("pause.jobs: unknown job %q", id)
+		}
+		if c.locked(id) {
+			return fmt.Errorf("pause.jobs: job %q is locked", id)
+		}
+	}
+
+	for i, w := range c.Web {
+		if w.Name == "" || len(w.Domains) == 0 {
+			return fmt.Errorf("web[%d]: name and at least one domain are required", i)
+		}
+		for _, d := range w.Domains {
+			if d == "" || strings.ContainsAny(d, "/: \t") {
+				return fmt.Errorf("web %q: %q is not a bare domain", w.Name, d)
+			}
+		}
+	}
+
 	seenSvc := make(map[string]struct{})
 	for i, s := range c.Services {
 		if s.ID == "" {
@@ -165,3 +520,73 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+// Diff is how one config's jobs differ from another's, by job id. Each list
+// is sorted.
+type Diff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// Empty reports whether the two configs define the same jobs.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffJobs compares the jobs of from and to. A job whose plugin, schedule,
+// switches or config differ in any way counts as changed.
+func DiffJobs(from, to *Config) Diff {
+	old := make(map[string]Job, len(from.Jobs))
+	for _, j := range from.Jobs {
+		old[j.ID] = j
+	}
+	var d Diff
+	for _, j := range to.Jobs {
+		prev, ok := old[j.ID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, j.ID)
+		case !reflect.DeepEqual(prev, j):
+			d.Changed = append(d.Changed, j.ID)
+		}
+		delete(old, j.ID)
+	}
+	for id := range old {
+		d.Removed = append(d.Removed, id)
+	}
+	slices.Sort(d.Added)
+	slices.Sort(d.Removed)
+	slices.Sort(d.Changed)
+	return d
+}
+
+// LockViolations returns, sorted, the jobs locked in from that to removes,
+// disables or unlocks.
+func LockViolations(from, to *Config) []string {
+	kept := make(map[string]Job, len(to.Jobs))
+	for _, j := range to.Jobs {
+		kept[j.ID] = j
+	}
+	var ids []string
+	for _, j := range from.Jobs {
+		if !j.Locked {
+			continue
+		}
+		if k, ok := kept[j.ID]; !ok || !k.Enabled || !k.Locked {
+			ids = append(ids, j.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// locked reports whether the job with id is locked.
+func (c *Config) locked(id string) bool {
+	for _, j := range c.Jobs {
+		if j.ID == id {
+			return j.Locked
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -88,13 +89,20 @@ jobs:
 
 func TestValidationErrors(t *testing.T) {
 	cases := map[string]string{
-		"missing job id":     "jobs:\n  - plugin: p\n    schedule: \"* * * * *\"\n",
-		"missing plugin":     "jobs:\n  - id: j\n    schedule: \"* * * * *\"\n",
-		"missing schedule":   "jobs:\n  - id: j\n    plugin: p\n",
-		"negative retry":     "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\n    retry: -1\n",
-		"duplicate job id":   "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\n  - id: j\n    plugin: q\n    schedule: \"* * * * *\"\n",
-		"bad run_mode":       "platform:\n  run_mode: root\n",
-		"missing service id": "services:\n  - plugin: p\n",
+		"missing job id":            "jobs:\n  - plugin: p\n    schedule: \"* * * * *\"\n",
+		"missing plugin":            "jobs:\n  - id: j\n    schedule: \"* * * * *\"\n",
+		"missing schedule":          "jobs:\n  - id: j\n    plugin: p\n",
+		"negative retry":            "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\n    retry: -1\n",
+		"duplicate job id":          "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\n  - id: j\n    plugin: q\n    schedule: \"* * * * *\"\n",
+		"bad run_mode":              "platform:\n  run_mode: root\n",
+		"missing service id":        "services:\n  - plugin: p\n",
+		"window bad cron":           "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nprofiles:\n  w: {}\nwindows:\n  - profile: w\n    start: \"9am weekdays\"\n    duration: 1h\n",
+		"window unknown profile":    "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nwindows:\n  - profile: w\n    start: \"0 9 * * *\"\n    duration: 1h\n",
+		"window without duration":   "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nprofiles:\n  w: {}\nwindows:\n  - profile: w\n    start: \"0 9 * * *\"\n",
+		"profile names unknown job": "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nprofiles:\n  w:\n    jobs:\n      k: {}\n",
+		"break too long":            "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nbreaks:\n  per_week: 2\n  length: 3h\n  jobs: [j]\n",
+		"break names unknown job":   "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nbreaks:\n  per_week: 2\n  length: 15m\n  jobs: [k]\n",
+		"session names unknown job": "jobs:\n  - id: j\n    plugin: p\n    schedule: \"* * * * *\"\nsession:\n  jobs:\n    k:\n      extra_hosts: [a.com]\n",
 	}
 	for name, y := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -105,8 +113,293 @@ func TestValidationErrors(t *testing.T) {
 	}
 }
 
+func TestOverlayApply(t *testing.T) {
+	base := map[string]any{"hosts": []any{"a.com"}, "extra_hosts": []any{"b.com"}, "mode": "kill"}
+	o := Overlay{Jobs: map[string]map[string]any{"dns": {"extra_hosts": []any{"c.com"}, "mode": "detect"}}}
+	got := o.Apply("dns", base)
+	if extra := got["extra_hosts"].([]any); len(extra) != 2 || extra[1] != "c.com" {
+		t.Fatalf("lists must append: extra_hosts = %v", extra)
+	}
+	if got["mode"] != "detect" || len(got["hosts"].([]any)) != 1 {
+		t.Fatalf("overlay = %v", got)
+	}
+	if len(base["extra_hosts"].([]any)) != 1 || base["mode"] != "kill" {
+		t.Fatal("Apply mutated the base config")
+	}
+	if got := o.Apply("kill", base); len(got) != 3 {
+		t.Fatalf("unnamed job overlay = %v", got)
+	}
+}
+
+const windowsYAML = `
+jobs:
+  - id: dns
+    plugin: dns-block
+    enabled: true
+    schedule: "@every 10s"
+profiles:
+  work:
+    jobs:
+      dns:
+        extra_hosts: [slack.com]
+  night:
+    jobs:
+      dns:
+        extra_hosts: [reddit.com]
+session:
+  jobs:
+    dns:
+      extra_hosts: [news.ycombinator.com]
+windows:
+  - profile: work
+    start: "0 9 * * 1-5"
+    duration: 9h
+  - profile: night
+    start: "0 23 * * *"
+    duration: 7h
+`
+
+func TestWindowsSwitchProfiles(t *testing.T) {
+	cfg, err := Parse([]byte(windowsYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, min int) time.Time { // May 2026: the 4th is a Monday
+		return time.Date(2026, 5, day, hour, min, 0, 0, time.Local)
+	}
+	for _, tc := range []struct {
+		when time.Time
+		want string
+	}{
+		{at(4, 8, 59), ""},
+		{at(4, 9, 0), "work"},
+		{at(4, 17, 59), "work"},
+		{at(4, 18, 0), ""},
+		{at(4, 23, 30), "night"},
+		{at(5, 5, 59), "night"}, // crosses midnight
+		{at(5, 6, 0), ""},
+		{at(9, 10, 0), ""}, // Saturday
+	} {
+		if got := strings.Join(cfg.ActiveProfiles(tc.when), ","); got != tc.want {
+			t.Errorf("%s: active = %q, want %q", tc.when.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+
+	j := cfg.Jobs[0]
+	got := cfg.JobConfig(j, at(4, 10, 0), true)["extra_hosts"].([]any)
+	if len(got) != 2 || got[0] != "slack.com" || got[1] != "news.ycombinator.com" {
+		t.Fatalf("work + session extra_hosts = %v", got)
+	}
+	if cfg := cfg.JobConfig(j, at(9, 10, 0), false); cfg["extra_hosts"] != nil {
+		t.Fatalf("no window, no session: %v", cfg)
+	}
+	// A calendar profile applies like a window's, and only once.
+	if got := cfg.JobConfig(j, at(4, 10, 0), false, "work")["extra_hosts"].([]any); len(got) != 1 {
+		t.Fatalf("work from window and calendar applied twice: %v", got)
+	}
+	if cfg.JobConfig(j, at(9, 10, 0), false, "work")["extra_hosts"] == nil {
+		t.Fatal("calendar profile not applied outside its window")
+	}
+}
+
+func TestCalendarValidation(t *testing.T) {
+	base := strings.Replace(windowsYAML, "windows:", "calendar:\n  tag: \"#focus\"\n  profile: %s\n  poll: %s\nwindows:", 1)
+	for _, tc := range []struct {
+		profile, poll string
+		ok            bool
+	}{
+		{"work", "5m", true},
+		{"missing", "5m", false},
+		{"work", "10s", false},
+	} {
+		_, err := Parse([]byte(fmt.Sprintf(base, tc.profile, tc.poll)))
+		if (err == nil) != tc.ok {
+			t.Errorf("profile %s poll %s: err = %v, want ok=%v", tc.profile, tc.poll, err, tc.ok)
+		}
+	}
+}
+
+func TestPauseValidation(t *testing.T) {
+	base := strings.Replace(windowsYAML, "windows:", "pause:\n  max: %s\n  cooldown: %s\n  jobs: [%s]\nwindows:", 1)
+	for _, tc := range []struct {
+		max, cooldown, job string
+		ok                 bool
+	}{
+		{"20m", "10m", "dns", true},
+		{"2h", "10m", "dns", false},
+		{"20m", "30s", "dns", false},
+		{"20m", "10m", "missing", false},
+		{"0s", "0s", "", true},
+	} {
+		_, err := Parse([]byte(fmt.Sprintf(base, tc.max, tc.cooldown, tc.job)))
+		if (err == nil) != tc.ok {
+			t.Errorf("max %s cooldown %s job %q: err = %v, want ok=%v", tc.max, tc.cooldown, tc.job, err, tc.ok)
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	base := strings.Replace(validYAML, "    retry: 1\n", "    retry: 1\n    adaptive:\n      quiet: %s\n      hold: %s\n", 1)
+	cfg, err := Parse([]byte(fmt.Sprintf(base, "10m", "0s")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := cfg.Jobs[0].Adaptive
+	if !a.Enabled() || a.Quiet.Std() != 10*time.Minute || a.HoldFor() != DefaultAdaptiveHold {
+		t.Errorf("adaptive = %+v, hold %s", a, a.HoldFor())
+	}
+	if _, err := Parse([]byte(fmt.Sprintf(base, "-1m", "1h"))); err == nil {
+		t.Error("a negative quiet interval must be refused")
+	}
+	if (Adaptive{}).Enabled() {
+		t.Error("zero Adaptive must be off")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := strings.Replace(validYAML, "  log_level: debug\n", "  log_level: debug\n  backoff:\n    factor: %d\n    max: %s\n", 1)
+	for _, tc := range []struct {
+		factor int
+		max    string
+		ok     bool
+	}{
+		{3, "5m", true},
+		{0, "0s", true},
+		{11, "5m", false},
+		{-1, "5m", false},
+	} {
+		if _, err := Parse([]byte(fmt.Sprintf(base, tc.factor, tc.max))); (err == nil) != tc.ok {
+			t.Errorf("factor %d max %s: err = %v, want ok=%v", tc.factor, tc.max, err, tc.ok)
+		}
+	}
+	b := Backoff{Factor: 3, Max: Duration(5 * time.Minute)}
+	for every, want := range map[time.Duration]time.Duration{
+		10 * time.Second: 30 * time.Second,
+		time.Minute:      3 * time.Minute,
+		2 * time.Minute:  5 * time.Minute,
+		30 * time.Minute: 30 * time.Minute,
+	} {
+		if got := b.Stretch(every); got != want {
+			t.Errorf("Stretch(%s) = %s, want %s", every, got, want)
+		}
+	}
+	if got := (Backoff{}).Stretch(10 * time.Second); got != 10*time.Second {
+		t.Errorf("off backoff stretched to %s", got)
+	}
+	idle := Backoff{Idle: true}
+	if !idle.Watched() || idle.Enabled() || idle.IdleStretch(10*time.Second) != DefaultBackoffMax || b.IdleStretch(time.Hour) != time.Hour {
+		t.Errorf("idle backoff misread: %+v", idle)
+	}
+}
+
 func TestLoadMissingFile(t *testing.T) {
 	if _, err := Load("/no/such/focusd-config.yaml"); err == nil {
 		t.Error("expected error for missing file")
 	}
 }
+
+func TestDiffJobs(t *testing.T) {
+	from, err := Parse([]byte(`
+jobs:
+  - {id: a, plugin: p, enabled: true, schedule: "@every 1m"}
+  - {id: b, plugin: p, enabled: true, schedule: "@every 1m"}
+  - {id: c, plugin: p, enabled: true, schedule: "@every 1m"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := Parse([]byte(`
+jobs:
+  - {id: a, plugin: p, enabled: true, schedule: "@every 1m"}
+  - {id: c, plugin: p, enabled: true, schedule: "@every 1m", config: {domains: [x.com]}}
+  - {id: d, plugin: p, enabled: true, schedule: "@every 1m"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := DiffJobs(from, to)
+	if fmt.Sprint(d.Added, d.Removed, d.Changed) != "[d] [b] [c]" {
+		t.Fatalf("diff = %+v", d)
+	}
+	if !DiffJobs(to, to).Empty() {
+		t.Fatal("a config differs from itself")
+	}
+}
+
+func TestLockedJobs(t *testing.T) {
+	for name, body := range map[string]string{
+		"disabled": `
+jobs:
+  - {id: a, plugin: p, enabled: false, locked: true, schedule: "@every 1m"}
+`,
+		"breakable": `
+jobs:
+  - {id: a, plugin: p, enabled: true, locked: true, schedule: "@every 1m"}
+breaks: {per_week: 1, length: 10m, jobs: [a]}
+`,
+		"pausable": `
+jobs:
+  - {id: a, plugin: p, enabled: true, locked: true, schedule: "@every 1m"}
+pause: {max: 10m, cooldown: 5m, jobs: [a]}
+`,
+	} {
+		if _, err := Parse([]byte(body)); err == nil {
+			t.Errorf("%s: a locked job must be rejected", name)
+		}
+	}
+
+	from, err := Parse([]byte(`
+jobs:
+  - {id: a, plugin: p, enabled: true, locked: true, schedule: "@every 1m"}
+  - {id: b, plugin: p, enabled: true, locked: true, schedule: "@every 1m"}
+  - {id: c, plugin: p, enabled: true, locked: true, schedule: "@every 1m"}
+  - {id: d, plugin: p, enabled: true, schedule: "@every 1m"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := Parse([]byte(`
+jobs:
+  - {id: a, plugin: p, enabled: true, locked: true, schedule: "@every 5m"}
+  - {id: b, plugin: p, enabled: true, schedule: "@every 1m"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(LockViolations(from, to)); got != "[b c]" {
+		t.Fatalf("violations = %s, want [b c] (d is not locked; a stays locked)", got)
+	}
+}
+
+func TestWebBlocksReachDNSAndBrowserJobs(t *testing.T) {
+	cfg, err := Parse([]byte(`
+jobs:
+  - {id: dns, plugin: dns-block, enabled: true, schedule: "@every 1m", config: {extra_hosts: [a.com]}}
+  - {id: tabs, plugin: browser-monitor, enabled: true, schedule: "@every 1m"}
+  - {id: kill, plugin: kill-steam, enabled: true, schedule: "@every 1m"}
+web:
+  - {name: Twitch, domains: [twitch.tv]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if got := fmt.Sprint(cfg.JobConfig(cfg.Jobs[0], now, false)["extra_hosts"]); got != "[a.com twitch.tv]" {
+		t.Errorf("dns-block extra_hosts = %s", got)
+	}
+	if got := fmt.Sprint(cfg.JobConfig(cfg.Jobs[1], now, false)["extra_blocklist"]); got != "[twitch.tv]" {
+		t.Errorf("browser-monitor extra_blocklist = %s", got)
+	}
+	if got := cfg.JobConfig(cfg.Jobs[2], now, false); len(got) != 0 {
+		t.Errorf("kill-steam config = %v, want untouched", got)
+	}
+	for _, bad := range []string{
+		"web: [{name: x, domains: []}]",
+		"web: [{domains: [a.com]}]",
+		"web: [{name: x, domains: [\"https://a.com\"]}]",
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
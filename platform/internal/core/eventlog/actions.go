@@ -0,0 +1,98 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Actions are the enforcement counts a plugin reports in its result details.
+// The keys are the plugins' own (kill-steam: killed_count, blocked_apps,
+// uninstall_removed, uninstall_reclaimed_bytes, uninstall_reclaimed_files,
+// bypass.killed_pids,
+// backup_restored, net_restricted; freedom-protector: relaunched). Missing keys read as zero, so any plugin's
+// result decodes safely.
+type Actions struct {
+	Kills          int      `json:"kills,omitempty"`
+	BlockedApps    []string `json:"blocked_apps,omitempty"`
+	Removals       int      `json:"removals,omitempty"`
+	ReclaimedBytes int64    `json:"reclaimed_bytes,omitempty"`
+	ReclaimedFiles int      `json:"reclaimed_files,omitempty"`
+	BypassKills    int      `json:"bypass_kills,omitempty"`
+	Relaunches     int      `json:"relaunches,omitempty"`
+	// BackupRestores are blocked artifacts found put back from a backup: a
+	// bypass attempt, whatever the sweep then did with them.
+	BackupRestores int `json:"backup_restores,omitempty"`
+	// Restrictions are processes cut off the network instead of killed.
+	Restrictions int `json:"restrictions,omitempty"`
+}
+
+// Any reports whether the run acted at all.
+func (a Actions) Any() bool {
+	return a.Kills > 0 || len(a.BlockedApps) > 0 || a.Removals > 0 || a.BypassKills > 0 || a.Relaunches > 0 ||
+		a.BackupRestores > 0 || a.Restrictions > 0
+}
+
+// Blocked reports whether the run stopped a block attempt — killed or
+// blocked an app, or removed a reinstall. A relaunch alone restores a guard;
+// it is not an attempt on the user's side.
+func (a Actions) Blocked() bool {
+	return a.Kills > 0 || len(a.BlockedApps) > 0 || a.Removals > 0 || a.BypassKills > 0 || a.BackupRestores > 0 ||
+		a.Restrictions > 0
+}
+
+// parts renders the non-zero counts as short phrases ("2 kills",
+// "blocked Steam"), in a fixed order.
+func (a Actions) parts() []string {
+	var out []string
+	for _, c := range []struct {
+		n    int
+		what string
+	}{
+		{a.Kills, "kills"}, {a.Removals, "removals"}, {a.BypassKills, "bypass kills"},
+		{a.Relaunches, "relaunches"}, {a.BackupRestores, "backup restores"}, {a.Restrictions, "restrictions"},
+	} {
+		if c.n > 0 {
+			out = append(out, fmt.Sprintf("%d %s", c.n, c.what))
+		}
+	}
+	if len(a.BlockedApps) > 0 {
+		out = append(out, "blocked "+strings.Join(a.BlockedApps, ", "))
+	}
+	return out
+}
+
+// ParseActions extracts Actions from a plugin's stdout result JSON. Removed
+// paths are counted, never kept. Unparseable input yields zero Actions.
+func ParseActions(stdoutJSON string) Actions {
+	var r struct {
+		Details struct {
+			BlockedApps    []string `json:"blocked_apps"`
+			KilledCount    int      `json:"killed_count"`
+			Removed        []any    `json:"uninstall_removed"`
+			ReclaimedBytes int64    `json:"uninstall_reclaimed_bytes"`
+			ReclaimedFiles int      `json:"uninstall_reclaimed_files"`
+			Relaunched     []any    `json:"relaunched"`
+			Restored       []any    `json:"backup_restored"`
+			Restricted     []int    `json:"net_restricted"`
+			Bypass         struct {
+				KilledPIDs []int `json:"killed_pids"`
+			} `json:"bypass"`
+		} `json:"details"`
+	}
+	if json.Unmarshal([]byte(stdoutJSON), &r) != nil {
+		return Actions{}
+	}
+	d := r.Details
+	return Actions{
+		Kills:          d.KilledCount,
+		BlockedApps:    d.BlockedApps,
+		Removals:       len(d.Removed),
+		ReclaimedBytes: d.ReclaimedBytes,
+		ReclaimedFiles: d.ReclaimedFiles,
+		BypassKills:    len(d.Bypass.KilledPIDs),
+		Relaunches:     len(d.Relaunched),
+		BackupRestores: len(d.Restored),
+		Restrictions:   len(d.Restricted),
+	}
+}
@@ -0,0 +1,210 @@
+// Package eventlog is the platform's machine-readable event stream: one JSON
+// object per line (JSONL), one line per protection action, appended next to
+// state.db for external log pipelines (Vector, Fluent Bit, Promtail...) to
+// tail. The slog text log stays the human/whitebox channel; this file is the
+// stable, parseable one.
+//
+// Lines carry only path-free primitives — job/plugin ids, statuses, action
+// counts, app labels, sha prefixes — never a plugin's free-text message or
+// the paths it removed. The file is size-rotated (FileName → FileName.1 …
+// FileName.<keep>) so it can never grow without bound.
+package eventlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the stream's basename in the workdir. Neutral, like svc.log.
+const FileName = "svc.jsonl"
+
+const (
+	// DefaultMaxBytes / DefaultKeep bound the stream to ~4 × 5 MiB.
+	DefaultMaxBytes = 5 << 20
+	DefaultKeep     = 3
+	fileMode        = 0o644
+)
+
+// Event types.
+const (
+	// TypeEnforcement: a run whose plugin reported acting (see Actions).
+	TypeEnforcement = "enforcement"
+	// TypeRunFailed: a run that ended failed, error or timed out.
+	TypeRunFailed = "run_failed"
+	// TypeTamperRepaired / TypeIntegrityCheckFailed mirror the DB events of
+	// the same names (ADR-0019).
+	TypeTamperRepaired       = "tamper_repaired"
+	TypeIntegrityCheckFailed = "integrity_check_failed"
+	// TypeBreakRedeemed: a break token paused Job (Reason carries the
+	// length).
+	TypeBreakRedeemed = "break_redeemed"
+	// TypePauseStarted: `platform pause` stopped the pausable jobs (Reason
+	// carries the length; the user's reason stays in state.db).
+	TypePauseStarted = "pause_started"
+	// TypePolicyChanged: the enforced jobs changed since the last platform
+	// start (Reason carries the counts; job ids stay in state.db).
+	TypePolicyChanged = "policy_changed"
+)
+
+// Event is one line of the stream.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Job  string    `json:"job,omitempty"`
+	// Run is the job_runs row id of the run the event came from.
+	Run     int64    `json:"run,omitempty"`
+	Plugin  string   `json:"plugin,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Actions *Actions `json:"actions,omitempty"`
+	// Reason is a fixed, platform-authored string (never plugin output).
+	Reason  string `json:"reason,omitempty"`
+	WantSHA string `json:"want_sha,omitempty"`
+	GotSHA  string `json:"got_sha,omitempty"`
+}
+
+// Text renders e as one terminal line for `platform watch`: local time,
+// type and job, then what happened. Like the JSON line it carries no paths.
+func (e Event) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %-22s", e.Time.Local().Format("15:04:05"), e.Type)
+	if e.Job != "" {
+		b.WriteString("  " + e.Job)
+	}
+	var what []string
+	if e.Actions != nil {
+		what = e.Actions.parts()
+	}
+	if e.Type == TypeRunFailed && e.Status != "" {
+		what = append(what, e.Status)
+	}
+	if e.Reason != "" {
+		what = append(what, e.Reason)
+	}
+	if e.WantSHA != "" || e.GotSHA != "" {
+		what = append(what, "want "+e.WantSHA+" got "+e.GotSHA)
+	}
+	if len(what) > 0 {
+		b.WriteString(": " + strings.Join(what, ", "))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Log appends events and rotates by size. Safe for concurrent use. A nil
+// *Log is a no-op, so runners without a workdir need no nil checks.
+type Log struct {
+	path     string
+	maxBytes int64
+	keep     int
+	now      func() time.Time
+	mu       sync.Mutex
+
+	// subs are live subscribers (Subscribe); each gets every appended event.
+	subMu sync.Mutex
+	subs  map[chan Event]struct{}
+}
+
+// subBuffer is how many events a slow subscriber may lag before new ones
+// are dropped for it (the file still gets every event).
+const subBuffer = 64
+
+// New returns a Log writing dir/FileName with the default bounds.
+func New(dir string) *Log {
+	return &Log{path: filepath.Join(dir, FileName), maxBytes: DefaultMaxBytes, keep: DefaultKeep, now: time.Now}
+}
+
+// Append writes ev as one line, stamping Time when unset. A line that would
+// push the file past maxBytes rotates first.
+func (l *Log) Append(ev Event) error {
+	if l == nil {
+		return nil
+	}
+	if ev.Time.IsZero() {
+		ev.Time = l.now()
+	}
+	ev.Time = ev.Time.UTC()
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if fi, err := os.Stat(l.path); err == nil && fi.Size() > 0 && fi.Size()+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotate event log: %w", err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileMode)
+	if err != nil {
+		return err
+	}
+	// One write call per line: O_APPEND keeps a tailing reader from ever
+	// seeing two events interleaved.
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	l.publish(ev)
+	return f.Close()
+}
+
+// Subscribe returns a channel of events appended from now on and a cancel
+// func that unsubscribes and closes it. A subscriber that falls subBuffer
+// events behind misses the overflow rather than slowing Append. A nil *Log
+// returns a channel that never delivers.
+func (l *Log) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subBuffer)
+	if l == nil {
+		return ch, func() {}
+	}
+	l.subMu.Lock()
+	if l.subs == nil {
+		l.subs = map[chan Event]struct{}{}
+	}
+	l.subs[ch] = struct{}{}
+	l.subMu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			l.subMu.Lock()
+			delete(l.subs, ch)
+			l.subMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (l *Log) publish(ev Event) {
+	l.subMu.Lock()
+	defer l.subMu.Unlock()
+	for ch := range l.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// rotate shifts FileName.i → FileName.i+1 (dropping the oldest) and moves
+// the live file to FileName.1. Caller holds l.mu.
+func (l *Log) rotate() error {
+	if err := os.Remove(l.rotated(l.keep)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for i := l.keep - 1; i >= 1; i-- {
+		if err := os.Rename(l.rotated(i), l.rotated(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(l.path, l.rotated(1))
+}
+
+func (l *Log) rotated(i int) string { return fmt.Sprintf("%s.%d", l.path, i) }
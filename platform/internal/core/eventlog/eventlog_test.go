@@ -0,0 +1,172 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var out []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("line %q is not one JSON event: %v", sc.Text(), err)
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+func TestAppendWritesJSONL(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return at }
+	acts := ParseActions(`{"details":{"killed_count":3,"blocked_apps":["Dota 2"],"uninstall_removed":["/Applications/Steam.app"]}}`)
+	if err := l.Append(Event{Type: TypeEnforcement, Job: "kill", Actions: &acts}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Append(Event{Type: TypeRunFailed, Job: "kill", Status: "timedout"}); err != nil {
+		t.Fatal(err)
+	}
+	evs := readEvents(t, filepath.Join(dir, FileName))
+	if len(evs) != 2 || !evs[0].Time.Equal(at) || evs[0].Actions.Kills != 3 || evs[0].Actions.Removals != 1 ||
+		evs[1].Status != "timedout" {
+		t.Fatalf("events = %+v", evs)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, FileName))
+	if strings.Contains(string(b), "Steam.app") {
+		t.Fatal("removed paths must be counted, never written")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir)
+	l.maxBytes, l.keep = 300, 2
+	for i := 0; i < 40; i++ {
+		if err := l.Append(Event{Type: TypeRunFailed, Job: "job", Status: "error"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{FileName, FileName + ".1", FileName + ".2"} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if fi.Size() > 300 {
+			t.Errorf("%s is %d bytes, over the bound", name, fi.Size())
+		}
+		readEvents(t, filepath.Join(dir, name)) // every file is whole lines
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName+".3")); !os.IsNotExist(err) {
+		t.Fatal("rotation must keep only `keep` old files")
+	}
+}
+
+func TestConcurrentAppendsStayWhole(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.Append(Event{Type: TypeEnforcement, Job: "j", Actions: &Actions{Kills: 1}})
+		}()
+	}
+	wg.Wait()
+	if n := len(readEvents(t, filepath.Join(dir, FileName))); n != 20 {
+		t.Fatalf("got %d events, want 20", n)
+	}
+}
+
+func TestNilLogIsNoop(t *testing.T) {
+	var l *Log
+	if err := l.Append(Event{Type: TypeRunFailed}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	if a := ParseActions(`not json`); a.Any() {
+		t.Errorf("garbage parsed as %+v", a)
+	}
+	if a := ParseActions(`{"details":{"killed_count":0,"blocked_apps":[]}}`); a.Any() {
+		t.Errorf("a no-op run parsed as acting: %+v", a)
+	}
+	a := ParseActions(`{"details":{"relaunched":["app","proxy"],"bypass":{"killed_pids":[1,2,3]}}}`)
+	if a.Relaunches != 2 || a.BypassKills != 3 || !a.Any() {
+		t.Errorf("ParseActions = %+v", a)
+	}
+	if a := ParseActions(`{"details":{"backup_restored":["/Users/a/Library/Application Support/Steam"]}}`); a.BackupRestores != 1 || !a.Blocked() {
+		t.Errorf("a restore from backup is a blocked attempt: %+v", a)
+	}
+	if a := ParseActions(`{"details":{"net_restricted":[501,502]}}`); a.Restrictions != 2 || !a.Blocked() {
+		t.Errorf("a network cut is a block: %+v", a)
+	}
+}
+
+func TestSubscribeSeesNewEvents(t *testing.T) {
+	l := New(t.TempDir())
+	if err := l.Append(Event{Type: TypeRunFailed, Job: "before"}); err != nil {
+		t.Fatal(err)
+	}
+	ch, cancel := l.Subscribe()
+	if err := l.Append(Event{Type: TypeRunFailed, Job: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-ch:
+		if ev.Job != "after" {
+			t.Fatalf("got %q, want only events appended after Subscribe", ev.Job)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber saw nothing")
+	}
+	cancel()
+	cancel() // idempotent
+	if _, open := <-ch; open {
+		t.Fatal("channel still open after cancel")
+	}
+	if err := l.Append(Event{Type: TypeRunFailed}); err != nil {
+		t.Fatalf("append after unsubscribe: %v", err)
+	}
+}
+
+func TestEventText(t *testing.T) {
+	at := time.Date(2026, 5, 1, 9, 30, 5, 0, time.Local)
+	cases := []struct {
+		ev   Event
+		want string
+	}{
+		{Event{Time: at, Type: TypeEnforcement, Job: "kill-steam-reconcile",
+			Actions: &Actions{Kills: 2, Removals: 1, BlockedApps: []string{"Steam"}}},
+			"09:30:05  enforcement             kill-steam-reconcile: 2 kills, 1 removals, blocked Steam"},
+		{Event{Time: at, Type: TypeEnforcement, Job: "freedom", Actions: &Actions{Relaunches: 1}},
+			"09:30:05  enforcement             freedom: 1 relaunches"},
+		{Event{Time: at, Type: TypeRunFailed, Job: "dns", Status: "timeout"},
+			"09:30:05  run_failed              dns: timeout"},
+		{Event{Time: at, Type: TypeTamperRepaired, Job: "kill-steam", WantSHA: "ab12", GotSHA: "cd34"},
+			"09:30:05  tamper_repaired         kill-steam: want ab12 got cd34"},
+		{Event{Time: at, Type: TypePolicyChanged}, "09:30:05  policy_changed"},
+	}
+	for _, c := range cases {
+		if got := c.ev.Text(); got != c.want {
+			t.Errorf("Text() =\n%q\nwant\n%q", got, c.want)
+		}
+	}
+}
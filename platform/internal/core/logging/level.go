@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxOverride bounds how long a runtime level change lasts: a debug window
+// left open by a forgotten reproduction must still close on its own.
+const MaxOverride = 24 * time.Hour
+
+// Level is the running logger's level. It starts at the configured level;
+// Override moves it for a window (`daemon log-level debug --for 10m`, over
+// the admin socket), after which it falls back on its own, so verbose
+// logging never outlives the reproduction it was turned on for.
+type Level struct {
+	v    slog.LevelVar
+	base slog.Level
+
+	mu    sync.Mutex
+	until time.Time
+	timer *time.Timer
+}
+
+// NewLevel returns a Level at the configured level (see parseLevel).
+func NewLevel(level string) *Level {
+	l := &Level{base: parseLevel(level)}
+	l.v.Set(l.base)
+	return l
+}
+
+// Level implements slog.Leveler.
+func (l *Level) Level() slog.Level { return l.v.Level() }
+
+// Base is the configured level a window falls back to.
+func (l *Level) Base() slog.Level { return l.base }
+
+// Until is when the current window ends; zero when none is open.
+func (l *Level) Until() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.until
+}
+
+// Override runs at level for d, replacing any open window. d <= 0 closes
+// the window now; d past MaxOverride is refused.
+func (l *Level) Override(level slog.Level, d time.Duration) error {
+	if d > MaxOverride {
+		return errors.New("logging: override longer than MaxOverride")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if d <= 0 {
+		l.v.Set(l.base)
+		l.until = time.Time{}
+		return nil
+	}
+	l.v.Set(level)
+	l.until = time.Now().Add(d)
+	l.timer = time.AfterFunc(d, l.expire)
+	return nil
+}
+
+// expire closes the window unless a later Override moved its end.
+func (l *Level) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.until.IsZero() || time.Now().Before(l.until) {
+		return
+	}
+	l.v.Set(l.base)
+	l.until, l.timer = time.Time{}, nil
+}
+
+// ParseLevel is parseLevel for callers that must reject a name it does not
+// know instead of defaulting to info.
+func ParseLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug", "info", "warn", "warning", "error":
+		return parseLevel(s), true
+	}
+	return 0, false
+}
@@ -18,11 +18,36 @@ import (
 // daemon redirects the child's stdio to the same file in the workdir).
 const LogName = "svc.log"
 
-// New builds a slog.Logger at the given level, teeing to stderr and, if
-// logDir is non-empty, to <logDir>/svc.log.
-func New(level, logDir string) (*slog.Logger, func() error, error) {
+// LevelEnv / FormatEnv override the configured log level and pick the
+// encoding (`daemon logging`, handed over by the daemon). MUST match the
+// daemon's platformsvc.LogLevelEnvKey / LogFormatEnvKey.
+const (
+	LevelEnv  = "APP_LOG_LEVEL"
+	FormatEnv = "APP_LOG_FORMAT"
+)
+
+// SinkEnv adds a sink beside the file (`daemon config set log.sink`).
+// "syslog" also writes each line to the system log, where macOS keeps it in
+// the unified log. MUST match the daemon's platformsvc.LogSinkEnvKey.
+const SinkEnv = "APP_LOG_SINK"
+
+// SinkSyslog is the SinkEnv value that adds the system log.
+const SinkSyslog = "syslog"
+
+// New builds a slog.Logger at level, teeing to stderr and, if logDir is
+// non-empty, to <logDir>/svc.log, and to the system log when sink is
+// SinkSyslog. format "json" writes JSON lines; anything else the text
+// format. A system log that cannot be reached is skipped: the file is the
+// record.
+func New(level *Level, format, sink, logDir string) (*slog.Logger, func() error, error) {
 	w := io.Writer(os.Stderr)
 	closer := func() error { return nil }
+	if sink == SinkSyslog {
+		if sw, err := openSyslog(); err == nil {
+			w = io.MultiWriter(w, sw)
+			closer = sw.Close
+		}
+	}
 
 	if logDir != "" {
 		if err := os.MkdirAll(logDir, 0o755); err != nil {
@@ -33,12 +58,19 @@ func New(level, logDir string) (*slog.Logger, func() error, error) {
 		if err != nil {
 			return nil, nil, fmt.Errorf("open log file: %w", err)
 		}
-		w = io.MultiWriter(os.Stderr, f)
-		closer = f.Close
+		w = io.MultiWriter(w, f)
+		prev := closer
+		closer = func() error {
+			prev()
+			return f.Close()
+		}
 	}
 
-	h := slog.NewTextHandler(w, &slog.HandlerOptions{Level: parseLevel(level)})
-	return slog.New(h), closer, nil
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts)), closer, nil
+	}
+	return slog.New(slog.NewTextHandler(w, opts)), closer, nil
 }
 
 func parseLevel(s string) slog.Level {
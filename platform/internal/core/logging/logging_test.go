@@ -1,10 +1,12 @@
 package logging
 
 import (
+	"encoding/json"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestParseLevel(t *testing.T) {
@@ -23,7 +25,7 @@ func TestParseLevel(t *testing.T) {
 
 func TestNewWritesToFile(t *testing.T) {
 	dir := t.TempDir()
-	log, closer, err := New("debug", dir)
+	log, closer, err := New(NewLevel("debug"), "", "", dir)
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
@@ -41,7 +43,7 @@ func TestNewWritesToFile(t *testing.T) {
 }
 
 func TestNewNoFileWhenDirEmpty(t *testing.T) {
-	log, closer, err := New("info", "")
+	log, closer, err := New(NewLevel("info"), "", "", "")
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
@@ -50,3 +52,51 @@ func TestNewNoFileWhenDirEmpty(t *testing.T) {
 		t.Errorf("closer: %v", err)
 	}
 }
+
+func TestNewJSONFormat(t *testing.T) {
+	dir := t.TempDir()
+	log, closer, err := New(NewLevel("info"), "json", "", dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	log.Debug("dropped")
+	log.Info("hello", "k", "v")
+	closer()
+	b, err := os.ReadFile(filepath.Join(dir, LogName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(b, &line); err != nil || line["msg"] != "hello" || line["k"] != "v" {
+		t.Fatalf("log = %q (%v), want one JSON line", b, err)
+	}
+}
+
+// TestLevelOverrideReverts: a window raises the level, then falls back to
+// the configured one on its own; a zero window closes it at once.
+func TestLevelOverrideReverts(t *testing.T) {
+	l := NewLevel("info")
+	if err := l.Override(slog.LevelDebug, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if l.Level() != slog.LevelDebug || l.Until().IsZero() {
+		t.Fatalf("level %v until %v, want debug with a deadline", l.Level(), l.Until())
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Level() != slog.LevelInfo {
+		if time.Now().After(deadline) {
+			t.Fatal("window never closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !l.Until().IsZero() {
+		t.Fatal("closed window keeps a deadline")
+	}
+	_ = l.Override(slog.LevelError, time.Hour)
+	if err := l.Override(0, 0); err != nil || l.Level() != slog.LevelInfo {
+		t.Fatalf("level %v (%v), want info after a zero window", l.Level(), err)
+	}
+	if l.Override(slog.LevelDebug, MaxOverride+time.Second) == nil {
+		t.Fatal("window past MaxOverride accepted")
+	}
+}
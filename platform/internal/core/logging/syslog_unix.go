@@ -0,0 +1,17 @@
+//go:build !windows
+
+package logging
+
+import (
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+)
+
+// openSyslog dials the system log, tagged with this process's own name:
+// on macOS `log stream --predicate 'process == "NAME"'` finds the lines.
+// The base name only, since the full path is the install's secret.
+func openSyslog() (io.WriteCloser, error) {
+	return syslog.New(syslog.LOG_NOTICE|syslog.LOG_DAEMON, filepath.Base(os.Args[0]))
+}
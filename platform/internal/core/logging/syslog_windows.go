@@ -0,0 +1,11 @@
+package logging
+
+import (
+	"errors"
+	"io"
+)
+
+// openSyslog: Windows has no syslog; the file stays the only sink.
+func openSyslog() (io.WriteCloser, error) {
+	return nil, errors.New("logging: no system log on windows")
+}
@@ -0,0 +1,99 @@
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+)
+
+func TestSealOpen(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, _ := ParsePublicKey(pub)
+	key, _ := ParsePrivateKey(priv)
+	env, err := Seal(to, []byte("three kills today"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Open(key, env); err != nil || string(got) != "three kills today" {
+		t.Fatalf("open = %q, %v", got, err)
+	}
+	_, other, _ := GenerateKey()
+	wrong, _ := ParsePrivateKey(other)
+	if _, err := Open(wrong, env); err == nil {
+		t.Fatal("another key opened the envelope")
+	}
+	if _, err := ParsePublicKey("not a key"); err != ErrKey {
+		t.Fatalf("bad key: %v", err)
+	}
+}
+
+// TestShipperKeepsBatchUntilTaken: a refused batch stays queued and goes
+// out whole with the next flush.
+func TestShipperKeepsBatchUntilTaken(t *testing.T) {
+	pub, priv, _ := GenerateKey()
+	to, _ := ParsePublicKey(pub)
+	key, _ := ParsePrivateKey(priv)
+	var (
+		mu     sync.Mutex
+		refuse = true
+		got    []Batch
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if refuse {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var env Envelope
+		_ = json.NewDecoder(r.Body).Decode(&env)
+		plain, err := Open(key, env)
+		if err != nil {
+			t.Errorf("the reader could not open the envelope: %v", err)
+		}
+		var b Batch
+		_ = json.Unmarshal(plain, &b)
+		got = append(got, b)
+	}))
+	defer srv.Close()
+	s := &Shipper{URL: srv.URL, Key: to, Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	s.add(eventlog.Event{Type: eventlog.TypeEnforcement, Job: "kill-steam"})
+	s.add(eventlog.Event{Type: eventlog.TypeRunFailed, Job: "dns-block"})
+	if err := s.Flush(context.Background()); err == nil {
+		t.Fatal("a refused batch reported success")
+	}
+	mu.Lock()
+	refuse = false
+	mu.Unlock()
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Events) != 2 || got[0].Events[1].Job != "dns-block" {
+		t.Fatalf("server got %+v", got)
+	}
+	if len(s.pending) != 0 {
+		t.Fatal("a delivered batch stayed queued")
+	}
+}
+
+// TestShipperBoundsQueue: past MaxPending the oldest events go, and the
+// count travels with the next batch.
+func TestShipperBoundsQueue(t *testing.T) {
+	s := &Shipper{}
+	for i := range MaxPending + 3 {
+		s.add(eventlog.Event{Run: int64(i)})
+	}
+	if len(s.pending) != MaxPending || s.dropped != 3 || s.pending[0].Run != 3 {
+		t.Fatalf("pending %d, dropped %d, first run %d", len(s.pending), s.dropped, s.pending[0].Run)
+	}
+}
@@ -0,0 +1,159 @@
+// Package logship ships the event stream (package eventlog) off the Mac in
+// batches, sealed so only the holder of one private key can read them. The
+// receiving server stores opaque envelopes, so a wiped Mac keeps its
+// history and a partner's dashboard can read it, while the server cannot.
+//
+// A batch is sealed to an X25519 public key: a fresh ephemeral key per
+// batch, ECDH, HKDF-SHA256, then AES-256-GCM. Open is the reader's half
+// (`platform ship open`).
+package logship
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+)
+
+// URLEnv and KeyEnv carry the ship URL and the recipient's public key from
+// the daemon (`daemon config set log.ship` / `log.ship-key`). Neutral, like
+// the other APP_* keys; the URL may carry a token and is never logged.
+const (
+	URLEnv = "APP_SHIP_URL"
+	KeyEnv = "APP_SHIP_KEY"
+)
+
+// Version is the envelope format.
+const Version = 1
+
+// hkdfInfo binds the derived key to this format.
+const hkdfInfo = "focusd logship v1"
+
+// ErrKey: a key is not a base64 X25519 key.
+var ErrKey = errors.New("logship: not a base64 X25519 key")
+
+// Envelope is one sealed batch, the body of each POST. Byte fields are
+// standard base64.
+type Envelope struct {
+	V     int    `json:"v"`
+	EPK   string `json:"epk"`
+	Nonce string `json:"nonce"`
+	CT    string `json:"ct"`
+}
+
+// ValidURL accepts an absolute https URL.
+func ValidURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// ParsePublicKey reads a base64 X25519 public key.
+func ParsePublicKey(s string) (*ecdh.PublicKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrKey
+	}
+	k, err := ecdh.X25519().NewPublicKey(b)
+	if err != nil {
+		return nil, ErrKey
+	}
+	return k, nil
+}
+
+// ParsePrivateKey reads a base64 X25519 private key.
+func ParsePrivateKey(s string) (*ecdh.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrKey
+	}
+	k, err := ecdh.X25519().NewPrivateKey(b)
+	if err != nil {
+		return nil, ErrKey
+	}
+	return k, nil
+}
+
+// GenerateKey makes a key pair for a reader, both halves base64.
+func GenerateKey() (public, private string, err error) {
+	k, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	enc := base64.StdEncoding.EncodeToString
+	return enc(k.PublicKey().Bytes()), enc(k.Bytes()), nil
+}
+
+// Seal encrypts plaintext to to.
+func Seal(to *ecdh.PublicKey, plaintext []byte) (Envelope, error) {
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return Envelope{}, err
+	}
+	aead, err := envelopeAEAD(eph, to, eph.PublicKey(), to)
+	if err != nil {
+		return Envelope{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Envelope{}, err
+	}
+	enc := base64.StdEncoding.EncodeToString
+	return Envelope{
+		V:     Version,
+		EPK:   enc(eph.PublicKey().Bytes()),
+		Nonce: enc(nonce),
+		CT:    enc(aead.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+// Open decrypts an envelope sealed to key's public half.
+func Open(key *ecdh.PrivateKey, e Envelope) ([]byte, error) {
+	if e.V != Version {
+		return nil, errors.New("logship: unknown envelope version")
+	}
+	epk, err := ParsePublicKey(e.EPK)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(e.Nonce)
+	if err != nil {
+		return nil, errors.New("logship: bad nonce")
+	}
+	ct, err := base64.StdEncoding.DecodeString(e.CT)
+	if err != nil {
+		return nil, errors.New("logship: bad ciphertext")
+	}
+	aead, err := envelopeAEAD(key, epk, epk, key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("logship: bad nonce")
+	}
+	return aead.Open(nil, nonce, ct, nil)
+}
+
+// envelopeAEAD derives the batch key from the ECDH of mine and peer, salted
+// with the ephemeral and recipient public keys so a key is never reused
+// across batches or recipients.
+func envelopeAEAD(mine *ecdh.PrivateKey, peer, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := mine.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, ephemeral.Bytes()...), recipient.Bytes()...)
+	key, err := hkdf.Key(sha256.New, shared, salt, hkdfInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
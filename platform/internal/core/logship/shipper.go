@@ -0,0 +1,128 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+)
+
+const (
+	// DefaultEvery is how often a batch goes out.
+	DefaultEvery = time.Minute
+	// MaxBatch caps the events in one envelope; a fuller queue sends early.
+	MaxBatch = 500
+	// MaxPending bounds what is held while the server is unreachable. The
+	// oldest events go first, and the next batch says how many.
+	MaxPending = 5000
+)
+
+// Batch is the plaintext of one envelope.
+type Batch struct {
+	Events []eventlog.Event `json:"events"`
+	// Dropped counts the events lost to a full queue since the last batch
+	// the server took.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// Shipper batches events and POSTs each batch, sealed to Key, to URL.
+type Shipper struct {
+	URL    string
+	Key    *ecdh.PublicKey
+	Client *http.Client
+	Log    *slog.Logger
+	// Every is the send interval; 0 ⇒ DefaultEvery.
+	Every time.Duration
+
+	pending []eventlog.Event
+	dropped int
+}
+
+// Run ships events from the stream until ctx is done or the stream closes.
+// A batch the server does not take stays queued for the next interval.
+func (s *Shipper) Run(ctx context.Context, events <-chan eventlog.Event) {
+	every := s.Every
+	if every <= 0 {
+		every = DefaultEvery
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			s.add(ev)
+			if len(s.pending) >= MaxBatch {
+				_ = s.Flush(ctx)
+			}
+		case <-t.C:
+			_ = s.Flush(ctx)
+		}
+	}
+}
+
+func (s *Shipper) add(ev eventlog.Event) {
+	if len(s.pending) >= MaxPending {
+		s.pending = s.pending[1:]
+		s.dropped++
+	}
+	s.pending = append(s.pending, ev)
+}
+
+// Flush sends up to MaxBatch queued events as one envelope.
+func (s *Shipper) Flush(ctx context.Context) error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	n := min(len(s.pending), MaxBatch)
+	if err := s.send(ctx, Batch{Events: s.pending[:n], Dropped: s.dropped}); err != nil {
+		s.Log.Warn("event shipping failed; batch kept", "events", n, "err", err.Error())
+		return err
+	}
+	s.pending = append(s.pending[:0], s.pending[n:]...)
+	s.dropped = 0
+	return nil
+}
+
+func (s *Shipper) send(ctx context.Context, b Batch) error {
+	plain, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("%T", err)
+	}
+	env, err := Seal(s.Key, plain)
+	if err != nil {
+		return fmt.Errorf("%T", err)
+	}
+	body, _ := json.Marshal(env)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%T", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error text embeds the URL, which may carry a token.
+		return fmt.Errorf("%T", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Package pause is the friction in front of `platform pause`: before the
+// pausable jobs stop, the user transcribes a fixed passage by hand, then
+// waits out the configured cooldown. A pause that takes longer to earn
+// than the impulse it answers lasts is one only a deliberate user takes.
+//
+// Like the daemon's uninstall gate (a separate module; the comparison is
+// mirrored rather than shared), this is friction, not a seal: the passage
+// is open source, and the wait is the real defense.
+package pause
+
+import (
+	_ "embed"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Tunables for accepting a transcription.
+const (
+	SimilarityThreshold = 0.95             // normalized match; forgives a typo or two
+	MinTypingDuration   = 30 * time.Second // submitted faster ⇒ almost certainly pasted
+)
+
+//go:embed passage.txt
+var passage string
+
+// Passage is the text the user must transcribe.
+func Passage() string { return passage }
+
+// Accept reports whether typed is a good enough transcription of Passage,
+// entered over elapsed. The reason is for the user when it is not.
+func Accept(typed string, elapsed time.Duration) (bool, string) {
+	if elapsed < MinTypingDuration {
+		return false, "submitted too fast — type the passage out by hand"
+	}
+	if similarity(typed, passage) < SimilarityThreshold {
+		return false, "transcription does not match closely enough"
+	}
+	return true, ""
+}
+
+// normalize folds case and collapses every run of whitespace to one space,
+// so line breaks and double spaces do not count against the user. Words and
+// punctuation still must be typed.
+func normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	prevSpace := false
+	for _, r := range strings.TrimSpace(s) {
+		if unicode.IsSpace(r) {
+			if !prevSpace {
+				b.WriteByte(' ')
+				prevSpace = true
+			}
+			continue
+		}
+		prevSpace = false
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// similarity is 1 - levenshtein(a, b) / max(len) over the normalized texts.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(normalize(a)), []rune(normalize(b))
+	n := max(len(ra), len(rb))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(n)
+}
+
+// levenshtein is the two-row edit distance.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(cur[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
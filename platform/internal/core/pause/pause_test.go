@@ -0,0 +1,29 @@
+package pause
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccept(t *testing.T) {
+	reflowed := strings.ToUpper(strings.Join(strings.Fields(Passage()), "  "))
+	typo := strings.Replace(Passage(), "deliberate", "deliberat", 1)
+	typo = strings.Replace(typo, "protection", "protectoin", 1)
+	for name, tc := range map[string]struct {
+		typed   string
+		elapsed time.Duration
+		ok      bool
+	}{
+		"exact":       {Passage(), time.Minute, true},
+		"reflowed":    {reflowed, time.Minute, true},
+		"a typo":      {typo, time.Minute, true},
+		"pasted fast": {Passage(), time.Second, false},
+		"first line":  {strings.SplitN(Passage(), "\n", 2)[0], time.Minute, false},
+		"empty":       {"", time.Minute, false},
+	} {
+		if ok, _ := Accept(tc.typed, tc.elapsed); ok != tc.ok {
+			t.Errorf("%s: accepted = %v, want %v", name, ok, tc.ok)
+		}
+	}
+}
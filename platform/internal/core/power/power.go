@@ -0,0 +1,170 @@
+// Package power tells the scheduler when the Mac is on battery or
+// thermally throttled, so job cadence can back off (config
+// platform.backoff). A 10-second reconcile and a per-minute bundle hash are
+// cheap on mains power and a measurable drain on a laptop battery.
+//
+// It also tells when nobody is at the machine — the screen locked, or no
+// keyboard or mouse input for IdleAfter — so cadence can drop further, and
+// when the user is back, so every job can run at once.
+package power
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State is what the last probe saw. The zero State is unconstrained: mains
+// power, no throttling, and every platform without a probe.
+type State struct {
+	Battery bool
+	Thermal bool
+	// Idle: the console screen is locked or there has been no input for
+	// IdleAfter. Only probed when the Watcher has an IdleProbe.
+	Idle bool
+}
+
+// Constrained reports whether cadence should back off.
+func (s State) Constrained() bool { return s.Battery || s.Thermal }
+
+// Reason is a fixed phrase for logs and status, "" when unconstrained and
+// not idle.
+func (s State) Reason() string {
+	var parts []string
+	if s.Idle {
+		parts = append(parts, "idle")
+	}
+	if s.Battery {
+		parts = append(parts, "on battery")
+	}
+	if s.Thermal {
+		parts = append(parts, "thermal pressure")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkEvery is how often Run probes. Power source changes are rare, and
+// a probe is two short pmset runs and at most two ioreg runs. Idleness is
+// read on the same probe: it stretches cadence to the backoff's max (5m by
+// default), so noticing the return within a minute is enough, and a
+// faster poll would itself keep a sleeping-user laptop busy all night.
+const checkEvery = time.Minute
+
+// IdleAfter is how long without input counts as idle.
+const IdleAfter = 5 * time.Minute
+
+// Watcher probes the power state and reports changes.
+type Watcher struct {
+	// Probe reads the current state; nil uses Read.
+	Probe func(ctx context.Context) State
+	// OnChange is called from Run whenever the state differs from the last
+	// probe, including a first probe that finds the machine constrained.
+	OnChange func(State)
+	// IdleProbe reads idleness; nil never reads the machine as idle.
+	IdleProbe func(ctx context.Context) bool
+	// OnReturn is called from Run when an idle machine is in use again,
+	// after OnChange.
+	OnReturn func()
+
+	mu  sync.Mutex
+	cur State
+}
+
+// Current returns the last probed state.
+func (w *Watcher) Current() State {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cur
+}
+
+// Run probes now and every minute until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	for {
+		w.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(checkEvery):
+		}
+	}
+}
+
+// check probes the power state and idleness once.
+func (w *Watcher) check(ctx context.Context) {
+	probe := w.Probe
+	if probe == nil {
+		probe = Read
+	}
+	s := probe(ctx)
+	s.Idle = w.IdleProbe != nil && w.IdleProbe(ctx)
+	w.mu.Lock()
+	prev := w.cur
+	w.cur = s
+	w.mu.Unlock()
+	if s != prev && w.OnChange != nil {
+		w.OnChange(s)
+	}
+	if prev.Idle && !s.Idle && w.OnReturn != nil {
+		w.OnReturn()
+	}
+}
+
+// parseHIDIdle reads `ioreg -c IOHIDSystem -d 4`: HIDIdleTime is the
+// nanoseconds since the last keyboard or mouse input. ok is false when
+// there is none to read.
+func parseHIDIdle(out string) (d time.Duration, ok bool) {
+	for _, line := range strings.Split(out, "\n") {
+		_, val, found := strings.Cut(line, `"HIDIdleTime" = `)
+		if !found {
+			continue
+		}
+		if n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64); err == nil {
+			return time.Duration(n), true
+		}
+	}
+	return 0, false
+}
+
+// parseLocked reads `ioreg -n Root -d 1`: IOConsoleUsers holds one
+// dictionary per login session, and the screen is locked when the one on
+// the console says so. A user switched away from, locked, does not count.
+func parseLocked(out string) bool {
+	for _, session := range strings.Split(out, "},{") {
+		if strings.Contains(session, `"kCGSSessionOnConsoleKey"=Yes`) &&
+			strings.Contains(session, `"CGSSessionScreenIsLocked"=Yes`) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBatt reads `pmset -g batt`: its first line names the power source,
+// "Now drawing from 'Battery Power'" or "'AC Power'".
+func parseBatt(out string) bool {
+	return strings.Contains(out, "'Battery Power'")
+}
+
+// parseTherm reads `pmset -g therm`: the CPU is throttled when
+// CPU_Speed_Limit is below 100 (percent), or when a thermal warning level
+// is recorded.
+func parseTherm(out string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Note:") {
+			continue
+		}
+		if strings.Contains(strings.ToLower(line), "thermal warning level") {
+			return true
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "CPU_Speed_Limit" {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(val)); err == nil && n < 100 {
+			return true
+		}
+	}
+	return false
+}
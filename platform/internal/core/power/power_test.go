@@ -0,0 +1,84 @@
+package power
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseBatt(t *testing.T) {
+	if !parseBatt("Now drawing from 'Battery Power'\n -InternalBattery-0 (id=1)\t81%; discharging;") {
+		t.Error("battery not detected")
+	}
+	if parseBatt("Now drawing from 'AC Power'\n -InternalBattery-0 (id=1)\t100%; charged;") {
+		t.Error("AC read as battery")
+	}
+}
+
+func TestParseTherm(t *testing.T) {
+	calm := "Note: No thermal warning level has been recorded\nNote: No performance warning level has been recorded\n" +
+		"2026-10-16 09:00:00 +1100 CPU Power notify\n\tCPU_Scheduler_Limit \t= 100\n\tCPU_Available_CPUs \t= 8\n\tCPU_Speed_Limit \t= 100\n"
+	if parseTherm(calm) {
+		t.Error("calm machine read as throttled")
+	}
+	if !parseTherm("\tCPU_Speed_Limit \t= 70\n") {
+		t.Error("speed limit 70 not detected")
+	}
+	if !parseTherm("Thermal warning level set to 2.\n") {
+		t.Error("warning level not detected")
+	}
+}
+
+func TestWatcherReportsChanges(t *testing.T) {
+	cur := State{}
+	var seen []State
+	w := &Watcher{Probe: func(context.Context) State { return cur }, OnChange: func(s State) { seen = append(seen, s) }}
+	w.check(context.Background())
+	if len(seen) != 0 {
+		t.Fatalf("an unconstrained first probe is no change: %v", seen)
+	}
+	cur = State{Battery: true}
+	w.check(context.Background())
+	w.check(context.Background())
+	cur = State{}
+	w.check(context.Background())
+	if len(seen) != 2 || !seen[0].Battery || seen[1].Constrained() {
+		t.Fatalf("changes = %v", seen)
+	}
+	if got := (State{Battery: true, Thermal: true}).Reason(); got != "on battery, thermal pressure" {
+		t.Errorf("reason = %q", got)
+	}
+}
+
+func TestParseIdle(t *testing.T) {
+	hid := "    | |   \"HIDIdleTime\" = 420000000000\n    | |   \"HIDParameters\" = {}\n"
+	if d, ok := parseHIDIdle(hid); !ok || d != 7*time.Minute {
+		t.Errorf("HIDIdleTime read as %s (ok %v)", d, ok)
+	}
+	if _, ok := parseHIDIdle("no idle here"); ok {
+		t.Error("missing HIDIdleTime read as present")
+	}
+	locked := `  "IOConsoleUsers" = ({"kCGSSessionOnConsoleKey"=Yes,"CGSSessionScreenIsLocked"=Yes,"kCGSSessionUserNameKey"="a"})`
+	switched := `  "IOConsoleUsers" = ({"kCGSSessionOnConsoleKey"=No,"CGSSessionScreenIsLocked"=Yes,"kCGSSessionUserNameKey"="a"},{"kCGSSessionOnConsoleKey"=Yes,"kCGSSessionUserNameKey"="b"})`
+	if !parseLocked(locked) || parseLocked(switched) {
+		t.Error("console lock misread")
+	}
+}
+
+func TestWatcherRunsOnReturn(t *testing.T) {
+	idle := false
+	returned := 0
+	w := &Watcher{Probe: func(context.Context) State { return State{Battery: true} },
+		IdleProbe: func(context.Context) bool { return idle }, OnReturn: func() { returned++ }}
+	w.check(context.Background())
+	idle = true
+	w.check(context.Background())
+	if s := w.Current(); !s.Idle || !s.Battery || s.Reason() != "idle, on battery" {
+		t.Fatalf("an idle check must keep the power reading: %+v", s)
+	}
+	idle = false
+	w.check(context.Background())
+	if returned != 1 {
+		t.Fatalf("OnReturn called %d times, want 1", returned)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build darwin
+
+package power
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// Read probes the power source and thermal state with pmset. A probe that
+// fails reads as unconstrained: backing off is an optimisation, and never
+// a reason to enforce less on a guess.
+func Read(ctx context.Context) State {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var s State
+	if out, err := exec.CommandContext(ctx, "pmset", "-g", "batt").Output(); err == nil {
+		s.Battery = parseBatt(string(out))
+	}
+	if out, err := exec.CommandContext(ctx, "pmset", "-g", "therm").Output(); err == nil {
+		s.Thermal = parseTherm(string(out))
+	}
+	return s
+}
+
+// ReadIdle reports whether the console screen is locked or there has been
+// no input for IdleAfter. HIDIdleTime is read first, so an idle machine
+// costs one ioreg run per probe; the lock is read only while input is
+// recent. A probe that fails reads as in use, for the same reason Read's
+// failures read as unconstrained.
+func ReadIdle(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "ioreg", "-c", "IOHIDSystem", "-d", "4").Output(); err == nil {
+		if d, ok := parseHIDIdle(string(out)); ok && d >= IdleAfter {
+			return true
+		}
+	}
+	out, err := exec.CommandContext(ctx, "ioreg", "-n", "Root", "-d", "1").Output()
+	return err == nil && parseLocked(string(out))
+}
@@ -0,0 +1,13 @@
+//go:build !darwin
+
+package power
+
+import "context"
+
+// Read has no probe off macOS: the platform's laptop target is the Mac,
+// and elsewhere the cadence never backs off.
+func Read(context.Context) State { return State{} }
+
+// ReadIdle has no probe off macOS either: the machine always reads as in
+// use.
+func ReadIdle(context.Context) bool { return false }
@@ -152,3 +152,22 @@ exit 0`)
 		}
 	}
 }
+
+// TestLogBackupRestoreWarnsWithoutPath: a plugin reporting an app put back
+// from a backup yields one WARN naming the job and the count — the restored
+// path stays in the run row.
+func TestLogBackupRestoreWarnsWithoutPath(t *testing.T) {
+	r, buf := capturedLog(t)
+	p := testutil.ScriptPlugin(t, "ok-plugin", `echo '{"status":"ok","details":{"backup_restored":["/Users/a/Library/Application Support/Steam"]}}'
+exit 0`)
+	if _, err := r.Run(context.Background(), Job{ID: "j1"}, p, "scheduler"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	warns := linesAtLevel(buf, "WARN")
+	if len(warns) != 1 || !strings.Contains(warns[0], "restored from backup") || !strings.Contains(warns[0], "restores=1") {
+		t.Fatalf("want 1 restore WARN, got:\n%s", buf.String())
+	}
+	if strings.Contains(warns[0], "Library") {
+		t.Errorf("WARN leaked the restored path:\n%s", warns[0])
+	}
+}
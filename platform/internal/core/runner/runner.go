@@ -32,10 +32,12 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
 	"github.com/eliteGoblin/focusd/platform/internal/core/plugin"
 	"github.com/eliteGoblin/focusd/platform/internal/core/snapshot"
 	"github.com/eliteGoblin/focusd/platform/internal/core/state"
 	"github.com/eliteGoblin/focusd/platform/internal/osadapter"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 // discardLogger is a no-op slog.Logger used when no logger is injected, so
@@ -75,6 +77,10 @@ type Outcome struct {
 	Attempts   int
 	DurationMS int64
 	Result     plugin.Result
+	// RunID is the job_runs row of the attempt this outcome reports; it
+	// ties the scheduler's log lines and the event stream to that row. 0
+	// when no row was started (an integrity refusal records its own).
+	RunID int64
 	// terminal marks an outcome that must NOT be retried within the current
 	// tick even though its Status (e.g. RunStatusError) would normally be
 	// retryable. Set by the point-of-use integrity-verify-error path so a
@@ -112,6 +118,13 @@ type Runner struct {
 	// *snapshot.Store is a no-op, so an injected-less runner (tests, in-memory
 	// DBs with no workdir) behaves exactly as before.
 	snap *snapshot.Store
+	// events appends each protection action (enforcing run, failed run,
+	// tamper repair, integrity refusal) to the JSONL event stream. A nil
+	// *eventlog.Log is a no-op, like snap.
+	events *eventlog.Log
+	// tracer records an OTel span per run (attempts and the integrity check
+	// as children). nil => tracing off.
+	tracer *tracing.Tracer
 	// afterPin is a TEST-ONLY seam (nil in production) fired immediately after
 	// the point-of-use check pins the verified content and BEFORE the pre-exec
 	// TOCTOU guard re-checks it. A test uses it to deterministically simulate a
@@ -147,6 +160,36 @@ func (r *Runner) WithSnapshot(s *snapshot.Store) *Runner {
 	return r
 }
 
+// WithEventLog returns r wired to append protection actions to the JSONL
+// event stream (see package eventlog). Returns the same *Runner.
+func (r *Runner) WithEventLog(l *eventlog.Log) *Runner {
+	r.events = l
+	return r
+}
+
+// WithTracer returns r wired to record enforcement-run spans (see package
+// tracing). Returns the same *Runner.
+func (r *Runner) WithTracer(t *tracing.Tracer) *Runner {
+	r.tracer = t
+	return r
+}
+
+// recordEvent appends one event. Best-effort like recordSnapshot: the DB row
+// is the record of truth, so a stream write failure is logged, never fatal.
+func (r *Runner) recordEvent(ev eventlog.Event) {
+	if err := r.events.Append(ev); err != nil {
+		r.log.Warn("event stream write failed", "type", ev.Type, "err_type", fmt.Sprintf("%T", err))
+	}
+}
+
+// recordStats bumps the persistent `platform stats` counters. Best-effort,
+// like recordEvent: a failed increment is logged, never fails the run.
+func (r *Runner) recordStats(d state.DayStats) {
+	if err := r.DB.Stats.Add(d); err != nil {
+		r.log.Warn("stats counter update failed", "job", d.JobID, "err_type", fmt.Sprintf("%T", err))
+	}
+}
+
 // recordSnapshot mirrors one terminal run into the status snapshot. It is
 // best-effort: the DB row is already the source of truth, so a snapshot write
 // failure is logged and swallowed rather than failing the run. nil-safe via
@@ -204,13 +247,28 @@ func (r *Runner) Run(ctx context.Context, job Job, p plugin.Discovered, triggere
 		return Outcome{}, fmt.Errorf("plugin %s has no manifest", p.Dir)
 	}
 
+	ctx, span := r.tracer.Start(ctx, "job.run",
+		tracing.String("job.id", job.ID), tracing.String("plugin.id", p.Manifest.ID),
+		tracing.String("plugin.version", p.Manifest.Version), tracing.String("trigger", triggeredBy))
+	defer span.End()
+
 	attempts := job.Retry + 1
 	var last Outcome
 	for attempt := 1; attempt <= attempts; attempt++ {
-		out, err := r.runOnce(ctx, job, p, triggeredBy)
+		actx, as := tracing.Start(ctx, "plugin.attempt", tracing.Int("attempt", int64(attempt)))
+		out, err := r.runOnce(actx, job, p, triggeredBy)
 		if err != nil {
+			as.Fail(fmt.Sprintf("%T", err))
+			as.End()
+			span.Fail("error")
 			return out, err
 		}
+		as.Set(tracing.String("status", out.Status), tracing.Int("exit_code", int64(out.ExitCode)),
+			tracing.Int("duration_ms", out.DurationMS))
+		if out.Status != state.RunStatusOK {
+			as.Fail(out.Status)
+		}
+		as.End()
 		out.Attempts = attempt
 		last = out
 		// Terminal: success, a controlled failure (exit 1 is a real job
@@ -229,6 +287,13 @@ func (r *Runner) Run(ctx context.Context, job Job, p plugin.Discovered, triggere
 			break // parent cancelled; stop retrying
 		}
 	}
+	acts := eventlog.ParseActions(last.Stdout)
+	span.Set(tracing.String("status", last.Status), tracing.Int("attempts", int64(last.Attempts)),
+		tracing.Int("kills", int64(acts.Kills+acts.BypassKills)), tracing.Int("removals", int64(acts.Removals)),
+		tracing.Int("relaunches", int64(acts.Relaunches)))
+	if last.Status != state.RunStatusOK {
+		span.Fail(last.Status)
+	}
 	return last, nil
 }
 
@@ -281,7 +346,13 @@ func (r *Runner) runOnce(ctx context.Context, job Job, p plugin.Discovered, trig
 		dir := filepath.Clean(p.Dir)
 		pluginRoot := filepath.Dir(dir)
 		subdir := filepath.Base(dir)
+		_, vspan := tracing.Start(ctx, "plugin.verify")
 		restored, wantPrefix, gotPrefix, verr := r.verifier.VerifyOrRestore(pluginRoot, subdir)
+		vspan.Set(tracing.Bool("restored", restored))
+		if verr != nil {
+			vspan.Fail(fmt.Sprintf("%T", verr))
+		}
+		vspan.End()
 		if verr != nil {
 			const reason = "plugin integrity check failed; refusing to run possibly-tampered binary"
 			return r.integrityRefuse(job, p.Manifest.ID, reason, "integrity verify errored", verr)
@@ -298,6 +369,9 @@ func (r *Runner) runOnce(ctx context.Context, job Job, p plugin.Discovered, trig
 			if rerr := r.DB.Events.RecordTamperRepaired(job.ID, p.Manifest.ID, wantPrefix, gotPrefix); rerr != nil {
 				return Outcome{}, rerr
 			}
+			r.recordEvent(eventlog.Event{Type: eventlog.TypeTamperRepaired, Job: job.ID,
+				Plugin: p.Manifest.ID, WantSHA: wantPrefix, GotSHA: gotPrefix})
+			r.recordStats(state.DayStats{Day: state.Day(time.Now()), JobID: job.ID, Restores: 1})
 		}
 		// Capture the now-genuine content so the pre-exec guard can detect a
 		// swap that lands after this point. A read failure here means we can't
@@ -391,6 +465,7 @@ func (r *Runner) runOnce(ctx context.Context, job Job, p plugin.Discovered, trig
 		Stdout:     stdout.String(),
 		Stderr:     stderr.String(),
 		DurationMS: dur.Milliseconds(),
+		RunID:      runID,
 	}
 	classify(&out, runCtx, runErr)
 
@@ -427,6 +502,32 @@ func (r *Runner) runOnce(ctx context.Context, job Job, p plugin.Discovered, trig
 	// fast path) using the SAME start time the DB row carries, so the snapshot
 	// and DB agree on recency.
 	r.recordSnapshot(job.ID, out.Status, startedAt)
+	acts := eventlog.ParseActions(stdoutJSON)
+	if acts.BackupRestores > 0 {
+		// Putting a removed app back from a backup is deliberate; it stands
+		// out in the text log as well as in the stream.
+		r.log.Warn("bypass attempt: blocked app restored from backup",
+			"job", job.ID, "run", runID, "restores", acts.BackupRestores)
+	}
+	if out.Status == state.RunStatusOK || out.Status == state.RunStatusFailed {
+		d := state.DayStats{Day: state.Day(startedAt), JobID: job.ID, Runs: 1,
+			Kills: acts.Kills + acts.BypassKills, Removals: acts.Removals, Relaunches: acts.Relaunches}
+		if acts.Blocked() {
+			d.Blocks = 1
+		}
+		r.recordStats(d)
+	}
+	switch {
+	case out.Status != state.RunStatusOK:
+		ev := eventlog.Event{Type: eventlog.TypeRunFailed, Job: job.ID, Run: runID, Plugin: p.Manifest.ID, Status: out.Status}
+		if acts.Any() { // a partial failure can still have acted
+			ev.Actions = &acts
+		}
+		r.recordEvent(ev)
+	case acts.Any():
+		r.recordEvent(eventlog.Event{Type: eventlog.TypeEnforcement, Job: job.ID, Run: runID,
+			Plugin: p.Manifest.ID, Status: out.Status, Actions: &acts})
+	}
 	return out, nil
 }
 
@@ -447,6 +548,8 @@ func (r *Runner) integrityRefuse(job Job, pluginID, runReason, eventReason strin
 	if rerr := r.DB.Events.RecordIntegrityCheckFailed(job.ID, pluginID, eventReason); rerr != nil {
 		return Outcome{}, rerr
 	}
+	r.recordEvent(eventlog.Event{Type: eventlog.TypeIntegrityCheckFailed, Job: job.ID,
+		Plugin: pluginID, Status: state.RunStatusError, Reason: eventReason})
 	r.recordSnapshot(job.ID, state.RunStatusError, time.Now())
 	errStr := ""
 	if cause != nil {
@@ -487,6 +590,14 @@ func classify(out *Outcome, ctx context.Context, runErr error) {
 		out.ExitCode = 0
 		return
 	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		// The scheduler cancels in-flight runs at shutdown; a run that
+		// finished first keeps its own result.
+		out.Status = state.RunStatusError
+		out.ExitCode = -1
+		out.Err = "killed: platform shutting down"
+		return
+	}
 	var ee *exec.ExitError
 	if errors.As(runErr, &ee) {
 		code := ee.ExitCode()
@@ -2,12 +2,21 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/plugin"
 	"github.com/eliteGoblin/focusd/platform/internal/core/state"
 	"github.com/eliteGoblin/focusd/platform/internal/testutil"
+	"github.com/eliteGoblin/focusd/shared/tracing"
 )
 
 func newRunner(t *testing.T) *Runner {
@@ -41,6 +50,94 @@ exit 0`)
 	}
 }
 
+func TestRunAppendsProtectionEvents(t *testing.T) {
+	r := newRunner(t)
+	dir := t.TempDir()
+	r.WithEventLog(eventlog.New(dir))
+	noop := testutil.ScriptPlugin(t, "noop-plugin",
+		`echo '{"status":"ok","message":"clean","details":{"killed_count":0}}'`)
+	acted := testutil.ScriptPlugin(t, "kill-plugin",
+		`echo '{"status":"ok","message":"killed","details":{"killed_count":2,"blocked_apps":["Steam"],"uninstall_removed":["/Applications/Steam.app"]}}'`)
+	failed := testutil.ScriptPlugin(t, "fail-plugin", `exit 3`)
+	for _, p := range []plugin.Discovered{noop, acted, failed} {
+		if _, err := r.Run(context.Background(), Job{ID: p.Manifest.ID}, p, "scheduler"); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(dir, eventlog.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want one enforcement + one failure line (no-op runs are silent), got:\n%s", b)
+	}
+	var enf, fail eventlog.Event
+	_ = json.Unmarshal([]byte(lines[0]), &enf)
+	_ = json.Unmarshal([]byte(lines[1]), &fail)
+	if enf.Type != eventlog.TypeEnforcement || enf.Job != "kill-plugin" || enf.Actions.Kills != 2 || enf.Actions.Removals != 1 {
+		t.Errorf("enforcement event = %+v", enf)
+	}
+	if fail.Type != eventlog.TypeRunFailed || fail.Status != state.RunStatusError {
+		t.Errorf("failure event = %+v", fail)
+	}
+	if strings.Contains(string(b), "Steam.app") {
+		t.Error("the event stream must not carry removed paths")
+	}
+}
+
+func TestRunBumpsStatsCounters(t *testing.T) {
+	r := newRunner(t)
+	acted := testutil.ScriptPlugin(t, "kill-plugin",
+		`echo '{"status":"ok","message":"killed","details":{"killed_count":2,"bypass":{"killed_pids":[9]}}}'`)
+	noop := testutil.ScriptPlugin(t, "noop-plugin", `echo '{"status":"ok","message":"clean"}'`)
+	for _, p := range []plugin.Discovered{acted, acted, noop} {
+		if _, err := r.Run(context.Background(), Job{ID: p.Manifest.ID}, p, "scheduler"); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	days, err := r.DB.Stats.Days()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]state.DayStats{}
+	for _, d := range days {
+		got[d.JobID] = d
+	}
+	if k := got["kill-plugin"]; k.Runs != 2 || k.Blocks != 2 || k.Kills != 6 {
+		t.Errorf("kill-plugin counters = %+v", k)
+	}
+	if n := got["noop-plugin"]; n.Runs != 1 || n.Blocks != 0 {
+		t.Errorf("noop-plugin counters = %+v", n)
+	}
+}
+
+func TestRunRecordsTraceSpans(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ = io.ReadAll(req.Body)
+	}))
+	defer srv.Close()
+	tr := tracing.New(func() string { return srv.URL }, "platform", nil)
+	r := newRunner(t).WithTracer(tr)
+	p := testutil.ScriptPlugin(t, "kill-plugin",
+		`echo '{"status":"ok","message":"killed","details":{"killed_count":2,"uninstall_removed":["/Applications/Steam.app"]}}'`)
+	if _, err := r.Run(context.Background(), Job{ID: "kill-steam"}, p, "scheduler"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"name":"job.run"`, `"name":"plugin.attempt"`, `"key":"job.id"`, `"stringValue":"kill-steam"`, `"key":"kills"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("export missing %s:\n%s", want, body)
+		}
+	}
+	if strings.Contains(string(body), "Steam.app") || strings.Contains(string(body), p.Dir) {
+		t.Error("spans must not carry paths")
+	}
+}
+
 func TestRunControlledFailure(t *testing.T) {
 	r := newRunner(t)
 	p := testutil.ScriptPlugin(t, "fail-plugin",
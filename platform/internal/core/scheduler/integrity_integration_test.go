@@ -109,7 +109,7 @@ func TestSchedulerRestoresTamperedPluginBetweenTicks(t *testing.T) {
 	}
 
 	// --- Tick 1: genuine binary runs cleanly. ---
-	s.trigger(job, found[0])
+	s.trigger(job, found[0], "scheduler")
 	assertLastRunIsGenuine(t, db, "guarded", "tick 1 (clean)")
 
 	// --- Tamper: overwrite the plugin with an exit-0 do-nothing stub that
@@ -121,7 +121,7 @@ func TestSchedulerRestoresTamperedPluginBetweenTicks(t *testing.T) {
 
 	// --- Tick 2: the point-of-use check must restore + run the GENUINE binary
 	// and record a tamper event — the substitute must never execute. ---
-	s.trigger(job, found[0])
+	s.trigger(job, found[0], "scheduler")
 	assertLastRunIsGenuine(t, db, "guarded", "tick 2 (after tamper)")
 
 	// The on-disk binary must be back to genuine content.
@@ -9,10 +9,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
 	"github.com/eliteGoblin/focusd/platform/internal/core/plugin"
 	"github.com/eliteGoblin/focusd/platform/internal/core/runner"
 	"github.com/eliteGoblin/focusd/platform/internal/core/snapshot"
@@ -33,10 +35,32 @@ type Scheduler struct {
 	// scheduler mirrors the ones IT records before the runner is reached. A
 	// nil store is a no-op, so existing New(...) callers/tests are unaffected.
 	snap *snapshot.Store
+	// overlay, when set, yields the config a job runs with this tick (a
+	// focus session lays extra keys over it). nil runs j.Config as is.
+	overlay func(config.Job) map[string]any
+	// paused, when set, reports a job on a redeemed break: its ticks are
+	// recorded as skipped instead of run.
+	paused func(jobID string) bool
+	// backoff stretches cron ticks while constrained reports the machine on
+	// battery or throttled (see WithBackoff), and while idle reports nobody
+	// at the machine (WithIdle). lastTick (under mu) is when each job's, or
+	// the sweep's, last paced tick went through.
+	backoff     config.Backoff
+	constrained func() bool
+	idle        func() bool
+	lastTick    map[string]time.Time
+	// adaptive (under mu) is the activity pacing of each job that has one;
+	// lastBlock is when one of its runs last blocked something, and tight
+	// marks a job currently held at its full schedule by a block.
+	adaptive  map[string]config.Adaptive
+	lastBlock map[string]time.Time
+	tight     map[string]bool
 
 	mu         sync.Mutex
 	triggered  map[string]int  // jobID -> trigger count (test/observability)
 	skipLogged map[string]bool // jobID -> Info-logged "first run_as skip"
+	streaks    map[string]*failStreak
+	now        func() time.Time
 
 	// kickstart holds one fire-once trigger per registered job. Start runs
 	// each ONCE immediately (then the cron interval takes over) so every
@@ -46,6 +70,25 @@ type Scheduler struct {
 	// those in-flight runs so Stop drains them alongside cron-dispatched ones.
 	kickstart []func()
 	kickWG    sync.WaitGroup
+	// byID holds each registered job's binding for RunJob, the targeted
+	// scan. Built by Register alongside kickstart.
+	byID map[string]binding
+	// stopped (under mu) refuses RunNow once Stop has begun draining kickWG.
+	stopped bool
+	// lastMaintained (under mu) is when the last state maintenance pass
+	// finished; maintaining marks one in progress (see RegisterMaintenance).
+	lastMaintained time.Time
+	maintaining    bool
+	// runs is the parent context of every plugin run; cancelRuns ends it,
+	// so a shutdown can cut in-flight runs short (see Shutdown).
+	runs       context.Context
+	cancelRuns context.CancelFunc
+}
+
+// binding is one registered job and the plugin it runs.
+type binding struct {
+	job    config.Job
+	plugin plugin.Discovered
 }
 
 // New builds a scheduler. The runner and DB must be ready. mode is the
@@ -54,7 +97,10 @@ type Scheduler struct {
 // privilege-drop (it does NOT run them as root); a user-mode platform
 // cannot serve system plugins and marks them unavailable.
 func New(r *runner.Runner, db *state.DB, log *slog.Logger, mode osadapter.RunMode) *Scheduler {
+	runs, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
+		runs:       runs,
+		cancelRuns: cancel,
 		cron:       cron.New(),
 		run:        r,
 		db:         db,
@@ -62,9 +108,32 @@ func New(r *runner.Runner, db *state.DB, log *slog.Logger, mode osadapter.RunMod
 		mode:       mode,
 		triggered:  map[string]int{},
 		skipLogged: map[string]bool{},
+		streaks:    map[string]*failStreak{},
+		lastTick:   map[string]time.Time{},
+		adaptive:   map[string]config.Adaptive{},
+		lastBlock:  map[string]time.Time{},
+		tight:      map[string]bool{},
+		now:        time.Now,
+		byID:       map[string]binding{},
 	}
 }
 
+// RepeatSummaryEvery is how often a job that keeps failing the same way
+// gets a summary log line. The repeats in between log at Debug; every run
+// is still a job_runs row.
+const RepeatSummaryEvery = time.Hour
+
+// failStreak is a job's current run of identical failures. key is status
+// plus message: the message is compared, never logged (it is plugin
+// output and may carry a path).
+type failStreak struct {
+	key      string
+	first    time.Time
+	reported time.Time
+	total    int // repeats since first
+	pending  int // repeats since the last summary
+}
+
 // WithSnapshot wires the scheduler to mirror the terminal runs IT records
 // (no-overlap skips, mode-unavailable rows) into the status snapshot. A nil
 // store leaves it a no-op writer. Returns the same *Scheduler for chaining.
@@ -73,6 +142,110 @@ func (s *Scheduler) WithSnapshot(snap *snapshot.Store) *Scheduler {
 	return s
 }
 
+// WithOverlay sets the per-tick config source for every job; see
+// Scheduler.overlay. Returns the same *Scheduler for chaining.
+func (s *Scheduler) WithOverlay(fn func(config.Job) map[string]any) *Scheduler {
+	s.overlay = fn
+	return s
+}
+
+// WithPause sets the break check consulted before every run; see
+// Scheduler.paused. Returns the same *Scheduler for chaining.
+func (s *Scheduler) WithPause(fn func(jobID string) bool) *Scheduler {
+	s.paused = fn
+	return s
+}
+
+// WithBackoff stretches cron ticks by b while constrained returns true:
+// a tick that comes sooner than the stretched interval after the last one
+// that ran is dropped. Only cron ticks are paced; a kickstart, RunNow or
+// RunJob always runs. Returns the same *Scheduler for chaining.
+func (s *Scheduler) WithBackoff(b config.Backoff, constrained func() bool) *Scheduler {
+	s.backoff = b
+	s.constrained = constrained
+	return s
+}
+
+// WithIdle stretches cron ticks to the backoff's Max while idle returns
+// true and b.Idle is set (see config.Backoff). Returns the same *Scheduler
+// for chaining.
+func (s *Scheduler) WithIdle(idle func() bool) *Scheduler {
+	s.idle = idle
+	return s
+}
+
+// paced wraps a cron tick for key, whose schedule fires every interval,
+// with the backoff check and, for an adaptive job, the quiet interval.
+// The longer of the two applies.
+func (s *Scheduler) paced(key string, every time.Duration, fire func()) func() {
+	return func() {
+		now := s.now()
+		s.mu.Lock()
+		last, seen := s.lastTick[key]
+		target, why := every, ""
+		a, adaptive := s.adaptive[key]
+		quiet := adaptive && now.Sub(s.lastBlock[key]) >= a.HoldFor()
+		if quiet && a.Quiet.Std() > target {
+			target, why = a.Quiet.Std(), "quiet"
+		}
+		if s.backoff.Enabled() && s.constrained != nil && s.constrained() && s.backoff.Stretch(every) > target {
+			target, why = s.backoff.Stretch(every), "backoff"
+		}
+		if s.backoff.Idle && s.idle != nil && s.idle() && s.backoff.IdleStretch(every) > target {
+			target, why = s.backoff.IdleStretch(every), "idle"
+		}
+		relaxed := quiet && s.tight[key]
+		if relaxed {
+			delete(s.tight, key)
+		}
+		// Half an interval of slack: cron ticks land on the interval, but
+		// the recorded time is when the previous tick ran.
+		skip := seen && every > 0 && now.Sub(last)+every/2 < target
+		if !skip {
+			s.lastTick[key] = now
+		}
+		s.mu.Unlock()
+		if relaxed {
+			s.log.Info("cadence relaxed (quiet)", "job", key, "every", a.Quiet.Std().String())
+		}
+		if skip {
+			s.log.Debug("tick skipped ("+why+")", "job", key)
+			return
+		}
+		fire()
+	}
+}
+
+// noteActivity holds an adaptive job at its full schedule for the hold
+// period after a run that blocked something.
+func (s *Scheduler) noteActivity(jobID string, out runner.Outcome) {
+	s.mu.Lock()
+	a, adaptive := s.adaptive[jobID]
+	if !adaptive || !eventlog.ParseActions(out.Stdout).Blocked() {
+		s.mu.Unlock()
+		return
+	}
+	s.lastBlock[jobID] = s.now()
+	tightened := !s.tight[jobID]
+	s.tight[jobID] = true
+	s.mu.Unlock()
+	if tightened {
+		s.log.Info("cadence tightened (block seen)", "job", jobID, "run", out.RunID,
+			"hold", a.HoldFor().String())
+	}
+}
+
+// interval is how far apart spec's ticks are, taken from its next two; 0
+// for a spec cron cannot parse.
+func interval(spec string, now time.Time) time.Duration {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return 0
+	}
+	next := sched.Next(now)
+	return sched.Next(next).Sub(next)
+}
+
 // recordSnapshot mirrors one scheduler-recorded terminal run into the status
 // snapshot. Best-effort: the DB row is the source of truth, so a snapshot
 // write failure is logged and swallowed. nil-safe via the Store's receiver.
@@ -149,8 +322,13 @@ func (s *Scheduler) Register(jobs []config.Job, plugins map[string]plugin.Discov
 
 		job := j // capture
 		disc := p
-		fire := func() { s.trigger(job, disc) }
-		_, err := s.cron.AddFunc(job.Schedule, fire)
+		if job.Adaptive.Enabled() {
+			s.mu.Lock()
+			s.adaptive[job.ID] = job.Adaptive
+			s.mu.Unlock()
+		}
+		fire := func() { s.trigger(job, disc, "scheduler") }
+		_, err := s.cron.AddFunc(job.Schedule, s.paced(job.ID, interval(job.Schedule, s.now()), fire))
 		if err != nil {
 			reason := fmt.Sprintf("invalid schedule %q for job %q: %v", job.Schedule, job.ID, err)
 			s.event(state.SeverityError, "bad_schedule", reason, job.ID)
@@ -160,6 +338,7 @@ func (s *Scheduler) Register(jobs []config.Job, plugins map[string]plugin.Discov
 		// Same closure Start fires once immediately (kickstart), so the job's
 		// first run is at t=0 rather than t=schedule.
 		s.kickstart = append(s.kickstart, fire)
+		s.byID[job.ID] = binding{job: job, plugin: disc}
 		registered++
 		s.log.Info("job registered", "job", job.ID, "plugin", job.Plugin, "schedule", job.Schedule)
 	}
@@ -187,13 +366,13 @@ func (s *Scheduler) RegisterIntegritySweep(interval time.Duration, sweep func()
 		interval = config.DefaultSweepInterval
 	}
 	schedule := "@every " + interval.String()
-	_, err := s.cron.AddFunc(schedule, func() {
+	_, err := s.cron.AddFunc(schedule, s.paced("integrity-sweep", interval, func() {
 		if err := sweep(); err != nil {
 			s.event(state.SeverityError, state.EventIntegritySweepFailed,
 				"plugin integrity sweep failed", "integrity-sweep")
 			s.log.Error("integrity sweep failed", "err", err)
 		}
-	})
+	}))
 	if err != nil {
 		return fmt.Errorf("register integrity sweep: %w", err)
 	}
@@ -201,8 +380,65 @@ func (s *Scheduler) RegisterIntegritySweep(interval time.Duration, sweep func()
 	return nil
 }
 
-// trigger runs one job occurrence, enforcing no-overlap.
-func (s *Scheduler) trigger(j config.Job, p plugin.Discovered) {
+// State maintenance cadence: checked every MaintenanceCheck, run at most
+// once per MaintenanceEvery.
+const (
+	MaintenanceCheck = time.Hour
+	MaintenanceEvery = 24 * time.Hour
+)
+
+// RegisterMaintenance adds one synthetic @every MaintenanceCheck entry that
+// keeps state.db bounded (state.DB.Maintain with l). A check runs the pass
+// only in an idle window — the last pass is MaintenanceEvery old and no job
+// holds its run lock — so the VACUUM never stalls a protection run; a busy
+// check waits for the next one. A failed pass is logged and retried next
+// check: a bloated DB is not a protection gap.
+func (s *Scheduler) RegisterMaintenance(l state.Limits) error {
+	schedule := "@every " + MaintenanceCheck.String()
+	if _, err := s.cron.AddFunc(schedule, func() { s.maintain(l) }); err != nil {
+		return fmt.Errorf("register maintenance: %w", err)
+	}
+	s.log.Info("state maintenance registered", "schedule", schedule)
+	return nil
+}
+
+// maintain is one maintenance check; it reports whether a pass ran.
+func (s *Scheduler) maintain(l state.Limits) bool {
+	now := s.now()
+	s.mu.Lock()
+	due := !s.maintaining && (s.lastMaintained.IsZero() || now.Sub(s.lastMaintained) >= MaintenanceEvery)
+	if due {
+		s.maintaining = true
+	}
+	s.mu.Unlock()
+	if !due {
+		return false
+	}
+	defer func() {
+		s.mu.Lock()
+		s.maintaining = false
+		s.mu.Unlock()
+	}()
+	if busy, err := s.db.Locks.AnyHeld(); err != nil || busy {
+		return false
+	}
+	m, err := s.db.Maintain(l)
+	if err != nil {
+		s.log.Warn("state maintenance failed", "err", err)
+		return false
+	}
+	s.mu.Lock()
+	s.lastMaintained = now
+	s.mu.Unlock()
+	s.log.Info("state maintained", "runs_trimmed", m.Runs, "events_trimmed", m.Events, "vacuumed", m.Vacuumed)
+	return true
+}
+
+// trigger runs one job occurrence, enforcing no-overlap, and returns how
+// it ended: the runner's outcome, or a skipped/unavailable/error outcome
+// when the run never reached the runner. by is recorded as the run's
+// trigger.
+func (s *Scheduler) trigger(j config.Job, p plugin.Discovered, by string) runner.Outcome {
 	s.mu.Lock()
 	s.triggered[j.ID]++
 	s.mu.Unlock()
@@ -234,7 +470,17 @@ func (s *Scheduler) trigger(j config.Job, p plugin.Discovered) {
 		}
 		s.log.Debug("job unavailable (run_as not servable in this mode)",
 			"job", j.ID, "run_as", p.Manifest.RunAs, "mode", string(s.mode))
-		return
+		return runner.Outcome{Status: state.RunStatusUnavailable}
+	}
+
+	// A redeemed break token pauses the job. Recorded as a skip (like a
+	// no-overlap skip) so status shows the job alive; the redemption itself
+	// is the audit event, so no event per paused tick.
+	if s.paused != nil && s.paused(j.ID) {
+		_ = s.db.Runs.RecordSkipped(j.ID, j.Plugin, "on a break")
+		s.recordSnapshot(j.ID, state.RunStatusSkipped)
+		s.log.Debug("job paused (break)", "job", j.ID)
+		return runner.Outcome{Status: state.RunStatusSkipped, Message: "on a break"}
 	}
 
 	if !j.AllowOverlap {
@@ -246,50 +492,170 @@ func (s *Scheduler) trigger(j config.Job, p plugin.Discovered) {
 		ok, err := s.db.Locks.TryAcquire(j.ID, 0, ttl)
 		if err != nil {
 			s.log.Error("lock acquire failed", "job", j.ID, "err", err)
-			return
+			return runner.Outcome{Status: state.RunStatusError}
 		}
 		if !ok {
 			_ = s.db.Runs.RecordSkipped(j.ID, j.Plugin, "previous run still active (no-overlap)")
 			s.recordSnapshot(j.ID, state.RunStatusSkipped)
 			s.event(state.SeverityInfo, "job_skipped", "no-overlap: previous run active", j.ID)
 			s.log.Info("job skipped (no-overlap)", "job", j.ID)
-			return
+			return runner.Outcome{Status: state.RunStatusSkipped, Message: "previous run still active (no-overlap)"}
 		}
 		defer s.db.Locks.Release(j.ID)
 	}
 
+	cfg := j.Config
+	if s.overlay != nil {
+		cfg = s.overlay(j)
+	}
 	rj := runner.Job{
 		ID:      j.ID,
 		Timeout: j.Timeout.Std(),
 		Retry:   j.Retry,
-		Config:  j.Config,
+		Config:  cfg,
 	}
-	out, err := s.run.Run(context.Background(), rj, p, "scheduler")
+	out, err := s.run.Run(s.runs, rj, p, by)
 	if err != nil {
 		s.log.Error("job run error", "job", j.ID, "err", err)
 		s.event(state.SeverityError, "job_run_error", err.Error(), j.ID)
-		return
+		return runner.Outcome{Status: state.RunStatusError}
+	}
+	s.logFinished(j.ID, out)
+	s.noteActivity(j.ID, out)
+	return out
+}
+
+// logFinished logs a finished run. A failure identical to the job's last
+// one is a repeat: it logs at Debug, and once per RepeatSummaryEvery a Warn
+// line counts the repeats since the previous summary. The first success
+// after a streak logs how long it lasted. A permission error hit every ten
+// minutes is one line an hour, not 144 a day.
+func (s *Scheduler) logFinished(jobID string, out runner.Outcome) {
+	now := s.now()
+	s.mu.Lock()
+	st := s.streaks[jobID]
+	var summary, recovered, repeat bool
+	var pending int
+	switch {
+	case out.Status == state.RunStatusOK:
+		delete(s.streaks, jobID)
+		recovered = st != nil && st.total > 0
+	case st != nil && st.key == out.Status+"\x00"+out.Message:
+		repeat = true
+		st.total++
+		st.pending++
+		if now.Sub(st.reported) >= RepeatSummaryEvery {
+			summary, pending = true, st.pending
+			st.reported, st.pending = now, 0
+		}
+	default:
+		s.streaks[jobID] = &failStreak{key: out.Status + "\x00" + out.Message, first: now, reported: now}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case summary:
+		s.log.Warn("job failing repeatedly", "job", jobID, "run", out.RunID, "status", out.Status,
+			"repeats", pending, "since", st.first.UTC().Format(time.RFC3339))
+	case repeat:
+		s.log.Debug("job finished (repeat)", "job", jobID, "run", out.RunID, "status", out.Status)
+	default:
+		if recovered {
+			s.log.Info("job recovered", "job", jobID, "run", out.RunID,
+				"repeats", st.total, "failing_for", now.Sub(st.first).Round(time.Second).String())
+		}
+		s.log.Info("job finished", "job", jobID, "run", out.RunID, "status", out.Status,
+			"exit", out.ExitCode, "ms", out.DurationMS, "attempts", out.Attempts)
 	}
-	s.log.Info("job finished", "job", j.ID, "status", out.Status,
-		"exit", out.ExitCode, "ms", out.DurationMS, "attempts", out.Attempts)
 }
 
 func (s *Scheduler) persistJob(j config.Job, p plugin.Discovered) error {
+	if err := s.db.Jobs.Upsert(jobRow(j)); err != nil {
+		return err
+	}
+	row := plugin.ToInventoryRow(p)
+	row.Enabled = true
+	return s.db.Plugins.Upsert(row)
+}
+
+// jobRow is j's jobs-table projection. ConfigHash is a content digest of
+// its config, so any config edit reads as a change.
+func jobRow(j config.Job) state.Job {
 	hash := ""
 	if b, err := json.Marshal(j.Config); err == nil {
 		sum := sha256.Sum256(b) // real content digest (change marker)
 		hash = hex.EncodeToString(sum[:])
 	}
-	if err := s.db.Jobs.Upsert(state.Job{
+	return state.Job{
 		ID: j.ID, PluginID: j.Plugin, Enabled: j.Enabled, Schedule: j.Schedule,
 		TimeoutMS: j.Timeout.Std().Milliseconds(), Retry: j.Retry,
 		AllowOverlap: j.AllowOverlap, ConfigHash: hash,
-	}); err != nil {
-		return err
 	}
-	row := plugin.ToInventoryRow(p)
-	row.Enabled = true
-	return s.db.Plugins.Upsert(row)
+}
+
+// PolicyAudit is what AuditPolicy found: how the enabled jobs differ from
+// the last applied policy, and which of them have no usable plugin on this
+// machine. An unavailable job is still policy, so it is compared like any
+// other and listed apart rather than read as added.
+type PolicyAudit struct {
+	config.Diff
+	Unavailable []string
+}
+
+// AuditPolicy compares the enabled jobs with the policy the previous start
+// applied and records a policy_changed event when they differ, then
+// persists jobs as the applied policy. Rows for jobs no longer enforced are
+// pruned, so a removal is reported once. A first start has nothing to
+// compare and records nothing.
+func (s *Scheduler) AuditPolicy(jobs []config.Job, plugins map[string]plugin.Discovered) (PolicyAudit, error) {
+	prev, err := s.db.Jobs.List()
+	if err != nil {
+		return PolicyAudit{}, err
+	}
+	old := make(map[string]state.Job, len(prev))
+	for _, r := range prev {
+		old[r.ID] = r
+	}
+	var a PolicyAudit
+	var d config.Diff
+	for _, j := range jobs {
+		if !j.Enabled {
+			continue
+		}
+		if p, ok := plugins[j.Plugin]; !ok || !p.OK {
+			a.Unavailable = append(a.Unavailable, j.ID)
+		}
+		cur := jobRow(j)
+		was, ok := old[j.ID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, j.ID)
+		case was.PluginID != cur.PluginID || was.Schedule != cur.Schedule ||
+			was.TimeoutMS != cur.TimeoutMS || was.Retry != cur.Retry ||
+			was.AllowOverlap != cur.AllowOverlap || was.ConfigHash != cur.ConfigHash:
+			d.Changed = append(d.Changed, j.ID)
+		}
+		delete(old, j.ID)
+		if err := s.db.Jobs.Upsert(cur); err != nil {
+			return a, err
+		}
+	}
+	slices.Sort(a.Unavailable)
+	for _, r := range prev {
+		if _, gone := old[r.ID]; gone {
+			d.Removed = append(d.Removed, r.ID)
+			if err := s.db.Jobs.Delete(r.ID); err != nil {
+				return a, err
+			}
+		}
+	}
+	if len(prev) == 0 || d.Empty() {
+		return a, nil
+	}
+	slices.Sort(d.Added)
+	slices.Sort(d.Changed)
+	a.Diff = d
+	return a, s.db.Events.RecordPolicyChanged(d.Added, d.Removed, d.Changed)
 }
 
 // logFirstSkip emits a single Info-level "unavailable in this mode" the
@@ -352,6 +718,9 @@ func (s *Scheduler) Start() {
 // kickstart runs (drained via kickWG). The returned context is Done only once
 // BOTH have settled, so callers get one honest "fully drained" signal.
 func (s *Scheduler) Stop() context.Context {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
 	cronCtx := s.cron.Stop()
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -362,6 +731,69 @@ func (s *Scheduler) Stop() context.Context {
 	return ctx
 }
 
+// Shutdown stops scheduling and gives in-flight runs grace to finish on
+// their own; past it they are cancelled (killed, and recorded as errors),
+// and wait bounds how long their bookkeeping may take. drained reports
+// that every run settled — its job_runs row and events written — and
+// cancelled that the grace ran out first. grace + wait must fit inside the
+// supervisor's stop window, or its SIGKILL lands mid-write.
+func (s *Scheduler) Shutdown(grace, wait time.Duration) (drained, cancelled bool) {
+	done := s.Stop().Done()
+	select {
+	case <-done:
+		return true, false
+	case <-time.After(grace):
+	}
+	s.log.Warn("shutdown grace spent; cancelling in-flight runs", "grace", grace.String())
+	s.cancelRuns()
+	select {
+	case <-done:
+		return true, true
+	case <-time.After(wait):
+		return false, true
+	}
+}
+
+// RunNow fires every registered job once, immediately, on top of its
+// schedule — the admin API's on-demand scan. The runs go through the same
+// trigger path as a cron tick (no-overlap lock included, so a job already
+// running records a skip) and Stop drains them like kickstart runs. Returns
+// how many jobs were fired; 0 once Stop has been called.
+func (s *Scheduler) RunNow() int {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return 0
+	}
+	s.kickWG.Add(len(s.kickstart))
+	s.mu.Unlock()
+	for _, fire := range s.kickstart {
+		go func() {
+			defer s.kickWG.Done()
+			fire()
+		}()
+	}
+	return len(s.kickstart)
+}
+
+// RunJob runs one registered job now and waits for it — the targeted scan
+// (`platform scan --policy`). It takes the same trigger path as a tick, so
+// a break, the no-overlap lock and the run-mode gate all still apply, and
+// Stop drains it like a kickstart run. ok is false for a job that is not
+// registered (unknown or disabled) or once Stop has been called.
+func (s *Scheduler) RunJob(jobID string) (out runner.Outcome, ok bool) {
+	s.mu.Lock()
+	b, found := s.byID[jobID]
+	if s.stopped || !found {
+		s.mu.Unlock()
+		return runner.Outcome{}, false
+	}
+	s.kickWG.Add(1)
+	s.mu.Unlock()
+	defer s.kickWG.Done()
+	return s.trigger(b.job, b.plugin, "scan"), true
+}
+
 // TriggerCount reports how many times a job has been triggered (test
 // and observability aid).
 func (s *Scheduler) TriggerCount(jobID string) int {
@@ -1,9 +1,11 @@
 package scheduler
 
 import (
+	"bytes"
 	"io"
 	"log/slog"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -105,7 +107,7 @@ func TestTriggerRunsAndRecordsRun(t *testing.T) {
 	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok","message":"done"}'`)
 	j := config.Job{ID: "j1", Plugin: "ok", Enabled: true,
 		Schedule: "* * * * *", Timeout: dur(5 * time.Second)}
-	s.trigger(j, p)
+	s.trigger(j, p, "scheduler")
 
 	if s.TriggerCount("j1") != 1 {
 		t.Errorf("trigger count = %d", s.TriggerCount("j1"))
@@ -123,6 +125,39 @@ func TestTriggerRunsAndRecordsRun(t *testing.T) {
 	}
 }
 
+func TestTriggerRunsWithOverlayConfig(t *testing.T) {
+	s, db := newSched(t)
+	s.WithOverlay(func(j config.Job) map[string]any {
+		return map[string]any{"extra_hosts": []any{"slack.com"}}
+	})
+	p := testutil.ScriptPlugin(t, "ok", `cfg=$(cat)
+case "$cfg" in
+*slack.com*) echo '{"status":"ok","message":"overlay"}' ;;
+*) echo '{"status":"failed","message":"base config"}'; exit 1 ;;
+esac`)
+	j := config.Job{ID: "j1", Plugin: "ok", Enabled: true,
+		Schedule: "* * * * *", Timeout: dur(5 * time.Second)}
+	s.trigger(j, p, "scheduler")
+	if _, err := db.Runs.LastByStatus("j1", state.RunStatusOK); err != nil {
+		t.Fatalf("plugin did not see the overlay config: %v", err)
+	}
+}
+
+func TestTriggerSkipsPausedJob(t *testing.T) {
+	s, db := newSched(t)
+	s.WithPause(func(jobID string) bool { return jobID == "j1" })
+	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok","message":"ran"}'`)
+	j := config.Job{ID: "j1", Plugin: "ok", Enabled: true,
+		Schedule: "* * * * *", Timeout: dur(5 * time.Second)}
+	s.trigger(j, p, "scheduler")
+	if _, err := db.Runs.LastByStatus("j1", state.RunStatusOK); err == nil {
+		t.Fatal("paused job ran")
+	}
+	if _, err := db.Runs.LastByStatus("j1", state.RunStatusSkipped); err != nil {
+		t.Fatalf("paused tick not recorded as skipped: %v", err)
+	}
+}
+
 func TestTriggerNoOverlapSkipsWhenLocked(t *testing.T) {
 	s, db := newSched(t)
 	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok"}'`)
@@ -133,7 +168,7 @@ func TestTriggerNoOverlapSkipsWhenLocked(t *testing.T) {
 	if ok, _ := db.Locks.TryAcquire("j1", 99, time.Minute); !ok {
 		t.Fatal("precondition: could not pre-acquire lock")
 	}
-	s.trigger(j, p)
+	s.trigger(j, p, "scheduler")
 
 	skipped, err := db.Runs.LastByStatus("j1", state.RunStatusSkipped)
 	if err != nil {
@@ -154,7 +189,7 @@ func TestTriggerAllowOverlapIgnoresLock(t *testing.T) {
 		Schedule: "* * * * *", AllowOverlap: true, Timeout: dur(5 * time.Second)}
 
 	db.Locks.TryAcquire("j1", 1, time.Minute) // held, but overlap allowed
-	s.trigger(j, p)
+	s.trigger(j, p, "scheduler")
 
 	if _, err := db.Runs.LastByStatus("j1", state.RunStatusOK); err != nil {
 		t.Errorf("allow_overlap job should run despite lock: %v", err)
@@ -214,7 +249,7 @@ func TestTriggerRunAsGate(t *testing.T) {
 			p.Manifest.RunAs = tc.runAs
 			j := config.Job{ID: "j1", Plugin: "x", Enabled: true,
 				Schedule: "* * * * *", Timeout: dur(2 * time.Second)}
-			s.trigger(j, p)
+			s.trigger(j, p, "scheduler")
 
 			_, runErr := db.Runs.LastByStatus("j1", state.RunStatusOK)
 			didRun := runErr == nil
@@ -293,3 +328,273 @@ func TestStartKickstartsRegisteredJobsImmediately(t *testing.T) {
 		t.Errorf("kickstart trigger count = %d, want exactly 1", got)
 	}
 }
+
+func TestRunNowFiresEveryJobOnceAndStopsAfterStop(t *testing.T) {
+	s, db := newSched(t)
+	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok"}'`)
+	s.Register([]config.Job{{ID: "j1", Plugin: "ok", Enabled: true,
+		Schedule: "@every 1h", Timeout: dur(5 * time.Second)}},
+		map[string]plugin.Discovered{"ok": p})
+
+	if n := s.RunNow(); n != 1 {
+		t.Fatalf("RunNow fired %d jobs, want 1", n)
+	}
+	deadline := time.After(3 * time.Second)
+	for {
+		if _, err := db.Runs.LastByStatus("j1", state.RunStatusOK); err == nil {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("RunNow job did not record a run within 3s")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	select {
+	case <-s.Stop().Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not drain in time")
+	}
+	if n := s.RunNow(); n != 0 {
+		t.Fatalf("RunNow after Stop fired %d jobs, want 0", n)
+	}
+}
+
+func TestRunJobRunsOnlyThatJobAndWaits(t *testing.T) {
+	s, db := newSched(t)
+	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok","details":{"killed_count":2}}'`)
+	s.Register([]config.Job{
+		{ID: "j1", Plugin: "ok", Enabled: true, Schedule: "@every 1h", Timeout: dur(5 * time.Second)},
+		{ID: "j2", Plugin: "ok", Enabled: true, Schedule: "@every 1h", Timeout: dur(5 * time.Second)},
+		{ID: "off", Plugin: "ok", Enabled: false, Schedule: "@every 1h", Timeout: dur(5 * time.Second)},
+	}, map[string]plugin.Discovered{"ok": p})
+
+	out, ok := s.RunJob("j1")
+	if !ok || out.Status != state.RunStatusOK {
+		t.Fatalf("RunJob(j1) = %+v, ok=%v", out, ok)
+	}
+	if s.TriggerCount("j1") != 1 || s.TriggerCount("j2") != 0 {
+		t.Fatalf("triggers j1=%d j2=%d, want 1 and 0", s.TriggerCount("j1"), s.TriggerCount("j2"))
+	}
+	run, err := db.Runs.LastByStatus("j1", state.RunStatusOK)
+	if err != nil || run.TriggeredBy != "scan" {
+		t.Fatalf("recorded run = %+v, err %v", run, err)
+	}
+	for _, id := range []string{"off", "nope"} {
+		if _, ok := s.RunJob(id); ok {
+			t.Errorf("RunJob(%q) ran an unregistered job", id)
+		}
+	}
+	<-s.Stop().Done()
+	if _, ok := s.RunJob("j1"); ok {
+		t.Fatal("RunJob ran after Stop")
+	}
+}
+
+func TestAuditPolicyReportsReleaseDiffOnce(t *testing.T) {
+	s, db := newSched(t)
+	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok"}'`)
+	byID := map[string]plugin.Discovered{"ok": p}
+	job := func(id, sched string) config.Job {
+		return config.Job{ID: id, Plugin: "ok", Enabled: true, Schedule: sched}
+	}
+	v1 := []config.Job{job("keep", "* * * * *"), job("edit", "* * * * *"), job("drop", "* * * * *")}
+	if d, err := s.AuditPolicy(v1, byID); err != nil || !d.Empty() {
+		t.Fatalf("first start: diff %+v err %v, want nothing to compare", d, err)
+	}
+	if _, err := s.Register(v1, byID); err != nil {
+		t.Fatal(err)
+	}
+
+	s2 := New(runner.New(db), db, s.log, osadapter.ModeUser)
+	v2 := []config.Job{job("keep", "* * * * *"), job("edit", "*/5 * * * *"), job("new", "* * * * *")}
+	d, err := s2.AuditPolicy(v2, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Added) != 1 || d.Added[0] != "new" || len(d.Changed) != 1 || d.Changed[0] != "edit" ||
+		len(d.Removed) != 1 || d.Removed[0] != "drop" {
+		t.Fatalf("diff = %+v", d)
+	}
+	if _, err := db.Jobs.Get("drop"); err == nil {
+		t.Error("removed job's row should be pruned")
+	}
+	ev, _ := db.Events.Recent(1)
+	if len(ev) != 1 || ev[0].EventType != state.EventPolicyChanged || ev[0].Severity != state.SeverityWarn {
+		t.Fatalf("event = %+v", ev)
+	}
+	if _, err := s2.Register(v2, byID); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := s2.AuditPolicy(v2, byID); !d.Empty() {
+		t.Fatalf("unchanged restart: diff %+v", d)
+	}
+}
+
+// TestAuditPolicyListsUnavailableApart: a job whose plugin is missing is
+// policy all the same. It is listed as unavailable on every start, and
+// reported as added only on the start that first applied it.
+func TestAuditPolicyListsUnavailableApart(t *testing.T) {
+	s, db := newSched(t)
+	p := testutil.ScriptPlugin(t, "ok", `echo '{"status":"ok"}'`)
+	byID := map[string]plugin.Discovered{"ok": p}
+	jobs := []config.Job{
+		{ID: "here", Plugin: "ok", Enabled: true, Schedule: "* * * * *"},
+		{ID: "gone", Plugin: "missing", Enabled: true, Schedule: "* * * * *"},
+	}
+	if _, err := s.AuditPolicy(jobs, byID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Register(jobs, byID); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		s2 := New(runner.New(db), db, s.log, osadapter.ModeUser)
+		d, err := s2.AuditPolicy(jobs, byID)
+		if err != nil || !d.Empty() || len(d.Unavailable) != 1 || d.Unavailable[0] != "gone" {
+			t.Fatalf("restart: audit %+v err %v, want no change and one unavailable", d, err)
+		}
+	}
+}
+
+func TestRepeatedFailuresCollapseIntoSummaries(t *testing.T) {
+	s, _ := newSched(t)
+	var buf bytes.Buffer
+	s.log = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	fail := testutil.ScriptPlugin(t, "deny", `echo '{"status":"failed","message":"permission denied"}'; exit 1`)
+	ok := testutil.ScriptPlugin(t, "deny", `echo '{"status":"ok"}'`)
+	j := config.Job{ID: "j1", Plugin: "deny", Enabled: true, Schedule: "* * * * *", Timeout: dur(5 * time.Second)}
+
+	for range 6 { // an hour of 10-minute ticks
+		s.trigger(j, fail, "scheduler")
+		now = now.Add(10 * time.Minute)
+	}
+	if n := strings.Count(buf.String(), `msg="job finished"`); n != 1 {
+		t.Fatalf("want only the first failure logged, got %d:\n%s", n, buf.String())
+	}
+	s.trigger(j, fail, "scheduler")
+	if !strings.Contains(buf.String(), `msg="job failing repeatedly" job=j1 run=7 status=failed repeats=6`) {
+		t.Fatalf("no hourly summary:\n%s", buf.String())
+	}
+	s.trigger(j, ok, "scheduler")
+	if !strings.Contains(buf.String(), `msg="job recovered" job=j1 run=8 repeats=6 failing_for=1h0m0s`) {
+		t.Fatalf("no recovery line:\n%s", buf.String())
+	}
+	if n := strings.Count(buf.String(), `msg="job finished"`); n != 2 {
+		t.Fatalf("the success must log as usual:\n%s", buf.String())
+	}
+}
+
+func TestBackoffStretchesCronTicksWhileConstrained(t *testing.T) {
+	s, _ := newSched(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	constrained := false
+	s.WithBackoff(config.Backoff{Factor: 3, Max: dur(time.Minute)}, func() bool { return constrained })
+	ran := 0
+	tick := s.paced("j1", 10*time.Second, func() { ran++ })
+
+	step := func(n int) {
+		for range n {
+			tick()
+			now = now.Add(10 * time.Second)
+		}
+	}
+	step(3)
+	if ran != 3 {
+		t.Fatalf("unconstrained: ran %d of 3 ticks", ran)
+	}
+	constrained = true
+	step(6)
+	if ran != 5 {
+		t.Fatalf("on battery: ran %d, want 5 (every third tick)", ran)
+	}
+	constrained = false
+	step(2)
+	if ran != 7 {
+		t.Fatalf("back on AC: ran %d, want 7", ran)
+	}
+	if interval("@every 10s", now) != 10*time.Second || interval("*/5 * * * *", now) != 5*time.Minute {
+		t.Error("interval misreads a schedule")
+	}
+}
+
+func TestIdleStretchesCronTicksToMax(t *testing.T) {
+	s, _ := newSched(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	idle := true
+	s.WithBackoff(config.Backoff{Max: dur(time.Minute), Idle: true}, func() bool { return false }).
+		WithIdle(func() bool { return idle })
+	ran := 0
+	tick := s.paced("j1", 10*time.Second, func() { ran++ })
+	for range 12 {
+		tick()
+		now = now.Add(10 * time.Second)
+	}
+	if ran != 2 {
+		t.Fatalf("idle: ran %d of 12 ticks, want 2 (once a minute)", ran)
+	}
+	idle = false
+	tick()
+	if ran != 3 {
+		t.Fatalf("back at the machine: ran %d, want 3", ran)
+	}
+}
+
+func TestAdaptiveRelaxesWhenQuietAndTightensOnABlock(t *testing.T) {
+	s, _ := newSched(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.adaptive["j1"] = config.Adaptive{Quiet: dur(2 * time.Minute), Hold: dur(time.Hour)}
+	ran := 0
+	tick := s.paced("j1", 30*time.Second, func() { ran++ })
+	step := func(n int) {
+		for range n {
+			tick()
+			now = now.Add(30 * time.Second)
+		}
+	}
+
+	step(8)
+	if ran != 2 {
+		t.Fatalf("quiet: ran %d of 8 ticks, want 2 (every 2m)", ran)
+	}
+	s.noteActivity("j1", runner.Outcome{Stdout: `{"status":"ok","details":{"killed_count":1}}`})
+	step(8)
+	if ran != 10 {
+		t.Fatalf("after a block: ran %d, want every tick (10)", ran)
+	}
+	s.noteActivity("j1", runner.Outcome{Stdout: `{"status":"ok","details":{"killed_count":0}}`})
+	now = now.Add(time.Hour)
+	step(8)
+	if ran != 12 {
+		t.Fatalf("an hour without blocks: ran %d, want 12", ran)
+	}
+}
+
+func TestShutdownCancelsRunsPastTheGrace(t *testing.T) {
+	s, _ := newSched(t)
+	s.Start()
+	if drained, cancelled := s.Shutdown(time.Second, time.Second); !drained || cancelled {
+		t.Fatalf("idle: drained=%v cancelled=%v; want drained, nothing cancelled", drained, cancelled)
+	}
+
+	s, _ = newSched(t)
+	s.kickstart = append(s.kickstart, func() { <-s.runs.Done() }) // a run that only ends when cancelled
+	s.Start()
+	if drained, cancelled := s.Shutdown(10*time.Millisecond, time.Second); !drained || !cancelled {
+		t.Fatalf("busy: drained=%v cancelled=%v; want both", drained, cancelled)
+	}
+
+	s, _ = newSched(t)
+	stuck := make(chan struct{})
+	defer close(stuck)
+	s.kickstart = append(s.kickstart, func() { <-stuck })
+	s.Start()
+	if drained, _ := s.Shutdown(10*time.Millisecond, 10*time.Millisecond); drained {
+		t.Fatal("a run that ignores cancellation must not read as drained")
+	}
+}
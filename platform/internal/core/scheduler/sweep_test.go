@@ -85,3 +85,36 @@ func TestRegisterIntegritySweep_RecordsFailureEvent(t *testing.T) {
 		t.Error("expected plugin_integrity_sweep_failed (error) event")
 	}
 }
+
+// TestMaintenance_RunsOnlyWhenIdleAndDue: a check with a job in flight
+// waits; an idle check runs the pass; a second check the same day does
+// not.
+func TestMaintenance_RunsOnlyWhenIdleAndDue(t *testing.T) {
+	s, db := newSched(t)
+	if err := s.RegisterMaintenance(state.DefaultLimits); err != nil {
+		t.Fatalf("RegisterMaintenance: %v", err)
+	}
+	clock := time.Now()
+	s.now = func() time.Time { return clock }
+
+	if ok, err := db.Locks.TryAcquire("j1", 1, time.Minute); err != nil || !ok {
+		t.Fatalf("TryAcquire: %v %v", ok, err)
+	}
+	if s.maintain(state.DefaultLimits) {
+		t.Fatal("maintenance ran while a job was in flight")
+	}
+	if err := db.Locks.Release("j1"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.maintain(state.DefaultLimits) {
+		t.Fatal("maintenance did not run in an idle window")
+	}
+	clock = clock.Add(MaintenanceCheck)
+	if s.maintain(state.DefaultLimits) {
+		t.Fatal("maintenance ran twice in a day")
+	}
+	clock = clock.Add(MaintenanceEvery)
+	if !s.maintain(state.DefaultLimits) {
+		t.Fatal("maintenance did not run once a day had passed")
+	}
+}
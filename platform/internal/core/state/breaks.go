@@ -0,0 +1,109 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BreakRepo is the break-token ledger (table break_tokens): each redeemed
+// token pauses one job for a fixed length, against a weekly budget.
+type BreakRepo struct{ db *sql.DB }
+
+// Break is one redeemed token.
+type Break struct {
+	ID        int64
+	JobID     string
+	StartedAt time.Time
+	EndsAt    time.Time
+}
+
+var (
+	// ErrNoBreaksLeft: this week's budget is spent.
+	ErrNoBreaksLeft = errors.New("no breaks left this week")
+	// ErrOnBreak: the job is already paused by an earlier token.
+	ErrOnBreak = errors.New("job is already on a break")
+)
+
+// WeekStart is the start of t's budget week: Monday 00:00, local time.
+func WeekStart(t time.Time) time.Time {
+	t = t.Local()
+	back := (int(t.Weekday()) + 6) % 7 // days since Monday
+	y, m, d := t.AddDate(0, 0, -back).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// Redeem spends one of perWeek tokens to pause jobID for length from now.
+// The budget check and the insert share one transaction, so two redeems
+// racing for the last token cannot both win.
+func (r *BreakRepo) Redeem(jobID string, now time.Time, length time.Duration, perWeek int) (Break, error) {
+	if length <= 0 {
+		return Break{}, errors.New("break length must be positive")
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return Break{}, fmt.Errorf("redeem break: %w", err)
+	}
+	defer tx.Rollback()
+
+	var used, active int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM break_tokens WHERE started_at >= ?`,
+		sessionTime(WeekStart(now))).Scan(&used); err != nil {
+		return Break{}, fmt.Errorf("redeem break: %w", err)
+	}
+	if used >= perWeek {
+		return Break{}, ErrNoBreaksLeft
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM break_tokens WHERE job_id=? AND ends_at > ?`,
+		jobID, sessionTime(now)).Scan(&active); err != nil {
+		return Break{}, fmt.Errorf("redeem break: %w", err)
+	}
+	if active > 0 {
+		return Break{}, ErrOnBreak
+	}
+	b := Break{JobID: jobID, StartedAt: now.UTC().Truncate(time.Second), EndsAt: now.Add(length).UTC().Truncate(time.Second)}
+	res, err := tx.Exec(`INSERT INTO break_tokens (job_id, started_at, ends_at) VALUES (?,?,?)`,
+		jobID, sessionTime(b.StartedAt), sessionTime(b.EndsAt))
+	if err != nil {
+		return Break{}, fmt.Errorf("redeem break: %w", err)
+	}
+	b.ID, _ = res.LastInsertId()
+	return b, tx.Commit()
+}
+
+// Used counts the tokens redeemed in now's budget week.
+func (r *BreakRepo) Used(now time.Time) (int, error) {
+	var n int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM break_tokens WHERE started_at >= ?`,
+		sessionTime(WeekStart(now))).Scan(&n); err != nil {
+		return 0, fmt.Errorf("breaks used: %w", err)
+	}
+	return n, nil
+}
+
+// Active returns the breaks in force at now, soonest-ending first.
+func (r *BreakRepo) Active(now time.Time) ([]Break, error) {
+	rows, err := r.db.Query(`SELECT id, job_id, started_at, ends_at FROM break_tokens
+        WHERE started_at <= ? AND ends_at > ? ORDER BY ends_at`, sessionTime(now), sessionTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("active breaks: %w", err)
+	}
+	defer rows.Close()
+	var out []Break
+	for rows.Next() {
+		var b Break
+		var started, ends string
+		if err := rows.Scan(&b.ID, &b.JobID, &started, &ends); err != nil {
+			return nil, err
+		}
+		if b.StartedAt, err = time.Parse(time.RFC3339, started); err != nil {
+			return nil, fmt.Errorf("active breaks: %w", err)
+		}
+		if b.EndsAt, err = time.Parse(time.RFC3339, ends); err != nil {
+			return nil, fmt.Errorf("active breaks: %w", err)
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
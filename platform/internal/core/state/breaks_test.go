@@ -0,0 +1,50 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakBudgetIsWeekly(t *testing.T) {
+	db := openTest(t)
+	mon := time.Date(2026, 5, 4, 10, 0, 0, 0, time.Local) // a Monday
+
+	b, err := db.Breaks.Redeem("browser", mon, 15*time.Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Breaks.Redeem("browser", mon.Add(time.Minute), 15*time.Minute, 2); !errors.Is(err, ErrOnBreak) {
+		t.Fatalf("second break on a paused job: err = %v, want ErrOnBreak", err)
+	}
+	if active, err := db.Breaks.Active(mon.Add(14 * time.Minute)); err != nil || len(active) != 1 || active[0].ID != b.ID {
+		t.Fatalf("active = %+v, err %v", active, err)
+	}
+	if active, _ := db.Breaks.Active(mon.Add(15 * time.Minute)); len(active) != 0 {
+		t.Fatalf("break outlived its length: %+v", active)
+	}
+
+	if _, err := db.Breaks.Redeem("freedom", mon.Add(time.Hour), 15*time.Minute, 2); err != nil {
+		t.Fatal(err)
+	}
+	sun := mon.AddDate(0, 0, 6).Add(12 * time.Hour)
+	if _, err := db.Breaks.Redeem("browser", sun, 15*time.Minute, 2); !errors.Is(err, ErrNoBreaksLeft) {
+		t.Fatalf("third break in one week: err = %v, want ErrNoBreaksLeft", err)
+	}
+	nextMon := mon.AddDate(0, 0, 7)
+	if n, _ := db.Breaks.Used(nextMon); n != 0 {
+		t.Fatalf("used next week = %d, want a fresh budget", n)
+	}
+	if _, err := db.Breaks.Redeem("browser", nextMon, 15*time.Minute, 2); err != nil {
+		t.Fatalf("new week: %v", err)
+	}
+}
+
+func TestWeekStartIsMonday(t *testing.T) {
+	for _, day := range []int{4, 6, 10} { // Mon, Wed, Sun of one week
+		got := WeekStart(time.Date(2026, 5, day, 23, 59, 0, 0, time.Local))
+		if want := time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+			t.Errorf("WeekStart(May %d) = %v, want %v", day, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClockRepo records the daemon's trusted-clock skew (table clock_skew), so
+// the platform's CLI times sessions, breaks and pauses like the running
+// platform does.
+type ClockRepo struct{ db *sql.DB }
+
+// SetSkew records that the system clock is skew ahead of the trusted one.
+func (r *ClockRepo) SetSkew(skew time.Duration) error {
+	_, err := r.db.Exec(`INSERT INTO clock_skew (id, skew_s) VALUES (1, ?)
+        ON CONFLICT(id) DO UPDATE SET skew_s=excluded.skew_s`, int64(skew/time.Second))
+	if err != nil {
+		return fmt.Errorf("record clock skew: %w", err)
+	}
+	return nil
+}
+
+// Skew returns the recorded skew, 0 when the daemon never reported one.
+func (r *ClockRepo) Skew() (time.Duration, error) {
+	var s int64
+	err := r.db.QueryRow(`SELECT skew_s FROM clock_skew WHERE id = 1`).Scan(&s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("clock skew: %w", err)
+	}
+	return time.Duration(s) * time.Second, nil
+}
+
+// Now is the trusted time when the system clock reads sys. An unreadable
+// skew (an older read-only database) falls back to sys.
+func (r *ClockRepo) Now(sys time.Time) time.Time {
+	skew, err := r.Skew()
+	if err != nil {
+		return sys
+	}
+	return sys.Add(-skew)
+}
@@ -0,0 +1,23 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	db := openTest(t)
+	sys := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	if got := db.Clock.Now(sys); !got.Equal(sys) {
+		t.Fatalf("no skew recorded: Now = %v, want the system time", got)
+	}
+	if err := db.Clock.SetSkew(72 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Clock.SetSkew(48 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.Clock.Now(sys); !got.Equal(sys.Add(-48 * time.Hour)) {
+		t.Fatalf("Now = %v, want the latest skew taken off", got)
+	}
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -21,6 +22,24 @@ const (
 	// EventIntegritySweepFailed: the periodic whole-bundle integrity sweep
 	// errored. Recorded so a wedged sweep can't hide behind a green status.
 	EventIntegritySweepFailed = "plugin_integrity_sweep_failed"
+	// EventBreakRedeemed: a break token paused a job (`platform break`).
+	EventBreakRedeemed = "break_redeemed"
+	// EventPauseStarted: a transcribed pause stopped the pausable jobs
+	// (`platform pause`).
+	EventPauseStarted = "pause_started"
+	// EventCalendarProfile: a tagged calendar entry switched a profile on,
+	// or its end switched it off.
+	EventCalendarProfile = "calendar_profile"
+	// EventPolicyChanged: the enforced jobs differ from the ones the
+	// previous platform start persisted (a release changed the policy).
+	EventPolicyChanged = "policy_changed"
+	// EventCadence: job cadence started or stopped backing off (battery or
+	// thermal pressure; config platform.backoff).
+	EventCadence = "cadence_backoff"
+	// EventStarted and EventStopped bracket each `platform run`. A start
+	// with no stop after it is a run that did not shut down cleanly.
+	EventStarted = "platform_started"
+	EventStopped = "platform_stopped"
 )
 
 // EventRepo records platform-level events (skips, validation failures,
@@ -101,6 +120,163 @@ func (r *EventRepo) RecordIntegrityCheckFailed(jobID, pluginID, reason string) e
 		"plugin integrity check failed; did not run", string(details))
 }
 
+// RecordBreakRedeemed audits one redeemed break token: which job, for how
+// long, and how many tokens the week has left.
+func (r *EventRepo) RecordBreakRedeemed(b Break, left int) error {
+	details, _ := json.Marshal(map[string]any{
+		"job_id":    b.JobID,
+		"length_s":  int64(b.EndsAt.Sub(b.StartedAt) / time.Second),
+		"ends_at":   b.EndsAt.Format(time.RFC3339),
+		"left_week": left,
+	})
+	return r.Record(SeverityWarn, EventBreakRedeemed, "break token redeemed; job paused", string(details))
+}
+
+// RecordPauseStarted audits one pause: its window and the reason given.
+func (r *EventRepo) RecordPauseStarted(p Pause) error {
+	details, _ := json.Marshal(map[string]any{
+		"started_at": p.StartedAt.Format(time.RFC3339),
+		"ends_at":    p.EndsAt.Format(time.RFC3339),
+		"length_s":   int64(p.EndsAt.Sub(p.StartedAt) / time.Second),
+		"reason":     p.Reason,
+	})
+	return r.Record(SeverityWarn, EventPauseStarted, "pause started after transcription", string(details))
+}
+
+// RecordPolicyChanged audits a policy change by job id. A removal loosens
+// enforcement, so it raises the severity to warn.
+func (r *EventRepo) RecordPolicyChanged(added, removed, changed []string) error {
+	details, _ := json.Marshal(map[string][]string{
+		"added":   added,
+		"removed": removed,
+		"changed": changed,
+	})
+	sev := SeverityInfo
+	if len(removed) > 0 {
+		sev = SeverityWarn
+	}
+	msg := fmt.Sprintf("policy changed: %d added, %d changed, %d removed", len(added), len(changed), len(removed))
+	return r.Record(sev, EventPolicyChanged, msg, string(details))
+}
+
+// CalendarBlock is the calendar entry driving a profile.
+type CalendarBlock struct {
+	Profile string    `json:"profile"`
+	Entry   string    `json:"entry"`
+	EndsAt  time.Time `json:"ends_at"`
+}
+
+// RecordCalendarProfile audits a calendar entry switching b.Profile on, or
+// off when on is false.
+func (r *EventRepo) RecordCalendarProfile(b CalendarBlock, on bool) error {
+	details, _ := json.Marshal(struct {
+		CalendarBlock
+		On bool `json:"on"`
+	}{b, on})
+	msg := "calendar entry ended; profile off"
+	if on {
+		msg = "calendar entry started; profile on"
+	}
+	return r.Record(SeverityInfo, EventCalendarProfile, msg, string(details))
+}
+
+// ActiveCalendarBlock returns the calendar entry in force at now: the
+// latest calendar_profile event, when it switched a profile on and its end
+// is still ahead.
+func (r *EventRepo) ActiveCalendarBlock(now time.Time) (CalendarBlock, bool, error) {
+	var details string
+	err := r.db.QueryRow(`SELECT details_json FROM platform_events WHERE event_type=?
+        ORDER BY id DESC LIMIT 1`, EventCalendarProfile).Scan(&details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CalendarBlock{}, false, nil
+	}
+	if err != nil {
+		return CalendarBlock{}, false, fmt.Errorf("calendar block: %w", err)
+	}
+	var b struct {
+		CalendarBlock
+		On bool `json:"on"`
+	}
+	if err := json.Unmarshal([]byte(details), &b); err != nil {
+		return CalendarBlock{}, false, fmt.Errorf("calendar block: %w", err)
+	}
+	if !b.On || !now.Before(b.EndsAt) {
+		return CalendarBlock{}, false, nil
+	}
+	return b.CalendarBlock, true, nil
+}
+
+// RecordCadence audits cadence backing off for reason ("on battery",
+// "thermal pressure"), or returning to normal when reason is "".
+func (r *EventRepo) RecordCadence(reason string) error {
+	details, _ := json.Marshal(map[string]string{"reason": reason})
+	msg := "cadence back to normal"
+	if reason != "" {
+		msg = "cadence backing off: " + reason
+	}
+	return r.Record(SeverityInfo, EventCadence, msg, string(details))
+}
+
+// ReducedCadence returns why cadence is backed off, from the latest
+// cadence event; "" when it is normal or was never reduced.
+func (r *EventRepo) ReducedCadence() (string, error) {
+	var details string
+	err := r.db.QueryRow(`SELECT details_json FROM platform_events WHERE event_type=?
+        ORDER BY id DESC LIMIT 1`, EventCadence).Scan(&details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cadence: %w", err)
+	}
+	var d struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(details), &d); err != nil {
+		return "", fmt.Errorf("cadence: %w", err)
+	}
+	return d.Reason, nil
+}
+
+// RecordStarted marks a platform run under way.
+func (r *EventRepo) RecordStarted() error {
+	return r.Record(SeverityInfo, EventStarted, "platform started", "")
+}
+
+// RecordStopped marks the run over. drained says every in-flight run
+// settled, its row and events written, before the process exits.
+func (r *EventRepo) RecordStopped(drained bool) error {
+	details, _ := json.Marshal(map[string]bool{"drained": drained})
+	msg := "platform stopped"
+	if !drained {
+		msg = "platform stopped with runs in flight"
+	}
+	return r.Record(SeverityInfo, EventStopped, msg, string(details))
+}
+
+// PreviousShutdown reports how the last platform run ended, from the
+// latest start or stop event: clean when it recorded a drained stop.
+// known is false on a database no run has started on.
+func (r *EventRepo) PreviousShutdown() (clean, known bool, err error) {
+	var typ, details string
+	err = r.db.QueryRow(`SELECT event_type, details_json FROM platform_events
+        WHERE event_type IN (?,?) ORDER BY id DESC LIMIT 1`, EventStarted, EventStopped).Scan(&typ, &details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("previous shutdown: %w", err)
+	}
+	if typ == EventStarted {
+		return false, true, nil
+	}
+	var d struct {
+		Drained bool `json:"drained"`
+	}
+	_ = json.Unmarshal([]byte(details), &d)
+	return d.Drained, true, nil
+}
+
 // escapeLike escapes the SQL LIKE metacharacters (\, %, _) in s using `\`
 // as the escape character, so an arbitrary jobID is matched literally
 // rather than as a wildcard pattern. The backslash itself is escaped first
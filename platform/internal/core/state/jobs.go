@@ -33,6 +33,14 @@ ON CONFLICT(id) DO UPDATE SET
 	return nil
 }
 
+// Delete removes one job's row. Run history keeps its job id.
+func (r *JobRepo) Delete(id string) error {
+	if _, err := r.db.Exec(`DELETE FROM jobs WHERE id=?`, id); err != nil {
+		return fmt.Errorf("delete job %s: %w", id, err)
+	}
+	return nil
+}
+
 // List returns all jobs ordered by id.
 func (r *JobRepo) List() ([]Job, error) {
 	rows, err := r.db.Query(`SELECT id,plugin_id,enabled,schedule,timeout_ms,
@@ -74,3 +74,24 @@ func (r *JobLockRepo) Held(jobID string) (bool, error) {
 	}
 	return nowTime().Before(exp), nil
 }
+
+// AnyHeld reports whether any job has a run in flight (a non-expired
+// lock). Maintenance waits for a moment when none does.
+func (r *JobLockRepo) AnyHeld() (bool, error) {
+	rows, err := r.db.Query(`SELECT expires_at FROM job_locks`)
+	if err != nil {
+		return false, fmt.Errorf("read locks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var expiresAt string
+		if err := rows.Scan(&expiresAt); err != nil {
+			return false, err
+		}
+		exp, perr := time.Parse(time.RFC3339Nano, expiresAt)
+		if perr != nil || nowTime().Before(exp) {
+			return true, nil // unparseable => treat as held (conservative)
+		}
+	}
+	return false, rows.Err()
+}
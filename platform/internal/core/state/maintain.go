@@ -0,0 +1,66 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits bounds the two tables that grow with every tick: job_runs and
+// platform_events. Rows past the newest MaxRuns / MaxEvents are trimmed,
+// oldest first, but never one younger than Keep, so the default `platform
+// report` window stays whole however busy the jobs are. The newest row of
+// each job+status and of each event type always survives: status, metrics
+// and the latest-event reads (calendar, cadence, shutdown) key on those.
+type Limits struct {
+	MaxRuns   int
+	MaxEvents int
+	Keep      time.Duration
+}
+
+// DefaultLimits are the caps the scheduler's maintenance uses. At the
+// default cadence (a handful of 10s jobs) 200k runs is about a week; the
+// lifetime figures live in daily_stats, which is never trimmed.
+var DefaultLimits = Limits{MaxRuns: 200_000, MaxEvents: 50_000, Keep: 8 * 24 * time.Hour}
+
+// Maintained is what one Maintain pass did.
+type Maintained struct {
+	Runs, Events int64 // rows trimmed
+	Vacuumed     bool
+}
+
+// Maintain trims job_runs and platform_events to l, then refreshes the
+// query planner's statistics (ANALYZE) and, when it trimmed anything,
+// returns the freed pages to the filesystem (VACUUM). VACUUM holds the
+// write lock for the rewrite, so the scheduler runs this only while no job
+// is in flight; a concurrent read-only status rides it out on its
+// busy_timeout.
+func (d *DB) Maintain(l Limits) (Maintained, error) {
+	var m Maintained
+	cutoff := nowTime().Add(-l.Keep).Format(time.RFC3339Nano)
+	res, err := d.sql.Exec(`DELETE FROM job_runs
+        WHERE id <= (SELECT id FROM job_runs ORDER BY id DESC LIMIT 1 OFFSET ?)
+          AND started_at < ?
+          AND id NOT IN (SELECT MAX(id) FROM job_runs GROUP BY job_id, status)`, l.MaxRuns, cutoff)
+	if err != nil {
+		return m, fmt.Errorf("trim runs: %w", err)
+	}
+	m.Runs, _ = res.RowsAffected()
+	res, err = d.sql.Exec(`DELETE FROM platform_events
+        WHERE id <= (SELECT id FROM platform_events ORDER BY id DESC LIMIT 1 OFFSET ?)
+          AND timestamp < ?
+          AND id NOT IN (SELECT MAX(id) FROM platform_events GROUP BY event_type)`, l.MaxEvents, cutoff)
+	if err != nil {
+		return m, fmt.Errorf("trim events: %w", err)
+	}
+	m.Events, _ = res.RowsAffected()
+	if _, err := d.sql.Exec(`ANALYZE`); err != nil {
+		return m, fmt.Errorf("analyze: %w", err)
+	}
+	if m.Runs+m.Events > 0 {
+		if _, err := d.sql.Exec(`VACUUM`); err != nil {
+			return m, fmt.Errorf("vacuum: %w", err)
+		}
+		m.Vacuumed = true
+	}
+	return m, nil
+}
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func countRows(t *testing.T, db *DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.sql.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMaintainTrimsToCapKeepingLatestPerKind(t *testing.T) {
+	db := openTest(t)
+	// One old failure for j2, then a run of skips for j1.
+	if err := db.Runs.RecordError("j2", "p", "boom"); err != nil {
+		t.Fatal(err)
+	}
+	for range 20 {
+		if err := db.Runs.RecordSkipped("j1", "p", "overlap"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Events.RecordCadence("on battery"); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		if err := db.Events.Record(SeverityInfo, EventStarted, "platform started", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := db.Maintain(Limits{MaxRuns: 5, MaxEvents: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 newest j1 skips + j2's only (latest) error survive.
+	if got := countRows(t, db, "job_runs"); got != 6 || m.Runs != 15 {
+		t.Fatalf("job_runs = %d (trimmed %d), want 6 (15)", got, m.Runs)
+	}
+	if got := countRows(t, db, "platform_events"); got != 4 || m.Events != 7 {
+		t.Fatalf("platform_events = %d (trimmed %d), want 4 (7)", got, m.Events)
+	}
+	if !m.Vacuumed {
+		t.Fatal("trimmed rows but did not vacuum")
+	}
+	if why, _ := db.Events.ReducedCadence(); why != "on battery" {
+		t.Fatalf("the latest cadence event was trimmed: %q", why)
+	}
+	if _, err := db.Runs.LastByStatus("j2", "error"); err != nil {
+		t.Fatalf("j2's latest error was trimmed: %v", err)
+	}
+}
+
+func TestMaintainKeepsRecentRowsOverCap(t *testing.T) {
+	db := openTest(t)
+	for range 10 {
+		if err := db.Runs.RecordSkipped("j1", "p", "overlap"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := db.Maintain(Limits{MaxRuns: 2, MaxEvents: 2, Keep: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Runs != 0 || m.Vacuumed || countRows(t, db, "job_runs") != 10 {
+		t.Fatalf("trimmed rows inside the keep window: %+v", m)
+	}
+}
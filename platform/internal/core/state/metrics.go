@@ -0,0 +1,135 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Aggregates for `platform metrics` (the Prometheus textfile output). All
+// are whole-history reads over the rows still in the DB, so a counter only
+// goes backwards if the DB itself is reset or maintenance trims history
+// (Maintain) — which a scraper reads as a counter reset, the standard
+// semantics.
+
+// RunCount is how many runs of one job ended in one status.
+type RunCount struct {
+	JobID  string
+	Status string
+	N      int64
+}
+
+// CountByStatus returns per-job, per-status run counts (in-progress rows
+// excluded), ordered by job then status.
+func (r *JobRunRepo) CountByStatus() ([]RunCount, error) {
+	return r.countByStatus(`status<>'running'`)
+}
+
+// countByStatus groups the rows matching where (an SQL predicate) by job and
+// status.
+func (r *JobRunRepo) countByStatus(where string, args ...any) ([]RunCount, error) {
+	rows, err := r.db.Query(`SELECT job_id,status,COUNT(*) FROM job_runs
+        WHERE `+where+` GROUP BY job_id,status ORDER BY job_id,status`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count runs: %w", err)
+	}
+	defer rows.Close()
+	var out []RunCount
+	for rows.Next() {
+		var c RunCount
+		if err := rows.Scan(&c.JobID, &c.Status, &c.N); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// LastRun is a job's most recent finished run.
+type LastRun struct {
+	JobID      string
+	StartedAt  time.Time
+	DurationMS int64
+}
+
+// LastRuns returns each job's newest finished run, ordered by job. Skipped
+// and unavailable rows are excluded: they carry no duration and would make
+// a wedged job look freshly active.
+func (r *JobRunRepo) LastRuns() ([]LastRun, error) {
+	rows, err := r.db.Query(`SELECT job_id,started_at,duration_ms FROM job_runs
+        WHERE id IN (SELECT MAX(id) FROM job_runs
+                     WHERE status NOT IN ('running','skipped','unavailable') GROUP BY job_id)
+        ORDER BY job_id`)
+	if err != nil {
+		return nil, fmt.Errorf("last runs: %w", err)
+	}
+	defer rows.Close()
+	var out []LastRun
+	for rows.Next() {
+		var l LastRun
+		var ts string
+		if err := rows.Scan(&l.JobID, &ts, &l.DurationMS); err != nil {
+			return nil, err
+		}
+		t, perr := time.Parse(time.RFC3339Nano, ts)
+		if perr != nil {
+			continue // an unparseable row can't yield an age; skip it
+		}
+		l.StartedAt = t
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// Enforcement totals the actions plugins reported in their result details:
+// processes killed (details.killed_count, details.bypass.killed_pids), paths
+// removed (details.uninstall_removed) and apps relaunched
+// (details.relaunched). Plugins that report none of these contribute zeros.
+type Enforcement struct {
+	JobID      string
+	Kills      int64
+	Removals   int64
+	Relaunches int64
+}
+
+// EnforcementTotals sums Enforcement over every run with a valid JSON result,
+// ordered by job.
+func (r *JobRunRepo) EnforcementTotals() ([]Enforcement, error) {
+	rows, err := r.db.Query(`SELECT job_id,
+        SUM(COALESCE(json_extract(stdout_json,'$.details.killed_count'),0)
+          + COALESCE(json_array_length(stdout_json,'$.details.bypass.killed_pids'),0)),
+        SUM(COALESCE(json_array_length(stdout_json,'$.details.uninstall_removed'),0)),
+        SUM(COALESCE(json_array_length(stdout_json,'$.details.relaunched'),0))
+        FROM job_runs WHERE json_valid(stdout_json) GROUP BY job_id ORDER BY job_id`)
+	if err != nil {
+		return nil, fmt.Errorf("enforcement totals: %w", err)
+	}
+	defer rows.Close()
+	var out []Enforcement
+	for rows.Next() {
+		var e Enforcement
+		if err := rows.Scan(&e.JobID, &e.Kills, &e.Removals, &e.Relaunches); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// CountByType returns how many platform events of each type were recorded.
+func (r *EventRepo) CountByType() (map[string]int64, error) {
+	rows, err := r.db.Query(`SELECT event_type,COUNT(*) FROM platform_events GROUP BY event_type`)
+	if err != nil {
+		return nil, fmt.Errorf("count events: %w", err)
+	}
+	defer rows.Close()
+	out := map[string]int64{}
+	for rows.Next() {
+		var t string
+		var n int64
+		if err := rows.Scan(&t, &n); err != nil {
+			return nil, err
+		}
+		out[t] = n
+	}
+	return out, rows.Err()
+}
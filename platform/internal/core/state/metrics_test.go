@@ -0,0 +1,65 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetricsAggregates(t *testing.T) {
+	db := openTest(t)
+	finish := func(job, status string, ms int64, stdout string) {
+		t.Helper()
+		id, err := db.Runs.Start(job, "p", "1", "scheduler")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Runs.Finish(JobRun{ID: id, DurationMS: ms, Status: status, StdoutJSON: stdout}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	finish("kill", RunStatusOK, 10, `{"status":"ok","details":{"killed_count":2,"uninstall_removed":["a","b","c"],"bypass":{"killed_pids":[7]}}}`)
+	finish("kill", RunStatusFailed, 30, `{"status":"failed","details":{"killed_count":1}}`)
+	finish("kill", RunStatusOK, 20, `not json`)
+	finish("freedom", RunStatusOK, 5, `{"status":"ok","details":{"relaunched":["app","proxy"]}}`)
+	if _, err := db.Runs.Start("kill", "p", "1", "scheduler"); err != nil { // in-flight: excluded
+		t.Fatal(err)
+	}
+	_ = db.Runs.RecordSkipped("freedom", "p", "overlap")
+	_ = db.Events.Record(SeverityWarn, EventTamperRepaired, "m", "")
+	_ = db.Events.Record(SeverityWarn, EventTamperRepaired, "m", "")
+
+	counts, err := db.Runs.CountByStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCounts := []RunCount{
+		{"freedom", RunStatusOK, 1}, {"freedom", RunStatusSkipped, 1},
+		{"kill", RunStatusFailed, 1}, {"kill", RunStatusOK, 2},
+	}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("CountByStatus = %+v", counts)
+	}
+
+	last, err := db.Runs.LastRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last) != 2 || last[0].JobID != "freedom" || last[0].DurationMS != 5 ||
+		last[1].DurationMS != 20 || last[1].StartedAt.IsZero() {
+		t.Errorf("LastRuns = %+v, want the newest finished (non-skipped) run per job", last)
+	}
+
+	enf, err := db.Runs.EnforcementTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEnf := []Enforcement{{"freedom", 0, 0, 2}, {"kill", 4, 3, 0}}
+	if !reflect.DeepEqual(enf, wantEnf) {
+		t.Errorf("EnforcementTotals = %+v, want %+v", enf, wantEnf)
+	}
+
+	ev, err := db.Events.CountByType()
+	if err != nil || ev[EventTamperRepaired] != 2 {
+		t.Errorf("CountByType = %v err=%v", ev, err)
+	}
+}
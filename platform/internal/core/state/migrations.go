@@ -94,6 +94,120 @@ CREATE TABLE platform_events (
     details_json TEXT NOT NULL DEFAULT ''
 );
 CREATE INDEX idx_events_ts ON platform_events(timestamp);
+`,
+	},
+	{
+		// Persistent per-day, per-job counters behind `platform stats`, kept
+		// incrementally by the runner so lifetime totals never rescan
+		// job_runs. day is the LOCAL calendar day (streaks are lived in local
+		// time). The INSERTs backfill the counters from existing history.
+		version: 2,
+		sql: `
+CREATE TABLE daily_stats (
+    day        TEXT NOT NULL,
+    job_id     TEXT NOT NULL,
+    runs       INTEGER NOT NULL DEFAULT 0,
+    blocks     INTEGER NOT NULL DEFAULT 0,
+    kills      INTEGER NOT NULL DEFAULT 0,
+    removals   INTEGER NOT NULL DEFAULT 0,
+    relaunches INTEGER NOT NULL DEFAULT 0,
+    restores   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, job_id)
+);
+
+INSERT INTO daily_stats (day, job_id, runs, blocks, kills, removals, relaunches)
+SELECT date(started_at, 'localtime'), job_id, COUNT(*),
+       SUM(CASE WHEN json_valid(stdout_json) AND (
+                COALESCE(json_extract(stdout_json,'$.details.killed_count'),0)>0
+             OR COALESCE(json_array_length(stdout_json,'$.details.blocked_apps'),0)>0
+             OR COALESCE(json_array_length(stdout_json,'$.details.uninstall_removed'),0)>0
+             OR COALESCE(json_array_length(stdout_json,'$.details.bypass.killed_pids'),0)>0)
+           THEN 1 ELSE 0 END),
+       SUM(CASE WHEN json_valid(stdout_json) THEN
+                COALESCE(json_extract(stdout_json,'$.details.killed_count'),0)
+              + COALESCE(json_array_length(stdout_json,'$.details.bypass.killed_pids'),0)
+           ELSE 0 END),
+       SUM(CASE WHEN json_valid(stdout_json) THEN
+                COALESCE(json_array_length(stdout_json,'$.details.uninstall_removed'),0) ELSE 0 END),
+       SUM(CASE WHEN json_valid(stdout_json) THEN
+                COALESCE(json_array_length(stdout_json,'$.details.relaunched'),0) ELSE 0 END)
+FROM job_runs WHERE status IN ('ok','failed')
+GROUP BY 1, 2;
+
+INSERT INTO daily_stats (day, job_id, restores)
+SELECT date(timestamp, 'localtime'), COALESCE(json_extract(details_json,'$.job_id'),''), COUNT(*)
+FROM platform_events WHERE event_type='plugin_tamper_repaired' AND json_valid(details_json)
+GROUP BY 1, 2
+ON CONFLICT (day, job_id) DO UPDATE SET restores = restores + excluded.restores;
+`,
+	},
+	{
+		// Focus sessions (`platform session start`): windows of extra
+		// strictness. Rows are never updated to end early; extending a
+		// session inserts nothing and only moves ends_at later. Times are
+		// second-precision UTC RFC3339, fixed width, so they compare as text.
+		version: 3,
+		sql: `
+CREATE TABLE focus_sessions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TEXT NOT NULL,
+    ends_at    TEXT NOT NULL
+);
+CREATE INDEX idx_focus_sessions_ends ON focus_sessions(ends_at);
+`,
+	},
+	{
+		// Break tokens (`platform break`): each row is one redeemed token
+		// pausing one job until ends_at. Rows are the budget ledger too, so
+		// they are never deleted. Times as in focus_sessions.
+		version: 4,
+		sql: `
+CREATE TABLE break_tokens (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id     TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    ends_at    TEXT NOT NULL
+);
+CREATE INDEX idx_break_tokens_started ON break_tokens(started_at);
+`,
+	},
+	{
+		// Strict lock (`daemon lock`): the one row is the latest end the
+		// daemon has handed the running platform. Read by `platform break`,
+		// which refuses while it holds.
+		version: 5,
+		sql: `
+CREATE TABLE strict_lock (
+    id    INTEGER PRIMARY KEY CHECK (id = 1),
+    until TEXT NOT NULL
+);
+`,
+	},
+	{
+		// Pauses (`platform pause`): each row is one earned pause of the
+		// pausable jobs until ends_at, with the reason the user typed. Kept
+		// as the audit trail; times as in focus_sessions.
+		version: 6,
+		sql: `
+CREATE TABLE pauses (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TEXT NOT NULL,
+    ends_at    TEXT NOT NULL,
+    reason     TEXT NOT NULL
+);
+CREATE INDEX idx_pauses_ends ON pauses(ends_at);
+`,
+	},
+	{
+		// Trusted clock: the one row is how far the system clock was ahead
+		// of the daemon's trusted clock at its last report. Sessions,
+		// breaks and pauses are timed against the trusted clock.
+		version: 7,
+		sql: `
+CREATE TABLE clock_skew (
+    id     INTEGER PRIMARY KEY CHECK (id = 1),
+    skew_s INTEGER NOT NULL
+);
 `,
 	},
 }
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PauseRepo records earned pauses (table pauses). Like sessions, a pause
+// cannot be ended or extended once written: it stops applying at its end.
+type PauseRepo struct{ db *sql.DB }
+
+// Pause is one recorded pause and the reason given for it.
+type Pause struct {
+	ID        int64
+	StartedAt time.Time
+	EndsAt    time.Time
+	Reason    string
+}
+
+// ErrPaused: a pause is already running; the next one starts after it.
+var ErrPaused = errors.New("a pause is already running")
+
+// Start records a pause lasting d from now. The active check and the
+// insert share one transaction, so two pauses cannot overlap.
+func (r *PauseRepo) Start(now time.Time, d time.Duration, reason string) (Pause, error) {
+	if d <= 0 {
+		return Pause{}, errors.New("pause length must be positive")
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return Pause{}, fmt.Errorf("start pause: %w", err)
+	}
+	defer tx.Rollback()
+
+	var active int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pauses WHERE ends_at > ?`, sessionTime(now)).Scan(&active); err != nil {
+		return Pause{}, fmt.Errorf("start pause: %w", err)
+	}
+	if active > 0 {
+		return Pause{}, ErrPaused
+	}
+	p := Pause{StartedAt: now.UTC().Truncate(time.Second), EndsAt: now.Add(d).UTC().Truncate(time.Second), Reason: reason}
+	res, err := tx.Exec(`INSERT INTO pauses (started_at, ends_at, reason) VALUES (?,?,?)`,
+		sessionTime(p.StartedAt), sessionTime(p.EndsAt), reason)
+	if err != nil {
+		return Pause{}, fmt.Errorf("start pause: %w", err)
+	}
+	p.ID, _ = res.LastInsertId()
+	return p, tx.Commit()
+}
+
+// Active returns the pause in force at now, if any.
+func (r *PauseRepo) Active(now time.Time) (Pause, bool, error) {
+	var p Pause
+	var started, ends string
+	err := r.db.QueryRow(`SELECT id, started_at, ends_at, reason FROM pauses
+        WHERE started_at <= ? AND ends_at > ? ORDER BY ends_at DESC LIMIT 1`,
+		sessionTime(now), sessionTime(now)).Scan(&p.ID, &started, &ends, &p.Reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Pause{}, false, nil
+	}
+	if err != nil {
+		return Pause{}, false, fmt.Errorf("active pause: %w", err)
+	}
+	if p.StartedAt, err = time.Parse(time.RFC3339, started); err != nil {
+		return Pause{}, false, fmt.Errorf("active pause: %w", err)
+	}
+	if p.EndsAt, err = time.Parse(time.RFC3339, ends); err != nil {
+		return Pause{}, false, fmt.Errorf("active pause: %w", err)
+	}
+	return p, true, nil
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPauseDoesNotOverlapAndIsAudited(t *testing.T) {
+	db := openTest(t)
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	p, err := db.Pauses.Start(now, 15*time.Minute, "dinner recipe video")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Pauses.Start(now.Add(time.Minute), 5*time.Minute, "again"); !errors.Is(err, ErrPaused) {
+		t.Fatalf("overlapping pause: err=%v, want ErrPaused", err)
+	}
+	if got, ok, err := db.Pauses.Active(now.Add(10 * time.Minute)); err != nil || !ok || got.Reason != p.Reason {
+		t.Fatalf("active = %+v %v %v", got, ok, err)
+	}
+	if _, ok, _ := db.Pauses.Active(now.Add(15 * time.Minute)); ok {
+		t.Fatal("pause still active at its end")
+	}
+	if _, err := db.Pauses.Start(now.Add(20*time.Minute), 5*time.Minute, "later"); err != nil {
+		t.Fatalf("pause after the last one ended: %v", err)
+	}
+
+	if err := db.Events.RecordPauseStarted(p); err != nil {
+		t.Fatal(err)
+	}
+	evs, err := db.Events.Recent(1)
+	if err != nil || len(evs) != 1 || evs[0].EventType != EventPauseStarted {
+		t.Fatalf("events = %+v, err %v", evs, err)
+	}
+	var d struct {
+		LengthS int64  `json:"length_s"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(evs[0].DetailsJSON), &d); err != nil || d.LengthS != 900 || d.Reason != p.Reason {
+		t.Fatalf("details = %s", evs[0].DetailsJSON)
+	}
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Window reads for `platform report`. Each takes a [from, to) window on
+// started_at/timestamp; both are stored as UTC RFC3339Nano, so the string
+// comparison in SQL orders correctly.
+
+func window(from, to time.Time) (string, string) {
+	return from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano)
+}
+
+// CountByStatusBetween is CountByStatus restricted to runs started in the
+// window.
+func (r *JobRunRepo) CountByStatusBetween(from, to time.Time) ([]RunCount, error) {
+	f, t := window(from, to)
+	return r.countByStatus(`status<>'running' AND started_at>=? AND started_at<?`, f, t)
+}
+
+// ActiveHours counts the distinct UTC hours in the window that saw at least
+// one run complete (ok or failed — either proves the platform was up and
+// scheduling). It is the report's uptime proxy.
+func (r *JobRunRepo) ActiveHours(from, to time.Time) (int, error) {
+	f, t := window(from, to)
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(DISTINCT substr(started_at,1,13)) FROM job_runs
+        WHERE status IN ('ok','failed') AND started_at>=? AND started_at<?`, f, t).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("active hours: %w", err)
+	}
+	return n, nil
+}
+
+// RunResult is one run's plugin result JSON.
+type RunResult struct {
+	JobID      string
+	StdoutJSON string
+}
+
+// EnforcementResults returns the results of runs in the window whose plugin
+// reported taking an action (the keys EnforcementTotals sums, plus
+// blocked_apps). Steady-state no-op runs are filtered out in SQL, so a week
+// of 10s ticks reads back as a handful of rows.
+func (r *JobRunRepo) EnforcementResults(from, to time.Time) ([]RunResult, error) {
+	f, t := window(from, to)
+	rows, err := r.db.Query(`SELECT job_id,stdout_json FROM job_runs
+        WHERE started_at>=? AND started_at<? AND json_valid(stdout_json) AND (
+              COALESCE(json_extract(stdout_json,'$.details.killed_count'),0)>0
+           OR COALESCE(json_array_length(stdout_json,'$.details.blocked_apps'),0)>0
+           OR COALESCE(json_array_length(stdout_json,'$.details.uninstall_removed'),0)>0
+           OR COALESCE(json_array_length(stdout_json,'$.details.bypass.killed_pids'),0)>0
+           OR COALESCE(json_array_length(stdout_json,'$.details.relaunched'),0)>0)
+        ORDER BY id`, f, t)
+	if err != nil {
+		return nil, fmt.Errorf("enforcement results: %w", err)
+	}
+	defer rows.Close()
+	var out []RunResult
+	for rows.Next() {
+		var rr RunResult
+		if err := rows.Scan(&rr.JobID, &rr.StdoutJSON); err != nil {
+			return nil, err
+		}
+		out = append(out, rr)
+	}
+	return out, rows.Err()
+}
+
+// CountByTypeBetween is CountByType restricted to events in the window.
+func (r *EventRepo) CountByTypeBetween(from, to time.Time) (map[string]int64, error) {
+	f, t := window(from, to)
+	rows, err := r.db.Query(`SELECT event_type,COUNT(*) FROM platform_events
+        WHERE timestamp>=? AND timestamp<? GROUP BY event_type`, f, t)
+	if err != nil {
+		return nil, fmt.Errorf("count events: %w", err)
+	}
+	defer rows.Close()
+	out := map[string]int64{}
+	for rows.Next() {
+		var typ string
+		var n int64
+		if err := rows.Scan(&typ, &n); err != nil {
+			return nil, err
+		}
+		out[typ] = n
+	}
+	return out, rows.Err()
+}
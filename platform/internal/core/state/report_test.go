@@ -0,0 +1,57 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportWindowReads(t *testing.T) {
+	db := openTest(t)
+	finish := func(job, status, stdout string) {
+		t.Helper()
+		id, err := db.Runs.Start(job, "p", "1", "scheduler")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Runs.Finish(JobRun{ID: id, Status: status, StdoutJSON: stdout}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	finish("kill", RunStatusOK, `{"status":"ok","details":{"killed_count":0,"blocked_apps":[]}}`) // no-op
+	finish("kill", RunStatusOK, `{"status":"ok","details":{"killed_count":3,"blocked_apps":["Steam"]}}`)
+	finish("kill", RunStatusFailed, `{"status":"failed","details":{"uninstall_removed":["x"]}}`)
+	finish("freedom", RunStatusOK, `{"status":"ok","details":{"relaunched":["app"]}}`)
+	_ = db.Events.Record(SeverityWarn, EventTamperRepaired, "m", "")
+
+	now := time.Now()
+	in0, in1 := now.Add(-time.Hour), now.Add(time.Hour)
+	past0, past1 := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+
+	res, err := db.Runs.EnforcementResults(in0, in1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 || res[0].JobID != "kill" || res[2].JobID != "freedom" {
+		t.Errorf("EnforcementResults = %+v, want the three acting runs", res)
+	}
+	if res, _ := db.Runs.EnforcementResults(past0, past1); len(res) != 0 {
+		t.Errorf("a window before every run returned %d results", len(res))
+	}
+
+	counts, err := db.Runs.CountByStatusBetween(in0, in1)
+	if err != nil || len(counts) != 3 {
+		t.Errorf("CountByStatusBetween = %+v err=%v", counts, err)
+	}
+	if h, err := db.Runs.ActiveHours(in0, in1); err != nil || h < 1 || h > 2 {
+		t.Errorf("ActiveHours = %d err=%v, want the current hour", h, err)
+	}
+	if h, _ := db.Runs.ActiveHours(past0, past1); h != 0 {
+		t.Errorf("ActiveHours(past) = %d, want 0", h)
+	}
+	if ev, err := db.Events.CountByTypeBetween(in0, in1); err != nil || ev[EventTamperRepaired] != 1 {
+		t.Errorf("CountByTypeBetween = %v err=%v", ev, err)
+	}
+	if ev, _ := db.Events.CountByTypeBetween(past0, past1); len(ev) != 0 {
+		t.Errorf("CountByTypeBetween(past) = %v", ev)
+	}
+}
@@ -93,6 +93,27 @@ func (r *JobRunRepo) History(jobID string, limit int) ([]JobRun, error) {
 	return out, rows.Err()
 }
 
+// Recent returns the most recent runs across every job, newest first.
+func (r *JobRunRepo) Recent(limit int) ([]JobRun, error) {
+	rows, err := r.db.Query(`SELECT id,job_id,plugin_id,plugin_version,started_at,
+        COALESCE(ended_at,''),duration_ms,status,exit_code,message,stdout_json,
+        stderr_text,error_text,timed_out,triggered_by
+        FROM job_runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recent runs: %w", err)
+	}
+	defer rows.Close()
+	var out []JobRun
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
+
 // LastByStatus returns the most recent run for a job with the given
 // status, or sql.ErrNoRows.
 func (r *JobRunRepo) LastByStatus(jobID, status string) (JobRun, error) {
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SessionRepo records focus sessions (table focus_sessions). There is no
+// way to end one: a session stops applying when its end time passes.
+type SessionRepo struct{ db *sql.DB }
+
+// Session is one focus session.
+type Session struct {
+	ID        int64
+	StartedAt time.Time
+	EndsAt    time.Time
+}
+
+// sessionTime is the stored form: UTC, whole seconds, fixed width.
+func sessionTime(t time.Time) string { return t.UTC().Truncate(time.Second).Format(time.RFC3339) }
+
+// Start begins a session lasting d from now. If one is already active it
+// is extended to now+d instead, and only when that ends later: starting a
+// shorter session never shortens the running one.
+func (r *SessionRepo) Start(now time.Time, d time.Duration) (Session, error) {
+	if d <= 0 {
+		return Session{}, errors.New("session duration must be positive")
+	}
+	ends := now.Add(d)
+	cur, ok, err := r.Active(now)
+	if err != nil {
+		return Session{}, err
+	}
+	if ok {
+		if !ends.After(cur.EndsAt) {
+			return cur, nil
+		}
+		if _, err := r.db.Exec(`UPDATE focus_sessions SET ends_at=? WHERE id=?`, sessionTime(ends), cur.ID); err != nil {
+			return Session{}, fmt.Errorf("extend session: %w", err)
+		}
+		cur.EndsAt = ends.UTC().Truncate(time.Second)
+		return cur, nil
+	}
+	res, err := r.db.Exec(`INSERT INTO focus_sessions (started_at, ends_at) VALUES (?,?)`,
+		sessionTime(now), sessionTime(ends))
+	if err != nil {
+		return Session{}, fmt.Errorf("start session: %w", err)
+	}
+	id, _ := res.LastInsertId()
+	return Session{ID: id, StartedAt: now.UTC().Truncate(time.Second), EndsAt: ends.UTC().Truncate(time.Second)}, nil
+}
+
+// Active returns the session in force at now, if any.
+func (r *SessionRepo) Active(now time.Time) (Session, bool, error) {
+	var s Session
+	var started, ends string
+	err := r.db.QueryRow(`SELECT id, started_at, ends_at FROM focus_sessions
+        WHERE started_at <= ? AND ends_at > ? ORDER BY ends_at DESC LIMIT 1`,
+		sessionTime(now), sessionTime(now)).Scan(&s.ID, &started, &ends)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, false, nil
+	}
+	if err != nil {
+		return Session{}, false, fmt.Errorf("active session: %w", err)
+	}
+	if s.StartedAt, err = time.Parse(time.RFC3339, started); err != nil {
+		return Session{}, false, fmt.Errorf("active session: %w", err)
+	}
+	if s.EndsAt, err = time.Parse(time.RFC3339, ends); err != nil {
+		return Session{}, false, fmt.Errorf("active session: %w", err)
+	}
+	return s, true, nil
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionOnlyExtends(t *testing.T) {
+	db := openTest(t)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, ok, err := db.Sessions.Active(now); err != nil || ok {
+		t.Fatalf("fresh DB: active=%v err=%v", ok, err)
+	}
+	s, err := db.Sessions.Start(now, 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.EndsAt.Equal(now.Add(2 * time.Hour)) {
+		t.Fatalf("ends %v", s.EndsAt)
+	}
+	// A shorter start mid-session leaves the end where it was.
+	if s2, err := db.Sessions.Start(now.Add(time.Hour), 30*time.Minute); err != nil || !s2.EndsAt.Equal(s.EndsAt) || s2.ID != s.ID {
+		t.Fatalf("shorter start = %+v, err %v", s2, err)
+	}
+	// A longer one extends the same session.
+	if s3, err := db.Sessions.Start(now.Add(time.Hour), 3*time.Hour); err != nil || !s3.EndsAt.Equal(now.Add(4*time.Hour)) || s3.ID != s.ID {
+		t.Fatalf("longer start = %+v, err %v", s3, err)
+	}
+	if _, ok, _ := db.Sessions.Active(now.Add(4*time.Hour - time.Second)); !ok {
+		t.Fatal("session over a second early")
+	}
+	if _, ok, _ := db.Sessions.Active(now.Add(4 * time.Hour)); ok {
+		t.Fatal("session still active at its end time")
+	}
+	if _, err := db.Sessions.Start(now, 0); err == nil {
+		t.Fatal("zero-length session accepted")
+	}
+}
+
+func TestActiveCalendarBlockFollowsLatestEvent(t *testing.T) {
+	db := openTest(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, ok, err := db.Events.ActiveCalendarBlock(now); err != nil || ok {
+		t.Fatalf("fresh DB: ok=%v err=%v", ok, err)
+	}
+	b := CalendarBlock{Profile: "strict", Entry: "Deep work #focus", EndsAt: now.Add(time.Hour)}
+	if err := db.Events.RecordCalendarProfile(b, true); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := db.Events.ActiveCalendarBlock(now)
+	if err != nil || !ok || got.Entry != b.Entry || !got.EndsAt.Equal(b.EndsAt) {
+		t.Fatalf("on: %+v ok=%v err=%v", got, ok, err)
+	}
+	if _, ok, _ := db.Events.ActiveCalendarBlock(now.Add(time.Hour)); ok {
+		t.Fatal("block still active at its end")
+	}
+	if err := db.Events.RecordCalendarProfile(b, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := db.Events.ActiveCalendarBlock(now); ok {
+		t.Fatal("block active after the off event")
+	}
+}
+
+func TestReducedCadenceFollowsLatestEvent(t *testing.T) {
+	db := openTest(t)
+	if r, err := db.Events.ReducedCadence(); err != nil || r != "" {
+		t.Fatalf("fresh DB: %q err=%v", r, err)
+	}
+	if err := db.Events.RecordCadence("on battery"); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := db.Events.ReducedCadence(); r != "on battery" {
+		t.Fatalf("reduced: %q", r)
+	}
+	if err := db.Events.RecordCadence(""); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := db.Events.ReducedCadence(); r != "" {
+		t.Fatalf("back to normal: %q", r)
+	}
+}
+
+func TestPreviousShutdownFollowsTheLifecycle(t *testing.T) {
+	db := openTest(t)
+	check := func(step string, wantClean, wantKnown bool) {
+		t.Helper()
+		clean, known, err := db.Events.PreviousShutdown()
+		if err != nil || clean != wantClean || known != wantKnown {
+			t.Fatalf("%s: clean=%v known=%v err=%v", step, clean, known, err)
+		}
+	}
+	check("fresh DB", false, false)
+	_ = db.Events.RecordStarted()
+	check("started, never stopped", false, true)
+	_ = db.Events.RecordStopped(true)
+	check("drained stop", true, true)
+	_ = db.Events.RecordStarted()
+	_ = db.Events.RecordStopped(false)
+	check("stop with runs in flight", false, true)
+}
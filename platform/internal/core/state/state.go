@@ -20,11 +20,17 @@ import (
 type DB struct {
 	sql *sql.DB
 
-	Plugins *PluginRepo
-	Jobs    *JobRepo
-	Runs    *JobRunRepo
-	Locks   *JobLockRepo
-	Events  *EventRepo
+	Plugins  *PluginRepo
+	Jobs     *JobRepo
+	Runs     *JobRunRepo
+	Locks    *JobLockRepo
+	Events   *EventRepo
+	Stats    *StatsRepo
+	Sessions *SessionRepo
+	Breaks   *BreakRepo
+	Strict   *StrictLockRepo
+	Pauses   *PauseRepo
+	Clock    *ClockRepo
 }
 
 // Open creates/opens the state DB at path, creating parent dirs and
@@ -78,6 +84,12 @@ func Open(path string) (*DB, error) {
 	db.Runs = &JobRunRepo{db: sqldb}
 	db.Locks = &JobLockRepo{db: sqldb}
 	db.Events = &EventRepo{db: sqldb}
+	db.Stats = &StatsRepo{db: sqldb}
+	db.Sessions = &SessionRepo{db: sqldb}
+	db.Breaks = &BreakRepo{db: sqldb}
+	db.Strict = &StrictLockRepo{db: sqldb}
+	db.Pauses = &PauseRepo{db: sqldb}
+	db.Clock = &ClockRepo{db: sqldb}
 	return db, nil
 }
 
@@ -112,6 +124,12 @@ func OpenReadOnly(path string) (*DB, error) {
 	db.Runs = &JobRunRepo{db: sqldb}
 	db.Locks = &JobLockRepo{db: sqldb}
 	db.Events = &EventRepo{db: sqldb}
+	db.Stats = &StatsRepo{db: sqldb}
+	db.Sessions = &SessionRepo{db: sqldb}
+	db.Breaks = &BreakRepo{db: sqldb}
+	db.Strict = &StrictLockRepo{db: sqldb}
+	db.Pauses = &PauseRepo{db: sqldb}
+	db.Clock = &ClockRepo{db: sqldb}
 	return db, nil
 }
 
@@ -122,6 +122,22 @@ func TestJobRunLifecycle(t *testing.T) {
 	}
 }
 
+func TestRecentSpansJobs(t *testing.T) {
+	db := openTest(t)
+	for _, job := range []string{"job1", "job2", "job1"} {
+		if err := db.Runs.RecordSkipped(job, "kill-steam", "previous run active"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := db.Runs.Recent(2)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("Recent(2) = %d runs, err %v", len(runs), err)
+	}
+	if runs[0].JobID != "job1" || runs[1].JobID != "job2" {
+		t.Fatalf("order = %s, %s; want newest first", runs[0].JobID, runs[1].JobID)
+	}
+}
+
 func TestJobLockNoOverlap(t *testing.T) {
 	db := openTest(t)
 
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StatsRepo maintains the persistent per-day counters behind
+// `platform stats` (table daily_stats).
+type StatsRepo struct{ db *sql.DB }
+
+// DayStats is one job's counters for one local calendar day. As an Add
+// argument the counts are increments.
+type DayStats struct {
+	Day        string // YYYY-MM-DD, local time
+	JobID      string
+	Runs       int // completed runs (ok or failed)
+	Blocks     int // runs that stopped a block attempt
+	Kills      int
+	Removals   int
+	Relaunches int
+	Restores   int // plugin binaries repaired after tampering
+}
+
+// Day is the daily_stats key for t: its local calendar date.
+func Day(t time.Time) string { return t.Local().Format("2006-01-02") }
+
+// Add increments d's counters on its (day, job) row, creating the row on
+// first use.
+func (r *StatsRepo) Add(d DayStats) error {
+	_, err := r.db.Exec(`INSERT INTO daily_stats
+        (day, job_id, runs, blocks, kills, removals, relaunches, restores)
+        VALUES (?,?,?,?,?,?,?,?)
+        ON CONFLICT (day, job_id) DO UPDATE SET
+            runs=runs+excluded.runs, blocks=blocks+excluded.blocks,
+            kills=kills+excluded.kills, removals=removals+excluded.removals,
+            relaunches=relaunches+excluded.relaunches, restores=restores+excluded.restores`,
+		d.Day, d.JobID, d.Runs, d.Blocks, d.Kills, d.Removals, d.Relaunches, d.Restores)
+	if err != nil {
+		return fmt.Errorf("add daily stats: %w", err)
+	}
+	return nil
+}
+
+// Days returns every counter row, oldest day first. The table holds one row
+// per job per day, so even years of history read back small.
+func (r *StatsRepo) Days() ([]DayStats, error) {
+	rows, err := r.db.Query(`SELECT day, job_id, runs, blocks, kills, removals, relaunches, restores
+        FROM daily_stats ORDER BY day, job_id`)
+	if err != nil {
+		return nil, fmt.Errorf("daily stats: %w", err)
+	}
+	defer rows.Close()
+	var out []DayStats
+	for rows.Next() {
+		var d DayStats
+		if err := rows.Scan(&d.Day, &d.JobID, &d.Runs, &d.Blocks, &d.Kills, &d.Removals, &d.Relaunches, &d.Restores); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatsAddAccumulates(t *testing.T) {
+	db := openTest(t)
+	day := Day(time.Now())
+	for _, d := range []DayStats{
+		{Day: day, JobID: "kill", Runs: 1, Blocks: 1, Kills: 2},
+		{Day: day, JobID: "kill", Runs: 1, Removals: 1},
+		{Day: "2026-01-01", JobID: "freedom", Runs: 1, Relaunches: 1, Restores: 1},
+	} {
+		if err := db.Stats.Add(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := db.Stats.Days()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DayStats{
+		{Day: "2026-01-01", JobID: "freedom", Runs: 1, Relaunches: 1, Restores: 1},
+		{Day: day, JobID: "kill", Runs: 2, Blocks: 1, Kills: 2, Removals: 1},
+	}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Days = %+v, want %+v", got, want)
+	}
+}
+
+// TestStatsMigrationBackfillsHistory replays migration 2 over a DB that
+// already holds runs and a tamper event, as an upgrade from v1 would.
+func TestStatsMigrationBackfillsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish := func(job, status, stdout string) {
+		t.Helper()
+		id, err := db.Runs.Start(job, "p", "1", "scheduler")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Runs.Finish(JobRun{ID: id, Status: status, StdoutJSON: stdout}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	finish("kill", RunStatusOK, `{"status":"ok","details":{"killed_count":0}}`)
+	finish("kill", RunStatusOK, `{"status":"ok","details":{"killed_count":2,"bypass":{"killed_pids":[7]}}}`)
+	finish("kill", RunStatusFailed, `not json`)
+	finish("kill", RunStatusError, `{"status":"error"}`) // not a completed run
+	if err := db.Events.RecordTamperRepaired("kill", "p", "aa", "bb"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.sql.Exec(`DROP TABLE daily_stats; DROP TABLE focus_sessions; DROP TABLE break_tokens; DROP TABLE strict_lock; DROP TABLE pauses; DROP TABLE clock_skew; DELETE FROM schema_migrations WHERE version>=2`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("re-open (replay from migration 2): %v", err)
+	}
+	defer db.Close()
+	got, err := db.Stats.Days()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DayStats{Day: Day(time.Now()), JobID: "kill", Runs: 3, Blocks: 1, Kills: 3, Restores: 1}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("backfilled = %+v, want [%+v]", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StrictLockRepo records the strict lock's end (table strict_lock). The
+// daemon owns the lock and its tamper-resistant clock; this is the copy the
+// platform's own CLI can read.
+type StrictLockRepo struct{ db *sql.DB }
+
+// Hold records that the lock holds until at least until. A later end
+// replaces an earlier one, never the reverse.
+func (r *StrictLockRepo) Hold(until time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO strict_lock (id, until) VALUES (1, ?)
+        ON CONFLICT(id) DO UPDATE SET until=excluded.until WHERE excluded.until > strict_lock.until`,
+		sessionTime(until))
+	if err != nil {
+		return fmt.Errorf("hold strict lock: %w", err)
+	}
+	return nil
+}
+
+// Until returns the recorded end, the zero time when never locked.
+func (r *StrictLockRepo) Until() (time.Time, error) {
+	var until string
+	err := r.db.QueryRow(`SELECT until FROM strict_lock WHERE id = 1`).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("strict lock: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("strict lock: %w", err)
+	}
+	return t, nil
+}
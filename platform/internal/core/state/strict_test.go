@@ -0,0 +1,29 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStrictLockOnlyLengthens(t *testing.T) {
+	db := openTest(t)
+	if until, err := db.Strict.Until(); err != nil || !until.IsZero() {
+		t.Fatalf("fresh DB: until=%v err=%v", until, err)
+	}
+	end := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	if err := db.Strict.Hold(end); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Strict.Hold(end.Add(-48 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if until, _ := db.Strict.Until(); !until.Equal(end) {
+		t.Fatalf("earlier end replaced the lock: %v", until)
+	}
+	if err := db.Strict.Hold(end.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if until, _ := db.Strict.Until(); !until.Equal(end.Add(time.Hour)) {
+		t.Fatalf("later end not recorded: %v", until)
+	}
+}
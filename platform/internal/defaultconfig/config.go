@@ -15,7 +15,9 @@
 package defaultconfig
 
 import (
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/eliteGoblin/focusd/platform/internal/core/config"
@@ -28,6 +30,14 @@ var raw []byte
 // inspection callers.
 func Bytes() []byte { return raw }
 
+// Digest is the hex SHA-256 of the embedded default: the pin of the active
+// policy set. It changes only with a signed release, so a reported digest
+// that differs from a release's is a different binary, not an edited file.
+func Digest() string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
 // Load parses and returns the embedded default Config. This is the only
 // policy source on the daemon-managed run path — there is no override to
 // merge. A malformed embedded default is a build defect, so a parse
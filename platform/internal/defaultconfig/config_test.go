@@ -1,6 +1,8 @@
 package defaultconfig
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"reflect"
 	"testing"
 
@@ -92,3 +94,52 @@ func findJob(jobs []config.Job, id string) *config.Job {
 	}
 	return nil
 }
+
+// A session must add to dns-block's list, never replace it: the overlay key
+// is extra_hosts, and it must not carry a "hosts" override.
+func TestSessionOverlayIsAdditive(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := cfg.Session.Jobs["dns-block-reconcile"]
+	if hosts, _ := extra["extra_hosts"].([]any); len(hosts) == 0 {
+		t.Fatal("session adds no extra_hosts to dns-block")
+	}
+	for id, keys := range cfg.Session.Jobs {
+		if _, ok := keys["hosts"]; ok {
+			t.Errorf("session overlay for %q replaces the base list via hosts", id)
+		}
+	}
+}
+
+// Break tokens and pauses must never reach the core protections: a weak
+// moment could otherwise spend them on exactly the layer the install exists
+// for.
+func TestBreaksSpareCoreProtections(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, core := range []string{"kill-steam-reconcile", "skill-protector-reconcile", "dns-block-reconcile", "network-block-reconcile"} {
+		if cfg.Breaks.Breakable(core) {
+			t.Errorf("%s is breakable in the signed default", core)
+		}
+		if cfg.Pause.Pausable(core) {
+			t.Errorf("%s is pausable in the signed default", core)
+		}
+	}
+}
+
+// wantDigest is the SHA-256 of config.yaml as released. An edit to the
+// embedded policy fails TestDigestPinsEmbeddedBytes until this is updated
+// with it, so a policy change is always a reviewed one.
+const wantDigest = "80df7d71a9023e41823266a36ce44ff548102e7a23ce7fd1a06ba8f06a6fa9e9"
+
+// Digest pins exactly the embedded bytes the run path parses.
+func TestDigestPinsEmbeddedBytes(t *testing.T) {
+	sum := sha256.Sum256(Bytes())
+	if d := Digest(); d != wantDigest || hex.EncodeToString(sum[:]) != wantDigest {
+		t.Fatalf("Digest = %q, want %q (update wantDigest with a reviewed config.yaml change)", d, wantDigest)
+	}
+}
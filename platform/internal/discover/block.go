@@ -0,0 +1,88 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+)
+
+// Lookup finds what `platform block NAME` means by name: a catalog entry
+// (by its name, job-id slug, bundle name or cask token, any case), or
+// failing that an app bundle installed under root, for which it builds a
+// minimal entry matching the bundle's executable. Sources is empty for a
+// catalog app that is not installed. ok is false when name is neither.
+func Lookup(root string, cfg *config.Config, name string) (Found, bool) {
+	want := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), ".app"))
+	if want == "" {
+		return Found{}, false
+	}
+	enabled := enabledJobs(cfg)
+	for _, e := range Catalog {
+		if strings.ToLower(e.Name) == want || slug(e.Name) == want ||
+			anyIn(e.Apps, map[string]bool{want: true}) || anyIn(e.Casks, map[string]bool{want: true}) {
+			var src []string
+			for _, f := range Scan(root, cfg) {
+				if f.Name == e.Name {
+					src = f.Sources
+				}
+			}
+			return judge(e, src, enabled), true
+		}
+	}
+	for _, dir := range appDirs(root) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, de := range entries {
+			base, isApp := strings.CutSuffix(de.Name(), ".app")
+			if !isApp || !de.IsDir() || strings.ToLower(base) != want {
+				continue
+			}
+			e := Entry{Name: base, Apps: []string{base}, Processes: []string{bundleExecutable(filepath.Join(dir, de.Name()), base)}}
+			return judge(e, []string{SourceApplications}, enabled), true
+		}
+	}
+	return Found{}, false
+}
+
+// Job is the job f would add — Suggest for its entry — or false when f is
+// already covered or has no process to match.
+func (f Found) Job() (config.Job, bool) {
+	if f.Suggested == "" {
+		return config.Job{}, false
+	}
+	return Suggest(f.entry), true
+}
+
+// appDirs are the folders app bundles are installed in: /Applications and
+// every user's ~/Applications.
+func appDirs(root string) []string {
+	dirs := []string{filepath.Join(root, "Applications")}
+	for _, h := range userHomes(root) {
+		dirs = append(dirs, filepath.Join(h, "Applications"))
+	}
+	return dirs
+}
+
+var plistExecutable = regexp.MustCompile(`<key>CFBundleExecutable</key>\s*<string>([^<]+)</string>`)
+
+// bundleExecutable is the process name a bundle runs as: CFBundleExecutable
+// from an XML Info.plist, else the one file in Contents/MacOS, else the
+// bundle name (what the process is called for most apps).
+func bundleExecutable(bundle, fallback string) string {
+	if b, err := os.ReadFile(filepath.Join(bundle, "Contents", "Info.plist")); err == nil {
+		if m := plistExecutable.FindSubmatch(b); m != nil {
+			if exe := strings.TrimSpace(string(m[1])); exe != "" {
+				return exe
+			}
+		}
+	}
+	if entries, err := os.ReadDir(filepath.Join(bundle, "Contents", "MacOS")); err == nil && len(entries) == 1 {
+		return entries[0].Name()
+	}
+	return fallback
+}
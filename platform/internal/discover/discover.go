@@ -0,0 +1,273 @@
+// Package discover implements `platform discover`: it looks for installed
+// apps the catalog knows as distractions — app bundles, Homebrew casks,
+// Steam library folders and login items — and reports, for each one found,
+// whether a job of the enforced policy already covers it or what job would.
+//
+// It only reads. A suggestion becomes policy the same way any change does:
+// written into a draft (DraftWith), checked with `platform policies import`,
+// and shipped in a signed release. Output names apps, never paths.
+package discover
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is one catalog app and the traces it leaves on a Mac.
+type Entry struct {
+	Name string
+	// Apps are bundle names without ".app", in /Applications or
+	// ~/Applications.
+	Apps []string
+	// Casks are Homebrew cask tokens.
+	Casks []string
+	// SteamDirs are folder names under a Steam library's steamapps/common.
+	SteamDirs []string
+	// Agents are launch-agent label prefixes (login items).
+	Agents []string
+	// Processes are the names a kill job matches; empty means the app has
+	// no process of its own (a Steam game runs under Steam).
+	Processes []string
+	// Job is the signed-default job that handles the app, if any.
+	Job string
+}
+
+// Catalog is the built-in list. Matching is case-insensitive.
+var Catalog = []Entry{
+	{Name: "Steam", Apps: []string{"Steam"}, Casks: []string{"steam"},
+		Agents: []string{"com.valvesoftware."}, Job: "kill-steam-reconcile"},
+	{Name: "Dota 2", SteamDirs: []string{"dota 2 beta"}, Job: "kill-steam-reconcile"},
+	{Name: "Counter-Strike 2", SteamDirs: []string{"Counter-Strike Global Offensive"}, Job: "kill-steam-reconcile"},
+	{Name: "Epic Games Launcher", Apps: []string{"Epic Games Launcher"}, Casks: []string{"epic-games"},
+		Agents: []string{"com.epicgames."}, Processes: []string{"EpicGamesLauncher", "Epic Games Launcher"}},
+	{Name: "Battle.net", Apps: []string{"Battle.net"}, Casks: []string{"battle-net"},
+		Agents: []string{"com.blizzard."}, Processes: []string{"Battle.net"}},
+	{Name: "League of Legends", Apps: []string{"League of Legends"}, Casks: []string{"league-of-legends"},
+		Agents: []string{"com.riotgames."}, Processes: []string{"LeagueClient", "League of Legends", "RiotClientServices"}},
+	{Name: "Minecraft", Apps: []string{"Minecraft"}, Casks: []string{"minecraft"}, Processes: []string{"Minecraft"}},
+	{Name: "Roblox", Apps: []string{"Roblox", "RobloxPlayer"}, Casks: []string{"roblox"},
+		Agents: []string{"com.roblox."}, Processes: []string{"RobloxPlayer", "Roblox"}},
+	{Name: "GOG Galaxy", Apps: []string{"GOG Galaxy"}, Casks: []string{"gog-galaxy"}, Processes: []string{"GOG Galaxy"}},
+	{Name: "Discord", Apps: []string{"Discord"}, Casks: []string{"discord"},
+		Agents: []string{"com.hnc.Discord"}, Processes: []string{"Discord"}},
+}
+
+// Source names where an app was found.
+const (
+	SourceApplications = "applications"
+	SourceHomebrew     = "homebrew"
+	SourceSteam        = "steam library"
+	SourceLoginItem    = "login item"
+)
+
+// Found is one catalog app present on this machine.
+type Found struct {
+	Name    string   `json:"name"`
+	Sources []string `json:"sources"`
+	// CoveredBy is the enabled job that already handles the app.
+	CoveredBy string `json:"covered_by,omitempty"`
+	// Suggested is the id of the job that would, for an uncovered app with
+	// processes to match (see Suggest).
+	Suggested string `json:"suggested_job,omitempty"`
+
+	entry Entry
+}
+
+// Scan looks under root (/ in production) for every catalog app and marks
+// the ones an enabled job of cfg covers. Unreadable locations are skipped.
+func Scan(root string, cfg *config.Config) []Found {
+	appDirs := []string{filepath.Join(root, "Applications")}
+	caskDirs := []string{filepath.Join(root, "opt", "homebrew", "Caskroom"), filepath.Join(root, "usr", "local", "Caskroom")}
+	agentDirs := []string{filepath.Join(root, "Library", "LaunchAgents")}
+	var steamDirs []string
+	for _, h := range userHomes(root) {
+		appDirs = append(appDirs, filepath.Join(h, "Applications"))
+		steamDirs = append(steamDirs, filepath.Join(h, "Library", "Application Support", "Steam", "steamapps", "common"))
+		agentDirs = append(agentDirs, filepath.Join(h, "Library", "LaunchAgents"))
+	}
+	apps := names(appDirs, ".app")
+	casks := names(caskDirs, "")
+	games := names(steamDirs, "")
+	agents := names(agentDirs, ".plist")
+
+	enabled := enabledJobs(cfg)
+	var out []Found
+	for _, e := range Catalog {
+		var src []string
+		if anyIn(e.Apps, apps) {
+			src = append(src, SourceApplications)
+		}
+		if anyIn(e.Casks, casks) {
+			src = append(src, SourceHomebrew)
+		}
+		if anyIn(e.SteamDirs, games) {
+			src = append(src, SourceSteam)
+		}
+		if anyPrefix(e.Agents, agents) {
+			src = append(src, SourceLoginItem)
+		}
+		if len(src) == 0 {
+			continue
+		}
+		out = append(out, judge(e, src, enabled))
+	}
+	return out
+}
+
+func enabledJobs(cfg *config.Config) map[string]bool {
+	enabled := map[string]bool{}
+	for _, j := range cfg.Jobs {
+		enabled[j.ID] = j.Enabled
+	}
+	return enabled
+}
+
+// judge is e found at src: covered by its enabled job, or given a
+// suggested one when it has processes to match.
+func judge(e Entry, src []string, enabled map[string]bool) Found {
+	f := Found{Name: e.Name, Sources: src, entry: e}
+	switch {
+	case e.Job != "" && enabled[e.Job]:
+		f.CoveredBy = e.Job
+	case len(e.Processes) > 0:
+		f.Suggested = Suggest(e).ID
+	}
+	return f
+}
+
+// Suggest is the job that would cover e: kill-steam matching e's
+// processes, killing on sight and never deleting, since the uninstall
+// sweep only knows Steam's files.
+func Suggest(e Entry) config.Job {
+	return config.Job{
+		ID:       "kill-" + slug(e.Name),
+		Plugin:   "kill-steam",
+		Enabled:  true,
+		Schedule: "@every 10s",
+		Timeout:  config.Duration(20 * time.Second),
+		Config: map[string]any{
+			"process_names": slices.Clone(e.Processes),
+			"severity":      "medium",
+			"actions":       []any{"kill"},
+		},
+	}
+}
+
+// DraftWith returns base (a config.yaml) with the suggested jobs for picks
+// inserted at the top of its jobs list, comments kept. The result is a
+// draft for `platform policies import`, never policy by itself.
+func DraftWith(base []byte, picks []Found) ([]byte, error) {
+	jobs := make([]config.Job, 0, len(picks))
+	for _, f := range picks {
+		if f.Suggested != "" {
+			jobs = append(jobs, Suggest(f.entry))
+		}
+	}
+	if len(jobs) == 0 {
+		return base, nil
+	}
+	y, err := yaml.Marshal(jobs)
+	if err != nil {
+		return nil, err
+	}
+	var block strings.Builder
+	for _, line := range strings.SplitAfter(string(y), "\n") {
+		if line != "" {
+			block.WriteString("  " + line)
+		}
+	}
+	s := string(base)
+	at := strings.Index(s, "\njobs:\n")
+	if at < 0 {
+		return nil, errors.New("base config has no top-level jobs list")
+	}
+	at += len("\njobs:\n")
+	return []byte(s[:at] + block.String() + s[at:]), nil
+}
+
+// userHomes lists root/Users/* the way the uninstaller does: real
+// directories, no dotted ones, no Shared.
+func userHomes(root string) []string {
+	entries, err := os.ReadDir(filepath.Join(root, "Users"))
+	if err != nil {
+		return nil
+	}
+	var homes []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") && e.Name() != "Shared" {
+			homes = append(homes, filepath.Join(root, "Users", e.Name()))
+		}
+	}
+	return homes
+}
+
+// names lists the lower-cased entry names in dirs that end in suffix,
+// with the suffix cut. Only directories count as ".app" bundles.
+func names(dirs []string, suffix string) map[string]bool {
+	out := map[string]bool{}
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			n := strings.ToLower(e.Name())
+			if suffix == ".app" && !e.IsDir() {
+				continue // a bundle is a directory; a file there is kill-steam's tombstone
+			}
+			if suffix != "" {
+				var ok bool
+				if n, ok = strings.CutSuffix(n, suffix); !ok {
+					continue
+				}
+			}
+			out[n] = true
+		}
+	}
+	return out
+}
+
+func anyIn(want []string, have map[string]bool) bool {
+	for _, w := range want {
+		if have[strings.ToLower(w)] {
+			return true
+		}
+	}
+	return false
+}
+
+func anyPrefix(prefixes []string, have map[string]bool) bool {
+	for _, p := range prefixes {
+		p = strings.ToLower(p)
+		for h := range have {
+			if strings.HasPrefix(h, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// slug turns "Epic Games Launcher" into "epic-games-launcher".
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
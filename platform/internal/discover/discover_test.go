@@ -0,0 +1,142 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/config"
+	"github.com/eliteGoblin/focusd/platform/internal/defaultconfig"
+)
+
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanFindsCatalogAppsAndCoverage(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root,
+		"Applications/Steam.app",
+		"Applications/Safari.app",
+		"opt/homebrew/Caskroom/epic-games",
+		"Users/alice/Library/Application Support/Steam/steamapps/common/dota 2 beta",
+		"Users/alice/Applications/Minecraft.app",
+	)
+	mkdirs(t, root, "Users/alice/Library/LaunchAgents")
+	if err := os.WriteFile(filepath.Join(root, "Users/alice/Library/LaunchAgents/com.epicgames.launcher.plist"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]Found{}
+	for _, f := range Scan(root, cfg) {
+		got[f.Name] = f
+	}
+	if len(got) != 4 {
+		t.Fatalf("found %v", got)
+	}
+	if got["Steam"].CoveredBy != "kill-steam-reconcile" || got["Dota 2"].CoveredBy != "kill-steam-reconcile" {
+		t.Errorf("Steam/Dota coverage = %+v / %+v", got["Steam"], got["Dota 2"])
+	}
+	if e := got["Epic Games Launcher"]; e.Suggested != "kill-epic-games-launcher" || len(e.Sources) != 2 {
+		t.Errorf("epic = %+v", e)
+	}
+	if got["Minecraft"].Suggested != "kill-minecraft" {
+		t.Errorf("minecraft = %+v", got["Minecraft"])
+	}
+}
+
+func TestDraftWithIsAValidDraft(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "Applications/Roblox.app", "Applications/Steam.app")
+	if err := os.WriteFile(filepath.Join(root, "Applications/Minecraft.app"), []byte("reserved\n"), 0o444); err != nil {
+		t.Fatal(err) // a tombstone, not an install
+	}
+	base := defaultconfig.Bytes()
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft, err := DraftWith(base, Scan(root, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := config.Parse(draft)
+	if err != nil {
+		t.Fatalf("draft does not parse: %v\n%s", err, draft)
+	}
+	d := config.DiffJobs(cfg, parsed)
+	if len(d.Added) != 1 || d.Added[0] != "kill-roblox" || len(d.Changed)+len(d.Removed) != 0 {
+		t.Fatalf("diff = %+v", d)
+	}
+	if same, _ := DraftWith(base, nil); string(same) != string(base) {
+		t.Fatal("no picks must leave the base untouched")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root,
+		"Applications/Steam.app",
+		"Users/alice/Applications/Hades.app/Contents/MacOS",
+		"Applications/Factorio.app/Contents",
+	)
+	if err := os.WriteFile(filepath.Join(root, "Users/alice/Applications/Hades.app/Contents/MacOS/Hades-mac"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plist := "<plist><dict>\n<key>CFBundleExecutable</key>\n\t<string>factorio</string>\n</dict></plist>"
+	if err := os.WriteFile(filepath.Join(root, "Applications/Factorio.app/Contents/Info.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := defaultconfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f, ok := Lookup(root, cfg, "steam"); !ok || f.CoveredBy != "kill-steam-reconcile" || len(f.Sources) != 1 {
+		t.Errorf("steam = %+v, %v", f, ok)
+	}
+	// A catalog app by cask token, not installed: still a job to draft.
+	f, ok := Lookup(root, cfg, "epic-games")
+	if !ok || f.Name != "Epic Games Launcher" || len(f.Sources) != 0 {
+		t.Fatalf("epic = %+v, %v", f, ok)
+	}
+	if j, ok := f.Job(); !ok || j.ID != "kill-epic-games-launcher" {
+		t.Errorf("epic job = %+v, %v", j, ok)
+	}
+	// Installed bundles outside the catalog: the executable from Info.plist,
+	// else the one file in Contents/MacOS.
+	for name, proc := range map[string]string{"Factorio": "factorio", "hades.app": "Hades-mac"} {
+		f, ok := Lookup(root, cfg, name)
+		j, jok := f.Job()
+		if !ok || !jok || f.Sources[0] != SourceApplications {
+			t.Fatalf("%s = %+v, %v", name, f, ok)
+		}
+		if got := j.Config["process_names"].([]string); len(got) != 1 || got[0] != proc {
+			t.Errorf("%s processes = %v, want [%s]", name, got, proc)
+		}
+	}
+	if _, ok := Lookup(root, cfg, "Safari"); ok {
+		t.Error("an app that is neither catalogued nor installed was found")
+	}
+
+	draft, err := DraftWith(defaultconfig.Bytes(), []Found{f})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := config.Parse(draft)
+	if err != nil {
+		t.Fatalf("draft does not parse: %v", err)
+	}
+	if d := config.DiffJobs(cfg, parsed); len(d.Added) != 1 || d.Added[0] != "kill-epic-games-launcher" {
+		t.Fatalf("diff = %+v", d)
+	}
+}
@@ -0,0 +1,140 @@
+// Package metrics implements `platform metrics`: the platform's run history
+// rendered as Prometheus text exposition, for node_exporter's textfile
+// collector (or any scraper that can read a file or a command's stdout).
+//
+// There is deliberately no listener. A localhost /metrics port would be one
+// more thing `lsof -i` shows and one more surface to hold open; a textfile
+// written on demand (cron/launchd) carries the same numbers. Like `platform
+// status`, it is read-only and path-free: labels are job ids, run statuses
+// and event types only.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+// Source is the slice of the state DB the collector reads; a seam so
+// rendering is tested without a DB.
+type Source interface {
+	CountByStatus() ([]state.RunCount, error)
+	LastRuns() ([]state.LastRun, error)
+	EnforcementTotals() ([]state.Enforcement, error)
+}
+
+// EventSource counts platform events by type.
+type EventSource interface {
+	CountByType() (map[string]int64, error)
+}
+
+// Sample is one collection of every exported series.
+type Sample struct {
+	At          time.Time
+	Runs        []state.RunCount
+	Last        []state.LastRun
+	Enforcement []state.Enforcement
+	Events      map[string]int64
+}
+
+// Collect reads a Sample. Any read error fails the whole collection: a
+// partial file would read to a scraper as counters dropping to zero.
+func Collect(runs Source, events EventSource, now time.Time) (Sample, error) {
+	s := Sample{At: now}
+	var err error
+	if s.Runs, err = runs.CountByStatus(); err != nil {
+		return Sample{}, err
+	}
+	if s.Last, err = runs.LastRuns(); err != nil {
+		return Sample{}, err
+	}
+	if s.Enforcement, err = runs.EnforcementTotals(); err != nil {
+		return Sample{}, err
+	}
+	if s.Events, err = events.CountByType(); err != nil {
+		return Sample{}, err
+	}
+	return s, nil
+}
+
+// Write renders s in the Prometheus text format (version 0.0.4).
+func Write(w io.Writer, s Sample) error {
+	var b strings.Builder
+	family := func(name, typ, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+	sample := func(name string, v float64, labels ...string) {
+		b.WriteString(name)
+		if len(labels) > 0 {
+			b.WriteByte('{')
+			for i := 0; i < len(labels); i += 2 {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				fmt.Fprintf(&b, `%s="%s"`, labels[i], escape(labels[i+1]))
+			}
+			b.WriteByte('}')
+		}
+		fmt.Fprintf(&b, " %g\n", v)
+	}
+
+	family("focusd_job_runs_total", "counter", "Finished job runs by terminal status.")
+	for _, c := range s.Runs {
+		sample("focusd_job_runs_total", float64(c.N), "job", c.JobID, "status", c.Status)
+	}
+
+	family("focusd_job_last_run_age_seconds", "gauge", "Seconds since the job's newest finished run started.")
+	for _, l := range s.Last {
+		age := s.At.Sub(l.StartedAt).Seconds()
+		if age < 0 {
+			age = 0
+		}
+		sample("focusd_job_last_run_age_seconds", age, "job", l.JobID)
+	}
+	family("focusd_job_last_duration_seconds", "gauge", "Duration of the job's newest finished run.")
+	for _, l := range s.Last {
+		sample("focusd_job_last_duration_seconds", float64(l.DurationMS)/1000, "job", l.JobID)
+	}
+
+	for _, m := range []struct {
+		name, help string
+		val        func(state.Enforcement) int64
+	}{
+		{"focusd_enforcement_kills_total", "Processes terminated, as reported by plugins.",
+			func(e state.Enforcement) int64 { return e.Kills }},
+		{"focusd_enforcement_removals_total", "Paths removed, as reported by plugins.",
+			func(e state.Enforcement) int64 { return e.Removals }},
+		{"focusd_enforcement_relaunches_total", "Protected apps relaunched, as reported by plugins.",
+			func(e state.Enforcement) int64 { return e.Relaunches }},
+	} {
+		family(m.name, "counter", m.help)
+		for _, e := range s.Enforcement {
+			sample(m.name, float64(m.val(e)), "job", e.JobID)
+		}
+	}
+
+	family("focusd_events_total", "counter", "Platform events by type (e.g. plugin tamper repairs).")
+	types := make([]string, 0, len(s.Events))
+	for t := range s.Events {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		sample("focusd_events_total", float64(s.Events[t]), "type", t)
+	}
+
+	family("focusd_metrics_collected_timestamp_seconds", "gauge", "When this sample was collected.")
+	sample("focusd_metrics_collected_timestamp_seconds", float64(s.At.Unix()))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escape applies the exposition format's label-value escaping.
+func escape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+type fakeSource struct {
+	err error
+}
+
+func (f fakeSource) CountByStatus() ([]state.RunCount, error) {
+	return []state.RunCount{{JobID: "kill-steam-reconcile", Status: "ok", N: 12}}, f.err
+}
+
+func (f fakeSource) LastRuns() ([]state.LastRun, error) {
+	return []state.LastRun{{JobID: "kill-steam-reconcile",
+		StartedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), DurationMS: 1500}}, nil
+}
+
+func (f fakeSource) EnforcementTotals() ([]state.Enforcement, error) {
+	return []state.Enforcement{{JobID: "kill-steam-reconcile", Kills: 3, Removals: 1}}, nil
+}
+
+func (f fakeSource) CountByType() (map[string]int64, error) {
+	return map[string]int64{state.EventTamperRepaired: 2, `we"ird`: 1}, nil
+}
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 42, 0, time.UTC)
+	s, err := Collect(fakeSource{}, fakeSource{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Write(&b, s); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE focusd_job_runs_total counter\n",
+		`focusd_job_runs_total{job="kill-steam-reconcile",status="ok"} 12` + "\n",
+		`focusd_job_last_run_age_seconds{job="kill-steam-reconcile"} 42` + "\n",
+		`focusd_job_last_duration_seconds{job="kill-steam-reconcile"} 1.5` + "\n",
+		`focusd_enforcement_kills_total{job="kill-steam-reconcile"} 3` + "\n",
+		`focusd_enforcement_removals_total{job="kill-steam-reconcile"} 1` + "\n",
+		`focusd_events_total{type="plugin_tamper_repaired"} 2` + "\n",
+		`focusd_events_total{type="we\"ird"} 1` + "\n",
+		"focusd_metrics_collected_timestamp_seconds 1.767225642e+09\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "/") {
+		t.Error("metrics output must carry no path")
+	}
+}
+
+func TestCollectFailsWhole(t *testing.T) {
+	if _, err := Collect(fakeSource{err: errors.New("locked")}, fakeSource{}, time.Now()); err == nil {
+		t.Fatal("a failed read must fail the collection")
+	}
+}
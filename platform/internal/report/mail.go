@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mailer sends a rendered report through an SMTP relay. With a User set it
+// authenticates with PLAIN, which net/smtp only permits over TLS (STARTTLS)
+// or to localhost — a password is never sent in the clear.
+type Mailer struct {
+	Addr     string // relay host:port
+	From     string
+	To       []string
+	User     string
+	Password string
+
+	// send is the net/smtp.SendMail seam.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Validate checks the relay settings without contacting it.
+func (m *Mailer) Validate() error {
+	if _, _, err := net.SplitHostPort(m.Addr); err != nil {
+		return errors.New("smtp relay must be host:port")
+	}
+	if !strings.Contains(m.From, "@") || len(m.To) == 0 {
+		return errors.New("mail needs a from address and at least one recipient")
+	}
+	for _, to := range m.To {
+		if !strings.Contains(to, "@") || strings.ContainsAny(to, "\r\n") {
+			return fmt.Errorf("invalid recipient %q", to)
+		}
+	}
+	if strings.ContainsAny(m.From, "\r\n") {
+		return errors.New("invalid from address")
+	}
+	return nil
+}
+
+// Send mails r as a plain-text message.
+func (m *Mailer) Send(r Report) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	RenderText(r, &body)
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&msg, "Subject: focusd report %s\r\n", r.To.Local().Format(time.DateOnly))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if m.User != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.User, m.Password, host)
+	}
+	send := m.send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(m.Addr, auth, m.From, m.To, msg.Bytes())
+}
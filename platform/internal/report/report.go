@@ -0,0 +1,167 @@
+// Package report implements `platform report`: a summary of one window
+// (by default the last seven days) of enforcement, built from the run
+// history the platform already persists — blocked launches per app, paths
+// deleted and disk reclaimed, relaunches, an uptime proxy, and incidents.
+//
+// Like status, it is path-free by construction: removed paths are counted,
+// never listed; jobs and apps appear by id/label only. Rendering (text,
+// JSON) and mailing are separate from collection so each is tested alone.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/eventlog"
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+// Source is the slice of the state DB a report reads.
+type Source interface {
+	CountByStatusBetween(from, to time.Time) ([]state.RunCount, error)
+	ActiveHours(from, to time.Time) (int, error)
+	EnforcementResults(from, to time.Time) ([]state.RunResult, error)
+}
+
+// EventSource counts platform events in the window.
+type EventSource interface {
+	CountByTypeBetween(from, to time.Time) (map[string]int64, error)
+}
+
+// Report is one window's summary. Its JSON form is the --json output.
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// BlockAttempts counts, per app, the runs that stopped it — one launch
+	// killing several helper processes is one attempt.
+	BlockAttempts  map[string]int `json:"block_attempts"`
+	BypassKills    int            `json:"bypass_kills"`
+	PathsDeleted   int            `json:"paths_deleted"`
+	ReclaimedBytes int64          `json:"reclaimed_bytes"`
+	ReclaimedFiles int            `json:"reclaimed_files"`
+	Relaunches     int            `json:"relaunches"`
+	// ActiveHours of WindowHours saw a completed run: the uptime proxy.
+	ActiveHours int `json:"active_hours"`
+	WindowHours int `json:"window_hours"`
+	// FailedRuns counts failed/error/timed-out runs per job; Events counts
+	// platform events (tamper repairs, integrity failures) per type.
+	FailedRuns map[string]int   `json:"failed_runs"`
+	Events     map[string]int64 `json:"events"`
+}
+
+// Build reads the window [from, to) into a Report.
+func Build(runs Source, events EventSource, from, to time.Time) (Report, error) {
+	r := Report{
+		From: from, To: to,
+		BlockAttempts: map[string]int{},
+		FailedRuns:    map[string]int{},
+		WindowHours:   int(to.Sub(from).Round(time.Hour) / time.Hour),
+	}
+	results, err := runs.EnforcementResults(from, to)
+	if err != nil {
+		return Report{}, err
+	}
+	for _, res := range results {
+		a := eventlog.ParseActions(res.StdoutJSON)
+		for _, app := range a.BlockedApps {
+			r.BlockAttempts[app]++
+		}
+		if len(a.BlockedApps) == 0 && a.Kills > 0 {
+			// A plugin predating blocked_apps: count the attempt under its job.
+			r.BlockAttempts[res.JobID]++
+		}
+		r.BypassKills += a.BypassKills
+		r.PathsDeleted += a.Removals
+		r.ReclaimedBytes += a.ReclaimedBytes
+		r.ReclaimedFiles += a.ReclaimedFiles
+		r.Relaunches += a.Relaunches
+	}
+	counts, err := runs.CountByStatusBetween(from, to)
+	if err != nil {
+		return Report{}, err
+	}
+	for _, c := range counts {
+		switch c.Status {
+		case state.RunStatusFailed, state.RunStatusError, state.RunStatusTimedOut:
+			r.FailedRuns[c.JobID] += int(c.N)
+		}
+	}
+	if r.ActiveHours, err = runs.ActiveHours(from, to); err != nil {
+		return Report{}, err
+	}
+	if r.Events, err = events.CountByTypeBetween(from, to); err != nil {
+		return Report{}, err
+	}
+	return r, nil
+}
+
+// Uptime is ActiveHours as a percentage of the window.
+func (r Report) Uptime() float64 {
+	if r.WindowHours <= 0 {
+		return 0
+	}
+	return 100 * float64(r.ActiveHours) / float64(r.WindowHours)
+}
+
+// RenderJSON writes the report as indented JSON.
+func RenderJSON(r Report, out io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// RenderText writes the human-readable report.
+func RenderText(r Report, out io.Writer) {
+	fmt.Fprintf(out, "focusd report  %s → %s\n\n",
+		r.From.Local().Format(time.DateOnly), r.To.Local().Format(time.DateOnly))
+	fmt.Fprintln(out, "  Blocked launches")
+	if len(r.BlockAttempts) == 0 {
+		fmt.Fprintf(out, "    %-24s %s\n", "none", "")
+	}
+	for _, app := range sortedKeys(r.BlockAttempts) {
+		fmt.Fprintf(out, "    %-24s %d\n", app, r.BlockAttempts[app])
+	}
+	fmt.Fprintf(out, "  %-26s %d\n", "Bypass tools killed", r.BypassKills)
+	fmt.Fprintf(out, "  %-26s %d (%s reclaimed, %d files)\n", "Paths deleted", r.PathsDeleted, humanBytes(r.ReclaimedBytes), r.ReclaimedFiles)
+	fmt.Fprintf(out, "  %-26s %d\n", "Apps relaunched", r.Relaunches)
+	fmt.Fprintf(out, "  %-26s %.0f%% (%d/%d h with a completed run)\n", "Protection uptime", r.Uptime(), r.ActiveHours, r.WindowHours)
+	fmt.Fprintln(out, "  Incidents")
+	if len(r.FailedRuns) == 0 && len(r.Events) == 0 {
+		fmt.Fprintf(out, "    %-24s\n", "none")
+	}
+	for _, job := range sortedKeys(r.FailedRuns) {
+		fmt.Fprintf(out, "    %-24s %d failed run(s)\n", job, r.FailedRuns[job])
+	}
+	for _, typ := range sortedKeys(r.Events) {
+		fmt.Fprintf(out, "    %-24s %d\n", typ, r.Events[typ])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// humanBytes formats n in binary units (1.4 GiB).
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+type fakeSource struct{}
+
+func (fakeSource) CountByStatusBetween(time.Time, time.Time) ([]state.RunCount, error) {
+	return []state.RunCount{
+		{JobID: "kill-steam-reconcile", Status: state.RunStatusOK, N: 900},
+		{JobID: "kill-steam-reconcile", Status: state.RunStatusFailed, N: 2},
+		{JobID: "dns-block-reconcile", Status: state.RunStatusTimedOut, N: 1},
+	}, nil
+}
+
+func (fakeSource) ActiveHours(time.Time, time.Time) (int, error) { return 84, nil }
+
+func (fakeSource) EnforcementResults(time.Time, time.Time) ([]state.RunResult, error) {
+	return []state.RunResult{
+		{JobID: "kill-steam-reconcile", StdoutJSON: `{"details":{"killed_count":5,"blocked_apps":["Steam","Dota 2"]}}`},
+		{JobID: "kill-steam-reconcile", StdoutJSON: `{"details":{"killed_count":1,"blocked_apps":["Dota 2"],"bypass":{"killed_pids":[9]}}}`},
+		{JobID: "kill-steam-reconcile", StdoutJSON: `{"details":{"uninstall_removed":["/a","/b"],"uninstall_reclaimed_bytes":1610612736,"uninstall_reclaimed_files":3200}}`},
+		{JobID: "legacy-killer", StdoutJSON: `{"details":{"killed_count":2}}`},
+		{JobID: "freedom-protector-reconcile", StdoutJSON: `{"details":{"relaunched":["app"]}}`},
+		{JobID: "x", StdoutJSON: `not json`},
+	}, nil
+}
+
+func (fakeSource) CountByTypeBetween(time.Time, time.Time) (map[string]int64, error) {
+	return map[string]int64{state.EventTamperRepaired: 1}, nil
+}
+
+func build(t *testing.T) Report {
+	t.Helper()
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	r, err := Build(fakeSource{}, fakeSource{}, to.AddDate(0, 0, -7), to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestBuild(t *testing.T) {
+	r := build(t)
+	if r.BlockAttempts["Dota 2"] != 2 || r.BlockAttempts["Steam"] != 1 || r.BlockAttempts["legacy-killer"] != 1 {
+		t.Errorf("block attempts = %v", r.BlockAttempts)
+	}
+	if r.BypassKills != 1 || r.PathsDeleted != 2 || r.ReclaimedBytes != 1610612736 || r.ReclaimedFiles != 3200 || r.Relaunches != 1 {
+		t.Errorf("totals = %+v", r)
+	}
+	if r.WindowHours != 168 || r.Uptime() != 50 {
+		t.Errorf("window %d h, uptime %.1f%%", r.WindowHours, r.Uptime())
+	}
+	if r.FailedRuns["kill-steam-reconcile"] != 2 || r.FailedRuns["dns-block-reconcile"] != 1 || len(r.FailedRuns) != 2 {
+		t.Errorf("failed runs = %v", r.FailedRuns)
+	}
+}
+
+func TestRenderNeverListsPaths(t *testing.T) {
+	r := build(t)
+	var text, js bytes.Buffer
+	RenderText(r, &text)
+	if err := RenderJSON(r, &js); err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{text.String(), js.String()} {
+		if strings.Contains(out, "/a") {
+			t.Fatalf("report must count removed paths, not list them:\n%s", out)
+		}
+	}
+	for _, want := range []string{"Dota 2", "1.5 GiB reclaimed, 3200 files", "50% (84/168 h", "plugin_tamper_repaired"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text missing %q:\n%s", want, text.String())
+		}
+	}
+	var back Report
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil || back.PathsDeleted != 2 {
+		t.Errorf("JSON round-trip: %v %+v", err, back)
+	}
+}
+
+func TestMailerSend(t *testing.T) {
+	var gotTo []string
+	var gotMsg string
+	var gotAuth smtp.Auth
+	m := &Mailer{Addr: "smtp.example.com:587", From: "me@example.com", To: []string{"me@example.com"},
+		User: "me", Password: "pw",
+		send: func(_ string, a smtp.Auth, _ string, to []string, msg []byte) error {
+			gotAuth, gotTo, gotMsg = a, to, string(msg)
+			return nil
+		}}
+	if err := m.Send(build(t)); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth == nil || len(gotTo) != 1 || !strings.Contains(gotMsg, "Subject: focusd report") ||
+		!strings.Contains(gotMsg, "Blocked launches") {
+		t.Fatalf("auth=%v to=%v msg=\n%s", gotAuth, gotTo, gotMsg)
+	}
+
+	for _, bad := range []*Mailer{
+		{Addr: "smtp.example.com", From: "a@b", To: []string{"c@d"}},
+		{Addr: "h:25", From: "a@b"},
+		{Addr: "h:25", From: "a@b", To: []string{"c@d\r\nBcc: e@f"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package stats implements `platform stats`: lifetime and rolling (7/30-day)
+// enforcement counters per policy, protection uptime, and the streak of days
+// without a block attempt — the motivational read of the same history
+// `platform report` summarizes per window.
+//
+// The counters come from the persistent daily_stats table the runner keeps
+// (state.StatsRepo), so a lifetime read never rescans job_runs. Like report,
+// it is path-free: jobs appear by id, everything else is a count.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+// DaySource reads the per-day counters.
+type DaySource interface {
+	Days() ([]state.DayStats, error)
+}
+
+// HourSource measures uptime: hours in a window that saw a completed run.
+type HourSource interface {
+	ActiveHours(from, to time.Time) (int, error)
+}
+
+// Totals are summed counters.
+type Totals struct {
+	Runs       int `json:"runs"`
+	Blocks     int `json:"block_attempts"`
+	Kills      int `json:"kills"`
+	Removals   int `json:"removals"`
+	Relaunches int `json:"relaunches"`
+	Restores   int `json:"restores"`
+}
+
+func (t *Totals) add(d state.DayStats) {
+	t.Runs += d.Runs
+	t.Blocks += d.Blocks
+	t.Kills += d.Kills
+	t.Removals += d.Removals
+	t.Relaunches += d.Relaunches
+	t.Restores += d.Restores
+}
+
+// Window is one period's totals plus its uptime.
+type Window struct {
+	Totals
+	// ActiveHours of the period saw a completed run (the uptime proxy).
+	ActiveHours int `json:"active_hours"`
+}
+
+// Stats is the `platform stats` result. Its JSON form is the --json output.
+type Stats struct {
+	// Since is the first day with any counter (local date); "" on a fresh
+	// install. TrackedDays counts calendar days from Since through today.
+	Since       string `json:"since"`
+	TrackedDays int    `json:"tracked_days"`
+
+	Last7    Window `json:"last_7d"`
+	Last30   Window `json:"last_30d"`
+	Lifetime Window `json:"lifetime"`
+	// PerJob are the lifetime totals per policy (job id).
+	PerJob map[string]Totals `json:"per_job"`
+
+	// Streaks count calendar days without a block attempt. A day the
+	// platform was down counts as clean: absence of evidence is not a slip.
+	CurrentStreakDays int    `json:"current_streak_days"`
+	LongestStreakDays int    `json:"longest_streak_days"`
+	LastBlockDay      string `json:"last_block_day,omitempty"`
+}
+
+// Build reads every counter and computes the windows and streaks as of now.
+func Build(days DaySource, hours HourSource, now time.Time) (Stats, error) {
+	rows, err := days.Days()
+	if err != nil {
+		return Stats{}, err
+	}
+	s := Stats{PerJob: map[string]Totals{}}
+	today := state.Day(now)
+	from7 := state.Day(now.AddDate(0, 0, -6))
+	from30 := state.Day(now.AddDate(0, 0, -29))
+	blockDays := map[string]bool{}
+	for _, d := range rows {
+		if s.Since == "" || d.Day < s.Since {
+			s.Since = d.Day
+		}
+		s.Lifetime.add(d)
+		if d.Day >= from30 && d.Day <= today {
+			s.Last30.add(d)
+		}
+		if d.Day >= from7 && d.Day <= today {
+			s.Last7.add(d)
+		}
+		t := s.PerJob[d.JobID]
+		t.add(d)
+		s.PerJob[d.JobID] = t
+		if d.Blocks > 0 {
+			blockDays[d.Day] = true
+			if d.Day > s.LastBlockDay {
+				s.LastBlockDay = d.Day
+			}
+		}
+	}
+	if s.Since == "" {
+		return s, nil
+	}
+	s.TrackedDays, s.CurrentStreakDays, s.LongestStreakDays = streaks(s.Since, today, blockDays)
+
+	start, err := time.ParseInLocation(time.DateOnly, s.Since, time.Local)
+	if err != nil {
+		return Stats{}, fmt.Errorf("stats: bad day %q", s.Since)
+	}
+	for _, w := range []struct {
+		win  *Window
+		from time.Time
+	}{
+		{&s.Last7, now.AddDate(0, 0, -7)},
+		{&s.Last30, now.AddDate(0, 0, -30)},
+		{&s.Lifetime, start},
+	} {
+		if w.win.ActiveHours, err = hours.ActiveHours(w.from, now); err != nil {
+			return Stats{}, err
+		}
+	}
+	return s, nil
+}
+
+// streaks walks the calendar from since to today (both local dates) and
+// returns the day count, the clean run ending today, and the longest clean
+// run.
+func streaks(since, today string, blockDays map[string]bool) (days, current, longest int) {
+	d, err := time.ParseInLocation(time.DateOnly, since, time.Local)
+	if err != nil {
+		return 0, 0, 0
+	}
+	for ; d.Format(time.DateOnly) <= today; d = d.AddDate(0, 0, 1) {
+		day := d.Format(time.DateOnly)
+		days++
+		if blockDays[day] {
+			current = 0
+			continue
+		}
+		current++
+		longest = max(longest, current)
+	}
+	return days, current, longest
+}
+
+// RenderJSON writes s as indented JSON.
+func RenderJSON(s Stats, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// RenderText writes the human-readable stats.
+func RenderText(s Stats, out io.Writer) {
+	if s.Since == "" {
+		fmt.Fprintln(out, "focusd stats  no runs recorded yet")
+		return
+	}
+	fmt.Fprintf(out, "focusd stats  since %s (%d days)\n\n", s.Since, s.TrackedDays)
+	fmt.Fprintf(out, "  %-22s %8s %8s %10s\n", "", "7 days", "30 days", "lifetime")
+	row := func(label string, f func(Window) int) {
+		fmt.Fprintf(out, "  %-22s %8d %8d %10d\n", label, f(s.Last7), f(s.Last30), f(s.Lifetime))
+	}
+	row("Block attempts", func(w Window) int { return w.Blocks })
+	row("Processes killed", func(w Window) int { return w.Kills })
+	row("Reinstalls removed", func(w Window) int { return w.Removals })
+	row("Apps relaunched", func(w Window) int { return w.Relaunches })
+	row("Tamper restores", func(w Window) int { return w.Restores })
+	row("Hours protected", func(w Window) int { return w.ActiveHours })
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %-22s %d day(s)\n", "Current clean streak", s.CurrentStreakDays)
+	fmt.Fprintf(out, "  %-22s %d day(s)\n", "Longest clean streak", s.LongestStreakDays)
+	if s.LastBlockDay != "" {
+		fmt.Fprintf(out, "  %-22s %s\n", "Last block attempt", s.LastBlockDay)
+	}
+	fmt.Fprintln(out, "\n  Per policy (lifetime)")
+	jobs := make([]string, 0, len(s.PerJob))
+	for j := range s.PerJob {
+		jobs = append(jobs, j)
+	}
+	sort.Strings(jobs)
+	for _, j := range jobs {
+		t := s.PerJob[j]
+		fmt.Fprintf(out, "    %-20s %d attempts, %d kills, %d removals, %d relaunches, %d restores\n",
+			j, t.Blocks, t.Kills, t.Removals, t.Relaunches, t.Restores)
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/platform/internal/core/state"
+)
+
+type fakeDays []state.DayStats
+
+func (f fakeDays) Days() ([]state.DayStats, error) { return f, nil }
+
+type fakeHours struct{ froms []time.Time }
+
+func (f *fakeHours) ActiveHours(from, _ time.Time) (int, error) {
+	f.froms = append(f.froms, from)
+	return 24 * len(f.froms), nil
+}
+
+func TestBuildWindowsAndStreaks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	day := func(back int) string { return state.Day(now.AddDate(0, 0, -back)) }
+	rows := fakeDays{
+		{Day: day(40), JobID: "kill-steam", Runs: 10, Blocks: 1, Kills: 3},
+		{Day: day(20), JobID: "kill-steam", Runs: 10, Blocks: 2, Kills: 4, Removals: 1},
+		{Day: day(20), JobID: "freedom", Runs: 5, Relaunches: 2, Restores: 1},
+		{Day: day(3), JobID: "kill-steam", Runs: 10, Blocks: 1, Kills: 1},
+		{Day: day(0), JobID: "kill-steam", Runs: 4},
+	}
+	h := &fakeHours{}
+	s, err := Build(rows, h, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Since != day(40) || s.TrackedDays != 41 {
+		t.Errorf("since = %s over %d days", s.Since, s.TrackedDays)
+	}
+	if s.Last7.Blocks != 1 || s.Last30.Blocks != 3 || s.Lifetime.Blocks != 4 || s.Lifetime.Kills != 8 {
+		t.Errorf("windows: 7d=%+v 30d=%+v life=%+v", s.Last7, s.Last30, s.Lifetime)
+	}
+	if s.PerJob["freedom"].Restores != 1 || s.PerJob["kill-steam"].Removals != 1 {
+		t.Errorf("per job = %+v", s.PerJob)
+	}
+	// Block days at 40, 20 and 3 days back: the longest clean run is the 19
+	// days between 40 and 20; the current one is the 3 days since the last.
+	if s.CurrentStreakDays != 3 || s.LongestStreakDays != 19 || s.LastBlockDay != day(3) {
+		t.Errorf("streaks: current=%d longest=%d last=%s", s.CurrentStreakDays, s.LongestStreakDays, s.LastBlockDay)
+	}
+	if len(h.froms) != 3 || s.Lifetime.ActiveHours != 72 {
+		t.Errorf("uptime windows = %v, lifetime hours = %d", h.froms, s.Lifetime.ActiveHours)
+	}
+}
+
+func TestBuildEmptyAndRender(t *testing.T) {
+	s, err := Build(fakeDays(nil), &fakeHours{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text bytes.Buffer
+	RenderText(s, &text)
+	if !strings.Contains(text.String(), "no runs recorded yet") {
+		t.Errorf("empty text = %q", text.String())
+	}
+
+	s, _ = Build(fakeDays{{Day: state.Day(time.Now()), JobID: "kill-steam", Runs: 1, Blocks: 1, Kills: 2}}, &fakeHours{}, time.Now())
+	var js bytes.Buffer
+	if err := RenderJSON(s, &js); err != nil {
+		t.Fatal(err)
+	}
+	var back map[string]any
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back["current_streak_days"] != float64(0) || back["lifetime"].(map[string]any)["kills"] != float64(2) {
+		t.Errorf("json = %s", js.String())
+	}
+	text.Reset()
+	RenderText(s, &text)
+	if !strings.Contains(text.String(), "kill-steam") || !strings.Contains(text.String(), "Longest clean streak") {
+		t.Errorf("text = %s", text.String())
+	}
+}
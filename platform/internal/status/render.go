@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 )
 
 // ANSI colours; suppressed when color=false (NO_COLOR / --no-color).
@@ -56,6 +58,36 @@ func RenderText(r Report, out io.Writer, color bool) {
 	if r.SweepFailing {
 		fmt.Fprintf(out, "  %-26s %s\n", "integrity sweep", paint(cRed, "FAILING"))
 	}
+	for _, b := range r.Breaks {
+		left := (time.Duration(b.RemainingS) * time.Second).Round(time.Minute)
+		fmt.Fprintf(out, "  %-26s %s\n", "break", paint(cYellow, fmt.Sprintf("%s paused · %s left", jobLabel(b.ID), left)))
+	}
+	if r.Pause != nil {
+		left := (time.Duration(r.Pause.RemainingS) * time.Second).Round(time.Minute)
+		labels := make([]string, len(r.Pause.Jobs))
+		for i, id := range r.Pause.Jobs {
+			labels[i] = jobLabel(id)
+		}
+		fmt.Fprintf(out, "  %-26s %s\n", "pause", paint(cYellow, fmt.Sprintf("%s paused · %s left", strings.Join(labels, " + "), left)))
+	}
+	if len(r.Profiles) > 0 {
+		fmt.Fprintf(out, "  %-26s %s\n", "policy profile", strings.Join(r.Profiles, " + "))
+	}
+	if r.Calendar != nil {
+		left := (time.Duration(r.Calendar.RemainingS) * time.Second).Round(time.Minute)
+		fmt.Fprintf(out, "  %-26s %s\n", "calendar", fmt.Sprintf("%q → %s · %s left", r.Calendar.Entry, r.Calendar.Profile, left))
+	}
+	if r.Session != nil {
+		left := (time.Duration(r.Session.RemainingS) * time.Second).Round(time.Minute)
+		fmt.Fprintf(out, "  %-26s %s\n", "focus session", paint(cGreen, fmt.Sprintf("active · %s left", left)))
+	}
+	if r.Cadence != nil {
+		how := "reduced"
+		if r.Cadence.Factor > 1 {
+			how = fmt.Sprintf("reduced ×%d", r.Cadence.Factor)
+		}
+		fmt.Fprintf(out, "  %-26s %s\n", "cadence", paint(cYellow, how+" · "+r.Cadence.Reason))
+	}
 	fmt.Fprintf(out, "  %-26s %s\n", "OVERALL", paint(verdictColor(r.Overall), string(r.Overall)))
 }
 
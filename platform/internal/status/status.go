@@ -90,6 +90,54 @@ type Report struct {
 	// It degrades Overall and renders a distinct "integrity sweep: FAILING"
 	// line — defense-in-depth over the point-of-use check.
 	SweepFailing bool `json:"sweep_failing,omitempty"`
+	// Profiles are the policy profiles whose scheduled windows are open, or
+	// whose calendar entry is on.
+	Profiles []string `json:"profiles,omitempty"`
+	// Calendar is the calendar entry switching a profile on, nil when none.
+	Calendar *CalendarEntry `json:"calendar,omitempty"`
+	// Breaks are the jobs currently paused by a break token.
+	Breaks []JobBreak `json:"breaks,omitempty"`
+	// Pause is the running `platform pause`, nil when none.
+	Pause *Pause `json:"pause,omitempty"`
+	// Session is the active focus session, nil when none is running.
+	Session *FocusSession `json:"session,omitempty"`
+	// Cadence is set while jobs run less often to save power.
+	Cadence *Cadence `json:"reduced_cadence,omitempty"`
+}
+
+// Cadence is a backed-off cadence: why, and how many times longer job
+// intervals are (up to the configured cap); Factor 0 while idle, when they
+// are stretched to the cap itself.
+type Cadence struct {
+	Reason string `json:"reason"`
+	Factor int    `json:"factor"`
+}
+
+// JobBreak is a job paused by a break token, and for how much longer.
+type JobBreak struct {
+	ID         string `json:"id"`
+	RemainingS int64  `json:"remaining_s"`
+}
+
+// Pause is a running `platform pause`: the jobs it stops and for how much
+// longer. The reason stays in the audit log.
+type Pause struct {
+	Jobs       []string `json:"jobs"`
+	RemainingS int64    `json:"remaining_s"`
+}
+
+// CalendarEntry is the tagged calendar entry in force: its title, the
+// profile it switched on and how long it has left.
+type CalendarEntry struct {
+	Entry      string `json:"entry"`
+	Profile    string `json:"profile"`
+	RemainingS int64  `json:"remaining_s"`
+}
+
+// FocusSession is a running `platform session`: how long its extra
+// strictness still applies.
+type FocusSession struct {
+	RemainingS int64 `json:"remaining_s"`
 }
 
 // Collect builds the report from the configured jobs and a run-history
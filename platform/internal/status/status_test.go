@@ -260,3 +260,50 @@ func TestSweepFailing_FalseIsNoSignal(t *testing.T) {
 		t.Fatalf("overall = %q, want HEALTHY", rep.Overall)
 	}
 }
+
+func TestRender_SessionProfileAndBreakLines(t *testing.T) {
+	r := Report{Mode: "system", Overall: Healthy, Session: &FocusSession{RemainingS: 4830}, Profiles: []string{"work", "night"},
+		Breaks: []JobBreak{{ID: "browser-monitor-reconcile", RemainingS: 600}}}
+	var buf bytes.Buffer
+	RenderText(r, &buf, false)
+	if !strings.Contains(buf.String(), "active · 1h21m0s left") {
+		t.Fatalf("no session line:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "paused · 10m0s left") {
+		t.Fatalf("no break line:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "work + night") {
+		t.Fatalf("no profile line:\n%s", buf.String())
+	}
+	buf.Reset()
+	r.Pause = &Pause{Jobs: []string{"browser-monitor-reconcile", "freedom-protector-reconcile"}, RemainingS: 720}
+	RenderText(r, &buf, false)
+	if !strings.Contains(buf.String(), "pause") || !strings.Contains(buf.String(), " + ") || !strings.Contains(buf.String(), "paused · 12m0s left") {
+		t.Fatalf("no pause line:\n%s", buf.String())
+	}
+	r.Pause = nil
+	buf.Reset()
+	r.Calendar = &CalendarEntry{Entry: "Deep work #focus", Profile: "work", RemainingS: 2400}
+	RenderText(r, &buf, false)
+	if !strings.Contains(buf.String(), `"Deep work #focus" → work · 40m0s left`) {
+		t.Fatalf("no calendar line:\n%s", buf.String())
+	}
+	buf.Reset()
+	r.Cadence = &Cadence{Reason: "on battery", Factor: 3}
+	RenderText(r, &buf, false)
+	if !strings.Contains(buf.String(), "reduced ×3 · on battery") {
+		t.Fatalf("no cadence line:\n%s", buf.String())
+	}
+	buf.Reset()
+	r.Cadence = &Cadence{Reason: "idle"}
+	RenderText(r, &buf, false)
+	if !strings.Contains(buf.String(), "reduced · idle") {
+		t.Fatalf("no idle cadence line:\n%s", buf.String())
+	}
+	buf.Reset()
+	RenderText(Report{Overall: Healthy}, &buf, false)
+	if strings.Contains(buf.String(), "focus session") || strings.Contains(buf.String(), "policy profile") ||
+		strings.Contains(buf.String(), "cadence") {
+		t.Fatalf("session line without a session:\n%s", buf.String())
+	}
+}
@@ -10,7 +10,8 @@
 //
 // The job config can override the embedded blocklist with an explicit
 // "hosts": ["…", …] list (useful for tests/future server-driven mode).
-// If absent, the plugin uses the embedded data/*.txt.
+// If absent, the plugin uses the embedded data/*.txt. "extra_hosts" adds to
+// whichever list applies; the platform sets it during a focus session.
 package main
 
 import (
@@ -68,8 +69,14 @@ func run(args []string) int {
 		emit(result{Status: "error", Message: err.Error()})
 		return 2
 	}
+	extra, err := loadStringList(raw, "extra_hosts")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		emit(result{Status: "error", Message: err.Error()})
+		return 2
+	}
 
-	r := &reconciler.Reconciler{Domains: hosts}
+	r := &reconciler.Reconciler{Domains: hosts, Extra: extra}
 	out, rerr := r.Reconcile()
 	if rerr != nil {
 		fmt.Fprintln(os.Stderr, "reconcile:", rerr)
@@ -110,6 +117,12 @@ func readJobConfig(cfgPath string) ([]byte, error) {
 // present, otherwise nil (which makes the reconciler use the embedded
 // blocklist). Empty/nil raw is tolerated (run with no config = embedded).
 func loadHostsOverride(raw []byte) ([]string, error) {
+	return loadStringList(raw, "hosts")
+}
+
+// loadStringList returns config[key] as a string list, or nil when raw is
+// empty or the key is absent.
+func loadStringList(raw []byte, key string) ([]string, error) {
 	if len(raw) == 0 {
 		return nil, nil
 	}
@@ -117,19 +130,19 @@ func loadHostsOverride(raw []byte) ([]string, error) {
 	if err := json.Unmarshal(raw, &in); err != nil {
 		return nil, fmt.Errorf("parse config JSON: %w", err)
 	}
-	v, ok := in.Config["hosts"]
+	v, ok := in.Config[key]
 	if !ok {
 		return nil, nil
 	}
 	arr, ok := v.([]any)
 	if !ok {
-		return nil, fmt.Errorf("config.hosts must be an array")
+		return nil, fmt.Errorf("config.%s must be an array", key)
 	}
 	out := make([]string, 0, len(arr))
 	for _, x := range arr {
 		s, ok := x.(string)
 		if !ok {
-			return nil, fmt.Errorf("config.hosts must be strings")
+			return nil, fmt.Errorf("config.%s must be strings", key)
 		}
 		out = append(out, s)
 	}
@@ -68,6 +68,20 @@ func TestLoadHostsOverrideErrors(t *testing.T) {
 	}
 }
 
+func TestLoadExtraHosts(t *testing.T) {
+	raw := []byte(`{"config":{"extra_hosts":["slack.com"]}}`)
+	extra, err := loadStringList(raw, "extra_hosts")
+	if err != nil || len(extra) != 1 || extra[0] != "slack.com" {
+		t.Fatalf("extra_hosts = %v, %v", extra, err)
+	}
+	if h, err := loadHostsOverride(raw); err != nil || h != nil {
+		t.Fatalf("extra_hosts must not become a hosts override: %v, %v", h, err)
+	}
+	if _, err := loadStringList([]byte(`{"config":{"extra_hosts":"slack.com"}}`), "extra_hosts"); err == nil {
+		t.Fatal("expected type error for non-array extra_hosts")
+	}
+}
+
 // --- readJobConfig: --config (compat) vs stdin (disguised) ---
 
 func TestReadJobConfigFromFile(t *testing.T) {
@@ -37,6 +37,9 @@ type Reconciler struct {
 	// Domains explicitly overrides the embedded blocklist (tests + future
 	// platform-config-driven mode).
 	Domains []string
+	// Extra is added to whichever list applies (a focus session's
+	// extra_hosts). It never replaces the base list.
+	Extra []string
 	// GetEUID is a test seam (defaults to os.Geteuid).
 	GetEUID func() int
 }
@@ -155,11 +158,11 @@ func (r *Reconciler) hostsPath() string {
 }
 
 // resolveDomains returns explicit Domains if set, otherwise reads every
-// data/*.txt embedded file. Lines starting with # or blank are ignored.
-// Output is dedup'd and sorted for stable rendering.
+// data/*.txt embedded file, plus Extra either way. Lines starting with # or
+// blank are ignored. Output is dedup'd and sorted for stable rendering.
 func (r *Reconciler) resolveDomains() ([]string, error) {
 	if len(r.Domains) > 0 {
-		return uniqueSorted(r.Domains), nil
+		return uniqueSorted(append(append([]string(nil), r.Domains...), r.Extra...)), nil
 	}
 	entries, err := defaultBlocklist.ReadDir("data")
 	if err != nil {
@@ -182,7 +185,7 @@ func (r *Reconciler) resolveDomains() ([]string, error) {
 			all = append(all, line)
 		}
 	}
-	return uniqueSorted(all), nil
+	return uniqueSorted(append(all, r.Extra...)), nil
 }
 
 func uniqueSorted(s []string) []string {
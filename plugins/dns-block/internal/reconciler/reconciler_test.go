@@ -90,6 +90,29 @@ func TestResolveDomains_ExplicitWins(t *testing.T) {
 	}
 }
 
+func TestResolveDomains_ExtraAddsToEitherList(t *testing.T) {
+	r := &Reconciler{Domains: []string{"b"}, Extra: []string{"slack.com", "b"}}
+	got, err := r.resolveDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "b" || got[1] != "slack.com" {
+		t.Fatalf("explicit + extra = %v", got)
+	}
+
+	base, err := (&Reconciler{}).resolveDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	withExtra, err := (&Reconciler{Extra: []string{"slack.com"}}).resolveDomains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withExtra) != len(base)+1 {
+		t.Fatalf("embedded + extra: %d domains, want %d", len(withExtra), len(base)+1)
+	}
+}
+
 func TestResolveDomains_EmbeddedHasSteamAndPersonal(t *testing.T) {
 	r := &Reconciler{}
 	got, err := r.resolveDomains()
@@ -8,8 +8,12 @@
 //
 // Input  : JSON file {job_id, plugin_id, config:{app_path?, app_process?,
 //
-//	proxy_process?, proxy_port?, proxy_rpcport?}} — all optional;
-//	empty config => grounded defaults.
+//	proxy_process?, proxy_port?, proxy_rpcport?, protectors?,
+//	app_paths?}} — all optional; empty config => grounded defaults
+//	(Freedom only). protectors is a list of ids to keep alive (freedom,
+//	cold-turkey, selfcontrol, 1focus, one-sec); app_paths maps an id to a
+//	non-default .app location. app_cache_dir overrides where installed
+//	app bundles are archived for restore after deletion.
 //
 // Output : structured JSON result on stdout, diagnostics on stderr.
 // Exit   : 0 success (or benign skip) · 1 controlled failure (a relaunch
@@ -25,6 +29,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/eliteGoblin/focusd/plugins/freedom-protector/internal/reconciler"
@@ -85,6 +90,10 @@ func run(args []string) int {
 		return 2
 	}
 
+	if opts.CacheDir == "" {
+		opts.CacheDir = defaultCacheDir()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), reconcileBudget)
 	defer cancel()
 	out, err := reconciler.New(opts).Reconcile(ctx)
@@ -101,6 +110,10 @@ func run(args []string) int {
 		"proxy_running":   out.ProxyRunning,
 		"relaunched":      out.Relaunched,
 		"login_item_note": out.LoginItemNote,
+		"protectors":      out.Protectors,
+	}
+	if out.SystemProxy != "" {
+		details["system_proxy"] = out.SystemProxy
 	}
 	if out.SkipReason != "" {
 		details["skip_reason"] = out.SkipReason
@@ -125,8 +138,8 @@ func run(args []string) int {
 	}
 
 	emit(result{Status: "ok",
-		Message: fmt.Sprintf("app_running=%v proxy_running=%v relaunched=%v",
-			out.AppRunning, out.ProxyRunning, out.Relaunched),
+		Message: fmt.Sprintf("app_running=%v proxy_running=%v relaunched=%v system_proxy=%s",
+			out.AppRunning, out.ProxyRunning, out.Relaunched, out.SystemProxy),
 		Details: details})
 	return 0
 }
@@ -159,13 +172,46 @@ func loadOptions(raw []byte) (reconciler.Options, error) {
 	if err := json.Unmarshal(raw, &in); err != nil {
 		return reconciler.Options{}, fmt.Errorf("parse config JSON: %w", err)
 	}
-	return reconciler.Options{
+	opts := reconciler.Options{
 		AppPath:      stringField(in.Config, "app_path"),
 		AppProcess:   stringField(in.Config, "app_process"),
 		ProxyProcess: stringField(in.Config, "proxy_process"),
 		ProxyPort:    stringField(in.Config, "proxy_port"),
 		ProxyRPCPort: stringField(in.Config, "proxy_rpcport"),
-	}, nil
+		CacheDir:     stringField(in.Config, "app_cache_dir"),
+	}
+	// Unlike the path/port knobs, a protector id is a choice of what to
+	// protect: a typo silently protecting nothing is worse than a config
+	// error, so an unknown id fails the job.
+	if list, ok := in.Config["protectors"].([]any); ok {
+		for _, v := range list {
+			id, _ := v.(string)
+			if !reconciler.ValidProtector(id) {
+				return reconciler.Options{}, fmt.Errorf("unknown protector %v (known: %v)", v, reconciler.KnownProtectors())
+			}
+			opts.Protectors = append(opts.Protectors, id)
+		}
+	}
+	if m, ok := in.Config["app_paths"].(map[string]any); ok {
+		opts.AppPaths = map[string]string{}
+		for id := range m {
+			if p := stringField(m, id); p != "" {
+				opts.AppPaths[id] = p
+			}
+		}
+	}
+	return opts, nil
+}
+
+// defaultCacheDir is the per-user app-bundle cache: a neutral hidden dir in
+// the user cache root, so neither the plugin nor the protected app shows up
+// in its name. "" (no cache) when the cache root is unknown.
+func defaultCacheDir() string {
+	root, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(root, ".lcache")
 }
 
 // stringField returns config[key] when present and a string, else "".
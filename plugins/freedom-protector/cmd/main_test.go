@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/eliteGoblin/focusd/plugins/freedom-protector/internal/reconciler"
@@ -34,7 +35,7 @@ func withStdin(t *testing.T, data string, fn func()) {
 
 func TestLoadOptionsDefaultsWhenEmpty(t *testing.T) {
 	opts, err := loadOptions(nil)
-	if err != nil || opts != (reconciler.Options{}) {
+	if err != nil || !reflect.DeepEqual(opts, reconciler.Options{}) {
 		t.Errorf("nil raw => zero opts,nil err; got %+v,%v", opts, err)
 	}
 }
@@ -58,7 +59,7 @@ func TestLoadOptionsIgnoresWrongTypesAndMissingKeys(t *testing.T) {
 	if err != nil {
 		t.Fatalf("loadOptions: %v", err)
 	}
-	if opts != (reconciler.Options{}) {
+	if !reflect.DeepEqual(opts, reconciler.Options{}) {
 		t.Errorf("wrong-typed values should be ignored, got %+v", opts)
 	}
 }
@@ -67,6 +68,24 @@ func TestLoadOptionsErrors(t *testing.T) {
 	if _, err := loadOptions([]byte(`{not json`)); err == nil {
 		t.Error("expected parse error")
 	}
+	if _, err := loadOptions([]byte(`{"config":{"protectors":["freedom","cold-turkee"]}}`)); err == nil {
+		t.Error("expected error for an unknown protector id")
+	}
+}
+
+func TestLoadOptionsProtectors(t *testing.T) {
+	raw := []byte(`{"config":{"protectors":["freedom","cold-turkey"],` +
+		`"app_paths":{"cold-turkey":"/Apps/CT.app","1focus":7}}}`)
+	opts, err := loadOptions(raw)
+	if err != nil {
+		t.Fatalf("loadOptions: %v", err)
+	}
+	if !reflect.DeepEqual(opts.Protectors, []string{"freedom", "cold-turkey"}) {
+		t.Errorf("protectors = %v", opts.Protectors)
+	}
+	if !reflect.DeepEqual(opts.AppPaths, map[string]string{"cold-turkey": "/Apps/CT.app"}) {
+		t.Errorf("app_paths = %v (wrong-typed entries must be ignored)", opts.AppPaths)
+	}
 }
 
 // --- readJobConfig: --config (compat) vs stdin (disguised) ---
@@ -90,7 +109,7 @@ func TestReadJobConfig_StdinMatchesFile(t *testing.T) {
 	}
 	fromStdin, _ := loadOptions(fromStdinRaw)
 
-	if fromFile != fromStdin || fromStdin.AppPath != "/A/Free.app" || fromStdin.ProxyPort != "7" {
+	if !reflect.DeepEqual(fromFile, fromStdin) || fromStdin.AppPath != "/A/Free.app" || fromStdin.ProxyPort != "7" {
 		t.Errorf("stdin %+v != file %+v", fromStdin, fromFile)
 	}
 }
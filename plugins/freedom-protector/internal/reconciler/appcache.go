@@ -0,0 +1,95 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// cacheTimeout bounds one archive or restore of an app bundle. A large app
+// that cannot be archived inside it is simply retried on a later pass.
+const cacheTimeout = 10 * time.Second
+
+// cachePath is where p's bundle archive lives under dir. The basename is a
+// digest of the bundle path so the cache names neither the app nor focusd.
+func cachePath(dir string, p appProtector) string {
+	sum := sha256.Sum256([]byte(p.bundle()))
+	return filepath.Join(dir, hex.EncodeToString(sum[:6])+".z")
+}
+
+// restoreApp re-creates a deleted bundle from its cached archive. It reports
+// whether the bundle is back; no cache (or a disabled one) is not an error.
+func (r *Reconciler) restoreApp(ctx context.Context, p appProtector) (bool, error) {
+	if r.cacheDir == "" {
+		return false, nil
+	}
+	zip := cachePath(r.cacheDir, p)
+	if !r.stat(zip) {
+		return false, nil
+	}
+	cctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+	if err := r.extract(cctx, zip, filepath.Dir(p.bundle())); err != nil {
+		return false, err
+	}
+	return r.stat(p.bundle()), nil
+}
+
+// refreshCache archives an installed bundle when there is no archive yet or
+// the bundle changed since (an app update), so a later deletion restores the
+// version the user last had.
+func (r *Reconciler) refreshCache(ctx context.Context, p appProtector) error {
+	if r.cacheDir == "" {
+		return nil
+	}
+	zip := cachePath(r.cacheDir, p)
+	if r.stat(zip) && !r.newer(p.bundle(), zip) {
+		return nil
+	}
+	cctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+	return r.archive(cctx, p.bundle(), zip)
+}
+
+// dittoArchive is the real archive seam: `ditto -c -k --keepParent` keeps
+// the bundle's code signature and extended attributes intact. It writes to
+// a temp name and renames, so a timed-out archive never replaces a good one.
+func dittoArchive(ctx context.Context, bundle, zip string) error {
+	if err := os.MkdirAll(filepath.Dir(zip), 0o700); err != nil {
+		return err
+	}
+	tmp := zip + ".tmp"
+	defer os.Remove(tmp)
+	if out, err := exec.CommandContext(ctx, "ditto", "-c", "-k", "--keepParent", bundle, tmp).CombinedOutput(); err != nil {
+		return fmt.Errorf("archive: %v: %s", err, out)
+	}
+	return os.Rename(tmp, zip)
+}
+
+// dittoExtract is the real restore seam: unpack the archive (which carries
+// the bundle's own directory name) into dir.
+func dittoExtract(ctx context.Context, zip, dir string) error {
+	if out, err := exec.CommandContext(ctx, "ditto", "-x", "-k", zip, dir).CombinedOutput(); err != nil {
+		return fmt.Errorf("restore: %v: %s", err, out)
+	}
+	return nil
+}
+
+// modifiedAfter is the real staleness seam: whether a was modified after b.
+// An unreadable bundle counts as unchanged — never re-archive blindly.
+func modifiedAfter(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return true
+	}
+	return fa.ModTime().After(fb.ModTime())
+}
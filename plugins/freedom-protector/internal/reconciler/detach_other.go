@@ -2,9 +2,15 @@
 
 package reconciler
 
-import "os/exec"
+import (
+	"errors"
+	"os/exec"
+)
 
 // detach is a no-op on platforms without POSIX process groups. The plugin
 // is darwin-only (see plugin.json supported_os); this stub exists solely
 // so the cross-platform workspace build (windows) still compiles.
 func detach(_ *exec.Cmd) {}
+
+// killPID is unsupported off POSIX; see detach.
+func killPID(int) error { return errors.New("kill unsupported on this OS") }
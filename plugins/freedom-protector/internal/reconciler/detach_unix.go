@@ -13,3 +13,7 @@ import (
 func detach(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
+
+// killPID is the real kill seam for an unresponsive helper. SIGKILL, since a
+// wedged process is exactly the one that would ignore a polite signal.
+func killPID(pid int) error { return syscall.Kill(pid, syscall.SIGKILL) }
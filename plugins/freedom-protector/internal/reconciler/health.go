@@ -0,0 +1,83 @@
+package reconciler
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// dialTimeout bounds the loopback liveness probe of a listening helper. A
+// healthy local listener accepts in microseconds; a second is generous.
+const dialTimeout = time.Second
+
+// System-proxy verdicts reported in Outcome.SystemProxy.
+const (
+	SystemProxyOK      = "ok"         // an enabled system proxy routes to the proxy port
+	SystemProxyBypass  = "not_routed" // settings readable, but nothing routes to the proxy
+	SystemProxyUnknown = "unknown"    // settings unreadable (or not macOS)
+)
+
+// listening reports whether something accepts TCP on loopback:port. It is
+// the health signal for a target with a port: a FreedomProxy process that
+// exists but no longer accepts connections blocks nothing.
+func (r *Reconciler) listening(ctx context.Context, port string) bool {
+	cctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	return r.dial(cctx, net.JoinHostPort("127.0.0.1", port)) == nil
+}
+
+// dialTCP is the real probe seam.
+func dialTCP(ctx context.Context, addr string) error {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return c.Close()
+}
+
+// readSystemProxy is the real settings seam: `scutil --proxy` prints the
+// active network service's proxy dictionary.
+func readSystemProxy(ctx context.Context) (string, error) {
+	cctx, cancel := context.WithTimeout(ctx, dialTimeout*5)
+	defer cancel()
+	out, err := exec.CommandContext(cctx, "scutil", "--proxy").Output()
+	return string(out), err
+}
+
+// systemProxyVerdict classifies `scutil --proxy` output: OK when an enabled
+// HTTP or HTTPS proxy points at loopback:port, or an enabled PAC URL names
+// the port; not routed otherwise.
+func systemProxyVerdict(scutil, port string) string {
+	kv := map[string]string{}
+	sc := bufio.NewScanner(strings.NewReader(scutil))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), " : ")
+		if ok {
+			kv[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	if len(kv) == 0 {
+		return SystemProxyUnknown
+	}
+	for _, scheme := range []string{"HTTP", "HTTPS"} {
+		if kv[scheme+"Enable"] == "1" && kv[scheme+"Port"] == port && isLoopback(kv[scheme+"Proxy"]) {
+			return SystemProxyOK
+		}
+	}
+	if kv["ProxyAutoConfigEnable"] == "1" && strings.Contains(kv["ProxyAutoConfigURLString"], ":"+port) {
+		return SystemProxyOK
+	}
+	return SystemProxyBypass
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
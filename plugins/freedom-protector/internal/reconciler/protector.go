@@ -0,0 +1,152 @@
+package reconciler
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Protector IDs. ProtectorFreedom is the default (and historically the only)
+// protector; the rest are opt-in via Options.Protectors.
+const (
+	ProtectorFreedom     = "freedom"
+	ProtectorColdTurkey  = "cold-turkey"
+	ProtectorSelfControl = "selfcontrol"
+	Protector1Focus      = "1focus"
+	ProtectorOneSec      = "one-sec"
+)
+
+// appProtector is one third-party focus app the reconciler keeps alive. The
+// reconcile loop treats every enabled protector the same way: skip it when
+// its bundle is absent, otherwise check each target against the process
+// table (health) and relaunch the ones that are down (protect).
+type appProtector interface {
+	id() string
+	// bundle is the .app path whose presence means "installed".
+	bundle() string
+	// targets are the processes to keep alive, each with its relaunch command.
+	targets() []target
+}
+
+// target is one watched process of a protector.
+type target struct {
+	label   string   // result label ("app", "proxy", or a protector id)
+	process string   // absolute executable path matched against the process table
+	launch  []string // relaunch command: name, then args
+	// port, when set, is a loopback TCP port the process must accept on to
+	// count as healthy; a running process that does not is killed and
+	// relaunched.
+	port string
+}
+
+// freedomProtector keeps Freedom's main app and its FreedomProxy alive. Its
+// target labels stay "app"/"proxy" so the plugin's result shape is unchanged
+// for the default single-protector configuration.
+type freedomProtector struct {
+	appPath, appProcess, proxyProcess, proxyPort, proxyRPCPort string
+}
+
+func (f freedomProtector) id() string     { return ProtectorFreedom }
+func (f freedomProtector) bundle() string { return f.appPath }
+func (f freedomProtector) targets() []target {
+	return []target{
+		// `open -a` is the least-racy way to (re)launch a GUI .app: it is
+		// idempotent (a no-op if already up) and routes through Launch
+		// Services, and it is also the indirect path by which Freedom can
+		// re-register its own background/login item (see loginItemNote).
+		{label: "app", process: f.appProcess, launch: []string{"open", "-a", f.appPath}},
+		// The proxy is a plain helper binary, not a .app, so exec it
+		// directly with its expected args.
+		{label: "proxy", process: f.proxyProcess, port: f.proxyPort,
+			launch: []string{f.proxyProcess, "-port", f.proxyPort, "-rpcport", f.proxyRPCPort}},
+	}
+}
+
+// bundleProtector keeps a single-process .app alive — the shape of the other
+// supported blockers. The executable is the conventional
+// Contents/MacOS/<bundle name>, and the target label is the protector id.
+type bundleProtector struct {
+	name    string
+	appPath string
+}
+
+func (b bundleProtector) id() string     { return b.name }
+func (b bundleProtector) bundle() string { return b.appPath }
+func (b bundleProtector) targets() []target {
+	exe := strings.TrimSuffix(filepath.Base(b.appPath), ".app")
+	return []target{{
+		label:   b.name,
+		process: filepath.Join(b.appPath, "Contents", "MacOS", exe),
+		launch:  []string{"open", "-a", b.appPath},
+	}}
+}
+
+// defaultBundles are the install locations of the opt-in blockers. Unlike the
+// Freedom defaults these were not grounded on the reference machine; a
+// different install location is set per machine with Options.AppPaths.
+//
+// SelfControl enforces its block from a privileged helper daemon, not the
+// app: relaunching the app keeps its UI (and timer) reachable but the block
+// itself survives the app being quit.
+var defaultBundles = map[string]string{
+	ProtectorColdTurkey:  "/Applications/Cold Turkey Blocker.app",
+	ProtectorSelfControl: "/Applications/SelfControl.app",
+	Protector1Focus:      "/Applications/1Focus.app",
+	ProtectorOneSec:      "/Applications/one sec.app",
+}
+
+// KnownProtectors lists every protector id, sorted.
+func KnownProtectors() []string {
+	ids := []string{ProtectorFreedom}
+	for id := range defaultBundles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ValidProtector reports whether id names a known protector.
+func ValidProtector(id string) bool {
+	_, ok := defaultBundles[id]
+	return ok || id == ProtectorFreedom
+}
+
+// buildProtectors resolves the enabled protectors from opts. No explicit list
+// means Freedom only (the plugin's original behaviour); unknown or repeated
+// ids are dropped here — the command rejects unknown ids before New.
+func buildProtectors(opts Options, fr freedomProtector) []appProtector {
+	ids := opts.Protectors
+	if len(ids) == 0 {
+		ids = []string{ProtectorFreedom}
+	}
+	seen := map[string]bool{}
+	var out []appProtector
+	for _, id := range ids {
+		if seen[id] || !ValidProtector(id) {
+			continue
+		}
+		seen[id] = true
+		if id == ProtectorFreedom {
+			out = append(out, fr)
+			continue
+		}
+		out = append(out, bundleProtector{name: id, appPath: orDefault(opts.AppPaths[id], defaultBundles[id])})
+	}
+	return out
+}
+
+// ProtectorOutcome is one protector's share of a reconcile pass.
+type ProtectorOutcome struct {
+	ID         string          `json:"id"`
+	Skipped    bool            `json:"skipped"`               // app not installed
+	SkipReason string          `json:"skip_reason,omitempty"` // why skipped
+	Running    map[string]bool `json:"running,omitempty"`     // target label -> up
+	Relaunched []string        `json:"relaunched,omitempty"`
+	Failed     []string        `json:"failed,omitempty"`
+	Wedged     []string        `json:"wedged,omitempty"`      // running but not accepting on its port (killed)
+	Restored   bool            `json:"restored,omitempty"`    // deleted app re-created from the cache
+	CacheError string          `json:"cache_error,omitempty"` // archiving failed (best-effort)
+}
+
+func notPresent(path string) string { return fmt.Sprintf("%s not present", path) }
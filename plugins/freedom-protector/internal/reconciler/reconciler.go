@@ -1,17 +1,20 @@
 // Package reconciler is the core logic for the freedom-protector plugin:
 // keep the third-party Freedom focus app (Freedom.to) and its proxy
 // process alive, and make a best-effort attempt to keep Freedom's
-// macOS background/login item enabled.
+// macOS background/login item enabled. Other blockers (Cold Turkey,
+// SelfControl, 1Focus, One Sec) are opt-in protectors handled by the
+// same loop (see protector.go).
 //
 // On each reconcile pass it:
-//   - scans running processes for the Freedom main app and FreedomProxy;
+//   - scans running processes once for every enabled protector's targets
+//     (for Freedom: the main app and FreedomProxy);
 //   - relaunches whichever is down (the proxy with its expected args);
 //   - records a best-effort login-item note (see loginItemNote for the
 //     honest limitation — there is no reliable public scriptable setter).
 //
 // The reconcile is idempotent (only relaunches what is down), bounded
 // (every external launch runs under a timeout so the job never hangs),
-// and skips cleanly when Freedom is not installed. The only OS-bound
+// and skips cleanly when a protected app is not installed. The only OS-bound
 // inputs are the process lister and the launcher, both behind interface
 // seams so tests inject fakes and nothing real is touched.
 package reconciler
@@ -69,6 +72,17 @@ type Options struct {
 	ProxyProcess string
 	ProxyPort    string
 	ProxyRPCPort string
+
+	// Protectors are the enabled protector ids (see KnownProtectors), in
+	// reconcile order. Empty ⇒ Freedom only.
+	Protectors []string
+	// AppPaths overrides the .app location of a non-Freedom protector, keyed
+	// by id (Freedom keeps AppPath above).
+	AppPaths map[string]string
+
+	// CacheDir holds an archive of each installed app bundle so a deleted
+	// app is restored rather than skipped. Empty ⇒ no cache (skip as before).
+	CacheDir string
 }
 
 // Reconciler holds the OS seams and resolved target paths. Construct it
@@ -80,27 +94,54 @@ type Reconciler struct {
 	proxyPort    string
 	proxyRPCPort string
 
+	// protectors are the enabled apps, built once by New.
+	protectors []appProtector
+
 	list   procLister
 	launch launcher
 
-	// stat reports whether the Freedom app bundle exists on disk. Behind
-	// a seam so the "Freedom absent => benign skip" path is testable.
+	// stat reports whether a protected app bundle exists on disk. Behind
+	// a seam so the "app absent => benign skip" path is testable.
 	stat func(path string) bool
+
+	// cacheDir and the archive/extract/newer seams back the app-bundle
+	// cache (appcache.go); tests fake all three.
+	cacheDir string
+	archive  func(ctx context.Context, bundle, zip string) error
+	extract  func(ctx context.Context, zip, dir string) error
+	newer    func(a, b string) bool
+
+	// dial, kill and proxySettings back the listener health check and the
+	// system-proxy verdict (health.go).
+	dial          func(ctx context.Context, addr string) error
+	kill          func(pid int) error
+	proxySettings func(ctx context.Context) (string, error)
 }
 
 // New builds a Reconciler, filling any empty Options field with its
 // grounded default and wiring the real OS seams.
 func New(opts Options) *Reconciler {
 	r := &Reconciler{
-		appPath:      orDefault(opts.AppPath, DefaultAppPath),
-		appProcess:   orDefault(opts.AppProcess, DefaultAppProcess),
-		proxyProcess: orDefault(opts.ProxyProcess, DefaultProxyProcess),
-		proxyPort:    orDefault(opts.ProxyPort, DefaultProxyPort),
-		proxyRPCPort: orDefault(opts.ProxyRPCPort, DefaultProxyRPCPort),
-		list:         listProcesses,
-		launch:       launchDetached,
-		stat:         pathExists,
+		appPath:       orDefault(opts.AppPath, DefaultAppPath),
+		appProcess:    orDefault(opts.AppProcess, DefaultAppProcess),
+		proxyProcess:  orDefault(opts.ProxyProcess, DefaultProxyProcess),
+		proxyPort:     orDefault(opts.ProxyPort, DefaultProxyPort),
+		proxyRPCPort:  orDefault(opts.ProxyRPCPort, DefaultProxyRPCPort),
+		list:          listProcesses,
+		launch:        launchDetached,
+		stat:          pathExists,
+		cacheDir:      strings.TrimSpace(opts.CacheDir),
+		archive:       dittoArchive,
+		extract:       dittoExtract,
+		newer:         modifiedAfter,
+		dial:          dialTCP,
+		kill:          killPID,
+		proxySettings: readSystemProxy,
 	}
+	r.protectors = buildProtectors(opts, freedomProtector{
+		appPath: r.appPath, appProcess: r.appProcess, proxyProcess: r.proxyProcess,
+		proxyPort: r.proxyPort, proxyRPCPort: r.proxyRPCPort,
+	})
 	return r
 }
 
@@ -112,16 +153,23 @@ func orDefault(v, def string) string {
 }
 
 // Outcome summarises one reconcile pass. It is the shape the command
-// emits as JSON to stdout.
+// emits as JSON to stdout. The top-level fields aggregate every enabled
+// protector (AppRunning/ProxyRunning are Freedom's); Protectors carries the
+// per-app breakdown.
 type Outcome struct {
-	Skipped       bool     `json:"skipped"`               // Freedom not installed
+	Skipped       bool     `json:"skipped"`               // no enabled app installed
 	SkipReason    string   `json:"skip_reason,omitempty"` // why skipped
 	Scanned       int      `json:"scanned"`               // processes inspected
 	AppRunning    bool     `json:"app_running"`
 	ProxyRunning  bool     `json:"proxy_running"`
-	Relaunched    []string `json:"relaunched"`       // targets relaunched this pass ("app","proxy")
+	Relaunched    []string `json:"relaunched"`       // targets relaunched this pass ("app","proxy",…)
 	Failed        []string `json:"failed,omitempty"` // "target: reason" for launch failures
 	LoginItemNote string   `json:"login_item_note"`  // best-effort login-item status (honest)
+	// SystemProxy is whether macOS's proxy settings route through Freedom's
+	// proxy port (SystemProxyOK/Bypass/Unknown); "" when Freedom is not
+	// protected. Report-only: re-pointing system settings needs admin.
+	SystemProxy string             `json:"system_proxy,omitempty"`
+	Protectors  []ProtectorOutcome `json:"protectors"`
 }
 
 // loginItemNote is the explicit, honest record for acceptance #3.
@@ -148,50 +196,96 @@ const loginItemNote = "login-item re-enable: not scriptable (best-effort; " +
 // is recorded in Outcome.Failed and does not abort the pass (controlled
 // failure, surfaced to the caller for exit-code mapping).
 func (r *Reconciler) Reconcile(ctx context.Context) (Outcome, error) {
-	// Skip cleanly when Freedom is absent — never error or hang.
-	if !r.stat(r.appPath) {
-		return Outcome{
-			Skipped:       true,
-			SkipReason:    fmt.Sprintf("%s not present", r.appPath),
-			LoginItemNote: loginItemNote,
-		}, nil
+	out := Outcome{LoginItemNote: loginItemNote}
+
+	// A deleted app is restored from its cached archive when there is one;
+	// otherwise skip cleanly — never error or hang. With no enabled app
+	// installed the process table is not even read.
+	var reasons []string
+	for _, p := range r.protectors {
+		po := ProtectorOutcome{ID: p.id()}
+		if !r.stat(p.bundle()) {
+			restored, err := r.restoreApp(ctx, p)
+			if err != nil {
+				po.Failed = append(po.Failed, fmt.Sprintf("restore: %v", err))
+				out.Failed = append(out.Failed, po.Failed...)
+			}
+			po.Restored = restored
+		}
+		if !r.stat(p.bundle()) {
+			po.Skipped, po.SkipReason = true, notPresent(p.bundle())
+			reasons = append(reasons, po.SkipReason)
+		}
+		out.Protectors = append(out.Protectors, po)
+	}
+	if len(reasons) == len(r.protectors) {
+		out.Skipped, out.SkipReason = true, strings.Join(reasons, "; ")
+		return out, nil
 	}
 
 	procs, err := r.list()
 	if err != nil {
 		return Outcome{}, fmt.Errorf("enumerate processes: %w", err)
 	}
+	out.Scanned = len(procs)
 
-	out := Outcome{Scanned: len(procs), LoginItemNote: loginItemNote}
-	out.AppRunning = matchesAny(procs, r.appProcess)
-	out.ProxyRunning = matchesAny(procs, r.proxyProcess)
-
-	// Relaunch only what is down (idempotent).
-	if !out.AppRunning {
-		// `open -a` is the least-racy way to (re)launch a GUI .app: it is
-		// idempotent (a no-op if already up) and routes through Launch
-		// Services, and it is also the indirect path by which Freedom can
-		// re-register its own background/login item (see loginItemNote).
-		if err := r.runLaunch(ctx, "open", "-a", r.appPath); err != nil {
-			out.Failed = append(out.Failed, fmt.Sprintf("app: %v", err))
-		} else {
-			out.Relaunched = append(out.Relaunched, "app")
+	for i, p := range r.protectors {
+		po := &out.Protectors[i]
+		if po.Skipped {
+			continue
 		}
-	}
-	if !out.ProxyRunning {
-		// The proxy is a plain helper binary, not a .app, so exec it
-		// directly with its expected args.
-		if err := r.runLaunch(ctx, r.proxyProcess,
-			"-port", r.proxyPort, "-rpcport", r.proxyRPCPort); err != nil {
-			out.Failed = append(out.Failed, fmt.Sprintf("proxy: %v", err))
-		} else {
-			out.Relaunched = append(out.Relaunched, "proxy")
+		r.protect(ctx, p, procs, po)
+		out.Relaunched = append(out.Relaunched, po.Relaunched...)
+		out.Failed = append(out.Failed, po.Failed...)
+		// Archive after relaunching so keeping the app up is never delayed
+		// by the copy. A failed archive only costs the next restore.
+		if !po.Restored {
+			if err := r.refreshCache(ctx, p); err != nil {
+				po.CacheError = err.Error()
+			}
+		}
+		if p.id() == ProtectorFreedom {
+			out.AppRunning, out.ProxyRunning = po.Running["app"], po.Running["proxy"]
+			out.SystemProxy = SystemProxyUnknown
+			if raw, err := r.proxySettings(ctx); err == nil {
+				out.SystemProxy = systemProxyVerdict(raw, r.proxyPort)
+			}
 		}
 	}
 	sort.Strings(out.Relaunched)
 	return out, nil
 }
 
+// protect is the shared health/protect step: record which of p's targets are
+// up, and relaunch only the ones that are down (idempotent). A failed launch
+// is recorded in po.Failed and does not stop the other targets.
+func (r *Reconciler) protect(ctx context.Context, p appProtector, procs []procView, po *ProtectorOutcome) {
+	po.Running = map[string]bool{}
+	for _, t := range p.targets() {
+		pids := matchingPIDs(procs, t.process)
+		up := len(pids) > 0
+		if up && t.port != "" && !r.listening(ctx, t.port) {
+			// Present but not serving: a zombie that reports healthy by
+			// name while blocking nothing. Clear it so the relaunch below
+			// can bind the port.
+			po.Wedged = append(po.Wedged, t.label)
+			for _, pid := range pids {
+				_ = r.kill(pid)
+			}
+			up = false
+		}
+		po.Running[t.label] = up
+		if up {
+			continue
+		}
+		if err := r.runLaunch(ctx, t.launch[0], t.launch[1:]...); err != nil {
+			po.Failed = append(po.Failed, fmt.Sprintf("%s: %v", t.label, err))
+		} else {
+			po.Relaunched = append(po.Relaunched, t.label)
+		}
+	}
+}
+
 // runLaunch bounds a single launch with launchTimeout so a hanging
 // relaunch can never stall the reconcile loop (acceptance #2). A caller
 // ctx that is already cancelled / has a shorter deadline is honoured.
@@ -206,17 +300,38 @@ func (r *Reconciler) runLaunch(ctx context.Context, name string, args ...string)
 // the lister supplied one, else on the basename — so a process reported
 // only by name still matches and "FreedomProxy" never matches "Freedom".
 func matchesAny(procs []procView, target string) bool {
+	return len(matchingPIDs(procs, target)) > 0
+}
+
+// matchingPIDs returns the pids of every process matchesAny would accept.
+func matchingPIDs(procs []procView, target string) []int {
 	base := filepath.Base(target)
+	var pids []int
 	for _, p := range procs {
 		if p.Path != "" {
 			if p.Path == target {
-				return true
+				pids = append(pids, p.PID)
 			}
 			continue
 		}
-		if p.Name != "" && filepath.Base(p.Name) == base {
-			return true
+		if p.Name != "" && nameMatches(filepath.Base(p.Name), base) {
+			pids = append(pids, p.PID)
 		}
 	}
-	return false
+	return pids
+}
+
+// commSignificant is how many leading characters of a process name the
+// darwin kernel keeps (P_comm); see listProcesses.
+const commSignificant = 15
+
+// nameMatches compares a process-table name against an executable basename,
+// accepting the kernel-truncated form of a long name ("Cold Turkey Bloc"
+// for "Cold Turkey Blocker") but never a short prefix ("Freedom" is not
+// "FreedomProxy").
+func nameMatches(name, base string) bool {
+	if name == base {
+		return true
+	}
+	return len(name) >= commSignificant && len(base) > len(name) && strings.HasPrefix(base, name)
 }
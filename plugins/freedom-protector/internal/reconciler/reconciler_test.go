@@ -27,7 +27,7 @@ type harness struct {
 
 func newHarness(procs []procView, present bool, launchErr map[string]error) *harness {
 	h := &harness{}
-	r := New(Options{})
+	r := newTestReconciler(Options{})
 	r.stat = func(string) bool { return present }
 	r.list = func() ([]procView, error) { return procs, nil }
 	r.launch = func(_ context.Context, name string, args ...string) error {
@@ -43,6 +43,17 @@ func newHarness(procs []procView, present bool, launchErr map[string]error) *har
 	return h
 }
 
+// newTestReconciler is New with the network/kill/settings seams faked: the
+// proxy port accepts, nothing is killed, and the system proxy is unreadable.
+// Reconcile tests start here so no real process is ever signalled.
+func newTestReconciler(opts Options) *Reconciler {
+	r := New(opts)
+	r.dial = func(context.Context, string) error { return nil }
+	r.kill = func(int) error { return errors.New("kill not faked") }
+	r.proxySettings = func(context.Context) (string, error) { return "", errors.New("no scutil") }
+	return r
+}
+
 func appProc() procView   { return procView{PID: 100, Path: DefaultAppProcess} }
 func proxyProc() procView { return procView{PID: 200, Path: DefaultProxyProcess} }
 
@@ -174,7 +185,7 @@ func TestMatchesAny_PathAndNameFallback(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestReconcile_LaunchHangDoesNotStall(t *testing.T) {
-	r := New(Options{})
+	r := newTestReconciler(Options{})
 	r.stat = func(string) bool { return true }
 	r.list = func() ([]procView, error) { return nil, nil } // both down
 
@@ -215,7 +226,7 @@ func TestReconcile_LaunchHangDoesNotStall(t *testing.T) {
 }
 
 func TestReconcile_EnumerationErrorIsClean(t *testing.T) {
-	r := New(Options{})
+	r := newTestReconciler(Options{})
 	r.stat = func(string) bool { return true }
 	r.list = func() ([]procView, error) { return nil, errors.New("procfs boom") }
 	_, err := r.Reconcile(context.Background())
@@ -226,7 +237,7 @@ func TestReconcile_EnumerationErrorIsClean(t *testing.T) {
 
 func TestReconcile_SkipsCleanlyWhenFreedomAbsent(t *testing.T) {
 	calls := 0
-	r := New(Options{})
+	r := newTestReconciler(Options{})
 	r.stat = func(string) bool { return false } // not installed
 	r.list = func() ([]procView, error) { calls++; return nil, nil }
 	r.launch = func(context.Context, string, ...string) error { calls++; return nil }
@@ -310,6 +321,248 @@ func TestOptionsOverrideDefaults(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Protectors — one loop over every enabled app; absent apps skip on their own
+// without hiding the installed ones.
+// ---------------------------------------------------------------------------
+
+func TestReconcile_MultipleProtectors(t *testing.T) {
+	ct := "/Applications/Cold Turkey Blocker.app"
+	r := newTestReconciler(Options{Protectors: []string{ProtectorFreedom, ProtectorColdTurkey, Protector1Focus}})
+	r.stat = func(p string) bool { return p != "/Applications/1Focus.app" }
+	// Freedom fully up; Cold Turkey down (a truncated P_comm of another
+	// app must not count as it).
+	r.list = func() ([]procView, error) {
+		return []procView{appProc(), proxyProc(), {PID: 3, Name: "Cold Turkey Help"}}, nil
+	}
+	var calls []launchCall
+	r.launch = func(_ context.Context, name string, args ...string) error {
+		calls = append(calls, launchCall{name: name, args: args})
+		return nil
+	}
+
+	out, err := r.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if out.Skipped || !out.AppRunning || !out.ProxyRunning {
+		t.Errorf("freedom view = %+v", out)
+	}
+	if !equalStrings(out.Relaunched, []string{ProtectorColdTurkey}) {
+		t.Errorf("relaunched = %v, want only cold-turkey", out.Relaunched)
+	}
+	assertCalls(t, calls, []launchCall{{name: "open", args: []string{"-a", ct}}})
+	if len(out.Protectors) != 3 || out.Protectors[2].ID != Protector1Focus || !out.Protectors[2].Skipped {
+		t.Errorf("per-protector outcomes = %+v, want 1focus skipped last", out.Protectors)
+	}
+}
+
+func TestReconcile_AllProtectorsAbsentSkips(t *testing.T) {
+	r := newTestReconciler(Options{Protectors: []string{ProtectorOneSec, ProtectorSelfControl}})
+	r.stat = func(string) bool { return false }
+	r.list = func() ([]procView, error) { t.Fatal("must not scan when nothing is installed"); return nil, nil }
+	out, err := r.Reconcile(context.Background())
+	if err != nil || !out.Skipped || !contains(out.SkipReason, "SelfControl.app") {
+		t.Fatalf("want benign skip naming both apps, got %+v err=%v", out, err)
+	}
+}
+
+func TestBuildProtectors(t *testing.T) {
+	r := New(Options{
+		Protectors: []string{ProtectorColdTurkey, "nope", ProtectorColdTurkey},
+		AppPaths:   map[string]string{ProtectorColdTurkey: "/Users/me/Apps/CT.app"},
+	})
+	if len(r.protectors) != 1 {
+		t.Fatalf("unknown/repeated ids must be dropped, got %d protectors", len(r.protectors))
+	}
+	tg := r.protectors[0].targets()
+	if r.protectors[0].bundle() != "/Users/me/Apps/CT.app" || tg[0].process != "/Users/me/Apps/CT.app/Contents/MacOS/CT" {
+		t.Errorf("app_paths override not applied: bundle=%s targets=%+v", r.protectors[0].bundle(), tg)
+	}
+	if def := New(Options{}); len(def.protectors) != 1 || def.protectors[0].id() != ProtectorFreedom {
+		t.Errorf("default must be Freedom only")
+	}
+}
+
+func TestNameMatches(t *testing.T) {
+	tests := []struct {
+		name, base string
+		want       bool
+	}{
+		{"Freedom", "Freedom", true},
+		{"Freedom", "FreedomProxy", false},
+		{"Cold Turkey Bloc", "Cold Turkey Blocker", true},
+		{"Cold Turkey Blo", "Cold Turkey Blocker", true},
+		{"Cold Turkey Help", "Cold Turkey Blocker", false},
+	}
+	for _, tc := range tests {
+		if got := nameMatches(tc.name, tc.base); got != tc.want {
+			t.Errorf("nameMatches(%q, %q) = %v, want %v", tc.name, tc.base, got, tc.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// App cache — a deleted bundle comes back from its archive; an installed one
+// is archived only when missing or changed.
+// ---------------------------------------------------------------------------
+
+func TestReconcile_RestoresDeletedAppFromCache(t *testing.T) {
+	r := newTestReconciler(Options{CacheDir: "/cache"})
+	zip := cachePath("/cache", r.protectors[0])
+	present := map[string]bool{zip: true}
+	r.stat = func(p string) bool { return present[p] }
+	r.list = func() ([]procView, error) { return nil, nil }
+	r.launch = func(context.Context, string, ...string) error { return nil }
+	var extracted string
+	r.extract = func(_ context.Context, z, dir string) error {
+		extracted = z + " -> " + dir
+		present[DefaultAppPath] = true
+		return nil
+	}
+	r.archive = func(context.Context, string, string) error {
+		t.Fatal("a just-restored app must not be re-archived")
+		return nil
+	}
+
+	out, err := r.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if extracted != zip+" -> /Applications" {
+		t.Errorf("extract = %q", extracted)
+	}
+	if out.Skipped || !out.Protectors[0].Restored {
+		t.Errorf("want restored, not skipped: %+v", out)
+	}
+	if !equalStrings(out.Relaunched, []string{"app", "proxy"}) {
+		t.Errorf("restored app must be relaunched, got %v", out.Relaunched)
+	}
+}
+
+func TestReconcile_RestoreFailureStillSkips(t *testing.T) {
+	r := newTestReconciler(Options{CacheDir: "/cache"})
+	zip := cachePath("/cache", r.protectors[0])
+	r.stat = func(p string) bool { return p == zip }
+	r.extract = func(context.Context, string, string) error { return errors.New("disk full") }
+	out, err := r.Reconcile(context.Background())
+	if err != nil || !out.Skipped || len(out.Failed) != 1 || !contains(out.Failed[0], "restore") {
+		t.Fatalf("want skip with a recorded restore failure, got %+v err=%v", out, err)
+	}
+}
+
+func TestReconcile_RefreshesCacheOnlyWhenStale(t *testing.T) {
+	tests := []struct {
+		name      string
+		haveZip   bool
+		changed   bool
+		wantArchv bool
+	}{
+		{"no archive yet", false, false, true},
+		{"archive up to date", true, false, false},
+		{"app updated since", true, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness([]procView{appProc(), proxyProc()}, true, nil)
+			r := h.r
+			r.cacheDir = "/cache"
+			zip := cachePath("/cache", r.protectors[0])
+			r.stat = func(p string) bool { return p != zip || tc.haveZip }
+			r.newer = func(string, string) bool { return tc.changed }
+			archived := false
+			r.archive = func(_ context.Context, bundle, z string) error {
+				archived = bundle == DefaultAppPath && z == zip
+				return nil
+			}
+			if _, err := r.Reconcile(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if archived != tc.wantArchv {
+				t.Errorf("archived = %v, want %v", archived, tc.wantArchv)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Listener health — a FreedomProxy that is running but not accepting on its
+// port is a zombie: killed and relaunched, not reported healthy.
+// ---------------------------------------------------------------------------
+
+func TestReconcile_WedgedProxyIsKilledAndRelaunched(t *testing.T) {
+	h := newHarness([]procView{appProc(), proxyProc()}, true, nil)
+	var dialed string
+	h.r.dial = func(_ context.Context, addr string) error {
+		dialed = addr
+		return errors.New("connection refused")
+	}
+	var killed []int
+	h.r.kill = func(pid int) error { killed = append(killed, pid); return nil }
+
+	out, err := h.r.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if dialed != "127.0.0.1:"+DefaultProxyPort {
+		t.Errorf("probe dialed %q", dialed)
+	}
+	if out.ProxyRunning || !out.AppRunning {
+		t.Errorf("wedged proxy must not count as running: %+v", out)
+	}
+	if !reflect.DeepEqual(killed, []int{200}) || !equalStrings(out.Protectors[0].Wedged, []string{"proxy"}) {
+		t.Errorf("killed=%v wedged=%v, want proxy pid 200", killed, out.Protectors[0].Wedged)
+	}
+	assertCalls(t, h.calls, []launchCall{
+		{name: DefaultProxyProcess, args: []string{"-port", DefaultProxyPort, "-rpcport", DefaultProxyRPCPort}},
+	})
+}
+
+func TestSystemProxyVerdict(t *testing.T) {
+	const routed = `<dictionary> {
+  HTTPEnable : 1
+  HTTPPort : 7769
+  HTTPProxy : 127.0.0.1
+}`
+	tests := []struct {
+		name, in, want string
+	}{
+		{"http routed", routed, SystemProxyOK},
+		{"https routed via localhost", "HTTPSEnable : 1\nHTTPSPort : 7769\nHTTPSProxy : localhost", SystemProxyOK},
+		{"pac names port", "ProxyAutoConfigEnable : 1\nProxyAutoConfigURLString : http://127.0.0.1:7769/proxy.pac", SystemProxyOK},
+		{"disabled", strings.Replace(routed, "HTTPEnable : 1", "HTTPEnable : 0", 1), SystemProxyBypass},
+		{"other port", strings.Replace(routed, "7769", "8080", 1), SystemProxyBypass},
+		{"remote host", strings.Replace(routed, "127.0.0.1", "10.0.0.1", 1), SystemProxyBypass},
+		{"empty", "", SystemProxyUnknown},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := systemProxyVerdict(tc.in, DefaultProxyPort); got != tc.want {
+				t.Errorf("verdict = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReconcile_ReportsSystemProxy(t *testing.T) {
+	h := newHarness([]procView{appProc(), proxyProc()}, true, nil)
+	h.r.proxySettings = func(context.Context) (string, error) {
+		return "HTTPEnable : 1\nHTTPPort : 1\nHTTPProxy : 127.0.0.1\n", nil
+	}
+	out, _ := h.r.Reconcile(context.Background())
+	if out.SystemProxy != SystemProxyBypass {
+		t.Errorf("SystemProxy = %q, want %q", out.SystemProxy, SystemProxyBypass)
+	}
+
+	only := newTestReconciler(Options{Protectors: []string{ProtectorColdTurkey}})
+	only.stat = func(string) bool { return true }
+	only.list = func() ([]procView, error) { return nil, nil }
+	only.launch = func(context.Context, string, ...string) error { return nil }
+	if out, _ := only.Reconcile(context.Background()); out.SystemProxy != "" {
+		t.Errorf("no Freedom protector ⇒ no system-proxy verdict, got %q", out.SystemProxy)
+	}
+}
+
 // New must wire the real OS seams.
 func TestNewWiresRealSeams(t *testing.T) {
 	r := New(Options{})
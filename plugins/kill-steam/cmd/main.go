@@ -1,9 +1,28 @@
 // Command kill-steam is a focusd job plugin that terminates Steam/Dota2
-// processes. It follows the platform plugin contract:
+// processes, and polices known blocker-bypass tools (package bypass). It
+// follows the platform plugin contract:
 //
 //	kill-steam run --config <path-to-job-config.json>
+//	kill-steam test --root <dir> --procs <file> [--config <path>]
+//
+// Steam or Dota 2 run under Wine/CrossOver, or in a Parallels, UTM, VMware
+// or VirtualBox VM named for them, is matched by the wrapper's arguments;
+// the wrapper is killed and logged as a bypass attempt.
+//
+// test simulates a pass: the path targets resolve under --root and the
+// process patterns match the names in --procs (one per line). It prints
+// the matches and touches nothing.
+//
+// Input  : JSON file {job_id, plugin_id, config:{process_names?:[...],
+//
+//	bypass_mode?: off|detect|kill, bypass_allowlist?:[...],
+//	notify_on_block?: bool, severity?: low|medium|high,
+//	escalate_after?: int, exclude?: [...],
+//	actions?: [kill, delete, restrict],
+//	paths?: ["{{Home}}/...", "{{SteamLibrary}}/..."],
+//	external_libraries?: bool, tombstone_days?: int,
+//	grace_seconds?: int, daily_allowance?: {"<app>": minutes}}}
 //
-// Input  : JSON file {job_id, plugin_id, config:{process_names?:[...]}}
 // Output : JSON result on stdout, diagnostics on stderr
 // Exit   : 0 success · 1 controlled failure (some kills failed) · 2 error
 package main
@@ -15,8 +34,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/allowance"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/backup"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/bypass"
 	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/killer"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/netcut"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/notice"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/severity"
 	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/uninstaller"
 )
 
@@ -43,6 +72,9 @@ func run(args []string) int {
 		fmt.Println("kill-steam", version)
 		return 0
 	}
+	if len(args) >= 1 && args[0] == "test" {
+		return simulate(args[1:])
+	}
 	if len(args) < 1 || args[0] != "run" {
 		fmt.Fprintln(os.Stderr, "usage: kill-steam run --config <path>")
 		return 2
@@ -54,6 +86,10 @@ func run(args []string) int {
 		return 2
 	}
 
+	// The signed job timeout runs from here; the removal pass must end
+	// inside it, after the grace countdown and the kill, with time left to
+	// report.
+	started := time.Now()
 	raw, err := readJobConfig(*cfgPath)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "config error:", err)
@@ -61,39 +97,249 @@ func run(args []string) int {
 		return 2
 	}
 	names, err := loadNames(raw)
+	if err == nil {
+		err = checkBypassConfig(raw)
+	}
+	var notifyOn bool
+	if err == nil {
+		notifyOn, err = loadNotify(raw)
+	}
+	var base severity.Level
+	var every int
+	if err == nil {
+		base, every, err = loadSeverity(raw)
+	}
+	var exclude, paths []string
+	if err == nil {
+		exclude, err = loadExclude(raw)
+	}
+	if err == nil {
+		paths, err = loadPaths(raw)
+	}
+	var acts actions
+	if err == nil {
+		acts, err = loadActions(raw)
+	}
+	var tombstoneDays int
+	if err == nil {
+		tombstoneDays, err = loadTombstoneDays(raw)
+	}
+	var volumes bool
+	if err == nil {
+		volumes, err = loadExternalLibraries(raw)
+	}
+	var grace int
+	if err == nil {
+		grace, err = loadGrace(raw)
+	}
+	var limits map[string]int
+	if err == nil {
+		limits, err = loadAllowance(raw)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "config error:", err)
 		emit(result{Status: "error", Message: err.Error()})
 		return 2
 	}
 
-	// Phase 1 — kill any live Steam/Dota processes (the existing logic).
-	out, err := killer.New(names).Run()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "kill error:", err)
-		emit(result{Status: "error", Message: err.Error()})
-		return 2
+	// Phase 0 — grade the response. Below high, escalation needs the
+	// launches seen this hour, so look before acting; a run that may not
+	// kill looks so it can report.
+	k := killer.New(names)
+	// A limit policy: an app with daily_allowance left is metered and
+	// spared; once it is spent the app is handled like any other.
+	var used, left map[string]time.Duration
+	if len(limits) > 0 {
+		if used, left, err = meterAllowance(k, limits); err != nil {
+			fmt.Fprintln(os.Stderr, "kill error:", err)
+			emit(result{Status: "error", Message: err.Error()})
+			return 2
+		}
+		k.Sparing(func(label string) bool {
+			_, ok := left[notice.AppLabel(label)]
+			return ok
+		})
+	}
+	level, launches := base, 0
+	var seen killer.Match
+	if base < severity.High || !acts.kill {
+		if seen, err = k.Detect(); err != nil {
+			fmt.Fprintln(os.Stderr, "kill error:", err)
+			emit(result{Status: "error", Message: err.Error()})
+			return 2
+		}
+		if every > 0 && base < severity.High {
+			launches, _ = ledgerNew().Observe(seen.PIDs) // unreadable ledger ⇒ no escalation yet
+			level = severity.Escalate(base, launches, every)
+		}
 	}
 
-	// Phase 2 — if Steam.app exists on disk, full auto-uninstall:
-	// remove the app + every user's Steam appdata + caches + launchd
+	// Phase 1 — kill any live Steam/Dota processes (medium and up, when
+	// kill is an action). With grace_seconds, a match is warned about and
+	// killed that many seconds later, in this run: the wait is fixed, so
+	// nothing the user does stretches it.
+	out := killer.Outcome{Scanned: seen.Scanned}
+	var warned []string
+	var graceErr error
+	if level >= severity.Medium && acts.kill {
+		if grace > 0 {
+			warned, graceErr = warnBeforeKill(k, &seen, grace)
+		}
+		if out, err = k.Run(); err != nil {
+			fmt.Fprintln(os.Stderr, "kill error:", err)
+			emit(result{Status: "error", Message: err.Error()})
+			return 2
+		}
+	}
+	// Phase 1b — restrict instead of kill (Linux): the matched processes
+	// stay up but lose outbound network.
+	var cut netcut.Outcome
+	if level >= severity.Medium && acts.restrict && !acts.kill {
+		cut = netNew().Restrict(seen.PIDs)
+	}
+
+	// Phase 2 — high only, when delete is an action: full auto-uninstall,
+	// removing the app + every user's Steam appdata + caches + launchd
 	// helper. Cheap when Steam is absent (one os.Stat → return).
-	un := (&uninstaller.Reconciler{}).Reconcile()
+	// Whatever is on disk is first kept out of Time Machine, and a copy
+	// restored from an older backup is flagged before the sweep removes it.
+	rec := &uninstaller.Reconciler{Exclude: exclude, Paths: paths, ScanVolumes: volumes,
+		Deadline: started.Add(uninstaller.DefaultBudget)}
+	guard := guardNew()
+	restored, tmExcluded, bkErr := guard.Observe(rec.Plan())
+	un := uninstaller.Outcome{Detected: rec.Detect(), Reason: "kept (severity " + level.String() + ")"}
+	switch {
+	case !acts.delete:
+		un.Reason = "kept (delete not in actions)"
+	case len(left) > 0:
+		// Deleting the install would take the allowance with it.
+		un.Reason = "kept (allowance left today)"
+	case level == severity.High:
+		un = rec.Reconcile()
+	}
+	if err := guard.Removed(un.Removed); err != nil && bkErr == nil {
+		bkErr = err
+	}
+	// A removed app bundle's path is locked with a placeholder for
+	// tombstone_days, then freed again; with the option off, any left are
+	// freed now.
+	tombstoned, tombCleared, tombErrs := rec.Tombstones(un.Removed,
+		time.Duration(tombstoneDays)*24*time.Hour, time.Now())
+
+	// Phase 3 — bypass tools (alt DNS clients, proxy managers, unblocker
+	// VPNs). Report-only unless config sets bypass_mode: kill; low, or
+	// actions without kill, never kills.
+	mode, allow := loadBypass(raw)
+	if (level == severity.Low || !acts.kill) && mode == bypass.ModeKill {
+		mode = bypass.ModeDetect
+	}
+	by, byErr := bypass.New(mode, allow).Run()
+
+	// The apps this run stopped, as a person names them: a single Steam
+	// launch kills several helpers, which is one blocked attempt, not five.
+	apps := blockedApps(out.KilledNames)
 
 	res := result{
 		Status: "ok",
 		Message: fmt.Sprintf("scanned=%d killed=%d uninstall_detected=%v removed=%d",
 			out.Scanned, out.KilledCount(), un.Detected, len(un.Removed)),
 		Details: map[string]any{
-			"scanned":            out.Scanned,
-			"killed_count":       out.KilledCount(),
-			"killed_pids":        out.KilledPIDs,
-			"uninstall_detected": un.Detected,
-			"uninstall_removed":  un.Removed,
-			"uninstall_errors":   un.Errors,
-			"uninstall_reason":   un.Reason,
+			"scanned":                   out.Scanned,
+			"killed_count":              out.KilledCount(),
+			"killed_pids":               out.KilledPIDs,
+			"skipped_pids":              out.SkippedPIDs,
+			"wrapped":                   out.Wrapped,
+			"uninstall_detected":        un.Detected,
+			"uninstall_removed":         un.Removed,
+			"uninstall_errors":          un.Errors,
+			"uninstall_reason":          un.Reason,
+			"uninstall_reclaimed_bytes": un.ReclaimedBytes,
+			"uninstall_reclaimed_files": un.ReclaimedFiles,
+			"uninstall_forensics":       un.Forensics,
+			"uninstall_excluded":        un.Excluded,
+			"uninstall_unfinished":      un.Unfinished,
+			"backup_restored":           restored,
+			"backup_excluded":           tmExcluded,
+			"tombstoned":                tombstoned,
+			"tombstones_cleared":        tombCleared,
+			"blocked_apps":              apps,
+			"bypass":                    by,
+			"severity":                  level.String(),
 		},
 	}
+	if level == severity.Low || !acts.kill {
+		res.Details["seen_apps"] = blockedApps(seen.Names)
+		if len(seen.Wrapped) > 0 {
+			res.Details["seen_wrapped"] = seen.Wrapped
+		}
+	}
+	for _, w := range out.Wrapped {
+		// A game under Wine or in a VM is a deliberate way round the
+		// name match: say so in the job log, not just the details.
+		fmt.Fprintf(os.Stderr, "kill-steam: bypass attempt: %s under %s (pid %d) killed\n", w.Runs, w.Layer, w.PID)
+	}
+	if n := len(out.Wrapped); n > 0 {
+		res.Message += fmt.Sprintf(" wrapped=%d", n)
+	}
+	if len(limits) > 0 {
+		res.Details["allowance_used_seconds"] = seconds(used)
+		res.Details["allowance_left_seconds"] = seconds(left)
+	}
+	if grace > 0 {
+		res.Details["grace_seconds"] = grace
+		res.Details["grace_warned"] = warned
+		if graceErr != nil {
+			// Best-effort: the kill goes ahead after the wait regardless.
+			res.Details["grace_error"] = graceErr.Error()
+		}
+	}
+	if every > 0 && base < severity.High {
+		res.Details["severity_base"] = base.String()
+		res.Details["launches_last_hour"] = launches
+	}
+	if acts.restrict && !acts.kill {
+		res.Details["net_restricted"] = cut.Restricted
+		if len(cut.Restricted) > 0 {
+			res.Details["restricted_apps"] = blockedApps(seen.Names)
+			res.Message += fmt.Sprintf(" net_restricted=%d", len(cut.Restricted))
+		}
+		if cut.Err != "" {
+			res.Details["restrict_error"] = cut.Err
+		}
+		if len(cut.Failed) > 0 {
+			res.Details["restrict_failed"] = cut.Failed
+		}
+	}
+	if byErr != nil {
+		res.Details["bypass_error"] = byErr.Error()
+	}
+	if len(tombErrs) > 0 {
+		// Best-effort too: the sweep still removes a reinstall next tick.
+		res.Details["tombstone_errors"] = tombErrs
+	}
+	if bkErr != nil {
+		// Best-effort, like notify: a missed exclusion is retried next run.
+		res.Details["backup_error"] = bkErr.Error()
+	}
+	if n := len(un.Unfinished); n > 0 {
+		// Partly removed within the per-path budget; the next tick goes on.
+		res.Message += fmt.Sprintf(" uninstall_unfinished=%d", n)
+	}
+	if len(restored) > 0 {
+		res.Message += fmt.Sprintf(" restored_from_backup=%d", len(restored))
+	}
+	if notifyOn {
+		sent, nerr := notifyBlocks(noticeNew(), apps, un)
+		res.Details["notified"] = sent
+		if nerr != nil {
+			// Best-effort: no GUI session (or no console user) must never
+			// fail enforcement that already succeeded.
+			res.Details["notify_error"] = nerr.Error()
+		}
+	}
+	if n := len(by.Detected); n > 0 {
+		res.Message += fmt.Sprintf(" bypass_detected=%d bypass_killed=%d", n, len(by.KilledPIDs))
+	}
 	if len(out.Failed) > 0 {
 		res.Status = "failed"
 		res.Message = fmt.Sprintf("killed %d, %d failed; %s",
@@ -102,7 +348,7 @@ func run(args []string) int {
 		emit(res)
 		return 1 // controlled failure
 	}
-	if len(un.Errors) > 0 {
+	if len(un.Errors) > 0 || len(by.Failed) > 0 || byErr != nil || cut.Err != "" {
 		res.Status = "failed"
 		emit(res)
 		return 1
@@ -111,6 +357,105 @@ func run(args []string) int {
 	return 0
 }
 
+// simulate is `kill-steam test`: the same matching as run, against a sandbox
+// tree and a process-name fixture, with every kill and removal skipped.
+// Exit 0 once it has reported, 2 on bad input.
+func simulate(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfgPath := fs.String("config", "", "path to resolved job config JSON (default: stdin)")
+	root := fs.String("root", "", "sandbox directory laid out like / (Applications, Users)")
+	procsPath := fs.String("procs", "", "process fixture: one process name per line")
+	if err := fs.Parse(args); err != nil || *root == "" {
+		fmt.Fprintln(os.Stderr, "usage: kill-steam test --root <dir> [--procs <file>] [--config <path>]")
+		return 2
+	}
+	raw, err := readJobConfig(*cfgPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config error:", err)
+		return 2
+	}
+	names, err := loadNames(raw)
+	if err == nil {
+		err = checkBypassConfig(raw)
+	}
+	var exclude, paths []string
+	if err == nil {
+		exclude, err = loadExclude(raw)
+	}
+	if err == nil {
+		paths, err = loadPaths(raw)
+	}
+	var volumes bool
+	if err == nil {
+		volumes, err = loadExternalLibraries(raw)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config error:", err)
+		return 2
+	}
+	var procs []string
+	if *procsPath != "" {
+		if procs, err = readProcs(*procsPath); err != nil {
+			fmt.Fprintln(os.Stderr, "procs error:", err)
+			return 2
+		}
+	}
+
+	out, _ := killer.New(names).WithProcesses(procs).Run()
+	mode, allow := loadBypass(raw)
+	by, _ := bypass.New(mode, allow).WithProcesses(procs).Run()
+	matched, bypassNames, pathMatches := append([]string{}, out.KilledNames...), []string{}, []string{}
+	for _, f := range by.Detected {
+		bypassNames = append(bypassNames, f.Name)
+	}
+	rec := uninstaller.Rooted(*root)
+	rec.Exclude, rec.Paths, rec.ScanVolumes = exclude, paths, volumes
+	rootRel := func(p string) string {
+		if rel, rerr := filepath.Rel(*root, p); rerr == nil {
+			return "/" + filepath.ToSlash(rel)
+		}
+		return p
+	}
+	for _, p := range rec.Plan() {
+		pathMatches = append(pathMatches, rootRel(p))
+	}
+	excluded := []string{}
+	for _, p := range rec.Spared() {
+		excluded = append(excluded, rootRel(p))
+	}
+	emit(result{
+		Status: "ok",
+		Message: fmt.Sprintf("simulated: %d process matches, %d bypass matches, %d path matches",
+			len(matched), len(bypassNames), len(pathMatches)),
+		Details: map[string]any{
+			"process_matches": matched,
+			"bypass_matches":  bypassNames,
+			"bypass_mode":     by.Mode,
+			"path_matches":    pathMatches,
+			"path_excluded":   excluded,
+		},
+	})
+	return 0
+}
+
+// readProcs reads a process fixture: one name per line; blank lines and
+// #-comments are skipped. Names may contain spaces ("Steam Helper").
+func readProcs(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, nil
+}
+
 // readJobConfig returns the job-config JSON bytes: from --config <path>
 // (compat) when set, else drained from stdin (the disguised path — the
 // config path never appears in this process's argv). Empty/absent => nil
@@ -157,6 +502,386 @@ func loadNames(raw []byte) ([]string, error) {
 	return names, nil
 }
 
+// loadBypass reads config.bypass_mode and config.bypass_allowlist (both
+// optional; validated by checkBypassConfig). Absent ⇒ detect, no allowlist.
+func loadBypass(raw []byte) (mode string, allow []string) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return bypass.ModeDetect, nil
+	}
+	mode, _ = in.Config["bypass_mode"].(string)
+	if list, ok := in.Config["bypass_allowlist"].([]any); ok {
+		for _, e := range list {
+			if s, ok := e.(string); ok {
+				allow = append(allow, s)
+			}
+		}
+	}
+	return mode, allow
+}
+
+// checkBypassConfig rejects a bypass_mode typo (which would otherwise fall
+// back to detect and silently stop killing) and a malformed allowlist.
+func checkBypassConfig(raw []byte) error {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return nil // loadNames already reported a parse error
+	}
+	if v, ok := in.Config["bypass_mode"]; ok {
+		if m, _ := v.(string); !bypass.ValidMode(m) {
+			return fmt.Errorf("config.bypass_mode must be one of off, detect, kill")
+		}
+	}
+	if v, ok := in.Config["bypass_allowlist"]; ok {
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("config.bypass_allowlist must be a list of strings")
+		}
+		for _, e := range arr {
+			if _, ok := e.(string); !ok {
+				return fmt.Errorf("config.bypass_allowlist entries must be strings")
+			}
+		}
+	}
+	return nil
+}
+
+// loadSeverity reads config.severity (low|medium|high, default high) and
+// config.escalate_after (launches per hour that raise it a level; 0 or
+// absent ⇒ never).
+func loadSeverity(raw []byte) (severity.Level, int, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return severity.High, 0, nil
+	}
+	level := severity.High
+	if v, ok := in.Config["severity"]; ok {
+		s, _ := v.(string)
+		var err error
+		if level, err = severity.Parse(s); err != nil || s == "" {
+			return severity.High, 0, fmt.Errorf("config.severity must be one of low, medium, high")
+		}
+	}
+	every := 0
+	if v, ok := in.Config["escalate_after"]; ok {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n != float64(int(n)) {
+			return severity.High, 0, fmt.Errorf("config.escalate_after must be a whole number of launches")
+		}
+		every = int(n)
+	}
+	return level, every, nil
+}
+
+// actions is what a run may do on a match. Severity picks from these; an
+// action left out never happens, escalation included. restrict is the
+// softer kill: where a kill would happen, matched processes are cut off
+// the network instead; with kill also listed, kill wins.
+type actions struct{ kill, delete, restrict bool }
+
+// loadActions reads config.actions, a subset of kill, delete and restrict;
+// absent means kill and delete. restrict is rejected where it cannot work.
+func loadActions(raw []byte) (actions, error) {
+	all := actions{kill: true, delete: true}
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return all, nil
+	}
+	v, ok := in.Config["actions"]
+	if !ok {
+		return all, nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return all, fmt.Errorf("config.actions must be a list of kill, delete, restrict")
+	}
+	var a actions
+	for _, e := range arr {
+		switch e {
+		case "kill":
+			a.kill = true
+		case "delete":
+			a.delete = true
+		case "restrict":
+			if !netcut.Supported {
+				return all, fmt.Errorf("config.actions: restrict needs Linux (nftables, cgroup v2)")
+			}
+			a.restrict = true
+		default:
+			return all, fmt.Errorf("config.actions entries must be kill, delete or restrict")
+		}
+	}
+	return a, nil
+}
+
+// loadTombstoneDays reads config.tombstone_days: how long a removed app's
+// location stays locked (0 or absent ⇒ no tombstones).
+func loadTombstoneDays(raw []byte) (int, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return 0, nil
+	}
+	v, ok := in.Config["tombstone_days"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n < 0 || n > uninstaller.MaxTombstoneDays || n != float64(int(n)) {
+		return 0, fmt.Errorf("config.tombstone_days must be a whole number of days, 0..%d", uninstaller.MaxTombstoneDays)
+	}
+	return int(n), nil
+}
+
+// loadExclude reads config.exclude: glob patterns for artifacts the sweep
+// must leave alone (see uninstaller.Reconciler.Exclude).
+func loadExclude(raw []byte) ([]string, error) {
+	return loadPatterns(raw, "exclude", uninstaller.ValidExclude)
+}
+
+// loadPaths reads config.paths: templated glob patterns the sweep removes
+// in addition to the Steam targets (see uninstaller.Reconciler.Paths).
+func loadPaths(raw []byte) ([]string, error) {
+	return loadPatterns(raw, "paths", uninstaller.ValidPaths)
+}
+
+// loadPatterns reads config[key] as a list of non-empty patterns that
+// valid accepts; absent ⇒ none.
+func loadPatterns(raw []byte, key string, valid func([]string) error) ([]string, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return nil, nil
+	}
+	v, ok := in.Config[key]
+	if !ok {
+		return nil, nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("config.%s must be a list of glob patterns", key)
+	}
+	patterns := make([]string, 0, len(arr))
+	for _, e := range arr {
+		s, ok := e.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("config.%s entries must be non-empty strings", key)
+		}
+		patterns = append(patterns, s)
+	}
+	if err := valid(patterns); err != nil {
+		return nil, fmt.Errorf("config.%s: %w", key, err)
+	}
+	return patterns, nil
+}
+
+// loadExternalLibraries reads config.external_libraries (optional bool,
+// default on): whether the sweep also covers Steam libraries on mounted
+// volumes (see uninstaller.Reconciler.ScanVolumes).
+func loadExternalLibraries(raw []byte) (bool, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return true, nil
+	}
+	v, ok := in.Config["external_libraries"]
+	if !ok {
+		return true, nil
+	}
+	on, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("config.external_libraries must be true or false")
+	}
+	return on, nil
+}
+
+// loadAllowance reads config.daily_allowance: minutes per day an app (as
+// notifications name it: "Steam", "Dota 2") may run before it is blocked.
+// Absent ⇒ no limit policy; every match is blocked at once.
+func loadAllowance(raw []byte) (map[string]int, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return nil, nil
+	}
+	v, ok := in.Config["daily_allowance"]
+	if !ok {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(`config.daily_allowance must map apps to minutes, e.g. {"Dota 2": 60}`)
+	}
+	limits := make(map[string]int, len(m))
+	for app, v := range m {
+		n, ok := v.(float64)
+		if app == "" || !ok || n < 1 || n > allowance.MaxMinutes || n != float64(int(n)) {
+			return nil, fmt.Errorf("config.daily_allowance[%q] must be a whole number of minutes, 1..%d", app, allowance.MaxMinutes)
+		}
+		limits[app] = int(n)
+	}
+	return limits, nil
+}
+
+// meterAllowance samples which limited apps are running and returns
+// today's use and the allowance left, per app. An unwritable meter still
+// answers from what it read; only a failed scan is an error.
+func meterAllowance(k *killer.Killer, limits map[string]int) (used, left map[string]time.Duration, err error) {
+	running, err := k.Detect()
+	if err != nil {
+		return nil, nil, err
+	}
+	var limited []string
+	for _, app := range blockedApps(running.Names) {
+		if _, ok := limits[app]; ok {
+			limited = append(limited, app)
+		}
+	}
+	used, _ = allowanceNew().Sample(limited)
+	return used, allowance.Left(limits, used), nil
+}
+
+// seconds renders durations per app as whole seconds, for details.
+func seconds(d map[string]time.Duration) map[string]int64 {
+	out := make(map[string]int64, len(d))
+	for app, v := range d {
+		out[app] = int64(v / time.Second)
+	}
+	return out
+}
+
+// MaxGraceSeconds caps grace_seconds: the countdown is a moment to save
+// work, not a way to keep playing, and it must fit the job's timeout.
+const MaxGraceSeconds = 10
+
+// loadGrace reads config.grace_seconds: how long a matched app is warned
+// about before it is killed (0 or absent ⇒ killed at once).
+func loadGrace(raw []byte) (int, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return 0, nil
+	}
+	v, ok := in.Config["grace_seconds"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n < 0 || n > MaxGraceSeconds || n != float64(int(n)) {
+		return 0, fmt.Errorf("config.grace_seconds must be a whole number of seconds, 0..%d", MaxGraceSeconds)
+	}
+	return int(n), nil
+}
+
+// warnBeforeKill posts a countdown for each app matching now and waits
+// out grace seconds; nothing matching means no warning and no wait. seen
+// is filled in when the run has not looked yet. Returns the apps warned
+// about and the first failed post.
+func warnBeforeKill(k *killer.Killer, seen *killer.Match, grace int) ([]string, error) {
+	if seen.PIDs == nil {
+		m, err := k.Detect()
+		if err != nil {
+			return nil, err
+		}
+		*seen = m
+	}
+	apps := blockedApps(seen.Names)
+	if len(apps) == 0 {
+		return apps, nil
+	}
+	n := noticeNew()
+	var first error
+	for _, app := range apps {
+		if _, err := n.Closing(app, grace); err != nil && first == nil {
+			first = err
+		}
+	}
+	sleep(time.Duration(grace) * time.Second)
+	return apps, first
+}
+
+// sleep is a seam so tests never wait out a grace period.
+var sleep = time.Sleep
+
+// loadNotify reads config.notify_on_block (optional bool, default off).
+func loadNotify(raw []byte) (bool, error) {
+	var in jobInput
+	if len(raw) == 0 || json.Unmarshal(raw, &in) != nil {
+		return false, nil
+	}
+	v, ok := in.Config["notify_on_block"]
+	if !ok {
+		return false, nil
+	}
+	on, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("config.notify_on_block must be true or false")
+	}
+	return on, nil
+}
+
+// noticeNew is a seam so tests never post a real notification. The tally
+// sits beside freedom-protector's cache, under the running user's cache dir.
+var noticeNew = func() blockNotifier { return notice.New(stateDir()) }
+
+// ledgerNew is the escalation ledger, beside the notice tally.
+var ledgerNew = func() *severity.Ledger { return severity.NewLedger(stateDir()) }
+
+// allowanceNew is the daily-allowance meter, beside the escalation ledger.
+var allowanceNew = func() *allowance.Meter { return allowance.New(stateDir()) }
+
+// guardNew is the backup guard, beside the escalation ledger.
+var guardNew = func() *backup.Guard { return backup.New(stateDir()) }
+
+// netNew is a seam so tests never touch cgroups or nftables.
+var netNew = func() restrictor { return netcut.New() }
+
+type restrictor interface {
+	Restrict(pids []int) netcut.Outcome
+}
+
+func stateDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, ".lcache")
+}
+
+type blockNotifier interface {
+	Blocked(app string) (string, error)
+	InstallBlocked(app string) (string, error)
+	Closing(app string, secs int) (string, error)
+}
+
+// blockedApps maps killed process names to distinct app labels, in order.
+func blockedApps(killed []string) []string {
+	apps := []string{}
+	for _, name := range killed {
+		if app := notice.AppLabel(name); !slices.Contains(apps, app) {
+			apps = append(apps, app)
+		}
+	}
+	return apps
+}
+
+// notifyBlocks posts one notification per blocked app and one per removed
+// install. Returns the messages posted and the first error.
+func notifyBlocks(n blockNotifier, apps []string, un uninstaller.Outcome) ([]string, error) {
+	var sent []string
+	var first error
+	record := func(msg string, err error) {
+		if err != nil && first == nil {
+			first = err
+		}
+		if err == nil {
+			sent = append(sent, msg)
+		}
+	}
+	for _, app := range apps {
+		record(n.Blocked(app))
+	}
+	if len(un.Removed) > 0 {
+		record(n.InstallBlocked("Steam"))
+	}
+	return sent, first
+}
+
 func emit(r result) {
 	b, _ := json.Marshal(r)
 	fmt.Println(string(b))
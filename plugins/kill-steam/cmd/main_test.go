@@ -1,9 +1,21 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/allowance"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/killer"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/netcut"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/severity"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/uninstaller"
 )
 
 // writeF writes a test fixture file, failing fast on I/O error so a
@@ -156,3 +168,399 @@ func TestRunErrorOnBadStdinConfig(t *testing.T) {
 		t.Errorf("bad stdin config exit = %d, want 2", code)
 	}
 }
+
+func TestBypassConfig(t *testing.T) {
+	mode, allow := loadBypass([]byte(`{"config":{"bypass_mode":"kill","bypass_allowlist":["tor"]}}`))
+	if mode != "kill" || len(allow) != 1 || allow[0] != "tor" {
+		t.Errorf("got mode=%q allow=%v", mode, allow)
+	}
+	if mode, _ := loadBypass(nil); mode != "detect" {
+		t.Errorf("no config => detect, got %q", mode)
+	}
+	for _, bad := range []string{
+		`{"config":{"bypass_mode":"kil"}}`,
+		`{"config":{"bypass_allowlist":"tor"}}`,
+		`{"config":{"bypass_allowlist":[1]}}`,
+	} {
+		if err := checkBypassConfig([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "job.json")
+	writeF(t, cfg, `{"config":{"bypass_mode":"nope"}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad bypass_mode exit = %d, want 2", code)
+	}
+}
+
+type fakeNotice struct{ calls []string }
+
+func (f *fakeNotice) Blocked(app string) (string, error) {
+	f.calls = append(f.calls, "blocked "+app)
+	return app, nil
+}
+
+func (f *fakeNotice) InstallBlocked(app string) (string, error) {
+	f.calls = append(f.calls, "install "+app)
+	return app + " install", nil
+}
+
+func (f *fakeNotice) Closing(app string, secs int) (string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("closing %s in %d", app, secs))
+	return app, nil
+}
+
+func TestNotifyBlocksOncePerApp(t *testing.T) {
+	f := &fakeNotice{}
+	apps := blockedApps([]string{"steam_osx", "steamwebhelper", "Steam Helper", "dota2"})
+	un := uninstaller.Outcome{Removed: []string{"a", "b"}}
+	sent, err := notifyBlocks(f, apps, un)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "blocked Steam,blocked Dota 2,install Steam"
+	if got := strings.Join(f.calls, ","); got != want || len(sent) != 3 {
+		t.Fatalf("calls = %q (sent %v), want %q", got, sent, want)
+	}
+	f.calls = nil
+	if sent, _ := notifyBlocks(f, blockedApps(nil), uninstaller.Outcome{}); len(f.calls) != 0 || len(sent) != 0 {
+		t.Fatalf("a clean run must not notify: %v", f.calls)
+	}
+}
+
+func TestGraceConfig(t *testing.T) {
+	if g, err := loadGrace(nil); g != 0 || err != nil {
+		t.Errorf("no config => 0, got %d err=%v", g, err)
+	}
+	if g, err := loadGrace([]byte(`{"config":{"grace_seconds":10}}`)); g != 10 || err != nil {
+		t.Errorf("got %d err=%v", g, err)
+	}
+	for _, bad := range []string{
+		`{"config":{"grace_seconds":11}}`, // capped: no buying time
+		`{"config":{"grace_seconds":-1}}`,
+		`{"config":{"grace_seconds":2.5}}`,
+		`{"config":{"grace_seconds":"10"}}`,
+	} {
+		if _, err := loadGrace([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestWarnBeforeKill(t *testing.T) {
+	f := &fakeNotice{}
+	oldN, oldS := noticeNew, sleep
+	var slept time.Duration
+	noticeNew = func() blockNotifier { return f }
+	sleep = func(d time.Duration) { slept += d }
+	defer func() { noticeNew, sleep = oldN, oldS }()
+
+	var seen killer.Match
+	k := killer.New(nil).WithProcesses([]string{"Finder", "steam_osx", "steamwebhelper", "dota2"})
+	apps, err := warnBeforeKill(k, &seen, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(f.calls, ","); got != "closing Steam in 10,closing Dota 2 in 10" || len(apps) != 2 {
+		t.Fatalf("calls = %q apps = %v", got, apps)
+	}
+	if slept != 10*time.Second || len(seen.PIDs) != 3 {
+		t.Fatalf("slept %v, seen %+v", slept, seen)
+	}
+
+	f.calls, slept, seen = nil, 0, killer.Match{}
+	if _, err := warnBeforeKill(killer.New(nil).WithProcesses([]string{"Finder"}), &seen, 10); err != nil || len(f.calls) != 0 || slept != 0 {
+		t.Fatalf("nothing running must neither warn nor wait: calls %v slept %v", f.calls, slept)
+	}
+}
+
+func TestAllowanceConfig(t *testing.T) {
+	if l, err := loadAllowance(nil); l != nil || err != nil {
+		t.Errorf("no config => no limit, got %v err=%v", l, err)
+	}
+	if l, err := loadAllowance([]byte(`{"config":{"daily_allowance":{"Dota 2":60}}}`)); l["Dota 2"] != 60 || err != nil {
+		t.Errorf("got %v err=%v", l, err)
+	}
+	for _, bad := range []string{
+		`{"config":{"daily_allowance":60}}`,
+		`{"config":{"daily_allowance":{"Dota 2":0}}}`,
+		`{"config":{"daily_allowance":{"Dota 2":1441}}}`,
+		`{"config":{"daily_allowance":{"Dota 2":"1h"}}}`,
+	} {
+		if _, err := loadAllowance([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestMeterAllowanceSparesUntilSpent(t *testing.T) {
+	dir := t.TempDir()
+	old := allowanceNew
+	allowanceNew = func() *allowance.Meter { return allowance.New(dir) }
+	defer func() { allowanceNew = old }()
+
+	k := killer.New(nil).WithProcesses([]string{"steam_osx", "dota2"})
+	used, left, err := meterAllowance(k, map[string]int{"Dota 2": 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used["Dota 2"] != 0 || left["Dota 2"] != time.Hour || len(left) != 1 {
+		t.Fatalf("used %v left %v", used, left)
+	}
+	if _, ok := used["Steam"]; ok {
+		t.Fatal("an app without an allowance must not be metered")
+	}
+}
+
+func TestExternalLibrariesConfig(t *testing.T) {
+	if on, err := loadExternalLibraries(nil); !on || err != nil {
+		t.Errorf("no config => on, got on=%v err=%v", on, err)
+	}
+	if on, err := loadExternalLibraries([]byte(`{"config":{"external_libraries":false}}`)); on || err != nil {
+		t.Errorf("got on=%v err=%v", on, err)
+	}
+	if _, err := loadExternalLibraries([]byte(`{"config":{"external_libraries":"no"}}`)); err == nil {
+		t.Error("a non-bool external_libraries must be a config error")
+	}
+}
+
+func TestNotifyConfig(t *testing.T) {
+	if on, err := loadNotify([]byte(`{"config":{"notify_on_block":true}}`)); !on || err != nil {
+		t.Errorf("got on=%v err=%v", on, err)
+	}
+	if on, err := loadNotify(nil); on || err != nil {
+		t.Errorf("no config => off, got on=%v err=%v", on, err)
+	}
+	if _, err := loadNotify([]byte(`{"config":{"notify_on_block":"yes"}}`)); err == nil {
+		t.Error("a non-bool notify_on_block must be a config error")
+	}
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"notify_on_block":1}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad notify_on_block exit = %d, want 2", code)
+	}
+}
+
+func TestSeverityConfig(t *testing.T) {
+	if lv, every, err := loadSeverity([]byte(`{"config":{"severity":"low","escalate_after":3}}`)); lv != severity.Low || every != 3 || err != nil {
+		t.Errorf("got %v every=%d err=%v", lv, every, err)
+	}
+	if lv, every, err := loadSeverity(nil); lv != severity.High || every != 0 || err != nil {
+		t.Errorf("no config => high, got %v every=%d err=%v", lv, every, err)
+	}
+	for _, bad := range []string{
+		`{"config":{"severity":"severe"}}`,
+		`{"config":{"severity":""}}`,
+		`{"config":{"severity":2}}`,
+		`{"config":{"escalate_after":-1}}`,
+		`{"config":{"escalate_after":1.5}}`,
+	} {
+		if _, _, err := loadSeverity([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"severity":"mild"}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad severity exit = %d, want 2", code)
+	}
+}
+
+func TestRunLowSeverityKeepsALedger(t *testing.T) {
+	dir := t.TempDir()
+	old := ledgerNew
+	ledgerNew = func() *severity.Ledger { return severity.NewLedger(dir) }
+	defer func() { ledgerNew = old }()
+	cfg := filepath.Join(dir, "job.json")
+	writeF(t, cfg, `{"config":{"process_names":["zzz-focusd-test-nonexistent"],"severity":"low","escalate_after":2}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 0 {
+		t.Fatalf("low severity exit = %d, want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, severity.LedgerFile)); err != nil {
+		t.Fatalf("escalation ledger not written: %v", err)
+	}
+}
+
+func TestExcludeConfig(t *testing.T) {
+	if ex, err := loadExclude([]byte(`{"config":{"exclude":["*pipeline*"]}}`)); len(ex) != 1 || err != nil {
+		t.Errorf("got %v err=%v", ex, err)
+	}
+	if ex, err := loadExclude(nil); ex != nil || err != nil {
+		t.Errorf("no config => none, got %v err=%v", ex, err)
+	}
+	for _, bad := range []string{
+		`{"config":{"exclude":"*pipeline*"}}`,
+		`{"config":{"exclude":[""]}}`,
+		`{"config":{"exclude":["[unclosed"]}}`,
+	} {
+		if _, err := loadExclude([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	if p, err := loadPaths([]byte(`{"config":{"paths":["{{Home}}/Downloads/*steam*.zip"]}}`)); len(p) != 1 || err != nil {
+		t.Errorf("paths: got %v err=%v", p, err)
+	}
+	if _, err := loadPaths([]byte(`{"config":{"paths":["/Users/alice/Downloads"]}}`)); err == nil {
+		t.Error("an unanchored path must be a config error")
+	}
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"exclude":[1]}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad exclude exit = %d, want 2", code)
+	}
+}
+
+func TestActionsConfig(t *testing.T) {
+	if a, err := loadActions(nil); !a.kill || !a.delete || err != nil {
+		t.Errorf("no config => both, got %+v err=%v", a, err)
+	}
+	if a, err := loadActions([]byte(`{"config":{"actions":["kill"]}}`)); !a.kill || a.delete || err != nil {
+		t.Errorf("kill only: got %+v err=%v", a, err)
+	}
+	if a, err := loadActions([]byte(`{"config":{"actions":[]}}`)); a.kill || a.delete || err != nil {
+		t.Errorf("empty list => report only, got %+v err=%v", a, err)
+	}
+	a, err := loadActions([]byte(`{"config":{"actions":["restrict","delete"]}}`))
+	if netcut.Supported && (!a.restrict || a.kill || err != nil) {
+		t.Errorf("restrict: got %+v err=%v", a, err)
+	}
+	if !netcut.Supported && err == nil {
+		t.Error("restrict must be rejected where nftables is unavailable")
+	}
+	for _, bad := range []string{
+		`{"config":{"actions":"kill"}}`,
+		`{"config":{"actions":["uninstall"]}}`,
+	} {
+		if _, err := loadActions([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"actions":["nuke"]}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad actions exit = %d, want 2", code)
+	}
+}
+
+type fakeCut struct{ calls int }
+
+func (f *fakeCut) Restrict(pids []int) netcut.Outcome {
+	f.calls++
+	return netcut.Outcome{Restricted: pids}
+}
+
+func TestRunRestrictReplacesTheKill(t *testing.T) {
+	if !netcut.Supported {
+		t.Skip("restrict is Linux-only")
+	}
+	f := &fakeCut{}
+	old := netNew
+	netNew = func() restrictor { return f }
+	defer func() { netNew = old }()
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"process_names":["zzz-focusd-test-nonexistent"],"severity":"medium","actions":["restrict"]}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 0 || f.calls != 1 {
+		t.Fatalf("exit = %d, restrict calls = %d; want 0, 1", code, f.calls)
+	}
+	writeF(t, cfg, `{"config":{"process_names":["zzz-focusd-test-nonexistent"],"severity":"medium","actions":["kill","restrict"]}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 0 || f.calls != 1 {
+		t.Fatalf("with kill listed, kill wins: exit = %d, restrict calls = %d", code, f.calls)
+	}
+}
+
+func TestTombstoneDaysConfig(t *testing.T) {
+	if d, err := loadTombstoneDays(nil); d != 0 || err != nil {
+		t.Errorf("no config => off, got %d err=%v", d, err)
+	}
+	if d, err := loadTombstoneDays([]byte(`{"config":{"tombstone_days":30}}`)); d != 30 || err != nil {
+		t.Errorf("30 days: got %d err=%v", d, err)
+	}
+	for _, bad := range []string{
+		`{"config":{"tombstone_days":-1}}`,
+		`{"config":{"tombstone_days":1.5}}`,
+		`{"config":{"tombstone_days":366}}`,
+		`{"config":{"tombstone_days":"30"}}`,
+	} {
+		if _, err := loadTombstoneDays([]byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+	cfg := filepath.Join(t.TempDir(), "job.json")
+	writeF(t, cfg, `{"config":{"tombstone_days":-3}}`)
+	if code := run([]string{"run", "--config", cfg}); code != 2 {
+		t.Errorf("bad tombstone_days exit = %d, want 2", code)
+	}
+}
+
+func TestReadProcsSkipsBlanksAndComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "procs.txt")
+	writeF(t, path, "# fixture\nSteam Helper\n\n  dota2  \n")
+	got, err := readProcs(path)
+	if err != nil || len(got) != 2 || got[0] != "Steam Helper" || got[1] != "dota2" {
+		t.Fatalf("readProcs = %q, %v", got, err)
+	}
+}
+
+// TestSimulateTouchesNothing: test against a sandbox leaves every matched
+// artifact in place, and needs --root.
+func TestSimulateTouchesNothing(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "Applications", "Steam.app")
+	if err := os.MkdirAll(app, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := filepath.Join(root, "job.json")
+	procs := filepath.Join(root, "procs.txt")
+	writeF(t, cfg, `{"job_id":"j","plugin_id":"kill-steam","config":{}}`)
+	writeF(t, procs, "Steam\nFinder\n")
+	if code := run([]string{"test", "--config", cfg, "--root", root, "--procs", procs}); code != 0 {
+		t.Fatalf("test exit = %d, want 0", code)
+	}
+	if _, err := os.Stat(app); err != nil {
+		t.Fatal("simulation removed Steam.app")
+	}
+	if code := run([]string{"test", "--config", cfg}); code != 2 {
+		t.Fatalf("test without --root exit = %d, want 2", code)
+	}
+}
+
+// TestSimulateReportsConfiguredPaths: a configured paths entry reaches the
+// dry run, so it shows what run would delete.
+func TestSimulateReportsConfiguredPaths(t *testing.T) {
+	root := t.TempDir()
+	dota := filepath.Join(root, "Users", "alice", "Games", "dota")
+	if err := os.MkdirAll(dota, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := filepath.Join(root, "job.json")
+	writeF(t, cfg, `{"job_id":"j","plugin_id":"kill-steam","config":{"paths":["{{Home}}/Games/dota"]}}`)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	code := run([]string{"test", "--config", cfg, "--root", root})
+	os.Stdout = stdout
+	w.Close()
+	b, _ := io.ReadAll(r)
+	if code != 0 {
+		t.Fatalf("test exit = %d, want 0", code)
+	}
+	var res struct {
+		Details struct {
+			PathMatches []string `json:"path_matches"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatalf("output %q: %v", b, err)
+	}
+	if !slices.Contains(res.Details.PathMatches, "/Users/alice/Games/dota") {
+		t.Fatalf("path_matches = %v, want the configured path", res.Details.PathMatches)
+	}
+	if _, err := os.Stat(dota); err != nil {
+		t.Fatal("simulation removed a configured path")
+	}
+}
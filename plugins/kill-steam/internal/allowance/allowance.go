@@ -0,0 +1,100 @@
+// Package allowance meters how long each blocked app has run today, for a
+// "limit" policy: an app with a daily allowance of N minutes is left alone
+// until it has run N minutes, then killed and blocked like any other for
+// the rest of the day.
+//
+// Time is sampled, not measured: every run that sees the app still running
+// adds the time since the previous run that saw it. A gap longer than
+// MaxGap (the machine slept, the job was held up) adds only MaxGap, so a
+// pause never makes an app look unused, nor charges it for a whole night.
+package allowance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MeterFile is the meter's basename inside the state dir (neutral on
+// purpose, like the notice tally).
+const MeterFile = ".use"
+
+// MaxGap caps the time one sample may add: the slowest cadence the job
+// runs at (an adaptive quiet interval) and no more.
+const MaxGap = 10 * time.Minute
+
+// MaxMinutes is a whole day: a larger allowance is no limit at all.
+const MaxMinutes = 24 * 60
+
+// meter is the persisted state: today's use per app, in seconds, and when
+// each running app was last seen.
+type meter struct {
+	Day  string           `json:"d"`
+	Used map[string]int64 `json:"u"`
+	Seen map[string]int64 `json:"s"` // unix seconds
+}
+
+// Meter samples app uptime across runs.
+type Meter struct {
+	dir string
+	now func() time.Time
+}
+
+// New builds a Meter kept in dir.
+func New(dir string) *Meter {
+	return &Meter{dir: dir, now: time.Now}
+}
+
+// Sample records that running (app labels) are up now and returns today's
+// use of every app metered so far. An app first seen now has used nothing
+// yet; one no longer running stops accruing. A new day starts from zero.
+// A meter that cannot be read starts empty; the use is still returned when
+// the write fails.
+func (m *Meter) Sample(running []string) (map[string]time.Duration, error) {
+	path := filepath.Join(m.dir, MeterFile)
+	var s meter
+	if b, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(b, &s)
+	}
+	now := m.now()
+	day := now.Format(time.DateOnly)
+	if s.Day != day || s.Used == nil {
+		s = meter{Day: day, Used: map[string]int64{}}
+	}
+	seen := make(map[string]int64, len(running))
+	for _, app := range running {
+		if last, ok := s.Seen[app]; ok {
+			gap := now.Sub(time.Unix(last, 0))
+			s.Used[app] += int64(min(max(gap, 0), MaxGap) / time.Second)
+		}
+		seen[app] = now.Unix()
+	}
+	s.Seen = seen
+
+	used := make(map[string]time.Duration, len(s.Used))
+	for app, secs := range s.Used {
+		used[app] = time.Duration(secs) * time.Second
+	}
+	b, _ := json.Marshal(s)
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return used, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return used, err
+	}
+	return used, os.Rename(tmp, path)
+}
+
+// Left returns, per app in limits (minutes per day), the allowance still
+// unused; a spent allowance is absent.
+func Left(limits map[string]int, used map[string]time.Duration) map[string]time.Duration {
+	left := map[string]time.Duration{}
+	for app, mins := range limits {
+		if rest := time.Duration(mins)*time.Minute - used[app]; rest > 0 {
+			left[app] = rest
+		}
+	}
+	return left
+}
@@ -0,0 +1,51 @@
+package allowance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSampleAccruesWhileRunning(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	m := New(t.TempDir())
+	m.now = func() time.Time { return now }
+
+	step := func(d time.Duration, running ...string) map[string]time.Duration {
+		t.Helper()
+		now = now.Add(d)
+		used, err := m.Sample(running)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return used
+	}
+	if used := step(0, "Dota 2"); used["Dota 2"] != 0 {
+		t.Fatalf("first sight must use nothing yet: %v", used)
+	}
+	step(10*time.Second, "Dota 2")
+	if used := step(20*time.Second, "Dota 2"); used["Dota 2"] != 30*time.Second {
+		t.Fatalf("used %v, want 30s", used["Dota 2"])
+	}
+	// Closed for an hour: nothing accrues, and the relaunch starts afresh.
+	step(10 * time.Second)
+	step(time.Hour, "Dota 2")
+	if used := step(10*time.Second, "Dota 2"); used["Dota 2"] != 40*time.Second {
+		t.Fatalf("used %v, want 40s", used["Dota 2"])
+	}
+	// A night asleep with the game open charges at most MaxGap.
+	if used := step(8*time.Hour, "Dota 2"); used["Dota 2"] != 40*time.Second+MaxGap {
+		t.Fatalf("used %v after a long gap", used["Dota 2"])
+	}
+	// The next day starts from zero.
+	now = now.Add(24 * time.Hour)
+	if used := step(0, "Dota 2"); used["Dota 2"] != 0 {
+		t.Fatalf("new day used %v", used["Dota 2"])
+	}
+}
+
+func TestLeft(t *testing.T) {
+	left := Left(map[string]int{"Dota 2": 60, "Steam": 1}, map[string]time.Duration{"Dota 2": 45 * time.Minute, "Steam": time.Minute})
+	if len(left) != 1 || left["Dota 2"] != 15*time.Minute {
+		t.Fatalf("left = %v", left)
+	}
+}
@@ -0,0 +1,116 @@
+// Package backup closes the backup door on a removed install: the paths
+// kill-steam sweeps are excluded from Time Machine, and an artifact that
+// reappears already older than its removal — a restore from a backup, not a
+// fresh download — is reported so the run can log it as a bypass attempt.
+//
+// Exclusions are fixed-path (tmutil addexclusion -p, root only), so they
+// outlive the item and cover whatever is later put back at that path.
+// Backups taken before the exclusion still hold the files; restores from
+// those are what Restored catches.
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LedgerFile is the guard's basename inside the state dir (neutral, like
+// the escalation ledger).
+const LedgerFile = ".bkp"
+
+// ledger is the persisted state: when each path was last removed, and the
+// paths already excluded from backups.
+type ledger struct {
+	Removed  map[string]time.Time `json:"r"`
+	Excluded []string             `json:"x"`
+}
+
+// Guard tracks removals and backup exclusions across runs.
+type Guard struct {
+	dir     string
+	now     func() time.Time
+	exclude func(path string) error
+}
+
+// New builds a Guard kept in dir that excludes paths with tmutil.
+func New(dir string) *Guard {
+	return &Guard{dir: dir, now: time.Now, exclude: excludeFromBackups}
+}
+
+// Observe takes the artifacts present before this run's sweep. It returns
+// those restored from a backup: removed by an earlier run and back with a
+// modification time from before that removal. App bundles are left out —
+// a bundle copied off a disk image keeps its build date too, so an old
+// bundle is no sign of a restore. Every present path not yet excluded is
+// excluded from backups; excluded counts the new ones, and err is the
+// first exclusion or ledger write that failed.
+func (g *Guard) Observe(present []string) (restored []string, excluded int, err error) {
+	l := g.load()
+	for _, p := range present {
+		if at, ok := l.Removed[p]; ok && !strings.HasSuffix(p, ".app") {
+			if info, serr := os.Lstat(p); serr == nil && !info.ModTime().After(at) {
+				restored = append(restored, p)
+			}
+		}
+		if slices.Contains(l.Excluded, p) {
+			continue
+		}
+		if xerr := g.exclude(p); xerr != nil {
+			if err == nil {
+				err = xerr
+			}
+			continue
+		}
+		l.Excluded = append(l.Excluded, p)
+		excluded++
+	}
+	if excluded > 0 {
+		if werr := g.save(l); err == nil {
+			err = werr
+		}
+	}
+	return restored, excluded, err
+}
+
+// Removed records this run's removals, so a later copy of the same path
+// can be dated against them.
+func (g *Guard) Removed(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	l := g.load()
+	now := g.now()
+	for _, p := range paths {
+		l.Removed[p] = now
+	}
+	return g.save(l)
+}
+
+// load reads the ledger; an unreadable one starts empty.
+func (g *Guard) load() ledger {
+	var l ledger
+	if b, err := os.ReadFile(filepath.Join(g.dir, LedgerFile)); err == nil {
+		_ = json.Unmarshal(b, &l)
+	}
+	if l.Removed == nil {
+		l.Removed = map[string]time.Time{}
+	}
+	return l
+}
+
+func (g *Guard) save(l ledger) error {
+	b, _ := json.Marshal(l)
+	if err := os.MkdirAll(g.dir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(g.dir, LedgerFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestObserveFlagsAnOldCopyOfARemovedPath(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "Steam")
+	bundle := filepath.Join(root, "Steam.app")
+	fresh := filepath.Join(root, "Logs")
+	for _, p := range []string{data, bundle, fresh} {
+		if err := os.Mkdir(p, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removedAt := time.Now()
+	old := removedAt.Add(-48 * time.Hour)
+	for _, p := range []string{data, bundle} {
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tm []string
+	g := New(t.TempDir())
+	g.now = func() time.Time { return removedAt }
+	g.exclude = func(p string) error { tm = append(tm, p); return nil }
+	if err := g.Removed([]string{data, bundle, fresh}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fresh, removedAt.Add(time.Hour), removedAt.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, n, err := g.Observe([]string{data, bundle, fresh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0] != data {
+		t.Fatalf("restored = %v, want only the old data dir", restored)
+	}
+	if n != 3 || len(tm) != 3 {
+		t.Fatalf("excluded %d (%v), want all 3", n, tm)
+	}
+	if _, n, _ := g.Observe([]string{data}); n != 0 || len(tm) != 3 {
+		t.Fatalf("a path is excluded once: %d more, %v", n, tm)
+	}
+}
+
+func TestObserveRetriesAFailedExclusion(t *testing.T) {
+	g := New(t.TempDir())
+	fail := true
+	g.exclude = func(string) error {
+		if fail {
+			return errors.New("tmutil: not permitted")
+		}
+		return nil
+	}
+	if _, n, err := g.Observe([]string{"/Applications/Steam.app"}); err == nil || n != 0 {
+		t.Fatalf("n=%d err=%v, want the failure reported", n, err)
+	}
+	fail = false
+	if _, n, err := g.Observe([]string{"/Applications/Steam.app"}); err != nil || n != 1 {
+		t.Fatalf("retry: n=%d err=%v", n, err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package backup
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// excludeFromBackups adds a fixed-path Time Machine exclusion for path.
+// The error names the tool, never the path.
+func excludeFromBackups(path string) error {
+	if out, err := exec.Command("tmutil", "addexclusion", "-p", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("tmutil addexclusion: %v (%d bytes of output)", err, len(out))
+	}
+	return nil
+}
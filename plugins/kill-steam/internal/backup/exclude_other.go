@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package backup
+
+// excludeFromBackups is a no-op off macOS: there is no Time Machine.
+func excludeFromBackups(string) error { return nil }
@@ -0,0 +1,189 @@
+// Package bypass detects (and optionally terminates) tools commonly used to
+// route around a blocker: alternative DNS clients, proxy managers, and
+// consumer "unblocker" VPNs. Killing Steam is pointless if the user just
+// tunnels past Freedom and the DNS blocklist, so these are policed by the
+// same job, with an allowlist for tools a machine legitimately needs.
+//
+// Names are matched exactly (case-insensitive) against the process
+// basename, never as a substring — the same rule as package killer.
+package bypass
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/proctable"
+)
+
+// Categories of bypass tool.
+const (
+	CategoryDNS   = "dns"   // encrypted/alternative DNS clients
+	CategoryProxy = "proxy" // local proxy managers / tunnelling cores
+	CategoryVPN   = "vpn"   // consumer VPNs marketed as unblockers
+)
+
+// Modes select what Run does with a detected tool.
+const (
+	ModeOff    = "off"    // do nothing
+	ModeDetect = "detect" // report only (default)
+	ModeKill   = "kill"   // report and terminate
+)
+
+// ValidMode reports whether m names a known mode.
+func ValidMode(m string) bool {
+	switch m {
+	case ModeOff, ModeDetect, ModeKill:
+		return true
+	}
+	return false
+}
+
+// Policy is one category's process basenames (macOS).
+type Policy struct {
+	Category string
+	Names    []string
+}
+
+// DefaultPolicies is the built-in bypass catalogue. General-purpose VPN
+// clients (WireGuard, Tunnelblick, OpenVPN, vendor corporate clients) are
+// deliberately absent: they are routinely needed for work, and a bypass
+// configured through one is better handled per machine than by a blanket
+// kill.
+var DefaultPolicies = []Policy{
+	{CategoryDNS, []string{
+		"dnscrypt-proxy", "cloudflared", "stubby", "NextDNS",
+	}},
+	{CategoryProxy, []string{
+		"ClashX", "ClashX Pro", "Clash Verge", "clash", "mihomo",
+		"V2rayU", "v2ray", "xray", "sing-box", "ShadowsocksX-NG",
+		"ss-local", "Surge", "Shadowrocket", "tor",
+	}},
+	{CategoryVPN, []string{
+		"Psiphon", "Lantern", "Windscribe", "ProtonVPN", "Cloudflare WARP",
+		"Hotspot Shield", "TunnelBear",
+	}},
+}
+
+// Finding is one running bypass tool.
+type Finding struct {
+	PID      int    `json:"pid"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// Outcome summarises a bypass pass.
+type Outcome struct {
+	Mode       string    `json:"mode"`
+	Detected   []Finding `json:"detected"`
+	KilledPIDs []int     `json:"killed_pids,omitempty"`
+	Failed     []string  `json:"failed,omitempty"` // "pid: reason"
+	// SkippedPIDs were detected but gone or renamed by the kill, as in
+	// the killer: a reused PID is never killed.
+	SkippedPIDs []int `json:"skipped_pids,omitempty"`
+}
+
+type procView struct {
+	PID  int
+	Name string
+}
+
+// Detector matches the process table against the catalogue minus the
+// allowlist. list/killPID are seams so tests never touch real processes.
+type Detector struct {
+	mode    string
+	deny    map[string]string // lower-cased name -> category
+	list    func() ([]procView, error)
+	killPID func(p procView) error
+}
+
+// New builds a Detector for mode (empty ⇒ ModeDetect). allow names tools
+// this machine keeps (case-insensitive exact basenames).
+func New(mode string, allow []string) *Detector {
+	if mode == "" {
+		mode = ModeDetect
+	}
+	allowed := make(map[string]bool, len(allow))
+	for _, a := range allow {
+		allowed[strings.ToLower(a)] = true
+	}
+	deny := map[string]string{}
+	for _, p := range DefaultPolicies {
+		for _, n := range p.Names {
+			if l := strings.ToLower(n); !allowed[l] {
+				deny[l] = p.Category
+			}
+		}
+	}
+	return &Detector{mode: mode, deny: deny, list: listProcesses, killPID: killProcess}
+}
+
+// WithProcesses makes d scan the given basenames instead of the live
+// process table and kill nothing, so Run reports what it would detect.
+func (d *Detector) WithProcesses(names []string) *Detector {
+	d.list = func() ([]procView, error) {
+		out := make([]procView, len(names))
+		for i, n := range names {
+			out[i] = procView{PID: i + 1, Name: n}
+		}
+		return out, nil
+	}
+	d.killPID = func(procView) error { return nil }
+	return d
+}
+
+// Run scans once. ModeOff returns without reading the process table.
+func (d *Detector) Run() (Outcome, error) {
+	out := Outcome{Mode: d.mode}
+	if d.mode == ModeOff {
+		return out, nil
+	}
+	procs, err := d.list()
+	if err != nil {
+		return out, fmt.Errorf("enumerate processes: %w", err)
+	}
+	for _, p := range procs {
+		cat, hit := d.deny[strings.ToLower(p.Name)]
+		if !hit {
+			continue
+		}
+		out.Detected = append(out.Detected, Finding{PID: p.PID, Name: p.Name, Category: cat})
+		if d.mode != ModeKill {
+			continue
+		}
+		if err := d.killPID(p); errors.Is(err, errNotSame) {
+			out.SkippedPIDs = append(out.SkippedPIDs, p.PID)
+			continue
+		} else if err != nil {
+			out.Failed = append(out.Failed, fmt.Sprintf("%d: %v", p.PID, err))
+			continue
+		}
+		out.KilledPIDs = append(out.KilledPIDs, p.PID)
+	}
+	sort.Ints(out.KilledPIDs)
+	return out, nil
+}
+
+func listProcesses() ([]procView, error) {
+	ps, err := proctable.List()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]procView, len(ps))
+	for i, p := range ps {
+		out[i] = procView{PID: p.PID, Name: p.Name}
+	}
+	return out, nil
+}
+
+// errNotSame: the PID no longer runs the tool the scan found.
+var errNotSame = errors.New("pid no longer runs the detected tool")
+
+// killProcess kills v only if its PID still runs the exact name detected.
+func killProcess(v procView) error {
+	if name, err := proctable.NameOf(v.PID); err != nil || !strings.EqualFold(name, v.Name) {
+		return errNotSame
+	}
+	return proctable.Kill(v.PID)
+}
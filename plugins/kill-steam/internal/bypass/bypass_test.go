@@ -0,0 +1,88 @@
+package bypass
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newFake(mode string, allow []string, procs []procView, killErr map[int]error) (*Detector, *[]int) {
+	d := New(mode, allow)
+	var killed []int
+	d.list = func() ([]procView, error) { return procs, nil }
+	d.killPID = func(p procView) error {
+		killed = append(killed, p.PID)
+		return killErr[p.PID]
+	}
+	return d, &killed
+}
+
+var table = []procView{
+	{PID: 10, Name: "ClashX"},
+	{PID: 11, Name: "cloudflared"},
+	{PID: 12, Name: "clashx-helper"}, // not exact: must survive
+	{PID: 13, Name: "Psiphon"},
+	{PID: 14, Name: "Safari"},
+	{PID: 15, Name: "TOR"}, // case-insensitive exact
+}
+
+func TestRunModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		allow      []string
+		wantFound  []int
+		wantKilled []int
+	}{
+		{"detect is the default and kills nothing", "", nil, []int{10, 11, 13, 15}, nil},
+		{"kill terminates every match", ModeKill, nil, []int{10, 11, 13, 15}, []int{10, 11, 13, 15}},
+		{"allowlist exempts a tool", ModeKill, []string{"CLOUDFLARED"}, []int{10, 13, 15}, []int{10, 13, 15}},
+		{"off does nothing", ModeOff, nil, nil, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, killed := newFake(tc.mode, tc.allow, table, nil)
+			out, err := d.Run()
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			var found []int
+			for _, f := range out.Detected {
+				found = append(found, f.PID)
+			}
+			if !reflect.DeepEqual(found, tc.wantFound) {
+				t.Errorf("detected %v, want %v", found, tc.wantFound)
+			}
+			if !reflect.DeepEqual(out.KilledPIDs, tc.wantKilled) || len(*killed) != len(tc.wantKilled) {
+				t.Errorf("killed %v (calls %v), want %v", out.KilledPIDs, *killed, tc.wantKilled)
+			}
+		})
+	}
+}
+
+func TestFindingCategoryAndKillFailure(t *testing.T) {
+	d, _ := newFake(ModeKill, nil, table[:2], map[int]error{11: errors.New("EPERM")})
+	out, err := d.Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out.Detected[0].Category != CategoryProxy || out.Detected[1].Category != CategoryDNS {
+		t.Errorf("categories = %+v", out.Detected)
+	}
+	if len(out.Failed) != 1 || !reflect.DeepEqual(out.KilledPIDs, []int{10}) {
+		t.Errorf("want pid 11 failed and 10 killed, got %+v", out)
+	}
+
+	d, _ = newFake(ModeKill, nil, table[:2], map[int]error{11: errNotSame})
+	if out, _ := d.Run(); len(out.Failed) != 0 || !reflect.DeepEqual(out.SkippedPIDs, []int{11}) {
+		t.Errorf("a reused pid must be skipped, not failed: %+v", out)
+	}
+}
+
+func TestEnumerationError(t *testing.T) {
+	d := New(ModeKill, nil)
+	d.list = func() ([]procView, error) { return nil, errors.New("boom") }
+	if _, err := d.Run(); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,173 @@
+package killer
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/proctable"
+)
+
+// Compatibility layers run a blocked game under another process's name, so
+// the exact-name match never sees it. Wine and CrossOver run each Windows
+// program as a wine loader whose arguments name the .exe; a VM runs as one
+// process per machine whose arguments name the machine's bundle. These are
+// matched by their arguments. A wine loader is killed. A VM is only asked
+// to stop: a SIGKILL mid-write can corrupt the guest's disk, and a match
+// there is on the machine's name alone, never on a path or user around it.
+
+// Layers a wrapper can belong to, as reported in Wrapped.Layer.
+const (
+	LayerWine = "wine"
+	LayerVM   = "vm"
+)
+
+// wineLoaders are the process names a Wine or CrossOver bottle runs a
+// Windows program under.
+var wineLoaders = []string{"wine", "wine64", "wineloader", "wine-preloader", "wine64-preloader"}
+
+// vmHosts are the per-machine processes of Parallels, UTM/QEMU, VMware and
+// VirtualBox.
+var vmHosts = []string{
+	"prl_vm_app", "QEMULauncher", "qemu-system-x86_64", "qemu-system-aarch64",
+	"vmware-vmx", "VirtualBoxVM", "VBoxHeadless",
+}
+
+// vmBundles are the suffixes of the machine file or bundle a VM host is
+// started on; the name is the basename before the suffix.
+var vmBundles = []string{".pvm", ".vmx", ".utm", ".vbox"}
+
+// vmNameFlags carry a machine's name when no bundle is named: QEMU's -name,
+// VirtualBox's --comment and --startvm.
+var vmNameFlags = []string{"-name", "--comment", "--startvm"}
+
+// vmStopWait is how long a VM is given to exit after SIGTERM before the
+// pass reports it as still stopping. The next pass asks again; it stays
+// well inside the job's timeout.
+const vmStopWait = 5 * time.Second
+
+// vmTitle finds a game title in a VM's name as a whole word, so a machine
+// named "Dota Box" matches and one named "msteams-dev" does not.
+var vmTitle = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(steam|dota ?2?)(?:[^a-z0-9]|$)`)
+
+// Wrapped is a blocked game found running inside a compatibility layer.
+type Wrapped struct {
+	PID     int    `json:"pid"`
+	Layer   string `json:"layer"`
+	Wrapper string `json:"wrapper"` // the wrapper's process name
+	Runs    string `json:"runs"`    // the .exe or the title in the VM's name
+}
+
+// layerOf reports which layer a process name belongs to, "" for none.
+func layerOf(name string) string {
+	eq := func(s string) bool { return strings.EqualFold(s, name) }
+	switch {
+	case slices.ContainsFunc(wineLoaders, eq):
+		return LayerWine
+	case slices.ContainsFunc(vmHosts, eq):
+		return LayerVM
+	}
+	return ""
+}
+
+// wrappedRun returns what a wrapper of layer runs that k blocks: for Wine,
+// the blocked .exe (a configured name plus ".exe") its arguments name; for
+// a VM, the game title in the machine's name. "" when nothing blocked.
+func (k *Killer) wrappedRun(layer, args string) string {
+	switch layer {
+	case LayerWine:
+		a := strings.ToLower(args)
+		for _, n := range k.names {
+			if exe := strings.ToLower(n) + ".exe"; namesFile(a, exe) {
+				return exe
+			}
+		}
+	case LayerVM:
+		if m := vmTitle.FindStringSubmatch(vmName(args)); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// vmName reads the machine's name from a VM host's arguments: the basename
+// of its bundle, else the value of a name flag. "" when neither is there,
+// and such a VM is left alone.
+func vmName(args string) string {
+	lower := strings.ToLower(args)
+	if len(lower) != len(args) {
+		lower = args // a case fold that resized some rune; match as written
+	}
+	for _, ext := range vmBundles {
+		for i := len(lower); ; {
+			j := strings.LastIndex(lower[:i], ext)
+			if j < 0 {
+				break
+			}
+			if end := j + len(ext); end == len(lower) || strings.ContainsRune(`/" `, rune(lower[end])) {
+				start := strings.LastIndexAny(args[:j], `/\"`) + 1
+				return args[start:j]
+			}
+			i = j
+		}
+	}
+	padded := " " + args + " "
+	for _, f := range vmNameFlags {
+		i := strings.Index(padded, " "+f+" ")
+		if i < 0 {
+			continue
+		}
+		v := padded[i+len(f)+2:]
+		if j := strings.Index(v, " -"); j >= 0 {
+			v = v[:j]
+		}
+		v = strings.TrimPrefix(strings.Trim(strings.TrimSpace(v), `"'`), "guest=")
+		v, _, _ = strings.Cut(v, ",")
+		return v
+	}
+	return ""
+}
+
+// errStillStopping: a VM got SIGTERM but had not exited by vmStopWait.
+var errStillStopping = errors.New("VM asked to stop, still shutting down")
+
+// stopVM asks a VM host to shut down with SIGTERM, if the PID still runs
+// it, and waits up to vmStopWait for it to go. It never sends SIGKILL.
+func stopVM(v procView) error {
+	if name, err := proctable.NameOf(v.PID); err != nil || !strings.EqualFold(name, v.Name) {
+		return errNotSame
+	}
+	if err := proctable.Terminate(v.PID); err != nil {
+		return err
+	}
+	for deadline := time.Now().Add(vmStopWait); time.Now().Before(deadline); {
+		time.Sleep(250 * time.Millisecond)
+		if name, err := proctable.NameOf(v.PID); err != nil || !strings.EqualFold(name, v.Name) {
+			return nil
+		}
+	}
+	return errStillStopping
+}
+
+// namesFile reports whether args names file as a whole path element:
+// preceded by a path separator, a quote, a space or the start, and
+// followed by a quote, a space or the end. "notsteam.exe" is not
+// "steam.exe".
+func namesFile(args, file string) bool {
+	for i := 0; ; {
+		j := strings.Index(args[i:], file)
+		if j < 0 {
+			return false
+		}
+		j += i
+		end := j + len(file)
+		before := j == 0 || strings.ContainsRune(`\/" `, rune(args[j-1]))
+		after := end == len(args) || strings.ContainsRune(`" `, rune(args[end]))
+		if before && after {
+			return true
+		}
+		i = j + 1
+	}
+}
@@ -0,0 +1,122 @@
+package killer
+
+import (
+	"strings"
+	"testing"
+)
+
+func withArgs(k *Killer, args map[int]string) *Killer {
+	k.argsOf = func(pid int) (string, error) { return args[pid], nil }
+	return k
+}
+
+func TestRunKillsWrappedGames(t *testing.T) {
+	procs := []procView{
+		{PID: 20, Name: "wine64-preloader"},
+		{PID: 21, Name: "wineloader"},
+		{PID: 22, Name: "prl_vm_app"},
+		{PID: 23, Name: "QEMULauncher"},
+		{PID: 24, Name: "wine"},
+		{PID: 25, Name: "zsh"},
+	}
+	args := map[int]string{
+		20: `wine64-preloader C:\Program Files (x86)\Steam\steam.exe -silent`,
+		21: `wineloader C:\Windows\notepad.exe`,                   // not blocked
+		22: `prl_vm_app --openvm /Users/x/Parallels/Dota Box.pvm`, // killed
+		23: `QEMULauncher -name msteams-dev`,                      // "steam" inside a word
+		24: `wine "C:\Games\notsteam.exe"`,                        // not an exact .exe
+		25: `zsh -c wine C:\Program Files (x86)\Steam\steam.exe`,  // not a wrapper
+	}
+	out, err := withArgs(newFake(procs, nil), args).Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := out.KilledPIDs; len(got) != 2 || got[0] != 20 || got[1] != 22 {
+		t.Fatalf("killed %v, want [20 22]", got)
+	}
+	if got := strings.Join(out.KilledNames, ","); got != "steam.exe,Dota" {
+		t.Errorf("killed names = %q", got)
+	}
+	want := []Wrapped{
+		{PID: 20, Layer: LayerWine, Wrapper: "wine64-preloader", Runs: "steam.exe"},
+		{PID: 22, Layer: LayerVM, Wrapper: "prl_vm_app", Runs: "Dota"},
+	}
+	if len(out.Wrapped) != len(want) || out.Wrapped[0] != want[0] || out.Wrapped[1] != want[1] {
+		t.Errorf("wrapped = %+v", out.Wrapped)
+	}
+}
+
+func TestVMMatchesTheMachineNameOnly(t *testing.T) {
+	procs := []procView{
+		{PID: 40, Name: "prl_vm_app"},
+		{PID: 41, Name: "qemu-system-aarch64"},
+		{PID: 42, Name: "vmware-vmx"},
+		{PID: 43, Name: "VBoxHeadless"},
+		{PID: 44, Name: "qemu-system-x86_64"},
+		{PID: 45, Name: "VirtualBoxVM"},
+	}
+	args := map[int]string{
+		40: `prl_vm_app --openvm /Users/steam/Parallels/Work.pvm`,                              // user named steam
+		41: `qemu-system-aarch64 -name guest=dev,debug-threads=on -drive file=/vm/dota2.qcow2`, // disk named dota2
+		42: `vmware-vmx -s x=1 /Users/x/VMs/Dota 2 Box.vmwarevm/Dota 2 Box.vmx`,                // stopped
+		43: `VBoxHeadless --comment Steam Deck --startvm 9f1c`,                                 // stopped
+		44: `qemu-system-x86_64 -hda /home/steam.img`,                                          // no name at all
+		45: `VirtualBoxVM --comment "Win 11" --startvm dota`,                                   // --comment wins
+	}
+	var stopped, killed []int
+	k := withArgs(newFake(procs, nil), args)
+	k.stopVM = func(p procView) error { stopped = append(stopped, p.PID); return nil }
+	k.killPID = func(p procView) error { killed = append(killed, p.PID); return nil }
+	out, err := k.Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(killed) != 0 || len(stopped) != 2 || stopped[0] != 42 || stopped[1] != 43 {
+		t.Fatalf("stopped %v, killed %v; want [42 43] stopped and none killed", stopped, killed)
+	}
+	if got := strings.Join(out.KilledNames, ","); got != "Dota 2,Steam" {
+		t.Errorf("names = %q", got)
+	}
+}
+
+func TestVMName(t *testing.T) {
+	for args, want := range map[string]string{
+		`prl_vm_app --openvm "/Users/x/Parallels/Dota Box.pvm"`:        "Dota Box",
+		`prl_vm_app --openvm /Users/x/Parallels/Dota Box.pvm/`:         "Dota Box",
+		`QEMULauncher -name "Dota Box" -m 4096`:                        "Dota Box",
+		`qemu-system-x86_64 -name guest=win,debug-threads=on`:          "win",
+		`VBoxHeadless --startvm steam-box`:                             "steam-box",
+		`qemu-system-x86_64 -hda /home/steam/dota.img -m 4096`:         "",
+		`vmware-vmx /Users/dota/Virtual Machines/Win.vmwarevm/Win.vmx`: "Win",
+	} {
+		if got := vmName(args); got != want {
+			t.Errorf("vmName(%q) = %q, want %q", args, got, want)
+		}
+	}
+}
+
+func TestDetectReportsWrapped(t *testing.T) {
+	k := withArgs(newFake([]procView{{PID: 30, Name: "wine"}}, nil), map[int]string{30: "wine dota2.exe"})
+	m, err := k.Detect()
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(m.Wrapped) != 1 || m.Wrapped[0].Runs != "dota2.exe" || m.Names[0] != "dota2.exe" {
+		t.Fatalf("match = %+v", m)
+	}
+}
+
+func TestNamesFile(t *testing.T) {
+	for args, want := range map[string]bool{
+		`steam.exe`:              true,
+		`c:\steam\steam.exe -x`:  true,
+		`"c:/steam/steam.exe"`:   true,
+		`c:\steam\notsteam.exe`:  false,
+		`c:\steam\steam.exe.bak`: false,
+		`notsteam.exe steam.exe`: true,
+	} {
+		if got := namesFile(args, "steam.exe"); got != want {
+			t.Errorf("namesFile(%q) = %v", args, got)
+		}
+	}
+}
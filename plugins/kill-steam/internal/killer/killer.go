@@ -2,15 +2,18 @@
 // app_mon v0.6.1 policy + process layer, including the v0.6.1 #17 fix:
 // process names are matched EXACTLY (case-insensitive), never as a
 // substring — substring matching killed Microsoft Teams via the "steam"
-// inside "msteams".
+// inside "msteams". The match is checked again at the moment of the kill,
+// so a PID that exited and was reused after the scan is left alone.
 package killer
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
-	"github.com/shirou/gopsutil/v3/process"
+	"github.com/eliteGoblin/focusd/plugins/kill-steam/internal/proctable"
 )
 
 // DefaultProcessNames is the built-in Steam + Dota2 process basename set
@@ -26,9 +29,18 @@ var DefaultProcessNames = []string{
 
 // Outcome summarises a kill pass.
 type Outcome struct {
-	Scanned    int      `json:"scanned"`
-	KilledPIDs []int    `json:"killed_pids"`
-	Failed     []string `json:"failed,omitempty"` // "pid: reason"
+	Scanned    int   `json:"scanned"`
+	KilledPIDs []int `json:"killed_pids"`
+	// KilledNames are the distinct basenames of the killed processes.
+	KilledNames []string `json:"killed_names,omitempty"`
+	Failed      []string `json:"failed,omitempty"` // "pid: reason"
+	// SkippedPIDs matched at the scan but not at the kill: the process
+	// exited, or its PID now belongs to something else.
+	SkippedPIDs []int `json:"skipped_pids,omitempty"`
+	// Wrapped are the kills of a game running inside Wine, CrossOver or a
+	// VM: the wrapper process was killed, or the VM asked to shut down, a
+	// bypass attempt.
+	Wrapped []Wrapped `json:"wrapped,omitempty"`
 }
 
 // KilledCount is the number of processes successfully terminated.
@@ -43,7 +55,13 @@ type procView struct {
 type Killer struct {
 	names   []string
 	list    func() ([]procView, error)
-	killPID func(pid int) error
+	killPID func(p procView) error
+	// stopVM asks a VM host to shut down instead of killing it (compat.go).
+	stopVM func(p procView) error
+	// argsOf reads a wrapper process's arguments (compat.go).
+	argsOf func(pid int) (string, error)
+	// spare, when set, exempts the hits whose label it accepts.
+	spare func(label string) bool
 }
 
 // New builds a Killer. Empty names => DefaultProcessNames.
@@ -51,57 +69,169 @@ func New(names []string) *Killer {
 	if len(names) == 0 {
 		names = DefaultProcessNames
 	}
-	return &Killer{names: names, list: listProcesses, killPID: killProcess}
+	return &Killer{names: names, list: listProcesses, killPID: killProcess, stopVM: stopVM, argsOf: proctable.ArgsOf}
+}
+
+// Sparing makes k leave alone every match whose label (its name, or what a
+// wrapper runs) spare accepts: neither Run nor Detect reports it.
+func (k *Killer) Sparing(spare func(label string) bool) *Killer {
+	k.spare = spare
+	return k
+}
+
+// WithProcesses makes k scan the given basenames instead of the live
+// process table and kill nothing, so Run reports what it would stop. The
+// fixture processes get PIDs 1..n in order.
+func (k *Killer) WithProcesses(names []string) *Killer {
+	k.list = func() ([]procView, error) {
+		out := make([]procView, len(names))
+		for i, n := range names {
+			out[i] = procView{PID: i + 1, Name: n}
+		}
+		return out, nil
+	}
+	k.killPID = func(procView) error { return nil }
+	k.stopVM = k.killPID
+	k.argsOf = func(int) (string, error) { return "", nil }
+	return k
+}
+
+// Match is a detect-only pass: the matching processes, killed or not.
+type Match struct {
+	Scanned int       `json:"scanned"`
+	PIDs    []int     `json:"pids"`
+	Names   []string  `json:"names,omitempty"`
+	Wrapped []Wrapped `json:"wrapped,omitempty"`
+}
+
+// Detect scans running processes and reports those Run would kill,
+// killing nothing.
+func (k *Killer) Detect() (Match, error) {
+	procs, scanned, err := k.matching()
+	if err != nil {
+		return Match{}, err
+	}
+	m := Match{Scanned: scanned, PIDs: []int{}}
+	for _, h := range procs {
+		m.PIDs = append(m.PIDs, h.PID)
+		if !slices.Contains(m.Names, h.label()) {
+			m.Names = append(m.Names, h.label())
+		}
+		if h.wrapped != nil {
+			m.Wrapped = append(m.Wrapped, *h.wrapped)
+		}
+	}
+	sort.Ints(m.PIDs)
+	return m, nil
 }
 
 // Run scans running processes and kills every one whose basename exactly
-// (case-insensitively) matches a configured name.
+// (case-insensitively) matches a configured name, and every Wine wrapper
+// running one. A VM running one is asked to shut down instead (compat.go).
 func (k *Killer) Run() (Outcome, error) {
+	procs, scanned, err := k.matching()
+	if err != nil {
+		return Outcome{}, err
+	}
+	var out Outcome
+	out.Scanned = scanned
+	for _, h := range procs {
+		stop := k.killPID
+		if h.wrapped != nil && h.wrapped.Layer == LayerVM {
+			stop = k.stopVM
+		}
+		if err := stop(h.procView); errors.Is(err, errNotSame) {
+			out.SkippedPIDs = append(out.SkippedPIDs, h.PID)
+			continue
+		} else if err != nil {
+			out.Failed = append(out.Failed, fmt.Sprintf("%d: %v", h.PID, err))
+			continue
+		}
+		out.KilledPIDs = append(out.KilledPIDs, h.PID)
+		if !slices.Contains(out.KilledNames, h.label()) {
+			out.KilledNames = append(out.KilledNames, h.label())
+		}
+		if h.wrapped != nil {
+			out.Wrapped = append(out.Wrapped, *h.wrapped)
+		}
+	}
+	sort.Ints(out.KilledPIDs)
+	sort.Ints(out.SkippedPIDs)
+	return out, nil
+}
+
+// hit is one process to stop; wrapped is set when it is a compatibility
+// layer running a blocked game.
+type hit struct {
+	procView
+	wrapped *Wrapped
+}
+
+// label names the hit as reported: the process name, or what a wrapper
+// runs, so a killed wine loader reads as the game it ran.
+func (h hit) label() string {
+	if h.wrapped != nil {
+		return h.wrapped.Runs
+	}
+	return h.Name
+}
+
+// matching returns the processes whose basename matches a configured name,
+// and the wrappers running one, and how many were scanned. A wrapper's
+// arguments are read only for the few processes named like one.
+func (k *Killer) matching() ([]hit, int, error) {
 	procs, err := k.list()
 	if err != nil {
-		return Outcome{}, fmt.Errorf("enumerate processes: %w", err)
+		return nil, 0, fmt.Errorf("enumerate processes: %w", err)
 	}
 	want := make(map[string]struct{}, len(k.names))
 	for _, n := range k.names {
 		want[strings.ToLower(n)] = struct{}{}
 	}
-
-	var out Outcome
-	out.Scanned = len(procs)
+	var hits []hit
 	for _, p := range procs {
-		if _, hit := want[strings.ToLower(p.Name)]; !hit {
+		if _, ok := want[strings.ToLower(p.Name)]; ok {
+			if h := (hit{procView: p}); k.spare == nil || !k.spare(h.label()) {
+				hits = append(hits, h)
+			}
 			continue
 		}
-		if err := k.killPID(p.PID); err != nil {
-			out.Failed = append(out.Failed, fmt.Sprintf("%d: %v", p.PID, err))
+		layer := layerOf(p.Name)
+		if layer == "" {
 			continue
 		}
-		out.KilledPIDs = append(out.KilledPIDs, p.PID)
+		args, err := k.argsOf(p.PID)
+		if err != nil {
+			continue // exited since the scan
+		}
+		if runs := k.wrappedRun(layer, args); runs != "" && (k.spare == nil || !k.spare(runs)) {
+			hits = append(hits, hit{procView: p, wrapped: &Wrapped{PID: p.PID, Layer: layer, Wrapper: p.Name, Runs: runs}})
+		}
 	}
-	sort.Ints(out.KilledPIDs)
-	return out, nil
+	return hits, len(procs), nil
 }
 
 func listProcesses() ([]procView, error) {
-	ps, err := process.Processes()
+	ps, err := proctable.List()
 	if err != nil {
 		return nil, err
 	}
-	out := make([]procView, 0, len(ps))
-	for _, p := range ps {
-		name, err := p.Name()
-		if err != nil {
-			continue // process vanished or unreadable; skip
-		}
-		out = append(out, procView{PID: int(p.Pid), Name: name})
+	out := make([]procView, len(ps))
+	for i, p := range ps {
+		out[i] = procView{PID: p.PID, Name: p.Name}
 	}
 	return out, nil
 }
 
-func killProcess(pid int) error {
-	p, err := process.NewProcess(int32(pid))
-	if err != nil {
-		return err
+// errNotSame: the PID no longer runs the process the scan matched.
+var errNotSame = errors.New("pid no longer runs the matched process")
+
+// killProcess re-reads the PID's name and kills it only if it is still the
+// exact name the scan matched. A vanished process is errNotSame too: there
+// is nothing of ours left to stop.
+func killProcess(v procView) error {
+	if name, err := proctable.NameOf(v.PID); err != nil || !strings.EqualFold(name, v.Name) {
+		return errNotSame
 	}
-	return p.Kill()
+	return proctable.Kill(v.PID)
 }
@@ -2,13 +2,17 @@ package killer
 
 import (
 	"errors"
+	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 func newFake(procs []procView, killErr map[int]error) *Killer {
 	k := New(nil)
 	k.list = func() ([]procView, error) { return procs, nil }
-	k.killPID = func(pid int) error { return killErr[pid] }
+	k.killPID = func(p procView) error { return killErr[p.PID] }
+	k.stopVM = k.killPID
 	return k
 }
 
@@ -40,6 +44,9 @@ func TestKillsExactMatchOnly(t *testing.T) {
 	if out.Scanned != len(procs) {
 		t.Errorf("scanned = %d, want %d", out.Scanned, len(procs))
 	}
+	if got := strings.Join(out.KilledNames, ","); got != "Steam,steam_osx,dota2,STEAM" {
+		t.Errorf("killed names = %q", got)
+	}
 }
 
 func TestNothingRunningIsCleanOutcome(t *testing.T) {
@@ -68,7 +75,7 @@ func TestCustomNamesOverrideDefaults(t *testing.T) {
 	k.list = func() ([]procView, error) {
 		return []procView{{PID: 1, Name: "Steam"}, {PID: 2, Name: "OnlyThis"}}, nil
 	}
-	k.killPID = func(int) error { return nil }
+	k.killPID = func(procView) error { return nil }
 	out, _ := k.Run()
 	if out.KilledCount() != 1 || out.KilledPIDs[0] != 2 {
 		t.Errorf("custom names not honored: %+v", out)
@@ -98,11 +105,51 @@ func TestListProcessesRealIsSafe(t *testing.T) {
 func TestKillProcessInvalidPID(t *testing.T) {
 	// PID 0x7fffffff will not exist; must error, never panic, never
 	// kill anything real.
-	if err := killProcess(0x7fffffff); err == nil {
+	if err := killProcess(procView{PID: 0x7fffffff, Name: "Steam"}); err == nil {
 		t.Error("expected error killing non-existent pid")
 	}
 }
 
+func TestKillProcessRechecksName(t *testing.T) {
+	// A live child stands in for a PID reused after the scan: listed as
+	// "Steam", it now runs sleep, and must survive.
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skip("no sleep binary:", err)
+	}
+	defer cmd.Process.Kill()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	if err := killProcess(procView{PID: cmd.Process.Pid, Name: "Steam"}); !errors.Is(err, errNotSame) {
+		t.Fatalf("reused pid: err = %v, want errNotSame", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("a process that no longer matched was killed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := killProcess(procView{PID: cmd.Process.Pid, Name: "SLEEP"}); err != nil {
+		t.Fatalf("still-matching pid: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("still-matching process not killed")
+	}
+}
+
+func TestRunSkipsReusedPID(t *testing.T) {
+	k := newFake([]procView{{PID: 10, Name: "Steam"}, {PID: 11, Name: "dota2"}}, map[int]error{10: errNotSame})
+	out, err := k.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.KilledCount() != 1 || len(out.Failed) != 0 || len(out.SkippedPIDs) != 1 || out.SkippedPIDs[0] != 10 {
+		t.Fatalf("outcome = %+v", out)
+	}
+}
+
 func TestNewDefaultWiring(t *testing.T) {
 	k := New(nil)
 	if k.list == nil || k.killPID == nil {
@@ -139,3 +186,43 @@ func TestDefaultsCoverSteamAndDota(t *testing.T) {
 		}
 	}
 }
+
+func TestWithProcessesIsADryRun(t *testing.T) {
+	out, err := New(nil).WithProcesses([]string{"Finder", "steam_osx", "msteams", "dota2"}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.KilledNames) != 2 || out.KilledNames[0] != "steam_osx" || out.KilledNames[1] != "dota2" || out.Scanned != 4 {
+		t.Fatalf("outcome = %+v", out)
+	}
+}
+
+func TestDetectKillsNothing(t *testing.T) {
+	k := newFake([]procView{{PID: 12, Name: "msteams"}, {PID: 11, Name: "Steam Helper"}, {PID: 10, Name: "Steam"}}, nil)
+	k.killPID = func(p procView) error {
+		t.Fatalf("Detect killed pid %d", p.PID)
+		return nil
+	}
+	m, err := k.Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Scanned != 3 || len(m.PIDs) != 2 || m.PIDs[0] != 10 || strings.Join(m.Names, ",") != "Steam Helper,Steam" {
+		t.Fatalf("match = %+v", m)
+	}
+}
+
+func TestSparingExemptsByLabel(t *testing.T) {
+	procs := []procView{{PID: 10, Name: "steam_osx"}, {PID: 11, Name: "dota2"}}
+	k := newFake(procs, nil).Sparing(func(label string) bool { return label == "dota2" })
+	out, err := k.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.KilledPIDs) != 1 || out.KilledPIDs[0] != 10 {
+		t.Fatalf("killed %v, want only the unspared steam_osx", out.KilledPIDs)
+	}
+	if m, _ := k.Detect(); len(m.PIDs) != 1 {
+		t.Fatalf("detect must not report a spared process: %+v", m)
+	}
+}
@@ -0,0 +1,88 @@
+// Package netcut is the soft alternative to a kill: matched processes keep
+// running but lose the network. It moves them into a dedicated cgroup (v2)
+// and loads an nftables table that drops that cgroup's outbound traffic,
+// loopback excepted — a launcher that cannot reach its servers is as
+// useless as a dead one, without the crash dialogs. Linux only (Supported).
+//
+// A child process forks into its parent's cgroup, so helpers a restricted
+// launcher spawns are cut too; a fresh launch starts outside it and is
+// moved on the next run.
+package netcut
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Group is the cgroup's and the nftables table's name, neutral on purpose
+// like the plugin's other on-disk names.
+const Group = "lc"
+
+// Outcome summarises a restriction pass.
+type Outcome struct {
+	Restricted []int    `json:"restricted_pids"`
+	Failed     []string `json:"failed,omitempty"` // "pid: reason"
+	// Err is set when the cgroup or the rules could not be put in place;
+	// nothing was restricted then.
+	Err string `json:"error,omitempty"`
+}
+
+// Restrictor puts processes behind the network cut.
+type Restrictor struct {
+	// CgroupRoot is the cgroup v2 mount. Default: /sys/fs/cgroup.
+	CgroupRoot string
+	nft        func(script string) error
+}
+
+// New builds a Restrictor on the system cgroup mount and nft.
+func New() *Restrictor {
+	return &Restrictor{CgroupRoot: "/sys/fs/cgroup", nft: runNft}
+}
+
+// Restrict moves pids into Group and (re)loads the drop rule. The rule is
+// reloaded every pass: nft resolves the cgroup path when the rule loads,
+// so a recreated cgroup needs a fresh rule. A pid that exited meanwhile
+// is reported in Failed.
+func (r *Restrictor) Restrict(pids []int) Outcome {
+	var o Outcome
+	if len(pids) == 0 {
+		return o
+	}
+	dir := filepath.Join(r.CgroupRoot, Group)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		o.Err = fmt.Sprintf("cgroup: %v", err)
+		return o
+	}
+	if err := r.nft(Ruleset()); err != nil {
+		o.Err = fmt.Sprintf("nft: %v", err)
+		return o
+	}
+	procs := filepath.Join(dir, "cgroup.procs")
+	for _, pid := range pids {
+		// cgroup.procs takes one pid per write.
+		if err := os.WriteFile(procs, []byte(strconv.Itoa(pid)), 0o644); err != nil {
+			o.Failed = append(o.Failed, fmt.Sprintf("%d: %v", pid, err))
+			continue
+		}
+		o.Restricted = append(o.Restricted, pid)
+	}
+	return o
+}
+
+// Ruleset is the nft script: it replaces the table wholesale (declare,
+// delete, recreate — idempotent whether or not it existed) with one
+// output-hook rule dropping every non-loopback packet from a socket in
+// Group.
+func Ruleset() string {
+	return fmt.Sprintf(`table inet %[1]s
+delete table inet %[1]s
+table inet %[1]s {
+	chain out {
+		type filter hook output priority 0; policy accept;
+		socket cgroupv2 level 1 "%[1]s" oifname != "lo" drop
+	}
+}
+`, Group)
+}
@@ -0,0 +1,38 @@
+package netcut
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRestrictMovesPIDsAndLoadsTheRule(t *testing.T) {
+	root := t.TempDir()
+	var scripts []string
+	r := &Restrictor{CgroupRoot: root, nft: func(s string) error { scripts = append(scripts, s); return nil }}
+
+	if o := r.Restrict(nil); len(scripts) != 0 || o.Err != "" {
+		t.Fatalf("no pids must touch nothing: %+v", o)
+	}
+	o := r.Restrict([]int{41, 42})
+	if o.Err != "" || len(o.Restricted) != 2 || len(scripts) != 1 {
+		t.Fatalf("outcome %+v, %d scripts", o, len(scripts))
+	}
+	// A plain file keeps only the last write; the kernel file takes each.
+	if b, _ := os.ReadFile(filepath.Join(root, Group, "cgroup.procs")); string(b) != "42" {
+		t.Errorf("cgroup.procs = %q", b)
+	}
+	if !strings.Contains(scripts[0], `socket cgroupv2 level 1 "lc" oifname != "lo" drop`) ||
+		!strings.Contains(scripts[0], "delete table inet lc") {
+		t.Errorf("ruleset:\n%s", scripts[0])
+	}
+}
+
+func TestRestrictReportsAFailedRuleLoad(t *testing.T) {
+	r := &Restrictor{CgroupRoot: t.TempDir(), nft: func(string) error { return errors.New("nft: not found") }}
+	if o := r.Restrict([]int{7}); o.Err == "" || len(o.Restricted) != 0 {
+		t.Fatalf("outcome %+v, want the rule failure and nothing restricted", o)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package netcut
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Supported reports whether this build can restrict at all.
+const Supported = true
+
+// runNft loads script with `nft -f -`.
+func runNft(script string) error {
+	cmd := exec.Command("nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package netcut
+
+import "errors"
+
+// Supported reports whether this build can restrict at all: cgroup v2 and
+// nftables are Linux's.
+const Supported = false
+
+func runNft(string) error { return errors.New("network restriction needs Linux") }
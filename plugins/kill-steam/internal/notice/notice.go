@@ -0,0 +1,140 @@
+// Package notice posts a macOS user notification when kill-steam enforces —
+// "Dota 2 was blocked (attempt 3 today)" — so enforcement is not silent:
+// the running count is the point, showing how often the blocked apps are
+// actually being reached for.
+//
+// Counts are per app per local day, kept in a small tally file. The plugin
+// runs as root in system mode, where a plain osascript would post into
+// root's (invisible) session, so the notification is posted into the
+// console user's GUI session via launchctl asuser.
+package notice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TallyFile is the tally's basename inside the state dir (neutral on purpose).
+const TallyFile = ".tally"
+
+// tally is the persisted per-day count. A new day starts from zero.
+type tally struct {
+	Day    string         `json:"d"`
+	Counts map[string]int `json:"c"`
+}
+
+// AppLabel maps a killed process basename to the app a person would name:
+// the Steam helpers all read "Steam", the Dota binaries "Dota 2"; anything
+// else (a configured process_names entry) is shown as-is.
+func AppLabel(process string) string {
+	p := strings.ToLower(process)
+	switch {
+	case strings.Contains(p, "dota"):
+		return "Dota 2"
+	case strings.Contains(p, "steam"):
+		return "Steam"
+	}
+	return process
+}
+
+// Notifier counts enforcement events and posts notifications.
+type Notifier struct {
+	dir  string
+	now  func() time.Time
+	post func(title, body string) error
+}
+
+// New builds a Notifier whose tally lives in dir.
+func New(dir string) *Notifier {
+	return &Notifier{dir: dir, now: time.Now, post: postNotification}
+}
+
+// Blocked records one blocked launch of app and notifies. It returns the
+// message posted. A tally read/write failure still notifies (with the count
+// it could establish); only a failed post is an error.
+func (n *Notifier) Blocked(app string) (string, error) {
+	c, _ := n.bump(app)
+	return n.send(fmt.Sprintf("%s was blocked (attempt %d today)", app, c))
+}
+
+// InstallBlocked records one removed install of app and notifies.
+func (n *Notifier) InstallBlocked(app string) (string, error) {
+	c, _ := n.bump(app + " install")
+	return n.send(fmt.Sprintf("%s install was removed (attempt %d today)", app, c))
+}
+
+// Closing warns that app is about to be killed, secs from now. It is not
+// a blocked attempt, so the tally is left alone.
+func (n *Notifier) Closing(app string, secs int) (string, error) {
+	return n.send(fmt.Sprintf("%s closes in %d seconds — save your work", app, secs))
+}
+
+func (n *Notifier) send(msg string) (string, error) {
+	if err := n.post("Focus", msg); err != nil {
+		return msg, fmt.Errorf("post notification: %w", err)
+	}
+	return msg, nil
+}
+
+// bump increments today's count for key and returns it (1 when the tally
+// cannot be read).
+func (n *Notifier) bump(key string) (int, error) {
+	day := n.now().Format(time.DateOnly)
+	path := filepath.Join(n.dir, TallyFile)
+	var t tally
+	if b, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(b, &t)
+	}
+	if t.Day != day || t.Counts == nil {
+		t = tally{Day: day, Counts: map[string]int{}}
+	}
+	t.Counts[key]++
+	b, _ := json.Marshal(t)
+	if err := os.MkdirAll(n.dir, 0o700); err != nil {
+		return t.Counts[key], err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return t.Counts[key], err
+	}
+	return t.Counts[key], os.Rename(tmp, path)
+}
+
+// notifyScript takes the body and title as arguments, so neither is ever
+// spliced into AppleScript source.
+var notifyScript = []string{
+	"-e", "on run argv",
+	"-e", "display notification (item 1 of argv) with title (item 2 of argv)",
+	"-e", "end run",
+}
+
+// postNotification runs osascript directly when not root, else inside the
+// console user's session (no console user ⇒ nobody to tell; not an error).
+func postNotification(title, body string) error {
+	args := append(append([]string{}, notifyScript...), body, title)
+	if os.Geteuid() != 0 {
+		return exec.Command("/usr/bin/osascript", args...).Run()
+	}
+	uid, err := consoleUID()
+	if err != nil || uid == 0 {
+		return nil
+	}
+	u := strconv.Itoa(uid)
+	return exec.Command("/bin/launchctl",
+		append([]string{"asuser", u, "/usr/bin/sudo", "-u", "#" + u, "/usr/bin/osascript"}, args...)...).Run()
+}
+
+// consoleUID is the uid owning /dev/console — the logged-in GUI user.
+func consoleUID() (int, error) {
+	out, err := exec.Command("/usr/bin/stat", "-f", "%u", "/dev/console").Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
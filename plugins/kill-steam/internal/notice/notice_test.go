@@ -0,0 +1,102 @@
+package notice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testNotifier(t *testing.T, now *time.Time) (*Notifier, *[]string) {
+	t.Helper()
+	var posted []string
+	n := New(t.TempDir())
+	n.now = func() time.Time { return *now }
+	n.post = func(_, body string) error { posted = append(posted, body); return nil }
+	return n, &posted
+}
+
+func TestBlockedCountsPerAppPerDay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	n, posted := testNotifier(t, &now)
+
+	for i := 0; i < 3; i++ {
+		if _, err := n.Blocked("Dota 2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := n.Blocked("Steam"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.InstallBlocked("Steam"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(24 * time.Hour)
+	if _, err := n.Blocked("Dota 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Dota 2 was blocked (attempt 1 today)",
+		"Dota 2 was blocked (attempt 2 today)",
+		"Dota 2 was blocked (attempt 3 today)",
+		"Steam was blocked (attempt 1 today)",
+		"Steam install was removed (attempt 1 today)",
+		"Dota 2 was blocked (attempt 1 today)", // next day starts over
+	}
+	if len(*posted) != len(want) {
+		t.Fatalf("posted %q", *posted)
+	}
+	for i, w := range want {
+		if (*posted)[i] != w {
+			t.Errorf("post %d = %q, want %q", i, (*posted)[i], w)
+		}
+	}
+}
+
+func TestBlockedSurvivesCorruptTally(t *testing.T) {
+	now := time.Now()
+	n, posted := testNotifier(t, &now)
+	if err := os.WriteFile(filepath.Join(n.dir, TallyFile), []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Blocked("Steam"); err != nil || (*posted)[0] != "Steam was blocked (attempt 1 today)" {
+		t.Fatalf("err=%v posted=%q", err, *posted)
+	}
+}
+
+func TestPostFailureIsReported(t *testing.T) {
+	now := time.Now()
+	n, _ := testNotifier(t, &now)
+	n.post = func(string, string) error { return errors.New("no session") }
+	if _, err := n.Blocked("Steam"); err == nil {
+		t.Fatal("a failed post must be reported")
+	}
+}
+
+func TestAppLabel(t *testing.T) {
+	for proc, want := range map[string]string{
+		"steam_osx": "Steam", "Steam Helper (GPU)": "Steam",
+		"dota_osx64": "Dota 2", "Dota 2": "Dota 2", "Factorio": "Factorio",
+	} {
+		if got := AppLabel(proc); got != want {
+			t.Errorf("AppLabel(%q) = %q, want %q", proc, got, want)
+		}
+	}
+}
+
+func TestClosingLeavesTallyAlone(t *testing.T) {
+	now := time.Now()
+	n, posted := testNotifier(t, &now)
+	if _, err := n.Closing("Dota 2", 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Blocked("Dota 2"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Dota 2 closes in 10 seconds — save your work", "Dota 2 was blocked (attempt 1 today)"}
+	if len(*posted) != 2 || (*posted)[0] != want[0] || (*posted)[1] != want[1] {
+		t.Fatalf("posted %q", *posted)
+	}
+}
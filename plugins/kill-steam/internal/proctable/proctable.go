@@ -0,0 +1,73 @@
+// Package proctable reads the process table once per scan. The killer and
+// the bypass detector both match basenames against the whole table every
+// run; asking for each process's name one by one (gopsutil's
+// Processes+Name) costs several syscalls per PID and dominated the
+// plugin's CPU on a busy Mac. On darwin one `ps` run returns every
+// basename at once.
+package proctable
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Proc is one process: its PID and executable basename.
+type Proc struct {
+	PID  int
+	Name string
+}
+
+// List returns every process whose name could be read.
+func List() ([]Proc, error) { return list() }
+
+// NameOf re-reads one PID's basename, by the same source as List so a
+// match made on a List name compares like for like.
+func NameOf(pid int) (string, error) { return nameOf(pid) }
+
+// ArgsOf reads one PID's full command line, for the few processes whose
+// arguments matter (a Wine loader, a VM host).
+func ArgsOf(pid int) (string, error) { return argsOf(pid) }
+
+// Kill sends SIGKILL to pid.
+func Kill(pid int) error {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// Terminate sends SIGTERM to pid, for a process that must be let to shut
+// down cleanly.
+func Terminate(pid int) error {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return err
+	}
+	return p.Terminate()
+}
+
+// parsePS reads `ps -axo pid=,comm=` output. comm is the executable path
+// (or bare name when the path is not readable) and may contain spaces
+// ("Steam Helper (GPU)"), so everything after the PID is the name. Lines
+// that do not start with a PID are skipped.
+func parsePS(out []byte) []Proc {
+	var procs []Proc
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		f, rest, ok := strings.Cut(strings.TrimSpace(sc.Text()), " ")
+		pid, err := strconv.Atoi(f)
+		if !ok || err != nil {
+			continue
+		}
+		if name := path.Base(strings.TrimSpace(rest)); name != "" && name != "." && name != "/" {
+			procs = append(procs, Proc{PID: pid, Name: name})
+		}
+	}
+	return procs
+}
@@ -0,0 +1,37 @@
+package proctable
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func list() ([]Proc, error) {
+	out, err := exec.Command("/bin/ps", "-axo", "pid=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ps: %w", err)
+	}
+	return parsePS(out), nil
+}
+
+func nameOf(pid int) (string, error) {
+	out, err := exec.Command("/bin/ps", "-o", "pid=,comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", fmt.Errorf("ps: %w", err) // ps exits 1 when the PID is gone
+	}
+	for _, p := range parsePS(out) {
+		if p.PID == pid {
+			return p.Name, nil
+		}
+	}
+	return "", fmt.Errorf("pid %d not listed", pid)
+}
+
+func argsOf(pid int) (string, error) {
+	out, err := exec.Command("/bin/ps", "-ww", "-o", "args=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", fmt.Errorf("ps: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,39 @@
+//go:build !darwin
+
+package proctable
+
+import "github.com/shirou/gopsutil/v3/process"
+
+// list reads the table through gopsutil, which off darwin reads /proc
+// without a syscall storm.
+func list() ([]Proc, error) {
+	ps, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Proc, 0, len(ps))
+	for _, p := range ps {
+		name, err := p.Name()
+		if err != nil {
+			continue // process vanished or unreadable; skip
+		}
+		out = append(out, Proc{PID: int(p.Pid), Name: name})
+	}
+	return out, nil
+}
+
+func nameOf(pid int) (string, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return "", err
+	}
+	return p.Name()
+}
+
+func argsOf(pid int) (string, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return "", err
+	}
+	return p.Cmdline()
+}
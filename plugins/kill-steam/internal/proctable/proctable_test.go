@@ -0,0 +1,42 @@
+package proctable
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParsePS(t *testing.T) {
+	out := []byte(`    1 /sbin/launchd
+  412 /Applications/Steam.app/Contents/MacOS/steam_osx
+  418 /Applications/Steam.app/Contents/Frameworks/Steam Helper.app/Contents/MacOS/Steam Helper (GPU)
+  500 msteams
+  PID COMMAND
+  601
+`)
+	want := []Proc{
+		{1, "launchd"},
+		{412, "steam_osx"},
+		{418, "Steam Helper (GPU)"},
+		{500, "msteams"},
+	}
+	if got := parsePS(out); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePS = %+v", got)
+	}
+}
+
+func TestListFindsSelf(t *testing.T) {
+	procs, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range procs {
+		if p.PID == os.Getpid() {
+			if n, err := NameOf(p.PID); err != nil || n != p.Name {
+				t.Fatalf("NameOf = %q, %v; List said %q", n, err, p.Name)
+			}
+			return
+		}
+	}
+	t.Fatalf("own pid missing from %d processes", len(procs))
+}
@@ -0,0 +1,121 @@
+// Package severity grades how hard kill-steam responds to a match, and
+// escalates the grade when the blocked apps keep coming back.
+//
+//	low    — log the matches; kill and remove nothing
+//	medium — kill matching processes; leave installs on disk
+//	high   — kill, remove the install and its data (the default)
+//
+// A low or medium policy can escalate: with escalate_after N, every N
+// launches seen within the last hour raise the response one level, so a
+// mild policy that is being worked around turns into the full one.
+package severity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Level is an enforcement grade, ordered low < medium < high.
+type Level int
+
+const (
+	Low Level = iota
+	Medium
+	High
+)
+
+// Window is how far back launches count toward escalation.
+const Window = time.Hour
+
+// LedgerFile is the launch ledger's basename inside the state dir (neutral
+// on purpose, like the notice tally).
+const LedgerFile = ".esc"
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	}
+	return "high"
+}
+
+// Parse maps a config value to a Level; "" is High (the pre-severity
+// behaviour, so an unset policy never loosens).
+func Parse(s string) (Level, error) {
+	switch s {
+	case "", "high":
+		return High, nil
+	case "medium":
+		return Medium, nil
+	case "low":
+		return Low, nil
+	}
+	return High, fmt.Errorf("severity must be one of low, medium, high")
+}
+
+// Escalate is base raised one level per every launches seen, capped at
+// High. every <= 0 disables escalation.
+func Escalate(base Level, launches, every int) Level {
+	if every <= 0 {
+		return base
+	}
+	return min(High, base+Level(launches/every))
+}
+
+// ledger is the persisted launch history: when each launch was first seen,
+// and the PIDs matched last pass (to tell a relaunch from a survivor).
+type ledger struct {
+	Launches []time.Time `json:"l"`
+	PIDs     []int       `json:"p"`
+}
+
+// Ledger counts launches of matched processes across runs.
+type Ledger struct {
+	dir string
+	now func() time.Time
+}
+
+// NewLedger builds a Ledger kept in dir.
+func NewLedger(dir string) *Ledger {
+	return &Ledger{dir: dir, now: time.Now}
+}
+
+// Observe records this pass's matched PIDs and returns the launches within
+// Window. A pass with a PID the previous pass did not see is one launch —
+// a single Steam start spawns several helpers, which is one attempt. A
+// process left running (low severity) is not counted again. A ledger that
+// cannot be read starts empty; the count is still returned when the write
+// fails.
+func (l *Ledger) Observe(pids []int) (int, error) {
+	path := filepath.Join(l.dir, LedgerFile)
+	var g ledger
+	if b, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(b, &g)
+	}
+	now := l.now()
+	for _, pid := range pids {
+		if !slices.Contains(g.PIDs, pid) {
+			g.Launches = append(g.Launches, now)
+			break
+		}
+	}
+	g.Launches = slices.DeleteFunc(g.Launches, func(t time.Time) bool {
+		return now.Sub(t) >= Window
+	})
+	g.PIDs = pids
+	b, _ := json.Marshal(g)
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return len(g.Launches), err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return len(g.Launches), err
+	}
+	return len(g.Launches), os.Rename(tmp, path)
+}
@@ -0,0 +1,65 @@
+package severity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Level{"": High, "high": High, "medium": Medium, "low": Low} {
+		if got, err := Parse(in); err != nil || got != want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := Parse("extreme"); err == nil {
+		t.Error("Parse must reject an unknown level")
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	for _, c := range []struct {
+		base            Level
+		launches, every int
+		want            Level
+	}{
+		{Low, 5, 0, Low},
+		{Low, 2, 3, Low},
+		{Low, 3, 3, Medium},
+		{Low, 6, 3, High},
+		{Medium, 9, 3, High},
+	} {
+		if got := Escalate(c.base, c.launches, c.every); got != c.want {
+			t.Errorf("Escalate(%v, %d, %d) = %v, want %v", c.base, c.launches, c.every, got, c.want)
+		}
+	}
+}
+
+func TestLedgerCountsRelaunchesWithinTheHour(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	l := NewLedger(t.TempDir())
+	l.now = func() time.Time { return now }
+	observe := func(pids ...int) int {
+		t.Helper()
+		n, err := l.Observe(pids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if n := observe(10, 11); n != 1 {
+		t.Fatalf("first launch: %d", n)
+	}
+	if n := observe(10, 11); n != 1 {
+		t.Fatalf("a survivor is not a relaunch: %d", n)
+	}
+	observe()
+	now = now.Add(10 * time.Minute)
+	if n := observe(20, 21, 22); n != 2 {
+		t.Fatalf("relaunch: %d", n)
+	}
+	now = now.Add(55 * time.Minute)
+	if n := observe(30); n != 2 {
+		t.Fatalf("the launch at 9:00 left the window: %d", n)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package uninstaller
+
+import (
+	"os"
+	"syscall"
+)
+
+// chflags(2) bits: the owner's and the system's immutable flag.
+const (
+	flagUserImmutable   = 0x2     // uchg
+	flagSystemImmutable = 0x20000 // schg
+)
+
+// lock makes path immutable: schg when running as root (system mode), so
+// only root can lift it, otherwise uchg.
+func lock(path string) error {
+	flag := flagUserImmutable
+	if os.Geteuid() == 0 {
+		flag = flagSystemImmutable
+	}
+	return syscall.Chflags(path, flag)
+}
+
+// unlock clears every flag lock may have set.
+func unlock(path string) error {
+	return syscall.Chflags(path, 0)
+}
@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package uninstaller
+
+// lock has no immutable flag to set off macOS; the read-only placeholder
+// is the whole tombstone there.
+func lock(string) error { return nil }
+
+func unlock(string) error { return nil }
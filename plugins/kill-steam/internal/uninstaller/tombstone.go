@@ -0,0 +1,92 @@
+package uninstaller
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// tombstoneBody marks a placeholder as ours, so Reconcile never mistakes it
+// for an install and Tombstones never touches a file it did not write.
+const tombstoneBody = "reserved\n"
+
+// MaxTombstoneDays bounds how long a removed app's location stays locked.
+const MaxTombstoneDays = 365
+
+// isTombstone reports whether path is a placeholder this package wrote: a
+// regular file (an app bundle is a directory) holding exactly the marker.
+func isTombstone(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() != int64(len(tombstoneBody)) {
+		return false
+	}
+	b, err := os.ReadFile(path)
+	return err == nil && bytes.Equal(b, []byte(tombstoneBody))
+}
+
+// Tombstones manages the placeholders at removed app locations. With ttl
+// > 0, each app bundle in removed gets a read-only, immutable file at its
+// path, so reinstalling to the default location fails; a placeholder older
+// than ttl is unlocked and deleted. With ttl 0 every existing placeholder
+// is deleted. placed lists the new ones; errs holds one line per failure.
+func (r *Reconciler) Tombstones(removed []string, ttl time.Duration, now time.Time) (placed []string, cleared int, errs []string) {
+	for _, t := range r.systemTargets() {
+		if !isTombstone(t.Path) {
+			continue
+		}
+		info, err := os.Lstat(t.Path)
+		if err != nil || (ttl > 0 && now.Sub(info.ModTime()) < ttl) {
+			continue
+		}
+		if err := removeTombstone(t.Path); err != nil {
+			errs = append(errs, "clear tombstone ("+t.What+"): "+err.Error())
+			continue
+		}
+		cleared++
+	}
+	if ttl <= 0 {
+		return nil, cleared, errs
+	}
+	for _, t := range r.systemTargets() {
+		if !strings.HasSuffix(t.Path, ".app") || !slices.Contains(removed, t.Path) {
+			continue
+		}
+		if err := placeTombstone(t.Path); err != nil {
+			errs = append(errs, "tombstone ("+t.What+"): "+err.Error())
+			continue
+		}
+		placed = append(placed, t.Path)
+	}
+	return placed, cleared, errs
+}
+
+// placeTombstone writes the placeholder read-only and locks it.
+func placeTombstone(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o444)
+	if err != nil {
+		return err
+	}
+	_, werr := f.WriteString(tombstoneBody)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		_ = os.Remove(path)
+		return werr
+	}
+	return lock(path)
+}
+
+// removeTombstone unlocks and deletes a placeholder.
+func removeTombstone(path string) error {
+	if err := unlock(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -15,11 +15,31 @@ package uninstaller
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
+// Removal pacing defaults. A pass has one deadline, DefaultBudget after it
+// starts unless the caller sets Deadline, and each path's time comes out of
+// what is left of it, capped at DefaultPathTimeout so one huge tree cannot
+// take a worker for the whole pass. However many paths are slow, the pass
+// ends with progress made inside the signed job timeout (20s) instead of
+// the runner killing the plugin mid-delete.
+const (
+	DefaultWorkers     = 4
+	DefaultPathTimeout = 10 * time.Second
+	DefaultBudget      = 15 * time.Second
+)
+
+// errUnfinished marks a removal that ran out of its time.
+var errUnfinished = errors.New("removal budget spent")
+
 // systemTarget is a literal path removed if present.
 type systemTarget struct {
 	Path string
@@ -50,6 +70,16 @@ var DefaultPerUserTargets = []perUserTarget{
 	{RelPath: "Library/Logs/DiagnosticReports", What: "Dota 2 crash reports (best effort glob)"}, // filtered by name
 }
 
+// DefaultLibraryTargets are Dota 2's files in a Steam library, removed from
+// every library found on a mounted volume (Reconciler.ScanVolumes). Only
+// Dota 2's own: an external library may hold games nobody objects to.
+var DefaultLibraryTargets = []perUserTarget{
+	{RelPath: "steamapps/common/dota 2 beta", What: "Dota 2 game files (external library)"},
+	{RelPath: "steamapps/appmanifest_570.acf", What: "Dota 2 app manifest (external library)"},
+	{RelPath: "steamapps/shadercache/570", What: "Dota 2 shader cache (external library)"},
+	{RelPath: "steamapps/workshop/content/570", What: "Dota 2 workshop content (external library)"},
+}
+
 // Reconciler is the testable surface. Override AppPath / UsersDir for
 // tests; defaults are the real macOS paths.
 type Reconciler struct {
@@ -60,6 +90,40 @@ type Reconciler struct {
 	// System and PerUser default to Default*Targets unless overridden.
 	System  []systemTarget
 	PerUser []perUserTarget
+	// Exclude carves known-safe matches out of the sweep: glob patterns
+	// (path.Match syntax) checked against each candidate's absolute path,
+	// or against its basename when the pattern has no "/". An excluded
+	// candidate is reported, never removed. See ValidExclude.
+	Exclude []string
+	// ScanVolumes also looks for Steam libraries on mounted volumes: a
+	// folder holding steamapps/ at the top of a volume under VolumesDir, or
+	// one or two levels below it (Steam's "SteamLibrary" folder). Each found
+	// loses its DefaultLibraryTargets and counts as a library for
+	// {{SteamLibrary}} paths — moving the library to an external SSD, and
+	// out of libraryfolders.vdf, no longer hides it.
+	ScanVolumes bool
+	// VolumesDir holds the mount points. Default: /Volumes.
+	VolumesDir string
+	// Paths are extra per-user targets: glob patterns that start with a
+	// {{Home}} or {{SteamLibrary}} variable and may use {{User}}. Each is
+	// expanded for every user home and, for {{SteamLibrary}}, every Steam
+	// library of that user (the default one plus any libraryfolders.vdf
+	// lists). See ValidPaths.
+	Paths []string
+	// Workers is how many paths are removed at once. Default: DefaultWorkers.
+	Workers int
+	// PathTimeout caps the time spent on any one path. A tree still on disk
+	// when it runs out is reported Unfinished and picked up again next pass.
+	// Default: DefaultPathTimeout.
+	PathTimeout time.Duration
+	// Deadline ends the whole pass: a path not finished by then, or not
+	// yet started, is Unfinished. The job sets it from its own start.
+	// Default: DefaultBudget after Reconcile starts.
+	Deadline time.Time
+
+	// root is the sandbox a Rooted reconciler resolves under; exclusions
+	// match the path as it would be on /.
+	root string
 }
 
 // Outcome summarises a single Reconcile pass.
@@ -68,14 +132,50 @@ type Outcome struct {
 	Removed  []string `json:"removed,omitempty"`
 	Errors   []string `json:"errors,omitempty"`
 	Reason   string   `json:"reason"`
+	// Excluded are the present artifacts an Exclude pattern spared.
+	Excluded []string `json:"excluded,omitempty"`
+	// Unfinished are the paths whose removal ran out of time; what was
+	// deleted of them stays deleted and counts in ReclaimedBytes.
+	Unfinished []string `json:"unfinished,omitempty"`
+	// ReclaimedBytes is the size of the regular files removed (symlinks
+	// are not followed); ReclaimedFiles is how many there were.
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+	ReclaimedFiles int   `json:"reclaimed_files"`
+	// Forensics record, per path, what was on disk just before its
+	// removal began, in Plan order: the proof of what was deleted.
+	Forensics []Forensic `json:"forensics,omitempty"`
+}
+
+// Forensic is one path as measured before it was removed.
+type Forensic struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"` // regular files only, as in ReclaimedBytes
+	Files int    `json:"files"`
+	// Partial: the walk ran out of the path's time budget, so the totals
+	// are a lower bound.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// tally counts the regular files a walk saw or removed.
+type tally struct {
+	bytes int64
+	files int
+}
+
+func (t *tally) add(info fs.FileInfo) {
+	if info.Mode().IsRegular() {
+		t.bytes += info.Size()
+		t.files++
+	}
 }
 
 // Detect is the cheap path: does Steam.app exist? Used as the gate
 // before any expensive enumeration / removal. Steady-state noop ticks
 // hit this and return in <1 ms.
+// A tombstone left at the path (see Tombstones) is not Steam.
 func (r *Reconciler) Detect() bool {
 	_, err := os.Stat(r.appPath())
-	return err == nil
+	return err == nil && !isTombstone(r.appPath())
 }
 
 // Reconcile is the full pass: sweep every known Steam artifact (system +
@@ -85,32 +185,67 @@ func (r *Reconciler) Detect() bool {
 // tens of GB) still on disk and re-launchable. Each removal is os.Stat-gated,
 // so a clean steady-state pass is cheap and idempotent; a pass with leftover
 // Dota 2 / Steam data removes it. (Detected is kept as informational only.)
+//
+// Paths are removed Workers at a time, each within PathTimeout, so one
+// tens-of-GB library no longer holds up the small targets behind it, and
+// all of them within Deadline. Results are reported in Plan order
+// regardless.
 func (r *Reconciler) Reconcile() Outcome {
 	o := Outcome{Detected: r.Detect()}
-
-	for _, t := range r.systemTargets() {
-		r.tryRemove(t.Path, t.What, &o)
+	deadline := r.Deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(DefaultBudget)
 	}
 
-	homes, err := r.findUserHomes()
+	found, skipped, err := r.scan()
+	o.Excluded = skipped
 	if err != nil {
 		o.Errors = append(o.Errors, fmt.Sprintf("enumerate users: %v", err))
 	}
-	for _, home := range homes {
-		for _, t := range r.perUserTargets() {
-			full := filepath.Join(home, t.RelPath)
-			// Special-case the DiagnosticReports dir — don't rm the dir,
-			// just the dota2-*.ips files in it.
-			if strings.HasSuffix(t.RelPath, "DiagnosticReports") {
-				r.cleanCrashReports(full, &o)
-				continue
+	type removal struct {
+		before Forensic
+		freed  tally
+		err    error
+	}
+	done := make([]removal, len(found))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(r.workers(), len(found)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				done[i].before, done[i].freed, done[i].err = r.remove(found[i], deadline)
 			}
-			r.tryRemove(full, t.What, &o)
+		}()
+	}
+	for i := range found {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, f := range found {
+		o.ReclaimedBytes += done[i].freed.bytes
+		o.ReclaimedFiles += done[i].freed.files
+		if done[i].before.Path != "" {
+			o.Forensics = append(o.Forensics, done[i].before)
+		}
+		switch err := done[i].err; {
+		case err == nil:
+			o.Removed = append(o.Removed, f.path)
+		case errors.Is(err, errUnfinished):
+			o.Unfinished = append(o.Unfinished, f.path)
+		case !f.crashReport:
+			// A crash report that won't go is best effort, not a failure.
+			o.Errors = append(o.Errors, fmt.Sprintf("%s (%s): %v", f.what, f.path, err))
 		}
 	}
 
-	switch len(o.Removed) {
-	case 0:
+	switch {
+	case len(o.Unfinished) > 0:
+		o.Reason = fmt.Sprintf("removed %d artifact(s), %d unfinished", len(o.Removed), len(o.Unfinished))
+	case len(o.Removed) == 0:
 		o.Reason = "clean (no Steam/Dota artifacts present)"
 	default:
 		o.Reason = fmt.Sprintf("removed %d artifact(s)", len(o.Removed))
@@ -118,32 +253,397 @@ func (r *Reconciler) Reconcile() Outcome {
 	return o
 }
 
-func (r *Reconciler) tryRemove(path, what string, o *Outcome) {
-	if _, err := os.Stat(path); err != nil {
-		return // not present
+// remove measures one found artifact, then deletes it within PathTimeout
+// or by the pass's deadline, whichever comes first, and returns the
+// measure and what was freed. Measuring may take at most half the time, so
+// a tree too big for one pass still shrinks each pass rather than being
+// measured and left. A crash report is a file; it is never removed as a
+// tree that happens to match. A path already gone has no measure, and one
+// reached after the deadline is not touched.
+func (r *Reconciler) remove(f present, deadline time.Time) (Forensic, tally, error) {
+	start := time.Now()
+	if !start.Before(deadline) {
+		return Forensic{}, tally{}, errUnfinished
 	}
-	if err := os.RemoveAll(path); err != nil {
-		o.Errors = append(o.Errors, fmt.Sprintf("%s (%s): %v", what, path, err))
-		return
+	if f.crashReport {
+		info, err := os.Lstat(f.path)
+		if err != nil {
+			return Forensic{}, tally{}, err
+		}
+		var t tally
+		t.add(info)
+		before := Forensic{Path: f.path, Bytes: t.bytes, Files: t.files}
+		if err := os.Remove(f.path); err != nil {
+			return before, tally{}, err
+		}
+		return before, t, nil
+	}
+	timeout := r.PathTimeout
+	if timeout <= 0 {
+		timeout = DefaultPathTimeout
+	}
+	timeout = min(timeout, deadline.Sub(start))
+	before, err := measure(f.path, start.Add(timeout/2))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Forensic{}, tally{}, nil // gone already, as os.RemoveAll treats it
 	}
-	o.Removed = append(o.Removed, path)
+	var t tally
+	err = removeTree(f.path, start.Add(timeout), &t)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return before, t, err
 }
 
-func (r *Reconciler) cleanCrashReports(dir string, o *Outcome) {
-	entries, err := os.ReadDir(dir)
+// measure totals the regular files under path without following
+// symlinks, stopping at deadline with a Partial result. Unreadable
+// entries are skipped; only a missing path is an error.
+func measure(path string, deadline time.Time) (Forensic, error) {
+	f := Forensic{Path: path}
+	var t tally
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == path {
+				return err
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			f.Partial = true
+			return filepath.SkipAll
+		}
+		if d.Type().IsRegular() {
+			if info, ierr := d.Info(); ierr == nil {
+				t.add(info)
+			}
+		}
+		return nil
+	})
+	f.Bytes, f.Files = t.bytes, t.files
+	return f, err
+}
+
+// removeTree deletes path depth first, adding each regular file it
+// deletes to t, and stops with errUnfinished once deadline passes. Like
+// os.RemoveAll it keeps going past an entry it cannot delete, so one
+// stuck file does not leave the rest of the tree; the errors are joined.
+// Symlinks are removed, never followed.
+func removeTree(path string, deadline time.Time, t *tally) error {
+	if time.Now().After(deadline) {
+		return errUnfinished
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		for _, e := range entries {
+			err := removeTree(filepath.Join(path, e.Name()), deadline, t)
+			if errors.Is(err, errUnfinished) {
+				return errors.Join(append(errs, err)...)
+			}
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...) // the directory cannot be empty
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	t.add(info)
+	return nil
+}
+
+func (r *Reconciler) workers() int {
+	if r.Workers > 0 {
+		return r.Workers
+	}
+	return DefaultWorkers
+}
+
+// Plan returns the paths Reconcile would remove right now, in the order it
+// would remove them. It removes nothing.
+func (r *Reconciler) Plan() []string {
+	found, _, _ := r.scan()
+	paths := make([]string, len(found))
+	for i, f := range found {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+// Spared returns the present paths an Exclude pattern keeps out of a pass.
+func (r *Reconciler) Spared() []string {
+	_, skipped, _ := r.scan()
+	return skipped
+}
+
+// Rooted returns a Reconciler whose system and per-user targets all resolve
+// under root instead of /, for simulating a pass against a sandbox tree.
+func Rooted(root string) *Reconciler {
+	sys := make([]systemTarget, len(DefaultSystemTargets))
+	for i, t := range DefaultSystemTargets {
+		sys[i] = systemTarget{Path: filepath.Join(root, t.Path), What: t.What}
+	}
+	return &Reconciler{
+		AppPath:    filepath.Join(root, "Applications", "Steam.app"),
+		UsersDir:   filepath.Join(root, "Users"),
+		VolumesDir: filepath.Join(root, "Volumes"),
+		System:     sys,
+		root:       root,
+	}
+}
+
+// Path template variables.
+const (
+	VarHome         = "{{Home}}"
+	VarUser         = "{{User}}"
+	VarSteamLibrary = "{{SteamLibrary}}"
+)
+
+// ValidPaths reports the first unusable Paths pattern: one that is not
+// anchored at {{Home}}/ or {{SteamLibrary}}/ with something below it, whose
+// first element below the anchor is a glob, has a ".." element, or is not
+// a valid glob. The anchor keeps a pattern from reaching outside a user's
+// own files, and a literal first element from matching everything in the
+// home or library: "{{Home}}/*" would remove the whole home directory.
+func ValidPaths(patterns []string) error {
+	for _, p := range patterns {
+		rest, ok := strings.CutPrefix(p, VarHome+"/")
+		if !ok {
+			rest, ok = strings.CutPrefix(p, VarSteamLibrary+"/")
+		}
+		first, _, _ := strings.Cut(strings.TrimLeft(rest, "/"), "/")
+		switch {
+		case !ok || strings.Trim(rest, "/") == "":
+			return fmt.Errorf("path %q must start with %s/ or %s/ and name something below it", p, VarHome, VarSteamLibrary)
+		case strings.ContainsAny(first, "*?[\\"):
+			return fmt.Errorf("path %q: the first element below the anchor must be a literal name", p)
+		case slices.Contains(strings.Split(rest, "/"), ".."):
+			return fmt.Errorf("path %q must not contain ..", p)
+		case strings.Contains(strings.ReplaceAll(rest, VarUser, ""), "{{"):
+			return fmt.Errorf("path %q: only %s may follow the anchor", p, VarUser)
+		}
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("path %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// expandPaths resolves r.Paths for one home and globs each expansion.
+func (r *Reconciler) expandPaths(home string, volumeLibs []string) []present {
+	if len(r.Paths) == 0 {
+		return nil
+	}
+	libs := r.steamLibraries(home)
+	for _, l := range volumeLibs {
+		if !slices.Contains(libs, l) {
+			libs = append(libs, l)
+		}
+	}
+	user := filepath.Base(home)
+	var found []present
+	for _, p := range r.Paths {
+		p = strings.ReplaceAll(p, VarUser, user)
+		anchors := []string{home}
+		rest, ok := strings.CutPrefix(p, VarHome)
+		if !ok {
+			rest, _ = strings.CutPrefix(p, VarSteamLibrary)
+			anchors = libs
+		}
+		for _, a := range anchors {
+			matches, _ := filepath.Glob(filepath.Join(a, rest))
+			for _, m := range matches {
+				found = append(found, present{path: m, what: "configured path"})
+			}
+		}
+	}
+	return found
+}
+
+// libraryPathRe pulls each library's "path" out of libraryfolders.vdf.
+var libraryPathRe = regexp.MustCompile(`"path"\s+"((?:[^"\\]|\\.)*)"`)
+
+// steamLibraries returns home's default Steam library and every other one
+// its libraryfolders.vdf lists (secondary drives, custom folders), each
+// once. Under a Rooted reconciler a listed path resolves under root.
+func (r *Reconciler) steamLibraries(home string) []string {
+	def := filepath.Join(home, "Library", "Application Support", "Steam")
+	libs := []string{def}
+	b, err := os.ReadFile(filepath.Join(def, "steamapps", "libraryfolders.vdf"))
 	if err != nil {
-		return // dir doesn't exist or unreadable — fine
+		return libs
 	}
+	for _, m := range libraryPathRe.FindAllStringSubmatch(string(b), -1) {
+		lib := strings.ReplaceAll(m[1], `\\`, `\`)
+		if !filepath.IsAbs(lib) || filepath.Clean(lib) == "/" {
+			continue // a library is a folder of its own, never the disk root
+		}
+		if r.root != "" {
+			lib = filepath.Join(r.root, lib)
+		}
+		if lib = filepath.Clean(lib); !slices.Contains(libs, lib) {
+			libs = append(libs, lib)
+		}
+	}
+	return libs
+}
+
+// volumeLibraries returns the Steam libraries on mounted volumes: every
+// folder at depth 0..2 below a mount point that holds a steamapps folder.
+// Symlinked entries are skipped — the boot volume appears under /Volumes
+// as a link to /, and following it would sweep the whole disk.
+func (r *Reconciler) volumeLibraries() []string {
+	if !r.ScanVolumes {
+		return nil
+	}
+	dir := r.VolumesDir
+	if dir == "" {
+		dir = "/Volumes"
+	}
+	var libs []string
+	var walk func(d string, depth int)
+	walk = func(d string, depth int) {
+		if fi, err := os.Stat(filepath.Join(d, "steamapps")); err == nil && fi.IsDir() {
+			libs = append(libs, d)
+			return
+		}
+		if depth == 2 {
+			return
+		}
+		entries, _ := os.ReadDir(d)
+		for _, e := range entries {
+			if e.IsDir() && e.Type()&fs.ModeSymlink == 0 && !strings.HasPrefix(e.Name(), ".") {
+				walk(filepath.Join(d, e.Name()), depth+1)
+			}
+		}
+	}
+	entries, _ := os.ReadDir(dir)
 	for _, e := range entries {
-		name := e.Name()
-		if !strings.HasPrefix(strings.ToLower(name), "dota2") {
+		if e.IsDir() && e.Type()&fs.ModeSymlink == 0 && !strings.HasPrefix(e.Name(), ".") {
+			walk(filepath.Join(dir, e.Name()), 0)
+		}
+	}
+	return libs
+}
+
+// ValidExclude reports the first malformed pattern in patterns.
+func ValidExclude(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("exclude pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// excluded reports whether an Exclude pattern matches path.
+func (r *Reconciler) excluded(path string) bool {
+	if r.root != "" {
+		if rel, err := filepath.Rel(r.root, path); err == nil {
+			path = "/" + filepath.ToSlash(rel)
+		}
+	}
+	for _, p := range r.Exclude {
+		subject := path
+		if !strings.Contains(p, "/") {
+			subject = filepath.Base(path)
+		}
+		if ok, _ := filepath.Match(p, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// present is one on-disk artifact a pass removes.
+type present struct {
+	path, what  string
+	crashReport bool
+}
+
+// scan is candidates less the ones an Exclude pattern matches, which are
+// returned apart as skipped.
+func (r *Reconciler) scan() (found []present, skipped []string, err error) {
+	all, err := r.candidates()
+	for _, f := range all {
+		if r.excluded(f.path) {
+			skipped = append(skipped, f.path)
 			continue
 		}
-		full := filepath.Join(dir, name)
-		if err := os.Remove(full); err == nil {
-			o.Removed = append(o.Removed, full)
+		found = append(found, f)
+	}
+	return found, skipped, err
+}
+
+// candidates finds every target present on disk: system targets, then the
+// Dota 2 files of each volume library (ScanVolumes), then each user's
+// targets, with the DiagnosticReports dir expanded to its dota2-*
+// files (the dir itself stays). The error is from enumerating user homes;
+// system targets are still returned.
+func (r *Reconciler) candidates() ([]present, error) {
+	var found []present
+	exists := func(path string) bool { _, err := os.Stat(path); return err == nil }
+	for _, t := range r.systemTargets() {
+		if exists(t.Path) && !isTombstone(t.Path) {
+			found = append(found, present{path: t.Path, what: t.What})
+		}
+	}
+	volumeLibs := r.volumeLibraries()
+	for _, lib := range volumeLibs {
+		for _, t := range DefaultLibraryTargets {
+			if full := filepath.Join(lib, t.RelPath); exists(full) {
+				found = append(found, present{path: full, what: t.What})
+			}
+		}
+	}
+	homes, err := r.findUserHomes()
+	for _, home := range homes {
+		for _, t := range r.perUserTargets() {
+			full := filepath.Join(home, t.RelPath)
+			if strings.HasSuffix(t.RelPath, "DiagnosticReports") {
+				found = append(found, crashReports(full)...)
+				continue
+			}
+			if exists(full) {
+				found = append(found, present{path: full, what: t.What})
+			}
+		}
+		found = append(found, r.expandPaths(home, volumeLibs)...)
+	}
+	// A configured {{SteamLibrary}} path can name a library target again.
+	seen := make(map[string]bool, len(found))
+	found = slices.DeleteFunc(found, func(f present) bool {
+		dup := seen[f.path]
+		seen[f.path] = true
+		return dup
+	})
+	return found, err
+}
+
+// crashReports lists the dota2-* files in a DiagnosticReports dir; a
+// missing or unreadable dir has none.
+func crashReports(dir string) []present {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var found []present
+	for _, e := range entries {
+		if strings.HasPrefix(strings.ToLower(e.Name()), "dota2") {
+			found = append(found, present{path: filepath.Join(dir, e.Name()), what: "Dota 2 crash report", crashReport: true})
 		}
 	}
+	return found
 }
 
 func (r *Reconciler) appPath() string {
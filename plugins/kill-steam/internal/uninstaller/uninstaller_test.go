@@ -1,9 +1,13 @@
 package uninstaller
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestDetect_AbsentIsCheap(t *testing.T) {
@@ -77,6 +81,22 @@ func TestReconcile_RemovesEverything(t *testing.T) {
 	if len(o.Removed) < 3 {
 		t.Fatalf("expected ≥3 removals, got %d: %+v", len(o.Removed), o.Removed)
 	}
+	// Info.plist (1) + two config.vdf (1 each) + the agent plist (8).
+	if o.ReclaimedBytes != 11 || o.ReclaimedFiles != 4 {
+		t.Fatalf("reclaimed %d bytes in %d files, want 11 in 4", o.ReclaimedBytes, o.ReclaimedFiles)
+	}
+	// Forensics are taken before each removal and account for all of it.
+	var bytes int64
+	var files int
+	for _, f := range o.Forensics {
+		bytes, files = bytes+f.Bytes, files+f.Files
+	}
+	if len(o.Forensics) != len(o.Removed) || bytes != 11 || files != 4 {
+		t.Fatalf("forensics %+v", o.Forensics)
+	}
+	if f := o.Forensics[0]; f.Path != app || f.Bytes != 1 || f.Files != 1 || f.Partial {
+		t.Fatalf("Steam.app measured as %+v", f)
+	}
 }
 
 // TestReconcile_RemovesDataWhenAppAbsent locks the gap fix: the plugin must
@@ -106,6 +126,100 @@ func TestReconcile_RemovesDataWhenAppAbsent(t *testing.T) {
 	}
 }
 
+// TestReconcile_UnfinishedTreeResumesNextPass: a tree that outlasts its
+// per-path budget is reported unfinished (not an error) while the other
+// paths still go, and the next pass picks it up.
+func TestReconcile_UnfinishedTreeResumesNextPass(t *testing.T) {
+	root := t.TempDir()
+	usersDir := filepath.Join(root, "Users")
+	for _, u := range []string{"alice", "bob", "carol"} {
+		lib := filepath.Join(usersDir, u, "Library", "Application Support", "Steam", "steamapps")
+		os.MkdirAll(lib, 0o755)
+		os.WriteFile(filepath.Join(lib, "dota.vpk"), []byte("vpk"), 0o644)
+	}
+	r := &Reconciler{
+		AppPath:     filepath.Join(root, "Steam.app"),
+		UsersDir:    usersDir,
+		System:      []systemTarget{},
+		Workers:     2,
+		PathTimeout: time.Nanosecond, // spent before the first entry
+	}
+	o := r.Reconcile()
+	if len(o.Unfinished) != 3 || len(o.Removed)+len(o.Errors) != 0 {
+		t.Fatalf("want 3 unfinished, nothing removed or failed: %+v", o)
+	}
+	for _, f := range o.Forensics {
+		if !f.Partial {
+			t.Fatalf("an out-of-time measure must say so: %+v", f)
+		}
+	}
+	r.PathTimeout = 0
+	o = r.Reconcile()
+	if len(o.Removed) != 3 || len(o.Unfinished) != 0 || o.ReclaimedBytes != 9 {
+		t.Fatalf("next pass must finish: %+v", o)
+	}
+	if want := r.Plan(); len(want) != 0 {
+		t.Fatalf("left on disk: %v", want)
+	}
+}
+
+// TestReconcile_DeadlineEndsThePass: however many paths there are, a pass
+// whose deadline has passed leaves the rest unfinished rather than running
+// on past the job timeout.
+func TestReconcile_DeadlineEndsThePass(t *testing.T) {
+	root := t.TempDir()
+	usersDir := filepath.Join(root, "Users")
+	for _, u := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		lib := filepath.Join(usersDir, u, "Library", "Application Support", "Steam", "steamapps")
+		os.MkdirAll(lib, 0o755)
+		os.WriteFile(filepath.Join(lib, "dota.vpk"), []byte("vpk"), 0o644)
+	}
+	r := &Reconciler{
+		AppPath:  filepath.Join(root, "Steam.app"),
+		UsersDir: usersDir,
+		System:   []systemTarget{},
+		Deadline: time.Now().Add(-time.Second),
+	}
+	o := r.Reconcile()
+	if len(o.Unfinished) != 5 || len(o.Removed)+len(o.Errors) != 0 || o.ReclaimedBytes != 0 {
+		t.Fatalf("want 5 unfinished, nothing touched: %+v", o)
+	}
+	if left := r.Plan(); len(left) != 5 {
+		t.Fatalf("a path after the deadline must not be touched: %v", left)
+	}
+}
+
+// TestRemoveTreeKeepsGoingPastAFailure: an entry that will not go does not
+// stop its siblings from being deleted; the error is still reported.
+func TestRemoveTreeKeepsGoingPastAFailure(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	root := filepath.Join(t.TempDir(), "Steam")
+	stuck := filepath.Join(root, "b-stuck")
+	os.MkdirAll(stuck, 0o755)
+	os.WriteFile(filepath.Join(stuck, "held"), []byte("x"), 0o644)
+	for _, n := range []string{"a", "c"} {
+		os.WriteFile(filepath.Join(root, n), []byte("xx"), 0o644)
+	}
+	os.Chmod(stuck, 0o555)
+	t.Cleanup(func() { os.Chmod(stuck, 0o755) })
+
+	var freed tally
+	err := removeTree(root, time.Now().Add(time.Minute), &freed)
+	if err == nil {
+		t.Fatal("want the stuck entry reported")
+	}
+	for _, n := range []string{"a", "c"} {
+		if _, serr := os.Lstat(filepath.Join(root, n)); !errors.Is(serr, fs.ErrNotExist) {
+			t.Fatalf("%s must be removed past the failure", n)
+		}
+	}
+	if freed.files != 2 || freed.bytes != 4 {
+		t.Fatalf("freed = %+v, want the two siblings", freed)
+	}
+}
+
 // TestReconcile_MissingUsersDirIsClean: on a non-macOS host (CI ubuntu has no
 // /Users) the always-sweep pass must NOT report an error — a missing users dir
 // just means "no per-user artifacts to sweep". Regression for the CI break that
@@ -144,3 +258,206 @@ func TestReconcile_IdempotentAfterRemoval(t *testing.T) {
 		t.Fatalf("second pass should be noop, got: %+v", o2)
 	}
 }
+
+// TestPlanUnderRootRemovesNothing: a sandbox laid out like / reports each
+// artifact a pass would remove, and is left untouched.
+func TestPlanUnderRootRemovesNothing(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "Applications", "Steam.app")
+	crash := filepath.Join(root, "Users", "alice", "Library", "Logs", "DiagnosticReports")
+	for _, d := range []string{app, crash, filepath.Join(root, "Users", "alice", "Library", "Caches", "Steam")} {
+		os.MkdirAll(d, 0o755)
+	}
+	os.WriteFile(filepath.Join(crash, "dota2_2026-03-01.ips"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(crash, "Safari_2026-03-01.ips"), []byte("x"), 0o644)
+
+	plan := Rooted(root).Plan()
+	if len(plan) != 3 || plan[0] != app {
+		t.Fatalf("plan = %v", plan)
+	}
+	for _, p := range plan {
+		if _, err := os.Stat(p); err != nil {
+			t.Fatalf("Plan removed %s", p)
+		}
+	}
+}
+
+// TestExcludeSparesMatches: an excluded crash report and an excluded target
+// survive the pass and are reported; the rest still goes.
+func TestExcludeSparesMatches(t *testing.T) {
+	root := t.TempDir()
+	crash := filepath.Join(root, "Users", "alice", "Library", "Logs", "DiagnosticReports")
+	caches := filepath.Join(root, "Users", "alice", "Library", "Caches", "Steam")
+	for _, d := range []string{crash, caches} {
+		os.MkdirAll(d, 0o755)
+	}
+	keep := filepath.Join(crash, "dota2-pipeline-build.ips")
+	drop := filepath.Join(crash, "dota2_2026-03-01.ips")
+	os.WriteFile(keep, []byte("x"), 0o644)
+	os.WriteFile(drop, []byte("x"), 0o644)
+
+	r := Rooted(root)
+	r.Exclude = []string{"*pipeline*", "/Users/*/Library/Caches/Steam"}
+	if plan := r.Plan(); len(plan) != 1 || plan[0] != drop {
+		t.Fatalf("plan = %v", plan)
+	}
+	o := r.Reconcile()
+	if len(o.Removed) != 1 || len(o.Excluded) != 2 {
+		t.Fatalf("removed %v, excluded %v", o.Removed, o.Excluded)
+	}
+	for _, p := range []string{keep, caches} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("excluded %s was removed", p)
+		}
+	}
+	if err := ValidExclude([]string{"[bad"}); err == nil {
+		t.Error("ValidExclude must reject a malformed pattern")
+	}
+}
+
+// TestVolumeLibraries: a library on an external volume loses Dota 2's files
+// and nothing else, a symlinked mount (the boot volume's) is never
+// followed, and with ScanVolumes off the volume is left alone.
+func TestVolumeLibraries(t *testing.T) {
+	root := t.TempDir()
+	lib := filepath.Join(root, "Volumes", "SSD", "Games", "SteamLibrary")
+	topLib := filepath.Join(root, "Volumes", "Stick")
+	for _, d := range []string{
+		filepath.Join(lib, "steamapps", "common", "dota 2 beta"),
+		filepath.Join(lib, "steamapps", "common", "Portal 2"),
+		filepath.Join(topLib, "steamapps", "common", "dota 2 beta"),
+		filepath.Join(root, "elsewhere", "steamapps", "common", "dota 2 beta"),
+	} {
+		os.MkdirAll(d, 0o755)
+	}
+	os.WriteFile(filepath.Join(lib, "steamapps", "appmanifest_570.acf"), []byte("x"), 0o644)
+	if err := os.Symlink(filepath.Join(root, "elsewhere"), filepath.Join(root, "Volumes", "Macintosh HD")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := Rooted(root)
+	if plan := r.Plan(); len(plan) != 0 {
+		t.Fatalf("ScanVolumes off: plan = %v", plan)
+	}
+	r.ScanVolumes = true
+	r.Paths = []string{"{{SteamLibrary}}/steamapps/common/dota 2 beta"}
+	os.MkdirAll(filepath.Join(root, "Users", "alice"), 0o755)
+	want := []string{
+		filepath.Join(lib, "steamapps", "common", "dota 2 beta"),
+		filepath.Join(lib, "steamapps", "appmanifest_570.acf"),
+		filepath.Join(topLib, "steamapps", "common", "dota 2 beta"),
+	}
+	plan := r.Plan()
+	slices.Sort(plan)
+	slices.Sort(want)
+	if !slices.Equal(plan, want) {
+		t.Fatalf("plan = %v\nwant %v", plan, want)
+	}
+}
+
+// TestPathsExpandPerUserAndSteamLibrary: {{Home}}/{{User}} resolve per home,
+// {{SteamLibrary}} covers the default library and the secondary ones
+// libraryfolders.vdf lists.
+func TestPathsExpandPerUserAndSteamLibrary(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "Users", "alice")
+	steam := filepath.Join(home, "Library", "Application Support", "Steam")
+	second := filepath.Join(root, "Volumes", "Games", "SteamLibrary")
+	for _, d := range []string{
+		filepath.Join(steam, "steamapps", "common", "dota 2 beta"),
+		filepath.Join(second, "steamapps", "common", "dota 2 beta"),
+		filepath.Join(home, "Downloads"),
+	} {
+		os.MkdirAll(d, 0o755)
+	}
+	os.WriteFile(filepath.Join(steam, "steamapps", "libraryfolders.vdf"), []byte(`"libraryfolders"
+{
+	"0"	{ "path"	"/Users/alice/Library/Application Support/Steam" }
+	"1"	{ "path"	"/Volumes/Games/SteamLibrary" }
+	"2"	{ "path"	"/" }
+}`), 0o644)
+	os.WriteFile(filepath.Join(home, "Downloads", "steam-installer-alice.zip"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(home, "Downloads", "notes.zip"), []byte("x"), 0o644)
+
+	r := Rooted(root)
+	r.Paths = []string{
+		"{{SteamLibrary}}/steamapps/common/dota 2 beta",
+		"{{Home}}/Downloads/*steam*-{{User}}.zip",
+	}
+	plan := r.Plan()
+	want := map[string]bool{
+		filepath.Join(steam, "steamapps", "common", "dota 2 beta"):    true,
+		filepath.Join(second, "steamapps", "common", "dota 2 beta"):   true,
+		filepath.Join(home, "Downloads", "steam-installer-alice.zip"): true,
+	}
+	var got []string
+	for _, p := range plan {
+		if want[p] {
+			got = append(got, p)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("plan = %v", plan)
+	}
+
+	for _, bad := range []string{
+		"/etc/hosts",
+		"{{Home}}",
+		"{{Home}}/",
+		"{{SteamLibrary}}/../x",
+		"{{Home}}/{{SteamLibrary}}/x",
+		"{{Home}}/[bad",
+		"{{Home}}/*",
+		"{{Home}}/*/dota",
+		"{{SteamLibrary}}/*",
+		"{{SteamLibrary}}//?ommon",
+	} {
+		if err := ValidPaths([]string{bad}); err == nil {
+			t.Errorf("ValidPaths(%q) = nil, want an error", bad)
+		}
+	}
+	if err := ValidPaths(r.Paths); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTombstonesLockRemovedAppThenExpire(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "Applications", "Steam.app")
+	if err := os.MkdirAll(filepath.Join(app, "Contents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := &Reconciler{AppPath: app, UsersDir: filepath.Join(root, "Users"),
+		System: []systemTarget{{Path: app, What: "Steam application"}}}
+	now := time.Now()
+	ttl := 30 * 24 * time.Hour
+
+	o := r.Reconcile()
+	placed, cleared, errs := r.Tombstones(o.Removed, ttl, now)
+	if len(placed) != 1 || cleared != 0 || len(errs) != 0 {
+		t.Fatalf("placed=%v cleared=%d errs=%v", placed, cleared, errs)
+	}
+	if r.Detect() || len(r.Plan()) != 0 {
+		t.Fatal("a tombstone must not read as Steam or be swept")
+	}
+	if err := os.Mkdir(app, 0o755); err == nil {
+		t.Fatal("reinstall to the tombstoned path succeeded")
+	}
+	if _, cleared, _ := r.Tombstones(nil, ttl, now.Add(24*time.Hour)); cleared != 0 {
+		t.Fatal("a fresh tombstone was cleared")
+	}
+	if _, cleared, _ := r.Tombstones(nil, ttl, now.Add(ttl+time.Hour)); cleared != 1 {
+		t.Fatal("an expired tombstone was kept")
+	}
+	if _, err := os.Lstat(app); !os.IsNotExist(err) {
+		t.Fatalf("tombstone still there: %v", err)
+	}
+
+	// A user's own file at the path is never taken for a tombstone.
+	if err := os.WriteFile(app, []byte("mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, cleared, _ := r.Tombstones(nil, 0, now); cleared != 0 {
+		t.Fatal("cleared a file it did not write")
+	}
+}
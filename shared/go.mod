@@ -0,0 +1,3 @@
+module github.com/eliteGoblin/focusd/shared
+
+go 1.25.6
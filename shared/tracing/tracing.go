@@ -0,0 +1,408 @@
+// Package tracing records OpenTelemetry spans and ships them to an
+// OTLP/HTTP collector in the protocol's JSON encoding. The daemon traces its
+// update and self-repair flows (platform fetch/swap/rollback, binary
+// re-materialize, mesh restore) and the platform its enforcement runs; both
+// export through this one package, told apart by service.name.
+//
+// It is a deliberately small, stdlib-only exporter rather than the OTel SDK:
+// each layer needs a handful of spans per tick, not the SDK's dependency
+// tree. The wire format is plain OTLP (POST {endpoint}/v1/traces,
+// application/json), so any collector, Jaeger or Tempo ingests it unchanged.
+//
+// Tracing is off unless an endpoint is configured: the daemon's is the one
+// `daemon notify --otlp` stored, falling back to the standard
+// OTEL_EXPORTER_OTLP_* variables, and the platform's the variables alone.
+// A nil *Tracer and a nil *Span are no-ops, so call sites never nil-check.
+//
+// Redaction: span names and attributes carry ids, versions, statuses and
+// counts only — never a path — and export failures are logged by error
+// type, never with the collector URL.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Standard OTel exporter variables. The traces-specific one is used verbatim;
+// the generic one is a base URL that gets the /v1/traces path appended.
+const (
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+const (
+	maxQueued     = 512 // spans held between flushes; the oldest are dropped past this
+	batchSize     = 64  // a full batch wakes the flusher early
+	flushInterval = 5 * time.Second
+	scopeName     = "focusd"
+)
+
+// Attr is one span attribute. Build with String, Int or Bool.
+type Attr struct {
+	Key   string
+	Value any // string, int64 or bool
+}
+
+// String returns a string attribute.
+func String(k, v string) Attr { return Attr{Key: k, Value: v} }
+
+// Int returns an integer attribute.
+func Int(k string, v int64) Attr { return Attr{Key: k, Value: v} }
+
+// Bool returns a boolean attribute.
+func Bool(k string, v bool) Attr { return Attr{Key: k, Value: v} }
+
+// Tracer buffers finished spans and exports them in batches.
+type Tracer struct {
+	endpoint func() string // full traces URL; "" disables export
+	service  string
+	Client   *http.Client
+	log      *slog.Logger
+
+	mu      sync.Mutex
+	queue   []*Span
+	dropped int
+	kick    chan struct{}
+}
+
+// New builds a tracer exporting to the traces URL endpoint returns (read at
+// every flush, so a changed setting needs no restart).
+func New(endpoint func() string, service string, log *slog.Logger) *Tracer {
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
+	return &Tracer{
+		endpoint: endpoint,
+		service:  service,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		log:      log,
+		kick:     make(chan struct{}, 1),
+	}
+}
+
+// FromEnv builds a tracer from the standard OTel endpoint variables, or
+// returns nil (tracing off) when neither is set.
+func FromEnv(service string, log *slog.Logger) *Tracer {
+	u := EnvURL()
+	if u == "" {
+		return nil
+	}
+	return New(func() string { return u }, service, log)
+}
+
+// EnvURL is the traces URL from the standard OTel endpoint variables, ""
+// when neither is set.
+func EnvURL() string {
+	return TracesURL(os.Getenv(TracesEndpointEnv), os.Getenv(EndpointEnv))
+}
+
+// ValidEndpoint reports whether s is usable as a base OTLP/HTTP endpoint: an
+// http(s) URL with a host and no query.
+func ValidEndpoint(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.RawQuery == ""
+}
+
+// TracesURL resolves the export URL from a traces-specific endpoint (used
+// as-is) or a base endpoint (gets /v1/traces appended). "" when both are.
+func TracesURL(traces, base string) string {
+	if traces = strings.TrimSpace(traces); traces != "" {
+		return traces
+	}
+	if base = strings.TrimSpace(base); base != "" {
+		return strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+type ctxKey struct{}
+
+// Span is one timed operation. Set attributes while it runs, then End it
+// exactly once (or Discard it if it turned out not to be worth exporting).
+type Span struct {
+	t        *Tracer
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	failed   bool
+	reason   string
+}
+
+// Start opens a span under ctx's current span, or a new trace when ctx has
+// none. Returns ctx unchanged and a nil span when t is nil.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &Span{t: t, name: name, start: time.Now(), attrs: attrs}
+	if parent, ok := ctx.Value(ctxKey{}).(*Span); ok && parent != nil {
+		s.traceID, s.parentID = parent.traceID, parent.spanID
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, ctxKey{}, s), s
+}
+
+// Start opens a child of ctx's current span using that span's tracer. It is
+// a no-op when ctx carries no span, so library code can instrument itself
+// without a tracer being threaded through.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	parent, _ := ctx.Value(ctxKey{}).(*Span)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.t.Start(ctx, name, attrs...)
+}
+
+// Set adds attributes to the span.
+func (s *Span) Set(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// Fail marks the span's status as an error. reason must be redaction-safe
+// (a run status or an error type, never an error string that may embed a
+// path).
+func (s *Span) Fail(reason string) {
+	if s == nil {
+		return
+	}
+	s.failed, s.reason = true, reason
+}
+
+// End finishes the span and queues it for export.
+func (s *Span) End() {
+	if s == nil || !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+	s.t.enqueue(s)
+}
+
+// Discard finishes the span without exporting it.
+func (s *Span) Discard() {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+}
+
+func (t *Tracer) enqueue(s *Span) {
+	t.mu.Lock()
+	if len(t.queue) >= maxQueued {
+		t.queue = t.queue[1:]
+		t.dropped++
+	}
+	t.queue = append(t.queue, s)
+	full := len(t.queue) >= batchSize
+	t.mu.Unlock()
+	if full {
+		select {
+		case t.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run flushes every flushInterval (or sooner when a batch fills) until ctx
+// ends. The owner calls Shutdown for the final flush. A nil tracer returns
+// immediately.
+func (t *Tracer) Run(ctx context.Context) {
+	if t == nil {
+		return
+	}
+	tk := time.NewTicker(flushInterval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		case <-t.kick:
+		}
+		t.flushLogged(ctx)
+	}
+}
+
+// Shutdown makes a final flush bounded to a few seconds, so a collector that
+// is down cannot hold up process exit.
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	t.flushLogged(ctx)
+}
+
+func (t *Tracer) flushLogged(ctx context.Context) {
+	if err := t.Flush(ctx); err != nil {
+		t.log.Warn("trace export failed", "err", err)
+	}
+}
+
+// Flush exports every queued span in one request. On failure the batch is
+// dropped (tracing is best-effort; protection never waits on a collector).
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	batch, dropped := t.queue, t.dropped
+	t.queue, t.dropped = nil, 0
+	t.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	if dropped > 0 {
+		t.log.Warn("trace queue overflowed", "dropped", dropped)
+	}
+	endpoint := t.endpoint()
+	if endpoint == "" {
+		return nil
+	}
+	body, err := json.Marshal(t.payload(batch))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid trace endpoint")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapper: its text embeds the collector URL.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP/JSON wire types (opentelemetry-proto, JSON mapping): ids are hex,
+// 64-bit integers are decimal strings.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKV `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string     `json:"traceId"`
+		SpanID            string     `json:"spanId"`
+		ParentSpanID      string     `json:"parentSpanId,omitempty"`
+		Name              string     `json:"name"`
+		Kind              int        `json:"kind"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+		Attributes        []otlpKV   `json:"attributes,omitempty"`
+		Status            otlpStatus `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKV struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"`
+		BoolValue   *bool   `json:"boolValue,omitempty"`
+	}
+)
+
+const (
+	spanKindInternal = 1
+	statusOK         = 1
+	statusError      = 2
+)
+
+func (t *Tracer) payload(batch []*Span) otlpRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		sp := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        kvs(s.attrs),
+			Status:            otlpStatus{Code: statusOK},
+		}
+		if s.parentID != [8]byte{} {
+			sp.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.failed {
+			sp.Status = otlpStatus{Code: statusError, Message: s.reason}
+		}
+		spans = append(spans, sp)
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: kvs([]Attr{String("service.name", t.service)})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: scopeName}, Spans: spans}},
+	}}}
+}
+
+func kvs(attrs []Attr) []otlpKV {
+	out := make([]otlpKV, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch x := a.Value.(type) {
+		case string:
+			v.StringValue = &x
+		case int64:
+			s := strconv.FormatInt(x, 10)
+			v.IntValue = &s
+		case bool:
+			v.BoolValue = &x
+		default:
+			s := fmt.Sprint(x)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKV{Key: a.Key, Value: v})
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// collector captures the OTLP/JSON bodies posted to it.
+func collector(t *testing.T, status int) (*httptest.Server, *[]otlpRequest) {
+	t.Helper()
+	var got []otlpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		b, _ := io.ReadAll(r.Body)
+		var req otlpRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			t.Errorf("body is not OTLP JSON: %v", err)
+		}
+		got = append(got, req)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestNilTracerAndSpanAreNoOps(t *testing.T) {
+	var tr *Tracer
+	ctx, s := tr.Start(context.Background(), "x")
+	if s != nil {
+		t.Fatal("nil tracer returned a span")
+	}
+	s.Set(String("k", "v"))
+	s.Fail("error")
+	s.End()
+	if _, c := Start(ctx, "child"); c != nil {
+		t.Fatal("Start without a parent span returned a span")
+	}
+	if err := tr.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlushExportsParentChildSpans(t *testing.T) {
+	srv, got := collector(t, http.StatusOK)
+	tr := New(func() string { return TracesURL("", srv.URL+"/") }, "daemon", nil)
+
+	ctx, root := tr.Start(context.Background(), "job.run", String("job.id", "kill-steam"))
+	_, child := Start(ctx, "plugin.exec", Int("attempt", 1))
+	child.Fail("error")
+	child.End()
+	root.Set(Bool("enforced", true))
+	root.End()
+	_, skipped := tr.Start(context.Background(), "steady")
+	skipped.Discard()
+
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*got) != 1 {
+		t.Fatalf("requests = %d, want 1", len(*got))
+	}
+	rs := (*got)[0].ResourceSpans[0]
+	if v := rs.Resource.Attributes[0]; v.Key != "service.name" || *v.Value.StringValue != "daemon" {
+		t.Errorf("resource = %+v", v)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2 (discarded span must not export)", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if c.TraceID != r.TraceID || c.ParentSpanID != r.SpanID || r.ParentSpanID != "" {
+		t.Errorf("child not linked to root: child=%+v root=%+v", c, r)
+	}
+	if c.Status.Code != statusError || c.Status.Message != "error" || r.Status.Code != statusOK {
+		t.Errorf("statuses: child=%+v root=%+v", c.Status, r.Status)
+	}
+	if *c.Attributes[0].Value.IntValue != "1" || !*r.Attributes[1].Value.BoolValue {
+		t.Errorf("attributes not encoded: child=%+v root=%+v", c.Attributes, r.Attributes)
+	}
+	// The queue is drained: a second flush sends nothing.
+	if err := tr.Flush(context.Background()); err != nil || len(*got) != 1 {
+		t.Fatalf("second flush sent again (err=%v, requests=%d)", err, len(*got))
+	}
+}
+
+func TestFlushErrorNeverCarriesEndpoint(t *testing.T) {
+	srv, _ := collector(t, http.StatusServiceUnavailable)
+	tr := New(func() string { return srv.URL + "/v1/traces" }, "daemon", nil)
+	_, s := tr.Start(context.Background(), "job.run")
+	s.End()
+	err := tr.Flush(context.Background())
+	if err == nil || err.Error() != "HTTP 503" {
+		t.Fatalf("err = %v, want HTTP 503", err)
+	}
+
+	dead := New(func() string { return "http://127.0.0.1:1/secret-host/v1/traces" }, "daemon", nil)
+	_, s = dead.Start(context.Background(), "job.run")
+	s.End()
+	if err := dead.Flush(context.Background()); err == nil || strings.Contains(err.Error(), "secret-host") {
+		t.Fatalf("err = %v, want a transport error without the URL", err)
+	}
+}
+
+func TestTracesURL(t *testing.T) {
+	for _, c := range []struct{ traces, base, want string }{
+		{"", "", ""},
+		{"", "http://c:4318", "http://c:4318/v1/traces"},
+		{"", "http://c:4318/", "http://c:4318/v1/traces"},
+		{"http://c:4318/custom", "http://ignored", "http://c:4318/custom"},
+	} {
+		if got := TracesURL(c.traces, c.base); got != c.want {
+			t.Errorf("TracesURL(%q, %q) = %q, want %q", c.traces, c.base, got, c.want)
+		}
+	}
+}
+
+func TestEnvURLAndValidEndpoint(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	t.Setenv(TracesEndpointEnv, "")
+	if got := EnvURL(); got != "" {
+		t.Fatalf("EnvURL() = %q with nothing set", got)
+	}
+	t.Setenv(EndpointEnv, "http://c:4318")
+	if got := EnvURL(); got != "http://c:4318/v1/traces" {
+		t.Fatalf("EnvURL() = %q", got)
+	}
+	for s, want := range map[string]bool{
+		"http://localhost:4318":  true,
+		"https://otel.example":   true,
+		"ftp://otel.example":     false,
+		"http://":                false,
+		"http://c:4318/?token=x": false,
+		"not a url":              false,
+	} {
+		if got := ValidEndpoint(s); got != want {
+			t.Errorf("ValidEndpoint(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestFromEnvOffWhenUnset(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	t.Setenv(TracesEndpointEnv, "")
+	if FromEnv("platform", nil) != nil {
+		t.Fatal("tracer built with no endpoint configured")
+	}
+	t.Setenv(EndpointEnv, "http://c:4318")
+	if FromEnv("platform", nil) == nil {
+		t.Fatal("no tracer with an endpoint configured")
+	}
+}